@@ -11,6 +11,10 @@ type AuthStsURLResponse struct {
 type AuthState struct {
 	RedirectURL string `json:"redirect_url"`
 	DeviceID    string `json:"device_id"`
+	// Nonce is used by OIDC provider to bind ID token and PKCE verifier
+	Nonce string `json:"nonce,omitempty"`
+	// UserCode is set when the login approves device authorization
+	UserCode string `json:"user_code,omitempty"`
 }
 
 // UserInfo provides basic info about user
@@ -46,3 +50,122 @@ type AuthTokenRefreshResponse struct {
 	Authorization *Authorization `json:"authorization"`
 	Profile       *UserInfo      `json:"profile"`
 }
+
+// Session provides information about a login session of the user,
+// the ID of the session is used as `jti` claim of issued access tokens
+type Session struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	DeviceID   string     `json:"device_id"`
+	IssuedAt   time.Time  `json:"issued_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	// Groups of the identity provider, asserted at login
+	Groups []string `json:"groups,omitempty"`
+	// Current is set if the session belongs to the caller's access token
+	Current bool `json:"current,omitempty"`
+}
+
+// SessionResponse provides response for a session request
+type SessionResponse struct {
+	Session *Session `json:"session"`
+}
+
+// SessionsResponse provides response for sessions request
+type SessionsResponse struct {
+	Sessions []*Session `json:"sessions"`
+}
+
+// Prefixes of the role binding subject, that specify the type of the subject,
+// so the names of different types do not collide
+const (
+	// RoleBindingUserPrefix specifies the subject of JWT, usually an email
+	RoleBindingUserPrefix = "user:"
+	// RoleBindingServiceAccountPrefix specifies a service account,
+	// in ${org}/${name} format
+	RoleBindingServiceAccountPrefix = "sa:"
+	// RoleBindingSPIFFEPrefix specifies SPIFFE ID of the TLS client certificate
+	RoleBindingSPIFFEPrefix = "spiffe:"
+	// RoleBindingCNPrefix specifies Common Name of the TLS client certificate
+	RoleBindingCNPrefix = "cn:"
+	// RoleBindingGroupPrefix specifies a group of the identity provider,
+	// in ${provider}/${group} format
+	RoleBindingGroupPrefix = "group:"
+)
+
+// RoleBindingPrefixes provides the supported prefixes of the role binding subject
+var RoleBindingPrefixes = []string{
+	RoleBindingUserPrefix,
+	RoleBindingServiceAccountPrefix,
+	RoleBindingSPIFFEPrefix,
+	RoleBindingCNPrefix,
+	RoleBindingGroupPrefix,
+}
+
+// ProviderGroup returns the group of the identity provider,
+// in ${provider}/${group} format, so the groups of different providers
+// do not collide
+func ProviderGroup(provider, group string) string {
+	return provider + "/" + group
+}
+
+// RoleBinding binds the role to the subject,
+// which is prefixed with its type: user:${subject}, sa:${org}/${name},
+// spiffe:${spiffe_id}, cn:${common_name}, or group:${provider}/${group}
+type RoleBinding struct {
+	ID        string    `json:"id"`
+	Role      string    `json:"role"`
+	Subject   string    `json:"subject"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateRoleBindingRequest binds the role to the subject
+type CreateRoleBindingRequest struct {
+	Role    string `json:"role"`
+	Subject string `json:"subject"`
+}
+
+// RoleBindingResponse provides response for a role binding request
+type RoleBindingResponse struct {
+	RoleBinding *RoleBinding `json:"role_binding"`
+}
+
+// RoleBindingsResponse provides response for role bindings request
+type RoleBindingsResponse struct {
+	RoleBindings []*RoleBinding `json:"role_bindings"`
+}
+
+// Device authorization errors, as defined in RFC 8628
+const (
+	DeviceAuthorizationPending = "authorization_pending"
+	DeviceSlowDown             = "slow_down"
+	DeviceExpiredToken         = "expired_token"
+	DeviceInvalidGrant         = "invalid_grant"
+)
+
+// DeviceAuthorizationRequest starts the device authorization flow
+type DeviceAuthorizationRequest struct {
+	DeviceID string `json:"device_id"`
+	// Provider specifies OAuth2 provider: github, google, or provider_id of configured OIDC provider
+	Provider string `json:"provider"`
+}
+
+// DeviceAuthorizationResponse provides response for DeviceAuthorizationRequest
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	// ExpiresIn specifies the lifetime in seconds of the codes
+	ExpiresIn int `json:"expires_in"`
+	// Interval specifies the minimum amount of time in seconds
+	// that the client should wait between polling requests
+	Interval int `json:"interval"`
+}
+
+// DeviceTokenRequest polls for the access token of approved device authorization
+type DeviceTokenRequest struct {
+	DeviceCode string `json:"device_code"`
+}
@@ -60,6 +60,16 @@ const (
 	// Response: v1.AuthStsURLResponse
 	PathForAuthURL = "/v1/auth/url"
 
+	// PathForAuthLogin starts the login in the browser,
+	// binds the OAuth state to the browser and redirects to the provider
+	//
+	// Verbs: GET
+	// Parameters:
+	//	redirect_url
+	//	device_id
+	//	sts
+	PathForAuthLogin = "/v1/auth/login"
+
 	// PathForAuthDone receives authenticated code and prints it
 	//
 	// Verbs: GET
@@ -80,6 +90,84 @@ const (
 
 	// PathForAuthGoogleCallback is auth callback for google
 	PathForAuthGoogleCallback = "/v1/auth/google/callback"
+
+	// PathForAuthOIDCCallback is auth callback for OpenID Connect providers
+	PathForAuthOIDCCallback = "/v1/auth/oidc/:provider/callback"
+
+	// PathForAuthDeviceCode starts device authorization flow,
+	// as defined in RFC 8628
+	//
+	// Verbs: POST
+	// Request: v1.DeviceAuthorizationRequest
+	// Response: v1.DeviceAuthorizationResponse
+	PathForAuthDeviceCode = "/v1/auth/device/code"
+
+	// PathForAuthDeviceToken returns access token of approved device authorization
+	//
+	// Verbs: POST
+	// Request: v1.DeviceTokenRequest
+	// Response: v1.AuthTokenRefreshResponse
+	PathForAuthDeviceToken = "/v1/auth/device/token"
+
+	// PathForAuthDevice is the verification page, where the user enters the code
+	// displayed on the device, confirms the device, and authenticates with OAuth2 provider
+	//
+	// Verbs: GET, POST
+	// Parameters:
+	//	user_code
+	// Response: the confirmation page on GET, redirect to OAuth2 provider on POST
+	PathForAuthDevice = "/v1/auth/device"
+
+	// PathForAuthDeviceApprove approves or denies the device authorization,
+	// on the consent of the user after the login
+	//
+	// Verbs: POST
+	// Parameters:
+	//	user_code
+	//	csrf
+	//	action: approve|deny
+	PathForAuthDeviceApprove = "/v1/auth/device/approve"
+
+	// PathForAuthSessions returns active sessions of the caller,
+	// or revokes all of them to log out everywhere
+	//
+	// Verbs: GET, DELETE
+	// Response: v1.SessionsResponse
+	PathForAuthSessions = "/v1/auth/sessions"
+
+	// PathForAuthSession revokes the session of the caller to log out the device
+	//
+	// Verbs: DELETE
+	// Response: v1.SessionResponse
+	PathForAuthSession = "/v1/auth/sessions/:id"
+
+	// PathForAuthUserSessions returns active sessions of the user,
+	// or revokes all of them to force log out, available to admins only
+	//
+	// Verbs: GET, DELETE
+	// Response: v1.SessionsResponse
+	PathForAuthUserSessions = "/v1/auth/users/:user_id/sessions"
+
+	// PathForAuthRoleBindings lists or creates role bindings,
+	// available to admins only
+	//
+	// Verbs: GET, POST
+	// Request: v1.CreateRoleBindingRequest
+	// Response: v1.RoleBindingsResponse or v1.RoleBindingResponse
+	PathForAuthRoleBindings = "/v1/auth/roles"
+
+	// PathForAuthRoleBinding deletes the role binding,
+	// available to admins only
+	//
+	// Verbs: DELETE
+	// Response: v1.RoleBindingResponse
+	PathForAuthRoleBinding = "/v1/auth/roles/:id"
+
+	// PathForJWKS returns the public keys to verify JWT issued by Trusty
+	//
+	// Verbs: GET
+	// Response: jwk.Set
+	PathForJWKS = "/.well-known/jwks.json"
 )
 
 // Workflow service API
@@ -106,6 +194,37 @@ const (
 	PathForWorkflowSyncOrgs = "/v1/wf/:provider/sync_orgs"
 )
 
+// Service Accounts API, provided by Workflow service
+const (
+	// PathForServiceAccounts lists or creates service accounts of the org
+	//
+	// Verbs: GET, POST
+	// Parameters:
+	//	org_id
+	// Request: v1.CreateServiceAccountRequest
+	// Response: v1.ServiceAccountsResponse or v1.ServiceAccountResponse
+	PathForServiceAccounts = "/v1/sa"
+
+	// PathForServiceAccount deletes the service account and its tokens
+	//
+	// Verbs: DELETE
+	// Response: v1.ServiceAccountResponse
+	PathForServiceAccount = "/v1/sa/:id"
+
+	// PathForServiceAccountTokens lists or creates API tokens of the service account
+	//
+	// Verbs: GET, POST
+	// Request: v1.CreateAPITokenRequest
+	// Response: v1.APITokensResponse or v1.CreateAPITokenResponse
+	PathForServiceAccountTokens = "/v1/sa/:id/tokens"
+
+	// PathForServiceAccountToken revokes API token of the service account
+	//
+	// Verbs: DELETE
+	// Response: v1.APITokenResponse
+	PathForServiceAccountToken = "/v1/sa/:id/tokens/:token_id"
+)
+
 // CIS service API
 const (
 	// PathForCIS is base path for the CIS service
@@ -116,6 +235,22 @@ const (
 	// Verbs: GET
 	// Response: RootsResponse
 	PathForCISRoots = "/v1/cis/roots"
+
+	// PathForAIACerts provides the issuer's certificate for AIA,
+	// the :id is the Subject Key ID of the issuer with extension:
+	// .crt for DER encoded certificate,
+	// .p7c for PKCS#7 certs-only bundle with the certificate and cross-certificates
+	//
+	// Verbs: GET
+	// Response: application/pkix-cert or application/pkcs7-mime
+	PathForAIACerts = "/v1/certs/:id"
+
+	// PathForSpiffeBundle provides SPIFFE trust bundle for the trust domain,
+	// in JWKS format per SPIFFE Trust Domain and Bundle specification
+	//
+	// Verbs: GET
+	// Response: SpiffeBundle
+	PathForSpiffeBundle = "/v1/spiffe/bundle/:trust_domain"
 )
 
 // CA service API
@@ -134,4 +269,12 @@ const (
 	// Verbs: GET
 	// Response: CertProfileInfo
 	PathForCAProfileInfo = "/v1/ca/csr/profile_info"
+
+	// PathForTSA provides RFC 3161 time-stamping,
+	// the optional issuer query parameter specifies the label of timestamp issuer
+	//
+	// Verbs: POST
+	// Request: application/timestamp-query
+	// Response: application/timestamp-reply
+	PathForTSA = "/v1/tsa"
 )
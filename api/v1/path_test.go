@@ -16,10 +16,25 @@ func TestPaths(t *testing.T) {
 	assert.Equal(t, "/v1/swagger/:service", v1.PathForSwagger)
 
 	assert.Equal(t, "/v1/auth/url", v1.PathForAuthURL)
+	assert.Equal(t, "/v1/auth/login", v1.PathForAuthLogin)
 	assert.Equal(t, "/v1/auth/token/refresh", v1.PathForAuthTokenRefresh)
 	assert.Equal(t, "/v1/auth/github", v1.PathForAuthGithub)
 	assert.Equal(t, "/v1/auth/github/callback", v1.PathForAuthGithubCallback)
+	assert.Equal(t, "/v1/auth/device", v1.PathForAuthDevice)
+	assert.Equal(t, "/v1/auth/device/approve", v1.PathForAuthDeviceApprove)
+	assert.Equal(t, "/v1/auth/device/code", v1.PathForAuthDeviceCode)
+	assert.Equal(t, "/v1/auth/device/token", v1.PathForAuthDeviceToken)
+	assert.Equal(t, "/v1/auth/sessions", v1.PathForAuthSessions)
+	assert.Equal(t, "/v1/auth/sessions/:id", v1.PathForAuthSession)
+	assert.Equal(t, "/v1/auth/users/:user_id/sessions", v1.PathForAuthUserSessions)
+	assert.Equal(t, "/v1/auth/roles", v1.PathForAuthRoleBindings)
+	assert.Equal(t, "/v1/auth/roles/:id", v1.PathForAuthRoleBinding)
 
 	assert.Equal(t, "/v1/wf", v1.PathForWorkflow)
 	assert.Equal(t, "/v1/wf/:provider/repos", v1.PathForWorkflowRepos)
+
+	assert.Equal(t, "/v1/sa", v1.PathForServiceAccounts)
+	assert.Equal(t, "/v1/sa/:id/tokens/:token_id", v1.PathForServiceAccountToken)
+
+	assert.Equal(t, "/v1/spiffe/bundle/:trust_domain", v1.PathForSpiffeBundle)
 }
@@ -0,0 +1,667 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1-devel
+// 	protoc        v3.6.1
+// source: signing.proto
+
+package pb
+
+import (
+	context "context"
+	reflect "reflect"
+	sync "sync"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// HashAlgorithm specifies the digest algorithm
+type HashAlgorithm int32
+
+const (
+	HashAlgorithm_SHA256 HashAlgorithm = 0
+	HashAlgorithm_SHA384 HashAlgorithm = 1
+	HashAlgorithm_SHA512 HashAlgorithm = 2
+)
+
+// Enum value maps for HashAlgorithm.
+var (
+	HashAlgorithm_name = map[int32]string{
+		0: "SHA256",
+		1: "SHA384",
+		2: "SHA512",
+	}
+	HashAlgorithm_value = map[string]int32{
+		"SHA256": 0,
+		"SHA384": 1,
+		"SHA512": 2,
+	}
+)
+
+func (x HashAlgorithm) Enum() *HashAlgorithm {
+	p := new(HashAlgorithm)
+	*p = x
+	return p
+}
+
+func (x HashAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HashAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_signing_proto_enumTypes[0].Descriptor()
+}
+
+func (HashAlgorithm) Type() protoreflect.EnumType {
+	return &file_signing_proto_enumTypes[0]
+}
+
+func (x HashAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HashAlgorithm.Descriptor instead.
+func (HashAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return file_signing_proto_rawDescGZIP(), []int{0}
+}
+
+// SignDigestRequest specifies the request to sign the digest
+type SignDigestRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// IssuerLabel specifies the codesign Issuer,
+	// if not provided, then the first codesign Issuer is used
+	IssuerLabel string `protobuf:"bytes,1,opt,name=issuer_label,json=issuerLabel,proto3" json:"issuer_label,omitempty"`
+	// Profile specifies the profile of the short-lived certificate,
+	// if not provided, then remote_codesign profile is used
+	Profile string `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"`
+	// HashAlgorithm specifies the digest algorithm
+	HashAlgorithm HashAlgorithm `protobuf:"varint,3,opt,name=hash_algorithm,json=hashAlgorithm,proto3,enum=pb.HashAlgorithm" json:"hash_algorithm,omitempty"`
+	// Digest specifies the digest of the content to sign
+	Digest []byte `protobuf:"bytes,4,opt,name=digest,proto3" json:"digest,omitempty"`
+	// Data specifies a small blob to sign, if the digest is not provided
+	Data []byte `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *SignDigestRequest) Reset() {
+	*x = SignDigestRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_signing_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignDigestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignDigestRequest) ProtoMessage() {}
+
+func (x *SignDigestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_signing_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignDigestRequest.ProtoReflect.Descriptor instead.
+func (*SignDigestRequest) Descriptor() ([]byte, []int) {
+	return file_signing_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SignDigestRequest) GetIssuerLabel() string {
+	if x != nil {
+		return x.IssuerLabel
+	}
+	return ""
+}
+
+func (x *SignDigestRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *SignDigestRequest) GetHashAlgorithm() HashAlgorithm {
+	if x != nil {
+		return x.HashAlgorithm
+	}
+	return HashAlgorithm_SHA256
+}
+
+func (x *SignDigestRequest) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+func (x *SignDigestRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// SignatureResponse returns the signature
+type SignatureResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Signature provides DER encoded detached CMS SignedData
+	Signature []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	// Certificate provides the signing certificate in PEM format
+	Certificate string `protobuf:"bytes,2,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	// Intermediates provides the issuer's certificates bundle in PEM format
+	Intermediates string `protobuf:"bytes,3,opt,name=intermediates,proto3" json:"intermediates,omitempty"`
+}
+
+func (x *SignatureResponse) Reset() {
+	*x = SignatureResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_signing_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignatureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignatureResponse) ProtoMessage() {}
+
+func (x *SignatureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_signing_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignatureResponse.ProtoReflect.Descriptor instead.
+func (*SignatureResponse) Descriptor() ([]byte, []int) {
+	return file_signing_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SignatureResponse) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *SignatureResponse) GetCertificate() string {
+	if x != nil {
+		return x.Certificate
+	}
+	return ""
+}
+
+func (x *SignatureResponse) GetIntermediates() string {
+	if x != nil {
+		return x.Intermediates
+	}
+	return ""
+}
+
+// SignKeylessRequest specifies the request for a short-lived certificate
+type SignKeylessRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// IssuerLabel specifies the codesign Issuer,
+	// if not provided, then the first codesign Issuer is used
+	IssuerLabel string `protobuf:"bytes,1,opt,name=issuer_label,json=issuerLabel,proto3" json:"issuer_label,omitempty"`
+	// Profile specifies the profile of the short-lived certificate,
+	// if not provided, then keyless profile is used
+	Profile string `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"`
+	// Token specifies JWT of the caller
+	Token string `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	// PublicKey specifies the caller's public key in PEM format
+	PublicKey string `protobuf:"bytes,4,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	// Proof specifies the signature of SHA-256 of the token by the caller's private key
+	Proof []byte `protobuf:"bytes,5,opt,name=proof,proto3" json:"proof,omitempty"`
+}
+
+func (x *SignKeylessRequest) Reset() {
+	*x = SignKeylessRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_signing_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignKeylessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignKeylessRequest) ProtoMessage() {}
+
+func (x *SignKeylessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_signing_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignKeylessRequest.ProtoReflect.Descriptor instead.
+func (*SignKeylessRequest) Descriptor() ([]byte, []int) {
+	return file_signing_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SignKeylessRequest) GetIssuerLabel() string {
+	if x != nil {
+		return x.IssuerLabel
+	}
+	return ""
+}
+
+func (x *SignKeylessRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *SignKeylessRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *SignKeylessRequest) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *SignKeylessRequest) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+// KeylessCertificateResponse returns the short-lived certificate
+type KeylessCertificateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Certificate provides the certificate in PEM format
+	Certificate string `protobuf:"bytes,1,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	// Intermediates provides the issuer's certificates bundle in PEM format
+	Intermediates string `protobuf:"bytes,2,opt,name=intermediates,proto3" json:"intermediates,omitempty"`
+}
+
+func (x *KeylessCertificateResponse) Reset() {
+	*x = KeylessCertificateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_signing_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeylessCertificateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeylessCertificateResponse) ProtoMessage() {}
+
+func (x *KeylessCertificateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_signing_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeylessCertificateResponse.ProtoReflect.Descriptor instead.
+func (*KeylessCertificateResponse) Descriptor() ([]byte, []int) {
+	return file_signing_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *KeylessCertificateResponse) GetCertificate() string {
+	if x != nil {
+		return x.Certificate
+	}
+	return ""
+}
+
+func (x *KeylessCertificateResponse) GetIntermediates() string {
+	if x != nil {
+		return x.Intermediates
+	}
+	return ""
+}
+
+var File_signing_proto protoreflect.FileDescriptor
+
+var file_signing_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x02, 0x70, 0x62, 0x22, 0xb6, 0x01, 0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x44, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x73, 0x73,
+	0x75, 0x65, 0x72, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x18, 0x0a, 0x07,
+	0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x38, 0x0a, 0x0e, 0x68, 0x61, 0x73, 0x68, 0x5f, 0x61,
+	0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11,
+	0x2e, 0x70, 0x62, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68,
+	0x6d, 0x52, 0x0d, 0x68, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d,
+	0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x79, 0x0a, 0x11,
+	0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6d,
+	0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x73, 0x22, 0x9c, 0x01, 0x0a, 0x12, 0x53, 0x69, 0x67, 0x6e,
+	0x4b, 0x65, 0x79, 0x6c, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21,
+	0x0a, 0x0c, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x4c, 0x61, 0x62, 0x65,
+	0x6c, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x64, 0x0a, 0x1a, 0x4b, 0x65, 0x79, 0x6c, 0x65, 0x73,
+	0x73, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6d,
+	0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x73, 0x2a, 0x33, 0x0a, 0x0d,
+	0x48, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x0a, 0x0a,
+	0x06, 0x53, 0x48, 0x41, 0x32, 0x35, 0x36, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x48, 0x41,
+	0x33, 0x38, 0x34, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x48, 0x41, 0x35, 0x31, 0x32, 0x10,
+	0x02, 0x32, 0x97, 0x01, 0x0a, 0x0e, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x3c, 0x0a, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x44, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x44, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x53,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x47, 0x0a, 0x0b, 0x53, 0x69, 0x67, 0x6e, 0x4b, 0x65, 0x79, 0x6c, 0x65, 0x73,
+	0x73, 0x12, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x4b, 0x65, 0x79, 0x6c, 0x65,
+	0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x62, 0x2e, 0x4b,
+	0x65, 0x79, 0x6c, 0x65, 0x73, 0x73, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x25, 0x5a, 0x23, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6b, 0x73, 0x70, 0x61, 0x6e,
+	0x64, 0x2f, 0x74, 0x72, 0x75, 0x73, 0x74, 0x79, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_signing_proto_rawDescOnce sync.Once
+	file_signing_proto_rawDescData = file_signing_proto_rawDesc
+)
+
+func file_signing_proto_rawDescGZIP() []byte {
+	file_signing_proto_rawDescOnce.Do(func() {
+		file_signing_proto_rawDescData = protoimpl.X.CompressGZIP(file_signing_proto_rawDescData)
+	})
+	return file_signing_proto_rawDescData
+}
+
+var file_signing_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_signing_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_signing_proto_goTypes = []interface{}{
+	(HashAlgorithm)(0),                 // 0: pb.HashAlgorithm
+	(*SignDigestRequest)(nil),          // 1: pb.SignDigestRequest
+	(*SignatureResponse)(nil),          // 2: pb.SignatureResponse
+	(*SignKeylessRequest)(nil),         // 3: pb.SignKeylessRequest
+	(*KeylessCertificateResponse)(nil), // 4: pb.KeylessCertificateResponse
+}
+var file_signing_proto_depIdxs = []int32{
+	0, // 0: pb.SignDigestRequest.hash_algorithm:type_name -> pb.HashAlgorithm
+	1, // 1: pb.SigningService.SignDigest:input_type -> pb.SignDigestRequest
+	3, // 2: pb.SigningService.SignKeyless:input_type -> pb.SignKeylessRequest
+	2, // 3: pb.SigningService.SignDigest:output_type -> pb.SignatureResponse
+	4, // 4: pb.SigningService.SignKeyless:output_type -> pb.KeylessCertificateResponse
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_signing_proto_init() }
+func file_signing_proto_init() {
+	if File_signing_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_signing_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignDigestRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_signing_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignatureResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_signing_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignKeylessRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_signing_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeylessCertificateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_signing_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_signing_proto_goTypes,
+		DependencyIndexes: file_signing_proto_depIdxs,
+		EnumInfos:         file_signing_proto_enumTypes,
+		MessageInfos:      file_signing_proto_msgTypes,
+	}.Build()
+	File_signing_proto = out.File
+	file_signing_proto_rawDesc = nil
+	file_signing_proto_goTypes = nil
+	file_signing_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// SigningServiceClient is the client API for SigningService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SigningServiceClient interface {
+	// SignDigest returns detached signature of the digest,
+	// signed with a short-lived certificate issued for the request
+	SignDigest(ctx context.Context, in *SignDigestRequest, opts ...grpc.CallOption) (*SignatureResponse, error)
+	// SignKeyless returns a short-lived certificate for the caller's public key,
+	// bound to the identity presented by the token
+	SignKeyless(ctx context.Context, in *SignKeylessRequest, opts ...grpc.CallOption) (*KeylessCertificateResponse, error)
+}
+
+type signingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSigningServiceClient(cc grpc.ClientConnInterface) SigningServiceClient {
+	return &signingServiceClient{cc}
+}
+
+func (c *signingServiceClient) SignDigest(ctx context.Context, in *SignDigestRequest, opts ...grpc.CallOption) (*SignatureResponse, error) {
+	out := new(SignatureResponse)
+	err := c.cc.Invoke(ctx, "/pb.SigningService/SignDigest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signingServiceClient) SignKeyless(ctx context.Context, in *SignKeylessRequest, opts ...grpc.CallOption) (*KeylessCertificateResponse, error) {
+	out := new(KeylessCertificateResponse)
+	err := c.cc.Invoke(ctx, "/pb.SigningService/SignKeyless", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SigningServiceServer is the server API for SigningService service.
+type SigningServiceServer interface {
+	// SignDigest returns detached signature of the digest,
+	// signed with a short-lived certificate issued for the request
+	SignDigest(context.Context, *SignDigestRequest) (*SignatureResponse, error)
+	// SignKeyless returns a short-lived certificate for the caller's public key,
+	// bound to the identity presented by the token
+	SignKeyless(context.Context, *SignKeylessRequest) (*KeylessCertificateResponse, error)
+}
+
+// UnimplementedSigningServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedSigningServiceServer struct {
+}
+
+func (*UnimplementedSigningServiceServer) SignDigest(context.Context, *SignDigestRequest) (*SignatureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignDigest not implemented")
+}
+func (*UnimplementedSigningServiceServer) SignKeyless(context.Context, *SignKeylessRequest) (*KeylessCertificateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignKeyless not implemented")
+}
+
+func RegisterSigningServiceServer(s *grpc.Server, srv SigningServiceServer) {
+	s.RegisterService(&_SigningService_serviceDesc, srv)
+}
+
+func _SigningService_SignDigest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignDigestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SigningServiceServer).SignDigest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.SigningService/SignDigest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SigningServiceServer).SignDigest(ctx, req.(*SignDigestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SigningService_SignKeyless_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignKeylessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SigningServiceServer).SignKeyless(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.SigningService/SignKeyless",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SigningServiceServer).SignKeyless(ctx, req.(*SignKeylessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _SigningService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.SigningService",
+	HandlerType: (*SigningServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SignDigest",
+			Handler:    _SigningService_SignDigest_Handler,
+		},
+		{
+			MethodName: "SignKeyless",
+			Handler:    _SigningService_SignKeyless_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "signing.proto",
+}
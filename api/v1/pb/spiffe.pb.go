@@ -0,0 +1,547 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1-devel
+// 	protoc        v3.6.1
+// source: spiffe.proto
+
+package pb
+
+import (
+	context "context"
+	reflect "reflect"
+	sync "sync"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// JWTSVIDRequest specifies the request for JWT-SVID
+type JWTSVIDRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// IssuerLabel specifies the spiffe Issuer,
+	// if not provided, then the Issuer of the caller's trust domain is used
+	IssuerLabel string `protobuf:"bytes,1,opt,name=issuer_label,json=issuerLabel,proto3" json:"issuer_label,omitempty"`
+	// Audience specifies the intended recipients of the token
+	Audience []string `protobuf:"bytes,2,rep,name=audience,proto3" json:"audience,omitempty"`
+}
+
+func (x *JWTSVIDRequest) Reset() {
+	*x = JWTSVIDRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_spiffe_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JWTSVIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JWTSVIDRequest) ProtoMessage() {}
+
+func (x *JWTSVIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spiffe_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JWTSVIDRequest.ProtoReflect.Descriptor instead.
+func (*JWTSVIDRequest) Descriptor() ([]byte, []int) {
+	return file_spiffe_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *JWTSVIDRequest) GetIssuerLabel() string {
+	if x != nil {
+		return x.IssuerLabel
+	}
+	return ""
+}
+
+func (x *JWTSVIDRequest) GetAudience() []string {
+	if x != nil {
+		return x.Audience
+	}
+	return nil
+}
+
+// JWTSVIDResponse returns JWT-SVID
+type JWTSVIDResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// SpiffeId specifies SPIFFE ID of the token subject
+	SpiffeId string `protobuf:"bytes,1,opt,name=spiffe_id,json=spiffeId,proto3" json:"spiffe_id,omitempty"`
+	// Token provides JWT-SVID
+	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	// ExpiresAt specifies the expiration time in Unix seconds
+	ExpiresAt int64 `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+}
+
+func (x *JWTSVIDResponse) Reset() {
+	*x = JWTSVIDResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_spiffe_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JWTSVIDResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JWTSVIDResponse) ProtoMessage() {}
+
+func (x *JWTSVIDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_spiffe_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JWTSVIDResponse.ProtoReflect.Descriptor instead.
+func (*JWTSVIDResponse) Descriptor() ([]byte, []int) {
+	return file_spiffe_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *JWTSVIDResponse) GetSpiffeId() string {
+	if x != nil {
+		return x.SpiffeId
+	}
+	return ""
+}
+
+func (x *JWTSVIDResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *JWTSVIDResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+// ValidateJWTSVIDRequest specifies the request to validate JWT-SVID
+type ValidateJWTSVIDRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Token provides JWT-SVID
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// Audience specifies the expected audience
+	Audience string `protobuf:"bytes,2,opt,name=audience,proto3" json:"audience,omitempty"`
+}
+
+func (x *ValidateJWTSVIDRequest) Reset() {
+	*x = ValidateJWTSVIDRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_spiffe_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValidateJWTSVIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateJWTSVIDRequest) ProtoMessage() {}
+
+func (x *ValidateJWTSVIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spiffe_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateJWTSVIDRequest.ProtoReflect.Descriptor instead.
+func (*ValidateJWTSVIDRequest) Descriptor() ([]byte, []int) {
+	return file_spiffe_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValidateJWTSVIDRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ValidateJWTSVIDRequest) GetAudience() string {
+	if x != nil {
+		return x.Audience
+	}
+	return ""
+}
+
+// ValidateJWTSVIDResponse returns the validated claims
+type ValidateJWTSVIDResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// SpiffeId specifies SPIFFE ID of the token subject
+	SpiffeId string `protobuf:"bytes,1,opt,name=spiffe_id,json=spiffeId,proto3" json:"spiffe_id,omitempty"`
+	// Audience provides the audience of the token
+	Audience []string `protobuf:"bytes,2,rep,name=audience,proto3" json:"audience,omitempty"`
+	// ExpiresAt specifies the expiration time in Unix seconds
+	ExpiresAt int64 `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+}
+
+func (x *ValidateJWTSVIDResponse) Reset() {
+	*x = ValidateJWTSVIDResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_spiffe_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValidateJWTSVIDResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateJWTSVIDResponse) ProtoMessage() {}
+
+func (x *ValidateJWTSVIDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_spiffe_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateJWTSVIDResponse.ProtoReflect.Descriptor instead.
+func (*ValidateJWTSVIDResponse) Descriptor() ([]byte, []int) {
+	return file_spiffe_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ValidateJWTSVIDResponse) GetSpiffeId() string {
+	if x != nil {
+		return x.SpiffeId
+	}
+	return ""
+}
+
+func (x *ValidateJWTSVIDResponse) GetAudience() []string {
+	if x != nil {
+		return x.Audience
+	}
+	return nil
+}
+
+func (x *ValidateJWTSVIDResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+var File_spiffe_proto protoreflect.FileDescriptor
+
+var file_spiffe_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x73, 0x70, 0x69, 0x66, 0x66, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02,
+	0x70, 0x62, 0x22, 0x4f, 0x0a, 0x0e, 0x4a, 0x57, 0x54, 0x53, 0x56, 0x49, 0x44, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x5f, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x73, 0x73, 0x75,
+	0x65, 0x72, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x75, 0x64, 0x69, 0x65,
+	0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x61, 0x75, 0x64, 0x69, 0x65,
+	0x6e, 0x63, 0x65, 0x22, 0x63, 0x0a, 0x0f, 0x4a, 0x57, 0x54, 0x53, 0x56, 0x49, 0x44, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x70, 0x69, 0x66, 0x66, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x70, 0x69, 0x66, 0x66,
+	0x65, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x78, 0x70,
+	0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x65,
+	0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x22, 0x4a, 0x0a, 0x16, 0x56, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x65, 0x4a, 0x57, 0x54, 0x53, 0x56, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x75, 0x64, 0x69,
+	0x65, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x75, 0x64, 0x69,
+	0x65, 0x6e, 0x63, 0x65, 0x22, 0x71, 0x0a, 0x17, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x4a, 0x57, 0x54, 0x53, 0x56, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x73, 0x70, 0x69, 0x66, 0x66, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x73, 0x70, 0x69, 0x66, 0x66, 0x65, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08,
+	0x61, 0x75, 0x64, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08,
+	0x61, 0x75, 0x64, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69,
+	0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x32, 0x97, 0x01, 0x0a, 0x0d, 0x53, 0x50, 0x49, 0x46,
+	0x46, 0x45, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x38, 0x0a, 0x0b, 0x53, 0x69, 0x67,
+	0x6e, 0x4a, 0x57, 0x54, 0x53, 0x56, 0x49, 0x44, 0x12, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x4a, 0x57,
+	0x54, 0x53, 0x56, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x70,
+	0x62, 0x2e, 0x4a, 0x57, 0x54, 0x53, 0x56, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x0f, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x4a,
+	0x57, 0x54, 0x53, 0x56, 0x49, 0x44, 0x12, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x56, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x65, 0x4a, 0x57, 0x54, 0x53, 0x56, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x4a, 0x57, 0x54, 0x53, 0x56, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x42, 0x25, 0x5a, 0x23, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x65, 0x6b, 0x73, 0x70, 0x61, 0x6e, 0x64, 0x2f, 0x74, 0x72, 0x75, 0x73, 0x74, 0x79, 0x2f, 0x61,
+	0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_spiffe_proto_rawDescOnce sync.Once
+	file_spiffe_proto_rawDescData = file_spiffe_proto_rawDesc
+)
+
+func file_spiffe_proto_rawDescGZIP() []byte {
+	file_spiffe_proto_rawDescOnce.Do(func() {
+		file_spiffe_proto_rawDescData = protoimpl.X.CompressGZIP(file_spiffe_proto_rawDescData)
+	})
+	return file_spiffe_proto_rawDescData
+}
+
+var file_spiffe_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_spiffe_proto_goTypes = []interface{}{
+	(*JWTSVIDRequest)(nil),          // 0: pb.JWTSVIDRequest
+	(*JWTSVIDResponse)(nil),         // 1: pb.JWTSVIDResponse
+	(*ValidateJWTSVIDRequest)(nil),  // 2: pb.ValidateJWTSVIDRequest
+	(*ValidateJWTSVIDResponse)(nil), // 3: pb.ValidateJWTSVIDResponse
+}
+var file_spiffe_proto_depIdxs = []int32{
+	0, // 0: pb.SPIFFEService.SignJWTSVID:input_type -> pb.JWTSVIDRequest
+	2, // 1: pb.SPIFFEService.ValidateJWTSVID:input_type -> pb.ValidateJWTSVIDRequest
+	1, // 2: pb.SPIFFEService.SignJWTSVID:output_type -> pb.JWTSVIDResponse
+	3, // 3: pb.SPIFFEService.ValidateJWTSVID:output_type -> pb.ValidateJWTSVIDResponse
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_spiffe_proto_init() }
+func file_spiffe_proto_init() {
+	if File_spiffe_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_spiffe_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JWTSVIDRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_spiffe_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JWTSVIDResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_spiffe_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ValidateJWTSVIDRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_spiffe_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ValidateJWTSVIDResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_spiffe_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_spiffe_proto_goTypes,
+		DependencyIndexes: file_spiffe_proto_depIdxs,
+		MessageInfos:      file_spiffe_proto_msgTypes,
+	}.Build()
+	File_spiffe_proto = out.File
+	file_spiffe_proto_rawDesc = nil
+	file_spiffe_proto_goTypes = nil
+	file_spiffe_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// SPIFFEServiceClient is the client API for SPIFFEService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SPIFFEServiceClient interface {
+	// SignJWTSVID returns JWT-SVID for SPIFFE ID of the caller,
+	// authenticated with X.509-SVID
+	SignJWTSVID(ctx context.Context, in *JWTSVIDRequest, opts ...grpc.CallOption) (*JWTSVIDResponse, error)
+	// ValidateJWTSVID validates JWT-SVID for the audience
+	ValidateJWTSVID(ctx context.Context, in *ValidateJWTSVIDRequest, opts ...grpc.CallOption) (*ValidateJWTSVIDResponse, error)
+}
+
+type sPIFFEServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSPIFFEServiceClient(cc grpc.ClientConnInterface) SPIFFEServiceClient {
+	return &sPIFFEServiceClient{cc}
+}
+
+func (c *sPIFFEServiceClient) SignJWTSVID(ctx context.Context, in *JWTSVIDRequest, opts ...grpc.CallOption) (*JWTSVIDResponse, error) {
+	out := new(JWTSVIDResponse)
+	err := c.cc.Invoke(ctx, "/pb.SPIFFEService/SignJWTSVID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sPIFFEServiceClient) ValidateJWTSVID(ctx context.Context, in *ValidateJWTSVIDRequest, opts ...grpc.CallOption) (*ValidateJWTSVIDResponse, error) {
+	out := new(ValidateJWTSVIDResponse)
+	err := c.cc.Invoke(ctx, "/pb.SPIFFEService/ValidateJWTSVID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SPIFFEServiceServer is the server API for SPIFFEService service.
+type SPIFFEServiceServer interface {
+	// SignJWTSVID returns JWT-SVID for SPIFFE ID of the caller,
+	// authenticated with X.509-SVID
+	SignJWTSVID(context.Context, *JWTSVIDRequest) (*JWTSVIDResponse, error)
+	// ValidateJWTSVID validates JWT-SVID for the audience
+	ValidateJWTSVID(context.Context, *ValidateJWTSVIDRequest) (*ValidateJWTSVIDResponse, error)
+}
+
+// UnimplementedSPIFFEServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedSPIFFEServiceServer struct {
+}
+
+func (*UnimplementedSPIFFEServiceServer) SignJWTSVID(context.Context, *JWTSVIDRequest) (*JWTSVIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignJWTSVID not implemented")
+}
+func (*UnimplementedSPIFFEServiceServer) ValidateJWTSVID(context.Context, *ValidateJWTSVIDRequest) (*ValidateJWTSVIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateJWTSVID not implemented")
+}
+
+func RegisterSPIFFEServiceServer(s *grpc.Server, srv SPIFFEServiceServer) {
+	s.RegisterService(&_SPIFFEService_serviceDesc, srv)
+}
+
+func _SPIFFEService_SignJWTSVID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JWTSVIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SPIFFEServiceServer).SignJWTSVID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.SPIFFEService/SignJWTSVID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SPIFFEServiceServer).SignJWTSVID(ctx, req.(*JWTSVIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SPIFFEService_ValidateJWTSVID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateJWTSVIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SPIFFEServiceServer).ValidateJWTSVID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.SPIFFEService/ValidateJWTSVID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SPIFFEServiceServer).ValidateJWTSVID(ctx, req.(*ValidateJWTSVIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _SPIFFEService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.SPIFFEService",
+	HandlerType: (*SPIFFEServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SignJWTSVID",
+			Handler:    _SPIFFEService_SignJWTSVID_Handler,
+		},
+		{
+			MethodName: "ValidateJWTSVID",
+			Handler:    _SPIFFEService_ValidateJWTSVID_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "spiffe.proto",
+}
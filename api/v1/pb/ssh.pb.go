@@ -0,0 +1,995 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1-devel
+// 	protoc        v3.6.1
+// source: ssh.proto
+
+package pb
+
+import (
+	context "context"
+	reflect "reflect"
+	sync "sync"
+
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SSHCertificate provides SSH certificate information
+type SSHCertificate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Id of the certificate
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// OrgId of the certificate, only used with Org scope
+	OrgId uint64 `protobuf:"varint,2,opt,name=org_id,proto3" json:"org_id,omitempty"`
+	// Ikid provides SHA256 fingerprint of the CA key
+	Ikid string `protobuf:"bytes,3,opt,name=ikid,proto3" json:"ikid,omitempty"`
+	// SerialNumber provides Serial Number
+	SerialNumber string `protobuf:"bytes,4,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	// KeyId provides Key ID of the certificate
+	KeyId string `protobuf:"bytes,5,opt,name=key_id,proto3" json:"key_id,omitempty"`
+	// CertType specifies type of the certificate: user|host
+	CertType string `protobuf:"bytes,6,opt,name=cert_type,proto3" json:"cert_type,omitempty"`
+	// Principals provides the list of valid principals
+	Principals []string `protobuf:"bytes,7,rep,name=principals,proto3" json:"principals,omitempty"`
+	// NotBefore is the time when the validity period starts
+	NotBefore *timestamp.Timestamp `protobuf:"bytes,8,opt,name=not_before,proto3" json:"not_before,omitempty"`
+	// NotAfter is the time when the validity period ends
+	NotAfter *timestamp.Timestamp `protobuf:"bytes,9,opt,name=not_after,proto3" json:"not_after,omitempty"`
+	// SHA256 thumbprint of the certificate
+	Sha256 string `protobuf:"bytes,10,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// Profile of the certificate
+	Profile string `protobuf:"bytes,11,opt,name=profile,proto3" json:"profile,omitempty"`
+	// Certificate in authorized_keys format
+	Certificate string `protobuf:"bytes,12,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	// RevokedAt is the time when the certificate was revoked
+	RevokedAt *timestamp.Timestamp `protobuf:"bytes,13,opt,name=revoked_at,proto3" json:"revoked_at,omitempty"`
+	// Reason specifies revocation reason
+	Reason Reason `protobuf:"varint,14,opt,name=reason,proto3,enum=pb.Reason" json:"reason,omitempty"`
+}
+
+func (x *SSHCertificate) Reset() {
+	*x = SSHCertificate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ssh_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SSHCertificate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SSHCertificate) ProtoMessage() {}
+
+func (x *SSHCertificate) ProtoReflect() protoreflect.Message {
+	mi := &file_ssh_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SSHCertificate.ProtoReflect.Descriptor instead.
+func (*SSHCertificate) Descriptor() ([]byte, []int) {
+	return file_ssh_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SSHCertificate) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SSHCertificate) GetOrgId() uint64 {
+	if x != nil {
+		return x.OrgId
+	}
+	return 0
+}
+
+func (x *SSHCertificate) GetIkid() string {
+	if x != nil {
+		return x.Ikid
+	}
+	return ""
+}
+
+func (x *SSHCertificate) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *SSHCertificate) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *SSHCertificate) GetCertType() string {
+	if x != nil {
+		return x.CertType
+	}
+	return ""
+}
+
+func (x *SSHCertificate) GetPrincipals() []string {
+	if x != nil {
+		return x.Principals
+	}
+	return nil
+}
+
+func (x *SSHCertificate) GetNotBefore() *timestamp.Timestamp {
+	if x != nil {
+		return x.NotBefore
+	}
+	return nil
+}
+
+func (x *SSHCertificate) GetNotAfter() *timestamp.Timestamp {
+	if x != nil {
+		return x.NotAfter
+	}
+	return nil
+}
+
+func (x *SSHCertificate) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *SSHCertificate) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *SSHCertificate) GetCertificate() string {
+	if x != nil {
+		return x.Certificate
+	}
+	return ""
+}
+
+func (x *SSHCertificate) GetRevokedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
+
+func (x *SSHCertificate) GetReason() Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return Reason_UNSPECIFIED
+}
+
+// SignSSHKeyRequest specifies the request to sign SSH public key
+type SignSSHKeyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Profile specifies the SSH certificate profile
+	Profile string `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	// PublicKey provides the public key in authorized_keys format
+	PublicKey string `protobuf:"bytes,2,opt,name=public_key,proto3" json:"public_key,omitempty"`
+	// KeyId specifies Key ID of the certificate,
+	// if not provided, then the caller's name is used,
+	// otherwise it must start with the caller's name followed by "/"
+	KeyId string `protobuf:"bytes,3,opt,name=key_id,proto3" json:"key_id,omitempty"`
+	// Principals specifies the requested principals,
+	// if not provided, then all allowed principals are included
+	Principals []string `protobuf:"bytes,4,rep,name=principals,proto3" json:"principals,omitempty"`
+	// OrgId specifies Org ID of the certificate,
+	// the caller must be a member of the organization
+	OrgId uint64 `protobuf:"varint,5,opt,name=org_id,proto3" json:"org_id,omitempty"`
+}
+
+func (x *SignSSHKeyRequest) Reset() {
+	*x = SignSSHKeyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ssh_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignSSHKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignSSHKeyRequest) ProtoMessage() {}
+
+func (x *SignSSHKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ssh_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignSSHKeyRequest.ProtoReflect.Descriptor instead.
+func (*SignSSHKeyRequest) Descriptor() ([]byte, []int) {
+	return file_ssh_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SignSSHKeyRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *SignSSHKeyRequest) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *SignSSHKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *SignSSHKeyRequest) GetPrincipals() []string {
+	if x != nil {
+		return x.Principals
+	}
+	return nil
+}
+
+func (x *SignSSHKeyRequest) GetOrgId() uint64 {
+	if x != nil {
+		return x.OrgId
+	}
+	return 0
+}
+
+// SSHCertificateResponse returns SSH certificate
+type SSHCertificateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Certificate *SSHCertificate `protobuf:"bytes,1,opt,name=certificate,proto3" json:"certificate,omitempty"`
+}
+
+func (x *SSHCertificateResponse) Reset() {
+	*x = SSHCertificateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ssh_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SSHCertificateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SSHCertificateResponse) ProtoMessage() {}
+
+func (x *SSHCertificateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ssh_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SSHCertificateResponse.ProtoReflect.Descriptor instead.
+func (*SSHCertificateResponse) Descriptor() ([]byte, []int) {
+	return file_ssh_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SSHCertificateResponse) GetCertificate() *SSHCertificate {
+	if x != nil {
+		return x.Certificate
+	}
+	return nil
+}
+
+// RevokeSSHCertificateRequest specifies the request to revoke SSH certificate
+type RevokeSSHCertificateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Ikid provides SHA256 fingerprint of the CA key
+	Ikid string `protobuf:"bytes,1,opt,name=ikid,proto3" json:"ikid,omitempty"`
+	// SerialNumber provides Serial Number
+	SerialNumber string `protobuf:"bytes,2,opt,name=serial_number,proto3" json:"serial_number,omitempty"`
+	// Reason specifies revocation reason
+	Reason Reason `protobuf:"varint,3,opt,name=reason,proto3,enum=pb.Reason" json:"reason,omitempty"`
+}
+
+func (x *RevokeSSHCertificateRequest) Reset() {
+	*x = RevokeSSHCertificateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ssh_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevokeSSHCertificateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSSHCertificateRequest) ProtoMessage() {}
+
+func (x *RevokeSSHCertificateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ssh_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSSHCertificateRequest.ProtoReflect.Descriptor instead.
+func (*RevokeSSHCertificateRequest) Descriptor() ([]byte, []int) {
+	return file_ssh_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RevokeSSHCertificateRequest) GetIkid() string {
+	if x != nil {
+		return x.Ikid
+	}
+	return ""
+}
+
+func (x *RevokeSSHCertificateRequest) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *RevokeSSHCertificateRequest) GetReason() Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return Reason_UNSPECIFIED
+}
+
+// PublishKRLRequest specifies the request to publish KRL
+type PublishKRLRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// IssuerLabel specifies the ssh Issuer,
+	// if not provided, then KRL is published for all ssh Issuers
+	IssuerLabel string `protobuf:"bytes,1,opt,name=issuer_label,proto3" json:"issuer_label,omitempty"`
+}
+
+func (x *PublishKRLRequest) Reset() {
+	*x = PublishKRLRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ssh_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublishKRLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishKRLRequest) ProtoMessage() {}
+
+func (x *PublishKRLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ssh_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishKRLRequest.ProtoReflect.Descriptor instead.
+func (*PublishKRLRequest) Descriptor() ([]byte, []int) {
+	return file_ssh_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PublishKRLRequest) GetIssuerLabel() string {
+	if x != nil {
+		return x.IssuerLabel
+	}
+	return ""
+}
+
+// KRL provides OpenSSH Key Revocation List
+type KRL struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Ikid provides SHA256 fingerprint of the CA key
+	Ikid string `protobuf:"bytes,1,opt,name=ikid,proto3" json:"ikid,omitempty"`
+	// IssuerLabel specifies the ssh Issuer
+	IssuerLabel string `protobuf:"bytes,2,opt,name=issuer_label,proto3" json:"issuer_label,omitempty"`
+	// Version provides KRL version
+	Version uint64 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// ThisUpdate is the time when the KRL was generated
+	ThisUpdate *timestamp.Timestamp `protobuf:"bytes,4,opt,name=this_update,proto3" json:"this_update,omitempty"`
+	// Krl provides KRL in binary format
+	Krl []byte `protobuf:"bytes,5,opt,name=krl,proto3" json:"krl,omitempty"`
+}
+
+func (x *KRL) Reset() {
+	*x = KRL{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ssh_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KRL) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KRL) ProtoMessage() {}
+
+func (x *KRL) ProtoReflect() protoreflect.Message {
+	mi := &file_ssh_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KRL.ProtoReflect.Descriptor instead.
+func (*KRL) Descriptor() ([]byte, []int) {
+	return file_ssh_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *KRL) GetIkid() string {
+	if x != nil {
+		return x.Ikid
+	}
+	return ""
+}
+
+func (x *KRL) GetIssuerLabel() string {
+	if x != nil {
+		return x.IssuerLabel
+	}
+	return ""
+}
+
+func (x *KRL) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *KRL) GetThisUpdate() *timestamp.Timestamp {
+	if x != nil {
+		return x.ThisUpdate
+	}
+	return nil
+}
+
+func (x *KRL) GetKrl() []byte {
+	if x != nil {
+		return x.Krl
+	}
+	return nil
+}
+
+// KRLResponse returns published KRLs
+type KRLResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Krls []*KRL `protobuf:"bytes,1,rep,name=krls,proto3" json:"krls,omitempty"`
+}
+
+func (x *KRLResponse) Reset() {
+	*x = KRLResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ssh_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KRLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KRLResponse) ProtoMessage() {}
+
+func (x *KRLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ssh_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KRLResponse.ProtoReflect.Descriptor instead.
+func (*KRLResponse) Descriptor() ([]byte, []int) {
+	return file_ssh_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *KRLResponse) GetKrls() []*KRL {
+	if x != nil {
+		return x.Krls
+	}
+	return nil
+}
+
+var File_ssh_proto protoreflect.FileDescriptor
+
+var file_ssh_proto_rawDesc = []byte{
+	0x0a, 0x09, 0x73, 0x73, 0x68, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x1a,
+	0x0a, 0x70, 0x6b, 0x69, 0x78, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf1, 0x03, 0x0a,
+	0x0e, 0x53, 0x53, 0x48, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x6f, 0x72, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x06, 0x6f, 0x72, 0x67, 0x5f, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x6b, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x6b, 0x69, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x73,
+	0x65, 0x72, 0x69, 0x61, 0x6c, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x12, 0x16, 0x0a, 0x06, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x65, 0x72, 0x74,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x65, 0x72,
+	0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x69, 0x6e, 0x63, 0x69,
+	0x70, 0x61, 0x6c, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x72, 0x69, 0x6e,
+	0x63, 0x69, 0x70, 0x61, 0x6c, 0x73, 0x12, 0x3a, 0x0a, 0x0a, 0x6e, 0x6f, 0x74, 0x5f, 0x62, 0x65,
+	0x66, 0x6f, 0x72, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x6e, 0x6f, 0x74, 0x5f, 0x62, 0x65, 0x66, 0x6f,
+	0x72, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x6e, 0x6f, 0x74, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x09, 0x6e, 0x6f, 0x74, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x68,
+	0x61, 0x32, 0x35, 0x36, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x20,
+	0x0a, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65,
+	0x12, 0x3a, 0x0a, 0x0a, 0x72, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0d,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0a, 0x72, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x12, 0x22, 0x0a, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x70,
+	0x62, 0x2e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x22, 0x9d, 0x01, 0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x53, 0x53, 0x48, 0x4b, 0x65, 0x79, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
+	0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79,
+	0x12, 0x16, 0x0a, 0x06, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x69, 0x6e,
+	0x63, 0x69, 0x70, 0x61, 0x6c, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x72,
+	0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x72, 0x67, 0x5f,
+	0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x72, 0x67, 0x5f, 0x69, 0x64,
+	0x22, 0x4e, 0x0a, 0x16, 0x53, 0x53, 0x48, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x0b, 0x63, 0x65,
+	0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x53, 0x48, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x52, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65,
+	0x22, 0x7b, 0x0a, 0x1b, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x53, 0x53, 0x48, 0x43, 0x65, 0x72,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x69, 0x6b, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69,
+	0x6b, 0x69, 0x64, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x5f, 0x6e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x65, 0x72, 0x69,
+	0x61, 0x6c, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x06, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x70, 0x62, 0x2e, 0x52,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x37, 0x0a,
+	0x11, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x4b, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x5f, 0x6c, 0x61, 0x62,
+	0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72,
+	0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0xa7, 0x01, 0x0a, 0x03, 0x4b, 0x52, 0x4c, 0x12, 0x12,
+	0x0a, 0x04, 0x69, 0x6b, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x6b,
+	0x69, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x5f, 0x6c, 0x61, 0x62,
+	0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72,
+	0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x3c, 0x0a, 0x0b, 0x74, 0x68, 0x69, 0x73, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x0b, 0x74, 0x68, 0x69, 0x73, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x72, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x72, 0x6c,
+	0x22, 0x2a, 0x0a, 0x0b, 0x4b, 0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x1b, 0x0a, 0x04, 0x6b, 0x72, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x07, 0x2e,
+	0x70, 0x62, 0x2e, 0x4b, 0x52, 0x4c, 0x52, 0x04, 0x6b, 0x72, 0x6c, 0x73, 0x32, 0xde, 0x01, 0x0a,
+	0x0a, 0x53, 0x53, 0x48, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x41, 0x0a, 0x0a, 0x53,
+	0x69, 0x67, 0x6e, 0x53, 0x53, 0x48, 0x4b, 0x65, 0x79, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x53,
+	0x69, 0x67, 0x6e, 0x53, 0x53, 0x48, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x53, 0x48, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x55,
+	0x0a, 0x14, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x53, 0x53, 0x48, 0x43, 0x65, 0x72, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f,
+	0x6b, 0x65, 0x53, 0x53, 0x48, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x53, 0x48,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x36, 0x0a, 0x0a, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x4b, 0x52, 0x4c, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x4b, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x70, 0x62, 0x2e,
+	0x4b, 0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x25, 0x5a,
+	0x23, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6b, 0x73, 0x70,
+	0x61, 0x6e, 0x64, 0x2f, 0x74, 0x72, 0x75, 0x73, 0x74, 0x79, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76,
+	0x31, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_ssh_proto_rawDescOnce sync.Once
+	file_ssh_proto_rawDescData = file_ssh_proto_rawDesc
+)
+
+func file_ssh_proto_rawDescGZIP() []byte {
+	file_ssh_proto_rawDescOnce.Do(func() {
+		file_ssh_proto_rawDescData = protoimpl.X.CompressGZIP(file_ssh_proto_rawDescData)
+	})
+	return file_ssh_proto_rawDescData
+}
+
+var file_ssh_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_ssh_proto_goTypes = []interface{}{
+	(*SSHCertificate)(nil),              // 0: pb.SSHCertificate
+	(*SignSSHKeyRequest)(nil),           // 1: pb.SignSSHKeyRequest
+	(*SSHCertificateResponse)(nil),      // 2: pb.SSHCertificateResponse
+	(*RevokeSSHCertificateRequest)(nil), // 3: pb.RevokeSSHCertificateRequest
+	(*PublishKRLRequest)(nil),           // 4: pb.PublishKRLRequest
+	(*KRL)(nil),                         // 5: pb.KRL
+	(*KRLResponse)(nil),                 // 6: pb.KRLResponse
+	(*timestamp.Timestamp)(nil),         // 7: google.protobuf.Timestamp
+	(Reason)(0),                         // 8: pb.Reason
+}
+var file_ssh_proto_depIdxs = []int32{
+	7,  // 0: pb.SSHCertificate.not_before:type_name -> google.protobuf.Timestamp
+	7,  // 1: pb.SSHCertificate.not_after:type_name -> google.protobuf.Timestamp
+	7,  // 2: pb.SSHCertificate.revoked_at:type_name -> google.protobuf.Timestamp
+	8,  // 3: pb.SSHCertificate.reason:type_name -> pb.Reason
+	0,  // 4: pb.SSHCertificateResponse.certificate:type_name -> pb.SSHCertificate
+	8,  // 5: pb.RevokeSSHCertificateRequest.reason:type_name -> pb.Reason
+	7,  // 6: pb.KRL.this_update:type_name -> google.protobuf.Timestamp
+	5,  // 7: pb.KRLResponse.krls:type_name -> pb.KRL
+	1,  // 8: pb.SSHService.SignSSHKey:input_type -> pb.SignSSHKeyRequest
+	3,  // 9: pb.SSHService.RevokeSSHCertificate:input_type -> pb.RevokeSSHCertificateRequest
+	4,  // 10: pb.SSHService.PublishKRL:input_type -> pb.PublishKRLRequest
+	2,  // 11: pb.SSHService.SignSSHKey:output_type -> pb.SSHCertificateResponse
+	2,  // 12: pb.SSHService.RevokeSSHCertificate:output_type -> pb.SSHCertificateResponse
+	6,  // 13: pb.SSHService.PublishKRL:output_type -> pb.KRLResponse
+	11, // [11:14] is the sub-list for method output_type
+	8,  // [8:11] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_ssh_proto_init() }
+func file_ssh_proto_init() {
+	if File_ssh_proto != nil {
+		return
+	}
+	file_pkix_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_ssh_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SSHCertificate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ssh_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignSSHKeyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ssh_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SSHCertificateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ssh_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeSSHCertificateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ssh_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishKRLRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ssh_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KRL); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ssh_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KRLResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_ssh_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ssh_proto_goTypes,
+		DependencyIndexes: file_ssh_proto_depIdxs,
+		MessageInfos:      file_ssh_proto_msgTypes,
+	}.Build()
+	File_ssh_proto = out.File
+	file_ssh_proto_rawDesc = nil
+	file_ssh_proto_goTypes = nil
+	file_ssh_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// SSHServiceClient is the client API for SSHService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SSHServiceClient interface {
+	// SignSSHKey returns SSH certificate for the public key,
+	// with the principals allowed for the caller
+	SignSSHKey(ctx context.Context, in *SignSSHKeyRequest, opts ...grpc.CallOption) (*SSHCertificateResponse, error)
+	// RevokeSSHCertificate returns the revoked SSH certificate
+	RevokeSSHCertificate(ctx context.Context, in *RevokeSSHCertificateRequest, opts ...grpc.CallOption) (*SSHCertificateResponse, error)
+	// PublishKRL returns published Key Revocation Lists
+	PublishKRL(ctx context.Context, in *PublishKRLRequest, opts ...grpc.CallOption) (*KRLResponse, error)
+}
+
+type sSHServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSSHServiceClient(cc grpc.ClientConnInterface) SSHServiceClient {
+	return &sSHServiceClient{cc}
+}
+
+func (c *sSHServiceClient) SignSSHKey(ctx context.Context, in *SignSSHKeyRequest, opts ...grpc.CallOption) (*SSHCertificateResponse, error) {
+	out := new(SSHCertificateResponse)
+	err := c.cc.Invoke(ctx, "/pb.SSHService/SignSSHKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sSHServiceClient) RevokeSSHCertificate(ctx context.Context, in *RevokeSSHCertificateRequest, opts ...grpc.CallOption) (*SSHCertificateResponse, error) {
+	out := new(SSHCertificateResponse)
+	err := c.cc.Invoke(ctx, "/pb.SSHService/RevokeSSHCertificate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sSHServiceClient) PublishKRL(ctx context.Context, in *PublishKRLRequest, opts ...grpc.CallOption) (*KRLResponse, error) {
+	out := new(KRLResponse)
+	err := c.cc.Invoke(ctx, "/pb.SSHService/PublishKRL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SSHServiceServer is the server API for SSHService service.
+type SSHServiceServer interface {
+	// SignSSHKey returns SSH certificate for the public key,
+	// with the principals allowed for the caller
+	SignSSHKey(context.Context, *SignSSHKeyRequest) (*SSHCertificateResponse, error)
+	// RevokeSSHCertificate returns the revoked SSH certificate
+	RevokeSSHCertificate(context.Context, *RevokeSSHCertificateRequest) (*SSHCertificateResponse, error)
+	// PublishKRL returns published Key Revocation Lists
+	PublishKRL(context.Context, *PublishKRLRequest) (*KRLResponse, error)
+}
+
+// UnimplementedSSHServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedSSHServiceServer struct {
+}
+
+func (*UnimplementedSSHServiceServer) SignSSHKey(context.Context, *SignSSHKeyRequest) (*SSHCertificateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignSSHKey not implemented")
+}
+func (*UnimplementedSSHServiceServer) RevokeSSHCertificate(context.Context, *RevokeSSHCertificateRequest) (*SSHCertificateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSSHCertificate not implemented")
+}
+func (*UnimplementedSSHServiceServer) PublishKRL(context.Context, *PublishKRLRequest) (*KRLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishKRL not implemented")
+}
+
+func RegisterSSHServiceServer(s *grpc.Server, srv SSHServiceServer) {
+	s.RegisterService(&_SSHService_serviceDesc, srv)
+}
+
+func _SSHService_SignSSHKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignSSHKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SSHServiceServer).SignSSHKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.SSHService/SignSSHKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SSHServiceServer).SignSSHKey(ctx, req.(*SignSSHKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SSHService_RevokeSSHCertificate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSSHCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SSHServiceServer).RevokeSSHCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.SSHService/RevokeSSHCertificate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SSHServiceServer).RevokeSSHCertificate(ctx, req.(*RevokeSSHCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SSHService_PublishKRL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishKRLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SSHServiceServer).PublishKRL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.SSHService/PublishKRL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SSHServiceServer).PublishKRL(ctx, req.(*PublishKRLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _SSHService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.SSHService",
+	HandlerType: (*SSHServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SignSSHKey",
+			Handler:    _SSHService_SignSSHKey_Handler,
+		},
+		{
+			MethodName: "RevokeSSHCertificate",
+			Handler:    _SSHService_RevokeSSHCertificate_Handler,
+		},
+		{
+			MethodName: "PublishKRL",
+			Handler:    _SSHService_PublishKRL_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ssh.proto",
+}
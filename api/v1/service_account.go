@@ -0,0 +1,73 @@
+package v1
+
+import "time"
+
+// APITokenPrefix specifies the prefix of API tokens,
+// to distinguish them from JWT in Authorization header
+const APITokenPrefix = "trusty_"
+
+// ServiceAccount represents an org-scoped account for automation
+type ServiceAccount struct {
+	ID          string    `json:"id"`
+	OrgID       string    `json:"org_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// APIToken provides information about API token of a service account,
+// the token value itself is never returned, except on creation
+type APIToken struct {
+	ID               string     `json:"id"`
+	ServiceAccountID string     `json:"service_account_id"`
+	Name             string     `json:"name"`
+	CreatedBy        string     `json:"created_by"`
+	CreatedAt        time.Time  `json:"created_at"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt       *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateServiceAccountRequest specifies a new service account
+type CreateServiceAccountRequest struct {
+	OrgID       string `json:"org_id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// ServiceAccountResponse returns the service account
+type ServiceAccountResponse struct {
+	ServiceAccount *ServiceAccount `json:"service_account"`
+}
+
+// ServiceAccountsResponse returns the list of service accounts
+type ServiceAccountsResponse struct {
+	ServiceAccounts []*ServiceAccount `json:"service_accounts"`
+}
+
+// CreateAPITokenRequest specifies a new API token
+type CreateAPITokenRequest struct {
+	Name string `json:"name"`
+	// Expiry specifies the token lifetime in Go duration format, for example 720h,
+	// if not provided, the token does not expire
+	Expiry string `json:"expiry,omitempty"`
+}
+
+// CreateAPITokenResponse returns the created API token
+type CreateAPITokenResponse struct {
+	Token *APIToken `json:"token"`
+	// Secret is the token value to be used as Bearer in Authorization header.
+	// It is returned only once and can not be retrieved later.
+	Secret string `json:"secret"`
+}
+
+// APITokenResponse returns the API token
+type APITokenResponse struct {
+	Token *APIToken `json:"token"`
+}
+
+// APITokensResponse returns the list of API tokens
+type APITokensResponse struct {
+	Tokens []*APIToken `json:"tokens"`
+}
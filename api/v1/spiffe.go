@@ -0,0 +1,13 @@
+package v1
+
+import "github.com/ekspand/trusty/pkg/jwk"
+
+// SpiffeBundle provides SPIFFE trust bundle in JWKS format
+type SpiffeBundle struct {
+	// Keys provides X.509 and JWT authorities of the trust domain
+	Keys []*jwk.Key `json:"keys"`
+	// Sequence specifies the bundle sequence number
+	Sequence uint64 `json:"spiffe_sequence,omitempty"`
+	// RefreshHint specifies the interval in seconds to refresh the bundle
+	RefreshHint int64 `json:"spiffe_refresh_hint,omitempty"`
+}
@@ -0,0 +1,53 @@
+package authority
+
+import (
+	"time"
+
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/juju/errors"
+)
+
+// DefaultApprovalExpiry specifies the default time to wait for approvals
+const DefaultApprovalExpiry = 72 * time.Hour
+
+// ApprovalPolicy specifies manual approval of the certificate requests
+type ApprovalPolicy struct {
+	// Approvers specifies the roles allowed to approve the requests
+	Approvers []string `json:"approvers" yaml:"approvers"`
+	// Approvals specifies the number of approvals from different approvers,
+	// required to issue the certificate, the default is 1
+	Approvals int `json:"approvals,omitempty" yaml:"approvals,omitempty"`
+	// Expiry specifies the time to wait for approvals,
+	// after which the pending request expires, the default is 72h
+	Expiry csr.Duration `json:"expiry,omitempty" yaml:"expiry,omitempty"`
+}
+
+// Validate returns error if the policy is not valid
+func (p *ApprovalPolicy) Validate() error {
+	if len(p.Approvers) == 0 {
+		return errors.New("missing approvers")
+	}
+	if p.Approvals < 0 {
+		return errors.Errorf("invalid approvals: %d", p.Approvals)
+	}
+	if p.Expiry < 0 {
+		return errors.Errorf("invalid expiry: %s", p.Expiry.TimeDuration())
+	}
+	return nil
+}
+
+// GetApprovals returns the number of required approvals
+func (p *ApprovalPolicy) GetApprovals() int {
+	if p.Approvals > 0 {
+		return p.Approvals
+	}
+	return 1
+}
+
+// GetExpiry returns the time to wait for approvals
+func (p *ApprovalPolicy) GetExpiry() time.Duration {
+	if p.Expiry > 0 {
+		return p.Expiry.TimeDuration()
+	}
+	return DefaultApprovalExpiry
+}
@@ -1,6 +1,12 @@
 package authority
 
 import (
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/csr"
 	"github.com/go-phorce/dolly/xlog"
 	"github.com/go-phorce/dolly/xpki/cryptoprov"
 	"github.com/juju/errors"
@@ -10,8 +16,8 @@ var logger = xlog.NewPackageLogger("github.com/ekspand/trusty", "authority")
 
 // Authority defines the CA
 type Authority struct {
-	issuers          map[string]*Issuer // label => Issuer
-	issuersByProfile map[string]*Issuer // cert profile => Issuer
+	issuers          map[string]*Issuer         // label => Issuer
+	issuersByProfile map[string]*profileIssuers // cert profile => Issuers
 
 	// Crypto holds providers for HSM, SoftHSM, KMS, etc.
 	crypto *cryptoprov.Crypto
@@ -26,13 +32,21 @@ func NewAuthority(cfg *Config, crypto *cryptoprov.Crypto) (*Authority, error) {
 	ca := &Authority{
 		crypto:           crypto,
 		issuers:          make(map[string]*Issuer),
-		issuersByProfile: make(map[string]*Issuer),
+		issuersByProfile: make(map[string]*profileIssuers),
 	}
 
 	ocspNextUpdate := cfg.Authority.DefaultAIA.GetOCSPExpiry()
 	crlNextUpdate := cfg.Authority.DefaultAIA.GetCRLExpiry()
 	crlRenewal := cfg.Authority.DefaultAIA.GetCRLRenewal()
 
+	weakKeys, err := csr.LoadWeakKeys(cfg.Authority.WeakKeys...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if weakKeys.Len() > 0 {
+		logger.Infof("reason=weak_keys, count=%d", weakKeys.Len())
+	}
+
 	for _, isscfg := range cfg.Authority.Issuers {
 		if isscfg.GetDisabled() {
 			logger.Infof("reason=disabled, issuer=%s", isscfg.Label)
@@ -66,15 +80,38 @@ func NewAuthority(cfg *Config, crypto *cryptoprov.Crypto) (*Authority, error) {
 			return nil, errors.Annotatef(err, "unable to create issuer: %q", isscfg.Label)
 		}
 
+		issuer.weakKeys = weakKeys
 		ca.issuers[isscfg.Label] = issuer
 
-		for profileName := range isscfg.Profiles {
-			/*
-				if is := ca.issuersByProfile[profileName]; is != nil {
-					return nil, errors.Errorf("profile %q is already registered by %q issuer", profileName, is.Label())
+		if state := issuer.State(); state != IssuerStateActive {
+			// retiring and retired issuers still serve CRL and OCSP,
+			// but do not issue certificates
+			logger.Noticef("reason=not_active, issuer=%s, state=%s", isscfg.Label, state)
+			continue
+		}
+		if isscfg.Type == IssuerTypeCrossSign || isscfg.Type == IssuerTypeTimestamp {
+			// cross-sign and timestamp issuers do not serve profiles for requests
+			continue
+		}
+
+		for profileName, profile := range isscfg.Profiles {
+			pi := ca.issuersByProfile[profileName]
+			if pi == nil {
+				pi = &profileIssuers{
+					labels:    profile.GetIssuerLabels(),
+					selection: profile.GetIssuerSelection(),
 				}
-			*/
-			ca.issuersByProfile[profileName] = issuer
+				ca.issuersByProfile[profileName] = pi
+			}
+			pi.issuers = append(pi.issuers, issuer)
+		}
+	}
+
+	for profileName, pi := range ca.issuersByProfile {
+		pi.sort()
+		if len(pi.issuers) > 1 {
+			logger.Warningf("reason=profile_overlap, profile=%s, selection=%s, issuers=[%s]",
+				profileName, pi.selection, strings.Join(pi.issuerLabels(), ","))
 		}
 	}
 
@@ -90,15 +127,73 @@ func (s *Authority) GetIssuerByLabel(label string) (*Issuer, error) {
 	return nil, errors.Errorf("issuer not found: %s", label)
 }
 
-// GetIssuerByProfile by profile
+// GetIssuerByProfile returns the issuer for the profile.
+// If the profile is served by multiple issuers,
+// then the issuer is selected by the profile's issuer_selection strategy.
 func (s *Authority) GetIssuerByProfile(profile string) (*Issuer, error) {
-	issuer, ok := s.issuersByProfile[profile]
+	pi, ok := s.issuersByProfile[profile]
 	if ok {
-		return issuer, nil
+		return pi.selectIssuer(), nil
 	}
 	return nil, errors.Errorf("issuer not found for profile: %s", profile)
 }
 
+// GetIssuerByProfileAndLabel returns the issuer by label,
+// if the issuer serves the profile
+func (s *Authority) GetIssuerByProfileAndLabel(profile, label string) (*Issuer, error) {
+	pi, ok := s.issuersByProfile[profile]
+	if !ok {
+		return nil, errors.Errorf("issuer not found for profile: %s", profile)
+	}
+	for _, issuer := range pi.issuers {
+		if strings.EqualFold(issuer.Label(), label) {
+			return issuer, nil
+		}
+	}
+	return nil, errors.Errorf("%q issuer does not support the request profile: %q", label, profile)
+}
+
+// IssuersByProfile returns the list of issuers for the profile,
+// in the order of preference
+func (s *Authority) IssuersByProfile(profile string) []*Issuer {
+	pi, ok := s.issuersByProfile[profile]
+	if !ok {
+		return nil
+	}
+	list := make([]*Issuer, len(pi.issuers))
+	copy(list, pi.issuers)
+	return list
+}
+
+// GetIssuerByType returns the active issuer of the type by label,
+// or the first one ordered by label, if the label is not provided
+func (s *Authority) GetIssuerByType(typ, label string) (*Issuer, error) {
+	var list []*Issuer
+	for _, issuer := range s.issuers {
+		if issuer.cfg.Type == typ &&
+			issuer.State() == IssuerStateActive &&
+			(label == "" || strings.EqualFold(issuer.Label(), label)) {
+			list = append(list, issuer)
+		}
+	}
+	if len(list) == 0 {
+		if label != "" {
+			return nil, errors.Errorf("%s issuer not found: %s", typ, label)
+		}
+		return nil, errors.Errorf("%s issuer not found", typ)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Label() < list[j].Label()
+	})
+	return list[0], nil
+}
+
+// TimestampIssuer returns the active timestamp issuer by label,
+// or the first one ordered by label, if the label is not provided
+func (s *Authority) TimestampIssuer(label string) (*Issuer, error) {
+	return s.GetIssuerByType(IssuerTypeTimestamp, label)
+}
+
 // Issuers returns a list of issuers
 func (s *Authority) Issuers() []*Issuer {
 	list := make([]*Issuer, 0, len(s.issuers))
@@ -108,3 +203,68 @@ func (s *Authority) Issuers() []*Issuer {
 
 	return list
 }
+
+// profileIssuers provides the list of issuers serving a profile
+type profileIssuers struct {
+	labels    []string
+	selection string
+	issuers   []*Issuer
+	next      uint32
+}
+
+// sort orders issuers by the position in the profile's issuer labels,
+// and then by label, to provide deterministic selection
+func (p *profileIssuers) sort() {
+	pos := func(label string) int {
+		for i, l := range p.labels {
+			if l == label {
+				return i
+			}
+		}
+		return len(p.labels)
+	}
+	sort.SliceStable(p.issuers, func(i, j int) bool {
+		pi, pj := pos(p.issuers[i].Label()), pos(p.issuers[j].Label())
+		if pi != pj {
+			return pi < pj
+		}
+		return p.issuers[i].Label() < p.issuers[j].Label()
+	})
+}
+
+func (p *profileIssuers) issuerLabels() []string {
+	labels := make([]string, len(p.issuers))
+	for i, issuer := range p.issuers {
+		labels[i] = issuer.Label()
+	}
+	return labels
+}
+
+func (p *profileIssuers) selectIssuer() *Issuer {
+	selected := p.issuers[0]
+	switch p.selection {
+	case IssuerSelectionRoundRobin:
+		n := atomic.AddUint32(&p.next, 1) - 1
+		selected = p.issuers[int(n%uint32(len(p.issuers)))]
+	case IssuerSelectionNewest:
+		for _, issuer := range p.issuers[1:] {
+			if issuer.Bundle().Cert.NotBefore.After(selected.Bundle().Cert.NotBefore) {
+				selected = issuer
+			}
+		}
+	case IssuerSelectionLongestValidity:
+		for _, issuer := range p.issuers[1:] {
+			if issuer.Bundle().Cert.NotAfter.After(selected.Bundle().Cert.NotAfter) {
+				selected = issuer
+			}
+		}
+	default:
+		now := time.Now()
+		for _, issuer := range p.issuers {
+			if now.Before(issuer.Bundle().Cert.NotAfter) {
+				return issuer
+			}
+		}
+	}
+	return selected
+}
@@ -1,14 +1,22 @@
 package authority_test
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ekspand/trusty/authority"
 	"github.com/ekspand/trusty/internal/config"
 	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/inmemcrypto"
 	"github.com/go-phorce/dolly/algorithms/guid"
 	"github.com/go-phorce/dolly/xpki/cryptoprov"
 	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -490,3 +498,166 @@ func (s *testSuite) TestIssuerSign() {
 		s.NotEmpty(crt.IPAddresses)
 	})
 }
+
+func TestIssuerSelection(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	crypto, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+	rootReq := csr.CertificateRequest{
+		CommonName: "[TEST] Trusty Root CA",
+		KeyRequest: prov.NewKeyRequest("TestIssuerSelection", "ECDSA", 256, csr.SigningKey),
+	}
+	rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &rootReq)
+	require.NoError(t, err)
+
+	rootSigner, err := authority.NewSignerFromPEM(crypto, rootKey)
+	require.NoError(t, err)
+
+	rootIssuer, err := authority.CreateIssuer(&authority.IssuerConfig{
+		Label: "ROOT",
+		Profiles: map[string]*authority.CertProfile{
+			"L1_CA": {
+				Usage:        []string{"cert sign", "crl sign"},
+				CAConstraint: authority.CAConstraint{IsCA: true},
+				Expiry:       csr.OneYear,
+			},
+		},
+	}, rootPEM, nil, nil, rootSigner)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "issuerselection")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rootFile := filepath.Join(dir, "root.pem")
+	require.NoError(t, ioutil.WriteFile(rootFile, rootPEM, 0644))
+
+	// old issuer is valid longer, the new issuer is issued later
+	issuers := []struct {
+		label    string
+		backdate time.Duration
+		expiry   time.Duration
+	}{
+		{"old", 2 * time.Hour, 720 * time.Hour},
+		{"new", 1 * time.Hour, 24 * time.Hour},
+	}
+	for _, is := range issuers {
+		csrPEM, key, _, _, err := prov.CreateRequestAndExportKey(&csr.CertificateRequest{
+			CommonName: "[TEST] Trusty Level 1 CA " + is.label,
+			KeyRequest: prov.NewKeyRequest("TestIssuerSelection", "ECDSA", 256, csr.SigningKey),
+		})
+		require.NoError(t, err)
+
+		profile := rootIssuer.Profile("L1_CA")
+		profile.Backdate = csr.Duration(is.backdate)
+		profile.Expiry = csr.Duration(is.expiry)
+		_, certPEM, err := rootIssuer.Sign(csr.SignRequest{
+			Request: string(csrPEM),
+			Profile: "L1_CA",
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, is.label+".pem"), certPEM, 0644))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, is.label+"-key.pem"), key, 0600))
+	}
+
+	cfgYAML := fmt.Sprintf(`
+authority:
+  default_aia:
+    crl_url: http://localhost/v1/crl/${ISSUER_ID}.crl
+  issuers:
+  - label: old
+    cert: %[1]s/old.pem
+    key: %[1]s/old-key.pem
+    root_bundle: %[1]s/root.pem
+  - label: new
+    cert: %[1]s/new.pem
+    key: %[1]s/new-key.pem
+    root_bundle: %[1]s/root.pem
+profiles:
+  default:
+    expiry: 24h
+    usages:
+    - signing
+  old_only:
+    issuer_label: old
+    expiry: 24h
+    usages:
+    - signing
+  by_label:
+    issuer_label: old
+    issuer_labels:
+    - new
+    expiry: 24h
+    usages:
+    - signing
+  newest:
+    issuer_labels: [old, new]
+    issuer_selection: prefer_newest
+    expiry: 24h
+    usages:
+    - signing
+  longest:
+    issuer_labels: [new, old]
+    issuer_selection: longest_validity
+    expiry: 24h
+    usages:
+    - signing
+  round_robin:
+    issuer_labels: [new, old]
+    issuer_selection: round_robin
+    expiry: 24h
+    usages:
+    - signing
+`, dir)
+	cfgFile := filepath.Join(dir, "ca-config.yaml")
+	require.NoError(t, ioutil.WriteFile(cfgFile, []byte(cfgYAML), 0644))
+
+	cfg, err := authority.LoadConfig(cfgFile)
+	require.NoError(t, err)
+
+	ca, err := authority.NewAuthority(cfg, crypto)
+	require.NoError(t, err)
+
+	_, err = ca.GetIssuerByProfile("default")
+	require.Error(t, err)
+	assert.Equal(t, "issuer not found for profile: default", err.Error())
+
+	tcases := []struct {
+		profile  string
+		expected []string
+	}{
+		{"old_only", []string{"old", "old", "old"}},
+		{"by_label", []string{"old", "old", "old"}},
+		{"newest", []string{"new", "new", "new"}},
+		{"longest", []string{"old", "old", "old"}},
+		{"round_robin", []string{"new", "old", "new"}},
+	}
+	for _, tc := range tcases {
+		for i, label := range tc.expected {
+			issuer, err := ca.GetIssuerByProfile(tc.profile)
+			require.NoError(t, err)
+			assert.Equal(t, label, issuer.Label(), "%s: request %d", tc.profile, i)
+		}
+	}
+
+	list := ca.IssuersByProfile("longest")
+	require.Len(t, list, 2)
+	assert.Equal(t, "new", list[0].Label())
+	assert.Equal(t, "old", list[1].Label())
+	assert.Empty(t, ca.IssuersByProfile("default"))
+
+	issuer, err := ca.GetIssuerByProfileAndLabel("by_label", "NEW")
+	require.NoError(t, err)
+	assert.Equal(t, "new", issuer.Label())
+
+	_, err = ca.GetIssuerByProfileAndLabel("old_only", "new")
+	require.Error(t, err)
+	assert.Equal(t, `"new" issuer does not support the request profile: "old_only"`, err.Error())
+
+	_, err = ca.GetIssuerByProfileAndLabel("default", "new")
+	require.Error(t, err)
+	assert.Equal(t, "issuer not found for profile: default", err.Error())
+}
@@ -0,0 +1,183 @@
+package authority
+
+import (
+	"bytes"
+	"crypto/x509"
+	"time"
+
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/juju/errors"
+)
+
+// verifyBundleFromPEM constructs and verifies the cert chain.
+// The bundler does not support Ed25519 certificates,
+// in which case the chain is built with the standard library.
+func verifyBundleFromPEM(certPEM, intCAPEM, rootPEM []byte) (*certutil.Bundle, *certutil.BundleStatus, error) {
+	crt, err := certutil.ParseFromPEM(certPEM)
+	if err != nil || crt.PublicKeyAlgorithm != x509.Ed25519 {
+		return certutil.VerifyBundleFromPEM(certPEM, intCAPEM, rootPEM)
+	}
+
+	var intermediates, roots []*x509.Certificate
+	if len(bytes.TrimSpace(intCAPEM)) > 0 {
+		intermediates, err = certutil.ParseChainFromPEM(intCAPEM)
+		if err != nil {
+			return nil, nil, errors.Annotate(err, "failed to parse intermediates")
+		}
+	}
+	if len(bytes.TrimSpace(rootPEM)) > 0 {
+		roots, err = certutil.ParseChainFromPEM(rootPEM)
+		if err != nil {
+			return nil, nil, errors.Annotate(err, "failed to parse roots")
+		}
+	}
+
+	rootsPool := x509.NewCertPool()
+	intsPool := x509.NewCertPool()
+	for _, c := range roots {
+		rootsPool.AddCert(c)
+	}
+	for _, c := range intermediates {
+		if len(roots) == 0 && bytes.Equal(c.RawIssuer, c.RawSubject) {
+			rootsPool.AddCert(c)
+		} else {
+			intsPool.AddCert(c)
+		}
+	}
+	if bytes.Equal(crt.RawIssuer, crt.RawSubject) {
+		rootsPool.AddCert(crt)
+	}
+
+	chains, err := crt.Verify(x509.VerifyOptions{
+		Roots:         rootsPool,
+		Intermediates: intsPool,
+		CurrentTime:   time.Now(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to bundle")
+	}
+
+	chain := chains[0]
+	root := chain[len(chain)-1]
+	if len(chain) > 1 {
+		chain = chain[:len(chain)-1]
+	}
+
+	expires := crt.NotAfter
+	for _, c := range chain {
+		if c.NotAfter.Before(expires) {
+			expires = c.NotAfter
+		}
+	}
+
+	pemCert, _ := certutil.EncodeToPEMString(true, crt)
+	pemRoot, _ := certutil.EncodeToPEMString(true, root)
+	var pemCA string
+	if len(chain) > 1 {
+		pemCA, _ = certutil.EncodeToPEMString(true, chain[1:]...)
+	}
+
+	bundle := &certutil.Bundle{
+		Chain:       chain,
+		Cert:        crt,
+		RootCert:    root,
+		IssuerCert:  certutil.FindIssuer(crt, chain, root),
+		Issuer:      &crt.Issuer,
+		IssuerID:    certutil.GetIssuerID(crt),
+		Subject:     &crt.Subject,
+		SubjectID:   certutil.GetSubjectID(crt),
+		Expires:     expires,
+		Hostnames:   crt.DNSNames,
+		CertPEM:     pemCert,
+		CACertsPEM:  pemCA,
+		RootCertPEM: pemRoot,
+	}
+	return bundle, &certutil.BundleStatus{}, nil
+}
+
+// alternateChains builds the chains for the certificate
+// that include the cross-certificates.
+// The chain for the cross-certificate issued for the certificate itself,
+// starts with the cross-certificate.
+func alternateChains(crt *x509.Certificate, intCAPEM, rootPEM, crossPEM []byte) ([][]*x509.Certificate, error) {
+	cross, err := certutil.ParseChainFromPEM(crossPEM)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to parse cross-certificates")
+	}
+
+	var intermediates, roots []*x509.Certificate
+	if len(bytes.TrimSpace(intCAPEM)) > 0 {
+		intermediates, err = certutil.ParseChainFromPEM(intCAPEM)
+		if err != nil {
+			return nil, errors.Annotate(err, "failed to parse intermediates")
+		}
+	}
+	if len(bytes.TrimSpace(rootPEM)) > 0 {
+		roots, err = certutil.ParseChainFromPEM(rootPEM)
+		if err != nil {
+			return nil, errors.Annotate(err, "failed to parse roots")
+		}
+	}
+
+	rootsPool := x509.NewCertPool()
+	intsPool := x509.NewCertPool()
+	for _, c := range roots {
+		rootsPool.AddCert(c)
+	}
+	for _, c := range append(intermediates, cross...) {
+		if bytes.Equal(c.RawIssuer, c.RawSubject) {
+			rootsPool.AddCert(c)
+		} else {
+			intsPool.AddCert(c)
+		}
+	}
+
+	isCross := map[string]bool{}
+	targets := []*x509.Certificate{crt}
+	for _, c := range cross {
+		if bytes.Equal(c.RawIssuer, c.RawSubject) {
+			continue
+		}
+		isCross[string(c.Raw)] = true
+		if bytes.Equal(c.RawSubject, crt.RawSubject) &&
+			bytes.Equal(c.RawSubjectPublicKeyInfo, crt.RawSubjectPublicKeyInfo) {
+			targets = append(targets, c)
+		}
+	}
+
+	var list [][]*x509.Certificate
+	found := map[string]bool{}
+	for _, target := range targets {
+		chains, err := target.Verify(x509.VerifyOptions{
+			Roots:         rootsPool,
+			Intermediates: intsPool,
+			CurrentTime:   time.Now(),
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		if err != nil {
+			logger.Warningf("reason=verify_cross, cn=%q, err=[%v]", target.Subject.CommonName, err)
+			continue
+		}
+
+		for _, chain := range chains {
+			var id []byte
+			alternate := false
+			for _, c := range chain {
+				id = append(id, c.Raw...)
+				if isCross[string(c.Raw)] {
+					alternate = true
+				}
+			}
+			if alternate && !found[string(id)] {
+				found[string(id)] = true
+				list = append(list, chain)
+			}
+		}
+	}
+
+	if len(list) == 0 {
+		return nil, errors.Errorf("cross-certificates do not chain: cn=%q", crt.Subject.CommonName)
+	}
+	return list, nil
+}
@@ -0,0 +1,133 @@
+package authority
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+
+	"github.com/ekspand/trusty/pkg/cms"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/juju/errors"
+)
+
+// DefaultCodeSignProfile specifies default profile for remote signing
+const DefaultCodeSignProfile = "remote_codesign"
+
+// CodeSignRequest specifies the request for remote signing
+type CodeSignRequest struct {
+	// Profile specifies the profile of the short-lived certificate,
+	// if not provided, then DefaultCodeSignProfile is used
+	Profile string
+	// Subject specifies Common Name of the certificate,
+	// usually the identity of the caller
+	Subject string
+	// SAN specifies Subject Alternative Names of the certificate
+	SAN []string
+	// Hash specifies the digest algorithm
+	Hash crypto.Hash
+	// Digest of the content to sign
+	Digest []byte
+}
+
+// SetCodeSigner sets the code signing key of the issuer,
+// the short-lived certificates are issued for this key
+func (ca *Issuer) SetCodeSigner(signer crypto.Signer) error {
+	switch signer.Public().(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+	default:
+		return errors.Errorf("unsupported code signing key: %T", signer.Public())
+	}
+	ca.codeSigner = signer
+	return nil
+}
+
+// CodeSignPublicKey returns the public key of the code signing key,
+// or nil if the issuer does not have it
+func (ca *Issuer) CodeSignPublicKey() crypto.PublicKey {
+	if ca.codeSigner == nil {
+		return nil
+	}
+	return ca.codeSigner.Public()
+}
+
+// CodeSign signs the digest with the code signing key of the issuer,
+// held by the crypto provider, and returns the short-lived certificate
+// issued for the request and DER encoded detached CMS signature.
+// The certificate binds the caller's identity to the signature.
+func (ca *Issuer) CodeSign(req *CodeSignRequest) (*x509.Certificate, []byte, error) {
+	if ca.cfg.Type != IssuerTypeCodesign {
+		return nil, nil, errors.Errorf("issuer does not support code signing: %s", ca.label)
+	}
+	if ca.codeSigner == nil {
+		return nil, nil, errors.Errorf("code signing key is not configured: %s", ca.label)
+	}
+
+	profileName := req.Profile
+	if profileName == "" {
+		profileName = DefaultCodeSignProfile
+	}
+	if _, err := ca.codeSignProfile(profileName); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if _, err := cms.HashOID(req.Hash); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if len(req.Digest) != req.Hash.Size() {
+		return nil, nil, errors.Errorf("invalid digest size: %d", len(req.Digest))
+	}
+
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: req.Subject},
+	}, ca.codeSigner)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to create request")
+	}
+
+	crt, _, err := ca.Sign(csr.SignRequest{
+		SAN:     req.SAN,
+		Request: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})),
+		Profile: profileName,
+	})
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	certs := append([]*x509.Certificate{crt}, ca.bundle.Chain...)
+
+	signature, err := cms.SignDetached(cms.OIDData, req.Hash, req.Digest, ca.codeSigner, crt, certs)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to sign")
+	}
+
+	return crt, signature, nil
+}
+
+func hasExtKeyUsage(list []x509.ExtKeyUsage, eku x509.ExtKeyUsage) bool {
+	for _, u := range list {
+		if u == eku {
+			return true
+		}
+	}
+	return false
+}
+
+// codeSignProfile returns the profile, that allows code signing
+func (ca *Issuer) codeSignProfile(profileName string) (*CertProfile, error) {
+	profile := ca.cfg.Profiles[profileName]
+	if profile == nil {
+		return nil, errors.New("unsupported profile: " + profileName)
+	}
+	if profile.CAConstraint.IsCA {
+		return nil, errors.Errorf("profile does not allow code signing: %s", profileName)
+	}
+	_, eku, _ := profile.Usages()
+	if !hasExtKeyUsage(eku, x509.ExtKeyUsageCodeSigning) {
+		return nil, errors.Errorf("profile does not allow code signing: %s", profileName)
+	}
+	return profile, nil
+}
@@ -0,0 +1,124 @@
+package authority_test
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/pkg/cms"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/inmemcrypto"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeSign(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	cryptoProv, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+
+	profiles := map[string]*authority.CertProfile{
+		authority.DefaultCodeSignProfile: {
+			Usage:  []string{"digital signature", "code signing"},
+			Expiry: csr.Duration(10 * time.Minute),
+		},
+		"server": {
+			Usage:  []string{"digital signature", "server auth"},
+			Expiry: csr.OneYear,
+		},
+	}
+
+	rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &csr.CertificateRequest{
+		CommonName: "[TEST] Trusty Codesign CA",
+		KeyRequest: prov.NewKeyRequest("TestCodeSign", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+	signer, err := authority.NewSignerFromPEM(cryptoProv, rootKey)
+	require.NoError(t, err)
+
+	issuer, err := authority.CreateIssuer(&authority.IssuerConfig{
+		Label:    "codesign",
+		Type:     authority.IssuerTypeCodesign,
+		Profiles: profiles,
+	}, rootPEM, nil, nil, signer)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("trusty"))
+	_, _, err = issuer.CodeSign(&authority.CodeSignRequest{
+		Subject: "build-agent",
+		Hash:    crypto.SHA256,
+		Digest:  digest[:],
+	})
+	assert.EqualError(t, err, "code signing key is not configured: codesign")
+	assert.Nil(t, issuer.CodeSignPublicKey())
+
+	// the code signing key is held by the provider
+	_, codeSignKey, _, err := prov.GenerateKeyAndRequest(&csr.CertificateRequest{
+		CommonName: "codesign",
+		KeyRequest: prov.NewKeyRequest("TestCodeSignKey", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+	require.NoError(t, issuer.SetCodeSigner(codeSignKey.(crypto.Signer)))
+	assert.Equal(t, codeSignKey.(crypto.Signer).Public(), issuer.CodeSignPublicKey())
+
+	crt, signature, err := issuer.CodeSign(&authority.CodeSignRequest{
+		Subject: "build-agent",
+		Hash:    crypto.SHA256,
+		Digest:  digest[:],
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "build-agent", crt.Subject.CommonName)
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}, crt.ExtKeyUsage)
+	assert.True(t, crt.NotAfter.Sub(crt.NotBefore) <= 15*time.Minute)
+	assert.Equal(t, issuer.CodeSignPublicKey(), crt.PublicKey)
+	require.NoError(t, crt.CheckSignatureFrom(issuer.Bundle().Cert))
+
+	sd, err := cms.Parse(signature)
+	require.NoError(t, err)
+	assert.Nil(t, sd.Content)
+	require.NotEmpty(t, sd.Certificates)
+	assert.Equal(t, crt.Raw, sd.Certificates[0].Raw)
+	require.NoError(t, sd.Verify(crt, digest[:]))
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := issuer.CodeSign(&authority.CodeSignRequest{
+			Profile: "server",
+			Subject: "build-agent",
+			Hash:    crypto.SHA256,
+			Digest:  digest[:],
+		})
+		assert.EqualError(t, err, "profile does not allow code signing: server")
+
+		_, _, err = issuer.CodeSign(&authority.CodeSignRequest{
+			Subject: "build-agent",
+			Hash:    crypto.SHA384,
+			Digest:  digest[:],
+		})
+		assert.EqualError(t, err, "invalid digest size: 32")
+
+		_, _, err = issuer.CodeSign(&authority.CodeSignRequest{
+			Subject: "build-agent",
+			Hash:    crypto.MD5,
+			Digest:  digest[:16],
+		})
+		assert.EqualError(t, err, "unsupported hash algorithm: MD5")
+
+		other, err := authority.CreateIssuer(&authority.IssuerConfig{
+			Label:    "other",
+			Profiles: profiles,
+		}, rootPEM, nil, nil, signer)
+		require.NoError(t, err)
+		_, _, err = other.CodeSign(&authority.CodeSignRequest{
+			Subject: "build-agent",
+			Hash:    crypto.SHA256,
+			Digest:  digest[:],
+		})
+		assert.EqualError(t, err, "issuer does not support code signing: other")
+	})
+}
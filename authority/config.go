@@ -8,8 +8,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ekspand/trusty/pkg/certlint"
+	"github.com/ekspand/trusty/pkg/certpolicy"
 	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/webhook"
 	"github.com/go-phorce/dolly/algorithms/slices"
+	"github.com/go-phorce/dolly/xpki/certutil"
 	"github.com/jinzhu/copier"
 	"github.com/juju/errors"
 	"gopkg.in/yaml.v2"
@@ -24,6 +28,56 @@ var (
 	DefaultOCSPExpiry = 1 * 24 * time.Hour // 1 day
 )
 
+const (
+	// IssuerSelectionLabel specifies to select the first available issuer
+	// in the order of the profile's issuer_label and issuer_labels
+	IssuerSelectionLabel = "label"
+	// IssuerSelectionNewest specifies to select the most recently issued issuer
+	IssuerSelectionNewest = "prefer_newest"
+	// IssuerSelectionRoundRobin specifies to rotate issuers for each request
+	IssuerSelectionRoundRobin = "round_robin"
+	// IssuerSelectionLongestValidity specifies to select the issuer
+	// with the longest remaining validity
+	IssuerSelectionLongestValidity = "longest_validity"
+)
+
+const (
+	// IssuerTypeCrossSign specifies the issuer for cross-certificates
+	IssuerTypeCrossSign = "cross-sign"
+	// IssuerTypeTimestamp specifies the issuer for RFC 3161 time-stamp tokens
+	IssuerTypeTimestamp = "timestamp"
+	// IssuerTypeCodesign specifies the issuer for code signing certificates,
+	// that also serves remote signing with short-lived certificates
+	IssuerTypeCodesign = "codesign"
+	// IssuerTypeSpiffe specifies the issuer for SPIFFE X.509-SVID
+	IssuerTypeSpiffe = "spiffe"
+	// IssuerTypeSSH specifies the issuer for OpenSSH user and host certificates
+	IssuerTypeSSH = "ssh"
+)
+
+const (
+	// IssuerStateActive specifies the issuer that issues certificates
+	IssuerStateActive = "active"
+	// IssuerStateRetiring specifies the issuer that does not issue certificates,
+	// but still publishes CRL and OCSP
+	IssuerStateRetiring = "retiring"
+	// IssuerStateRetired specifies the issuer that is no longer in use
+	IssuerStateRetired = "retired"
+)
+
+var issuerStates = []string{
+	IssuerStateActive,
+	IssuerStateRetiring,
+	IssuerStateRetired,
+}
+
+var issuerSelections = []string{
+	IssuerSelectionLabel,
+	IssuerSelectionNewest,
+	IssuerSelectionRoundRobin,
+	IssuerSelectionLongestValidity,
+}
+
 // Config provides configuration for Certification Authority
 type Config struct {
 	Authority *CAConfig               `json:"authority,omitempty" yaml:"authority,omitempty"`
@@ -43,6 +97,10 @@ type CAConfig struct {
 
 	// PublicRoots specifies the list of public Root Certs files.
 	PublicRoots []string `json:"public_roots,omitempty" yaml:"public_roots,omitempty"`
+
+	// WeakKeys specifies the list of files with blocklisted RSA keys,
+	// in openssl-blacklist format.
+	WeakKeys []string `json:"weak_keys,omitempty" yaml:"weak_keys,omitempty"`
 }
 
 // IssuerConfig contains configuration info for the issuing certificate
@@ -53,7 +111,15 @@ type IssuerConfig struct {
 	// Label specifies Issuer's label
 	Label string `json:"label,omitempty" yaml:"label,omitempty"`
 
-	// Type specifies type: tls|codesign|timestamp|ocsp|spiffe|trusty
+	// Type specifies type: tls|codesign|timestamp|ocsp|spiffe|ssh|trusty|cross-sign.
+	// The cross-sign issuer does not serve profiles for certificate requests,
+	// and used only to issue cross-certificates for other CAs.
+	// The timestamp issuer does not serve profiles for certificate requests,
+	// and used only to sign time-stamp tokens.
+	// The spiffe issuer requires exactly one SPIFFE ID in URI SAN
+	// in its trust domain.
+	// The ssh issuer serves only profiles with ssh configuration,
+	// and used only to sign OpenSSH certificates.
 	Type string
 
 	// CertFile specifies location of the cert
@@ -71,10 +137,89 @@ type IssuerConfig struct {
 	// AIA specifies AIA configuration
 	AIA *AIAConfig `json:"aia,omitempty" yaml:"aia,omitempty"`
 
+	// CrossCertFiles specifies locations of cross-certificates,
+	// issued for the issuer or its parents by other roots,
+	// including their chains to the other roots.
+	// The cross-certificates are used to build alternate chains.
+	CrossCertFiles []string `json:"cross_certs,omitempty" yaml:"cross_certs,omitempty"`
+
+	// RSAPSS specifies to sign certificates with RSA-PSS,
+	// applicable only for issuers with RSA key
+	RSAPSS bool `json:"rsa_pss,omitempty" yaml:"rsa_pss,omitempty"`
+
+	// State specifies the issuer's state: active|retiring|retired.
+	// The retiring issuer does not issue certificates,
+	// but still publishes CRL and OCSP until it's retired.
+	// If not provided, then the issuer is active.
+	State string `json:"state,omitempty" yaml:"state,omitempty"`
+
+	// RetireAfter specifies the time when the retiring issuer becomes retired,
+	// usually when the last certificate issued by it expires.
+	RetireAfter *time.Time `json:"retire_after,omitempty" yaml:"retire_after,omitempty"`
+
+	// TSA specifies time-stamping configuration,
+	// applicable only for the timestamp issuer
+	TSA *TSAConfig `json:"tsa,omitempty" yaml:"tsa,omitempty"`
+
+	// SPIFFE specifies SPIFFE configuration,
+	// applicable only for the spiffe issuer
+	SPIFFE *SPIFFEConfig `json:"spiffe,omitempty" yaml:"spiffe,omitempty"`
+
+	// SSH specifies SSH CA configuration,
+	// applicable only for the ssh issuer
+	SSH *SSHConfig `json:"ssh,omitempty" yaml:"ssh,omitempty"`
+
+	// CodeSign specifies code signing configuration,
+	// applicable only for the codesign issuer
+	CodeSign *CodeSignConfig `json:"codesign,omitempty" yaml:"codesign,omitempty"`
+
 	// Profiles are populated after loading
 	Profiles map[string]*CertProfile `json:"-" yaml:"-"`
 }
 
+// TSAConfig provides configuration for RFC 3161 time-stamping
+type TSAConfig struct {
+	// Policy specifies TSA policy OID to be included in time-stamp tokens,
+	// the requests with other policy are rejected
+	Policy csr.OID `json:"policy" yaml:"policy"`
+
+	// Accuracy specifies value in 1s format for accuracy of the time
+	Accuracy time.Duration `json:"accuracy,omitempty" yaml:"accuracy,omitempty"`
+
+	// Ordering specifies if the tokens can be ordered based on the time
+	Ordering bool `json:"ordering,omitempty" yaml:"ordering,omitempty"`
+}
+
+// SPIFFEConfig provides configuration for SPIFFE issuer
+type SPIFFEConfig struct {
+	// TrustDomain specifies the trust domain of SPIFFE IDs,
+	// for example: trusty.ekspand.com
+	TrustDomain string `json:"trust_domain" yaml:"trust_domain"`
+
+	// JWTKeyFile specifies location of the key to sign JWT-SVID,
+	// if not provided, then JWT-SVID is not supported by the issuer
+	JWTKeyFile string `json:"jwt_key,omitempty" yaml:"jwt_key,omitempty"`
+
+	// JWTExpiry specifies value in 5m format for JWT-SVID lifetime,
+	// if not provided, then DefaultJWTSVIDExpiry is used
+	JWTExpiry time.Duration `json:"jwt_expiry,omitempty" yaml:"jwt_expiry,omitempty"`
+}
+
+// SSHConfig provides configuration for SSH issuer
+type SSHConfig struct {
+	// KeyFile specifies location of the SSH CA key,
+	// if not provided, then the issuer's key is used
+	KeyFile string `json:"key,omitempty" yaml:"key,omitempty"`
+}
+
+// CodeSignConfig provides configuration for codesign issuer
+type CodeSignConfig struct {
+	// KeyFile specifies location of the code signing key,
+	// usually PKCS#11 URI of the key held by HSM or KMS.
+	// If not provided, then the issuer supports only keyless signing.
+	KeyFile string `json:"key,omitempty" yaml:"key,omitempty"`
+}
+
 // AIAConfig contains AIA configuration info
 type AIAConfig struct {
 	// AiaURL specifies a template for AIA URL.
@@ -202,16 +347,89 @@ type CertProfile struct {
 	// PoliciesCritical specifies to mark Policies as Critical extension
 	PoliciesCritical bool `json:"policies_critical" yaml:"policies_critical"`
 
-	IssuerLabel  string   `json:"issuer_label" yaml:"issuer_label"`
+	IssuerLabel string `json:"issuer_label" yaml:"issuer_label"`
+
+	// IssuerLabels specifies additional issuers to serve the profile,
+	// for example during rollover from an expiring intermediate.
+	IssuerLabels []string `json:"issuer_labels,omitempty" yaml:"issuer_labels,omitempty"`
+
+	// IssuerSelection specifies the strategy to select an issuer,
+	// when the profile is served by multiple issuers:
+	// label|prefer_newest|round_robin|longest_validity.
+	// If not provided, then the first available issuer in
+	// the order of IssuerLabel and IssuerLabels is selected.
+	IssuerSelection string `json:"issuer_selection,omitempty" yaml:"issuer_selection,omitempty"`
+
 	AllowedRoles []string `json:"allowed_roles" yaml:"allowed_roles"`
 	DeniedRoles  []string `json:"denied_roles" yaml:"denied_roles"`
 
+	// RSAPSS specifies to sign certificates of this profile with RSA-PSS,
+	// applicable only for issuers with RSA key
+	RSAPSS bool `json:"rsa_pss,omitempty" yaml:"rsa_pss,omitempty"`
+
+	// KeyPolicy specifies allowed key algorithms and sizes.
+	// If not provided, then csr.DefaultKeyPolicy is used.
+	KeyPolicy *csr.KeyPolicy `json:"key_policy,omitempty" yaml:"key_policy,omitempty"`
+
+	// Lint specifies levels of pre-issuance lints by name: error|warn|ignore.
+	// If a lint is not present, then its default level is used.
+	Lint map[string]certlint.Level `json:"lint,omitempty" yaml:"lint,omitempty"`
+
+	// IssuancePolicy specifies rules with CEL expressions,
+	// evaluated with the CSR, the caller and the org before issuance.
+	// The request is denied by the first rule that does not allow it.
+	IssuancePolicy certpolicy.Rules `json:"issuance_policy,omitempty" yaml:"issuance_policy,omitempty"`
+
+	// Webhook specifies an external service to approve or deny the request,
+	// called after the issuance policy and before signing.
+	// If the webhook replaces SAN, then the issuance policy is evaluated
+	// again with the final list.
+	Webhook *webhook.Config `json:"webhook,omitempty" yaml:"webhook,omitempty"`
+
+	// Approval specifies that the requests must be approved before signing,
+	// the requests are queued by RA until the required approvals are received.
+	Approval *ApprovalPolicy `json:"approval,omitempty" yaml:"approval,omitempty"`
+
+	// SSH specifies OpenSSH certificate profile,
+	// applicable only for the ssh issuer
+	SSH *SSHProfile `json:"ssh,omitempty" yaml:"ssh,omitempty"`
+
+	// Keyless specifies that the profile can be used by SigningService.SignKeyless,
+	// its expiry must not exceed MaxKeylessExpiry
+	Keyless bool `json:"keyless,omitempty" yaml:"keyless,omitempty"`
+
 	AllowedNamesRegex *regexp.Regexp `json:"-" yaml:"-"`
 	AllowedDNSRegex   *regexp.Regexp `json:"-" yaml:"-"`
 	AllowedEmailRegex *regexp.Regexp `json:"-" yaml:"-"`
 	AllowedURIRegex   *regexp.Regexp `json:"-" yaml:"-"`
 }
 
+// SSHProfile specifies OpenSSH certificate profile
+type SSHProfile struct {
+	// CertType specifies type of the certificate: user|host
+	CertType string `json:"cert_type" yaml:"cert_type"`
+
+	// Principals specifies patterns of allowed principals.
+	// The ${NAME}, ${USER}, ${ROLE} and ${ORG} variables will be replaced
+	// with the caller's name, the user part of the caller's email in UserDomains, role,
+	// and the logins of the caller's organizations.
+	// The patterns may include shell wildcards, for example *.trusty.local
+	Principals []string `json:"principals" yaml:"principals"`
+
+	// UserDomains specifies the email domains of the callers,
+	// for which ${USER} is replaced with the user part of the email.
+	// Must be provided, if ${USER} is used in Principals
+	UserDomains []string `json:"user_domains,omitempty" yaml:"user_domains,omitempty"`
+
+	// CriticalOptions specifies critical options of the certificate,
+	// for example force-command or source-address
+	CriticalOptions map[string]string `json:"critical_options,omitempty" yaml:"critical_options,omitempty"`
+
+	// Extensions specifies extensions of the certificate,
+	// for example permit-pty or permit-port-forwarding
+	Extensions map[string]string `json:"extensions,omitempty" yaml:"extensions,omitempty"`
+}
+
 // CAConstraint specifies various CA constraints on the signed certificate.
 // CAConstraint would verify against (and override) the CA
 // extensions in the given CSR.
@@ -247,6 +465,29 @@ func (p *CertProfile) IsAllowed(role string) bool {
 	return true
 }
 
+// GetIssuerLabels returns the list of issuers to serve the profile,
+// in the order of preference
+func (p *CertProfile) GetIssuerLabels() []string {
+	var labels []string
+	if p.IssuerLabel != "" {
+		labels = append(labels, p.IssuerLabel)
+	}
+	for _, label := range p.IssuerLabels {
+		if !slices.ContainsString(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// GetIssuerSelection returns the strategy to select an issuer
+func (p *CertProfile) GetIssuerSelection() string {
+	if p.IssuerSelection == "" {
+		return IssuerSelectionLabel
+	}
+	return p.IssuerSelection
+}
+
 // DefaultCertProfile returns a default configuration
 // for a certificate profile, specifying basic key
 // usage and a 1 year expiration time.
@@ -321,8 +562,9 @@ func LoadConfig(path string) (*Config, error) {
 
 			iss.Profiles = make(map[string]*CertProfile)
 			for name, profile := range cfg.Profiles {
-				if profile.IssuerLabel == iss.Label ||
-					(profile.IssuerLabel == "" && len(cfg.Authority.Issuers) == 1) {
+				labels := profile.GetIssuerLabels()
+				if slices.ContainsString(labels, iss.Label) ||
+					(len(labels) == 0 && len(cfg.Authority.Issuers) == 1) {
 					iss.Profiles[name] = profile
 				}
 			}
@@ -347,7 +589,11 @@ func (p *CertProfile) Validate() error {
 		return errors.New("no expiry set")
 	}
 
-	if len(p.Usage) == 0 {
+	if p.SSH != nil {
+		if err := p.SSH.Validate(); err != nil {
+			return errors.Annotate(err, "invalid ssh")
+		}
+	} else if len(p.Usage) == 0 {
 		return errors.New("no usages specified")
 	} else if _, _, unk := p.Usages(); len(unk) > 0 {
 		return errors.Errorf("unknown usage: %s", strings.Join(unk, ","))
@@ -363,6 +609,40 @@ func (p *CertProfile) Validate() error {
 		}
 	}
 
+	if p.KeyPolicy != nil {
+		if err := p.KeyPolicy.Validate(); err != nil {
+			return errors.Annotate(err, "invalid key policy")
+		}
+	}
+
+	if !slices.ContainsString(issuerSelections, p.GetIssuerSelection()) {
+		return errors.Errorf("unsupported issuer_selection: %s", p.IssuerSelection)
+	}
+
+	if err := certlint.ValidateLevels(p.Lint); err != nil {
+		return errors.Annotate(err, "invalid lint")
+	}
+
+	if err := p.IssuancePolicy.Compile(); err != nil {
+		return errors.Annotate(err, "invalid issuance_policy")
+	}
+
+	if p.Webhook != nil {
+		if err := p.Webhook.Validate(); err != nil {
+			return errors.Annotate(err, "invalid webhook")
+		}
+	}
+
+	if p.Approval != nil {
+		if err := p.Approval.Validate(); err != nil {
+			return errors.Annotate(err, "invalid approval")
+		}
+	}
+
+	if p.Keyless && p.Expiry.TimeDuration() > MaxKeylessExpiry {
+		return errors.Errorf("keyless expiry must not exceed %v", MaxKeylessExpiry)
+	}
+
 	if p.AllowedNames != "" && p.AllowedNamesRegex == nil {
 		rule, err := regexp.Compile(p.AllowedNames)
 		if err != nil {
@@ -395,6 +675,14 @@ func (p *CertProfile) Validate() error {
 	return nil
 }
 
+// GetKeyPolicy returns KeyPolicy of the profile, or the default policy
+func (p *CertProfile) GetKeyPolicy() *csr.KeyPolicy {
+	if p.KeyPolicy != nil {
+		return p.KeyPolicy
+	}
+	return csr.DefaultKeyPolicy()
+}
+
 // IsAllowedExtention returns true of the extension is allowed
 func (p *CertProfile) IsAllowedExtention(oid csr.OID) bool {
 	for _, allowed := range p.AllowedExtensions {
@@ -405,6 +693,33 @@ func (p *CertProfile) IsAllowedExtention(oid csr.OID) bool {
 	return false
 }
 
+// validateRSAPSS returns an error if RSA-PSS is requested
+// by the issuer or its profiles, but the issuer's key is not RSA
+func (c *IssuerConfig) validateRSAPSS() error {
+	profile := ""
+	for name, p := range c.Profiles {
+		if p.RSAPSS {
+			profile = name
+			break
+		}
+	}
+	if !c.RSAPSS && profile == "" {
+		return nil
+	}
+
+	crt, err := certutil.LoadFromPEM(c.CertFile)
+	if err != nil {
+		return errors.Annotate(err, "unable to load cert")
+	}
+	if crt.PublicKeyAlgorithm != x509.RSA {
+		if profile != "" {
+			return errors.Errorf("RSA-PSS is not supported for %s key: profile=%s", crt.PublicKeyAlgorithm, profile)
+		}
+		return errors.Errorf("RSA-PSS is not supported for %s key", crt.PublicKeyAlgorithm)
+	}
+	return nil
+}
+
 // Validate returns an error if the configuration is invalid
 func (c *Config) Validate() error {
 	var err error
@@ -414,6 +729,35 @@ func (c *Config) Validate() error {
 		for i := range c.Authority.Issuers {
 			iss := &c.Authority.Issuers[i]
 			issuers[iss.Label] = true
+			if iss.State != "" && !slices.ContainsString(issuerStates, iss.State) {
+				return errors.Errorf("unsupported state for %s issuer: %s", iss.Label, iss.State)
+			}
+			if iss.Type == IssuerTypeTimestamp && (iss.TSA == nil || len(iss.TSA.Policy) == 0) {
+				return errors.Errorf("missing TSA policy for %s issuer", iss.Label)
+			}
+			if iss.Type == IssuerTypeSpiffe {
+				if iss.SPIFFE == nil || iss.SPIFFE.TrustDomain == "" {
+					return errors.Errorf("missing SPIFFE trust_domain for %s issuer", iss.Label)
+				}
+				if err = ValidateTrustDomain(iss.SPIFFE.TrustDomain); err != nil {
+					return errors.Annotatef(err, "invalid SPIFFE trust_domain for %s issuer", iss.Label)
+				}
+				for name, profile := range iss.Profiles {
+					if err = validateSVIDProfile(profile); err != nil {
+						return errors.Annotatef(err, "invalid %s profile for %s issuer", name, iss.Label)
+					}
+				}
+			}
+			if iss.Type == IssuerTypeSSH {
+				for name, profile := range iss.Profiles {
+					if profile.SSH == nil {
+						return errors.Errorf("invalid %s profile for %s issuer: missing ssh", name, iss.Label)
+					}
+				}
+			}
+			if err = iss.validateRSAPSS(); err != nil {
+				return errors.Annotatef(err, "invalid rsa_pss option for %s issuer", iss.Label)
+			}
 		}
 	}
 
@@ -1,6 +1,15 @@
 package authority_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -48,6 +57,13 @@ func TestLoadInvalidConfigFile(t *testing.T) {
 		{"testdata/invalid_uri.json", "invalid configuration: invalid withregex profile: failed to compile AllowedURI: error parsing regexp: missing closing ]: `[}`"},
 		{"testdata/invalid_email.json", "invalid configuration: invalid withregex profile: failed to compile AllowedEmail: error parsing regexp: missing closing ]: `[}`"},
 		{"testdata/invalid_qualifier.json", "invalid configuration: invalid with-qt profile: invalid policy qualifier type: qt-type"},
+		{"testdata/invalid_keypolicy.json", "invalid configuration: invalid with-keypolicy profile: invalid key policy: min_rsa_size must be at least 2048 bits"},
+		{"testdata/invalid_lint.json", "invalid configuration: invalid with-lint profile: invalid lint: unknown lint: no_such_lint"},
+		{"testdata/invalid_issuancepolicy.json", "invalid configuration: invalid with-policy profile: invalid issuance_policy: rule \"cn\": undeclared reference to 'user' (in container '') at 1:28"},
+		{"testdata/invalid_webhook.json", "invalid configuration: invalid with-webhook profile: invalid webhook: unsupported url: localhost:8443"},
+		{"testdata/invalid_approval.json", "invalid configuration: invalid with-approval profile: invalid approval: missing approvers"},
+		{"testdata/invalid_keyless.json", "invalid configuration: invalid with-keyless profile: keyless expiry must not exceed 1h0m0s"},
+		{"testdata/invalid_issuerselection.json", "invalid configuration: invalid with-selection profile: unsupported issuer_selection: random"},
 	}
 	for _, tc := range tcases {
 		t.Run(tc.file, func(t *testing.T) {
@@ -192,3 +208,51 @@ func TestProfilePolicyIsAllowed(t *testing.T) {
 			tc.policy.IssuerLabel, tc.role, tc.policy.AllowedRoles, tc.policy.DeniedRoles)
 	}
 }
+
+func TestValidateRSAPSS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "[TEST] ECDSA CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(t.TempDir(), "ecdsa_ca.pem")
+	err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+	require.NoError(t, err)
+
+	profile := &authority.CertProfile{
+		Usage:  []string{"client auth"},
+		Expiry: csr.OneYear,
+	}
+	cfg := &authority.Config{
+		Authority: &authority.CAConfig{
+			Issuers: []authority.IssuerConfig{
+				{
+					Label:    "ecdsa",
+					CertFile: certFile,
+					Profiles: map[string]*authority.CertProfile{"client": profile},
+				},
+			},
+		},
+		Profiles: map[string]*authority.CertProfile{"client": profile},
+	}
+	require.NoError(t, cfg.Validate())
+
+	profile.RSAPSS = true
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "invalid rsa_pss option for ecdsa issuer: RSA-PSS is not supported for ECDSA key: profile=client", err.Error())
+
+	profile.RSAPSS = false
+	cfg.Authority.Issuers[0].RSAPSS = true
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "invalid rsa_pss option for ecdsa issuer: RSA-PSS is not supported for ECDSA key", err.Error())
+}
@@ -0,0 +1,115 @@
+package authority
+
+import (
+	"crypto/x509"
+	"strings"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/juju/errors"
+)
+
+// CrossSign issues a cross-certificate for the existing CA certificate.
+// The cross-certificate has the same subject, public key and Subject Key ID
+// as the provided certificate, and does not outlive it.
+func (ca *Issuer) CrossSign(crt *x509.Certificate, profileName string) (*x509.Certificate, []byte, error) {
+	if profileName == "" {
+		profileName = "default"
+	}
+	profile := ca.cfg.Profiles[profileName]
+	if profile == nil {
+		return nil, nil, errors.New("unsupported profile: " + profileName)
+	}
+	if !profile.CAConstraint.IsCA {
+		return nil, nil, errors.Errorf("profile does not allow CA: %s", profileName)
+	}
+	if !crt.IsCA {
+		return nil, nil, errors.New("certificate is not CA")
+	}
+	if len(crt.SubjectKeyId) == 0 {
+		return nil, nil, errors.New("certificate does not have Subject Key ID")
+	}
+
+	err := ca.weakKeys.Check(crt.PublicKey)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "weak key")
+	}
+
+	template := x509.Certificate{
+		RawSubject:         crt.RawSubject,
+		Subject:            crt.Subject,
+		PublicKey:          crt.PublicKey,
+		PublicKeyAlgorithm: crt.PublicKeyAlgorithm,
+		SignatureAlgorithm: ca.sigAlgo,
+		NotAfter:           crt.NotAfter,
+	}
+	if profile.RSAPSS {
+		template.SignatureAlgorithm, err = csr.PSSSigAlgo(ca.sigAlgo)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "invalid rsa_pss option: profile=%s", profileName)
+		}
+	}
+
+	template.SerialNumber, err = newSerialNumber()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	err = ca.fillTemplate(&template, profile, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, nil, errors.Annotatef(err, "failed to populate template")
+	}
+	// preserve SKID, so the certificates issued by the CA
+	// can be chained to either of the roots
+	template.SubjectKeyId = crt.SubjectKeyId
+
+	signedCertPEM, err := ca.sign(&template, profile)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	cross, err := certutil.ParseFromPEM(signedCertPEM)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	return cross, signedCertPEM, nil
+}
+
+// AlternateChains returns the chains for the issuer,
+// built with the cross-certificates.
+// The primary chain is returned by Bundle.
+func (ca *Issuer) AlternateChains() [][]*x509.Certificate {
+	return ca.altChains
+}
+
+// AlternatesPEM returns PEM encoded certificates from the alternate chains,
+// that are not included in the primary bundle.
+func (ca *Issuer) AlternatesPEM() string {
+	primary := map[string]bool{
+		string(ca.bundle.Cert.Raw): true,
+	}
+	if ca.bundle.RootCert != nil {
+		primary[string(ca.bundle.RootCert.Raw)] = true
+	}
+	for _, c := range ca.bundle.Chain {
+		primary[string(c.Raw)] = true
+	}
+
+	var list []*x509.Certificate
+	for _, chain := range ca.altChains {
+		for _, c := range chain {
+			if !primary[string(c.Raw)] {
+				primary[string(c.Raw)] = true
+				list = append(list, c)
+			}
+		}
+	}
+	if len(list) == 0 {
+		return ""
+	}
+
+	pem, _ := certutil.EncodeToPEMString(true, list...)
+	return strings.TrimSpace(pem)
+}
@@ -0,0 +1,151 @@
+package authority_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/inmemcrypto"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrossSign(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	crypto, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+
+	dir, err := ioutil.TempDir("", "crosssign")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	profiles := map[string]*authority.CertProfile{
+		"L1_CA": {
+			Usage:        []string{"cert sign", "crl sign"},
+			CAConstraint: authority.CAConstraint{IsCA: true},
+			Expiry:       csr.OneYear,
+		},
+		"server": {
+			Usage:  []string{"signing", "server auth"},
+			Expiry: csr.OneYear,
+		},
+	}
+
+	createRoot := func(name string) (*authority.Issuer, []byte) {
+		rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &csr.CertificateRequest{
+			CommonName: "[TEST] Trusty Root CA " + name,
+			KeyRequest: prov.NewKeyRequest("TestCrossSign", "ECDSA", 256, csr.SigningKey),
+		})
+		require.NoError(t, err)
+
+		signer, err := authority.NewSignerFromPEM(crypto, rootKey)
+		require.NoError(t, err)
+
+		issuer, err := authority.CreateIssuer(&authority.IssuerConfig{
+			Label:    name,
+			Type:     authority.IssuerTypeCrossSign,
+			Profiles: profiles,
+		}, rootPEM, nil, nil, signer)
+		require.NoError(t, err)
+
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+".pem"), rootPEM, 0644))
+		return issuer, rootPEM
+	}
+
+	rootA, _ := createRoot("A")
+	rootB, rootBPEM := createRoot("B")
+
+	// L1 issued by Root A
+	csrPEM, key, _, _, err := prov.CreateRequestAndExportKey(&csr.CertificateRequest{
+		CommonName: "[TEST] Trusty Level 1 CA",
+		KeyRequest: prov.NewKeyRequest("TestCrossSign", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+	l1, l1PEM, err := rootA.Sign(csr.SignRequest{
+		Request: string(csrPEM),
+		Profile: "L1_CA",
+	})
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "L1.pem"), l1PEM, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "L1-key.pem"), key, 0600))
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := rootB.CrossSign(rootA.Bundle().Cert, "server")
+		assert.EqualError(t, err, "profile does not allow CA: server")
+
+		_, _, err = rootB.CrossSign(rootA.Bundle().Cert, "unknown")
+		assert.EqualError(t, err, "unsupported profile: unknown")
+
+		leafPEM, _, _, _, err := prov.CreateRequestAndExportKey(&csr.CertificateRequest{
+			CommonName: "trusty.com",
+			KeyRequest: prov.NewKeyRequest("TestCrossSign", "ECDSA", 256, csr.SigningKey),
+		})
+		require.NoError(t, err)
+		leaf, _, err := rootA.Sign(csr.SignRequest{
+			SAN:     []string{"trusty.com"},
+			Request: string(leafPEM),
+			Profile: "server",
+		})
+		require.NoError(t, err)
+		_, _, err = rootB.CrossSign(leaf, "L1_CA")
+		assert.EqualError(t, err, "certificate is not CA")
+	})
+
+	// Root A cross-signed by Root B
+	orig := rootA.Bundle().Cert
+	cross, crossPEM, err := rootB.CrossSign(orig, "L1_CA")
+	require.NoError(t, err)
+	assert.Equal(t, orig.RawSubject, cross.RawSubject)
+	assert.Equal(t, orig.RawSubjectPublicKeyInfo, cross.RawSubjectPublicKeyInfo)
+	assert.Equal(t, orig.SubjectKeyId, cross.SubjectKeyId)
+	assert.Equal(t, rootB.Bundle().Cert.RawSubject, cross.RawIssuer)
+	assert.True(t, cross.IsCA)
+	assert.False(t, cross.NotAfter.After(orig.NotAfter))
+	require.NoError(t, cross.CheckSignatureFrom(rootB.Bundle().Cert))
+
+	crossFile := filepath.Join(dir, "A-by-B.pem")
+	require.NoError(t, ioutil.WriteFile(crossFile, append(append(crossPEM, '\n'), rootBPEM...), 0644))
+
+	issuer, err := authority.NewIssuer(&authority.IssuerConfig{
+		Label:          "L1",
+		CertFile:       filepath.Join(dir, "L1.pem"),
+		KeyFile:        filepath.Join(dir, "L1-key.pem"),
+		RootBundleFile: filepath.Join(dir, "A.pem"),
+		CrossCertFiles: []string{crossFile},
+		Profiles:       profiles,
+	}, crypto)
+	require.NoError(t, err)
+	assert.Equal(t, orig.Raw, issuer.Bundle().RootCert.Raw)
+
+	chains := issuer.AlternateChains()
+	require.Len(t, chains, 1)
+	require.Len(t, chains[0], 3)
+	assert.Equal(t, l1.Raw, chains[0][0].Raw)
+	assert.Equal(t, cross.Raw, chains[0][1].Raw)
+	assert.Equal(t, rootB.Bundle().Cert.Raw, chains[0][2].Raw)
+
+	alts, err := certutil.ParseChainFromPEM([]byte(issuer.AlternatesPEM()))
+	require.NoError(t, err)
+	require.Len(t, alts, 2)
+	assert.Equal(t, cross.Raw, alts[0].Raw)
+	assert.Equal(t, rootB.Bundle().Cert.Raw, alts[1].Raw)
+
+	// cross-certificates for other CA
+	_, err = authority.NewIssuer(&authority.IssuerConfig{
+		Label:          "L1",
+		CertFile:       filepath.Join(dir, "L1.pem"),
+		KeyFile:        filepath.Join(dir, "L1-key.pem"),
+		RootBundleFile: filepath.Join(dir, "A.pem"),
+		CrossCertFiles: []string{filepath.Join(dir, "B.pem")},
+	}, crypto)
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "cross-certificates do not chain"), err.Error())
+}
@@ -37,6 +37,9 @@ var (
 	//   mechanisms(5) pkix(7) id-qt(2) id-qt-unotice(2)
 	iDQTUserNotice = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 2, 2}
 
+	oidExtensionExtKeyUsage    = asn1.ObjectIdentifier{2, 5, 29, 37}
+	oidExtKeyUsageTimeStamping = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 8}
+
 	// CTPoisonOID is the object ID of the critical poison extension for precertificates
 	// https://tools.ietf.org/html/rfc6962#page-9
 	CTPoisonOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 3}
@@ -14,11 +14,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ekspand/trusty/pkg/certlint"
 	"github.com/ekspand/trusty/pkg/csr"
 	"github.com/go-phorce/dolly/xlog"
 	"github.com/go-phorce/dolly/xpki/certutil"
 	"github.com/go-phorce/dolly/xpki/cryptoprov"
 	"github.com/juju/errors"
+	"golang.org/x/crypto/ssh"
 )
 
 var (
@@ -46,6 +48,22 @@ type Issuer struct {
 
 	keyHash  map[crypto.Hash][]byte
 	nameHash map[crypto.Hash][]byte
+
+	// weakKeys provides the blocklist of weak keys
+	weakKeys *csr.WeakKeys
+
+	// altChains contains alternate chains built with cross-certificates
+	altChains [][]*x509.Certificate
+
+	// jwtSigner signs JWT-SVID, applicable only for spiffe issuer
+	jwtSigner crypto.Signer
+	jwtKeyID  string
+
+	// sshSigner is used to sign OpenSSH certificates
+	sshSigner ssh.Signer
+
+	// codeSigner signs digests, applicable only for codesign issuer
+	codeSigner crypto.Signer
 }
 
 // Bundle returns certificates bundle
@@ -108,6 +126,24 @@ func (ca *Issuer) OcspExpiry() time.Duration {
 	return ca.ocspExpiry
 }
 
+// State returns the issuer's state: active|retiring|retired.
+// The retiring issuer becomes retired after RetireAfter time,
+// or when its certificate expires.
+func (ca *Issuer) State() string {
+	switch ca.cfg.State {
+	case IssuerStateRetired:
+		return IssuerStateRetired
+	case IssuerStateRetiring:
+		now := time.Now()
+		if (ca.cfg.RetireAfter != nil && now.After(*ca.cfg.RetireAfter)) ||
+			now.After(ca.bundle.Cert.NotAfter) {
+			return IssuerStateRetired
+		}
+		return IssuerStateRetiring
+	}
+	return IssuerStateActive
+}
+
 // Profile returns CertProfile
 func (ca *Issuer) Profile(name string) *CertProfile {
 	return ca.cfg.Profiles[name]
@@ -148,6 +184,52 @@ func NewIssuer(cfg *IssuerConfig, prov *cryptoprov.Crypto) (*Issuer, error) {
 		return nil, errors.Trace(err)
 	}
 
+	if len(cfg.CrossCertFiles) > 0 {
+		var crossBytes []byte
+		for _, file := range cfg.CrossCertFiles {
+			b, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, errors.Annotatef(err, "failed to load cross-certificates")
+			}
+			crossBytes = append(append(crossBytes, b...), '\n')
+		}
+
+		issuer.altChains, err = alternateChains(issuer.bundle.Cert, intCAbytes, rootBytes, crossBytes)
+		if err != nil {
+			return nil, errors.Annotatef(err, "failed to build alternate chains: label=%s", cfg.Label)
+		}
+	}
+
+	if cfg.Type == IssuerTypeSpiffe && cfg.SPIFFE != nil && cfg.SPIFFE.JWTKeyFile != "" {
+		jwtSigner, err := NewSignerFromFromFile(prov, cfg.SPIFFE.JWTKeyFile)
+		if err != nil {
+			return nil, errors.Annotatef(err, "unable to create JWT-SVID signer: label=%s", cfg.Label)
+		}
+		if err = issuer.SetJWTSigner(jwtSigner); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	if cfg.Type == IssuerTypeCodesign && cfg.CodeSign != nil && cfg.CodeSign.KeyFile != "" {
+		codeSigner, err := NewSignerFromFromFile(prov, cfg.CodeSign.KeyFile)
+		if err != nil {
+			return nil, errors.Annotatef(err, "unable to create code signer: label=%s", cfg.Label)
+		}
+		if err = issuer.SetCodeSigner(codeSigner); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	if cfg.Type == IssuerTypeSSH && cfg.SSH != nil && cfg.SSH.KeyFile != "" {
+		sshSigner, err := NewSignerFromFromFile(prov, cfg.SSH.KeyFile)
+		if err != nil {
+			return nil, errors.Annotatef(err, "unable to create SSH signer: label=%s", cfg.Label)
+		}
+		if err = issuer.SetSSHSigner(sshSigner); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	return issuer, nil
 }
 
@@ -155,7 +237,7 @@ func NewIssuer(cfg *IssuerConfig, prov *cryptoprov.Crypto) (*Issuer, error) {
 // this method is mostly used for testing
 func CreateIssuer(cfg *IssuerConfig, certBytes, intCAbytes, rootBytes []byte, signer crypto.Signer) (*Issuer, error) {
 	label := cfg.Label
-	bundle, status, err := certutil.VerifyBundleFromPEM(certBytes, intCAbytes, rootBytes)
+	bundle, status, err := verifyBundleFromPEM(certBytes, intCAbytes, rootBytes)
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to create signing CA cert bundle")
 	}
@@ -204,16 +286,24 @@ func CreateIssuer(cfg *IssuerConfig, certBytes, intCAbytes, rootBytes []byte, si
 			label, certutil.HashAlgoToStr(h), hex.EncodeToString(keyHash[h]), hex.EncodeToString(nameHash[h]))
 	}
 
+	sigAlgo := csr.DefaultSigAlgo(signer)
+	if cfg.RSAPSS {
+		sigAlgo, err = csr.PSSSigAlgo(sigAlgo)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid rsa_pss option: label=%s", label)
+		}
+	}
+
 	cabundlePEM := strings.TrimSpace(bundle.CertPEM)
 	if bundle.CACertsPEM != "" {
 		cabundlePEM = cabundlePEM + "\n" + strings.TrimSpace(bundle.CACertsPEM)
 	}
 
-	return &Issuer{
+	issuer := &Issuer{
 		cfg:         *cfg,
 		skid:        certutil.GetSubjectKeyID(bundle.Cert),
 		signer:      signer,
-		sigAlgo:     csr.DefaultSigAlgo(signer),
+		sigAlgo:     sigAlgo,
 		bundle:      bundle,
 		label:       label,
 		crlURL:      crl,
@@ -225,7 +315,14 @@ func CreateIssuer(cfg *IssuerConfig, certBytes, intCAbytes, rootBytes []byte, si
 		crlRenewal:  crlRenewal,
 		crlExpiry:   crlExpiry,
 		ocspExpiry:  ocspExpiry,
-	}, nil
+	}
+
+	if cfg.Type == IssuerTypeSSH {
+		if err = issuer.SetSSHSigner(signer); err != nil {
+			return nil, errors.Annotatef(err, "label=%s", label)
+		}
+	}
+	return issuer, nil
 }
 
 // Sign signs a new certificate based on the PEM-encoded
@@ -238,16 +335,34 @@ func (ca *Issuer) Sign(req csr.SignRequest) (*x509.Certificate, []byte, error) {
 		profileName = "default"
 	}
 	profile := ca.cfg.Profiles[profileName]
-	if profile == nil {
+	if profile == nil || profile.SSH != nil {
 		return nil, nil, errors.New("unsupported profile: " + profileName)
 	}
+	if state := ca.State(); state != IssuerStateActive {
+		return nil, nil, errors.Errorf("issuer is %s: %s", state, ca.label)
+	}
 
 	csrTemplate, err := csr.ParsePEM([]byte(req.Request))
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
 
+	err = profile.GetKeyPolicy().Check(csrTemplate.PublicKey)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "key policy")
+	}
+	err = ca.weakKeys.Check(csrTemplate.PublicKey)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "weak key")
+	}
+
 	csrTemplate.SignatureAlgorithm = ca.sigAlgo
+	if profile.RSAPSS {
+		csrTemplate.SignatureAlgorithm, err = csr.PSSSigAlgo(ca.sigAlgo)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "invalid rsa_pss option: profile=%s", profileName)
+		}
+	}
 
 	// Copy out only the fields from the CSR authorized by policy.
 	safeTemplate := x509.Certificate{}
@@ -328,24 +443,16 @@ func (ca *Issuer) Sign(req csr.SignRequest) (*x509.Certificate, []byte, error) {
 			}
 		}
 	}
-
-	{
-		// RFC 5280 4.1.2.2:
-		// Certificate users MUST be able to handle serialNumber
-		// values up to 20 octets.  Conforming CAs MUST NOT use
-		// serialNumber values longer than 20 octets.
-		serialNumber := make([]byte, 20)
-		_, err = io.ReadFull(rand.Reader, serialNumber)
+	if ca.cfg.Type == IssuerTypeSpiffe && !profile.CAConstraint.IsCA {
+		err = validateSVID(&safeTemplate, ca.TrustDomain())
 		if err != nil {
-			return nil, nil, errors.Annotatef(err, "failed to generate serial number")
+			return nil, nil, errors.Trace(err)
 		}
+	}
 
-		// SetBytes interprets buf as the bytes of a big-endian
-		// unsigned integer. The leading byte should be masked
-		// off to ensure it isn't negative.
-		serialNumber[0] &= 0x7F
-
-		safeTemplate.SerialNumber = new(big.Int).SetBytes(serialNumber)
+	safeTemplate.SerialNumber, err = newSerialNumber()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
 	}
 
 	if len(req.Extensions) > 0 {
@@ -374,7 +481,7 @@ func (ca *Issuer) Sign(req csr.SignRequest) (*x509.Certificate, []byte, error) {
 
 	var certTBS = safeTemplate
 
-	signedCertPEM, err := ca.sign(&certTBS)
+	signedCertPEM, err := ca.sign(&certTBS, profile)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
@@ -389,7 +496,26 @@ func (ca *Issuer) Sign(req csr.SignRequest) (*x509.Certificate, []byte, error) {
 	return crt, signedCertPEM, nil
 }
 
-func (ca *Issuer) sign(template *x509.Certificate) ([]byte, error) {
+func newSerialNumber() (*big.Int, error) {
+	// RFC 5280 4.1.2.2:
+	// Certificate users MUST be able to handle serialNumber
+	// values up to 20 octets.  Conforming CAs MUST NOT use
+	// serialNumber values longer than 20 octets.
+	serialNumber := make([]byte, 20)
+	_, err := io.ReadFull(rand.Reader, serialNumber)
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to generate serial number")
+	}
+
+	// SetBytes interprets buf as the bytes of a big-endian
+	// unsigned integer. The leading byte should be masked
+	// off to ensure it isn't negative.
+	serialNumber[0] &= 0x7F
+
+	return new(big.Int).SetBytes(serialNumber), nil
+}
+
+func (ca *Issuer) sign(template *x509.Certificate, profile *CertProfile) ([]byte, error) {
 	var caCert *x509.Certificate
 
 	if ca.bundle == nil {
@@ -405,6 +531,21 @@ func (ca *Issuer) sign(template *x509.Certificate) ([]byte, error) {
 		caCert = ca.bundle.Cert
 	}
 
+	// lint TBS before the signature exists
+	lints := certlint.Check(template, profile.Lint)
+	for _, r := range lints {
+		logger.KV(xlog.WARNING,
+			"reason", "lint",
+			"serial", template.SerialNumber.String(),
+			"cn", template.Subject.CommonName,
+			"lint", r.Lint,
+			"level", r.Level,
+			"message", r.Message)
+	}
+	if err := lints.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	derBytes, err := x509.CreateCertificate(rand.Reader, template, caCert, template.PublicKey, ca.signer)
 	if err != nil {
 		return nil, errors.Annotatef(err, "create certificate")
@@ -508,6 +649,19 @@ func (ca *Issuer) fillTemplate(template *x509.Certificate, profile *CertProfile,
 		}
 		template.ExtraExtensions = append(template.ExtraExtensions, ocspNoCheckExtension)
 	}
+	if len(eku) == 1 && eku[0] == x509.ExtKeyUsageTimeStamping {
+		// RFC 3161 2.3: the TSA certificate MUST contain only one instance of
+		// the extended key usage field extension, marked as critical
+		value, err := asn1.Marshal([]asn1.ObjectIdentifier{oidExtKeyUsageTimeStamping})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, pkix.Extension{
+			Id:       oidExtensionExtKeyUsage,
+			Critical: true,
+			Value:    value,
+		})
+	}
 
 	return nil
 }
@@ -2,9 +2,23 @@ package authority_test
 
 import (
 	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"testing"
+	"time"
 
 	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/pkg/certlint"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/inmemcrypto"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func (s *testSuite) TestNewIssuer() {
@@ -81,3 +95,330 @@ func (s *testSuite) TestNewIssuerErrors() {
 	s.Require().Error(err)
 	s.Equal("failed to load ca-bundle: open not_found: no such file or directory", err.Error())
 }
+
+func TestIssuerSignLint(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	crypto, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+	rootReq := csr.CertificateRequest{
+		CommonName: "[TEST] Trusty Root CA",
+		KeyRequest: prov.NewKeyRequest("TestIssuerSignLint", "ECDSA", 256, csr.SigningKey),
+	}
+	rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &rootReq)
+	require.NoError(t, err)
+
+	rootSigner, err := authority.NewSignerFromPEM(crypto, rootKey)
+	require.NoError(t, err)
+
+	cfg := &authority.IssuerConfig{
+		Label: "TrustyRoot",
+		Profiles: map[string]*authority.CertProfile{
+			"server": {
+				Usage:  []string{"server auth", "digital signature"},
+				Expiry: 2 * csr.OneYear,
+			},
+			"server_strict": {
+				Usage:  []string{"server auth", "digital signature"},
+				Expiry: 2 * csr.OneYear,
+				Lint: map[string]certlint.Level{
+					"validity_max_leaf": certlint.LevelError,
+					"san_present":       certlint.LevelError,
+				},
+			},
+			"server_relaxed": {
+				Usage:  []string{"server auth", "digital signature"},
+				Expiry: 2 * csr.OneYear,
+				Lint: map[string]certlint.Level{
+					"san_present": certlint.LevelIgnore,
+				},
+			},
+		},
+	}
+	for name, profile := range cfg.Profiles {
+		require.NoError(t, profile.Validate(), "failed to validate %s profile", name)
+	}
+
+	issuer, err := authority.CreateIssuer(cfg, rootPEM, nil, nil, rootSigner)
+	require.NoError(t, err)
+
+	csrPEM, _, _, _, err := prov.CreateRequestAndExportKey(&csr.CertificateRequest{
+		CommonName: "trusty.com",
+		SAN:        []string{"trusty.com"},
+		KeyRequest: prov.NewKeyRequest("TestIssuerSignLint", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+
+	crt, _, err := issuer.Sign(csr.SignRequest{
+		Request: string(csrPEM),
+		Profile: "server",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2*csr.OneYear.TimeDuration(), crt.NotAfter.Sub(crt.NotBefore))
+
+	_, _, err = issuer.Sign(csr.SignRequest{
+		Request: string(csrPEM),
+		Profile: "server_strict",
+	})
+	require.Error(t, err)
+	assert.Equal(t, "certificate lint failed: validity_max_leaf: validity period is 730 days", err.Error())
+
+	noSanPEM, _, _, _, err := prov.CreateRequestAndExportKey(&csr.CertificateRequest{
+		CommonName: "trusty.com",
+		KeyRequest: prov.NewKeyRequest("TestIssuerSignLint", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+
+	crt, _, err = issuer.Sign(csr.SignRequest{
+		Request:   string(noSanPEM),
+		Profile:   "server",
+		NotBefore: time.Now().UTC(),
+	})
+	require.NoError(t, err, "san_present is a warning by default")
+	assert.Empty(t, crt.DNSNames)
+
+	_, _, err = issuer.Sign(csr.SignRequest{
+		Request:   string(noSanPEM),
+		Profile:   "server_strict",
+		NotBefore: time.Now().UTC(),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "san_present: Subject Alternative Name is missing")
+
+	crt, _, err = issuer.Sign(csr.SignRequest{
+		Request:   string(noSanPEM),
+		Profile:   "server_relaxed",
+		NotBefore: time.Now().UTC(),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, crt.DNSNames)
+}
+
+func TestIssuerSignKeyPolicy(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	crypto, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+	rootReq := csr.CertificateRequest{
+		CommonName: "[TEST] Trusty Root CA",
+		KeyRequest: prov.NewKeyRequest("TestIssuerSignKeyPolicy", "ECDSA", 256, csr.SigningKey),
+	}
+	rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &rootReq)
+	require.NoError(t, err)
+
+	rootSigner, err := authority.NewSignerFromPEM(crypto, rootKey)
+	require.NoError(t, err)
+
+	cfg := &authority.IssuerConfig{
+		Label: "TrustyRoot",
+		Profiles: map[string]*authority.CertProfile{
+			"client": {
+				Usage:  []string{"client auth", "digital signature"},
+				Expiry: csr.OneYear,
+			},
+			"client_p384": {
+				Usage:  []string{"client auth", "digital signature"},
+				Expiry: csr.OneYear,
+				KeyPolicy: &csr.KeyPolicy{
+					Algorithms:  []string{"ECDSA"},
+					ECDSACurves: []string{"P-384"},
+				},
+			},
+		},
+	}
+	for name, profile := range cfg.Profiles {
+		require.NoError(t, profile.Validate(), "failed to validate %s profile", name)
+	}
+
+	issuer, err := authority.CreateIssuer(cfg, rootPEM, nil, nil, rootSigner)
+	require.NoError(t, err)
+
+	tcases := []struct {
+		algo    string
+		size    int
+		profile string
+		err     string
+	}{
+		{"RSA", 1024, "client", "key policy: RSA key is too weak: 1024 bits, minimum 2048 bits is required"},
+		{"RSA", 2048, "client", ""},
+		{"ECDSA", 256, "client", ""},
+		{"ECDSA", 256, "client_p384", "key policy: ECDSA curve is not allowed: P-256"},
+		{"RSA", 2048, "client_p384", "key policy: RSA key is not allowed"},
+		{"ECDSA", 384, "client_p384", ""},
+	}
+
+	for _, tc := range tcases {
+		t.Run(fmt.Sprintf("%s_%d_%s", tc.algo, tc.size, tc.profile), func(t *testing.T) {
+			var csrPEM []byte
+			if tc.algo == "RSA" && tc.size < 2048 {
+				// key request does not allow to generate weak keys
+				key, err := rsa.GenerateKey(rand.Reader, tc.size)
+				require.NoError(t, err)
+				der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+					Subject: pkix.Name{CommonName: "weak"},
+				}, key)
+				require.NoError(t, err)
+				csrPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+			} else {
+				csrPEM, _, _, _, err = prov.CreateRequestAndExportKey(&csr.CertificateRequest{
+					CommonName: "client",
+					KeyRequest: prov.NewKeyRequest("TestIssuerSignKeyPolicy", tc.algo, tc.size, csr.SigningKey),
+				})
+				require.NoError(t, err)
+			}
+
+			crt, _, err := issuer.Sign(csr.SignRequest{
+				Request: string(csrPEM),
+				Profile: tc.profile,
+			})
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.err, err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, crt)
+			}
+		})
+	}
+}
+
+func TestIssuerEd25519(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	crypto, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+	rootReq := csr.CertificateRequest{
+		CommonName: "[TEST] Trusty Root CA",
+		KeyRequest: prov.NewKeyRequest("TestIssuerEd25519", "ED25519", 0, csr.SigningKey),
+	}
+	rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &rootReq)
+	require.NoError(t, err)
+
+	root, err := certutil.ParseFromPEM(rootPEM)
+	require.NoError(t, err)
+	assert.Equal(t, x509.PureEd25519, root.SignatureAlgorithm)
+
+	rootSigner, err := authority.NewSignerFromPEM(crypto, rootKey)
+	require.NoError(t, err)
+
+	cfg := &authority.IssuerConfig{
+		Label: "TrustyRoot",
+		Profiles: map[string]*authority.CertProfile{
+			"client": {
+				Usage:  []string{"client auth", "digital signature"},
+				Expiry: csr.OneYear,
+			},
+		},
+	}
+	issuer, err := authority.CreateIssuer(cfg, rootPEM, nil, nil, rootSigner)
+	require.NoError(t, err)
+
+	csrPEM, _, _, _, err := prov.CreateRequestAndExportKey(&csr.CertificateRequest{
+		CommonName: "client",
+		KeyRequest: prov.NewKeyRequest("TestIssuerEd25519", "ED25519", 0, csr.SigningKey),
+	})
+	require.NoError(t, err)
+
+	crt, _, err := issuer.Sign(csr.SignRequest{
+		Request: string(csrPEM),
+		Profile: "client",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, x509.PureEd25519, crt.SignatureAlgorithm)
+	assert.Equal(t, x509.Ed25519, crt.PublicKeyAlgorithm)
+	assert.NoError(t, crt.CheckSignatureFrom(root))
+}
+
+func TestIssuerRSAPSS(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	crypto, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+	profiles := map[string]*authority.CertProfile{
+		"client": {
+			Usage:  []string{"client auth", "digital signature"},
+			Expiry: csr.OneYear,
+		},
+		"client_pss": {
+			Usage:  []string{"client auth", "digital signature"},
+			Expiry: csr.OneYear,
+			RSAPSS: true,
+		},
+	}
+
+	csrPEM, _, _, _, err := prov.CreateRequestAndExportKey(&csr.CertificateRequest{
+		CommonName: "client",
+		KeyRequest: prov.NewKeyRequest("TestIssuerRSAPSS", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+
+	t.Run("RSA", func(t *testing.T) {
+		rootReq := csr.CertificateRequest{
+			CommonName: "[TEST] Trusty Root CA",
+			KeyRequest: prov.NewKeyRequest("TestIssuerRSAPSS", "RSA", 2048, csr.SigningKey),
+		}
+		rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &rootReq)
+		require.NoError(t, err)
+		rootSigner, err := authority.NewSignerFromPEM(crypto, rootKey)
+		require.NoError(t, err)
+
+		issuer, err := authority.CreateIssuer(&authority.IssuerConfig{
+			Label:    "TrustyRoot",
+			Profiles: profiles,
+		}, rootPEM, nil, nil, rootSigner)
+		require.NoError(t, err)
+
+		crt, _, err := issuer.Sign(csr.SignRequest{Request: string(csrPEM), Profile: "client"})
+		require.NoError(t, err)
+		assert.Equal(t, x509.SHA256WithRSA, crt.SignatureAlgorithm)
+
+		crt, _, err = issuer.Sign(csr.SignRequest{Request: string(csrPEM), Profile: "client_pss"})
+		require.NoError(t, err)
+		assert.Equal(t, x509.SHA256WithRSAPSS, crt.SignatureAlgorithm)
+		assert.NoError(t, crt.CheckSignatureFrom(issuer.Bundle().Cert))
+
+		issuer, err = authority.CreateIssuer(&authority.IssuerConfig{
+			Label:    "TrustyRoot",
+			Profiles: profiles,
+			RSAPSS:   true,
+		}, rootPEM, nil, nil, rootSigner)
+		require.NoError(t, err)
+
+		crt, _, err = issuer.Sign(csr.SignRequest{Request: string(csrPEM), Profile: "client"})
+		require.NoError(t, err)
+		assert.Equal(t, x509.SHA256WithRSAPSS, crt.SignatureAlgorithm)
+	})
+
+	t.Run("ECDSA", func(t *testing.T) {
+		rootReq := csr.CertificateRequest{
+			CommonName: "[TEST] Trusty Root CA",
+			KeyRequest: prov.NewKeyRequest("TestIssuerRSAPSS", "ECDSA", 256, csr.SigningKey),
+		}
+		rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &rootReq)
+		require.NoError(t, err)
+		rootSigner, err := authority.NewSignerFromPEM(crypto, rootKey)
+		require.NoError(t, err)
+
+		_, err = authority.CreateIssuer(&authority.IssuerConfig{
+			Label:    "TrustyRoot",
+			Profiles: profiles,
+			RSAPSS:   true,
+		}, rootPEM, nil, nil, rootSigner)
+		require.Error(t, err)
+		assert.Equal(t, "invalid rsa_pss option: label=TrustyRoot: RSA-PSS is not supported for ECDSA-SHA256", err.Error())
+
+		issuer, err := authority.CreateIssuer(&authority.IssuerConfig{
+			Label:    "TrustyRoot",
+			Profiles: profiles,
+		}, rootPEM, nil, nil, rootSigner)
+		require.NoError(t, err)
+
+		_, _, err = issuer.Sign(csr.SignRequest{Request: string(csrPEM), Profile: "client_pss"})
+		require.Error(t, err)
+		assert.Equal(t, "invalid rsa_pss option: profile=client_pss: RSA-PSS is not supported for ECDSA-SHA256", err.Error())
+	})
+}
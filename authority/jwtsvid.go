@@ -0,0 +1,175 @@
+package authority
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/jwt"
+	"github.com/juju/errors"
+)
+
+// DefaultJWTSVIDExpiry specifies default lifetime of JWT-SVID
+const DefaultJWTSVIDExpiry = 5 * time.Minute
+
+// JWTSVIDClaims provides claims of JWT-SVID
+type JWTSVIDClaims struct {
+	// Subject specifies SPIFFE ID of the workload
+	Subject string `json:"sub"`
+	// Audience specifies the intended recipients of the token
+	Audience jwt.Audience `json:"aud"`
+	// ExpiresAt specifies the expiration time in Unix seconds
+	ExpiresAt int64 `json:"exp"`
+	// IssuedAt specifies the issue time in Unix seconds
+	IssuedAt int64 `json:"iat,omitempty"`
+}
+
+// SetJWTSigner sets the key to sign JWT-SVID,
+// the Key ID is derived from the public key
+func (ca *Issuer) SetJWTSigner(signer crypto.Signer) error {
+	if _, _, err := jwt.Algorithm(signer.Public()); err != nil {
+		return errors.Trace(err)
+	}
+	spki, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	kid := sha256.Sum256(spki)
+
+	ca.jwtSigner = signer
+	ca.jwtKeyID = base64.RawURLEncoding.EncodeToString(kid[:])
+	return nil
+}
+
+// JWTKeyID returns Key ID of JWT-SVID signer,
+// or empty string if JWT-SVID is not supported
+func (ca *Issuer) JWTKeyID() string {
+	return ca.jwtKeyID
+}
+
+// JWTPublicKey returns the public key of JWT-SVID signer,
+// or nil if JWT-SVID is not supported
+func (ca *Issuer) JWTPublicKey() crypto.PublicKey {
+	if ca.jwtSigner == nil {
+		return nil
+	}
+	return ca.jwtSigner.Public()
+}
+
+// JWTPublicKeyPEM returns PEM encoded public key of JWT-SVID signer,
+// or empty string if JWT-SVID is not supported
+func (ca *Issuer) JWTPublicKeyPEM() string {
+	if ca.jwtSigner == nil {
+		return ""
+	}
+	spki, err := x509.MarshalPKIXPublicKey(ca.jwtSigner.Public())
+	if err != nil {
+		return ""
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: spki}))
+}
+
+// JWTExpiry returns lifetime of JWT-SVID
+func (ca *Issuer) JWTExpiry() time.Duration {
+	if ca.cfg.SPIFFE != nil && ca.cfg.SPIFFE.JWTExpiry > 0 {
+		return ca.cfg.SPIFFE.JWTExpiry
+	}
+	return DefaultJWTSVIDExpiry
+}
+
+// SignJWTSVID returns JWT-SVID for the SPIFFE ID and audience
+func (ca *Issuer) SignJWTSVID(spiffeID *url.URL, audience []string) (string, *JWTSVIDClaims, error) {
+	if ca.jwtSigner == nil {
+		return "", nil, errors.Errorf("issuer does not support JWT-SVID: %s", ca.label)
+	}
+	if state := ca.State(); state != IssuerStateActive {
+		return "", nil, errors.Errorf("issuer is %s: %s", state, ca.label)
+	}
+	if len(audience) == 0 {
+		return "", nil, errors.New("missing audience")
+	}
+	err := ValidateSpiffeID(spiffeID, ca.TrustDomain())
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+
+	now := time.Now().UTC()
+	claims := &JWTSVIDClaims{
+		Subject:   spiffeID.String(),
+		Audience:  audience,
+		ExpiresAt: now.Add(ca.JWTExpiry()).Unix(),
+		IssuedAt:  now.Unix(),
+	}
+
+	token, err := jwt.Sign(ca.jwtSigner, ca.jwtKeyID, claims)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	return token, claims, nil
+}
+
+// GetJWTSVIDIssuer returns the active spiffe issuer of the trust domain,
+// that supports JWT-SVID, by label, or the first one ordered by label,
+// if the label is not provided
+func (s *Authority) GetJWTSVIDIssuer(trustDomain, label string) (*Issuer, error) {
+	var list []*Issuer
+	for _, issuer := range s.issuers {
+		if issuer.TrustDomain() == trustDomain &&
+			issuer.jwtSigner != nil &&
+			issuer.State() == IssuerStateActive &&
+			(label == "" || strings.EqualFold(issuer.Label(), label)) {
+			list = append(list, issuer)
+		}
+	}
+	if len(list) == 0 {
+		return nil, errors.Errorf("JWT-SVID issuer not found for trust domain: %s", trustDomain)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Label() < list[j].Label()
+	})
+	return list[0], nil
+}
+
+// ValidateJWTSVID returns claims of JWT-SVID, signed by one of spiffe issuers,
+// if the token is not expired and the audience is in the token
+func (s *Authority) ValidateJWTSVID(token, audience string) (*JWTSVIDClaims, error) {
+	var signer *Issuer
+	claims := new(JWTSVIDClaims)
+	_, err := jwt.Verify(token, func(h *jwt.Header) (crypto.PublicKey, error) {
+		if h.Kid == "" {
+			return nil, errors.New("missing kid")
+		}
+		for _, issuer := range s.issuers {
+			if issuer.jwtKeyID == h.Kid && issuer.State() != IssuerStateRetired {
+				signer = issuer
+				return issuer.JWTPublicKey(), nil
+			}
+		}
+		return nil, errors.Errorf("unexpected kid: %s", h.Kid)
+	}, claims)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to verify token")
+	}
+
+	if claims.ExpiresAt == 0 || time.Now().Unix() >= claims.ExpiresAt {
+		return nil, errors.New("token is expired")
+	}
+	if audience == "" || !claims.Audience.Contains(audience) {
+		return nil, errors.Errorf("token audience does not match: %s", audience)
+	}
+	id, err := url.Parse(claims.Subject)
+	if err != nil {
+		return nil, errors.Errorf("invalid subject: %s", claims.Subject)
+	}
+	err = ValidateSpiffeID(id, signer.TrustDomain())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,121 @@
+package authority_test
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/inmemcrypto"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTSVID(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	cryptoProv, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+
+	dir, err := ioutil.TempDir("", "jwtsvid")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &csr.CertificateRequest{
+		CommonName: "[TEST] Trusty SPIFFE Root CA",
+		KeyRequest: prov.NewKeyRequest("TestJWTSVID", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+
+	_, jwtKey, _, _, err := prov.CreateRequestAndExportKey(&csr.CertificateRequest{
+		CommonName: "[TEST] Trusty JWT-SVID",
+		KeyRequest: prov.NewKeyRequest("TestJWTSVID", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "root.pem"), rootPEM, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "root-key.pem"), rootKey, 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "jwt-key.pem"), jwtKey, 0600))
+
+	cfg := &authority.Config{
+		Authority: &authority.CAConfig{
+			DefaultAIA: &authority.AIAConfig{},
+			Issuers: []authority.IssuerConfig{
+				{
+					Label:    "SPIFFE",
+					Type:     authority.IssuerTypeSpiffe,
+					CertFile: filepath.Join(dir, "root.pem"),
+					KeyFile:  filepath.Join(dir, "root-key.pem"),
+					SPIFFE: &authority.SPIFFEConfig{
+						TrustDomain: "trusty.ekspand.com",
+						JWTKeyFile:  filepath.Join(dir, "jwt-key.pem"),
+						JWTExpiry:   time.Minute,
+					},
+				},
+				{
+					Label:    "NOJWT",
+					Type:     authority.IssuerTypeSpiffe,
+					CertFile: filepath.Join(dir, "root.pem"),
+					KeyFile:  filepath.Join(dir, "root-key.pem"),
+					SPIFFE: &authority.SPIFFEConfig{
+						TrustDomain: "other.com",
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+
+	ca, err := authority.NewAuthority(cfg, cryptoProv)
+	require.NoError(t, err)
+
+	issuer, err := ca.GetIssuerByType(authority.IssuerTypeSpiffe, "SPIFFE")
+	require.NoError(t, err)
+	assert.NotEmpty(t, issuer.JWTKeyID())
+	assert.NotNil(t, issuer.JWTPublicKey())
+	assert.Equal(t, time.Minute, issuer.JWTExpiry())
+	assert.Contains(t, issuer.JWTPublicKeyPEM(), "PUBLIC KEY")
+
+	found, err := ca.GetJWTSVIDIssuer("trusty.ekspand.com", "")
+	require.NoError(t, err)
+	assert.Same(t, issuer, found)
+	_, err = ca.GetJWTSVIDIssuer("other.com", "")
+	assert.EqualError(t, err, "JWT-SVID issuer not found for trust domain: other.com")
+
+	id, err := url.Parse("spiffe://trusty.ekspand.com/workload")
+	require.NoError(t, err)
+
+	token, claims, err := issuer.SignJWTSVID(id, []string{"trusty", "backend"})
+	require.NoError(t, err)
+	assert.Equal(t, id.String(), claims.Subject)
+	assert.True(t, claims.ExpiresAt-claims.IssuedAt <= 60)
+
+	validated, err := ca.ValidateJWTSVID(token, "backend")
+	require.NoError(t, err)
+	assert.Equal(t, claims, validated)
+
+	_, err = ca.ValidateJWTSVID(token, "other")
+	assert.EqualError(t, err, "token audience does not match: other")
+	_, err = ca.ValidateJWTSVID(token[:len(token)-4]+"AAAA", "trusty")
+	assert.EqualError(t, err, "failed to verify token: invalid signature")
+
+	_, _, err = issuer.SignJWTSVID(id, nil)
+	assert.EqualError(t, err, "missing audience")
+
+	other, err := url.Parse("spiffe://other.com/workload")
+	require.NoError(t, err)
+	_, _, err = issuer.SignJWTSVID(other, []string{"trusty"})
+	assert.EqualError(t, err, "SPIFFE ID does not belong to trusty.ekspand.com trust domain: spiffe://other.com/workload")
+
+	nojwt, err := ca.GetIssuerByType(authority.IssuerTypeSpiffe, "NOJWT")
+	require.NoError(t, err)
+	assert.Empty(t, nojwt.JWTKeyID())
+	_, _, err = nojwt.SignJWTSVID(other, []string{"trusty"})
+	assert.EqualError(t, err, "issuer does not support JWT-SVID: NOJWT")
+}
@@ -0,0 +1,197 @@
+package authority
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/juju/errors"
+)
+
+// DefaultKeylessProfile specifies default profile for keyless signing
+const DefaultKeylessProfile = "keyless"
+
+// MaxKeylessExpiry specifies the maximum expiry of keyless profiles
+const MaxKeylessExpiry = time.Hour
+
+// KeylessRequest specifies the request for a short-lived certificate,
+// bound to the identity of the caller presented by the token
+type KeylessRequest struct {
+	// Profile specifies the profile of the short-lived certificate,
+	// if not provided, then DefaultKeylessProfile is used
+	Profile string
+	// PublicKey specifies the public key of the caller
+	PublicKey crypto.PublicKey
+	// Token specifies the presented token, the Proof is bound to
+	Token string
+	// Proof specifies the signature of SHA-256 of the Token by the caller's private key
+	Proof []byte
+	// Subject specifies the subject of the validated token
+	Subject string
+	// Issuer specifies the issuer of the validated token
+	Issuer string
+}
+
+// SignKeyless issues a short-lived code signing certificate for the public key,
+// after the proof of possession of the private key is verified.
+// The SAN of the certificate is the Email, if the subject is an email address,
+// or URI built from the token's issuer and subject otherwise.
+func (ca *Issuer) SignKeyless(req *KeylessRequest) (*x509.Certificate, []byte, error) {
+	if ca.cfg.Type != IssuerTypeCodesign {
+		return nil, nil, errors.Errorf("issuer does not support code signing: %s", ca.label)
+	}
+	if state := ca.State(); state != IssuerStateActive {
+		return nil, nil, errors.Errorf("issuer is %s: %s", state, ca.label)
+	}
+
+	profileName := req.Profile
+	if profileName == "" {
+		profileName = DefaultKeylessProfile
+	}
+	profile, err := ca.keylessProfile(profileName)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	if req.Subject == "" {
+		return nil, nil, errors.New("missing subject")
+	}
+	err = VerifyProofOfPossession(req.PublicKey, req.Token, req.Proof)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	err = profile.GetKeyPolicy().Check(req.PublicKey)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "key policy")
+	}
+	err = ca.weakKeys.Check(req.PublicKey)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "weak key")
+	}
+
+	template := &x509.Certificate{
+		PublicKey:          req.PublicKey,
+		SignatureAlgorithm: ca.sigAlgo,
+	}
+
+	// the Subject is empty, the SAN extension is marked as critical
+	if email, ok := emailAddress(req.Subject); ok {
+		if profile.AllowedEmailRegex != nil && !profile.AllowedEmailRegex.MatchString(email) {
+			return nil, nil, errors.New("Email does not match allowed list: " + email)
+		}
+		template.EmailAddresses = []string{email}
+	} else {
+		uri, err := keylessURI(req.Issuer, req.Subject)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if profile.AllowedURIRegex != nil && !profile.AllowedURIRegex.MatchString(uri.String()) {
+			return nil, nil, errors.New("URI does not match allowed list: " + uri.String())
+		}
+		template.URIs = []*url.URL{uri}
+	}
+
+	template.SerialNumber, err = newSerialNumber()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	err = ca.fillTemplate(template, profile, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, nil, errors.Annotatef(err, "failed to populate template")
+	}
+
+	signedCertPEM, err := ca.sign(template, profile)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	crt, err := certutil.ParseFromPEM(signedCertPEM)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return crt, signedCertPEM, nil
+}
+
+// keylessProfile returns the code signing profile, marked as keyless,
+// with short expiry
+func (ca *Issuer) keylessProfile(profileName string) (*CertProfile, error) {
+	profile, err := ca.codeSignProfile(profileName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !profile.Keyless {
+		return nil, errors.Errorf("profile does not allow keyless signing: %s", profileName)
+	}
+	if profile.Expiry.TimeDuration() > MaxKeylessExpiry {
+		return nil, errors.Errorf("profile expiry exceeds %v: %s", MaxKeylessExpiry, profileName)
+	}
+	return profile, nil
+}
+
+// VerifyProofOfPossession verifies the signature of the presented token
+// by the private key of the public key.
+// The proof is bound to the token, and can not be replayed with another token.
+// ECDSA, RSA PKCS#1 v1.5 and Ed25519 signatures are computed over SHA-256 of the token.
+func VerifyProofOfPossession(pub crypto.PublicKey, token string, proof []byte) error {
+	if token == "" {
+		return errors.New("missing token")
+	}
+	if len(proof) == 0 {
+		return errors.New("missing proof of possession")
+	}
+
+	digest := sha256.Sum256([]byte(token))
+	var ok bool
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		ok = ecdsa.VerifyASN1(key, digest[:], proof)
+	case *rsa.PublicKey:
+		ok = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], proof) == nil
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(key, digest[:], proof)
+	default:
+		return errors.Errorf("unsupported public key: %T", pub)
+	}
+	if !ok {
+		return errors.New("invalid proof of possession")
+	}
+	return nil
+}
+
+// emailAddress returns the address, if the subject is a plain email address
+func emailAddress(subject string) (string, bool) {
+	addr, err := mail.ParseAddress(subject)
+	if err != nil || addr.Address != subject {
+		return "", false
+	}
+	return addr.Address, true
+}
+
+// keylessURI returns URI of the subject in the issuer's namespace,
+// the issuer without a scheme is treated as https host
+func keylessURI(issuer, subject string) (*url.URL, error) {
+	if issuer == "" {
+		return nil, errors.New("missing issuer")
+	}
+	if !strings.Contains(issuer, "://") {
+		issuer = "https://" + issuer
+	}
+	u, err := url.Parse(issuer)
+	if err != nil || u.Host == "" {
+		return nil, errors.Errorf("invalid issuer: %s", issuer)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + subject
+	u.RawPath = ""
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u, nil
+}
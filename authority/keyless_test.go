@@ -0,0 +1,205 @@
+package authority_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/inmemcrypto"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignKeyless(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	cryptoProv, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+
+	profiles := map[string]*authority.CertProfile{
+		authority.DefaultKeylessProfile: {
+			Usage:   []string{"digital signature", "code signing"},
+			Expiry:  csr.Duration(10 * time.Minute),
+			Keyless: true,
+		},
+		"codesign": {
+			Usage:  []string{"digital signature", "code signing"},
+			Expiry: csr.OneYear,
+		},
+		"keyless_long": {
+			Usage:   []string{"digital signature", "code signing"},
+			Expiry:  csr.OneYear,
+			Keyless: true,
+		},
+		"server": {
+			Usage:  []string{"digital signature", "server auth"},
+			Expiry: csr.OneYear,
+		},
+	}
+
+	rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &csr.CertificateRequest{
+		CommonName: "[TEST] Trusty Keyless CA",
+		KeyRequest: prov.NewKeyRequest("TestSignKeyless", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+	signer, err := authority.NewSignerFromPEM(cryptoProv, rootKey)
+	require.NoError(t, err)
+
+	issuer, err := authority.CreateIssuer(&authority.IssuerConfig{
+		Label:    "codesign",
+		Type:     authority.IssuerTypeCodesign,
+		Profiles: profiles,
+	}, rootPEM, nil, nil, signer)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	// the tokens are opaque to the issuer, they are validated by the service
+	proof := func(token string) []byte {
+		digest := sha256.Sum256([]byte(token))
+		sig, err := key.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.NoError(t, err)
+		return sig
+	}
+
+	t.Run("email", func(t *testing.T) {
+		crt, _, err := issuer.SignKeyless(&authority.KeylessRequest{
+			PublicKey: key.Public(),
+			Token:     "token1",
+			Proof:     proof("token1"),
+			Subject:   "denis@ekspand.com",
+			Issuer:    "dev.trusty.com",
+		})
+		require.NoError(t, err)
+		assert.Empty(t, crt.Subject.CommonName)
+		assert.Equal(t, []string{"denis@ekspand.com"}, crt.EmailAddresses)
+		assert.Empty(t, crt.URIs)
+		assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}, crt.ExtKeyUsage)
+		assert.True(t, crt.NotAfter.Sub(crt.NotBefore) <= 15*time.Minute)
+		require.NoError(t, crt.CheckSignatureFrom(issuer.Bundle().Cert))
+	})
+
+	t.Run("uri", func(t *testing.T) {
+		crt, _, err := issuer.SignKeyless(&authority.KeylessRequest{
+			PublicKey: key.Public(),
+			Token:     "token2",
+			Proof:     proof("token2"),
+			Subject:   "repo:ekspand/trusty",
+			Issuer:    "https://token.ci.ekspand.com/",
+		})
+		require.NoError(t, err)
+		assert.Empty(t, crt.EmailAddresses)
+		require.Len(t, crt.URIs, 1)
+		assert.Equal(t, "https://token.ci.ekspand.com/repo:ekspand/trusty", crt.URIs[0].String())
+	})
+
+	t.Run("ed25519", func(t *testing.T) {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		crt, _, err := issuer.SignKeyless(&authority.KeylessRequest{
+			PublicKey: pub,
+			Token:     "token3",
+			Proof:     ed25519.Sign(priv, sha256digest("token3")),
+			Subject:   "ci-job",
+			Issuer:    "dev.trusty.com",
+		})
+		require.NoError(t, err)
+		require.Len(t, crt.URIs, 1)
+		assert.Equal(t, "https://dev.trusty.com/ci-job", crt.URIs[0].String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := issuer.SignKeyless(&authority.KeylessRequest{
+			PublicKey: key.Public(),
+			Token:     "token1",
+			Proof:     proof("token2"),
+			Subject:   "denis@ekspand.com",
+			Issuer:    "dev.trusty.com",
+		})
+		assert.EqualError(t, err, "invalid proof of possession")
+
+		_, _, err = issuer.SignKeyless(&authority.KeylessRequest{
+			PublicKey: key.Public(),
+			Token:     "token1",
+			Subject:   "denis@ekspand.com",
+			Issuer:    "dev.trusty.com",
+		})
+		assert.EqualError(t, err, "missing proof of possession")
+
+		_, _, err = issuer.SignKeyless(&authority.KeylessRequest{
+			PublicKey: key.Public(),
+			Proof:     proof("token1"),
+			Subject:   "denis@ekspand.com",
+			Issuer:    "dev.trusty.com",
+		})
+		assert.EqualError(t, err, "missing token")
+
+		_, _, err = issuer.SignKeyless(&authority.KeylessRequest{
+			PublicKey: key.Public(),
+			Token:     "token3",
+			Proof:     proof("token3"),
+			Subject:   "ci-job",
+		})
+		assert.EqualError(t, err, "missing issuer")
+
+		_, _, err = issuer.SignKeyless(&authority.KeylessRequest{
+			Profile:   "server",
+			PublicKey: key.Public(),
+			Token:     "token1",
+			Proof:     proof("token1"),
+			Subject:   "denis@ekspand.com",
+			Issuer:    "dev.trusty.com",
+		})
+		assert.EqualError(t, err, "profile does not allow code signing: server")
+
+		_, _, err = issuer.SignKeyless(&authority.KeylessRequest{
+			Profile:   "codesign",
+			PublicKey: key.Public(),
+			Token:     "token1",
+			Proof:     proof("token1"),
+			Subject:   "denis@ekspand.com",
+			Issuer:    "dev.trusty.com",
+		})
+		assert.EqualError(t, err, "profile does not allow keyless signing: codesign")
+
+		_, _, err = issuer.SignKeyless(&authority.KeylessRequest{
+			Profile:   "keyless_long",
+			PublicKey: key.Public(),
+			Token:     "token1",
+			Proof:     proof("token1"),
+			Subject:   "denis@ekspand.com",
+			Issuer:    "dev.trusty.com",
+		})
+		assert.EqualError(t, err, "profile expiry exceeds 1h0m0s: keyless_long")
+
+		other, err := authority.CreateIssuer(&authority.IssuerConfig{
+			Label:    "other",
+			Profiles: profiles,
+		}, rootPEM, nil, nil, signer)
+		require.NoError(t, err)
+		_, _, err = other.SignKeyless(&authority.KeylessRequest{
+			PublicKey: key.Public(),
+			Token:     "token1",
+			Proof:     proof("token1"),
+			Subject:   "denis@ekspand.com",
+			Issuer:    "dev.trusty.com",
+		})
+		assert.EqualError(t, err, "issuer does not support code signing: other")
+	})
+}
+
+func sha256digest(s string) []byte {
+	digest := sha256.Sum256([]byte(s))
+	return digest[:]
+}
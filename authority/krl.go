@@ -0,0 +1,82 @@
+package authority
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// KRL format is specified in PROTOCOL.krl of OpenSSH
+const (
+	krlMagic         = uint64(0x5353484b524c0a00)
+	krlFormatVersion = uint32(1)
+
+	krlSectionCertificates = byte(1)
+	krlSectionSerialList   = byte(0x20)
+)
+
+// MarshalKRL returns OpenSSH Key Revocation List in binary format,
+// that revokes the certificates with the serial numbers issued by the CA key.
+// The KRL can be used with RevokedKeys option of sshd,
+// or checked with ssh-keygen -Q
+func MarshalKRL(caKey ssh.PublicKey, serials []uint64, version uint64, generated time.Time, comment string) []byte {
+	list := make([]uint64, 0, len(serials))
+	for _, sn := range serials {
+		// zero serial can not be revoked
+		if sn != 0 {
+			list = append(list, sn)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+
+	var serialList bytes.Buffer
+	var last uint64
+	for i, sn := range list {
+		if i > 0 && sn == last {
+			continue
+		}
+		putUint64(&serialList, sn)
+		last = sn
+	}
+
+	var certs bytes.Buffer
+	putString(&certs, caKey.Marshal())
+	putString(&certs, nil) // reserved
+	if serialList.Len() > 0 {
+		certs.WriteByte(krlSectionSerialList)
+		putString(&certs, serialList.Bytes())
+	}
+
+	var krl bytes.Buffer
+	putUint64(&krl, krlMagic)
+	putUint32(&krl, krlFormatVersion)
+	putUint64(&krl, version)
+	putUint64(&krl, uint64(generated.Unix()))
+	putUint64(&krl, 0)   // flags
+	putString(&krl, nil) // reserved
+	putString(&krl, []byte(comment))
+	krl.WriteByte(krlSectionCertificates)
+	putString(&krl, certs.Bytes())
+
+	return krl.Bytes()
+}
+
+func putUint32(b *bytes.Buffer, v uint32) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	b.Write(buf[:])
+}
+
+func putUint64(b *bytes.Buffer, v uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	b.Write(buf[:])
+}
+
+func putString(b *bytes.Buffer, s []byte) {
+	putUint32(b, uint32(len(s)))
+	b.Write(s)
+}
@@ -0,0 +1,254 @@
+package authority
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"io/ioutil"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/go-phorce/dolly/algorithms/slices"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// RolloverRequest returns a certificate request for the new key of the issuer,
+// with the same subject and key algorithm as the issuer's certificate
+func RolloverRequest(prov *csr.Provider, crt *x509.Certificate, keyLabel string) (*csr.CertificateRequest, error) {
+	var algo string
+	var size int
+	switch pub := crt.PublicKey.(type) {
+	case *rsa.PublicKey:
+		algo, size = "RSA", pub.N.BitLen()
+	case *ecdsa.PublicKey:
+		algo, size = "ECDSA", pub.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		algo = "ED25519"
+	default:
+		return nil, errors.Errorf("unsupported public key: %T", crt.PublicKey)
+	}
+
+	name := csr.X509Name{
+		SerialNumber: crt.Subject.SerialNumber,
+	}
+	if len(crt.Subject.Country) > 0 {
+		name.C = crt.Subject.Country[0]
+	}
+	if len(crt.Subject.Province) > 0 {
+		name.ST = crt.Subject.Province[0]
+	}
+	if len(crt.Subject.Locality) > 0 {
+		name.L = crt.Subject.Locality[0]
+	}
+	if len(crt.Subject.Organization) > 0 {
+		name.O = crt.Subject.Organization[0]
+	}
+	if len(crt.Subject.OrganizationalUnit) > 0 {
+		name.OU = crt.Subject.OrganizationalUnit[0]
+	}
+
+	req := &csr.CertificateRequest{
+		CommonName: crt.Subject.CommonName,
+		KeyRequest: prov.NewKeyRequest(keyLabel, algo, size, csr.SigningKey),
+	}
+	if name != (csr.X509Name{}) {
+		req.Names = []csr.X509Name{name}
+	}
+	return req, nil
+}
+
+// ImportRollover verifies the certificate signed by the parent CA for the new key,
+// and returns the configuration for the new issuer,
+// based on the configuration of the current issuer
+func ImportRollover(current *IssuerConfig, label, certFile, keyFile string, crypto *cryptoprov.Crypto) (*IssuerConfig, error) {
+	cfg := current.Copy()
+	cfg.Label = label
+	cfg.CertFile = certFile
+	cfg.KeyFile = keyFile
+	cfg.State = ""
+	cfg.RetireAfter = nil
+
+	issuer, err := NewIssuer(cfg, crypto)
+	if err != nil {
+		return nil, errors.Annotate(err, "unable to create new issuer")
+	}
+
+	crt := issuer.Bundle().Cert
+	if !crt.IsCA {
+		return nil, errors.New("certificate is not CA")
+	}
+
+	pub, err := x509.MarshalPKIXPublicKey(issuer.Signer().Public())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !bytes.Equal(pub, crt.RawSubjectPublicKeyInfo) {
+		return nil, errors.New("certificate does not match the key")
+	}
+
+	if currentCert, err := certutil.LoadFromPEM(current.CertFile); err == nil {
+		if bytes.Equal(currentCert.RawSubjectPublicKeyInfo, crt.RawSubjectPublicKeyInfo) {
+			return nil, errors.New("certificate has the same key as the current issuer")
+		}
+		if !bytes.Equal(currentCert.RawSubject, crt.RawSubject) {
+			logger.Warningf("reason=subject_changed, current=%q, new=%q",
+				currentCert.Subject.String(), crt.Subject.String())
+		}
+	}
+
+	return cfg, nil
+}
+
+// RolloverConfig updates the CA configuration file:
+// the current issuer is marked as retiring until retireAfter,
+// the new issuer is added after the current one,
+// and the profiles served by the current issuer are moved to the new issuer.
+// Only YAML format is supported.
+func RolloverConfig(body []byte, current string, issuer *IssuerConfig, retireAfter time.Time) ([]byte, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(body, &doc); err != nil {
+		return nil, errors.Annotate(err, "failed to unmarshal configuration")
+	}
+
+	authority, ok := mapValue(doc, "authority").(yaml.MapSlice)
+	if !ok {
+		return nil, errors.New("missing authority configuration")
+	}
+	issuers, _ := mapValue(authority, "issuers").([]interface{})
+
+	var updated []interface{}
+	found := false
+	for _, item := range issuers {
+		iss, ok := item.(yaml.MapSlice)
+		if !ok {
+			return nil, errors.New("invalid issuer configuration")
+		}
+		label, _ := mapValue(iss, "label").(string)
+		if label == issuer.Label {
+			return nil, errors.Errorf("issuer already exists: %s", issuer.Label)
+		}
+		if label != current {
+			updated = append(updated, iss)
+			continue
+		}
+		found = true
+
+		next := make(yaml.MapSlice, 0, len(iss))
+		for _, kv := range iss {
+			switch kv.Key {
+			case "label":
+				kv.Value = issuer.Label
+			case "cert":
+				kv.Value = issuer.CertFile
+			case "key":
+				kv.Value = issuer.KeyFile
+			case "state", "retire_after":
+				continue
+			}
+			next = append(next, kv)
+		}
+
+		iss = setMapValue(iss, "state", IssuerStateRetiring)
+		iss = setMapValue(iss, "retire_after", retireAfter.UTC())
+		updated = append(updated, iss, next)
+	}
+	if !found {
+		return nil, errors.Errorf("issuer not found: %s", current)
+	}
+	authority = setMapValue(authority, "issuers", updated)
+	doc = setMapValue(doc, "authority", authority)
+
+	if profiles, ok := mapValue(doc, "profiles").(yaml.MapSlice); ok {
+		for i, kv := range profiles {
+			profile, ok := kv.Value.(yaml.MapSlice)
+			if !ok {
+				continue
+			}
+			profiles[i].Value = rolloverProfile(profile, current, issuer.Label, len(issuers) == 1)
+		}
+	}
+
+	return yaml.Marshal(doc)
+}
+
+// RolloverExpiry returns the time when all certificates
+// issued by the issuer with the current profiles expire.
+// The time is computed as now plus the longest expiry of the profiles,
+// not from the certificates actually issued, as the tool has no access
+// to the certificates DB. It's an upper bound: the last certificate
+// issued by the retiring issuer may expire earlier,
+// in this case retire_after can be updated in CA configuration.
+func RolloverExpiry(cfg *IssuerConfig) time.Time {
+	var max time.Duration
+	for _, profile := range cfg.Profiles {
+		if d := profile.Expiry.TimeDuration(); d > max {
+			max = d
+		}
+	}
+	return time.Now().Add(max).Truncate(time.Minute)
+}
+
+// LoadRolloverConfig loads the CA configuration file
+// and returns the configuration of the issuer
+func LoadRolloverConfig(file, label string) ([]byte, *IssuerConfig, error) {
+	body, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "unable to read configuration file")
+	}
+	cfg, err := LoadConfig(file)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if cfg.Authority != nil {
+		for i := range cfg.Authority.Issuers {
+			if cfg.Authority.Issuers[i].Label == label {
+				return body, &cfg.Authority.Issuers[i], nil
+			}
+		}
+	}
+	return nil, nil, errors.Errorf("issuer not found: %s", label)
+}
+
+func rolloverProfile(profile yaml.MapSlice, current, label string, single bool) yaml.MapSlice {
+	issuerLabel, _ := mapValue(profile, "issuer_label").(string)
+	labels, _ := mapValue(profile, "issuer_labels").([]interface{})
+
+	if issuerLabel == current || (issuerLabel == "" && len(labels) == 0 && single) {
+		return setMapValue(profile, "issuer_label", label)
+	}
+
+	var list []string
+	for _, l := range labels {
+		if s, ok := l.(string); ok {
+			list = append(list, s)
+		}
+	}
+	if slices.ContainsString(list, current) && !slices.ContainsString(list, label) {
+		return setMapValue(profile, "issuer_labels", append(labels, label))
+	}
+	return profile
+}
+
+func mapValue(m yaml.MapSlice, key string) interface{} {
+	for _, kv := range m {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return nil
+}
+
+func setMapValue(m yaml.MapSlice, key string, value interface{}) yaml.MapSlice {
+	for i, kv := range m {
+		if kv.Key == key {
+			m[i].Value = value
+			return m
+		}
+	}
+	return append(m, yaml.MapItem{Key: key, Value: value})
+}
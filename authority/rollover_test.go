@@ -0,0 +1,253 @@
+package authority_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/inmemcrypto"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollover(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	crypto, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+	rootReq := csr.CertificateRequest{
+		CommonName: "[TEST] Trusty Root CA",
+		KeyRequest: prov.NewKeyRequest("TestRollover", "ECDSA", 256, csr.SigningKey),
+	}
+	rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &rootReq)
+	require.NoError(t, err)
+
+	rootSigner, err := authority.NewSignerFromPEM(crypto, rootKey)
+	require.NoError(t, err)
+
+	rootIssuer, err := authority.CreateIssuer(&authority.IssuerConfig{
+		Label: "ROOT",
+		Profiles: map[string]*authority.CertProfile{
+			"L1_CA": {
+				Usage:        []string{"cert sign", "crl sign"},
+				CAConstraint: authority.CAConstraint{IsCA: true},
+				Expiry:       csr.OneYear,
+			},
+		},
+	}, rootPEM, nil, nil, rootSigner)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "rollover")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rootFile := filepath.Join(dir, "root.pem")
+	require.NoError(t, ioutil.WriteFile(rootFile, rootPEM, 0644))
+
+	signL1 := func(req *csr.CertificateRequest, name string) {
+		csrPEM, key, _, _, err := prov.CreateRequestAndExportKey(req)
+		require.NoError(t, err)
+
+		_, certPEM, err := rootIssuer.Sign(csr.SignRequest{
+			Request: string(csrPEM),
+			Profile: "L1_CA",
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+".pem"), certPEM, 0644))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+"-key.pem"), key, 0600))
+	}
+
+	signL1(&csr.CertificateRequest{
+		CommonName: "[TEST] Trusty Level 1 CA",
+		Names:      []csr.X509Name{{O: "trusty.com", C: "US"}},
+		KeyRequest: prov.NewKeyRequest("TestRollover", "ECDSA", 256, csr.SigningKey),
+	}, "L1")
+
+	cfgFile := filepath.Join(dir, "ca-config.yaml")
+	cfgYAML := fmt.Sprintf(`
+authority:
+  default_aia:
+    crl_url: http://localhost/v1/crl/${ISSUER_ID}.crl
+  issuers:
+  - label: L1
+    type: tls
+    cert: %[1]s/L1.pem
+    key: %[1]s/L1-key.pem
+    root_bundle: %[1]s/root.pem
+profiles:
+  default:
+    expiry: 24h
+    usages:
+    - signing
+  server:
+    issuer_label: L1
+    expiry: 168h
+    usages:
+    - signing
+  client:
+    issuer_labels:
+    - L1
+    expiry: 24h
+    usages:
+    - signing
+`, dir)
+	require.NoError(t, ioutil.WriteFile(cfgFile, []byte(cfgYAML), 0644))
+
+	body, current, err := authority.LoadRolloverConfig(cfgFile, "L1")
+	require.NoError(t, err)
+	assert.Equal(t, "L1", current.Label)
+
+	_, _, err = authority.LoadRolloverConfig(cfgFile, "L2")
+	assert.EqualError(t, err, "issuer not found: L2")
+
+	// prepare
+	crt, err := certutil.LoadFromPEM(current.CertFile)
+	require.NoError(t, err)
+
+	req, err := authority.RolloverRequest(prov, crt, "TestRollover")
+	require.NoError(t, err)
+	assert.Equal(t, crt.Subject.CommonName, req.CommonName)
+	require.Len(t, req.Names, 1)
+	assert.Equal(t, "trusty.com", req.Names[0].O)
+	assert.Equal(t, "US", req.Names[0].C)
+	assert.Equal(t, "ECDSA", req.KeyRequest.Algo())
+	assert.Equal(t, 256, req.KeyRequest.Size())
+
+	signL1(req, "L1-2")
+
+	// import
+	_, err = authority.ImportRollover(current, "L1-2", filepath.Join(dir, "L1-2.pem"), filepath.Join(dir, "L1-key.pem"), crypto)
+	assert.Error(t, err)
+
+	_, err = authority.ImportRollover(current, "L1-2", current.CertFile, current.KeyFile, crypto)
+	assert.EqualError(t, err, "certificate has the same key as the current issuer")
+
+	isscfg, err := authority.ImportRollover(current, "L1-2", filepath.Join(dir, "L1-2.pem"), filepath.Join(dir, "L1-2-key.pem"), crypto)
+	require.NoError(t, err)
+	assert.Equal(t, "L1-2", isscfg.Label)
+	assert.Empty(t, isscfg.State)
+
+	retireAfter := authority.RolloverExpiry(current)
+	assert.True(t, retireAfter.After(time.Now().Add(167*time.Hour)))
+
+	_, err = authority.RolloverConfig(body, "L2", isscfg, retireAfter)
+	assert.EqualError(t, err, "issuer not found: L2")
+
+	updated, err := authority.RolloverConfig(body, current.Label, isscfg, retireAfter)
+	require.NoError(t, err)
+
+	_, err = authority.RolloverConfig(updated, current.Label, isscfg, retireAfter)
+	assert.EqualError(t, err, "issuer already exists: L1-2")
+
+	require.NoError(t, ioutil.WriteFile(cfgFile, updated, 0644))
+	cfg, err := authority.LoadConfig(cfgFile)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+	require.Len(t, cfg.Authority.Issuers, 2)
+
+	old := cfg.Authority.Issuers[0]
+	assert.Equal(t, "L1", old.Label)
+	assert.Equal(t, authority.IssuerStateRetiring, old.State)
+	require.NotNil(t, old.RetireAfter)
+	assert.Equal(t, retireAfter.Unix(), old.RetireAfter.Unix())
+
+	next := cfg.Authority.Issuers[1]
+	assert.Equal(t, "L1-2", next.Label)
+	assert.Empty(t, next.State)
+	assert.Nil(t, next.RetireAfter)
+	assert.Equal(t, "tls", next.Type)
+
+	assert.Equal(t, "L1-2", cfg.Profiles["default"].IssuerLabel)
+	assert.Equal(t, "L1-2", cfg.Profiles["server"].IssuerLabel)
+	assert.Equal(t, []string{"L1", "L1-2"}, cfg.Profiles["client"].IssuerLabels)
+
+	ca, err := authority.NewAuthority(cfg, crypto)
+	require.NoError(t, err)
+	assert.Len(t, ca.Issuers(), 2)
+
+	issuer, err := ca.GetIssuerByLabel("L1")
+	require.NoError(t, err)
+	assert.Equal(t, authority.IssuerStateRetiring, issuer.State())
+
+	for _, profile := range []string{"default", "server", "client"} {
+		issuer, err = ca.GetIssuerByProfile(profile)
+		require.NoError(t, err)
+		assert.Equal(t, "L1-2", issuer.Label(), profile)
+		assert.Equal(t, authority.IssuerStateActive, issuer.State())
+	}
+
+	csrPEM, _, _, _, err := prov.CreateRequestAndExportKey(&csr.CertificateRequest{
+		CommonName: "trusty.com",
+		KeyRequest: prov.NewKeyRequest("TestRollover", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+
+	issuer, err = ca.GetIssuerByLabel("L1")
+	require.NoError(t, err)
+	_, _, err = issuer.Sign(csr.SignRequest{
+		Request: string(csrPEM),
+		Profile: "client",
+	})
+	assert.EqualError(t, err, "issuer is retiring: L1")
+}
+
+func TestIssuerState(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tcases := []struct {
+		state       string
+		retireAfter *time.Time
+		exp         string
+	}{
+		{"", nil, authority.IssuerStateActive},
+		{authority.IssuerStateActive, &past, authority.IssuerStateActive},
+		{authority.IssuerStateRetiring, nil, authority.IssuerStateRetiring},
+		{authority.IssuerStateRetiring, &future, authority.IssuerStateRetiring},
+		{authority.IssuerStateRetiring, &past, authority.IssuerStateRetired},
+		{authority.IssuerStateRetired, &future, authority.IssuerStateRetired},
+	}
+
+	defprov := inmemcrypto.NewProvider()
+	crypto, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+	rootReq := csr.CertificateRequest{
+		CommonName: "[TEST] Trusty Root CA",
+		KeyRequest: prov.NewKeyRequest("TestIssuerState", "ECDSA", 256, csr.SigningKey),
+	}
+	rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &rootReq)
+	require.NoError(t, err)
+
+	rootSigner, err := authority.NewSignerFromPEM(crypto, rootKey)
+	require.NoError(t, err)
+
+	for _, tc := range tcases {
+		issuer, err := authority.CreateIssuer(&authority.IssuerConfig{
+			Label:       "ROOT",
+			State:       tc.state,
+			RetireAfter: tc.retireAfter,
+		}, rootPEM, nil, nil, rootSigner)
+		require.NoError(t, err)
+		assert.Equal(t, tc.exp, issuer.State(), "%s/%v", tc.state, tc.retireAfter)
+	}
+
+	cfg := &authority.Config{
+		Authority: &authority.CAConfig{
+			Issuers: []authority.IssuerConfig{
+				{Label: "L1", State: "expired"},
+			},
+		},
+	}
+	assert.EqualError(t, cfg.Validate(), "unsupported state for L1 issuer: expired")
+}
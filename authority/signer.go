@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"strings"
 
+	"github.com/ekspand/trusty/pkg/csr"
 	"github.com/go-phorce/dolly/xpki/cryptoprov"
 	"github.com/juju/errors"
 )
@@ -27,15 +28,9 @@ func NewSignerFromFromFile(crypto *cryptoprov.Crypto, caKeyFile string) (crypto.
 // NewSignerFromPEM generates a new crypto signer from PEM encoded blocks,
 // or caKey contains PKCS#11 Uri
 func NewSignerFromPEM(prov *cryptoprov.Crypto, caKey []byte) (crypto.Signer, error) {
-	_, pvk, err := prov.LoadPrivateKey(caKey)
+	signer, err := csr.NewSigner(prov, caKey)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-
-	signer, supported := pvk.(crypto.Signer)
-	if !supported {
-		return nil, errors.Errorf("loaded key of %T type does not support crypto.Signer", pvk)
-	}
-
 	return signer, nil
 }
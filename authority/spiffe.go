@@ -0,0 +1,92 @@
+package authority
+
+import (
+	"crypto/x509"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// SpiffeScheme specifies URI scheme of SPIFFE ID
+const SpiffeScheme = "spiffe"
+
+// TrustDomain returns SPIFFE trust domain of the issuer,
+// or empty string if the issuer is not spiffe
+func (ca *Issuer) TrustDomain() string {
+	if ca.cfg.Type != IssuerTypeSpiffe || ca.cfg.SPIFFE == nil {
+		return ""
+	}
+	return ca.cfg.SPIFFE.TrustDomain
+}
+
+// ValidateTrustDomain returns an error if the trust domain name is invalid,
+// it may contain only lowercase letters, numbers, dots, dashes, and underscores.
+func ValidateTrustDomain(td string) error {
+	if td == "" {
+		return errors.New("trust domain is empty")
+	}
+	for _, c := range td {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_') {
+			return errors.Errorf("trust domain contains invalid character: %q", td)
+		}
+	}
+	return nil
+}
+
+// ValidateSpiffeID returns an error if the URI is not a valid SPIFFE ID
+// of a workload in the trust domain
+func ValidateSpiffeID(u *url.URL, trustDomain string) error {
+	if u.Scheme != SpiffeScheme {
+		return errors.Errorf("invalid SPIFFE ID scheme: %s", u.String())
+	}
+	if u.User != nil || u.Port() != "" || u.RawQuery != "" || u.Fragment != "" || u.Opaque != "" {
+		return errors.Errorf("invalid SPIFFE ID: %s", u.String())
+	}
+	if u.Host != trustDomain {
+		return errors.Errorf("SPIFFE ID does not belong to %s trust domain: %s", trustDomain, u.String())
+	}
+	if u.Path == "" || u.Path == "/" {
+		return errors.Errorf("SPIFFE ID must have a path: %s", u.String())
+	}
+	for _, segment := range strings.Split(u.Path[1:], "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return errors.Errorf("invalid SPIFFE ID path: %s", u.String())
+		}
+		for _, c := range segment {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_') {
+				return errors.Errorf("invalid SPIFFE ID path: %s", u.String())
+			}
+		}
+	}
+	return nil
+}
+
+// validateSVID returns an error if the template is not a valid X.509-SVID,
+// it must contain exactly one URI SAN with SPIFFE ID in the trust domain
+func validateSVID(template *x509.Certificate, trustDomain string) error {
+	if len(template.URIs) != 1 {
+		return errors.Errorf("X.509-SVID must have exactly one URI SAN, found %d", len(template.URIs))
+	}
+	return errors.Trace(ValidateSpiffeID(template.URIs[0], trustDomain))
+}
+
+// validateSVIDProfile returns an error if the profile key usages are not
+// allowed for X.509-SVID: the leaf must have digital signature,
+// and must not have cert sign or crl sign, the signing certificate must have cert sign
+func validateSVIDProfile(profile *CertProfile) error {
+	ku, _, _ := profile.Usages()
+	if profile.CAConstraint.IsCA {
+		if ku&x509.KeyUsageCertSign == 0 {
+			return errors.New("SVID signing certificate must have cert sign usage")
+		}
+		return nil
+	}
+	if ku&x509.KeyUsageDigitalSignature == 0 {
+		return errors.New("SVID must have digital signature usage")
+	}
+	if ku&(x509.KeyUsageCertSign|x509.KeyUsageCRLSign) != 0 {
+		return errors.New("SVID must not have cert sign or crl sign usage")
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package authority_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/inmemcrypto"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpiffeID(t *testing.T) {
+	assert.NoError(t, authority.ValidateTrustDomain("trusty.ekspand.com"))
+	assert.EqualError(t, authority.ValidateTrustDomain(""), "trust domain is empty")
+	assert.EqualError(t, authority.ValidateTrustDomain("Trusty.com"), `trust domain contains invalid character: "Trusty.com"`)
+
+	tcases := []struct {
+		uri string
+		err string
+	}{
+		{"spiffe://trusty.ekspand.com/ns/default/sa/workload", ""},
+		{"spifee://trusty.ekspand.com/workload", "invalid SPIFFE ID scheme: spifee://trusty.ekspand.com/workload"},
+		{"spiffe://other.com/workload", "SPIFFE ID does not belong to trusty.ekspand.com trust domain: spiffe://other.com/workload"},
+		{"spiffe://trusty.ekspand.com", "SPIFFE ID must have a path: spiffe://trusty.ekspand.com"},
+		{"spiffe://trusty.ekspand.com/", "SPIFFE ID must have a path: spiffe://trusty.ekspand.com/"},
+		{"spiffe://trusty.ekspand.com/a//b", "invalid SPIFFE ID path: spiffe://trusty.ekspand.com/a//b"},
+		{"spiffe://trusty.ekspand.com/a/../b", "invalid SPIFFE ID path: spiffe://trusty.ekspand.com/a/../b"},
+		{"spiffe://trusty.ekspand.com/a?b=c", "invalid SPIFFE ID: spiffe://trusty.ekspand.com/a?b=c"},
+		{"spiffe://trusty.ekspand.com:8080/a", "invalid SPIFFE ID: spiffe://trusty.ekspand.com:8080/a"},
+	}
+	for _, tc := range tcases {
+		u, err := url.Parse(tc.uri)
+		require.NoError(t, err)
+		err = authority.ValidateSpiffeID(u, "trusty.ekspand.com")
+		if tc.err == "" {
+			assert.NoError(t, err, tc.uri)
+		} else {
+			assert.EqualError(t, err, tc.err)
+		}
+	}
+}
+
+func TestSpiffeIssuer(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	cryptoProv, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+
+	profiles := map[string]*authority.CertProfile{
+		"svid": {
+			Usage:  []string{"digital signature", "key encipherment", "server auth", "client auth"},
+			Expiry: csr.OneYear,
+		},
+	}
+
+	rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &csr.CertificateRequest{
+		CommonName: "[TEST] Trusty SPIFFE CA",
+		KeyRequest: prov.NewKeyRequest("TestSpiffeIssuer", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+	signer, err := authority.NewSignerFromPEM(cryptoProv, rootKey)
+	require.NoError(t, err)
+
+	issuer, err := authority.CreateIssuer(&authority.IssuerConfig{
+		Label:    "spiffe",
+		Type:     authority.IssuerTypeSpiffe,
+		SPIFFE:   &authority.SPIFFEConfig{TrustDomain: "trusty.ekspand.com"},
+		Profiles: profiles,
+	}, rootPEM, nil, nil, signer)
+	require.NoError(t, err)
+	assert.Equal(t, "trusty.ekspand.com", issuer.TrustDomain())
+
+	csrPEM, _, _, err := prov.GenerateKeyAndRequest(&csr.CertificateRequest{
+		KeyRequest: prov.NewKeyRequest("svid", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+
+	crt, _, err := issuer.Sign(csr.SignRequest{
+		SAN:     []string{"spiffe://trusty.ekspand.com/ns/default/sa/workload"},
+		Request: string(csrPEM),
+		Profile: "svid",
+	})
+	require.NoError(t, err)
+	require.Len(t, crt.URIs, 1)
+	assert.Equal(t, "spiffe://trusty.ekspand.com/ns/default/sa/workload", crt.URIs[0].String())
+	assert.False(t, crt.IsCA)
+
+	_, _, err = issuer.Sign(csr.SignRequest{
+		SAN:     []string{"spiffe://other.com/workload"},
+		Request: string(csrPEM),
+		Profile: "svid",
+	})
+	assert.EqualError(t, err, "SPIFFE ID does not belong to trusty.ekspand.com trust domain: spiffe://other.com/workload")
+
+	_, _, err = issuer.Sign(csr.SignRequest{
+		SAN:     []string{"spiffe://trusty.ekspand.com/a", "spiffe://trusty.ekspand.com/b"},
+		Request: string(csrPEM),
+		Profile: "svid",
+	})
+	assert.EqualError(t, err, "X.509-SVID must have exactly one URI SAN, found 2")
+
+	_, _, err = issuer.Sign(csr.SignRequest{
+		SAN:     []string{"workload.trusty.ekspand.com"},
+		Request: string(csrPEM),
+		Profile: "svid",
+	})
+	assert.EqualError(t, err, "X.509-SVID must have exactly one URI SAN, found 0")
+
+	t.Run("config", func(t *testing.T) {
+		cfg := &authority.Config{
+			Authority: &authority.CAConfig{
+				Issuers: []authority.IssuerConfig{
+					{
+						Label:    "spiffe",
+						Type:     authority.IssuerTypeSpiffe,
+						Profiles: profiles,
+					},
+				},
+			},
+			Profiles: profiles,
+		}
+		assert.EqualError(t, cfg.Validate(), "missing SPIFFE trust_domain for spiffe issuer")
+
+		cfg.Authority.Issuers[0].SPIFFE = &authority.SPIFFEConfig{TrustDomain: "Trusty"}
+		err := cfg.Validate()
+		require.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "invalid SPIFFE trust_domain for spiffe issuer"))
+
+		cfg.Authority.Issuers[0].SPIFFE.TrustDomain = "trusty.ekspand.com"
+		assert.NoError(t, cfg.Validate())
+
+		cfg.Authority.Issuers[0].Profiles = map[string]*authority.CertProfile{
+			"svid": {
+				Usage:  []string{"digital signature", "crl sign"},
+				Expiry: csr.OneYear,
+			},
+		}
+		assert.EqualError(t, cfg.Validate(), "invalid svid profile for spiffe issuer: SVID must not have cert sign or crl sign usage")
+
+		cfg.Authority.Issuers[0].Profiles = map[string]*authority.CertProfile{
+			"svid": {
+				Usage:  []string{"key encipherment"},
+				Expiry: csr.OneYear,
+			},
+		}
+		assert.EqualError(t, cfg.Validate(), "invalid svid profile for spiffe issuer: SVID must have digital signature usage")
+	})
+}
@@ -0,0 +1,354 @@
+package authority
+
+import (
+	"crypto"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/juju/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// SSHCertTypeUser specifies OpenSSH user certificate
+	SSHCertTypeUser = "user"
+	// SSHCertTypeHost specifies OpenSSH host certificate
+	SSHCertTypeHost = "host"
+)
+
+// SSHIdentity specifies the identity of the caller,
+// used to expand the allowed principals of SSH profile
+type SSHIdentity struct {
+	// Name specifies the caller's name, usually email
+	Name string
+	// Role specifies the caller's role
+	Role string
+	// Orgs specifies the logins of the caller's organizations
+	Orgs []string
+}
+
+// SSHRequest specifies the request for OpenSSH certificate
+type SSHRequest struct {
+	// Profile specifies the profile of the certificate
+	Profile string
+	// PublicKey specifies the public key to sign
+	PublicKey ssh.PublicKey
+	// KeyID specifies Key ID of the certificate,
+	// if not provided, then the caller's name is used.
+	// The Key ID is logged by sshd, and must be the caller's name,
+	// or start with the caller's name followed by "/"
+	KeyID string
+	// Principals specifies the requested principals,
+	// if not provided, then all allowed principals without wildcards are used
+	Principals []string
+	// Identity specifies the caller
+	Identity SSHIdentity
+}
+
+// Validate returns an error if the profile is invalid
+func (p *SSHProfile) Validate() error {
+	if p.CertType != SSHCertTypeUser && p.CertType != SSHCertTypeHost {
+		return errors.Errorf("unsupported cert_type: %q", p.CertType)
+	}
+	if len(p.Principals) == 0 {
+		return errors.New("no principals specified")
+	}
+	for _, pattern := range p.Principals {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.Errorf("invalid principal: %q", pattern)
+		}
+		if strings.Contains(pattern, "${USER}") && len(p.UserDomains) == 0 {
+			return errors.Errorf("user_domains must be specified for principal: %q", pattern)
+		}
+	}
+	return nil
+}
+
+// globChars specifies the metacharacters of path.Match patterns
+const globChars = `*?[\`
+
+// AllowedPrincipals returns the list of principal patterns,
+// allowed for the identity.
+// The identity values with glob metacharacters are not substituted,
+// as they would widen the patterns.
+func (p *SSHProfile) AllowedPrincipals(idn *SSHIdentity) []string {
+	vars := map[string]string{
+		"${NAME}": idn.Name,
+		"${USER}": p.userName(idn.Name),
+		"${ROLE}": idn.Role,
+	}
+
+	var list []string
+	add := func(principal string) {
+		if principal == "" || strings.Contains(principal, "${") {
+			return
+		}
+		for _, p := range list {
+			if p == principal {
+				return
+			}
+		}
+		list = append(list, principal)
+	}
+
+	for _, pattern := range p.Principals {
+		expanded := pattern
+		for k, v := range vars {
+			if strings.Contains(expanded, k) {
+				if v == "" || strings.ContainsAny(v, globChars) {
+					// the variable is not available for the caller
+					expanded = ""
+					break
+				}
+				expanded = strings.Replace(expanded, k, v, -1)
+			}
+		}
+		if strings.Contains(expanded, "${ORG}") {
+			for _, org := range idn.Orgs {
+				if org != "" && !strings.ContainsAny(org, globChars) {
+					add(strings.Replace(expanded, "${ORG}", org, -1))
+				}
+			}
+			continue
+		}
+		add(expanded)
+	}
+	return list
+}
+
+// userName returns the user part of the email,
+// or empty string if the email domain is not in UserDomains,
+// the users of different domains must not map to the same principal
+func (p *SSHProfile) userName(email string) string {
+	i := strings.LastIndex(email, "@")
+	if i <= 0 {
+		return ""
+	}
+	domain := email[i+1:]
+	for _, d := range p.UserDomains {
+		if strings.EqualFold(d, domain) {
+			return email[:i]
+		}
+	}
+	return ""
+}
+
+// IsPrincipalAllowed returns true, if the principal matches
+// one of the allowed patterns
+func IsPrincipalAllowed(allowed []string, principal string) bool {
+	if principal == "" {
+		return false
+	}
+	for _, pattern := range allowed {
+		if matched, _ := path.Match(pattern, principal); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSSHSigner sets the key to sign OpenSSH certificates
+func (ca *Issuer) SetSSHSigner(signer crypto.Signer) error {
+	s, err := ssh.NewSignerFromSigner(signer)
+	if err != nil {
+		return errors.Annotate(err, "unsupported SSH CA key")
+	}
+	if as, ok := s.(ssh.AlgorithmSigner); ok && s.PublicKey().Type() == ssh.KeyAlgoRSA {
+		// SHA-1 signatures are rejected by the recent OpenSSH versions
+		s = &sshRSASigner{AlgorithmSigner: as}
+	}
+	ca.sshSigner = s
+	return nil
+}
+
+// SSHPublicKey returns SSH CA public key,
+// or nil if the issuer does not support SSH certificates
+func (ca *Issuer) SSHPublicKey() ssh.PublicKey {
+	if ca.sshSigner == nil {
+		return nil
+	}
+	return ca.sshSigner.PublicKey()
+}
+
+// SSHAuthorizedKey returns SSH CA public key in authorized_keys format,
+// or empty string if the issuer does not support SSH certificates
+func (ca *Issuer) SSHAuthorizedKey() string {
+	if ca.sshSigner == nil {
+		return ""
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(ca.sshSigner.PublicKey())))
+}
+
+// SSHKeyID returns SHA256 fingerprint of SSH CA key,
+// or empty string if the issuer does not support SSH certificates
+func (ca *Issuer) SSHKeyID() string {
+	if ca.sshSigner == nil {
+		return ""
+	}
+	return strings.TrimPrefix(ssh.FingerprintSHA256(ca.sshSigner.PublicKey()), "SHA256:")
+}
+
+// SignSSH returns OpenSSH certificate for the public key,
+// with the principals allowed by the profile for the caller
+func (ca *Issuer) SignSSH(req *SSHRequest) (*ssh.Certificate, error) {
+	if ca.sshSigner == nil {
+		return nil, errors.Errorf("issuer does not support SSH certificates: %s", ca.label)
+	}
+	if state := ca.State(); state != IssuerStateActive {
+		return nil, errors.Errorf("issuer is %s: %s", state, ca.label)
+	}
+
+	profile := ca.cfg.Profiles[req.Profile]
+	if profile == nil || profile.SSH == nil {
+		return nil, errors.New("unsupported profile: " + req.Profile)
+	}
+	if req.PublicKey == nil {
+		return nil, errors.New("missing public key")
+	}
+	if _, ok := req.PublicKey.(*ssh.Certificate); ok {
+		return nil, errors.New("public key must not be a certificate")
+	}
+
+	cpk, ok := req.PublicKey.(ssh.CryptoPublicKey)
+	if !ok {
+		return nil, errors.Errorf("unsupported key type: %s", req.PublicKey.Type())
+	}
+	err := profile.GetKeyPolicy().Check(cpk.CryptoPublicKey())
+	if err != nil {
+		return nil, errors.Annotate(err, "key policy")
+	}
+	err = ca.weakKeys.Check(cpk.CryptoPublicKey())
+	if err != nil {
+		return nil, errors.Annotate(err, "weak key")
+	}
+
+	allowed := profile.SSH.AllowedPrincipals(&req.Identity)
+	principals := req.Principals
+	if len(principals) == 0 {
+		for _, p := range allowed {
+			if !strings.ContainsAny(p, globChars) {
+				principals = append(principals, p)
+			}
+		}
+		if len(principals) == 0 {
+			return nil, errors.Errorf("no principals allowed for %s", req.Identity.Name)
+		}
+	}
+	for _, p := range principals {
+		if !IsPrincipalAllowed(allowed, p) {
+			return nil, errors.Errorf("principal is not allowed: %q", p)
+		}
+	}
+
+	keyID, err := sshKeyID(req.KeyID, req.Identity.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	serial, err := randomSerial(rand.Reader)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	certType := uint32(ssh.UserCert)
+	if profile.SSH.CertType == SSHCertTypeHost {
+		certType = ssh.HostCert
+	}
+
+	now := time.Now().UTC()
+	cert := &ssh.Certificate{
+		Key:             req.PublicKey,
+		Serial:          serial,
+		CertType:        certType,
+		KeyId:           keyID,
+		ValidPrincipals: principals,
+		ValidAfter:      uint64(now.Add(-profile.Backdate.TimeDuration()).Unix()),
+		ValidBefore:     uint64(now.Add(profile.Expiry.TimeDuration()).Unix()),
+		Permissions: ssh.Permissions{
+			CriticalOptions: copyStringMap(profile.SSH.CriticalOptions),
+			Extensions:      copyStringMap(profile.SSH.Extensions),
+		},
+	}
+
+	err = cert.SignCert(rand.Reader, ca.sshSigner)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to sign SSH certificate")
+	}
+
+	logger.Infof("issuer=%s, serial=%d, key_id=%q, principals=%v",
+		ca.label, cert.Serial, cert.KeyId, cert.ValidPrincipals)
+
+	return cert, nil
+}
+
+// CreateKRL returns OpenSSH Key Revocation List,
+// that revokes the certificates issued by the issuer with the serial numbers
+func (ca *Issuer) CreateKRL(serials []string, version uint64, generated time.Time) ([]byte, error) {
+	if ca.sshSigner == nil {
+		return nil, errors.Errorf("issuer does not support SSH certificates: %s", ca.label)
+	}
+
+	list := make([]uint64, 0, len(serials))
+	for _, s := range serials {
+		sn, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid serial number: %q", s)
+		}
+		list = append(list, sn)
+	}
+
+	return MarshalKRL(ca.sshSigner.PublicKey(), list, version, generated, ca.label), nil
+}
+
+// sshRSASigner signs with rsa-sha2-512 algorithm
+type sshRSASigner struct {
+	ssh.AlgorithmSigner
+}
+
+// Sign returns a signature with rsa-sha2-512 algorithm
+func (s *sshRSASigner) Sign(rand io.Reader, data []byte) (*ssh.Signature, error) {
+	return s.AlgorithmSigner.SignWithAlgorithm(rand, data, ssh.SigAlgoRSASHA2512)
+}
+
+// sshKeyID returns Key ID of the certificate, bound to the caller's name
+func sshKeyID(keyID, name string) (string, error) {
+	if name == "" {
+		return "", errors.New("missing caller name")
+	}
+	if keyID == "" {
+		return name, nil
+	}
+	if strings.IndexFunc(keyID, func(r rune) bool { return !unicode.IsPrint(r) }) >= 0 {
+		return "", errors.Errorf("invalid key_id: %q", keyID)
+	}
+	if keyID != name && !strings.HasPrefix(keyID, name+"/") {
+		return "", errors.Errorf("key_id must start with the caller's name: %q", keyID)
+	}
+	return keyID, nil
+}
+
+func randomSerial(r io.Reader) (uint64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, errors.Trace(err)
+	}
+	// keep the serial positive for DB and tools that use signed integers
+	return binary.BigEndian.Uint64(b[:]) >> 1, nil
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
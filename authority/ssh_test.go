@@ -0,0 +1,334 @@
+package authority_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/inmemcrypto"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSSHPrincipals(t *testing.T) {
+	p := &authority.SSHProfile{
+		CertType: authority.SSHCertTypeUser,
+		Principals: []string{
+			"${USER}",
+			"${NAME}",
+			"${ORG}-admin",
+			"role-${ROLE}",
+			"ops",
+			"*.trusty.local",
+		},
+		UserDomains: []string{"ekspand.com"},
+	}
+	require.NoError(t, p.Validate())
+
+	allowed := p.AllowedPrincipals(&authority.SSHIdentity{
+		Name: "denis@ekspand.com",
+		Role: "trusty-admin",
+		Orgs: []string{"ekspand", "go-phorce"},
+	})
+	assert.Equal(t, []string{
+		"denis",
+		"denis@ekspand.com",
+		"ekspand-admin",
+		"go-phorce-admin",
+		"role-trusty-admin",
+		"ops",
+		"*.trusty.local",
+	}, allowed)
+
+	allowed = p.AllowedPrincipals(&authority.SSHIdentity{Name: "guest"})
+	assert.Equal(t, []string{"guest", "ops", "*.trusty.local"}, allowed)
+
+	assert.True(t, authority.IsPrincipalAllowed(allowed, "ops"))
+	assert.True(t, authority.IsPrincipalAllowed(allowed, "web.trusty.local"))
+	assert.False(t, authority.IsPrincipalAllowed(allowed, "web.trusty.com"))
+	assert.False(t, authority.IsPrincipalAllowed(allowed, "root"))
+	assert.False(t, authority.IsPrincipalAllowed(allowed, ""))
+
+	// ${USER} is substituted only for the allowed domains
+	allowed = p.AllowedPrincipals(&authority.SSHIdentity{Name: "root@trusty.com"})
+	assert.Equal(t, []string{"root@trusty.com", "ops", "*.trusty.local"}, allowed)
+	assert.False(t, authority.IsPrincipalAllowed(allowed, "root"))
+
+	allowed = p.AllowedPrincipals(&authority.SSHIdentity{Name: "denis@EKSPAND.com"})
+	assert.True(t, authority.IsPrincipalAllowed(allowed, "denis"))
+
+	// the values with glob metacharacters are not substituted
+	allowed = p.AllowedPrincipals(&authority.SSHIdentity{
+		Name: "*@ekspand.com",
+		Role: "admin?",
+		Orgs: []string{"[a-z]*", "ekspand", `\*`},
+	})
+	assert.Equal(t, []string{"ekspand-admin", "ops", "*.trusty.local"}, allowed)
+	assert.False(t, authority.IsPrincipalAllowed(allowed, "denis"))
+
+	tcases := []struct {
+		p   *authority.SSHProfile
+		err string
+	}{
+		{&authority.SSHProfile{CertType: "any", Principals: []string{"ops"}}, `unsupported cert_type: "any"`},
+		{&authority.SSHProfile{CertType: authority.SSHCertTypeHost}, "no principals specified"},
+		{&authority.SSHProfile{CertType: authority.SSHCertTypeHost, Principals: []string{"[a"}}, `invalid principal: "[a"`},
+		{&authority.SSHProfile{CertType: authority.SSHCertTypeUser, Principals: []string{"${USER}-admin"}}, `user_domains must be specified for principal: "${USER}-admin"`},
+	}
+	for _, tc := range tcases {
+		assert.EqualError(t, tc.p.Validate(), tc.err)
+	}
+}
+
+func TestSSHIssuer(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	cryptoProv, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+
+	profiles := map[string]*authority.CertProfile{
+		"ssh_user": {
+			Expiry:   csr.Duration(8 * time.Hour),
+			Backdate: csr.Duration(time.Minute),
+			SSH: &authority.SSHProfile{
+				CertType:    authority.SSHCertTypeUser,
+				Principals:  []string{"${USER}", "${ORG}"},
+				UserDomains: []string{"ekspand.com"},
+				Extensions: map[string]string{
+					"permit-pty": "",
+				},
+			},
+		},
+		"ssh_host": {
+			Expiry: csr.Duration(24 * time.Hour),
+			SSH: &authority.SSHProfile{
+				CertType:   authority.SSHCertTypeHost,
+				Principals: []string{"*.trusty.local"},
+			},
+		},
+	}
+	for _, p := range profiles {
+		require.NoError(t, p.Validate())
+	}
+
+	rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &csr.CertificateRequest{
+		CommonName: "[TEST] Trusty SSH CA",
+		KeyRequest: prov.NewKeyRequest("TestSSHIssuer", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+	signer, err := authority.NewSignerFromPEM(cryptoProv, rootKey)
+	require.NoError(t, err)
+
+	issuer, err := authority.CreateIssuer(&authority.IssuerConfig{
+		Label:    "ssh",
+		Type:     authority.IssuerTypeSSH,
+		Profiles: profiles,
+	}, rootPEM, nil, nil, signer)
+	require.NoError(t, err)
+	require.NotNil(t, issuer.SSHPublicKey())
+	assert.Contains(t, issuer.SSHAuthorizedKey(), "ecdsa-sha2-nistp256 ")
+	assert.NotEmpty(t, issuer.SSHKeyID())
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+
+	idn := authority.SSHIdentity{
+		Name: "denis@ekspand.com",
+		Role: "trusty-admin",
+		Orgs: []string{"ekspand"},
+	}
+
+	cert, err := issuer.SignSSH(&authority.SSHRequest{
+		Profile:   "ssh_user",
+		PublicKey: sshPub,
+		Identity:  idn,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(ssh.UserCert), cert.CertType)
+	assert.Equal(t, "denis@ekspand.com", cert.KeyId)
+	assert.Equal(t, []string{"denis", "ekspand"}, cert.ValidPrincipals)
+	assert.Equal(t, map[string]string{"permit-pty": ""}, cert.Extensions)
+	assert.NotZero(t, cert.Serial)
+
+	checker := &ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool {
+			return string(auth.Marshal()) == string(issuer.SSHPublicKey().Marshal())
+		},
+	}
+	require.NoError(t, checker.CheckCert("denis", cert))
+	assert.Error(t, checker.CheckCert("root", cert))
+
+	cert, err = issuer.SignSSH(&authority.SSHRequest{
+		Profile:    "ssh_user",
+		PublicKey:  sshPub,
+		KeyID:      "denis@ekspand.com/key1",
+		Principals: []string{"ekspand"},
+		Identity:   idn,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "denis@ekspand.com/key1", cert.KeyId)
+	assert.Equal(t, []string{"ekspand"}, cert.ValidPrincipals)
+
+	host, err := issuer.SignSSH(&authority.SSHRequest{
+		Profile:    "ssh_host",
+		PublicKey:  sshPub,
+		Principals: []string{"web.trusty.local"},
+		Identity:   idn,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(ssh.HostCert), host.CertType)
+	assert.Nil(t, host.Extensions)
+
+	_, err = issuer.SignSSH(&authority.SSHRequest{
+		Profile:   "ssh_user",
+		PublicKey: sshPub,
+		KeyID:     "root@ekspand.com",
+		Identity:  idn,
+	})
+	assert.EqualError(t, err, `key_id must start with the caller's name: "root@ekspand.com"`)
+
+	_, err = issuer.SignSSH(&authority.SSHRequest{
+		Profile:   "ssh_user",
+		PublicKey: sshPub,
+		KeyID:     "denis@ekspand.com/key1\nroot",
+		Identity:  idn,
+	})
+	assert.EqualError(t, err, `invalid key_id: "denis@ekspand.com/key1\nroot"`)
+
+	_, err = issuer.SignSSH(&authority.SSHRequest{
+		Profile:    "ssh_user",
+		PublicKey:  sshPub,
+		Principals: []string{"root"},
+		Identity:   idn,
+	})
+	assert.EqualError(t, err, `principal is not allowed: "root"`)
+
+	_, err = issuer.SignSSH(&authority.SSHRequest{
+		Profile:   "ssh_host",
+		PublicKey: sshPub,
+		Identity:  idn,
+	})
+	assert.EqualError(t, err, "no principals allowed for denis@ekspand.com")
+
+	_, err = issuer.SignSSH(&authority.SSHRequest{
+		Profile:   "ssh_user",
+		PublicKey: cert,
+		Identity:  idn,
+	})
+	assert.EqualError(t, err, "public key must not be a certificate")
+
+	_, err = issuer.SignSSH(&authority.SSHRequest{
+		Profile:   "default",
+		PublicKey: sshPub,
+		Identity:  idn,
+	})
+	assert.EqualError(t, err, "unsupported profile: default")
+
+	_, _, err = issuer.Sign(csr.SignRequest{Profile: "ssh_user"})
+	assert.EqualError(t, err, "unsupported profile: ssh_user")
+
+	t.Run("krl", func(t *testing.T) {
+		krl, err := issuer.CreateKRL([]string{strconv.FormatUint(cert.Serial, 10), "0"}, 1, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, "SSHKRL\n\x00", string(krl[:8]))
+
+		_, err = issuer.CreateKRL([]string{"abc"}, 1, time.Now())
+		assert.EqualError(t, err, `invalid serial number: "abc"`)
+
+		if _, err := exec.LookPath("ssh-keygen"); err != nil {
+			t.Skip("ssh-keygen is not available")
+		}
+
+		dir, err := ioutil.TempDir("", "krl")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		krlFile := filepath.Join(dir, "krl")
+		revokedFile := filepath.Join(dir, "revoked-cert.pub")
+		validFile := filepath.Join(dir, "valid-cert.pub")
+		require.NoError(t, ioutil.WriteFile(krlFile, krl, 0644))
+		require.NoError(t, ioutil.WriteFile(revokedFile, ssh.MarshalAuthorizedKey(cert), 0644))
+		require.NoError(t, ioutil.WriteFile(validFile, ssh.MarshalAuthorizedKey(host), 0644))
+
+		out, err := exec.Command("ssh-keygen", "-Q", "-f", krlFile, revokedFile).CombinedOutput()
+		assert.Error(t, err, string(out))
+		assert.Contains(t, string(out), "REVOKED")
+
+		out, err = exec.Command("ssh-keygen", "-Q", "-f", krlFile, validFile).CombinedOutput()
+		assert.NoError(t, err, string(out))
+	})
+
+	t.Run("config", func(t *testing.T) {
+		cfg := &authority.Config{
+			Authority: &authority.CAConfig{
+				Issuers: []authority.IssuerConfig{
+					{
+						Label: "ssh",
+						Type:  authority.IssuerTypeSSH,
+						Profiles: map[string]*authority.CertProfile{
+							"server": authority.DefaultCertProfile(),
+						},
+					},
+				},
+			},
+		}
+		assert.EqualError(t, cfg.Validate(), "invalid server profile for ssh issuer: missing ssh")
+	})
+}
+
+func TestSSHIssuerRSA(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	cryptoProv, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+	rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &csr.CertificateRequest{
+		CommonName: "[TEST] Trusty SSH RSA CA",
+		KeyRequest: prov.NewKeyRequest("TestSSHIssuerRSA", "RSA", 2048, csr.SigningKey),
+	})
+	require.NoError(t, err)
+	signer, err := authority.NewSignerFromPEM(cryptoProv, rootKey)
+	require.NoError(t, err)
+
+	issuer, err := authority.CreateIssuer(&authority.IssuerConfig{
+		Label: "ssh",
+		Type:  authority.IssuerTypeSSH,
+		Profiles: map[string]*authority.CertProfile{
+			"ssh_user": {
+				Expiry: csr.Duration(time.Hour),
+				SSH: &authority.SSHProfile{
+					CertType:    authority.SSHCertTypeUser,
+					Principals:  []string{"${USER}"},
+					UserDomains: []string{"ekspand.com"},
+				},
+			},
+		},
+	}, rootPEM, nil, nil, signer)
+	require.NoError(t, err)
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+
+	cert, err := issuer.SignSSH(&authority.SSHRequest{
+		Profile:   "ssh_user",
+		PublicKey: sshPub,
+		Identity:  authority.SSHIdentity{Name: "denis@ekspand.com"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ssh.SigAlgoRSASHA2512, cert.Signature.Format)
+}
@@ -0,0 +1,71 @@
+package authority
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/tsa"
+	"github.com/juju/errors"
+)
+
+// Timestamp signs RFC 3161 time-stamp token for the request,
+// and returns TSTInfo and DER encoded TimeStampToken.
+// The returned errors of *tsa.Error type specify the failure info
+// to be returned to the client.
+func (ca *Issuer) Timestamp(req *tsa.Request) (*tsa.TSTInfo, []byte, error) {
+	if ca.cfg.Type != IssuerTypeTimestamp || ca.cfg.TSA == nil {
+		return nil, nil, errors.Errorf("issuer does not support timestamp: %s", ca.label)
+	}
+	if state := ca.State(); state != IssuerStateActive {
+		return nil, nil, errors.Errorf("issuer is %s: %s", state, ca.label)
+	}
+
+	crt := ca.bundle.Cert
+	if len(crt.ExtKeyUsage) != 1 || crt.ExtKeyUsage[0] != x509.ExtKeyUsageTimeStamping {
+		return nil, nil, errors.Errorf("issuer certificate does not allow timestamping: %s", ca.label)
+	}
+
+	policy := asn1.ObjectIdentifier(ca.cfg.TSA.Policy)
+	if len(req.ReqPolicy) > 0 && !req.ReqPolicy.Equal(policy) {
+		return nil, nil, tsa.NewError(tsa.UnacceptedPolicy, "unsupported policy: %s", req.ReqPolicy.String())
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	now := time.Now().UTC()
+	if now.After(crt.NotAfter) || now.Before(crt.NotBefore) {
+		return nil, nil, tsa.NewError(tsa.TimeNotAvailable, "issuer certificate is not valid at %s", now.Format(time.RFC3339))
+	}
+
+	info := &tsa.TSTInfo{
+		Version:        1,
+		Policy:         policy,
+		MessageImprint: req.MessageImprint,
+		SerialNumber:   serial,
+		GenTime:        now,
+		Accuracy:       tsa.NewAccuracy(ca.cfg.TSA.Accuracy),
+		Ordering:       ca.cfg.TSA.Ordering,
+		Nonce:          req.Nonce,
+	}
+
+	var certs []*x509.Certificate
+	if req.CertReq {
+		certs = append(certs, crt)
+		for _, c := range ca.bundle.Chain {
+			if !c.Equal(crt) {
+				certs = append(certs, c)
+			}
+		}
+	}
+
+	token, err := tsa.SignToken(info, ca.signer, crt, certs)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to sign timestamp token")
+	}
+
+	return info, token, nil
+}
@@ -0,0 +1,135 @@
+package authority_test
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/inmemcrypto"
+	"github.com/ekspand/trusty/pkg/tsa"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestamp(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	cryptoProv, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+
+	dir, err := ioutil.TempDir("", "timestamp")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	profiles := map[string]*authority.CertProfile{
+		"timestamp": {
+			Usage:  []string{"digital signature", "timestamping"},
+			Expiry: csr.OneYear,
+		},
+	}
+
+	rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &csr.CertificateRequest{
+		CommonName: "[TEST] Trusty Root CA",
+		KeyRequest: prov.NewKeyRequest("TestTimestamp", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+	signer, err := authority.NewSignerFromPEM(cryptoProv, rootKey)
+	require.NoError(t, err)
+	root, err := authority.CreateIssuer(&authority.IssuerConfig{
+		Label:    "ROOT",
+		Profiles: profiles,
+	}, rootPEM, nil, nil, signer)
+	require.NoError(t, err)
+
+	csrPEM, key, _, _, err := prov.CreateRequestAndExportKey(&csr.CertificateRequest{
+		CommonName: "[TEST] Trusty TSA",
+		KeyRequest: prov.NewKeyRequest("TestTimestamp", "ECDSA", 256, csr.SigningKey),
+	})
+	require.NoError(t, err)
+	crt, certPEM, err := root.Sign(csr.SignRequest{
+		Request: string(csrPEM),
+		Profile: "timestamp",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping}, crt.ExtKeyUsage)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "root.pem"), rootPEM, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "tsa.pem"), certPEM, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "tsa-key.pem"), key, 0600))
+
+	policy := csr.OID{1, 3, 6, 1, 4, 1, 59765, 1, 1}
+	cfg := &authority.Config{
+		Authority: &authority.CAConfig{
+			DefaultAIA: &authority.AIAConfig{},
+			Issuers: []authority.IssuerConfig{
+				{
+					Label:          "TSA",
+					Type:           authority.IssuerTypeTimestamp,
+					CertFile:       filepath.Join(dir, "tsa.pem"),
+					KeyFile:        filepath.Join(dir, "tsa-key.pem"),
+					RootBundleFile: filepath.Join(dir, "root.pem"),
+					TSA: &authority.TSAConfig{
+						Policy:   policy,
+						Accuracy: time.Second,
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+
+	ca, err := authority.NewAuthority(cfg, cryptoProv)
+	require.NoError(t, err)
+
+	_, err = ca.TimestampIssuer("unknown")
+	assert.EqualError(t, err, "timestamp issuer not found: unknown")
+
+	issuer, err := ca.TimestampIssuer("")
+	require.NoError(t, err)
+	assert.Equal(t, "TSA", issuer.Label())
+
+	digest := sha256.Sum256([]byte("trusty"))
+	req, err := tsa.NewRequest(crypto.SHA256, digest[:], big.NewInt(42), true)
+	require.NoError(t, err)
+
+	info, der, err := issuer.Timestamp(req)
+	require.NoError(t, err)
+	assert.Equal(t, asn1.ObjectIdentifier(policy), info.Policy)
+	assert.Equal(t, time.Second, info.Accuracy.Duration())
+	assert.Equal(t, int64(42), info.Nonce.Int64())
+
+	token, err := tsa.ParseToken(der)
+	require.NoError(t, err)
+	require.NoError(t, token.Verify(crt, digest[:]))
+	require.NotEmpty(t, token.Certificates)
+	assert.Equal(t, crt.Raw, token.Certificates[0].Raw)
+
+	t.Run("policy", func(t *testing.T) {
+		r := *req
+		r.ReqPolicy = asn1.ObjectIdentifier{1, 2, 3}
+		_, _, err := issuer.Timestamp(&r)
+		require.Error(t, err)
+		assert.Equal(t, tsa.UnacceptedPolicy, err.(*tsa.Error).Info)
+	})
+
+	t.Run("not_timestamp", func(t *testing.T) {
+		_, _, err := root.Timestamp(req)
+		assert.EqualError(t, err, "issuer does not support timestamp: ROOT")
+	})
+
+	t.Run("missing_policy", func(t *testing.T) {
+		cfg.Authority.Issuers[0].TSA = nil
+		assert.EqualError(t, cfg.Validate(), "missing TSA policy for TSA issuer")
+	})
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	v1 "github.com/ekspand/trusty/api/v1"
@@ -50,6 +52,9 @@ type Service struct {
 	oauthProv *oauth2client.Provider
 	db        db.OrgsDb
 	jwt       jwt.Provider
+
+	// userCodeLimiter limits the lookups of the device user codes
+	userCodeLimiter *rateLimiter
 }
 
 // Factory returns a factory of the service
@@ -65,6 +70,8 @@ func Factory(server *gserver.Server) interface{} {
 			oauthProv: oauthProv,
 			db:        sql,
 			jwt:       jwt,
+
+			userCodeLimiter: newRateLimiter(userCodeLookupLimit, userCodeLookupWindow),
 		}
 
 		if cfg.Github.BaseURL != "" {
@@ -106,10 +113,26 @@ func (s *Service) Close() {
 // RegisterRoute adds the Status API endpoints to the overall URL router
 func (s *Service) RegisterRoute(r rest.Router) {
 	r.GET(v1.PathForAuthURL, s.AuthURLHandler())
+	r.GET(v1.PathForAuthLogin, s.LoginHandler())
 	r.GET(v1.PathForAuthGithubCallback, s.GithubCallbackHandler())
 	r.GET(v1.PathForAuthGoogleCallback, s.GoogleCallbackHandler())
+	r.GET(v1.PathForAuthOIDCCallback, s.OIDCCallbackHandler())
 	r.GET(v1.PathForAuthTokenRefresh, s.RefreshHandler())
 	r.GET(v1.PathForAuthDone, s.AuthDoneHandler())
+	r.GET(v1.PathForJWKS, s.JWKSHandler())
+	r.POST(v1.PathForAuthDeviceCode, s.DeviceCodeHandler())
+	r.POST(v1.PathForAuthDeviceToken, s.DeviceTokenHandler())
+	r.GET(v1.PathForAuthDevice, s.DeviceHandler())
+	r.POST(v1.PathForAuthDevice, s.DeviceLoginHandler())
+	r.POST(v1.PathForAuthDeviceApprove, s.DeviceApproveHandler())
+	r.GET(v1.PathForAuthSessions, s.SessionsHandler())
+	r.DELETE(v1.PathForAuthSessions, s.RevokeSessionsHandler())
+	r.DELETE(v1.PathForAuthSession, s.RevokeSessionHandler())
+	r.GET(v1.PathForAuthUserSessions, s.UserSessionsHandler())
+	r.DELETE(v1.PathForAuthUserSessions, s.RevokeUserSessionsHandler())
+	r.GET(v1.PathForAuthRoleBindings, s.RoleBindingsHandler())
+	r.POST(v1.PathForAuthRoleBindings, s.CreateRoleBindingHandler())
+	r.DELETE(v1.PathForAuthRoleBinding, s.RemoveRoleBindingHandler())
 }
 
 // OAuthConfig returns oauth2client.Config,
@@ -118,50 +141,130 @@ func (s *Service) OAuthConfig(provider string) *oauth2client.Config {
 	return s.oauthProv.Client(provider).Config()
 }
 
-// AuthURLHandler handles v1.PathForAuthURL
+// AuthURLHandler handles v1.PathForAuthURL,
+// and returns URL of v1.PathForAuthLogin to be opened in the browser
 func (s *Service) AuthURLHandler() rest.Handle {
 	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
-		redirectURL, ok := r.URL.Query()["redirect_url"]
-		if !ok || len(redirectURL) != 1 || redirectURL[0] == "" {
-			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("missing redirect_url parameter"))
+		sts, authState, herr := s.parseAuthState(r)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
 			return
 		}
 
-		deviceID, ok := r.URL.Query()["device_id"]
-		if !ok || len(deviceID) != 1 || deviceID[0] == "" {
-			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("missing device_id parameter"))
-			return
+		query := url.Values{
+			"redirect_url": {authState.RedirectURL},
+			"device_id":    {authState.DeviceID},
+			"sts":          {sts},
+		}
+		res := &v1.AuthStsURLResponse{
+			URL: s.cfg.TrustyClient.ServerURL[config.WFEServerName][0] + v1.PathForAuthLogin + "?" + query.Encode(),
 		}
 
-		sts := ""
-		providerParam, ok := r.URL.Query()["sts"]
-		if !ok || len(providerParam) != 1 || providerParam[0] == "" {
-			// use github oauth2 provider by default
-			sts = v1.ProviderGithub
-		} else {
-			sts = providerParam[0]
+		logger.Tracef("reqRedirectURL=%q, deviceID=%s, sts=%s, url=%q",
+			authState.RedirectURL, authState.DeviceID, sts, res.URL)
+
+		marshal.WriteJSON(w, r, res)
+	}
+}
+
+// LoginHandler handles v1.PathForAuthLogin,
+// and redirects the browser to the consent page of the provider
+func (s *Service) LoginHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
+		sts, authState, herr := s.parseAuthState(r)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
 		}
 
-		redirectURLCallback := ""
-		switch sts {
-		case v1.ProviderGithub:
-			redirectURLCallback = v1.PathForAuthGithubCallback
-		case v1.ProviderGoogle:
-			redirectURLCallback = v1.PathForAuthGoogleCallback
-		default:
-			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("invalid oauth2 provider"))
+		authURL, herr := s.authCodeURL(r.Context(), w, sts, authState)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
 			return
 		}
 
-		js, _ := json.Marshal(&v1.AuthState{
-			RedirectURL: redirectURL[0],
-			DeviceID:    deviceID[0],
-		})
-		responseMode := oauth2.SetAuthURLParam("response_mode", "query")
-		oauth2ResponseType := oauth2.SetAuthURLParam("response_type", "code")
+		http.Redirect(w, r, authURL, http.StatusSeeOther)
+	}
+}
+
+// parseAuthState returns the provider and the state of the login request
+func (s *Service) parseAuthState(r *http.Request) (string, *v1.AuthState, *httperror.Error) {
+	redirectURL, ok := r.URL.Query()["redirect_url"]
+	if !ok || len(redirectURL) != 1 || redirectURL[0] == "" {
+		return "", nil, httperror.WithInvalidRequest("missing redirect_url parameter")
+	}
 
+	deviceID, ok := r.URL.Query()["device_id"]
+	if !ok || len(deviceID) != 1 || deviceID[0] == "" {
+		return "", nil, httperror.WithInvalidRequest("missing device_id parameter")
+	}
+
+	sts := ""
+	providerParam, ok := r.URL.Query()["sts"]
+	if !ok || len(providerParam) != 1 || providerParam[0] == "" {
+		// use github oauth2 provider by default
+		sts = v1.ProviderGithub
+	} else {
+		sts = providerParam[0]
+	}
+
+	switch sts {
+	case v1.ProviderGithub, v1.ProviderGoogle:
+	default:
+		if client := s.oauthProv.Client(sts); client == nil || !client.IsOIDC() {
+			return "", nil, httperror.WithInvalidRequest("invalid oauth2 provider")
+		}
+	}
+
+	return sts, &v1.AuthState{
+		RedirectURL: redirectURL[0],
+		DeviceID:    deviceID[0],
+	}, nil
+}
+
+// authCodeURL returns URL of the consent page of the provider,
+// the authState is returned to the callback as OAuth state,
+// and is bound to the browser with the state cookie
+func (s *Service) authCodeURL(ctx context.Context, w http.ResponseWriter, sts string, authState *v1.AuthState) (string, *httperror.Error) {
+	redirectURLCallback := ""
+	var oidcClient *oauth2client.Client
+	switch sts {
+	case v1.ProviderGithub:
+		redirectURLCallback = v1.PathForAuthGithubCallback
+	case v1.ProviderGoogle:
+		redirectURLCallback = v1.PathForAuthGoogleCallback
+	default:
+		oidcClient = s.oauthProv.Client(sts)
+		if oidcClient == nil || !oidcClient.IsOIDC() {
+			return "", httperror.WithInvalidRequest("invalid oauth2 provider")
+		}
+	}
+
+	responseMode := oauth2.SetAuthURLParam("response_mode", "query")
+	oauth2ResponseType := oauth2.SetAuthURLParam("response_type", "code")
+	authState.Nonce = certutil.RandomString(32)
+	opts := []oauth2.AuthCodeOption{
+		oauth2ResponseType,
+		responseMode,
+		oauth2.SetAuthURLParam("nonce", authState.Nonce),
+	}
+
+	var conf *oauth2.Config
+	verifier := ""
+	if oidcClient != nil {
+		var err error
+		conf, err = s.oidcConfig(ctx, oidcClient)
+		if err != nil {
+			return "", httperror.WithUnexpected("unable to discover OIDC provider: %s", err.Error()).WithCause(err)
+		}
+		verifier = oauth2client.NewCodeVerifier()
+		opts = append(opts,
+			oauth2.SetAuthURLParam("code_challenge", oauth2client.CodeChallenge(verifier)),
+			oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		)
+	} else {
 		o := s.OAuthConfig(sts)
-		conf := &oauth2.Config{
+		conf = &oauth2.Config{
 			ClientID:     o.ClientID,
 			ClientSecret: o.ClientSecret,
 			RedirectURL:  s.cfg.TrustyClient.ServerURL[config.WFEServerName][0] + redirectURLCallback,
@@ -171,45 +274,22 @@ func (s *Service) AuthURLHandler() rest.Handle {
 				TokenURL: o.TokenURL,
 			},
 		}
+	}
 
-		nonce := oauth2.SetAuthURLParam("nonce", certutil.RandomString(12))
-		res := &v1.AuthStsURLResponse{
-			// Redirect user to consent page to ask for permission
-			// for the scopes specified above.
-			URL: conf.AuthCodeURL(base64.RawURLEncoding.EncodeToString(js), oauth2ResponseType, responseMode, nonce),
-		}
-
-		logger.Tracef("reqRedirectURL=%q, confRedirectURL=%q, deviceID=%s, sts=%s, url=%q",
-			redirectURL[0], conf.RedirectURL, deviceID[0], sts, res.URL)
+	setStateCookie(w, authState.Nonce, verifier)
 
-		marshal.WriteJSON(w, r, res)
-	}
+	js, _ := json.Marshal(authState)
+	// Redirect user to consent page to ask for permission
+	// for the scopes specified above.
+	return conf.AuthCodeURL(base64.RawURLEncoding.EncodeToString(js), opts...), nil
 }
 
 // GithubCallbackHandler handles v1.PathForAuthGithubCallback
 func (s *Service) GithubCallbackHandler() rest.Handle {
 	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
-		code, ok := r.URL.Query()["code"]
-		if !ok || len(code) != 1 || code[0] == "" {
-			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("missing code parameter"))
-			return
-		}
-
-		state, ok := r.URL.Query()["state"]
-		if !ok || len(state) != 1 || state[0] == "" {
-			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("missing state parameter"))
-			return
-		}
-
-		js, err := base64.RawURLEncoding.DecodeString(state[0])
-		if err != nil {
-			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("invalid state parameter: %s", err.Error()))
-			return
-		}
-
-		var oauthStatus v1.AuthState
-		if err = json.Unmarshal(js, &oauthStatus); err != nil {
-			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("failed to decode state parameter: %s", err.Error()))
+		code, oauthStatus, _, herr := parseCallback(w, r)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
 			return
 		}
 
@@ -226,7 +306,7 @@ func (s *Service) GithubCallbackHandler() rest.Handle {
 		}
 
 		ctx := context.Background()
-		token, err := conf.Exchange(ctx, code[0])
+		token, err := conf.Exchange(ctx, code)
 		if err != nil {
 			err = errors.Trace(err)
 			logger.Debugf("reason=Exchange, confRedirectURL=%q, AuthURL=%q, TokenURL=%q, sec=%q, err=%q",
@@ -276,70 +356,16 @@ func (s *Service) GithubCallbackHandler() rest.Handle {
 			user.TokenExpiresAt = model.NullTime(&token.Expiry)
 		}
 
-		user, err = s.db.LoginUser(ctx, user)
-		if err != nil {
-			marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to login user: %s", err.Error()).WithCause(err))
-			return
-		}
-
-		dto := user.ToDto()
-		// initial token is valid for 1 min, the client has to refresh it
-		validFor := time.Minute
-		if oauthStatus.DeviceID == s.server.Hostname() {
-			// on the same host where the server is running on, allow for 8 hours
-			validFor = 8 * 60 * time.Minute
-			logger.Noticef("device=%s, email=%s, token_valid_for=%v",
-				oauthStatus.DeviceID, uemail, validFor)
-		}
-
-		audience := s.server.Configuration().IdentityMap.JWT.Audience
-		tokenStr, _, err := s.jwt.SignToken(dto.ID, user.Email, audience, validFor)
-		if err != nil {
-			marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to sign JWT: %s", err.Error()).WithCause(err))
-			return
-		}
-
-		redirect := fmt.Sprintf("%s?token=%s&device_id=%s", oauthStatus.RedirectURL, tokenStr, oauthStatus.DeviceID)
-		logger.KV(xlog.DEBUG, "redirect", redirect)
-
-		s.server.Audit(
-			ServiceName,
-			evtTokenIssued,
-			user.Email,
-			oauthStatus.DeviceID,
-			0,
-			fmt.Sprintf("ID=%s, ExternalID=%s, email=%s, name=%q",
-				dto.ID, dto.ExternalID, dto.Email, dto.Name),
-		)
-
-		http.Redirect(w, r, redirect, http.StatusSeeOther)
+		s.loginAndRedirect(ctx, w, r, user, nil, oauthStatus)
 	}
 }
 
 // GoogleCallbackHandler handles v1.PathForAuthGoogleCallback
 func (s *Service) GoogleCallbackHandler() rest.Handle {
 	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
-		code, ok := r.URL.Query()["code"]
-		if !ok || len(code) != 1 || code[0] == "" {
-			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("missing code parameter"))
-			return
-		}
-
-		state, ok := r.URL.Query()["state"]
-		if !ok || len(state) != 1 || state[0] == "" {
-			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("missing state parameter"))
-			return
-		}
-
-		js, err := base64.RawURLEncoding.DecodeString(state[0])
-		if err != nil {
-			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("invalid state parameter: %s", err.Error()))
-			return
-		}
-
-		var oauthStatus v1.AuthState
-		if err = json.Unmarshal(js, &oauthStatus); err != nil {
-			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("failed to decode state parameter: %s", err.Error()))
+		code, oauthStatus, _, herr := parseCallback(w, r)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
 			return
 		}
 
@@ -356,7 +382,7 @@ func (s *Service) GoogleCallbackHandler() rest.Handle {
 		}
 
 		ctx := context.Background()
-		token, err := conf.Exchange(ctx, code[0])
+		token, err := conf.Exchange(ctx, code)
 		if err != nil {
 			err = errors.Trace(err)
 			logger.Debugf("reason=Exchange, confRedirectURL=%q, AuthURL=%q, TokenURL=%q, sec=%q, err=%q",
@@ -413,44 +439,188 @@ func (s *Service) GoogleCallbackHandler() rest.Handle {
 			user.TokenExpiresAt = model.NullTime(&token.Expiry)
 		}
 
-		user, err = s.db.LoginUser(ctx, user)
+		s.loginAndRedirect(ctx, w, r, user, nil, oauthStatus)
+	}
+}
+
+// OIDCCallbackHandler handles v1.PathForAuthOIDCCallback
+func (s *Service) OIDCCallbackHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p rest.Params) {
+		provider := p.ByName("provider")
+		client := s.oauthProv.Client(provider)
+		if client == nil || !client.IsOIDC() {
+			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("invalid oauth2 provider"))
+			return
+		}
+
+		code, oauthStatus, codeVerifier, herr := parseCallback(w, r)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+		if codeVerifier == "" {
+			marshal.WriteJSON(w, r, httperror.WithForbidden("missing PKCE verifier of the login"))
+			return
+		}
+
+		ctx := r.Context()
+		conf, err := s.oidcConfig(ctx, client)
 		if err != nil {
-			marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to login user: %s", err.Error()).WithCause(err))
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to discover OIDC provider: %s", err.Error()).WithCause(err))
 			return
 		}
 
-		dto := user.ToDto()
-		// initial token is valid for 1 min, the client has to refresh it
-		validFor := time.Minute
-		if oauthStatus.DeviceID == s.server.Hostname() {
-			// on the same host where the server is running on, allow for 8 hours
-			validFor = 8 * 60 * time.Minute
-			logger.Noticef("device=%s, email=%s, token_valid_for=%v",
-				oauthStatus.DeviceID, uemail, validFor)
+		verifier := oauth2.SetAuthURLParam("code_verifier", codeVerifier)
+		token, err := conf.Exchange(ctx, code, verifier)
+		if err != nil {
+			err = errors.Trace(err)
+			logger.Debugf("reason=Exchange, provider=%s, confRedirectURL=%q, TokenURL=%q, err=%q",
+				provider, conf.RedirectURL, conf.Endpoint.TokenURL, err.Error())
+			marshal.WriteJSON(w, r, httperror.WithForbidden("authorization failed: %s", err.Error()).WithCause(err))
+			return
 		}
 
-		audience := s.server.Configuration().IdentityMap.JWT.Audience
-		tokenStr, _, err := s.jwt.SignToken(dto.ID, user.Email, audience, validFor)
+		rawIDToken, _ := token.Extra("id_token").(string)
+		if rawIDToken == "" {
+			marshal.WriteJSON(w, r, httperror.WithForbidden("retreived token without id_token"))
+			return
+		}
+
+		idToken, err := client.VerifyIDToken(ctx, rawIDToken, oauthStatus.Nonce)
 		if err != nil {
-			marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to sign JWT: %s", err.Error()).WithCause(err))
+			marshal.WriteJSON(w, r, httperror.WithForbidden("invalid id_token: %s", err.Error()).WithCause(err))
 			return
 		}
 
-		redirect := fmt.Sprintf("%s?token=%s&device_id=%s", oauthStatus.RedirectURL, tokenStr, oauthStatus.DeviceID)
-		logger.KV(xlog.DEBUG, "redirect", redirect)
+		info := client.User(idToken)
+		if info.Email == "" {
+			marshal.WriteJSON(w, r, httperror.WithForbidden("please update your profile with valid email"))
+			return
+		}
+		if !info.EmailVerified {
+			marshal.WriteJSON(w, r, httperror.WithForbidden("the email is not verified by the provider"))
+			return
+		}
+		login := info.Login
+		if login == "" {
+			login = info.Email
+		}
 
-		s.server.Audit(
-			ServiceName,
-			evtTokenIssued,
-			user.Email,
-			oauthStatus.DeviceID,
-			0,
-			fmt.Sprintf("ID=%s, ExternalID=%s, email=%s, name=%q",
-				dto.ID, dto.ExternalID, dto.Email, dto.Name),
-		)
+		logger.KV(xlog.DEBUG,
+			"provider", provider,
+			"subject", info.Subject,
+			"email", info.Email,
+			"groups", info.Groups)
+
+		user := &model.User{
+			Provider:     provider,
+			Subject:      info.Subject,
+			Login:        login,
+			Name:         info.Name,
+			Email:        info.Email,
+			AccessToken:  token.AccessToken,
+			RefreshToken: token.RefreshToken,
+		}
+
+		if !token.Expiry.IsZero() {
+			user.TokenExpiresAt = model.NullTime(&token.Expiry)
+		}
 
-		http.Redirect(w, r, redirect, http.StatusSeeOther)
+		// the groups are qualified by the provider, to not collide across providers
+		groups := make([]string, len(info.Groups))
+		for i, group := range info.Groups {
+			groups[i] = v1.ProviderGroup(provider, group)
+		}
+
+		s.loginAndRedirect(ctx, w, r, user, groups, oauthStatus)
+	}
+}
+
+// oidcConfig returns oauth2.Config with the discovered endpoints
+func (s *Service) oidcConfig(ctx context.Context, client *oauth2client.Client) (*oauth2.Config, error) {
+	discovery, err := client.Discover(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	o := client.Config()
+	scopes := o.Scopes
+	if len(scopes) == 0 {
+		scopes = oauth2client.DefaultOIDCScopes
+	}
+	callback := strings.Replace(v1.PathForAuthOIDCCallback, ":provider", o.ProviderID, 1)
+	return &oauth2.Config{
+		ClientID:     o.ClientID,
+		ClientSecret: o.ClientSecret,
+		RedirectURL:  s.cfg.TrustyClient.ServerURL[config.WFEServerName][0] + callback,
+		Scopes:       scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+	}, nil
+}
+
+// loginAndRedirect registers the user login,
+// and redirects to the client with Trusty token.
+// The groups asserted by the identity provider are stored with the session,
+// in ${provider}/${group} format, to resolve the roles of the user.
+func (s *Service) loginAndRedirect(ctx context.Context, w http.ResponseWriter, r *http.Request, user *model.User, groups []string, oauthStatus *v1.AuthState) {
+	user, err := s.db.LoginUser(ctx, user)
+	if err != nil {
+		marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to login user: %s", err.Error()).WithCause(err))
+		return
 	}
+
+	if oauthStatus.UserCode != "" {
+		s.confirmDevice(ctx, w, r, user, groups, oauthStatus)
+		return
+	}
+
+	dto := user.ToDto()
+	// initial token is valid for 1 min, the client has to refresh it
+	validFor := time.Minute
+	if oauthStatus.DeviceID == s.server.Hostname() {
+		// on the same host where the server is running on, allow for 8 hours
+		validFor = 8 * 60 * time.Minute
+		logger.Noticef("device=%s, email=%s, token_valid_for=%v",
+			oauthStatus.DeviceID, user.Email, validFor)
+	}
+
+	// the session ID is used as `jti` of the token, to allow revocation
+	session, err := s.db.CreateSession(ctx, &model.Session{
+		UserID:    user.ID,
+		DeviceID:  oauthStatus.DeviceID,
+		ExpiresAt: time.Now().Add(validFor),
+		Groups:    groups,
+	})
+	if err != nil {
+		marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to create session: %s", err.Error()).WithCause(err))
+		return
+	}
+	jti := strconv.FormatUint(session.ID, 10)
+
+	audience := s.server.Configuration().IdentityMap.JWT.Audience
+	tokenStr, _, err := s.jwt.SignToken(jti, user.Email, audience, validFor)
+	if err != nil {
+		marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to sign JWT: %s", err.Error()).WithCause(err))
+		return
+	}
+
+	redirect := fmt.Sprintf("%s?token=%s&device_id=%s", oauthStatus.RedirectURL, tokenStr, oauthStatus.DeviceID)
+	logger.KV(xlog.DEBUG, "redirect", redirect)
+
+	s.server.Audit(
+		ServiceName,
+		evtTokenIssued,
+		user.Email,
+		oauthStatus.DeviceID,
+		0,
+		fmt.Sprintf("ID=%s, ExternalID=%s, email=%s, name=%q, session=%s",
+			dto.ID, dto.ExternalID, dto.Email, dto.Name, jti),
+	)
+
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
 }
 
 // RefreshHandler  for token
@@ -472,9 +642,30 @@ func (s *Service) RefreshHandler() rest.Handle {
 			return
 		}
 
+		validFor := 8 * 60 * time.Minute
+		var session *model.Session
+		if sessionID := currentSessionID(idn); sessionID != 0 {
+			session, err = s.db.RefreshSession(r.Context(), sessionID, time.Now().Add(validFor))
+		} else {
+			session, err = s.db.CreateSession(r.Context(), &model.Session{
+				UserID:    user.ID,
+				DeviceID:  deviceID,
+				ExpiresAt: time.Now().Add(validFor),
+			})
+		}
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithForbidden("unable to refresh session: %s", err.Error()).WithCause(err))
+			return
+		}
+		// the token must not outlive the session
+		if sessionFor := time.Until(session.ExpiresAt); sessionFor < validFor {
+			validFor = sessionFor
+		}
+		jti := strconv.FormatUint(session.ID, 10)
+
 		dto := user.ToDto()
 		audience := s.server.Configuration().IdentityMap.JWT.Audience
-		auth, claims, err := s.jwt.SignToken(idn.UserID(), user.Email, audience, 8*60*time.Minute)
+		auth, claims, err := s.jwt.SignToken(jti, user.Email, audience, validFor)
 		if err != nil {
 			marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to sign JWT: %s", err.Error()).WithCause(err))
 			return
@@ -486,31 +677,36 @@ func (s *Service) RefreshHandler() rest.Handle {
 			user.Email,
 			deviceID,
 			0,
-			fmt.Sprintf("ID=%s, ExternalID=%s, email=%s, name=%q",
-				dto.ID, dto.ExternalID, dto.Email, dto.Name),
+			fmt.Sprintf("ID=%s, ExternalID=%s, email=%s, name=%q, session=%s",
+				dto.ID, dto.ExternalID, dto.Email, dto.Name, jti),
 		)
 
-		res := &v1.AuthTokenRefreshResponse{
-			Authorization: &v1.Authorization{
-				Version:  "v1.0",
-				DeviceID: deviceID,
-				UserID:   dto.ID,
-				Login:    user.Login,
-				Name:     user.Name,
-				Email:    user.Email,
-				//Role
-				ExpiresAt:   time.Unix(claims.ExpiresAt, 0),
-				IssuedAt:    time.Now(),
-				TokenType:   "jwt",
-				AccessToken: auth,
-			},
-			Profile: dto,
-		}
-
+		res := tokenResponse(user, deviceID, auth, time.Unix(claims.ExpiresAt, 0))
 		marshal.WriteJSON(w, r, res)
 	}
 }
 
+// tokenResponse returns the response with issued access token
+func tokenResponse(user *model.User, deviceID, token string, expiresAt time.Time) *v1.AuthTokenRefreshResponse {
+	dto := user.ToDto()
+	return &v1.AuthTokenRefreshResponse{
+		Authorization: &v1.Authorization{
+			Version:  "v1.0",
+			DeviceID: deviceID,
+			UserID:   dto.ID,
+			Login:    user.Login,
+			Name:     user.Name,
+			Email:    user.Email,
+			//Role
+			ExpiresAt:   expiresAt,
+			IssuedAt:    time.Now(),
+			TokenType:   "jwt",
+			AccessToken: token,
+		},
+		Profile: dto,
+	}
+}
+
 // AuthDoneHandler handles v1.PathForAuthDone
 func (s *Service) AuthDoneHandler() rest.Handle {
 	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
@@ -524,3 +720,14 @@ func (s *Service) AuthDoneHandler() rest.Handle {
 		fmt.Fprintf(w, "Authenticated!\n\nexport TRUSTY_AUTH_TOKEN=%s\n", token[0])
 	}
 }
+
+// JWKSHandler handles v1.PathForJWKS
+func (s *Service) JWKSHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
+		if s.jwt == nil {
+			marshal.WriteJSON(w, r, httperror.WithNotFound("JWT provider is not configured"))
+			return
+		}
+		marshal.WriteJSON(w, r, s.jwt.JWKS())
+	}
+}
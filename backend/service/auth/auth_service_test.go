@@ -16,6 +16,7 @@ import (
 	"github.com/ekspand/trusty/backend/trustymain"
 	"github.com/ekspand/trusty/internal/config"
 	"github.com/ekspand/trusty/pkg/gserver"
+	"github.com/ekspand/trusty/pkg/jwk"
 	"github.com/ekspand/trusty/tests/testutils"
 	"github.com/go-phorce/dolly/testify/servefiles"
 	"github.com/go-phorce/dolly/xhttp/marshal"
@@ -149,10 +150,55 @@ func Test_authURLHandler(t *testing.T) {
 		var res v1.AuthStsURLResponse
 		require.NoError(t, marshal.Decode(w.Body, &res))
 		require.NotNil(t, res)
-		assert.NotEmpty(t, res.URL)
+		assert.Contains(t, res.URL, v1.PathForAuthLogin+"?")
 	})
 }
 
+func Test_LoginHandler(t *testing.T) {
+	service := trustyServer.Service(auth.ServiceName).(*auth.Service)
+	require.NotNil(t, service)
+
+	h := service.LoginHandler()
+
+	t.Run("invalid_provider", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, v1.PathForAuthLogin+"?redirect_url=http://localhost&device_id=1234&sts=invalid", nil)
+		require.NoError(t, err)
+
+		h(w, r, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, w.Result().Cookies())
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		state, cookie := loginState(t, service)
+		assert.NotEmpty(t, state)
+		assert.True(t, cookie.HttpOnly)
+		assert.True(t, cookie.Secure)
+		assert.Equal(t, v1.PathForAuth, cookie.Path)
+	})
+}
+
+// loginState starts the login with github provider,
+// and returns OAuth state and the cookie that binds the state to the browser
+func loginState(t *testing.T, service *auth.Service) (string, *http.Cookie) {
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest(http.MethodGet, v1.PathForAuthLogin+"?redirect_url=https://localhost:7891/v1/status&device_id=1234", nil)
+	require.NoError(t, err)
+
+	service.LoginHandler()(w, r, nil)
+	require.Equal(t, http.StatusSeeOther, w.Code)
+
+	loc, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	state := loc.Query().Get("state")
+	require.NotEmpty(t, state)
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	return state, cookies[0]
+}
+
 func Test_GithubCallbackHandler(t *testing.T) {
 	service := trustyServer.Service(auth.ServiceName).(*auth.Service)
 	require.NotNil(t, service)
@@ -211,11 +257,38 @@ func Test_GithubCallbackHandler(t *testing.T) {
 		assert.Equal(t, "{\"code\":\"invalid_request\",\"message\":\"invalid state parameter: illegal base64 data at input byte 0\"}", string(w.Body.Bytes()))
 	})
 
+	t.Run("no_cookie", func(t *testing.T) {
+		state, _ := loginState(t, service)
+
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, v1.PathForAuthGithubCallback+"?code=9298935ecf8777061ff2&state="+state, nil)
+		require.NoError(t, err)
+
+		h(w, r, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("other_browser", func(t *testing.T) {
+		state, _ := loginState(t, service)
+		_, cookie := loginState(t, service)
+
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, v1.PathForAuthGithubCallback+"?code=9298935ecf8777061ff2&state="+state, nil)
+		require.NoError(t, err)
+		r.AddCookie(cookie)
+
+		h(w, r, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
 	t.Run("token", func(t *testing.T) {
+		state, cookie := loginState(t, service)
+
 		w := httptest.NewRecorder()
 		// Value of code is not magic. Mock configured in requests.json will ignore code and give back a token.
-		r, err := http.NewRequest(http.MethodGet, v1.PathForAuthGithubCallback+"?code=9298935ecf8777061ff2&state=eyJyZWRpcmVjdF91cmwiOiJodHRwczovL2xvY2FsaG9zdDo3ODkxL3YxL3N0YXR1cyIsImRldmljZV9pZCI6IjEyMzQifQ", nil)
+		r, err := http.NewRequest(http.MethodGet, v1.PathForAuthGithubCallback+"?code=9298935ecf8777061ff2&state="+state, nil)
 		require.NoError(t, err)
+		r.AddCookie(cookie)
 
 		h(w, r, nil)
 		require.Equal(t, http.StatusSeeOther, w.Code)
@@ -282,11 +355,38 @@ func Test_GoogleCallbackHandler(t *testing.T) {
 		assert.Equal(t, "{\"code\":\"invalid_request\",\"message\":\"invalid state parameter: illegal base64 data at input byte 0\"}", string(w.Body.Bytes()))
 	})
 
+	t.Run("no_cookie", func(t *testing.T) {
+		state, _ := loginState(t, service)
+
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, v1.PathForAuthGoogleCallback+"?code=9298935ecf8777061ff2&state="+state, nil)
+		require.NoError(t, err)
+
+		h(w, r, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("other_browser", func(t *testing.T) {
+		state, _ := loginState(t, service)
+		_, cookie := loginState(t, service)
+
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, v1.PathForAuthGoogleCallback+"?code=9298935ecf8777061ff2&state="+state, nil)
+		require.NoError(t, err)
+		r.AddCookie(cookie)
+
+		h(w, r, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
 	t.Run("token", func(t *testing.T) {
+		state, cookie := loginState(t, service)
+
 		w := httptest.NewRecorder()
 		// Value of code is not magic. Mock configured in requests.json will ignore code and give back a token.
-		r, err := http.NewRequest(http.MethodGet, v1.PathForAuthGoogleCallback+"?code=9298935ecf8777061ff2&state=eyJyZWRpcmVjdF91cmwiOiJodHRwczovL2xvY2FsaG9zdDo3ODkxL3YxL3N0YXR1cyIsImRldmljZV9pZCI6IjEyMzQifQ", nil)
+		r, err := http.NewRequest(http.MethodGet, v1.PathForAuthGoogleCallback+"?code=9298935ecf8777061ff2&state="+state, nil)
 		require.NoError(t, err)
+		r.AddCookie(cookie)
 
 		h(w, r, nil)
 		require.Equal(t, http.StatusSeeOther, w.Code)
@@ -347,3 +447,20 @@ func TestAuthDoneHandler(t *testing.T) {
 	service.AuthDoneHandler()(w, r, nil)
 	require.Equal(t, http.StatusOK, w.Code)
 }
+
+func TestJWKSHandler(t *testing.T) {
+	service := trustyServer.Service(auth.ServiceName).(*auth.Service)
+	require.NotNil(t, service)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest(http.MethodGet, v1.PathForJWKS, nil)
+	require.NoError(t, err)
+
+	service.JWKSHandler()(w, r, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var res jwk.Set
+	require.NoError(t, marshal.Decode(w.Body, &res))
+	// the dev configuration uses HMAC keys, that are not published
+	assert.Empty(t, res.Keys)
+}
@@ -0,0 +1,482 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/ekspand/trusty/internal/config"
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/go-phorce/dolly/rest"
+	"github.com/go-phorce/dolly/xhttp/header"
+	"github.com/go-phorce/dolly/xhttp/httperror"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/go-phorce/dolly/xhttp/marshal"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/juju/errors"
+)
+
+const (
+	// deviceCodeExpiry specifies the lifetime of the device and user codes
+	deviceCodeExpiry = 10 * time.Minute
+	// deviceCodeInterval specifies the minimum polling interval
+	deviceCodeInterval = 5 * time.Second
+	// deviceTokenExpiry specifies the lifetime of the session approved for the device
+	deviceTokenExpiry = 8 * 60 * time.Minute
+	// deviceConsentExpiry specifies the time to approve the device after the login
+	deviceConsentExpiry = 5 * time.Minute
+	// deviceCookieName specifies the cookie that binds the consent to the browser
+	deviceCookieName = "trusty_device"
+	// userCodeLookupLimit specifies the number of user code lookups
+	// allowed for a client in userCodeLookupWindow
+	userCodeLookupLimit  = 10
+	userCodeLookupWindow = time.Minute
+)
+
+const (
+	evtDeviceCodeIssued = "device_code_issued"
+	evtDeviceApproved   = "device_approved"
+	evtDeviceDenied     = "device_denied"
+)
+
+var deviceForm = template.Must(template.New("device").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Trusty device login</title></head>
+<body>
+<form method="GET" action="{{.}}">
+<p>Enter the code displayed on your device:</p>
+<input type="text" name="user_code" autocomplete="off" autofocus>
+<input type="submit" value="Continue">
+</form>
+</body>
+</html>
+`))
+
+// deviceConfirmForm is shown before the login,
+// the user must confirm the device that requested the code
+var deviceConfirmForm = template.Must(template.New("device_confirm").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Trusty device login</title></head>
+<body>
+<form method="POST" action="{{.Action}}">
+<p>The device <b>{{.DeviceID}}</b> requests to sign in with the code <b>{{.UserCode}}</b>.</p>
+<p>Continue only if you started the sign in on this device, and the code matches the code displayed on your device.</p>
+<input type="hidden" name="user_code" value="{{.UserCode}}">
+<input type="submit" value="Sign in with {{.Provider}}">
+</form>
+</body>
+</html>
+`))
+
+// deviceConsentForm is shown after the login,
+// the device is approved only on the consent of the user
+var deviceConsentForm = template.Must(template.New("device_consent").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Trusty device login</title></head>
+<body>
+<form method="POST" action="{{.Action}}">
+<p>Signed in with {{.Provider}} as <b>{{.Email}}</b>.</p>
+<p>Allow the device <b>{{.DeviceID}}</b> with the code <b>{{.UserCode}}</b> to access Trusty on your behalf?</p>
+<input type="hidden" name="user_code" value="{{.UserCode}}">
+<input type="hidden" name="csrf" value="{{.CSRF}}">
+<button type="submit" name="action" value="approve">Approve</button>
+<button type="submit" name="action" value="deny">Deny</button>
+</form>
+</body>
+</html>
+`))
+
+// devicePage provides the values of the device pages
+type devicePage struct {
+	Action   string
+	DeviceID string
+	UserCode string
+	Provider string
+	Email    string
+	CSRF     string
+}
+
+// DeviceCodeHandler handles v1.PathForAuthDeviceCode,
+// and starts device authorization flow
+func (s *Service) DeviceCodeHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
+		req := new(v1.DeviceAuthorizationRequest)
+		err := marshal.DecodeBody(w, r, req)
+		if err != nil {
+			return
+		}
+
+		if req.DeviceID == "" {
+			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("missing device_id parameter"))
+			return
+		}
+		if req.Provider == "" {
+			// use github oauth2 provider by default
+			req.Provider = v1.ProviderGithub
+		}
+		if s.oauthProv.Client(req.Provider) == nil {
+			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("invalid oauth2 provider"))
+			return
+		}
+
+		deviceCode, err := model.NewDeviceCode()
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to generate device code: %s", err.Error()).WithCause(err))
+			return
+		}
+		userCode, err := model.NewUserCode()
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to generate user code: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		d := &model.DeviceAuthorization{
+			DeviceCode: model.HashDeviceCode(deviceCode),
+			UserCode:   userCode,
+			Provider:   req.Provider,
+			DeviceID:   req.DeviceID,
+			ExpiresAt:  time.Now().Add(deviceCodeExpiry),
+		}
+		if err = d.Validate(); err != nil {
+			marshal.WriteJSON(w, r, httperror.WithInvalidRequest(err.Error()))
+			return
+		}
+
+		_, err = s.db.CreateDeviceAuthorization(r.Context(), d)
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to create device authorization: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		s.server.Audit(
+			ServiceName,
+			evtDeviceCodeIssued,
+			identity.FromRequest(r).Identity().Name(),
+			req.DeviceID,
+			0,
+			fmt.Sprintf("user_code=%s, provider=%s", userCode, req.Provider),
+		)
+
+		verificationURI := s.cfg.TrustyClient.ServerURL[config.WFEServerName][0] + v1.PathForAuthDevice
+		marshal.WriteJSON(w, r, &v1.DeviceAuthorizationResponse{
+			DeviceCode:              deviceCode,
+			UserCode:                userCode,
+			VerificationURI:         verificationURI,
+			VerificationURIComplete: verificationURI + "?user_code=" + url.QueryEscape(userCode),
+			ExpiresIn:               int(deviceCodeExpiry.Seconds()),
+			Interval:                int(deviceCodeInterval.Seconds()),
+		})
+	}
+}
+
+// DeviceHandler handles GET v1.PathForAuthDevice,
+// the verification page where the user enters the code,
+// and confirms the device before the login
+func (s *Service) DeviceHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
+		userCode := r.URL.Query().Get("user_code")
+		if userCode == "" {
+			w.Header().Set(header.ContentType, "text/html; charset=utf-8")
+			deviceForm.Execute(w, v1.PathForAuthDevice)
+			return
+		}
+
+		d, herr := s.deviceAuthorization(r, userCode)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+
+		w.Header().Set(header.ContentType, "text/html; charset=utf-8")
+		deviceConfirmForm.Execute(w, &devicePage{
+			Action:   v1.PathForAuthDevice,
+			DeviceID: d.DeviceID,
+			UserCode: d.UserCode,
+			Provider: d.Provider,
+		})
+	}
+}
+
+// DeviceLoginHandler handles POST v1.PathForAuthDevice,
+// and redirects to the provider, after the user confirmed the device
+func (s *Service) DeviceLoginHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
+		d, herr := s.deviceAuthorization(r, r.PostFormValue("user_code"))
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+
+		authURL, herr := s.authCodeURL(r.Context(), w, d.Provider, &v1.AuthState{
+			DeviceID: d.DeviceID,
+			UserCode: d.UserCode,
+		})
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+
+		http.Redirect(w, r, authURL, http.StatusSeeOther)
+	}
+}
+
+// deviceAuthorization returns the pending device authorization by the user code,
+// the lookups are rate limited for the client, to prevent guessing of the codes
+func (s *Service) deviceAuthorization(r *http.Request, userCode string) (*model.DeviceAuthorization, *httperror.Error) {
+	if userCode == "" {
+		return nil, httperror.WithInvalidRequest("missing user_code parameter")
+	}
+	if !s.userCodeLimiter.Allow(identity.ClientIPFromRequest(r), time.Now()) {
+		return nil, httperror.WithRateLimitExceeded("too many attempts, try again later")
+	}
+
+	d, err := s.db.GetDeviceAuthorization(r.Context(), model.NormalizeUserCode(userCode))
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, httperror.WithNotFound("the code is invalid or expired")
+		}
+		return nil, httperror.WithUnexpected("unable to find the code: %s", err.Error()).WithCause(err)
+	}
+	return d, nil
+}
+
+// DeviceTokenHandler handles v1.PathForAuthDeviceToken,
+// and returns access token of approved device authorization
+func (s *Service) DeviceTokenHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
+		req := new(v1.DeviceTokenRequest)
+		err := marshal.DecodeBody(w, r, req)
+		if err != nil {
+			return
+		}
+		if req.DeviceCode == "" {
+			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("missing device_code parameter"))
+			return
+		}
+
+		now := time.Now()
+		d, err := s.db.PollDeviceAuthorization(r.Context(), model.HashDeviceCode(req.DeviceCode), now)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				marshal.WriteJSON(w, r, deviceError(v1.DeviceInvalidGrant, "the device code is invalid"))
+			} else {
+				marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to find device authorization: %s", err.Error()).WithCause(err))
+			}
+			return
+		}
+
+		if d.IsExpired(now) {
+			s.db.RemoveDeviceAuthorization(r.Context(), d.ID)
+			marshal.WriteJSON(w, r, deviceError(v1.DeviceExpiredToken, "the device code is expired"))
+			return
+		}
+		if !d.IsApproved() {
+			// allow for a network latency between polls
+			if d.PolledAt.Valid && now.Sub(d.PolledAt.Time) < deviceCodeInterval-time.Second {
+				marshal.WriteJSON(w, r, deviceError(v1.DeviceSlowDown, "polling too frequently"))
+				return
+			}
+			marshal.WriteJSON(w, r, deviceError(v1.DeviceAuthorizationPending, "the authorization is pending"))
+			return
+		}
+
+		// the device code can be exchanged only once,
+		// only the poll that removes the authorization gets the token
+		err = s.db.RemoveDeviceAuthorization(r.Context(), d.ID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				marshal.WriteJSON(w, r, deviceError(v1.DeviceInvalidGrant, "the device code is invalid"))
+				return
+			}
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to remove device authorization: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		user, err := s.db.GetUser(r.Context(), d.UserID)
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithForbidden("user ID %d not found: %s", d.UserID, err.Error()).WithCause(err))
+			return
+		}
+		session, err := s.db.GetSession(r.Context(), d.SessionID)
+		if err != nil || !session.IsActive(now) {
+			marshal.WriteJSON(w, r, deviceError(v1.DeviceExpiredToken, "the session is revoked or expired"))
+			return
+		}
+
+		jti := strconv.FormatUint(session.ID, 10)
+		audience := s.server.Configuration().IdentityMap.JWT.Audience
+		auth, claims, err := s.jwt.SignToken(jti, user.Email, audience, session.ExpiresAt.Sub(now))
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to sign JWT: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		dto := user.ToDto()
+		s.server.Audit(
+			ServiceName,
+			evtTokenIssued,
+			user.Email,
+			d.DeviceID,
+			0,
+			fmt.Sprintf("ID=%s, ExternalID=%s, email=%s, name=%q, session=%s",
+				dto.ID, dto.ExternalID, dto.Email, dto.Name, jti),
+		)
+
+		marshal.WriteJSON(w, r, tokenResponse(user, d.DeviceID, auth, time.Unix(claims.ExpiresAt, 0)))
+	}
+}
+
+// confirmDevice asks the logged in user to approve the pending device authorization.
+// The session is created for the consent, and is extended when the device is approved.
+// The consent is bound to the browser with the cookie, and to the form with CSRF token.
+func (s *Service) confirmDevice(ctx context.Context, w http.ResponseWriter, r *http.Request, user *model.User, groups []string, oauthStatus *v1.AuthState) {
+	d, err := s.db.GetDeviceAuthorization(ctx, oauthStatus.UserCode)
+	if err != nil || d.DeviceID != oauthStatus.DeviceID {
+		marshal.WriteJSON(w, r, httperror.WithNotFound("the code is invalid or expired"))
+		return
+	}
+
+	session, err := s.db.CreateSession(ctx, &model.Session{
+		UserID:    user.ID,
+		DeviceID:  d.DeviceID,
+		ExpiresAt: time.Now().Add(deviceConsentExpiry),
+		Groups:    groups,
+	})
+	if err != nil {
+		marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to create session: %s", err.Error()).WithCause(err))
+		return
+	}
+
+	csrf := certutil.RandomString(32)
+	token, _, err := s.jwt.SignToken(strconv.FormatUint(session.ID, 10), user.Email,
+		deviceConsentAudience(d.UserCode, csrf), deviceConsentExpiry)
+	if err != nil {
+		marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to sign JWT: %s", err.Error()).WithCause(err))
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     deviceCookieName,
+		Value:    token,
+		Path:     v1.PathForAuthDevice,
+		MaxAge:   int(deviceConsentExpiry.Seconds()),
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	w.Header().Set(header.ContentType, "text/html; charset=utf-8")
+	deviceConsentForm.Execute(w, &devicePage{
+		Action:   v1.PathForAuthDeviceApprove,
+		DeviceID: d.DeviceID,
+		UserCode: d.UserCode,
+		Provider: d.Provider,
+		Email:    user.Email,
+		CSRF:     csrf,
+	})
+}
+
+// DeviceApproveHandler handles v1.PathForAuthDeviceApprove,
+// and approves or denies the pending device authorization
+// on the consent of the user, logged in the same browser
+func (s *Service) DeviceApproveHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
+		ctx := r.Context()
+		userCode := model.NormalizeUserCode(r.PostFormValue("user_code"))
+		csrf := r.PostFormValue("csrf")
+
+		cookie, err := r.Cookie(deviceCookieName)
+		if err != nil || userCode == "" || csrf == "" {
+			marshal.WriteJSON(w, r, httperror.WithForbidden("the login is not found or expired"))
+			return
+		}
+		claims, err := s.jwt.ParseToken(cookie.Value, deviceConsentAudience(userCode, csrf))
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithForbidden("the login is not found or expired"))
+			return
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name:     deviceCookieName,
+			Path:     v1.PathForAuthDevice,
+			MaxAge:   -1,
+			Secure:   true,
+			HttpOnly: true,
+			SameSite: http.SameSiteStrictMode,
+		})
+
+		now := time.Now()
+		sessionID, _ := model.ID(claims.Id)
+		session, err := s.db.GetSession(ctx, sessionID)
+		if err != nil || !session.IsActive(now) {
+			marshal.WriteJSON(w, r, httperror.WithForbidden("the login is not found or expired"))
+			return
+		}
+
+		d, herr := s.deviceAuthorization(r, userCode)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+		if d.DeviceID != session.DeviceID {
+			marshal.WriteJSON(w, r, httperror.WithNotFound("the code is invalid or expired"))
+			return
+		}
+
+		if r.PostFormValue("action") != "approve" {
+			s.db.RevokeSession(ctx, session.UserID, session.ID, now)
+			s.db.RemoveDeviceAuthorization(ctx, d.ID)
+
+			s.server.Audit(
+				ServiceName,
+				evtDeviceDenied,
+				claims.Subject,
+				d.DeviceID,
+				0,
+				fmt.Sprintf("UserID=%d, user_code=%s, session=%d", session.UserID, d.UserCode, session.ID),
+			)
+
+			w.Header().Set(header.ContentType, header.TextPlain)
+			fmt.Fprintf(w, "Device %s is denied.\n", d.DeviceID)
+			return
+		}
+
+		session, err = s.db.RefreshSession(ctx, session.ID, now.Add(deviceTokenExpiry))
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithForbidden("the login is not found or expired").WithCause(err))
+			return
+		}
+
+		_, err = s.db.ApproveDeviceAuthorization(ctx, d.ID, session.UserID, session.ID, now)
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithNotFound("the code is invalid or expired").WithCause(err))
+			return
+		}
+
+		s.server.Audit(
+			ServiceName,
+			evtDeviceApproved,
+			claims.Subject,
+			d.DeviceID,
+			0,
+			fmt.Sprintf("UserID=%d, user_code=%s, session=%d", session.UserID, d.UserCode, session.ID),
+		)
+
+		w.Header().Set(header.ContentType, header.TextPlain)
+		fmt.Fprintf(w, "Device %s is authorized!\n\nreturn to the terminal to continue.\n", d.DeviceID)
+	}
+}
+
+// deviceConsentAudience returns the audience of the consent token,
+// bound to the user code and CSRF token of the consent form
+func deviceConsentAudience(userCode, csrf string) string {
+	return "trusty-device/" + userCode + "/" + csrf
+}
+
+func deviceError(code, msg string) *httperror.Error {
+	return httperror.New(http.StatusBadRequest, code, msg)
+}
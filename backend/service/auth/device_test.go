@@ -0,0 +1,157 @@
+package auth_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/ekspand/trusty/backend/service/auth"
+	"github.com/go-phorce/dolly/xhttp/httperror"
+	"github.com/go-phorce/dolly/xhttp/marshal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceHandlers(t *testing.T) {
+	service := trustyServer.Service(auth.ServiceName).(*auth.Service)
+	require.NotNil(t, service)
+
+	post := func(path string, req interface{}) *http.Request {
+		js, err := json.Marshal(req)
+		require.NoError(t, err)
+		r, err := http.NewRequest(http.MethodPost, path, bytes.NewReader(js))
+		require.NoError(t, err)
+		return r
+	}
+
+	t.Run("form", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, v1.PathForAuthDevice, nil)
+		require.NoError(t, err)
+
+		service.DeviceHandler()(w, r, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `name="user_code"`)
+
+		w = httptest.NewRecorder()
+		r, err = http.NewRequest(http.MethodGet, v1.PathForAuthDevice+"?user_code=XXXX-XXXX", nil)
+		require.NoError(t, err)
+
+		service.DeviceHandler()(w, r, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		service.DeviceCodeHandler()(w, post(v1.PathForAuthDeviceCode, &v1.DeviceAuthorizationRequest{}), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		service.DeviceCodeHandler()(w, post(v1.PathForAuthDeviceCode, &v1.DeviceAuthorizationRequest{
+			DeviceID: "jumphost",
+			Provider: "unknown",
+		}), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		service.DeviceTokenHandler()(w, post(v1.PathForAuthDeviceToken, &v1.DeviceTokenRequest{DeviceCode: "unknown"}), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertDeviceError(t, w, v1.DeviceInvalidGrant)
+	})
+
+	t.Run("pending", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		service.DeviceCodeHandler()(w, post(v1.PathForAuthDeviceCode, &v1.DeviceAuthorizationRequest{
+			DeviceID: "jumphost",
+			Provider: v1.ProviderGithub,
+		}), nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var res v1.DeviceAuthorizationResponse
+		require.NoError(t, marshal.Decode(w.Body, &res))
+		assert.NotEmpty(t, res.DeviceCode)
+		assert.Len(t, res.UserCode, 9)
+		assert.Contains(t, res.VerificationURI, v1.PathForAuthDevice)
+		assert.Contains(t, res.VerificationURIComplete, res.UserCode)
+		assert.Equal(t, 600, res.ExpiresIn)
+		assert.Equal(t, 5, res.Interval)
+
+		w = httptest.NewRecorder()
+		service.DeviceTokenHandler()(w, post(v1.PathForAuthDeviceToken, &v1.DeviceTokenRequest{DeviceCode: res.DeviceCode}), nil)
+		assertDeviceError(t, w, v1.DeviceAuthorizationPending)
+
+		w = httptest.NewRecorder()
+		service.DeviceTokenHandler()(w, post(v1.PathForAuthDeviceToken, &v1.DeviceTokenRequest{DeviceCode: res.DeviceCode}), nil)
+		assertDeviceError(t, w, v1.DeviceSlowDown)
+
+		// the device is confirmed before the login
+		w = httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, v1.PathForAuthDevice+"?user_code="+res.UserCode, nil)
+		require.NoError(t, err)
+		service.DeviceHandler()(w, r, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "jumphost")
+		assert.Contains(t, w.Body.String(), res.UserCode)
+		assert.Contains(t, w.Body.String(), `method="POST"`)
+
+		w = httptest.NewRecorder()
+		r = postForm(t, v1.PathForAuthDevice, url.Values{"user_code": {res.UserCode}})
+		service.DeviceLoginHandler()(w, r, nil)
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Location"))
+		assert.NotEmpty(t, w.Result().Cookies())
+
+		// the device is not approved without the login in the same browser
+		w = httptest.NewRecorder()
+		r = postForm(t, v1.PathForAuthDeviceApprove, url.Values{
+			"user_code": {res.UserCode},
+			"csrf":      {"csrf"},
+			"action":    {"approve"},
+		})
+		service.DeviceApproveHandler()(w, r, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = httptest.NewRecorder()
+		r = postForm(t, v1.PathForAuthDeviceApprove, url.Values{
+			"user_code": {res.UserCode},
+			"csrf":      {"csrf"},
+			"action":    {"approve"},
+		})
+		r.AddCookie(&http.Cookie{Name: "trusty_device", Value: "forged"})
+		service.DeviceApproveHandler()(w, r, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("rate_limit", func(t *testing.T) {
+		code := 0
+		for i := 0; i < 20 && code != http.StatusTooManyRequests; i++ {
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(http.MethodGet, v1.PathForAuthDevice+"?user_code=XXXX-XXXX", nil)
+			require.NoError(t, err)
+			r.Header.Set("X-Real-Ip", "10.1.1.1")
+
+			service.DeviceHandler()(w, r, nil)
+			code = w.Code
+		}
+		assert.Equal(t, http.StatusTooManyRequests, code)
+	})
+}
+
+func postForm(t *testing.T, path string, form url.Values) *http.Request {
+	r, err := http.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func assertDeviceError(t *testing.T, w *httptest.ResponseRecorder, code string) {
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var res httperror.Error
+	require.NoError(t, marshal.Decode(w.Body, &res))
+	assert.Equal(t, code, res.Code)
+}
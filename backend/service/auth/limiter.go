@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// maxLimiterClients specifies the number of tracked clients,
+// after which the expired windows are removed
+const maxLimiterClients = 10000
+
+// rateLimiter allows up to limit requests per window for each client
+type rateLimiter struct {
+	lock    sync.Mutex
+	limit   int
+	window  time.Duration
+	clients map[string]*limiterWindow
+}
+
+type limiterWindow struct {
+	start time.Time
+	count int
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		window:  window,
+		clients: make(map[string]*limiterWindow),
+	}
+}
+
+// Allow returns false, if the client exceeded the limit in the current window
+func (l *rateLimiter) Allow(client string, now time.Time) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	w := l.clients[client]
+	if w == nil || now.Sub(w.start) >= l.window {
+		if w == nil && len(l.clients) >= maxLimiterClients {
+			l.removeExpired(now)
+		}
+		w = &limiterWindow{start: now}
+		l.clients[client] = w
+	}
+	if w.count >= l.limit {
+		return false
+	}
+	w.count++
+	return true
+}
+
+func (l *rateLimiter) removeExpired(now time.Time) {
+	for client, w := range l.clients {
+		if now.Sub(w.start) >= l.window {
+			delete(l.clients, client)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := newRateLimiter(2, time.Minute)
+	now := time.Now()
+
+	assert.True(t, l.Allow("10.0.0.1", now))
+	assert.True(t, l.Allow("10.0.0.1", now.Add(time.Second)))
+	assert.False(t, l.Allow("10.0.0.1", now.Add(2*time.Second)))
+	// other clients are not limited
+	assert.True(t, l.Allow("10.0.0.2", now.Add(2*time.Second)))
+	// the next window
+	assert.True(t, l.Allow("10.0.0.1", now.Add(time.Minute)))
+
+	l.removeExpired(now.Add(time.Minute + 2*time.Second))
+	assert.Len(t, l.clients, 1)
+}
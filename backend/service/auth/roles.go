@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/go-phorce/dolly/rest"
+	"github.com/go-phorce/dolly/xhttp/httperror"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/go-phorce/dolly/xhttp/marshal"
+	"github.com/juju/errors"
+)
+
+const (
+	evtRoleBindingCreated = "role_binding_created"
+	evtRoleBindingRemoved = "role_binding_removed"
+)
+
+// RoleBindingsHandler returns the role bindings,
+// the access is restricted to admins by authz configuration
+func (s *Service) RoleBindingsHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
+		list, err := s.db.GetRoleBindings(r.Context())
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to get role bindings: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		marshal.WriteJSON(w, r, &v1.RoleBindingsResponse{
+			RoleBindings: model.ToRoleBindingsDto(list),
+		})
+	}
+}
+
+// CreateRoleBindingHandler binds the role to the subject,
+// the access is restricted to admins by authz configuration
+func (s *Service) CreateRoleBindingHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
+		req := new(v1.CreateRoleBindingRequest)
+		err := marshal.DecodeBody(w, r, req)
+		if err != nil {
+			return
+		}
+
+		idn := identity.FromRequest(r).Identity()
+		// the caller may be a service, which is not a user
+		createdBy, _ := model.ID(idn.UserID())
+
+		b := &model.RoleBinding{
+			Role:      req.Role,
+			Subject:   req.Subject,
+			CreatedBy: createdBy,
+		}
+		if err = b.Validate(); err != nil {
+			marshal.WriteJSON(w, r, httperror.WithInvalidRequest(err.Error()))
+			return
+		}
+
+		b, err = s.db.CreateRoleBinding(r.Context(), b)
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to create role binding: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		s.server.Audit(
+			ServiceName,
+			evtRoleBindingCreated,
+			idn.Name(),
+			identity.FromRequest(r).CorrelationID(),
+			0,
+			fmt.Sprintf("ID=%d, role=%s, subject=%s", b.ID, b.Role, b.Subject),
+		)
+
+		marshal.WriteJSON(w, r, &v1.RoleBindingResponse{RoleBinding: b.ToDto()})
+	}
+}
+
+// RemoveRoleBindingHandler deletes the role binding,
+// the access is restricted to admins by authz configuration
+func (s *Service) RemoveRoleBindingHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p rest.Params) {
+		id, err := model.ID(p.ByName("id"))
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithInvalidParam("invalid role binding ID"))
+			return
+		}
+
+		b, err := s.db.RemoveRoleBinding(r.Context(), id)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				marshal.WriteJSON(w, r, httperror.WithNotFound("role binding not found"))
+			} else {
+				marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to remove role binding: %s", err.Error()).WithCause(err))
+			}
+			return
+		}
+
+		s.server.Audit(
+			ServiceName,
+			evtRoleBindingRemoved,
+			identity.FromRequest(r).Identity().Name(),
+			identity.FromRequest(r).CorrelationID(),
+			0,
+			fmt.Sprintf("ID=%d, role=%s, subject=%s", b.ID, b.Role, b.Subject),
+		)
+
+		marshal.WriteJSON(w, r, &v1.RoleBindingResponse{RoleBinding: b.ToDto()})
+	}
+}
@@ -0,0 +1,96 @@
+package auth_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/ekspand/trusty/backend/service/auth"
+	"github.com/go-phorce/dolly/rest"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/go-phorce/dolly/xhttp/marshal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleBindingsHandlers(t *testing.T) {
+	service := trustyServer.Service(auth.ServiceName).(*auth.Service)
+	require.NotNil(t, service)
+
+	admin := identity.NewIdentity("trusty-admin", "denis@trusty.com", "9223372036854775807")
+	subject := fmt.Sprintf("group:okta/rb%d", time.Now().UnixNano())
+
+	create := func(req *v1.CreateRoleBindingRequest) *httptest.ResponseRecorder {
+		js, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodPost, v1.PathForAuthRoleBindings, bytes.NewReader(js))
+		require.NoError(t, err)
+		r = identity.WithTestIdentity(r, admin)
+
+		service.CreateRoleBindingHandler()(w, r, nil)
+		return w
+	}
+
+	w := create(&v1.CreateRoleBindingRequest{Role: "trusty admin", Subject: subject})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = create(&v1.CreateRoleBindingRequest{Role: "trusty-admin"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the subject must have type prefix
+	w = create(&v1.CreateRoleBindingRequest{Role: "trusty-admin", Subject: "denis@trusty.com"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = create(&v1.CreateRoleBindingRequest{Role: "trusty-admin", Subject: subject})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var created v1.RoleBindingResponse
+	require.NoError(t, marshal.Decode(w.Body, &created))
+	assert.Equal(t, "trusty-admin", created.RoleBinding.Role)
+	assert.Equal(t, subject, created.RoleBinding.Subject)
+
+	t.Run("list", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, v1.PathForAuthRoleBindings, nil)
+		require.NoError(t, err)
+		r = identity.WithTestIdentity(r, admin)
+
+		service.RoleBindingsHandler()(w, r, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var res v1.RoleBindingsResponse
+		require.NoError(t, marshal.Decode(w.Body, &res))
+		found := false
+		for _, b := range res.RoleBindings {
+			if b.ID == created.RoleBinding.ID {
+				found = true
+			}
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodDelete, v1.PathForAuthRoleBinding, nil)
+		require.NoError(t, err)
+		r = identity.WithTestIdentity(r, admin)
+
+		service.RemoveRoleBindingHandler()(w, r, rest.Params{{Key: "id", Value: "invalid"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		service.RemoveRoleBindingHandler()(w, r, rest.Params{{Key: "id", Value: created.RoleBinding.ID}})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		service.RemoveRoleBindingHandler()(w, r, rest.Params{{Key: "id", Value: created.RoleBinding.ID}})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/go-phorce/dolly/rest"
+	"github.com/go-phorce/dolly/xhttp/httperror"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/go-phorce/dolly/xhttp/marshal"
+	"github.com/juju/errors"
+)
+
+const (
+	evtSessionRevoked      = "session_revoked"
+	evtSessionsRevoked     = "sessions_revoked"
+	evtUserSessionsRevoked = "user_sessions_revoked"
+)
+
+// SessionsHandler returns active sessions of the caller
+func (s *Service) SessionsHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
+		idn := identity.FromRequest(r).Identity()
+		userID, herr := callerID(idn)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+
+		list, err := s.db.GetUserSessions(r.Context(), userID)
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to get sessions: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		marshal.WriteJSON(w, r, &v1.SessionsResponse{
+			Sessions: toSessionsDto(list, currentSessionID(idn)),
+		})
+	}
+}
+
+// RevokeSessionHandler revokes the session of the caller, to log out the device
+func (s *Service) RevokeSessionHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p rest.Params) {
+		idn := identity.FromRequest(r).Identity()
+		userID, herr := callerID(idn)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+
+		id, err := model.ID(p.ByName("id"))
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithInvalidParam("invalid session ID"))
+			return
+		}
+
+		session, err := s.db.RevokeSession(r.Context(), userID, id, time.Now())
+		if err != nil {
+			if errors.IsNotFound(err) {
+				marshal.WriteJSON(w, r, httperror.WithNotFound("session not found"))
+			} else {
+				marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to revoke session: %s", err.Error()).WithCause(err))
+			}
+			return
+		}
+
+		s.server.Audit(
+			ServiceName,
+			evtSessionRevoked,
+			idn.Name(),
+			session.DeviceID,
+			0,
+			fmt.Sprintf("UserID=%d, session=%d", userID, id),
+		)
+
+		dto := session.ToDto()
+		dto.Current = session.ID == currentSessionID(idn)
+		marshal.WriteJSON(w, r, &v1.SessionResponse{Session: dto})
+	}
+}
+
+// RevokeSessionsHandler revokes all sessions of the caller, to log out everywhere
+func (s *Service) RevokeSessionsHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
+		idn := identity.FromRequest(r).Identity()
+		userID, herr := callerID(idn)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+
+		list, err := s.db.RevokeUserSessions(r.Context(), userID, time.Now())
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to revoke sessions: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		s.server.Audit(
+			ServiceName,
+			evtSessionsRevoked,
+			idn.Name(),
+			identity.FromRequest(r).CorrelationID(),
+			0,
+			fmt.Sprintf("UserID=%d, revoked=%d", userID, len(list)),
+		)
+
+		marshal.WriteJSON(w, r, &v1.SessionsResponse{
+			Sessions: toSessionsDto(list, currentSessionID(idn)),
+		})
+	}
+}
+
+// UserSessionsHandler returns active sessions of the user,
+// the access is restricted to admins by authz configuration
+func (s *Service) UserSessionsHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p rest.Params) {
+		user, herr := s.sessionsUser(r, p)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+
+		list, err := s.db.GetUserSessions(r.Context(), user.ID)
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to get sessions: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		marshal.WriteJSON(w, r, &v1.SessionsResponse{
+			Sessions: model.ToSessionsDto(list),
+		})
+	}
+}
+
+// RevokeUserSessionsHandler revokes all sessions of the user to force log out,
+// the access is restricted to admins by authz configuration
+func (s *Service) RevokeUserSessionsHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p rest.Params) {
+		user, herr := s.sessionsUser(r, p)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+
+		list, err := s.db.RevokeUserSessions(r.Context(), user.ID, time.Now())
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to revoke sessions: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		s.server.Audit(
+			ServiceName,
+			evtUserSessionsRevoked,
+			identity.FromRequest(r).Identity().Name(),
+			identity.FromRequest(r).CorrelationID(),
+			0,
+			fmt.Sprintf("UserID=%d, email=%s, revoked=%d", user.ID, user.Email, len(list)),
+		)
+
+		marshal.WriteJSON(w, r, &v1.SessionsResponse{
+			Sessions: model.ToSessionsDto(list),
+		})
+	}
+}
+
+// sessionsUser returns the user specified by user_id parameter,
+// the caller must be an authenticated user
+func (s *Service) sessionsUser(r *http.Request, p rest.Params) (*model.User, *httperror.Error) {
+	if _, herr := callerID(identity.FromRequest(r).Identity()); herr != nil {
+		return nil, herr
+	}
+
+	userID, err := model.ID(p.ByName("user_id"))
+	if err != nil {
+		return nil, httperror.WithInvalidParam("invalid user ID")
+	}
+
+	user, err := s.db.GetUser(r.Context(), userID)
+	if err != nil {
+		return nil, httperror.WithNotFound("user not found").WithCause(err)
+	}
+	return user, nil
+}
+
+// callerID returns ID of the authenticated user
+func callerID(idn identity.Identity) (uint64, *httperror.Error) {
+	userID, err := model.ID(idn.UserID())
+	if err != nil {
+		return 0, httperror.WithForbidden("the caller is not an authenticated user")
+	}
+	return userID, nil
+}
+
+// currentSessionID returns the session ID of the caller's access token,
+// or 0 if the identity was not issued by JWT
+func currentSessionID(idn identity.Identity) uint64 {
+	claims, ok := idn.UserInfo().(*jwtgo.StandardClaims)
+	if !ok || claims == nil {
+		return 0
+	}
+	id, err := strconv.ParseUint(claims.Id, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func toSessionsDto(list []*model.Session, current uint64) []*v1.Session {
+	res := model.ToSessionsDto(list)
+	for i, session := range list {
+		res[i].Current = session.ID == current
+	}
+	return res
+}
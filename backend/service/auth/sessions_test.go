@@ -0,0 +1,83 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/ekspand/trusty/backend/service/auth"
+	"github.com/go-phorce/dolly/rest"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/go-phorce/dolly/xhttp/marshal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionsHandlers(t *testing.T) {
+	service := trustyServer.Service(auth.ServiceName).(*auth.Service)
+	require.NotNil(t, service)
+
+	guest := identity.NewIdentity("guest", "10.0.0.1", "")
+	user := identity.NewIdentity("authenticated_jwt", "denis@trusty.com", "9223372036854775807")
+
+	t.Run("guest", func(t *testing.T) {
+		for _, h := range []rest.Handle{
+			service.SessionsHandler(),
+			service.RevokeSessionsHandler(),
+			service.RevokeSessionHandler(),
+			service.UserSessionsHandler(),
+			service.RevokeUserSessionsHandler(),
+		} {
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(http.MethodGet, v1.PathForAuthSessions, nil)
+			require.NoError(t, err)
+			r = identity.WithTestIdentity(r, guest)
+
+			h(w, r, rest.Params{{Key: "id", Value: "1"}, {Key: "user_id", Value: "1"}})
+			assert.Equal(t, http.StatusForbidden, w.Code)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, v1.PathForAuthSessions, nil)
+		require.NoError(t, err)
+		r = identity.WithTestIdentity(r, user)
+
+		service.SessionsHandler()(w, r, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var res v1.SessionsResponse
+		require.NoError(t, marshal.Decode(w.Body, &res))
+		assert.Empty(t, res.Sessions)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodDelete, v1.PathForAuthSession, nil)
+		require.NoError(t, err)
+		r = identity.WithTestIdentity(r, user)
+
+		service.RevokeSessionHandler()(w, r, rest.Params{{Key: "id", Value: "invalid"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		service.RevokeSessionHandler()(w, r, rest.Params{{Key: "id", Value: "1"}})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("user", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodDelete, v1.PathForAuthUserSessions, nil)
+		require.NoError(t, err)
+		r = identity.WithTestIdentity(r, user)
+
+		service.RevokeUserSessionsHandler()(w, r, rest.Params{{Key: "user_id", Value: "invalid"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		service.RevokeUserSessionsHandler()(w, r, rest.Params{{Key: "user_id", Value: "1"}})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/go-phorce/dolly/xhttp/httperror"
+)
+
+const (
+	// stateCookieName specifies the cookie that binds OAuth state to the browser
+	stateCookieName = "trusty_oauth"
+	// stateCookieExpiry specifies the time to complete the login with the provider
+	stateCookieExpiry = 10 * time.Minute
+)
+
+// setStateCookie binds the login to the browser,
+// the nonce of the state and PKCE verifier are kept in HttpOnly cookie,
+// and must be presented with the callback
+func setStateCookie(w http.ResponseWriter, nonce, verifier string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookieName,
+		Value:    nonce + "." + verifier,
+		Path:     v1.PathForAuth,
+		MaxAge:   int(stateCookieExpiry.Seconds()),
+		Secure:   true,
+		HttpOnly: true,
+		// the cookie must be sent with the redirect from the provider
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// clearStateCookie removes the state cookie, it can be used only once
+func clearStateCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookieName,
+		Path:     v1.PathForAuth,
+		MaxAge:   -1,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// parseCallback returns code and state parameters of OAuth callback,
+// and PKCE verifier of the login started in the same browser
+func parseCallback(w http.ResponseWriter, r *http.Request) (string, *v1.AuthState, string, *httperror.Error) {
+	code, ok := r.URL.Query()["code"]
+	if !ok || len(code) != 1 || code[0] == "" {
+		return "", nil, "", httperror.WithInvalidRequest("missing code parameter")
+	}
+
+	state, ok := r.URL.Query()["state"]
+	if !ok || len(state) != 1 || state[0] == "" {
+		return "", nil, "", httperror.WithInvalidRequest("missing state parameter")
+	}
+
+	js, err := base64.RawURLEncoding.DecodeString(state[0])
+	if err != nil {
+		return "", nil, "", httperror.WithInvalidRequest("invalid state parameter: %s", err.Error())
+	}
+
+	var oauthStatus v1.AuthState
+	if err = json.Unmarshal(js, &oauthStatus); err != nil {
+		return "", nil, "", httperror.WithInvalidRequest("failed to decode state parameter: %s", err.Error())
+	}
+
+	cookie, err := r.Cookie(stateCookieName)
+	if err != nil {
+		return "", nil, "", httperror.WithForbidden("the login was not started in this browser")
+	}
+	clearStateCookie(w)
+
+	nonce, verifier := cookie.Value, ""
+	if i := strings.IndexByte(nonce, '.'); i >= 0 {
+		nonce, verifier = nonce[:i], nonce[i+1:]
+	}
+	if oauthStatus.Nonce == "" ||
+		subtle.ConstantTimeCompare([]byte(nonce), []byte(oauthStatus.Nonce)) != 1 {
+		return "", nil, "", httperror.WithForbidden("the login was not started in this browser")
+	}
+
+	return code[0], &oauthStatus, verifier, nil
+}
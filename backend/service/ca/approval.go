@@ -0,0 +1,164 @@
+package ca
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/go-phorce/dolly/xlog"
+	"github.com/juju/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// queueApproval creates a pending request in the approval queue,
+// instead of signing the certificate
+func (s *Service) queueApproval(ctx context.Context, ca *authority.Issuer, policy *authority.ApprovalPolicy, req *pb.SignCertificateRequest, san []string) (*pb.CertificateResponse, error) {
+	csrv, err := csr.ParseRequestPEM([]byte(req.Request))
+	if err != nil {
+		return nil, v1.NewError(codes.InvalidArgument, "invalid request: %s", err.Error())
+	}
+
+	contextID, caller := s.policyCaller(ctx)
+	origin := callerOrigin(ctx)
+
+	if len(san) == 0 {
+		san = nil
+	}
+	a, err := s.db.CreateApproval(ctx, &model.Approval{
+		OrgID:             req.OrgId,
+		Profile:           req.Profile,
+		IssuerLabel:       ca.Label(),
+		Subject:           csrv.Subject.String(),
+		SAN:               san,
+		Request:           req.Request,
+		Requester:         caller.Name,
+		ClientIP:          origin.ClientIP,
+		RequestID:         origin.RequestID,
+		Labels:            req.Labels,
+		RequiredApprovals: policy.GetApprovals(),
+		ApproverRoles:     policy.Approvers,
+		ExpiresAt:         time.Now().Add(policy.GetExpiry()),
+	})
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "failed to create approval",
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.Internal, "failed to create approval")
+	}
+
+	logger.KV(xlog.NOTICE,
+		"status", "queued for approval",
+		"id", a.ID,
+		"profile", a.Profile,
+		"subject", a.Subject,
+	)
+
+	s.server.Audit(
+		ServiceName,
+		evtApprovalRequested,
+		caller.Name,
+		contextID,
+		0,
+		fmt.Sprintf("id=%d, profile=%q, issuer=%q, org_id=%d, subject=%q, san=%q, approvals=%d",
+			a.ID, a.Profile, a.IssuerLabel, a.OrgID, a.Subject, strings.Join(a.SAN, ","), a.RequiredApprovals),
+	)
+
+	return &pb.CertificateResponse{
+		Approval: a.ToDTO(),
+	}, nil
+}
+
+// signApproved issues the certificate for the approved request
+func (s *Service) signApproved(ctx context.Context, id uint64) (*pb.CertificateResponse, error) {
+	a, err := s.db.ClaimApproval(ctx, id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			a, err = s.db.GetApproval(ctx, id)
+			if err == nil {
+				return nil, v1.NewError(codes.FailedPrecondition, "the request is %s", a.Status)
+			}
+			if errors.IsNotFound(err) {
+				return nil, v1.NewError(codes.NotFound, "approval not found: %d", id)
+			}
+		}
+		logger.KV(xlog.ERROR,
+			"status", "failed to claim approval",
+			"id", id,
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.Internal, "failed to claim approval")
+	}
+
+	ca, err := s.Authority().GetIssuerByProfileAndLabel(a.Profile, a.IssuerLabel)
+	if err != nil {
+		s.releaseApproval(ctx, a.ID)
+		return nil, v1.NewError(codes.FailedPrecondition, err.Error())
+	}
+
+	san := a.SAN
+	if len(san) == 0 {
+		san = nil
+	}
+	mcert, err := s.issueCertificate(ctx, ca, &pb.SignCertificateRequest{
+		RequestFormat: pb.EncodingFormat_PEM,
+		Request:       a.Request,
+		Profile:       a.Profile,
+		IssuerLabel:   a.IssuerLabel,
+		OrgId:         a.OrgID,
+		Labels:        a.Labels,
+	}, san, requestOrigin{
+		Requester: a.Requester,
+		ClientIP:  a.ClientIP,
+		RequestID: a.RequestID,
+	})
+	if err != nil {
+		s.releaseApproval(ctx, a.ID)
+		return nil, err
+	}
+
+	a, err = s.db.IssueApproval(ctx, a.ID, mcert.ID)
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "failed to update approval",
+			"id", id,
+			"certificate_id", mcert.ID,
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.Internal, "failed to update approval")
+	}
+
+	contextID, caller := s.policyCaller(ctx)
+	s.server.Audit(
+		ServiceName,
+		evtApprovalIssued,
+		caller.Name,
+		contextID,
+		0,
+		fmt.Sprintf("id=%d, profile=%q, issuer=%q, subject=%q, requester=%q, certificate_id=%d",
+			a.ID, a.Profile, a.IssuerLabel, a.Subject, a.Requester, mcert.ID),
+	)
+
+	dto := a.ToDTO()
+	dto.Certificate = mcert.ToDTO()
+
+	return &pb.CertificateResponse{
+		Certificate: dto.Certificate,
+		Approval:    dto,
+	}, nil
+}
+
+// releaseApproval returns the claimed request to approved,
+// after the certificate failed to be issued
+func (s *Service) releaseApproval(ctx context.Context, id uint64) {
+	_, err := s.db.ReleaseApproval(ctx, id)
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "failed to release approval",
+			"id", id,
+			"err", errors.Details(err))
+	}
+}
@@ -3,19 +3,27 @@ package ca
 import (
 	"context"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
 	v1 "github.com/ekspand/trusty/api/v1"
 	pb "github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/authority"
 	"github.com/ekspand/trusty/internal/db"
 	"github.com/ekspand/trusty/internal/db/model"
 	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/go-phorce/dolly/algorithms/guid"
+	"github.com/go-phorce/dolly/algorithms/slices"
 	"github.com/go-phorce/dolly/metrics"
+	"github.com/go-phorce/dolly/xhttp/header"
+	"github.com/go-phorce/dolly/xhttp/identity"
 	"github.com/go-phorce/dolly/xlog"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/juju/errors"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 )
 
 var (
@@ -29,16 +37,23 @@ func (s *Service) ProfileInfo(ctx context.Context, req *pb.CertProfileInfoReques
 		return nil, v1.NewError(codes.InvalidArgument, "missing profile parameter")
 	}
 
-	ca, err := s.ca.GetIssuerByProfile(req.Profile)
+	a := s.Authority()
+	ca, err := a.GetIssuerByProfile(req.Profile)
 	if err != nil {
 		logger.Warningf("api=ProfileInfo, reason=no_issuer, profile=%q", req.Profile)
 		return nil, v1.NewError(codes.NotFound, "profile not found: %s", req.Profile)
 	}
 
-	label := strings.ToLower(req.Label)
-	if label != "" && label != strings.ToLower(ca.Label()) {
-		return nil, v1.NewError(codes.NotFound, "profile %q is served by %s issuer",
-			req.Profile, ca.Label())
+	if req.Label != "" {
+		ca, err = a.GetIssuerByProfileAndLabel(req.Profile, req.Label)
+		if err != nil {
+			var labels []string
+			for _, issuer := range a.IssuersByProfile(req.Profile) {
+				labels = append(labels, issuer.Label())
+			}
+			return nil, v1.NewError(codes.NotFound, "profile %q is served by %s issuer",
+				req.Profile, strings.Join(labels, ","))
+		}
 	}
 
 	profile := ca.Profile(req.Profile)
@@ -77,7 +92,25 @@ func (s *Service) ProfileInfo(ctx context.Context, req *pb.CertProfileInfoReques
 
 // Issuers returns the issuing CAs
 func (s *Service) Issuers(context.Context, *empty.Empty) (*pb.IssuersInfoResponse, error) {
-	issuers := s.ca.Issuers()
+	return issuersInfo(s.Authority()), nil
+}
+
+// ReloadConfig reloads the CA configuration and returns the issuing CAs
+func (s *Service) ReloadConfig(ctx context.Context, _ *empty.Empty) (*pb.IssuersInfoResponse, error) {
+	var caller string
+	if callerCtx := identity.FromContext(ctx); callerCtx != nil {
+		caller = callerCtx.Identity().String()
+	}
+
+	ca, err := s.Reload(ctx, caller)
+	if err != nil {
+		return nil, v1.NewError(codes.FailedPrecondition, "failed to reload configuration: %s", err.Error())
+	}
+	return issuersInfo(ca), nil
+}
+
+func issuersInfo(ca *authority.Authority) *pb.IssuersInfoResponse {
+	issuers := ca.Issuers()
 
 	res := &pb.IssuersInfoResponse{
 		Issuers: make([]*pb.IssuerInfo, len(issuers)),
@@ -90,14 +123,33 @@ func (s *Service) Issuers(context.Context, *empty.Empty) (*pb.IssuersInfoRespons
 			Intermediates: bundle.CACertsPEM,
 			Root:          bundle.RootCertPEM,
 			Label:         issuer.Label(),
+			State:         issuerState(issuer.State()),
+			Alternates:    issuer.AlternatesPEM(),
+			TrustDomain:   issuer.TrustDomain(),
+			JwtKeyId:      issuer.JWTKeyID(),
+			JwtPublicKey:  issuer.JWTPublicKeyPEM(),
+			SshPublicKey:  issuer.SSHAuthorizedKey(),
 		}
 	}
 
-	return res, nil
+	return res
+}
+
+func issuerState(state string) pb.IssuerState {
+	switch state {
+	case authority.IssuerStateRetiring:
+		return pb.IssuerState_RETIRING
+	case authority.IssuerStateRetired:
+		return pb.IssuerState_RETIRED
+	}
+	return pb.IssuerState_ACTIVE
 }
 
 // SignCertificate returns the certificate
 func (s *Service) SignCertificate(ctx context.Context, req *pb.SignCertificateRequest) (*pb.CertificateResponse, error) {
+	if req != nil && req.ApprovalId != 0 {
+		return s.signApproved(ctx, req.ApprovalId)
+	}
 	if req == nil || req.Profile == "" {
 		return nil, v1.NewError(codes.InvalidArgument, "missing profile")
 	}
@@ -107,23 +159,100 @@ func (s *Service) SignCertificate(ctx context.Context, req *pb.SignCertificateRe
 	if req.RequestFormat != pb.EncodingFormat_PEM {
 		return nil, v1.NewError(codes.InvalidArgument, "unsupported request_format: %v", req.RequestFormat)
 	}
+	if err := model.Labels(req.Labels).Validate(); err != nil {
+		return nil, v1.NewError(codes.InvalidArgument, err.Error())
+	}
 
-	ca, err := s.ca.GetIssuerByProfile(req.Profile)
+	a := s.Authority()
+	ca, err := a.GetIssuerByProfile(req.Profile)
 	if err != nil {
 		return nil, v1.NewError(codes.InvalidArgument, err.Error())
 	}
 
-	label := req.IssuerLabel
-	if label != "" && label != ca.Label() {
-		msg := fmt.Sprintf("%q issuer does not support the request profile: %q", label, req.Profile)
-		return nil, v1.NewError(codes.InvalidArgument, msg)
+	if req.IssuerLabel != "" {
+		ca, err = a.GetIssuerByProfileAndLabel(req.Profile, req.IssuerLabel)
+		if err != nil {
+			return nil, v1.NewError(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	if err = s.checkIssuancePolicy(ctx, ca, req, req.San); err != nil {
+		return nil, err
+	}
+
+	san, err := s.checkWebhook(ctx, ca, req)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.StringSlicesEqual(san, req.San) {
+		// the webhook replaced SAN, the policy is evaluated on the final list
+		if err = s.checkIssuancePolicy(ctx, ca, req, san); err != nil {
+			return nil, err
+		}
+	}
+
+	if profile := ca.Profile(req.Profile); profile != nil && profile.Approval != nil {
+		return s.queueApproval(ctx, ca, profile.Approval, req, san)
+	}
+
+	mcert, err := s.issueCertificate(ctx, ca, req, san, callerOrigin(ctx))
+	if err != nil {
+		return nil, err
+	}
 
+	res := &pb.CertificateResponse{
+		Certificate: mcert.ToDTO(),
 	}
 
+	return res, nil
+}
+
+// requestOrigin describes the caller who requested the certificate
+type requestOrigin struct {
+	Requester string
+	ClientIP  string
+	RequestID string
+}
+
+// callerOrigin returns the origin of the request from the context
+func callerOrigin(ctx context.Context) requestOrigin {
+	var origin requestOrigin
+	if callerCtx := identity.FromContext(ctx); callerCtx != nil {
+		origin.RequestID = callerCtx.CorrelationID()
+		origin.ClientIP = callerCtx.ClientIP()
+		if idn := callerCtx.Identity(); idn != nil {
+			origin.Requester = idn.Name()
+		}
+	}
+	if origin.RequestID == "" {
+		// gRPC requests provide the correlation ID in metadata, if any
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if vals := md.Get(header.XCorrelationID); len(vals) > 0 && len(vals[0]) <= 64 {
+				origin.RequestID = vals[0]
+			}
+		}
+		if origin.RequestID == "" {
+			origin.RequestID = guid.MustCreate()
+		}
+	}
+	if origin.ClientIP == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			origin.ClientIP = p.Addr.String()
+			if host, _, err := net.SplitHostPort(origin.ClientIP); err == nil {
+				origin.ClientIP = host
+			}
+		}
+	}
+	return origin
+}
+
+// issueCertificate signs and registers the certificate
+// with the request metadata
+func (s *Service) issueCertificate(ctx context.Context, ca *authority.Issuer, req *pb.SignCertificateRequest, san []string, origin requestOrigin) (*model.Certificate, error) {
 	cr := csr.SignRequest{
 		Request: req.Request,
 		Profile: req.Profile,
-		SAN:     req.San,
+		SAN:     san,
 	}
 
 	cert, pem, err := ca.Sign(cr)
@@ -142,6 +271,17 @@ func (s *Service) SignCertificate(ctx context.Context, req *pb.SignCertificateRe
 	metrics.IncrCounter(keyForCertIssued, 1, tags...)
 
 	mcert := model.NewCertificate(cert, req.OrgId, req.Profile, string(pem), ca.PEM())
+	mcert.Requester = origin.Requester
+	mcert.ClientIP = origin.ClientIP
+	mcert.RequestID = origin.RequestID
+	mcert.Request = req.Request
+	if len(san) > 0 {
+		mcert.SAN = san
+	}
+	if len(req.Labels) > 0 {
+		mcert.Labels = req.Labels
+	}
+
 	mcert, err = s.db.RegisterCertificate(ctx, mcert)
 	if err != nil {
 		logger.KV(xlog.ERROR,
@@ -154,13 +294,12 @@ func (s *Service) SignCertificate(ctx context.Context, req *pb.SignCertificateRe
 		"status", "signed certificate",
 		"id", mcert.ID,
 		"subject", mcert.Subject,
+		"requester", mcert.Requester,
+		"client_ip", mcert.ClientIP,
+		"request_id", mcert.RequestID,
 	)
 
-	res := &pb.CertificateResponse{
-		Certificate: mcert.ToDTO(),
-	}
-
-	return res, nil
+	return mcert, nil
 }
 
 // PublishCrls returns published CRLs
@@ -168,7 +307,10 @@ func (s *Service) PublishCrls(ctx context.Context, req *pb.PublishCrlsRequest) (
 	logger.KV(xlog.TRACE, "ikid", req.Ikid)
 
 	res := &pb.CrlsResponse{}
-	for _, issuer := range s.ca.Issuers() {
+	for _, issuer := range s.Authority().Issuers() {
+		if issuer.State() == authority.IssuerStateRetired {
+			continue
+		}
 		if req.Ikid == "" || req.Ikid == issuer.SubjectKID() {
 			crl, err := s.createGenericCRL(ctx, issuer)
 			if err != nil {
@@ -184,7 +326,8 @@ func (s *Service) PublishCrls(ctx context.Context, req *pb.PublishCrlsRequest) (
 	return res, nil
 }
 
-// GetCertificate returns Certificate
+// GetCertificate returns Certificate,
+// the request metadata is stripped, if the caller is not allowed to view it
 func (s *Service) GetCertificate(ctx context.Context, in *pb.GetCertificateRequest) (*pb.CertificateResponse, error) {
 	var crt *model.Certificate
 	var err error
@@ -203,6 +346,7 @@ func (s *Service) GetCertificate(ctx context.Context, in *pb.GetCertificateReque
 	res := &pb.CertificateResponse{
 		Certificate: crt.ToDTO(),
 	}
+	s.requestMetadataFilter(ctx)(res.Certificate)
 	return res, nil
 }
 
@@ -238,9 +382,15 @@ func (s *Service) RevokeCertificate(ctx context.Context, in *pb.RevokeCertificat
 	return res, nil
 }
 
-// ListCertificates returns stream of Certificates
+// ListCertificates returns stream of Certificates,
+// the request metadata is stripped, if the caller is not allowed to view it
 func (s *Service) ListCertificates(ctx context.Context, in *pb.ListByIssuerRequest) (*pb.CertificatesResponse, error) {
-	list, err := s.db.ListCertificates(ctx, in.Ikid, int(in.Limit), in.After)
+	selector, err := listSelector(in)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.db.ListCertificates(ctx, in.Ikid, selector, int(in.Limit), in.After)
 	if err != nil {
 		logger.KV(xlog.ERROR,
 			"request", in,
@@ -251,12 +401,21 @@ func (s *Service) ListCertificates(ctx context.Context, in *pb.ListByIssuerReque
 	res := &pb.CertificatesResponse{
 		List: list.ToDTO(),
 	}
+	filter := s.requestMetadataFilter(ctx)
+	for _, crt := range res.List {
+		filter(crt)
+	}
 	return res, nil
 }
 
 // ListRevokedCertificates returns stream of Revoked Certificates
 func (s *Service) ListRevokedCertificates(ctx context.Context, in *pb.ListByIssuerRequest) (*pb.RevokedCertificatesResponse, error) {
-	list, err := s.db.ListRevokedCertificates(ctx, in.Ikid, int(in.Limit), in.After)
+	selector, err := listSelector(in)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.db.ListRevokedCertificates(ctx, in.Ikid, selector, int(in.Limit), in.After)
 	if err != nil {
 		logger.KV(xlog.ERROR,
 			"request", in,
@@ -270,6 +429,82 @@ func (s *Service) ListRevokedCertificates(ctx context.Context, in *pb.ListByIssu
 	return res, nil
 }
 
+// listSelector returns the label selector of the list request
+func listSelector(in *pb.ListByIssuerRequest) (model.LabelSelector, error) {
+	selector, err := model.ParseLabelSelector(in.LabelSelector)
+	if err != nil {
+		return nil, v1.NewError(codes.InvalidArgument, err.Error())
+	}
+	if in.Ikid == "" && len(selector) == 0 {
+		return nil, v1.NewError(codes.InvalidArgument, "missing ikid or label_selector")
+	}
+	return selector, nil
+}
+
+// UpdateCertificateLabels updates the labels of the certificate
+func (s *Service) UpdateCertificateLabels(ctx context.Context, in *pb.UpdateCertificateLabelsRequest) (*pb.CertificateResponse, error) {
+	if len(in.Labels) == 0 && len(in.Remove) == 0 {
+		return nil, v1.NewError(codes.InvalidArgument, "missing labels to update")
+	}
+	if err := model.Labels(in.Labels).Validate(); err != nil {
+		return nil, v1.NewError(codes.InvalidArgument, err.Error())
+	}
+	for _, key := range in.Remove {
+		if err := model.ValidateLabelKey(key); err != nil {
+			return nil, v1.NewError(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	id := in.Id
+	if id == 0 {
+		crt, err := s.db.GetCertificateBySKID(ctx, in.Skid)
+		if err != nil {
+			logger.KV(xlog.ERROR,
+				"request", in,
+				"err", errors.Details(err),
+			)
+			return nil, v1.NewError(codes.NotFound, "unable to find certificate")
+		}
+		id = crt.ID
+	}
+
+	crt, err := s.db.UpdateCertificateLabels(ctx, id, in.Labels, in.Remove)
+	if err != nil {
+		switch {
+		case errors.IsNotFound(err):
+			return nil, v1.NewError(codes.NotFound, "unable to find certificate")
+		case errors.IsNotValid(err):
+			return nil, v1.NewError(codes.InvalidArgument, errors.Cause(err).Error())
+		}
+		logger.KV(xlog.ERROR,
+			"request", in,
+			"err", errors.Details(err),
+		)
+		return nil, v1.NewError(codes.Internal, "unable to update labels")
+	}
+
+	var contextID, caller string
+	if callerCtx := identity.FromContext(ctx); callerCtx != nil {
+		contextID = callerCtx.CorrelationID()
+		if idn := callerCtx.Identity(); idn != nil {
+			caller = idn.Name()
+		}
+	}
+	s.server.Audit(
+		ServiceName,
+		evtCertLabelsUpdated,
+		caller,
+		contextID,
+		0,
+		fmt.Sprintf("id=%d, skid=%s, subject=%q, labels=%q, removed=%q",
+			crt.ID, crt.SKID, crt.Subject, model.Labels(in.Labels).String(), strings.Join(in.Remove, ",")),
+	)
+
+	return &pb.CertificateResponse{
+		Certificate: crt.ToDTO(),
+	}, nil
+}
+
 // Db returns DB
 // Used in Unittests
 func (s *Service) Db() db.CertsDb {
@@ -2,15 +2,22 @@ package ca
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 
+	v1 "github.com/ekspand/trusty/api/v1"
 	pb "github.com/ekspand/trusty/api/v1/pb"
 	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/internal/config"
 	"github.com/ekspand/trusty/internal/db"
 	"github.com/ekspand/trusty/internal/db/model"
 	"github.com/ekspand/trusty/pkg/gserver"
+	"github.com/ekspand/trusty/pkg/jwt"
 	"github.com/go-phorce/dolly/rest"
 	"github.com/go-phorce/dolly/tasks"
 	"github.com/go-phorce/dolly/xlog"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
 	"github.com/juju/errors"
 	"google.golang.org/grpc"
 )
@@ -20,12 +27,47 @@ const ServiceName = "ca"
 
 var logger = xlog.NewPackageLogger("github.com/ekspand/trusty/backend/service", "ca")
 
+const (
+	evtConfigReloaded     = "CAConfigReloaded"
+	evtConfigReloadFailed = "CAConfigReloadFailed"
+	evtTimestampIssued    = "TimestampIssued"
+	evtDigestSigned       = "DigestSigned"
+	evtKeylessIssued      = "KeylessCertificateIssued"
+	evtJWTSVIDIssued      = "JWTSVIDIssued"
+	evtSSHCertIssued      = "SSHCertificateIssued"
+	evtSSHCertRevoked     = "SSHCertificateRevoked"
+	evtKRLPublished       = "KRLPublished"
+
+	evtIssuancePolicyDenied = "IssuancePolicyDenied"
+	evtWebhookApproved      = "WebhookApproved"
+	evtWebhookDenied        = "WebhookDenied"
+	evtWebhookFailed        = "WebhookFailed"
+	evtApprovalRequested    = "ApprovalRequested"
+	evtApprovalIssued       = "ApprovalIssued"
+	evtCertLabelsUpdated    = "CertificateLabelsUpdated"
+)
+
 // Service defines the Status service
 type Service struct {
 	server    *gserver.Server
-	ca        *authority.Authority
+	cfg       *config.Configuration
+	crypto    *cryptoprov.Crypto
 	db        db.CertsDb
+	orgs      db.OrgsDb
 	scheduler tasks.Scheduler
+	jwt       jwt.Parser
+	// sessions verifies login sessions of JWT presented for keyless signing
+	sessions *db.SessionVerifier
+
+	lock sync.RWMutex
+	ca   *authority.Authority
+
+	// reloadLock serializes Reload, triggered by SIGHUP or ReloadConfig
+	reloadLock sync.Mutex
+
+	webhooksLock sync.Mutex
+	// webhooks provides the clients by issuer and profile name
+	webhooks map[string]*webhookClient
 }
 
 // Factory returns a factory of the service
@@ -34,12 +76,19 @@ func Factory(server *gserver.Server) interface{} {
 		logger.Panic("status.Factory: invalid parameter")
 	}
 
-	return func(ca *authority.Authority, db db.CertsDb, scheduler tasks.Scheduler) {
+	return func(cfg *config.Configuration, crypto *cryptoprov.Crypto, ca *authority.Authority, certsDb db.CertsDb, orgs db.OrgsDb, scheduler tasks.Scheduler, jwt jwt.Parser) {
 		svc := &Service{
 			server:    server,
+			cfg:       cfg,
+			crypto:    crypto,
 			ca:        ca,
-			db:        db,
+			db:        certsDb,
+			orgs:      orgs,
 			scheduler: scheduler,
+			jwt:       jwt,
+		}
+		if server.Configuration().IdentityMap.JWT.Sessions {
+			svc.sessions = db.NewSessionVerifier(orgs)
 		}
 
 		server.AddService(svc)
@@ -63,25 +112,113 @@ func (s *Service) Close() {
 
 // RegisterRoute adds the Status API endpoints to the overall URL router
 func (s *Service) RegisterRoute(r rest.Router) {
+	r.POST(v1.PathForTSA, s.timestamp())
 }
 
 // RegisterGRPC registers gRPC handler
 func (s *Service) RegisterGRPC(r *grpc.Server) {
 	pb.RegisterCAServiceServer(r, s)
+	pb.RegisterSigningServiceServer(r, s)
+	pb.RegisterSPIFFEServiceServer(r, s)
+	pb.RegisterSSHServiceServer(r, s)
 }
 
 // OnStarted is called when the server started and
 // is ready to serve requests
 func (s *Service) OnStarted() error {
-	err := s.registerIssuers(context.Background())
+	err := s.registerIssuers(context.Background(), s.Authority())
 	if err != nil {
 		return errors.Trace(err)
 	}
 	return nil
 }
 
-func (s *Service) registerIssuers(ctx context.Context) error {
-	for _, ca := range s.ca.Issuers() {
+// OnReload is called when the server received a signal to reload configuration
+func (s *Service) OnReload() error {
+	_, err := s.Reload(context.Background(), "SIGHUP")
+	return errors.Trace(err)
+}
+
+// Authority returns the current Authority
+func (s *Service) Authority() *authority.Authority {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.ca
+}
+
+// Reload loads the CA configuration into a new Authority,
+// registers its issuers and replaces the current Authority.
+// If the configuration fails to load, the current Authority is kept.
+func (s *Service) Reload(ctx context.Context, caller string) (*authority.Authority, error) {
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	ca, err := s.loadAuthority()
+	if err == nil {
+		err = s.registerIssuers(ctx, ca)
+	}
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "failed to reload CA configuration",
+			"config", s.cfg.Authority,
+			"err", errors.Details(err))
+
+		s.server.Audit(
+			ServiceName,
+			evtConfigReloadFailed,
+			caller,
+			"",
+			0,
+			fmt.Sprintf("config=%q, err=%q", s.cfg.Authority, err.Error()),
+		)
+		return nil, errors.Trace(err)
+	}
+
+	s.lock.Lock()
+	s.ca = ca
+	s.lock.Unlock()
+
+	// the clients are created for the profiles of the new configuration
+	s.webhooksLock.Lock()
+	s.webhooks = nil
+	s.webhooksLock.Unlock()
+
+	issuers := ca.Issuers()
+	labels := make([]string, len(issuers))
+	for i, issuer := range issuers {
+		labels[i] = issuer.Label()
+	}
+
+	logger.KV(xlog.NOTICE,
+		"status", "reloaded CA configuration",
+		"config", s.cfg.Authority,
+		"issuers", labels)
+
+	s.server.Audit(
+		ServiceName,
+		evtConfigReloaded,
+		caller,
+		"",
+		0,
+		fmt.Sprintf("config=%q, issuers=[%s]", s.cfg.Authority, strings.Join(labels, ",")),
+	)
+	return ca, nil
+}
+
+func (s *Service) loadAuthority() (*authority.Authority, error) {
+	caCfg, err := authority.LoadConfig(s.cfg.Authority)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ca, err := authority.NewAuthority(caCfg, s.crypto)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ca, nil
+}
+
+func (s *Service) registerIssuers(ctx context.Context, ca *authority.Authority) error {
+	for _, ca := range ca.Issuers() {
 		bundle := ca.Bundle()
 		mcert := model.NewCertificate(bundle.Cert, 0, "ca", bundle.CertPEM, bundle.CACertsPEM)
 
@@ -1,33 +1,128 @@
 package ca_test
 
 import (
+	"bytes"
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"testing"
 	"time"
 
+	v1 "github.com/ekspand/trusty/api/v1"
 	"github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/authority"
 	"github.com/ekspand/trusty/backend/service/ca"
+	"github.com/ekspand/trusty/backend/service/ra"
 	"github.com/ekspand/trusty/backend/trustymain"
 	"github.com/ekspand/trusty/client"
 	"github.com/ekspand/trusty/client/embed"
+	"github.com/ekspand/trusty/internal/appcontainer"
 	"github.com/ekspand/trusty/internal/config"
+	"github.com/ekspand/trusty/internal/db"
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/ekspand/trusty/pkg/certpolicy"
+	"github.com/ekspand/trusty/pkg/cms"
 	"github.com/ekspand/trusty/pkg/csr"
 	"github.com/ekspand/trusty/pkg/gserver"
 	"github.com/ekspand/trusty/pkg/inmemcrypto"
+	"github.com/ekspand/trusty/pkg/jwt"
+	"github.com/ekspand/trusty/pkg/tsa"
+	"github.com/ekspand/trusty/pkg/webhook"
 	"github.com/ekspand/trusty/tests/testutils"
+	"github.com/go-phorce/dolly/audit"
+	"github.com/go-phorce/dolly/rest"
+	"github.com/go-phorce/dolly/xhttp/header"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
 	"github.com/juju/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 )
 
 var (
 	trustyServer    *gserver.Server
+	trustyCfg       *config.Configuration
 	authorityClient client.CAClient
+	jwtProvider     jwt.Provider
+	orgsDb          db.OrgsDb
+	auditor         = &mockAuditor{}
 )
 
+var rootCfg = &authority.Config{
+	Profiles: map[string]*authority.CertProfile{
+		"ROOT": {
+			Usage:  []string{"cert sign", "crl sign"},
+			Expiry: 5 * csr.OneYear,
+			CAConstraint: authority.CAConstraint{
+				IsCA:       true,
+				MaxPathLen: -1,
+			},
+		},
+		"timestamp": {
+			Usage:  []string{"digital signature", "timestamping"},
+			Expiry: csr.OneYear,
+		},
+	},
+}
+
+type auditEvent struct {
+	source, eventType, identity, contextID, message string
+}
+
+type mockAuditor struct {
+	lock   sync.Mutex
+	events []auditEvent
+}
+
+func (a *mockAuditor) Audit(source string, eventType string, identity string, contextID string, raftIndex uint64, message string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.events = append(a.events, auditEvent{source, eventType, identity, contextID, message})
+}
+
+func (a *mockAuditor) Close() error {
+	return nil
+}
+
+// last returns the last event of the type, or nil
+func (a *mockAuditor) last(eventType string) *auditEvent {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for i := len(a.events) - 1; i >= 0; i-- {
+		if a.events[i].eventType == eventType {
+			e := a.events[i]
+			return &e
+		}
+	}
+	return nil
+}
+
 const (
 	projFolder = "../../../"
 )
@@ -40,13 +135,26 @@ func TestMain(m *testing.M) {
 	if err != nil {
 		panic(errors.Trace(err))
 	}
+	trustyCfg = cfg
+
+	dir, err := ioutil.TempDir("", "ca-service")
+	if err != nil {
+		panic(errors.Trace(err))
+	}
+
+	webhookServer := httptest.NewServer(http.HandlerFunc(webhookHandler))
+
+	cfg.Authority, err = createAuthorityConfig(cfg.Authority, dir, webhookServer.URL)
+	if err != nil {
+		panic(errors.Trace(err))
+	}
 
 	httpAddr := testutils.CreateURLs("http", "")
 
 	for name, httpCfg := range cfg.HTTPServers {
 		switch name {
 		case ca.ServiceName:
-			httpCfg.Services = []string{ca.ServiceName}
+			httpCfg.Services = []string{ca.ServiceName, ra.ServiceName}
 			httpCfg.ListenURLs = []string{httpAddr}
 			httpCfg.Disabled = false
 		default:
@@ -60,6 +168,15 @@ func TestMain(m *testing.M) {
 		WithConfiguration(cfg).
 		WithSignal(sigs)
 
+	f := appcontainer.NewContainerFactory(app).
+		WithConfigurationProvider(func() (*config.Configuration, error) {
+			return cfg, nil
+		}).
+		WithAuditorProvider(func(*config.Configuration, appcontainer.CloseRegistrator) (audit.Auditor, error) {
+			return auditor, nil
+		})
+	app.WithContainerFactory(f.CreateContainerWithDependencies)
+
 	var wg sync.WaitGroup
 	startedCh := make(chan bool)
 
@@ -86,6 +203,15 @@ func TestMain(m *testing.M) {
 			}
 			authorityClient = embed.NewCAClient(trustyServer)
 
+			container, _ := app.Container()
+			err = container.Invoke(func(p jwt.Provider, orgs db.OrgsDb) {
+				jwtProvider = p
+				orgsDb = orgs
+			})
+			if err != nil {
+				panic(errors.Trace(err))
+			}
+
 			// Run the tests
 			rc = m.Run()
 
@@ -100,9 +226,326 @@ func TestMain(m *testing.M) {
 	// wait for stop
 	wg.Wait()
 
+	webhookServer.Close()
+	os.RemoveAll(dir)
 	os.Exit(rc)
 }
 
+// createAuthorityConfig returns the location of the authority configuration,
+// with the test issuers and profiles added to the configuration in file
+func createAuthorityConfig(file, dir, webhookURL string) (string, error) {
+	cfg, err := authority.LoadConfig(file)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	defprov := inmemcrypto.NewProvider()
+	cryptoProv, err := cryptoprov.New(defprov, nil)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	prov := csr.NewProvider(defprov)
+
+	rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &csr.CertificateRequest{
+		CommonName: "[TEST] Trusty Service Root CA",
+		KeyRequest: prov.NewKeyRequest("root", "ECDSA", 256, csr.SigningKey),
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	signer, err := authority.NewSignerFromPEM(cryptoProv, rootKey)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	root, err := authority.CreateIssuer(&authority.IssuerConfig{
+		Label:    "ROOT",
+		Profiles: rootCfg.Profiles,
+	}, rootPEM, nil, nil, signer)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	files := map[string][]byte{
+		"root.pem":     rootPEM,
+		"root-key.pem": rootKey,
+	}
+	location := func(name string) string {
+		return filepath.Join(dir, name)
+	}
+	// selfSigned creates the self-signed CA for the issuer
+	selfSigned := func(name, cn string) error {
+		certPEM, _, key, err := authority.NewRoot("ROOT", rootCfg, defprov, &csr.CertificateRequest{
+			CommonName: cn,
+			KeyRequest: prov.NewKeyRequest(name, "ECDSA", 256, csr.SigningKey),
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		files[name+".pem"] = certPEM
+		files[name+"-key.pem"] = key
+		return nil
+	}
+	// exportKey creates the key, that is not certified by the issuer
+	exportKey := func(name string) error {
+		_, key, _, _, err := prov.CreateRequestAndExportKey(&csr.CertificateRequest{
+			CommonName: name,
+			KeyRequest: prov.NewKeyRequest(name, "ECDSA", 256, csr.SigningKey),
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		files[name+"-key.pem"] = key
+		return nil
+	}
+
+	// the timestamp issuer
+	tsaCSR, tsaKey, _, _, err := prov.CreateRequestAndExportKey(&csr.CertificateRequest{
+		CommonName: "[TEST] Trusty TSA",
+		KeyRequest: prov.NewKeyRequest("tsa", "ECDSA", 256, csr.SigningKey),
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	_, files["tsa.pem"], err = root.Sign(csr.SignRequest{
+		Request: string(tsaCSR),
+		Profile: "timestamp",
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	files["tsa-key.pem"] = tsaKey
+
+	// the codesign issuers, with and without the code signing key
+	if err = selfSigned("codesign", "[TEST] Trusty Codesign CA"); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err = exportKey("codesign-signer"); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err = selfSigned("keyless", "[TEST] Trusty Keyless CA"); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	// the spiffe issuer with JWT-SVID key
+	if err = selfSigned("spiffe", "[TEST] Trusty SPIFFE CA"); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err = exportKey("spiffe-jwt"); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	// the ssh issuer
+	if err = selfSigned("ssh", "[TEST] Trusty SSH CA"); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	cfg.Authority.Issuers = append(cfg.Authority.Issuers,
+		authority.IssuerConfig{
+			Label:          "trusty.tsa",
+			Type:           authority.IssuerTypeTimestamp,
+			CertFile:       location("tsa.pem"),
+			KeyFile:        location("tsa-key.pem"),
+			RootBundleFile: location("root.pem"),
+			TSA: &authority.TSAConfig{
+				Policy:   csr.OID{1, 3, 6, 1, 4, 1, 59765, 1, 1},
+				Accuracy: time.Second,
+			},
+		},
+		authority.IssuerConfig{
+			Label:    "trusty.codesign",
+			Type:     authority.IssuerTypeCodesign,
+			CertFile: location("codesign.pem"),
+			KeyFile:  location("codesign-key.pem"),
+			CodeSign: &authority.CodeSignConfig{
+				KeyFile: location("codesign-signer-key.pem"),
+			},
+		},
+		authority.IssuerConfig{
+			Label:    "trusty.keyless",
+			Type:     authority.IssuerTypeCodesign,
+			CertFile: location("keyless.pem"),
+			KeyFile:  location("keyless-key.pem"),
+		},
+		authority.IssuerConfig{
+			Label:    "trusty.spiffe",
+			Type:     authority.IssuerTypeSpiffe,
+			CertFile: location("spiffe.pem"),
+			KeyFile:  location("spiffe-key.pem"),
+			SPIFFE: &authority.SPIFFEConfig{
+				TrustDomain: "trusty.ekspand.com",
+				JWTKeyFile:  location("spiffe-jwt-key.pem"),
+				JWTExpiry:   5 * time.Minute,
+			},
+		},
+		authority.IssuerConfig{
+			Label:    "trusty.ssh",
+			Type:     authority.IssuerTypeSSH,
+			CertFile: location("ssh.pem"),
+			KeyFile:  location("ssh-key.pem"),
+		},
+	)
+
+	cfg.Profiles[authority.DefaultCodeSignProfile] = &authority.CertProfile{
+		Description:  "Short-lived certificate profile for SigningService",
+		IssuerLabel:  "trusty.codesign",
+		IssuerLabels: []string{"trusty.keyless"},
+		Expiry:       csr.Duration(10 * time.Minute),
+		Backdate:     csr.Duration(time.Minute),
+		Usage:        []string{"digital signature", "code signing"},
+	}
+	cfg.Profiles[authority.DefaultKeylessProfile] = &authority.CertProfile{
+		Description:  "Short-lived certificate profile for SigningService.SignKeyless",
+		IssuerLabel:  "trusty.keyless",
+		IssuerLabels: []string{"trusty.codesign"},
+		Expiry:       csr.Duration(10 * time.Minute),
+		Backdate:     csr.Duration(time.Minute),
+		AllowedEmail: `^.*@ekspand\.com$`,
+		Usage:        []string{"digital signature", "code signing"},
+		Keyless:      true,
+	}
+	cfg.Profiles["webhook_server"] = &authority.CertProfile{
+		Description: "server profile, approved by the webhook",
+		IssuerLabel: "trusty.svc",
+		Expiry:      csr.Duration(5 * time.Minute),
+		Backdate:    csr.Duration(30 * time.Minute),
+		Usage:       []string{"signing", "key encipherment", "server auth"},
+		Webhook: &webhook.Config{
+			URL: webhookURL,
+		},
+	}
+	cfg.Profiles["webhook_policy"] = &authority.CertProfile{
+		Description: "server profile, approved by the webhook and the issuance policy",
+		IssuerLabel: "trusty.svc",
+		Expiry:      csr.Duration(5 * time.Minute),
+		Backdate:    csr.Duration(30 * time.Minute),
+		Usage:       []string{"signing", "key encipherment", "server auth"},
+		IssuancePolicy: certpolicy.Rules{
+			{
+				Name:       "api-san",
+				Expression: "san.exists(n, n == 'api.trusty.local')",
+				Message:    "api.trusty.local must be in SAN",
+			},
+		},
+		Webhook: &webhook.Config{
+			URL: webhookURL,
+		},
+	}
+	cfg.Profiles["webhook_fail_open"] = &authority.CertProfile{
+		Description: "server profile, issued if the webhook fails",
+		IssuerLabel: "trusty.svc",
+		Expiry:      csr.Duration(5 * time.Minute),
+		Backdate:    csr.Duration(30 * time.Minute),
+		Usage:       []string{"signing", "key encipherment", "server auth"},
+		Webhook: &webhook.Config{
+			URL:      webhookURL,
+			FailOpen: true,
+		},
+	}
+	cfg.Profiles["approval_server"] = &authority.CertProfile{
+		Description: "server profile, issued after two approvals of administrators",
+		IssuerLabel: "trusty.svc",
+		Expiry:      csr.Duration(5 * time.Minute),
+		Backdate:    csr.Duration(30 * time.Minute),
+		Usage:       []string{"signing", "key encipherment", "server auth"},
+		Approval: &authority.ApprovalPolicy{
+			Approvers: []string{"trusty-admin"},
+			Approvals: 2,
+			Expiry:    csr.Duration(time.Hour),
+		},
+	}
+	cfg.Profiles["ssh_user"] = &authority.CertProfile{
+		Description: "OpenSSH user certificate profile",
+		IssuerLabel: "trusty.ssh",
+		Expiry:      csr.Duration(16 * time.Hour),
+		Backdate:    csr.Duration(5 * time.Minute),
+		SSH: &authority.SSHProfile{
+			CertType:    authority.SSHCertTypeUser,
+			Principals:  []string{"${USER}", "${ORG}-admin"},
+			UserDomains: []string{"ekspand.com"},
+			Extensions: map[string]string{
+				"permit-pty": "",
+			},
+		},
+	}
+	cfg.Profiles["ssh_host"] = &authority.CertProfile{
+		Description: "OpenSSH host certificate profile",
+		IssuerLabel: "trusty.ssh",
+		Expiry:      csr.Duration(720 * time.Hour),
+		SSH: &authority.SSHProfile{
+			CertType:   authority.SSHCertTypeHost,
+			Principals: []string{"*.trusty.local"},
+		},
+	}
+
+	for name, content := range files {
+		err = ioutil.WriteFile(location(name), content, 0600)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+
+	js, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	err = ioutil.WriteFile(location("ca-config.json"), js, 0600)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return location("ca-config.json"), nil
+}
+
+// webhookHandler decides by the common name of CSR:
+// deny.* and reject.* are denied, fail.* fails,
+// and SAN of the other requests is narrowed to the common name
+func webhookHandler(w http.ResponseWriter, r *http.Request) {
+	req := new(webhook.Request)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	cn := req.CSR.CommonName
+	res := &webhook.Response{Allowed: true}
+	switch {
+	case strings.HasPrefix(cn, "fail."):
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	case strings.HasPrefix(cn, "deny."):
+		res.Allowed = false
+		res.Reason = fmt.Sprintf("%s is not allowed to request %s", req.Caller.Name, cn)
+	case strings.HasPrefix(cn, "reject."):
+		res.Allowed = false
+	default:
+		res.SAN = []string{cn}
+	}
+
+	w.Header().Set(header.ContentType, header.ApplicationJSON)
+	json.NewEncoder(w).Encode(res)
+}
+
+// spiffeContext returns the context of the caller,
+// authenticated with X.509-SVID of the SPIFFE ID
+func spiffeContext(spiffeID string) context.Context {
+	id, err := url.Parse(spiffeID)
+	if err != nil {
+		panic(errors.Trace(err))
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{URIs: []*url.URL{id}}},
+			},
+		},
+	})
+}
+
+// callerContext returns the context with the caller's identity
+func callerContext(role, name, userID string) context.Context {
+	return identity.AddToContext(context.Background(),
+		identity.NewRequestContext(identity.NewIdentity(role, name, userID)))
+}
+
 func TestReady(t *testing.T) {
 	assert.True(t, trustyServer.IsReady())
 }
@@ -113,6 +556,28 @@ func TestIssuers(t *testing.T) {
 	assert.NotEmpty(t, res.Issuers)
 }
 
+func TestReloadConfig(t *testing.T) {
+	svc := trustyServer.Service(ca.ServiceName).(*ca.Service)
+	current := svc.Authority()
+
+	res, err := authorityClient.ReloadConfig(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, res.Issuers)
+	assert.NotSame(t, current, svc.Authority())
+
+	current = svc.Authority()
+	caCfg := trustyCfg.Authority
+	trustyCfg.Authority = "notfound.yaml"
+	_, err = authorityClient.ReloadConfig(context.Background())
+	trustyCfg.Authority = caCfg
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to reload configuration")
+	assert.Same(t, current, svc.Authority())
+
+	require.NoError(t, svc.OnReload())
+	assert.NotSame(t, current, svc.Authority())
+}
+
 func TestProfileInfo(t *testing.T) {
 	tcases := []struct {
 		req *pb.CertProfileInfoRequest
@@ -157,6 +622,15 @@ func TestSignCertificate(t *testing.T) {
 	require.Error(t, err)
 	assert.Equal(t, "unsupported request_format: PKCS7", err.Error())
 
+	_, err = authorityClient.SignCertificate(context.Background(), &pb.SignCertificateRequest{
+		Profile:       "test",
+		Request:       "abcd",
+		RequestFormat: pb.EncodingFormat_PEM,
+		Labels:        map[string]string{"Team": "security"},
+	})
+	require.Error(t, err)
+	assert.Equal(t, "invalid label key: \"Team\"", err.Error())
+
 	_, err = authorityClient.SignCertificate(context.Background(), &pb.SignCertificateRequest{
 		Profile:       "test",
 		Request:       "abcd",
@@ -180,29 +654,157 @@ func TestSignCertificate(t *testing.T) {
 		RequestFormat: pb.EncodingFormat_PEM,
 	})
 	require.Error(t, err)
+	assert.Equal(t, "invalid request: unable to parse PEM", err.Error())
+
+	_, err = authorityClient.SignCertificate(context.Background(), &pb.SignCertificateRequest{
+		Profile:       "test_client",
+		Request:       "abcd",
+		RequestFormat: pb.EncodingFormat_PEM,
+	})
+	require.Error(t, err)
 	assert.Equal(t, "failed to sign certificate request", err.Error())
 
-	res, err := authorityClient.SignCertificate(context.Background(), &pb.SignCertificateRequest{
+	_, err = authorityClient.SignCertificate(context.Background(), &pb.SignCertificateRequest{
 		Profile:       "test_server",
 		Request:       string(generateCSR()),
+		San:           []string{"*.trusty.local"},
+		RequestFormat: pb.EncodingFormat_PEM,
+	})
+	require.Error(t, err)
+	assert.Equal(t, "issuance policy \"no-wildcards\": wildcard DNS names are not allowed", err.Error())
+
+	csr := string(generateCSR())
+	res, err := authorityClient.SignCertificate(context.Background(), &pb.SignCertificateRequest{
+		Profile:       "test_server",
+		Request:       csr,
 		RequestFormat: pb.EncodingFormat_PEM,
+		Labels:        map[string]string{"team": "security"},
 	})
 	require.NoError(t, err)
+	assert.Equal(t, csr, res.Certificate.Request)
+	assert.Equal(t, map[string]string{"team": "security"}, res.Certificate.Labels)
+	assert.NotEmpty(t, res.Certificate.RequestId)
 
 	// Signed cert must be registered in DB
 
 	svc := trustyServer.Service("ca").(*ca.Service)
-	crt, err := svc.GetCertificate(context.Background(),
+	adminCtx := callerContext("trusty-admin", "admin@ekspand.com", "")
+	crt, err := svc.GetCertificate(adminCtx,
 		&pb.GetCertificateRequest{Id: res.Certificate.Id})
 	require.NoError(t, err)
 	assert.Equal(t, res.Certificate.String(), crt.Certificate.String())
 
-	crt, err = svc.GetCertificate(context.Background(),
+	crt, err = svc.GetCertificate(adminCtx,
 		&pb.GetCertificateRequest{Skid: res.Certificate.Skid})
 	require.NoError(t, err)
 	assert.Equal(t, res.Certificate.String(), crt.Certificate.String())
 }
 
+func TestCertificateRequestMetadata(t *testing.T) {
+	svc := trustyServer.Service(ca.ServiceName).(*ca.Service)
+
+	res, err := svc.SignCertificate(callerContext("trusty-client", "build-agent", ""), &pb.SignCertificateRequest{
+		Profile:       "test_server",
+		Request:       string(generateCSR()),
+		RequestFormat: pb.EncodingFormat_PEM,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, res.Certificate.RequestId)
+
+	list := func(ctx context.Context) *pb.Certificate {
+		lres, err := svc.ListCertificates(ctx, &pb.ListByIssuerRequest{
+			Ikid:  res.Certificate.Ikid,
+			Limit: 1,
+			After: res.Certificate.Id - 1,
+		})
+		require.NoError(t, err)
+		require.Len(t, lres.List, 1)
+		require.Equal(t, res.Certificate.Id, lres.List[0].Id)
+		return lres.List[0]
+	}
+
+	t.Run("admin", func(t *testing.T) {
+		ctx := callerContext("trusty-admin", "admin@ekspand.com", "")
+		crt, err := svc.GetCertificate(ctx, &pb.GetCertificateRequest{Id: res.Certificate.Id})
+		require.NoError(t, err)
+		assert.Equal(t, "build-agent", crt.Certificate.Requester)
+		assert.Equal(t, res.Certificate.RequestId, crt.Certificate.RequestId)
+		assert.Equal(t, res.Certificate.Request, crt.Certificate.Request)
+
+		assert.Equal(t, "build-agent", list(ctx).Requester)
+	})
+
+	t.Run("other", func(t *testing.T) {
+		ctx := callerContext("trusty-client", "other-agent", "")
+		crt, err := svc.GetCertificate(ctx, &pb.GetCertificateRequest{Id: res.Certificate.Id})
+		require.NoError(t, err)
+		assert.Equal(t, res.Certificate.Pem, crt.Certificate.Pem)
+		assert.Empty(t, crt.Certificate.Requester)
+		assert.Empty(t, crt.Certificate.ClientIp)
+		assert.Empty(t, crt.Certificate.RequestId)
+		assert.Empty(t, crt.Certificate.Request)
+
+		assert.Empty(t, list(ctx).Requester)
+	})
+}
+
+func TestUpdateCertificateLabels(t *testing.T) {
+	ctx := context.Background()
+	res, err := authorityClient.SignCertificate(ctx, &pb.SignCertificateRequest{
+		Profile:       "test_server",
+		Request:       string(generateCSR()),
+		RequestFormat: pb.EncodingFormat_PEM,
+		Labels:        map[string]string{"team": "security", "env": "dev"},
+	})
+	require.NoError(t, err)
+
+	_, err = authorityClient.UpdateCertificateLabels(ctx, &pb.UpdateCertificateLabelsRequest{
+		Id: res.Certificate.Id,
+	})
+	require.Error(t, err)
+	assert.Equal(t, "missing labels to update", err.Error())
+
+	_, err = authorityClient.UpdateCertificateLabels(ctx, &pb.UpdateCertificateLabelsRequest{
+		Id:     res.Certificate.Id,
+		Remove: []string{"Team"},
+	})
+	require.Error(t, err)
+	assert.Equal(t, "invalid label key: \"Team\"", err.Error())
+
+	_, err = authorityClient.UpdateCertificateLabels(ctx, &pb.UpdateCertificateLabelsRequest{
+		Skid:   "notfound",
+		Labels: map[string]string{"env": "prod"},
+	})
+	require.Error(t, err)
+	assert.Equal(t, "unable to find certificate", err.Error())
+
+	updated, err := authorityClient.UpdateCertificateLabels(ctx, &pb.UpdateCertificateLabelsRequest{
+		Skid:   res.Certificate.Skid,
+		Labels: map[string]string{"env": "prod"},
+		Remove: []string{"team"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "prod"}, updated.Certificate.Labels)
+
+	_, err = authorityClient.ListCertificates(ctx, &pb.ListByIssuerRequest{})
+	require.Error(t, err)
+	assert.Equal(t, "missing ikid or label_selector", err.Error())
+
+	_, err = authorityClient.ListCertificates(ctx, &pb.ListByIssuerRequest{
+		LabelSelector: "Env=prod",
+	})
+	require.Error(t, err)
+	assert.Equal(t, "invalid selector \"Env=prod\": invalid label key: \"Env\"", err.Error())
+
+	list, err := authorityClient.ListCertificates(ctx, &pb.ListByIssuerRequest{
+		LabelSelector: "env=prod,!team",
+		After:         res.Certificate.Id - 1,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, list.List)
+	assert.Equal(t, res.Certificate.Id, list.List[0].Id)
+}
+
 func TestPublishCrls(t *testing.T) {
 	ctx := context.Background()
 	certRes, err := authorityClient.SignCertificate(ctx, &pb.SignCertificateRequest{
@@ -253,7 +855,7 @@ func TestE2E(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		crtRes, err := svc.GetCertificate(ctx,
+		crtRes, err := svc.GetCertificate(callerContext("trusty-ra", "ra", ""),
 			&pb.GetCertificateRequest{Skid: res.Certificate.Skid})
 		require.NoError(t, err)
 		assert.Equal(t, res.Certificate.String(), crtRes.Certificate.String())
@@ -329,6 +931,763 @@ func TestE2E(t *testing.T) {
 		"revoked:%d, count:%d, len:%d", revokedCount, certsCount, len(lRes.List))
 }
 
+func TestTimestamp(t *testing.T) {
+	svc := trustyServer.Service(ca.ServiceName).(*ca.Service)
+	router := rest.NewRouter(nil)
+	svc.RegisterRoute(router)
+
+	post := func(contentType, query string, body []byte) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, v1.PathForTSA+query, bytes.NewReader(body))
+		r.Header.Set(header.ContentType, contentType)
+		r = identity.WithTestIdentity(r, identity.NewIdentity("trusty-client", "build-agent", ""))
+		w := httptest.NewRecorder()
+		router.Handler().ServeHTTP(w, r)
+		return w
+	}
+
+	digest := sha256.Sum256([]byte("trusty"))
+	req, err := tsa.NewRequest(crypto.SHA256, digest[:], big.NewInt(42), true)
+	require.NoError(t, err)
+	query, err := req.Marshal()
+	require.NoError(t, err)
+
+	t.Run("content_type", func(t *testing.T) {
+		w := post("application/json", "", query)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "unsupported content type: \"application/json\"")
+	})
+
+	t.Run("too_large", func(t *testing.T) {
+		w := post(tsa.ContentTypeQuery, "", make([]byte, 64*1024+1))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "failed to read request")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		w := post(tsa.ContentTypeQuery, "", []byte("abcd"))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, tsa.ContentTypeReply, w.Header().Get(header.ContentType))
+
+		res, err := tsa.ParseResponse(w.Body.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, tsa.StatusRejection, res.Status)
+		assert.Equal(t, tsa.BadDataFormat, res.FailInfo)
+		assert.Nil(t, res.Token)
+	})
+
+	t.Run("issuer_not_found", func(t *testing.T) {
+		w := post(tsa.ContentTypeQuery, "?issuer=unknown", query)
+		res, err := tsa.ParseResponse(w.Body.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, tsa.StatusRejection, res.Status)
+		assert.Equal(t, tsa.BadRequest, res.FailInfo)
+		assert.Equal(t, []string{"timestamp issuer not found: unknown"}, res.StatusString)
+	})
+
+	t.Run("granted", func(t *testing.T) {
+		w := post(tsa.ContentTypeQuery, "?issuer=trusty.tsa", query)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, tsa.ContentTypeReply, w.Header().Get(header.ContentType))
+
+		res, err := tsa.ParseResponse(w.Body.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, tsa.StatusGranted, res.Status)
+		require.NotNil(t, res.Token)
+		assert.Equal(t, int64(42), res.Token.Info.Nonce.Int64())
+
+		issuer, err := svc.Authority().TimestampIssuer("trusty.tsa")
+		require.NoError(t, err)
+		require.NoError(t, res.Token.Verify(issuer.Bundle().Cert, digest[:]))
+
+		evt := auditor.last("TimestampIssued")
+		require.NotNil(t, evt)
+		assert.Equal(t, ca.ServiceName, evt.source)
+		assert.Equal(t, "trusty-client/build-agent", evt.identity)
+		assert.Contains(t, evt.message, "issuer=\"trusty.tsa\"")
+		assert.Contains(t, evt.message, "nonce=\"2a\"")
+	})
+}
+
+func TestSignDigest(t *testing.T) {
+	svc := trustyServer.Service(ca.ServiceName).(*ca.Service)
+	ctx := callerContext("trusty-client", "build-agent", "")
+	digest := sha256.Sum256([]byte("trusty"))
+
+	tcases := []struct {
+		ctx  context.Context
+		req  *pb.SignDigestRequest
+		code codes.Code
+		err  string
+	}{
+		{ctx, nil, codes.InvalidArgument, "missing request"},
+		{ctx, &pb.SignDigestRequest{HashAlgorithm: pb.HashAlgorithm(10)}, codes.InvalidArgument, "unsupported hash_algorithm: 10"},
+		{ctx, &pb.SignDigestRequest{}, codes.InvalidArgument, "missing digest or data"},
+		{ctx, &pb.SignDigestRequest{Data: make([]byte, 64*1024+1)}, codes.InvalidArgument, "data exceeds 65536 bytes, use digest"},
+		{ctx, &pb.SignDigestRequest{HashAlgorithm: pb.HashAlgorithm_SHA384, Digest: digest[:]}, codes.InvalidArgument, "invalid digest size: 32"},
+		{context.Background(), &pb.SignDigestRequest{Digest: digest[:]}, codes.Unauthenticated, "missing caller identity"},
+		{ctx, &pb.SignDigestRequest{Digest: digest[:], IssuerLabel: "trusty.svc"}, codes.InvalidArgument, "codesign issuer not found: trusty.svc"},
+		{ctx, &pb.SignDigestRequest{Digest: digest[:], IssuerLabel: "trusty.keyless"}, codes.FailedPrecondition, "issuer does not have code signing key: trusty.keyless"},
+		{ctx, &pb.SignDigestRequest{Digest: digest[:], Profile: "test_server"}, codes.Internal, "failed to sign"},
+	}
+	for _, tc := range tcases {
+		_, err := svc.SignDigest(tc.ctx, tc.req)
+		assertError(t, err, tc.code, tc.err)
+	}
+
+	for _, req := range []*pb.SignDigestRequest{
+		{Digest: digest[:]},
+		{Data: []byte("trusty"), IssuerLabel: "trusty.codesign"},
+	} {
+		res, err := svc.SignDigest(ctx, req)
+		require.NoError(t, err)
+
+		crt, err := certutil.ParseFromPEM([]byte(res.Certificate))
+		require.NoError(t, err)
+		assert.Equal(t, "build-agent", crt.Subject.CommonName)
+		assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}, crt.ExtKeyUsage)
+		assert.NotEmpty(t, res.Intermediates)
+
+		sd, err := cms.Parse(res.Signature)
+		require.NoError(t, err)
+		require.NoError(t, sd.Verify(crt, digest[:]))
+
+		evt := auditor.last("DigestSigned")
+		require.NotNil(t, evt)
+		assert.Equal(t, "build-agent", evt.identity)
+		assert.Contains(t, evt.message, "issuer=\"trusty.codesign\", profile=\"remote_codesign\"")
+		assert.Contains(t, evt.message, "digest="+hex.EncodeToString(digest[:]))
+
+		// the certificate must be registered in DB
+		_, err = svc.Db().GetCertificateBySKID(context.Background(), certutil.GetSubjectKeyID(crt))
+		require.NoError(t, err)
+	}
+}
+
+func TestSignKeyless(t *testing.T) {
+	svc := trustyServer.Service(ca.ServiceName).(*ca.Service)
+	ctx := context.Background()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	pub := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	proof := func(token string) []byte {
+		digest := sha256.Sum256([]byte(token))
+		sig, err := key.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.NoError(t, err)
+		return sig
+	}
+
+	user, err := orgsDb.LoginUser(ctx, &model.User{
+		Login: "keyless",
+		Email: "keyless@ekspand.com",
+		Name:  "Keyless",
+	})
+	require.NoError(t, err)
+
+	// the tokens are issued for login sessions of the user,
+	// the session ID is used as `jti`
+	newSession := func() string {
+		session, err := orgsDb.CreateSession(ctx, &model.Session{
+			UserID:    user.ID,
+			DeviceID:  "keyless",
+			ExpiresAt: time.Now().Add(time.Minute),
+		})
+		require.NoError(t, err)
+		return strconv.FormatUint(session.ID, 10)
+	}
+
+	audience := trustyServer.Configuration().IdentityMap.JWT.Audience
+	token, claims, err := jwtProvider.SignToken(newSession(), "denis@ekspand.com", audience, time.Minute)
+	require.NoError(t, err)
+	otherAudience, _, err := jwtProvider.SignToken(newSession(), "denis@ekspand.com", "other", time.Minute)
+	require.NoError(t, err)
+	notAllowed, _, err := jwtProvider.SignToken(newSession(), "denis@trusty.com", audience, time.Minute)
+	require.NoError(t, err)
+
+	revokedID := newSession()
+	revoked, _, err := jwtProvider.SignToken(revokedID, "denis@ekspand.com", audience, time.Minute)
+	require.NoError(t, err)
+	sessionID, err := strconv.ParseUint(revokedID, 10, 64)
+	require.NoError(t, err)
+	_, err = orgsDb.RevokeSession(ctx, user.ID, sessionID, time.Now())
+	require.NoError(t, err)
+	noSession, _, err := jwtProvider.SignToken("keyless-1", "denis@ekspand.com", audience, time.Minute)
+	require.NoError(t, err)
+
+	tcases := []struct {
+		req  *pb.SignKeylessRequest
+		code codes.Code
+		err  string
+	}{
+		{nil, codes.InvalidArgument, "missing request"},
+		{&pb.SignKeylessRequest{}, codes.InvalidArgument, "missing token"},
+		{&pb.SignKeylessRequest{Token: token}, codes.InvalidArgument, "missing public_key"},
+		{&pb.SignKeylessRequest{Token: token, PublicKey: pub}, codes.InvalidArgument, "missing proof"},
+		{&pb.SignKeylessRequest{Token: token, PublicKey: "abcd", Proof: proof(token)}, codes.InvalidArgument, "invalid public_key"},
+		{&pb.SignKeylessRequest{Token: "abcd", PublicKey: pub, Proof: proof("abcd")}, codes.Unauthenticated, "invalid token"},
+		{&pb.SignKeylessRequest{Token: otherAudience, PublicKey: pub, Proof: proof(otherAudience)}, codes.Unauthenticated, "invalid token"},
+		{&pb.SignKeylessRequest{Token: token, PublicKey: pub, Proof: proof(token), IssuerLabel: "trusty.svc"}, codes.InvalidArgument, "codesign issuer not found: trusty.svc"},
+		// the proof must be bound to the presented token
+		{&pb.SignKeylessRequest{Token: token, PublicKey: pub, Proof: proof(otherAudience)}, codes.InvalidArgument, "failed to sign certificate: invalid proof of possession"},
+		{&pb.SignKeylessRequest{Token: notAllowed, PublicKey: pub, Proof: proof(notAllowed)}, codes.InvalidArgument, "failed to sign certificate: Email does not match allowed list: denis@trusty.com"},
+		// the session of the token must be active
+		{&pb.SignKeylessRequest{Token: revoked, PublicKey: pub, Proof: proof(revoked)}, codes.Unauthenticated, "invalid token"},
+		{&pb.SignKeylessRequest{Token: noSession, PublicKey: pub, Proof: proof(noSession)}, codes.Unauthenticated, "invalid token"},
+		// long-lived code signing profile is not allowed for keyless
+		{&pb.SignKeylessRequest{Token: token, PublicKey: pub, Proof: proof(token), Profile: authority.DefaultCodeSignProfile}, codes.InvalidArgument, "failed to sign certificate: profile does not allow keyless signing: remote_codesign"},
+	}
+	for _, tc := range tcases {
+		_, err := svc.SignKeyless(ctx, tc.req)
+		assertError(t, err, tc.code, tc.err)
+	}
+
+	for _, label := range []string{"", "trusty.keyless"} {
+		res, err := svc.SignKeyless(ctx, &pb.SignKeylessRequest{
+			IssuerLabel: label,
+			Token:       token,
+			PublicKey:   pub,
+			Proof:       proof(token),
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.Intermediates)
+
+		crt, err := certutil.ParseFromPEM([]byte(res.Certificate))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"denis@ekspand.com"}, crt.EmailAddresses)
+		assert.Equal(t, key.Public(), crt.PublicKey)
+
+		evt := auditor.last("KeylessCertificateIssued")
+		require.NotNil(t, evt)
+		assert.Equal(t, "denis@ekspand.com", evt.identity)
+		assert.Contains(t, evt.message, fmt.Sprintf("token_issuer=%q, token_id=%q", claims.Issuer, claims.Id))
+
+		// the certificate must be registered in DB with the request metadata
+		mcert, err := svc.Db().GetCertificateBySKID(ctx, certutil.GetSubjectKeyID(crt))
+		require.NoError(t, err)
+		assert.Equal(t, "denis@ekspand.com", mcert.Requester)
+		assert.NotEmpty(t, mcert.RequestID)
+	}
+}
+
+func TestJWTSVID(t *testing.T) {
+	svc := trustyServer.Service(ca.ServiceName).(*ca.Service)
+	spiffeID := "spiffe://trusty.ekspand.com/ns/default/sa/workload"
+
+	tcases := []struct {
+		ctx  context.Context
+		req  *pb.JWTSVIDRequest
+		code codes.Code
+		err  string
+	}{
+		{spiffeContext(spiffeID), nil, codes.InvalidArgument, "missing audience"},
+		{spiffeContext(spiffeID), &pb.JWTSVIDRequest{}, codes.InvalidArgument, "missing audience"},
+		{callerContext("trusty-client", "workload", ""), &pb.JWTSVIDRequest{Audience: []string{"svc1"}}, codes.Unauthenticated, "caller must be authenticated with X.509-SVID"},
+		{spiffeContext("https://trusty.ekspand.com/workload"), &pb.JWTSVIDRequest{Audience: []string{"svc1"}}, codes.Unauthenticated, "caller must be authenticated with X.509-SVID"},
+		{spiffeContext("spiffe://other.com/workload"), &pb.JWTSVIDRequest{Audience: []string{"svc1"}}, codes.InvalidArgument, "JWT-SVID issuer not found for trust domain: other.com"},
+		{spiffeContext(spiffeID), &pb.JWTSVIDRequest{Audience: []string{"svc1"}, IssuerLabel: "trusty.svc"}, codes.InvalidArgument, "JWT-SVID issuer not found for trust domain: trusty.ekspand.com"},
+		{spiffeContext("spiffe://trusty.ekspand.com/"), &pb.JWTSVIDRequest{Audience: []string{"svc1"}}, codes.InvalidArgument, "failed to sign JWT-SVID: SPIFFE ID must have a path: spiffe://trusty.ekspand.com/"},
+	}
+	for _, tc := range tcases {
+		_, err := svc.SignJWTSVID(tc.ctx, tc.req)
+		assertError(t, err, tc.code, tc.err)
+	}
+
+	res, err := svc.SignJWTSVID(spiffeContext(spiffeID), &pb.JWTSVIDRequest{
+		Audience: []string{"svc1", "svc2"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, spiffeID, res.SpiffeId)
+	assert.NotEmpty(t, res.Token)
+	assert.True(t, res.ExpiresAt > time.Now().Unix())
+
+	evt := auditor.last("JWTSVIDIssued")
+	require.NotNil(t, evt)
+	assert.Equal(t, spiffeID, evt.identity)
+	assert.Equal(t, fmt.Sprintf("issuer=\"trusty.spiffe\", audience=\"svc1,svc2\", expires_at=%d", res.ExpiresAt), evt.message)
+
+	vcases := []struct {
+		req *pb.ValidateJWTSVIDRequest
+		err string
+	}{
+		{nil, "missing token"},
+		{&pb.ValidateJWTSVIDRequest{Audience: "svc1"}, "missing token"},
+		{&pb.ValidateJWTSVIDRequest{Token: res.Token}, "missing audience"},
+		{&pb.ValidateJWTSVIDRequest{Token: res.Token, Audience: "svc3"}, "invalid token: token audience does not match: svc3"},
+		{&pb.ValidateJWTSVIDRequest{Token: "abcd", Audience: "svc1"}, "invalid token: failed to verify token: invalid token format"},
+	}
+	for _, tc := range vcases {
+		_, err := svc.ValidateJWTSVID(context.Background(), tc.req)
+		assertError(t, err, codes.InvalidArgument, tc.err)
+	}
+
+	vres, err := svc.ValidateJWTSVID(context.Background(), &pb.ValidateJWTSVIDRequest{
+		Token:    res.Token,
+		Audience: "svc2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, spiffeID, vres.SpiffeId)
+	assert.Equal(t, []string{"svc1", "svc2"}, vres.Audience)
+	assert.Equal(t, res.ExpiresAt, vres.ExpiresAt)
+}
+
+func TestSSH(t *testing.T) {
+	svc := trustyServer.Service(ca.ServiceName).(*ca.Service)
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	authorizedKey := string(ssh.MarshalAuthorizedKey(sshPub))
+
+	ctx := callerContext("trusty-client", "denis@ekspand.com", "")
+
+	tcases := []struct {
+		ctx  context.Context
+		req  *pb.SignSSHKeyRequest
+		code codes.Code
+		err  string
+	}{
+		{ctx, nil, codes.InvalidArgument, "missing profile"},
+		{ctx, &pb.SignSSHKeyRequest{PublicKey: authorizedKey}, codes.InvalidArgument, "missing profile"},
+		{ctx, &pb.SignSSHKeyRequest{Profile: "ssh_user"}, codes.InvalidArgument, "missing public_key"},
+		{ctx, &pb.SignSSHKeyRequest{Profile: "ssh_user", PublicKey: "ssh-ed25519 invalid"}, codes.InvalidArgument, "invalid public_key"},
+		{context.Background(), &pb.SignSSHKeyRequest{Profile: "ssh_user", PublicKey: authorizedKey}, codes.Unauthenticated, "caller must be authenticated"},
+		{callerContext(identity.GuestRoleName, "denis@ekspand.com", ""), &pb.SignSSHKeyRequest{Profile: "ssh_user", PublicKey: authorizedKey}, codes.Unauthenticated, "caller must be authenticated"},
+		{ctx, &pb.SignSSHKeyRequest{Profile: "ssh_none", PublicKey: authorizedKey}, codes.InvalidArgument, "issuer not found for profile: ssh_none"},
+		{ctx, &pb.SignSSHKeyRequest{Profile: "ssh_user", PublicKey: authorizedKey, OrgId: 1000}, codes.PermissionDenied, "caller is not a member of org: 1000"},
+		{ctx, &pb.SignSSHKeyRequest{Profile: "ssh_user", PublicKey: authorizedKey, Principals: []string{"root"}}, codes.InvalidArgument, `failed to sign SSH certificate: principal is not allowed: "root"`},
+		{ctx, &pb.SignSSHKeyRequest{Profile: "ssh_user", PublicKey: authorizedKey, KeyId: "root"}, codes.InvalidArgument, `failed to sign SSH certificate: key_id must start with the caller's name: "root"`},
+		{ctx, &pb.SignSSHKeyRequest{Profile: "ssh_host", PublicKey: authorizedKey}, codes.InvalidArgument, "failed to sign SSH certificate: no principals allowed for denis@ekspand.com"},
+		{callerContext("trusty-client", "*", ""), &pb.SignSSHKeyRequest{Profile: "ssh_user", PublicKey: authorizedKey}, codes.InvalidArgument, "failed to sign SSH certificate: no principals allowed for *"},
+	}
+	for _, tc := range tcases {
+		_, err := svc.SignSSHKey(tc.ctx, tc.req)
+		assertError(t, err, tc.code, tc.err)
+	}
+
+	issuer, err := svc.Authority().GetIssuerByProfile("ssh_user")
+	require.NoError(t, err)
+	checker := &ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool {
+			return bytes.Equal(auth.Marshal(), issuer.SSHPublicKey().Marshal())
+		},
+		IsHostAuthority: func(auth ssh.PublicKey, address string) bool {
+			return bytes.Equal(auth.Marshal(), issuer.SSHPublicKey().Marshal())
+		},
+	}
+
+	res, err := svc.SignSSHKey(ctx, &pb.SignSSHKeyRequest{
+		Profile:   "ssh_user",
+		PublicKey: authorizedKey,
+		KeyId:     "denis@ekspand.com/laptop",
+	})
+	require.NoError(t, err)
+	c := res.Certificate
+	assert.Equal(t, issuer.SSHKeyID(), c.Ikid)
+	assert.Equal(t, "denis@ekspand.com/laptop", c.KeyId)
+	assert.Equal(t, authority.SSHCertTypeUser, c.CertType)
+	assert.Equal(t, []string{"denis"}, c.Principals)
+	assert.Equal(t, "ssh_user", c.Profile)
+
+	parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(c.Certificate))
+	require.NoError(t, err)
+	cert, ok := parsed.(*ssh.Certificate)
+	require.True(t, ok)
+	require.NoError(t, checker.CheckCert("denis", cert))
+	assert.Error(t, checker.CheckCert("root", cert))
+
+	evt := auditor.last("SSHCertificateIssued")
+	require.NotNil(t, evt)
+	assert.Equal(t, "denis@ekspand.com", evt.identity)
+	assert.Equal(t,
+		fmt.Sprintf(`id=%d, issuer="trusty.ssh", serial=%s, key_id="denis@ekspand.com/laptop", principals="denis"`, c.Id, c.SerialNumber),
+		evt.message)
+
+	host, err := svc.SignSSHKey(ctx, &pb.SignSSHKeyRequest{
+		Profile:    "ssh_host",
+		PublicKey:  authorizedKey,
+		Principals: []string{"web.trusty.local"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, authority.SSHCertTypeHost, host.Certificate.CertType)
+	assert.Equal(t, []string{"web.trusty.local"}, host.Certificate.Principals)
+
+	t.Run("revoke", func(t *testing.T) {
+		rcases := []struct {
+			req  *pb.RevokeSSHCertificateRequest
+			code codes.Code
+			err  string
+		}{
+			{nil, codes.InvalidArgument, "missing ikid"},
+			{&pb.RevokeSSHCertificateRequest{SerialNumber: c.SerialNumber}, codes.InvalidArgument, "missing ikid"},
+			{&pb.RevokeSSHCertificateRequest{Ikid: c.Ikid}, codes.InvalidArgument, "missing serial_number"},
+			{&pb.RevokeSSHCertificateRequest{Ikid: c.Ikid, SerialNumber: "1"}, codes.Internal, "unable to revoke SSH certificate"},
+		}
+		for _, tc := range rcases {
+			_, err := svc.RevokeSSHCertificate(ctx, tc.req)
+			assertError(t, err, tc.code, tc.err)
+		}
+
+		adminCtx := callerContext("trusty-admin", "admin@ekspand.com", "")
+		revoked, err := svc.RevokeSSHCertificate(adminCtx, &pb.RevokeSSHCertificateRequest{
+			Ikid:         c.Ikid,
+			SerialNumber: c.SerialNumber,
+			Reason:       pb.Reason_KEY_COMPROMISE,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, c.Id, revoked.Certificate.Id)
+		assert.Equal(t, pb.Reason_KEY_COMPROMISE, revoked.Certificate.Reason)
+		assert.NotNil(t, revoked.Certificate.RevokedAt)
+
+		evt := auditor.last("SSHCertificateRevoked")
+		require.NotNil(t, evt)
+		assert.Equal(t, "admin@ekspand.com", evt.identity)
+		assert.Equal(t,
+			fmt.Sprintf(`id=%d, ikid=%s, serial=%s, key_id="denis@ekspand.com/laptop", reason=KEY_COMPROMISE`, c.Id, c.Ikid, c.SerialNumber),
+			evt.message)
+	})
+
+	t.Run("krl", func(t *testing.T) {
+		_, err := svc.PublishKRL(ctx, &pb.PublishKRLRequest{IssuerLabel: "trusty.svc"})
+		assertError(t, err, codes.NotFound, "SSH issuer not found: trusty.svc")
+
+		lastVersion := uint64(0)
+		for _, label := range []string{"", "trusty.ssh"} {
+			res, err := svc.PublishKRL(ctx, &pb.PublishKRLRequest{IssuerLabel: label})
+			require.NoError(t, err)
+			require.Len(t, res.Krls, 1)
+			krl := res.Krls[0]
+			assert.Equal(t, issuer.SSHKeyID(), krl.Ikid)
+			assert.Equal(t, "trusty.ssh", krl.IssuerLabel)
+			assert.NotEmpty(t, krl.Krl)
+			// the KRLs published within the same second have different versions
+			assert.Greater(t, krl.Version, lastVersion)
+			lastVersion = krl.Version
+
+			evt := auditor.last("KRLPublished")
+			require.NotNil(t, evt)
+			assert.True(t, strings.HasPrefix(evt.message,
+				fmt.Sprintf(`issuer="trusty.ssh", ikid=%s, version=%d, revoked=`, krl.Ikid, krl.Version)),
+				evt.message)
+		}
+	})
+}
+
+func TestWebhook(t *testing.T) {
+	svc := trustyServer.Service(ca.ServiceName).(*ca.Service)
+	ctx := callerContext("trusty-client", "build-agent", "")
+
+	issuer, err := svc.Authority().GetIssuerByProfile("webhook_server")
+	require.NoError(t, err)
+	webhookURL := issuer.Profile("webhook_server").Webhook.URL
+
+	signRequest := func(profile, cn string, san ...string) *pb.SignCertificateRequest {
+		return &pb.SignCertificateRequest{
+			Profile:       profile,
+			Request:       string(generateServerCSR(cn, cn, "other.trusty.local")),
+			RequestFormat: pb.EncodingFormat_PEM,
+			San:           san,
+		}
+	}
+	auditMessage := func(profile, cn, details string) string {
+		return fmt.Sprintf("profile=%q, issuer=\"trusty.svc\", org_id=0, subject=\"CN=%s,OU=unit1,O=org1\", url=%q, %s",
+			profile, cn, webhookURL, details)
+	}
+
+	tcases := []struct {
+		cn      string
+		san     []string
+		code    codes.Code
+		err     string
+		evt     string
+		details string
+	}{
+		{
+			cn:      "deny.trusty.local",
+			code:    codes.PermissionDenied,
+			err:     "webhook: build-agent is not allowed to request deny.trusty.local",
+			evt:     "WebhookDenied",
+			details: `reason="build-agent is not allowed to request deny.trusty.local"`,
+		},
+		{
+			cn:      "reject.trusty.local",
+			code:    codes.PermissionDenied,
+			err:     "webhook: request is denied",
+			evt:     "WebhookDenied",
+			details: `reason="request is denied"`,
+		},
+		{
+			cn:      "fail.trusty.local",
+			code:    codes.Unavailable,
+			err:     "webhook is not available",
+			evt:     "WebhookFailed",
+			details: `fail_open=false, err="webhook returned status 500"`,
+		},
+		{
+			cn:      "web.trusty.local",
+			san:     []string{"other.trusty.local"},
+			code:    codes.Unavailable,
+			err:     "webhook is not available",
+			evt:     "WebhookFailed",
+			details: `fail_open=false, err="webhook returned SAN that was not requested: web.trusty.local"`,
+		},
+	}
+	for _, tc := range tcases {
+		_, err := svc.SignCertificate(ctx, signRequest("webhook_server", tc.cn, tc.san...))
+		assertError(t, err, tc.code, tc.err)
+
+		evt := auditor.last(tc.evt)
+		require.NotNil(t, evt)
+		assert.Equal(t, "build-agent", evt.identity)
+		assert.Equal(t, auditMessage("webhook_server", tc.cn, tc.details), evt.message)
+	}
+
+	_, err = svc.SignCertificate(ctx, &pb.SignCertificateRequest{
+		Profile:       "webhook_server",
+		Request:       "invalid",
+		RequestFormat: pb.EncodingFormat_PEM,
+	})
+	assertError(t, err, codes.InvalidArgument, "invalid request: unable to parse PEM")
+
+	t.Run("approved", func(t *testing.T) {
+		res, err := svc.SignCertificate(ctx, signRequest("webhook_server", "web.trusty.local"))
+		require.NoError(t, err)
+
+		crt, err := certutil.ParseFromPEM([]byte(res.Certificate.Pem))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"web.trusty.local"}, crt.DNSNames)
+
+		evt := auditor.last("WebhookApproved")
+		require.NotNil(t, evt)
+		assert.Equal(t, "build-agent", evt.identity)
+		assert.Equal(t, auditMessage("webhook_server", "web.trusty.local", `san="web.trusty.local"`), evt.message)
+	})
+
+	t.Run("fail_open", func(t *testing.T) {
+		res, err := svc.SignCertificate(ctx, signRequest("webhook_fail_open", "fail.trusty.local"))
+		require.NoError(t, err)
+
+		crt, err := certutil.ParseFromPEM([]byte(res.Certificate.Pem))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"fail.trusty.local", "other.trusty.local"}, crt.DNSNames)
+
+		evt := auditor.last("WebhookFailed")
+		require.NotNil(t, evt)
+		assert.Equal(t, auditMessage("webhook_fail_open", "fail.trusty.local", `fail_open=true, err="webhook returned status 500"`), evt.message)
+	})
+}
+
+func TestIssuancePolicy(t *testing.T) {
+	svc := trustyServer.Service(ca.ServiceName).(*ca.Service)
+	ctx := callerContext("trusty-client", "build-agent", "")
+
+	_, err := svc.SignCertificate(ctx, &pb.SignCertificateRequest{
+		Profile:       "test_server",
+		Request:       string(generateServerCSR("web.trusty.local", "web.trusty.local", "*.trusty.local")),
+		RequestFormat: pb.EncodingFormat_PEM,
+	})
+	assertError(t, err, codes.PermissionDenied, `issuance policy "no-wildcards": wildcard DNS names are not allowed`)
+
+	evt := auditor.last("IssuancePolicyDenied")
+	require.NotNil(t, evt)
+	assert.Equal(t, "build-agent", evt.identity)
+	assert.Equal(t,
+		`profile="test_server", issuer="trusty.svc", org_id=0, subject="CN=web.trusty.local,OU=unit1,O=org1", rule="no-wildcards", reason="wildcard DNS names are not allowed"`,
+		evt.message)
+
+	// SAN of the request is evaluated too
+	_, err = svc.SignCertificate(ctx, &pb.SignCertificateRequest{
+		Profile:       "test_server",
+		Request:       string(generateServerCSR("web.trusty.local", "web.trusty.local")),
+		RequestFormat: pb.EncodingFormat_PEM,
+		San:           []string{"*.trusty.local"},
+	})
+	assertError(t, err, codes.PermissionDenied, `issuance policy "no-wildcards": wildcard DNS names are not allowed`)
+
+	res, err := svc.SignCertificate(ctx, &pb.SignCertificateRequest{
+		Profile:       "test_server",
+		Request:       string(generateServerCSR("web.trusty.local", "web.trusty.local")),
+		RequestFormat: pb.EncodingFormat_PEM,
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, res.Certificate)
+}
+
+func TestIssuancePolicyWithWebhook(t *testing.T) {
+	svc := trustyServer.Service(ca.ServiceName).(*ca.Service)
+	ctx := callerContext("trusty-client", "build-agent", "")
+
+	// the requested SAN is allowed by the policy,
+	// but the webhook narrows SAN to CN, and the final list is denied
+	_, err := svc.SignCertificate(ctx, &pb.SignCertificateRequest{
+		Profile:       "webhook_policy",
+		Request:       string(generateServerCSR("web.trusty.local", "web.trusty.local", "api.trusty.local")),
+		RequestFormat: pb.EncodingFormat_PEM,
+		San:           []string{"web.trusty.local", "api.trusty.local"},
+	})
+	assertError(t, err, codes.PermissionDenied, `issuance policy "api-san": api.trusty.local must be in SAN`)
+
+	evt := auditor.last("IssuancePolicyDenied")
+	require.NotNil(t, evt)
+	assert.Contains(t, evt.message, `profile="webhook_policy"`)
+
+	// the SAN narrowed by the webhook is allowed by the policy
+	res, err := svc.SignCertificate(ctx, &pb.SignCertificateRequest{
+		Profile:       "webhook_policy",
+		Request:       string(generateServerCSR("api.trusty.local", "api.trusty.local", "web.trusty.local")),
+		RequestFormat: pb.EncodingFormat_PEM,
+		San:           []string{"api.trusty.local", "web.trusty.local"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, res.Certificate)
+
+	crt, err := certutil.ParseFromPEM([]byte(res.Certificate.Pem))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api.trusty.local"}, crt.DNSNames)
+}
+
+func TestApproval(t *testing.T) {
+	svc := trustyServer.Service(ca.ServiceName).(*ca.Service)
+	raSvc := trustyServer.Service(ra.ServiceName).(*ra.Service)
+
+	requesterCtx := callerContext("trusty-client", "build-agent", "")
+	admin1Ctx := callerContext("trusty-admin", "admin1@ekspand.com", "")
+	admin2Ctx := callerContext("trusty-admin", "admin2@ekspand.com", "")
+
+	res, err := svc.SignCertificate(requesterCtx, &pb.SignCertificateRequest{
+		Profile:       "approval_server",
+		Request:       string(generateServerCSR("approval.trusty.local", "approval.trusty.local")),
+		RequestFormat: pb.EncodingFormat_PEM,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, res.Certificate)
+	require.NotNil(t, res.Approval)
+
+	a := res.Approval
+	assert.Equal(t, pb.ApprovalStatus_PENDING, a.Status)
+	assert.Equal(t, "approval_server", a.Profile)
+	assert.Equal(t, "trusty.svc", a.IssuerLabel)
+	assert.Equal(t, "build-agent", a.Requester)
+	assert.Equal(t, uint32(2), a.RequiredApprovals)
+	assert.Equal(t, []string{"trusty-admin"}, a.ApproverRoles)
+
+	evt := auditor.last("ApprovalRequested")
+	require.NotNil(t, evt)
+	assert.Equal(t, "build-agent", evt.identity)
+	assert.Equal(t,
+		fmt.Sprintf(`id=%d, profile="approval_server", issuer="trusty.svc", org_id=0, subject="CN=approval.trusty.local,OU=unit1,O=org1", san="", approvals=2`, a.Id),
+		evt.message)
+
+	// the pending request can not be issued
+	_, err = svc.SignCertificate(admin1Ctx, &pb.SignCertificateRequest{ApprovalId: a.Id})
+	assertError(t, err, codes.FailedPrecondition, "the request is pending")
+
+	_, err = svc.SignCertificate(admin1Ctx, &pb.SignCertificateRequest{ApprovalId: a.Id + 1000000})
+	assertError(t, err, codes.NotFound, fmt.Sprintf("approval not found: %d", a.Id+1000000))
+
+	vcases := []struct {
+		ctx  context.Context
+		code codes.Code
+		err  string
+	}{
+		{requesterCtx, codes.PermissionDenied, "the caller is not an approver of the request"},
+		{callerContext("trusty-admin", "build-agent", ""), codes.PermissionDenied, "the requester can not approve or deny own request"},
+	}
+	for _, tc := range vcases {
+		_, err = raSvc.Approve(tc.ctx, &pb.ApprovalDecisionRequest{Id: a.Id})
+		assertError(t, err, tc.code, tc.err)
+	}
+
+	vres, err := raSvc.Approve(admin1Ctx, &pb.ApprovalDecisionRequest{Id: a.Id, Reason: "LGTM"})
+	require.NoError(t, err)
+	assert.Equal(t, pb.ApprovalStatus_PENDING, vres.Approval.Status)
+	assert.Nil(t, vres.Approval.Certificate)
+
+	evt = auditor.last("ApprovalApproved")
+	require.NotNil(t, evt)
+	assert.Equal(t, "admin1@ekspand.com", evt.identity)
+	assert.Equal(t,
+		fmt.Sprintf(`id=%d, profile="approval_server", subject="CN=approval.trusty.local,OU=unit1,O=org1", requester="build-agent", reason="LGTM", status=pending, approvals=1/2`, a.Id),
+		evt.message)
+
+	_, err = raSvc.Approve(admin1Ctx, &pb.ApprovalDecisionRequest{Id: a.Id})
+	assertError(t, err, codes.AlreadyExists, "the approver already voted")
+
+	// the second approval issues the certificate by CA
+	vres, err = raSvc.Approve(admin2Ctx, &pb.ApprovalDecisionRequest{Id: a.Id, Reason: "approved"})
+	require.NoError(t, err)
+	assert.Equal(t, pb.ApprovalStatus_ISSUED, vres.Approval.Status)
+	assert.Len(t, vres.Approval.Votes, 2)
+	c := vres.Approval.Certificate
+	require.NotNil(t, c)
+	assert.Equal(t, "approval_server", c.Profile)
+	assert.Equal(t, "build-agent", c.Requester)
+
+	crt, err := certutil.ParseFromPEM([]byte(c.Pem))
+	require.NoError(t, err)
+	assert.Equal(t, "approval.trusty.local", crt.Subject.CommonName)
+	assert.Equal(t, []string{"approval.trusty.local"}, crt.DNSNames)
+
+	evt = auditor.last("ApprovalIssued")
+	require.NotNil(t, evt)
+	assert.Equal(t, "admin2@ekspand.com", evt.identity)
+	assert.Equal(t,
+		fmt.Sprintf(`id=%d, profile="approval_server", issuer="trusty.svc", subject="CN=approval.trusty.local,OU=unit1,O=org1", requester="build-agent", certificate_id=%d`, a.Id, c.Id),
+		evt.message)
+
+	// the request is issued only once
+	_, err = svc.SignCertificate(admin2Ctx, &pb.SignCertificateRequest{ApprovalId: a.Id})
+	assertError(t, err, codes.FailedPrecondition, "the request is issued")
+
+	t.Run("denied", func(t *testing.T) {
+		res, err := svc.SignCertificate(requesterCtx, &pb.SignCertificateRequest{
+			Profile:       "approval_server",
+			Request:       string(generateServerCSR("denied.trusty.local", "denied.trusty.local")),
+			RequestFormat: pb.EncodingFormat_PEM,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, res.Approval)
+		id := res.Approval.Id
+
+		vres, err := raSvc.Deny(admin1Ctx, &pb.ApprovalDecisionRequest{Id: id, Reason: "not needed"})
+		require.NoError(t, err)
+		assert.Equal(t, pb.ApprovalStatus_DENIED, vres.Approval.Status)
+
+		evt := auditor.last("ApprovalDenied")
+		require.NotNil(t, evt)
+		assert.Equal(t, "admin1@ekspand.com", evt.identity)
+
+		_, err = svc.SignCertificate(admin1Ctx, &pb.SignCertificateRequest{ApprovalId: id})
+		assertError(t, err, codes.FailedPrecondition, "the request is denied")
+	})
+}
+
+// assertError checks the code and the message of the service error
+func assertError(t *testing.T, err error, code codes.Code, msg string) {
+	require.Error(t, err)
+	if terr, ok := err.(v1.TrustyError); assert.True(t, ok, "unexpected error type: %T", err) {
+		assert.Equal(t, code, terr.Code(), msg)
+	}
+	assert.Equal(t, msg, err.Error())
+}
+
+// generateServerCSR returns CSR with the common name and SAN
+func generateServerCSR(cn string, san ...string) []byte {
+	prov := csr.NewProvider(inmemcrypto.NewProvider())
+	req := prov.NewSigningCertificateRequest("label", "ECDSA", 256, cn, []csr.X509Name{
+		{
+			O:  "org1",
+			OU: "unit1",
+		},
+	}, san)
+
+	csrPEM, _, _, _ := prov.GenerateKeyAndRequest(req)
+	return csrPEM
+}
+
 func generateCSR() []byte {
 	prov := csr.NewProvider(inmemcrypto.NewProvider())
 	req := prov.NewSigningCertificateRequest("label", "ECDSA", 256, "localhost", []csr.X509Name{
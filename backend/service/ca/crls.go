@@ -24,7 +24,7 @@ func (s *Service) createGenericCRL(ctx context.Context, issuer *authority.Issuer
 	revokedCerts := make([]pkix.RevokedCertificate, 0, 1000)
 	last := uint64(0)
 	for {
-		revokedInfoList, err := s.db.ListRevokedCertificates(ctx, issuer.SubjectKID(), 0, last)
+		revokedInfoList, err := s.db.ListRevokedCertificates(ctx, issuer.SubjectKID(), nil, 0, last)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
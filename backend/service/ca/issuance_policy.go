@@ -0,0 +1,87 @@
+package ca
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/pkg/authz"
+	"github.com/ekspand/trusty/pkg/certpolicy"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/go-phorce/dolly/xlog"
+	"google.golang.org/grpc/codes"
+)
+
+// checkIssuancePolicy returns error if the request with the SAN list
+// is denied by the issuance policy of the profile
+func (s *Service) checkIssuancePolicy(ctx context.Context, ca *authority.Issuer, req *pb.SignCertificateRequest, san []string) error {
+	profile := ca.Profile(req.Profile)
+	if profile == nil || len(profile.IssuancePolicy) == 0 {
+		return nil
+	}
+
+	csrv, err := csr.ParseRequestPEM([]byte(req.Request))
+	if err != nil {
+		return v1.NewError(codes.InvalidArgument, "invalid request: %s", err.Error())
+	}
+
+	contextID, caller := s.policyCaller(ctx)
+	in := &certpolicy.Input{
+		CSR:     csrv,
+		SAN:     san,
+		Profile: req.Profile,
+		Issuer:  ca.Label(),
+		OrgID:   req.OrgId,
+		Caller:  caller,
+	}
+
+	d := profile.IssuancePolicy.Evaluate(in)
+	if d.Allowed {
+		return nil
+	}
+
+	logger.KV(xlog.NOTICE,
+		"status", "denied by issuance policy",
+		"profile", req.Profile,
+		"issuer", ca.Label(),
+		"rule", d.Rule,
+		"reason", d.Reason,
+	)
+
+	s.server.Audit(
+		ServiceName,
+		evtIssuancePolicyDenied,
+		in.Caller.Name,
+		contextID,
+		0,
+		fmt.Sprintf("profile=%q, issuer=%q, org_id=%d, subject=%q, rule=%q, reason=%q",
+			req.Profile, ca.Label(), req.OrgId, csrv.Subject.String(), d.Rule, d.Reason),
+	)
+
+	return v1.NewError(codes.PermissionDenied, "issuance policy %q: %s", d.Rule, d.Reason)
+}
+
+// policyCaller returns the correlation ID of the request,
+// and the caller's details for the issuance policy and webhook
+func (s *Service) policyCaller(ctx context.Context) (string, certpolicy.Caller) {
+	var contextID string
+	var idn identity.Identity
+	if callerCtx := identity.FromContext(ctx); callerCtx != nil {
+		contextID = callerCtx.CorrelationID()
+		idn = callerCtx.Identity()
+	}
+
+	caller := certpolicy.Caller{
+		Roles: authz.RolesOf(idn),
+	}
+	if idn != nil {
+		caller.ID = idn.UserID()
+		caller.Name = idn.Name()
+		caller.Role = idn.Role()
+		caller.Orgs = orgLogins(s.callerOrgs(ctx, idn))
+	}
+	return contextID, caller
+}
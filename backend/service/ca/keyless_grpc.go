@@ -0,0 +1,160 @@
+package ca
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/go-phorce/dolly/metrics"
+	"github.com/go-phorce/dolly/xlog"
+	"github.com/juju/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// defaultKeylessAudience specifies the token audience,
+// if it's not configured in the server's identity map
+const defaultKeylessAudience = "trusty"
+
+// SignKeyless returns a short-lived certificate for the caller's public key,
+// bound to the identity presented by the token
+func (s *Service) SignKeyless(ctx context.Context, req *pb.SignKeylessRequest) (*pb.KeylessCertificateResponse, error) {
+	if req == nil {
+		return nil, v1.NewError(codes.InvalidArgument, "missing request")
+	}
+	if req.Token == "" {
+		return nil, v1.NewError(codes.InvalidArgument, "missing token")
+	}
+	if req.PublicKey == "" {
+		return nil, v1.NewError(codes.InvalidArgument, "missing public_key")
+	}
+	if len(req.Proof) == 0 {
+		return nil, v1.NewError(codes.InvalidArgument, "missing proof")
+	}
+	if s.jwt == nil {
+		return nil, v1.NewError(codes.FailedPrecondition, "JWT provider is not configured")
+	}
+
+	pub, err := parsePublicKey(req.PublicKey)
+	if err != nil {
+		return nil, v1.NewError(codes.InvalidArgument, "invalid public_key")
+	}
+
+	audience := s.server.Configuration().IdentityMap.JWT.Audience
+	if audience == "" {
+		audience = defaultKeylessAudience
+	}
+
+	claims, err := s.jwt.ParseToken(req.Token, audience)
+	if err != nil {
+		logger.KV(xlog.WARNING,
+			"status", "invalid token",
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.Unauthenticated, "invalid token")
+	}
+	if s.sessions != nil {
+		// the token must not be used after its session is revoked
+		_, _, err = s.sessions.VerifySession(ctx, claims.Id)
+		if err != nil {
+			logger.KV(xlog.WARNING,
+				"status", "invalid session",
+				"subject", claims.Subject,
+				"id", claims.Id,
+				"err", errors.Details(err))
+			return nil, v1.NewError(codes.Unauthenticated, "invalid token")
+		}
+	}
+
+	origin := callerOrigin(ctx)
+	// the caller is identified by the token
+	origin.Requester = claims.Subject
+
+	ca, err := s.Authority().GetIssuerByType(authority.IssuerTypeCodesign, req.IssuerLabel)
+	if err != nil {
+		return nil, v1.NewError(codes.InvalidArgument, err.Error())
+	}
+
+	profile := req.Profile
+	if profile == "" {
+		profile = authority.DefaultKeylessProfile
+	}
+
+	crt, certPEM, err := ca.SignKeyless(&authority.KeylessRequest{
+		Profile:   profile,
+		PublicKey: pub,
+		Token:     req.Token,
+		Proof:     req.Proof,
+		Subject:   claims.Subject,
+		Issuer:    claims.Issuer,
+	})
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "failed to sign keyless certificate",
+			"issuer", ca.Label(),
+			"subject", claims.Subject,
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.InvalidArgument, "failed to sign certificate: %s", err.Error())
+	}
+
+	metrics.IncrCounter(keyForCertIssued, 1,
+		metrics.Tag{Name: "profile", Value: profile},
+		metrics.Tag{Name: "issuer", Value: ca.Label()},
+	)
+
+	mcert := model.NewCertificate(crt, 0, profile, string(certPEM), ca.PEM())
+	mcert.Requester = origin.Requester
+	mcert.ClientIP = origin.ClientIP
+	mcert.RequestID = origin.RequestID
+	mcert, err = s.db.RegisterCertificate(ctx, mcert)
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "failed to register certificate",
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.Internal, "failed to register certificate")
+	}
+
+	logger.KV(xlog.NOTICE,
+		"status", "signed keyless certificate",
+		"issuer", ca.Label(),
+		"id", mcert.ID,
+		"subject", claims.Subject,
+		"request_id", origin.RequestID)
+
+	s.server.Audit(
+		ServiceName,
+		evtKeylessIssued,
+		claims.Subject,
+		origin.RequestID,
+		0,
+		fmt.Sprintf("issuer=%q, profile=%q, id=%d, serial=%s, token_issuer=%q, token_id=%q",
+			ca.Label(),
+			profile,
+			mcert.ID,
+			mcert.SerialNumber,
+			claims.Issuer,
+			claims.Id),
+	)
+
+	return &pb.KeylessCertificateResponse{
+		Certificate:   string(certPEM),
+		Intermediates: ca.PEM(),
+	}, nil
+}
+
+// parsePublicKey returns the public key from PEM encoded PKIX public key
+func parsePublicKey(key string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("invalid PEM block")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return pub, nil
+}
@@ -0,0 +1,43 @@
+package ca
+
+import (
+	"context"
+
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/ekspand/trusty/pkg/authz"
+	"github.com/go-phorce/dolly/algorithms/slices"
+	"github.com/go-phorce/dolly/xhttp/identity"
+)
+
+// requestMetadataFilter returns a function to strip the request metadata
+// of the certificates that the caller is not allowed to view.
+// The metadata is returned to the roles in authz.request_metadata_roles,
+// and to the members of the certificate's org.
+func (s *Service) requestMetadataFilter(ctx context.Context) func(*pb.Certificate) {
+	var idn identity.Identity
+	if callerCtx := identity.FromContext(ctx); callerCtx != nil {
+		idn = callerCtx.Identity()
+	}
+
+	allowed := s.server.Configuration().Authz.RequestMetadataRoles
+	for _, role := range authz.RolesOf(idn) {
+		if slices.ContainsString(allowed, role) {
+			return func(*pb.Certificate) {}
+		}
+	}
+
+	var orgs []*model.Organization
+	if idn != nil {
+		orgs = s.callerOrgs(ctx, idn)
+	}
+	return func(crt *pb.Certificate) {
+		if crt == nil || (crt.OrgId != 0 && hasOrg(orgs, crt.OrgId)) {
+			return
+		}
+		crt.Requester = ""
+		crt.ClientIp = ""
+		crt.RequestId = ""
+		crt.Request = ""
+	}
+}
@@ -0,0 +1,144 @@
+package ca
+
+import (
+	"context"
+	"crypto"
+	"encoding/hex"
+	"fmt"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/go-phorce/dolly/metrics"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/go-phorce/dolly/xlog"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/juju/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// maxSignDataSize specifies the limit for the blob to sign,
+// larger content must be signed by digest
+const maxSignDataSize = 64 * 1024
+
+var keyForSignature = []string{"codesign", "signed"}
+
+var hashAlgorithms = map[pb.HashAlgorithm]crypto.Hash{
+	pb.HashAlgorithm_SHA256: crypto.SHA256,
+	pb.HashAlgorithm_SHA384: crypto.SHA384,
+	pb.HashAlgorithm_SHA512: crypto.SHA512,
+}
+
+// SignDigest returns detached signature of the digest,
+// signed with a short-lived certificate issued for the request
+func (s *Service) SignDigest(ctx context.Context, req *pb.SignDigestRequest) (*pb.SignatureResponse, error) {
+	if req == nil {
+		return nil, v1.NewError(codes.InvalidArgument, "missing request")
+	}
+	h, ok := hashAlgorithms[req.HashAlgorithm]
+	if !ok {
+		return nil, v1.NewError(codes.InvalidArgument, "unsupported hash_algorithm: %v", req.HashAlgorithm)
+	}
+
+	digest := req.Digest
+	if len(digest) == 0 {
+		if len(req.Data) == 0 {
+			return nil, v1.NewError(codes.InvalidArgument, "missing digest or data")
+		}
+		if len(req.Data) > maxSignDataSize {
+			return nil, v1.NewError(codes.InvalidArgument, "data exceeds %d bytes, use digest", maxSignDataSize)
+		}
+		d := h.New()
+		d.Write(req.Data)
+		digest = d.Sum(nil)
+	} else if len(digest) != h.Size() {
+		return nil, v1.NewError(codes.InvalidArgument, "invalid digest size: %d", len(digest))
+	}
+
+	var caller, contextID string
+	if callerCtx := identity.FromContext(ctx); callerCtx != nil {
+		caller = callerCtx.Identity().Name()
+		contextID = callerCtx.CorrelationID()
+	}
+	if caller == "" {
+		return nil, v1.NewError(codes.Unauthenticated, "missing caller identity")
+	}
+
+	ca, err := s.Authority().GetIssuerByType(authority.IssuerTypeCodesign, req.IssuerLabel)
+	if err != nil {
+		return nil, v1.NewError(codes.InvalidArgument, err.Error())
+	}
+	if ca.CodeSignPublicKey() == nil {
+		return nil, v1.NewError(codes.FailedPrecondition, "issuer does not have code signing key: %s", ca.Label())
+	}
+
+	profile := req.Profile
+	if profile == "" {
+		profile = authority.DefaultCodeSignProfile
+	}
+
+	crt, signature, err := ca.CodeSign(&authority.CodeSignRequest{
+		Profile: profile,
+		Subject: caller,
+		Hash:    h,
+		Digest:  digest,
+	})
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "failed to sign",
+			"issuer", ca.Label(),
+			"caller", caller,
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.Internal, "failed to sign")
+	}
+
+	certPEM, err := certutil.EncodeToPEMString(false, crt)
+	if err != nil {
+		return nil, v1.NewError(codes.Internal, "failed to encode certificate")
+	}
+
+	metrics.IncrCounter(keyForCertIssued, 1,
+		metrics.Tag{Name: "profile", Value: profile},
+		metrics.Tag{Name: "issuer", Value: ca.Label()},
+	)
+	metrics.IncrCounter(keyForSignature, 1,
+		metrics.Tag{Name: "issuer", Value: ca.Label()},
+	)
+
+	mcert := model.NewCertificate(crt, 0, profile, certPEM, ca.PEM())
+	mcert, err = s.db.RegisterCertificate(ctx, mcert)
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "failed to register certificate",
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.Internal, "failed to register certificate")
+	}
+
+	logger.KV(xlog.NOTICE,
+		"status", "signed digest",
+		"issuer", ca.Label(),
+		"id", mcert.ID,
+		"caller", caller)
+
+	s.server.Audit(
+		ServiceName,
+		evtDigestSigned,
+		caller,
+		contextID,
+		0,
+		fmt.Sprintf("issuer=%q, profile=%q, id=%d, serial=%s, hash=%s, digest=%s",
+			ca.Label(),
+			profile,
+			mcert.ID,
+			mcert.SerialNumber,
+			h.String(),
+			hex.EncodeToString(digest)),
+	)
+
+	return &pb.SignatureResponse{
+		Signature:     signature,
+		Certificate:   certPEM,
+		Intermediates: ca.PEM(),
+	}, nil
+}
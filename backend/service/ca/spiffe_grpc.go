@@ -0,0 +1,118 @@
+package ca
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/authority"
+	"github.com/go-phorce/dolly/metrics"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/go-phorce/dolly/xlog"
+	"github.com/juju/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+var keyForJWTSVIDIssued = []string{"jwtsvid", "issued"}
+
+// SignJWTSVID returns JWT-SVID for SPIFFE ID of the caller,
+// authenticated with X.509-SVID
+func (s *Service) SignJWTSVID(ctx context.Context, req *pb.JWTSVIDRequest) (*pb.JWTSVIDResponse, error) {
+	if req == nil || len(req.Audience) == 0 {
+		return nil, v1.NewError(codes.InvalidArgument, "missing audience")
+	}
+
+	spiffeID := callerSpiffeID(ctx)
+	if spiffeID == nil {
+		return nil, v1.NewError(codes.Unauthenticated, "caller must be authenticated with X.509-SVID")
+	}
+
+	var contextID string
+	if callerCtx := identity.FromContext(ctx); callerCtx != nil {
+		contextID = callerCtx.CorrelationID()
+	}
+
+	ca, err := s.Authority().GetJWTSVIDIssuer(spiffeID.Host, req.IssuerLabel)
+	if err != nil {
+		return nil, v1.NewError(codes.InvalidArgument, err.Error())
+	}
+
+	token, claims, err := ca.SignJWTSVID(spiffeID, req.Audience)
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "failed to sign JWT-SVID",
+			"issuer", ca.Label(),
+			"spiffe_id", spiffeID.String(),
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.InvalidArgument, "failed to sign JWT-SVID: %s", err.Error())
+	}
+
+	metrics.IncrCounter(keyForJWTSVIDIssued, 1,
+		metrics.Tag{Name: "issuer", Value: ca.Label()},
+	)
+
+	s.server.Audit(
+		ServiceName,
+		evtJWTSVIDIssued,
+		claims.Subject,
+		contextID,
+		0,
+		fmt.Sprintf("issuer=%q, audience=%q, expires_at=%d",
+			ca.Label(),
+			strings.Join(claims.Audience, ","),
+			claims.ExpiresAt),
+	)
+
+	return &pb.JWTSVIDResponse{
+		SpiffeId:  claims.Subject,
+		Token:     token,
+		ExpiresAt: claims.ExpiresAt,
+	}, nil
+}
+
+// ValidateJWTSVID validates JWT-SVID for the audience
+func (s *Service) ValidateJWTSVID(ctx context.Context, req *pb.ValidateJWTSVIDRequest) (*pb.ValidateJWTSVIDResponse, error) {
+	if req == nil || req.Token == "" {
+		return nil, v1.NewError(codes.InvalidArgument, "missing token")
+	}
+	if req.Audience == "" {
+		return nil, v1.NewError(codes.InvalidArgument, "missing audience")
+	}
+
+	claims, err := s.Authority().ValidateJWTSVID(req.Token, req.Audience)
+	if err != nil {
+		logger.KV(xlog.DEBUG,
+			"status", "invalid JWT-SVID",
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.InvalidArgument, "invalid token: %s", err.Error())
+	}
+
+	return &pb.ValidateJWTSVIDResponse{
+		SpiffeId:  claims.Subject,
+		Audience:  claims.Audience,
+		ExpiresAt: claims.ExpiresAt,
+	}, nil
+}
+
+// callerSpiffeID returns SPIFFE ID from X.509-SVID of the caller,
+// or nil if the caller is not authenticated with X.509-SVID
+func callerSpiffeID(ctx context.Context) *url.URL {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	si, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(si.State.PeerCertificates) == 0 {
+		return nil
+	}
+	uris := si.State.PeerCertificates[0].URIs
+	if len(uris) != 1 || uris[0].Scheme != authority.SpiffeScheme {
+		return nil
+	}
+	return uris[0]
+}
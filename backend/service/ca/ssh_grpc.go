@@ -0,0 +1,306 @@
+package ca
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/go-phorce/dolly/metrics"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/go-phorce/dolly/xlog"
+	"github.com/juju/errors"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var keyForSSHCertIssued = []string{"ssh", "issued"}
+
+// SignSSHKey returns SSH certificate for the public key,
+// with the principals allowed for the caller
+func (s *Service) SignSSHKey(ctx context.Context, req *pb.SignSSHKeyRequest) (*pb.SSHCertificateResponse, error) {
+	if req == nil || req.Profile == "" {
+		return nil, v1.NewError(codes.InvalidArgument, "missing profile")
+	}
+	if req.PublicKey == "" {
+		return nil, v1.NewError(codes.InvalidArgument, "missing public_key")
+	}
+
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(req.PublicKey))
+	if err != nil {
+		return nil, v1.NewError(codes.InvalidArgument, "invalid public_key")
+	}
+
+	var contextID string
+	var caller identity.Identity
+	if callerCtx := identity.FromContext(ctx); callerCtx != nil {
+		contextID = callerCtx.CorrelationID()
+		caller = callerCtx.Identity()
+	}
+	if caller == nil || caller.Name() == "" || caller.Role() == identity.GuestRoleName {
+		return nil, v1.NewError(codes.Unauthenticated, "caller must be authenticated")
+	}
+
+	ca, err := s.Authority().GetIssuerByProfile(req.Profile)
+	if err != nil {
+		return nil, v1.NewError(codes.InvalidArgument, err.Error())
+	}
+
+	orgs := s.callerOrgs(ctx, caller)
+	if req.OrgId != 0 && !hasOrg(orgs, req.OrgId) {
+		return nil, v1.NewError(codes.PermissionDenied, "caller is not a member of org: %d", req.OrgId)
+	}
+
+	idn := authority.SSHIdentity{
+		Name: caller.Name(),
+		Role: caller.Role(),
+		Orgs: orgLogins(orgs),
+	}
+
+	cert, err := ca.SignSSH(&authority.SSHRequest{
+		Profile:    req.Profile,
+		PublicKey:  pub,
+		KeyID:      req.KeyId,
+		Principals: req.Principals,
+		Identity:   idn,
+	})
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "failed to sign SSH certificate",
+			"issuer", ca.Label(),
+			"caller", idn.Name,
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.InvalidArgument, "failed to sign SSH certificate: %s", err.Error())
+	}
+
+	metrics.IncrCounter(keyForSSHCertIssued, 1,
+		metrics.Tag{Name: "profile", Value: req.Profile},
+		metrics.Tag{Name: "issuer", Value: ca.Label()},
+	)
+
+	mcert, err := s.db.RegisterSSHCertificate(ctx, newSSHCertificate(cert, ca.SSHKeyID(), req.OrgId, req.Profile))
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "failed to register SSH certificate",
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.Internal, "failed to register SSH certificate")
+	}
+
+	s.server.Audit(
+		ServiceName,
+		evtSSHCertIssued,
+		idn.Name,
+		contextID,
+		0,
+		fmt.Sprintf("id=%d, issuer=%q, serial=%s, key_id=%q, principals=%q",
+			mcert.ID,
+			ca.Label(),
+			mcert.SerialNumber,
+			mcert.KeyID,
+			mcert.PrincipalsString()),
+	)
+
+	return &pb.SSHCertificateResponse{
+		Certificate: mcert.ToDTO(),
+	}, nil
+}
+
+// RevokeSSHCertificate returns the revoked SSH certificate
+func (s *Service) RevokeSSHCertificate(ctx context.Context, req *pb.RevokeSSHCertificateRequest) (*pb.SSHCertificateResponse, error) {
+	if req == nil || req.Ikid == "" {
+		return nil, v1.NewError(codes.InvalidArgument, "missing ikid")
+	}
+	if req.SerialNumber == "" {
+		return nil, v1.NewError(codes.InvalidArgument, "missing serial_number")
+	}
+
+	var contextID, callerName string
+	if callerCtx := identity.FromContext(ctx); callerCtx != nil {
+		contextID = callerCtx.CorrelationID()
+		callerName = callerCtx.Identity().Name()
+	}
+
+	revoked, err := s.db.RevokeSSHCertificate(ctx, req.Ikid, req.SerialNumber, time.Now().UTC(), int(req.Reason))
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"request", req,
+			"err", errors.Details(err),
+		)
+		return nil, v1.NewError(codes.Internal, "unable to revoke SSH certificate")
+	}
+
+	s.server.Audit(
+		ServiceName,
+		evtSSHCertRevoked,
+		callerName,
+		contextID,
+		0,
+		fmt.Sprintf("id=%d, ikid=%s, serial=%s, key_id=%q, reason=%s",
+			revoked.ID,
+			revoked.IKID,
+			revoked.SerialNumber,
+			revoked.KeyID,
+			req.Reason.String()),
+	)
+
+	return &pb.SSHCertificateResponse{
+		Certificate: revoked.ToDTO(),
+	}, nil
+}
+
+// PublishKRL returns published Key Revocation Lists
+func (s *Service) PublishKRL(ctx context.Context, req *pb.PublishKRLRequest) (*pb.KRLResponse, error) {
+	var label string
+	if req != nil {
+		label = req.IssuerLabel
+	}
+
+	var issuers []*authority.Issuer
+	for _, issuer := range s.Authority().Issuers() {
+		if issuer.SSHPublicKey() == nil || issuer.State() == authority.IssuerStateRetired {
+			continue
+		}
+		if label == "" || strings.EqualFold(issuer.Label(), label) {
+			issuers = append(issuers, issuer)
+		}
+	}
+	if len(issuers) == 0 {
+		return nil, v1.NewError(codes.NotFound, "SSH issuer not found: %s", label)
+	}
+
+	res := &pb.KRLResponse{}
+	for _, issuer := range issuers {
+		krl, err := s.createKRL(ctx, issuer)
+		if err != nil {
+			logger.KV(xlog.ERROR,
+				"status", "failed to publish KRL",
+				"issuer", issuer.Label(),
+				"err", errors.Details(err))
+			return nil, v1.NewError(codes.Internal, "failed to publish KRL")
+		}
+		res.Krls = append(res.Krls, krl)
+	}
+	return res, nil
+}
+
+func (s *Service) createKRL(ctx context.Context, issuer *authority.Issuer) (*pb.KRL, error) {
+	ikid := issuer.SSHKeyID()
+	now := time.Now().UTC()
+
+	var serials []string
+	last := uint64(0)
+	for {
+		list, err := s.db.ListRevokedSSHCertificates(ctx, ikid, 0, last)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(list) == 0 {
+			break
+		}
+		for _, r := range list {
+			serials = append(serials, r.SerialNumber)
+			last = r.ID
+		}
+	}
+
+	// KRL version must increase with each published KRL,
+	// the counter is kept in DB to not depend on the clock
+	version, err := s.db.NextSSHKRLVersion(ctx, ikid, now)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	krl, err := issuer.CreateKRL(serials, version, now)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	s.server.Audit(
+		ServiceName,
+		evtKRLPublished,
+		"",
+		"",
+		0,
+		fmt.Sprintf("issuer=%q, ikid=%s, version=%d, revoked=%d",
+			issuer.Label(),
+			ikid,
+			version,
+			len(serials)),
+	)
+
+	return &pb.KRL{
+		Ikid:        ikid,
+		IssuerLabel: issuer.Label(),
+		Version:     version,
+		ThisUpdate:  timestamppb.New(now),
+		Krl:         krl,
+	}, nil
+}
+
+// callerOrgs returns the caller's organizations,
+// if the caller is a registered user
+func (s *Service) callerOrgs(ctx context.Context, caller identity.Identity) []*model.Organization {
+	if s.orgs == nil || caller.UserID() == "" {
+		return nil
+	}
+	userID, err := strconv.ParseUint(caller.UserID(), 10, 64)
+	if err != nil {
+		return nil
+	}
+	orgs, err := s.orgs.GetUserOrgs(ctx, userID)
+	if err != nil {
+		logger.KV(xlog.WARNING,
+			"status", "unable to get user orgs",
+			"user_id", userID,
+			"err", errors.Details(err))
+		return nil
+	}
+	return orgs
+}
+
+// orgLogins returns logins of the organizations
+func orgLogins(orgs []*model.Organization) []string {
+	list := make([]string, 0, len(orgs))
+	for _, org := range orgs {
+		list = append(list, org.Login)
+	}
+	return list
+}
+
+// hasOrg returns true, if the list contains the organization
+func hasOrg(orgs []*model.Organization, orgID uint64) bool {
+	for _, org := range orgs {
+		if org.ID == orgID {
+			return true
+		}
+	}
+	return false
+}
+
+func newSSHCertificate(cert *ssh.Certificate, ikid string, orgID uint64, profile string) *model.SSHCertificate {
+	certType := authority.SSHCertTypeUser
+	if cert.CertType == ssh.HostCert {
+		certType = authority.SSHCertTypeHost
+	}
+	sum := sha256.Sum256(cert.Marshal())
+	return &model.SSHCertificate{
+		OrgID:            orgID,
+		IKID:             ikid,
+		SerialNumber:     strconv.FormatUint(cert.Serial, 10),
+		KeyID:            cert.KeyId,
+		CertType:         certType,
+		Principals:       cert.ValidPrincipals,
+		NotBefore:        time.Unix(int64(cert.ValidAfter), 0).UTC(),
+		NotAfter:         time.Unix(int64(cert.ValidBefore), 0).UTC(),
+		ThumbprintSha256: hex.EncodeToString(sum[:]),
+		Profile:          profile,
+		Pem:              strings.TrimSpace(string(ssh.MarshalAuthorizedKey(cert))),
+	}
+}
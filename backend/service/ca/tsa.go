@@ -0,0 +1,110 @@
+package ca
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/tsa"
+	"github.com/go-phorce/dolly/rest"
+	"github.com/go-phorce/dolly/xhttp/header"
+	"github.com/go-phorce/dolly/xhttp/httperror"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/go-phorce/dolly/xhttp/marshal"
+	"github.com/go-phorce/dolly/xlog"
+	"github.com/juju/errors"
+)
+
+// maxTimestampQuerySize specifies the limit for TimeStampReq
+const maxTimestampQuerySize = 64 * 1024
+
+func (s *Service) timestamp() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ rest.Params) {
+		ct := r.Header.Get(header.ContentType)
+		if !strings.HasPrefix(ct, tsa.ContentTypeQuery) {
+			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("unsupported content type: %q", ct))
+			return
+		}
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxTimestampQuerySize))
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("failed to read request: %s", err.Error()))
+			return
+		}
+
+		var caller, contextID string
+		if idctx := identity.FromRequest(r); idctx != nil {
+			caller = idctx.Identity().String()
+			contextID = idctx.CorrelationID()
+		}
+
+		res, err := s.signTimestamp(body, r.URL.Query().Get("issuer"), caller, contextID)
+		if err != nil {
+			logger.KV(xlog.ERROR,
+				"status", "failed to sign timestamp",
+				"caller", caller,
+				"err", errors.Details(err))
+			res = tsa.FailureResponse(err)
+		}
+
+		w.Header().Set(header.ContentType, tsa.ContentTypeReply)
+		w.Write(res)
+	}
+}
+
+// signTimestamp returns DER encoded TimeStampResp for DER encoded TimeStampReq
+func (s *Service) signTimestamp(query []byte, label, caller, contextID string) ([]byte, error) {
+	req, err := tsa.ParseRequest(query)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	issuer, err := s.Authority().TimestampIssuer(label)
+	if err != nil {
+		return nil, tsa.NewError(tsa.BadRequest, err.Error())
+	}
+
+	info, token, err := issuer.Timestamp(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	res, err := tsa.NewResponse(token)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	h, _ := info.MessageImprint.Hash()
+	nonce := ""
+	if info.Nonce != nil {
+		nonce = info.Nonce.Text(16)
+	}
+	serial := info.SerialNumber.String()
+
+	logger.KV(xlog.NOTICE,
+		"status", "signed timestamp",
+		"issuer", issuer.Label(),
+		"serial", serial,
+		"caller", caller)
+
+	s.server.Audit(
+		ServiceName,
+		evtTimestampIssued,
+		caller,
+		contextID,
+		0,
+		fmt.Sprintf("issuer=%q, serial=%s, policy=%s, hash=%s, imprint=%s, nonce=%q, time=%s",
+			issuer.Label(),
+			serial,
+			info.Policy.String(),
+			h.String(),
+			hex.EncodeToString(info.MessageImprint.HashedMessage),
+			nonce,
+			info.GenTime.Format(time.RFC3339Nano)),
+	)
+
+	return res, nil
+}
@@ -0,0 +1,126 @@
+package ca
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/webhook"
+	"github.com/go-phorce/dolly/xlog"
+	"github.com/juju/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// checkWebhook calls the webhook of the profile, if configured,
+// and returns SAN to be used for the certificate
+func (s *Service) checkWebhook(ctx context.Context, ca *authority.Issuer, req *pb.SignCertificateRequest) ([]string, error) {
+	profile := ca.Profile(req.Profile)
+	if profile == nil || profile.Webhook == nil {
+		return req.San, nil
+	}
+
+	csrv, err := csr.ParseRequestPEM([]byte(req.Request))
+	if err != nil {
+		return nil, v1.NewError(codes.InvalidArgument, "invalid request: %s", err.Error())
+	}
+
+	contextID, caller := s.policyCaller(ctx)
+	wreq := &webhook.Request{
+		RequestID: contextID,
+		Profile:   req.Profile,
+		Issuer:    ca.Label(),
+		OrgID:     req.OrgId,
+		Caller: webhook.Caller{
+			ID:    caller.ID,
+			Name:  caller.Name,
+			Role:  caller.Role,
+			Roles: caller.Roles,
+			Orgs:  caller.Orgs,
+		},
+		CSR: webhook.NewCSR(csrv, req.Request),
+		SAN: req.San,
+	}
+
+	audit := func(evt, details string) {
+		s.server.Audit(
+			ServiceName,
+			evt,
+			caller.Name,
+			contextID,
+			0,
+			fmt.Sprintf("profile=%q, issuer=%q, org_id=%d, subject=%q, url=%q, %s",
+				req.Profile, ca.Label(), req.OrgId, csrv.Subject.String(), profile.Webhook.URL, details),
+		)
+	}
+
+	res, err := s.callWebhook(ctx, ca.Label()+"/"+req.Profile, profile.Webhook, wreq)
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "webhook failed",
+			"profile", req.Profile,
+			"url", profile.Webhook.URL,
+			"fail_open", profile.Webhook.FailOpen,
+			"err", errors.Details(err),
+		)
+		audit(evtWebhookFailed, fmt.Sprintf("fail_open=%t, err=%q", profile.Webhook.FailOpen, err.Error()))
+
+		if profile.Webhook.FailOpen {
+			return req.San, nil
+		}
+		return nil, v1.NewError(codes.Unavailable, "webhook is not available")
+	}
+
+	if !res.Allowed {
+		reason := res.Reason
+		if reason == "" {
+			reason = "request is denied"
+		}
+		logger.KV(xlog.NOTICE,
+			"status", "denied by webhook",
+			"profile", req.Profile,
+			"url", profile.Webhook.URL,
+			"reason", reason,
+		)
+		audit(evtWebhookDenied, fmt.Sprintf("reason=%q", reason))
+		return nil, v1.NewError(codes.PermissionDenied, "webhook: %s", reason)
+	}
+
+	san := req.San
+	if len(res.SAN) > 0 {
+		san = res.SAN
+	}
+	audit(evtWebhookApproved, fmt.Sprintf("san=%q", strings.Join(san, ",")))
+	return san, nil
+}
+
+// webhookClient provides the client for the webhook configuration
+type webhookClient struct {
+	cfg    *webhook.Config
+	client *webhook.Client
+}
+
+// callWebhook calls the webhook with the client for the profile,
+// the client is created again if the profile's configuration is reloaded
+func (s *Service) callWebhook(ctx context.Context, name string, cfg *webhook.Config, req *webhook.Request) (*webhook.Response, error) {
+	s.webhooksLock.Lock()
+	wc := s.webhooks[name]
+	if wc == nil || wc.cfg != cfg {
+		client, err := webhook.New(cfg)
+		if err != nil {
+			s.webhooksLock.Unlock()
+			return nil, errors.Trace(err)
+		}
+		if s.webhooks == nil {
+			s.webhooks = make(map[string]*webhookClient)
+		}
+		wc = &webhookClient{cfg: cfg, client: client}
+		s.webhooks[name] = wc
+	}
+	s.webhooksLock.Unlock()
+
+	return wc.client.Call(ctx, req)
+}
@@ -0,0 +1,159 @@
+package cis
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"net/http"
+	"path"
+	"strings"
+
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"github.com/go-phorce/dolly/rest"
+	"github.com/go-phorce/dolly/xhttp/header"
+	"github.com/go-phorce/dolly/xhttp/httperror"
+	"github.com/go-phorce/dolly/xhttp/marshal"
+	"github.com/go-phorce/dolly/xlog"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/juju/errors"
+)
+
+var (
+	oidData       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+)
+
+func (s *Service) aiaCerts() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p rest.Params) {
+		id := p.ByName("id")
+		ext := path.Ext(id)
+		if ext != ".crt" && ext != ".p7c" {
+			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("unsupported format: %q", ext))
+			return
+		}
+		skid := strings.ToLower(strings.TrimSuffix(id, ext))
+
+		ca, err := s.getCAClient()
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithNotReady("CA is not available"))
+			return
+		}
+
+		res, err := ca.Issuers(r.Context())
+		if err != nil {
+			logger.KV(xlog.ERROR, "err", errors.Details(err))
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to get issuers"))
+			return
+		}
+
+		certs := aiaCertificates(res.Issuers, skid)
+		if len(certs) == 0 {
+			marshal.WriteJSON(w, r, httperror.WithNotFound("certificate not found: %s", skid))
+			return
+		}
+
+		if ext == ".crt" {
+			w.Header().Set(header.ContentType, "application/pkix-cert")
+			w.Write(certs[0].Raw)
+			return
+		}
+
+		p7, err := certsOnlyPKCS7(certs)
+		if err != nil {
+			logger.KV(xlog.ERROR, "err", errors.Details(err))
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to encode certificates"))
+			return
+		}
+		w.Header().Set(header.ContentType, "application/pkcs7-mime")
+		w.Write(p7)
+	}
+}
+
+// aiaCertificates returns the certificates with the specified Subject Key ID.
+// The certificate from the primary chain is returned first,
+// followed by the cross-certificates from the alternate chains.
+func aiaCertificates(issuers []*pb.IssuerInfo, skid string) []*x509.Certificate {
+	var primary, alternates []*x509.Certificate
+	for _, issuer := range issuers {
+		for _, pem := range []string{issuer.Certificate, issuer.Intermediates, issuer.Root} {
+			primary = append(primary, parseCerts(pem)...)
+		}
+		alternates = append(alternates, parseCerts(issuer.Alternates)...)
+	}
+
+	var list []*x509.Certificate
+	for _, c := range append(primary, alternates...) {
+		if certutil.GetSubjectID(c) != skid {
+			continue
+		}
+		found := false
+		for _, l := range list {
+			if bytes.Equal(l.Raw, c.Raw) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+func parseCerts(pem string) []*x509.Certificate {
+	if strings.TrimSpace(pem) == "" {
+		return nil
+	}
+	certs, err := certutil.ParseChainFromPEM([]byte(pem))
+	if err != nil {
+		logger.KV(xlog.WARNING, "reason", "parse", "err", err.Error())
+		return nil
+	}
+	return certs
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"optional"`
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []asn1.RawValue `asn1:"set"`
+	ContentInfo      contentInfo
+	Certificates     asn1.RawValue
+	SignerInfos      []asn1.RawValue `asn1:"set"`
+}
+
+// certsOnlyPKCS7 returns degenerate PKCS#7 SignedData with certificates only,
+// as specified for AIA in RFC 5280 4.2.2.1
+func certsOnlyPKCS7(certs []*x509.Certificate) ([]byte, error) {
+	var raw []byte
+	for _, c := range certs {
+		raw = append(raw, c.Raw...)
+	}
+
+	sd, err := asn1.Marshal(signedData{
+		Version:     1,
+		ContentInfo: contentInfo{ContentType: oidData},
+		Certificates: asn1.RawValue{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        0,
+			IsCompound: true,
+			Bytes:      raw,
+		},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content: asn1.RawValue{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        0,
+			IsCompound: true,
+			Bytes:      sd,
+		},
+	})
+}
@@ -0,0 +1,109 @@
+package cis
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"testing"
+
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/inmemcrypto"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAIACertificates(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	crypto, err := cryptoprov.New(defprov, nil)
+	require.NoError(t, err)
+
+	prov := csr.NewProvider(defprov)
+	rootCfg := &authority.Config{
+		Profiles: map[string]*authority.CertProfile{
+			"ROOT": {
+				Usage:        []string{"cert sign", "crl sign"},
+				CAConstraint: authority.CAConstraint{IsCA: true},
+				Expiry:       csr.OneYear,
+			},
+		},
+	}
+
+	createRoot := func(name string) *authority.Issuer {
+		rootPEM, _, rootKey, err := authority.NewRoot("ROOT", rootCfg, defprov, &csr.CertificateRequest{
+			CommonName: "[TEST] Trusty Root CA " + name,
+			KeyRequest: prov.NewKeyRequest("TestAIACertificates", "ECDSA", 256, csr.SigningKey),
+		})
+		require.NoError(t, err)
+
+		signer, err := authority.NewSignerFromPEM(crypto, rootKey)
+		require.NoError(t, err)
+
+		issuer, err := authority.CreateIssuer(&authority.IssuerConfig{
+			Label:    name,
+			Profiles: rootCfg.Profiles,
+		}, rootPEM, nil, nil, signer)
+		require.NoError(t, err)
+		return issuer
+	}
+
+	rootA := createRoot("A")
+	rootB := createRoot("B")
+
+	cross, crossPEM, err := rootB.CrossSign(rootA.Bundle().Cert, "ROOT")
+	require.NoError(t, err)
+
+	issuers := []*pb.IssuerInfo{
+		{
+			Certificate: rootA.Bundle().CertPEM,
+			Root:        rootA.Bundle().RootCertPEM,
+			Alternates:  string(crossPEM) + "\n" + rootB.Bundle().CertPEM,
+		},
+		{
+			Certificate: rootB.Bundle().CertPEM,
+			Root:        rootB.Bundle().RootCertPEM,
+		},
+	}
+
+	certs := aiaCertificates(issuers, "unknown")
+	assert.Empty(t, certs)
+
+	certs = aiaCertificates(issuers, certutil.GetSubjectID(rootB.Bundle().Cert))
+	require.Len(t, certs, 1)
+	assert.Equal(t, rootB.Bundle().Cert.Raw, certs[0].Raw)
+
+	certs = aiaCertificates(issuers, certutil.GetSubjectID(rootA.Bundle().Cert))
+	require.Len(t, certs, 2)
+	assert.Equal(t, rootA.Bundle().Cert.Raw, certs[0].Raw)
+	assert.Equal(t, cross.Raw, certs[1].Raw)
+
+	der, err := certsOnlyPKCS7(certs)
+	require.NoError(t, err)
+
+	var ci struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue `asn1:"explicit,tag:0"`
+	}
+	_, err = asn1.Unmarshal(der, &ci)
+	require.NoError(t, err)
+	assert.Equal(t, oidSignedData, ci.ContentType)
+
+	var sd struct {
+		Version          int
+		DigestAlgorithms asn1.RawValue
+		ContentInfo      asn1.RawValue
+		Certificates     asn1.RawValue `asn1:"tag:0"`
+		SignerInfos      asn1.RawValue
+	}
+	_, err = asn1.Unmarshal(ci.Content.Bytes, &sd)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sd.Version)
+
+	list, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, rootA.Bundle().Cert.Raw, list[0].Raw)
+	assert.Equal(t, cross.Raw, list[1].Raw)
+}
@@ -40,5 +40,13 @@ func (s *Service) GetCertificate(ctx context.Context, in *pb.GetCertificateReque
 		return nil, v1.NewError(codes.Internal, "failed to get certificate")
 	}
 
+	// the request metadata is not published
+	if c := res.Certificate; c != nil {
+		c.Requester = ""
+		c.ClientIp = ""
+		c.RequestId = ""
+		c.Request = ""
+	}
+
 	return res, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 
+	v1 "github.com/ekspand/trusty/api/v1"
 	pb "github.com/ekspand/trusty/api/v1/pb"
 	"github.com/ekspand/trusty/client"
 	"github.com/ekspand/trusty/client/embed/proxy"
@@ -30,6 +31,8 @@ type Service struct {
 	clientFactory client.Factory
 	grpClient     *client.Client
 	ra            client.RAClient
+	caGrpClient   *client.Client
+	ca            client.CAClient
 	lock          sync.RWMutex
 	ctx           context.Context
 	cancel        context.CancelFunc
@@ -72,12 +75,17 @@ func (s *Service) Close() {
 	if s.grpClient != nil {
 		s.grpClient.Close()
 	}
+	if s.caGrpClient != nil {
+		s.caGrpClient.Close()
+	}
 
 	logger.KV(xlog.INFO, "closed", ServiceName)
 }
 
 // RegisterRoute adds the Status API endpoints to the overall URL router
 func (s *Service) RegisterRoute(r rest.Router) {
+	r.GET(v1.PathForAIACerts, s.aiaCerts())
+	r.GET(v1.PathForSpiffeBundle, s.spiffeBundle())
 }
 
 // RegisterGRPC registers gRPC handler
@@ -141,3 +149,43 @@ func (s *Service) getRAClient() (client.RAClient, error) {
 
 	return s.ra, nil
 }
+
+func (s *Service) getCAClient() (client.CAClient, error) {
+	var ca client.CAClient
+	s.lock.RLock()
+	ca = s.ca
+	s.lock.RUnlock()
+	if ca != nil {
+		return ca, nil
+	}
+
+	var pb pb.CAServiceServer
+	err := s.server.Discovery().Find(&pb)
+	if err == nil {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		s.ca = client.NewCAClientFromProxy(proxy.CAServerToClient(pb))
+		return s.ca, nil
+	}
+
+	grpClient, err := s.clientFactory.NewClient("ca")
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "failed to get CA client",
+			"err", errors.Details(err))
+		return nil, errors.Trace(err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.caGrpClient != nil {
+		s.caGrpClient.Close()
+	}
+	s.caGrpClient = grpClient
+	s.ca = grpClient.CAClient()
+
+	logger.KV(xlog.INFO, "status", "created CA client")
+
+	return s.ca, nil
+}
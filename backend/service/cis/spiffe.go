@@ -0,0 +1,144 @@
+package cis
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/pkg/jwk"
+	"github.com/go-phorce/dolly/rest"
+	"github.com/go-phorce/dolly/xhttp/httperror"
+	"github.com/go-phorce/dolly/xhttp/marshal"
+	"github.com/go-phorce/dolly/xlog"
+	"github.com/juju/errors"
+)
+
+// spiffeRefreshHint specifies the interval in seconds
+// for SPIFFE bundle consumers to refresh the bundle
+const spiffeRefreshHint = 300
+
+// JWK use for X.509 and JWT authorities
+const (
+	keyUseX509SVID = "x509-svid"
+	keyUseJWTSVID  = "jwt-svid"
+)
+
+func (s *Service) spiffeBundle() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p rest.Params) {
+		td := p.ByName("trust_domain")
+
+		ca, err := s.getCAClient()
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithNotReady("CA is not available"))
+			return
+		}
+
+		res, err := ca.Issuers(r.Context())
+		if err != nil {
+			logger.KV(xlog.ERROR, "err", errors.Details(err))
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to get issuers"))
+			return
+		}
+
+		keys, err := x509Authorities(res.Issuers, td)
+		if err != nil {
+			logger.KV(xlog.ERROR, "err", errors.Details(err))
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to encode bundle"))
+			return
+		}
+		jwtKeys, err := jwtAuthorities(res.Issuers, td)
+		if err != nil {
+			logger.KV(xlog.ERROR, "err", errors.Details(err))
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("failed to encode bundle"))
+			return
+		}
+		keys = append(keys, jwtKeys...)
+		if len(keys) == 0 {
+			marshal.WriteJSON(w, r, httperror.WithNotFound("trust domain not found: %s", td))
+			return
+		}
+
+		marshal.WriteJSON(w, r, &v1.SpiffeBundle{
+			Keys:        keys,
+			RefreshHint: spiffeRefreshHint,
+		})
+	}
+}
+
+// x509Authorities returns JWK of the trust anchors for X.509-SVID
+// of the spiffe issuers in the trust domain:
+// the root of the issuer, or the issuer itself, if the root is not provided
+func x509Authorities(issuers []*pb.IssuerInfo, td string) ([]*jwk.Key, error) {
+	var anchors []*x509.Certificate
+	for _, issuer := range issuers {
+		if td == "" || issuer.TrustDomain != td || issuer.State == pb.IssuerState_RETIRED {
+			continue
+		}
+		certs := parseCerts(issuer.Root)
+		if len(certs) == 0 {
+			certs = parseCerts(issuer.Certificate)
+		}
+		for _, c := range certs {
+			found := false
+			for _, a := range anchors {
+				if bytes.Equal(a.Raw, c.Raw) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				anchors = append(anchors, c)
+			}
+		}
+	}
+
+	keys := make([]*jwk.Key, 0, len(anchors))
+	for _, c := range anchors {
+		k, err := jwk.NewKeyFromCertificate(c, keyUseX509SVID, "")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// jwtAuthorities returns JWK of JWT-SVID signers
+// of the spiffe issuers in the trust domain
+func jwtAuthorities(issuers []*pb.IssuerInfo, td string) ([]*jwk.Key, error) {
+	var keys []*jwk.Key
+	for _, issuer := range issuers {
+		if td == "" || issuer.TrustDomain != td || issuer.State == pb.IssuerState_RETIRED ||
+			issuer.JwtKeyId == "" {
+			continue
+		}
+		found := false
+		for _, k := range keys {
+			if k.Kid == issuer.JwtKeyId {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		block, _ := pem.Decode([]byte(issuer.JwtPublicKey))
+		if block == nil {
+			return nil, errors.Errorf("invalid JWT public key: %s", issuer.Label)
+		}
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid JWT public key: %s", issuer.Label)
+		}
+		k, err := jwk.NewKey(pub, keyUseJWTSVID, issuer.JwtKeyId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
@@ -0,0 +1,102 @@
+package cis
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/inmemcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestX509Authorities(t *testing.T) {
+	defprov := inmemcrypto.NewProvider()
+	prov := csr.NewProvider(defprov)
+	rootCfg := &authority.Config{
+		Profiles: map[string]*authority.CertProfile{
+			"ROOT": {
+				Usage:        []string{"cert sign", "crl sign"},
+				CAConstraint: authority.CAConstraint{IsCA: true},
+				Expiry:       csr.OneYear,
+			},
+		},
+	}
+
+	createRoot := func(name string) string {
+		rootPEM, _, _, err := authority.NewRoot("ROOT", rootCfg, defprov, &csr.CertificateRequest{
+			CommonName: "[TEST] Trusty SPIFFE Root " + name,
+			KeyRequest: prov.NewKeyRequest("TestX509Authorities", "ECDSA", 256, csr.SigningKey),
+		})
+		require.NoError(t, err)
+		return string(rootPEM)
+	}
+
+	rootA := createRoot("A")
+	rootB := createRoot("B")
+
+	issuers := []*pb.IssuerInfo{
+		{Certificate: rootA, TrustDomain: "trusty.ekspand.com"},
+		{Certificate: "issuer", Root: rootA, TrustDomain: "trusty.ekspand.com"},
+		{Certificate: rootB, TrustDomain: "other.com"},
+		{Certificate: rootB},
+		{Certificate: rootB, TrustDomain: "trusty.ekspand.com", State: pb.IssuerState_RETIRED},
+	}
+
+	keys, err := x509Authorities(issuers, "trusty.ekspand.com")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, keyUseX509SVID, keys[0].Use)
+	assert.Equal(t, "EC", keys[0].Kty)
+	require.Len(t, keys[0].X5c, 1)
+
+	der, err := base64.StdEncoding.DecodeString(keys[0].X5c[0])
+	require.NoError(t, err)
+	certs := parseCerts(rootA)
+	require.Len(t, certs, 1)
+	assert.Equal(t, certs[0].Raw, der)
+
+	keys, err = x509Authorities(issuers, "other.com")
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+
+	keys, err = x509Authorities(issuers, "")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestJWTAuthorities(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	spki, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	pubPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: spki}))
+
+	issuers := []*pb.IssuerInfo{
+		{Label: "a", TrustDomain: "trusty.ekspand.com", JwtKeyId: "kid1", JwtPublicKey: pubPEM},
+		{Label: "b", TrustDomain: "trusty.ekspand.com", JwtKeyId: "kid1", JwtPublicKey: pubPEM},
+		{Label: "c", TrustDomain: "trusty.ekspand.com"},
+		{Label: "d", TrustDomain: "other.com", JwtKeyId: "kid2", JwtPublicKey: pubPEM},
+	}
+
+	keys, err := jwtAuthorities(issuers, "trusty.ekspand.com")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, keyUseJWTSVID, keys[0].Use)
+	assert.Equal(t, "kid1", keys[0].Kid)
+	pub, err := keys[0].PublicKey()
+	require.NoError(t, err)
+	assert.Equal(t, key.Public(), pub)
+
+	_, err = jwtAuthorities([]*pb.IssuerInfo{
+		{Label: "e", TrustDomain: "trusty.ekspand.com", JwtKeyId: "kid3", JwtPublicKey: "invalid"},
+	}, "trusty.ekspand.com")
+	assert.EqualError(t, err, "invalid JWT public key: e")
+}
@@ -0,0 +1,199 @@
+package ra
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/ekspand/trusty/pkg/authz"
+	"github.com/go-phorce/dolly/algorithms/slices"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/go-phorce/dolly/xlog"
+	"github.com/juju/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// ListApprovals returns the certificate requests in the approval queue
+func (s *Service) ListApprovals(ctx context.Context, req *pb.ListApprovalsRequest) (*pb.ApprovalsResponse, error) {
+	status := ""
+	if !req.All {
+		status = model.ApprovalStatus(req.Status)
+	}
+
+	list, err := s.db.ListApprovals(ctx, status, int(req.Limit), req.After)
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "unable to list approvals",
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.Internal, "unable to list approvals")
+	}
+
+	return &pb.ApprovalsResponse{
+		List: list.ToDTO(),
+	}, nil
+}
+
+// GetApproval returns the certificate request in the approval queue,
+// with the certificate when the request is issued
+func (s *Service) GetApproval(ctx context.Context, req *pb.GetApprovalRequest) (*pb.ApprovalResponse, error) {
+	a, err := s.getApproval(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.IsExpired(time.Now()) {
+		a.Status = model.ApprovalStatusExpired
+	}
+
+	dto := a.ToDTO()
+	if a.CertificateID != 0 {
+		c, err := s.db.GetCertificate(ctx, a.CertificateID)
+		if err != nil {
+			logger.KV(xlog.ERROR,
+				"status", "unable to get certificate",
+				"id", a.CertificateID,
+				"err", errors.Details(err))
+			return nil, v1.NewError(codes.Internal, "unable to get certificate")
+		}
+		dto.Certificate = c.ToDTO()
+	}
+
+	return &pb.ApprovalResponse{
+		Approval: dto,
+	}, nil
+}
+
+// Approve approves the certificate request,
+// the certificate is issued when the required approvals are received
+func (s *Service) Approve(ctx context.Context, req *pb.ApprovalDecisionRequest) (*pb.ApprovalResponse, error) {
+	return s.vote(ctx, req, true)
+}
+
+// Deny denies the certificate request
+func (s *Service) Deny(ctx context.Context, req *pb.ApprovalDecisionRequest) (*pb.ApprovalResponse, error) {
+	return s.vote(ctx, req, false)
+}
+
+func (s *Service) vote(ctx context.Context, req *pb.ApprovalDecisionRequest, approved bool) (*pb.ApprovalResponse, error) {
+	var contextID string
+	var idn identity.Identity
+	if callerCtx := identity.FromContext(ctx); callerCtx != nil {
+		contextID = callerCtx.CorrelationID()
+		idn = callerCtx.Identity()
+	}
+	approver := ""
+	if idn != nil {
+		approver = idn.Name()
+	}
+
+	a, err := s.getApproval(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isApprover(authz.RolesOf(idn), a.ApproverRoles) || approver == "" {
+		return nil, v1.NewError(codes.PermissionDenied, "the caller is not an approver of the request")
+	}
+	if strings.EqualFold(approver, a.Requester) {
+		return nil, v1.NewError(codes.PermissionDenied, "the requester can not approve or deny own request")
+	}
+
+	// the approved request is issued again, if the previous attempt failed
+	if !(approved && a.Status == model.ApprovalStatusApproved) {
+		a, err = s.db.VoteApproval(ctx, &model.ApprovalVote{
+			ApprovalID: a.ID,
+			Approver:   approver,
+			Approved:   approved,
+			Reason:     req.Reason,
+		}, time.Now())
+		if err != nil {
+			switch {
+			case errors.IsNotValid(err):
+				return nil, v1.NewError(codes.FailedPrecondition, "the request is not pending: %s", err.Error())
+			case errors.IsAlreadyExists(err):
+				return nil, v1.NewError(codes.AlreadyExists, "the approver already voted")
+			case errors.IsNotFound(err):
+				return nil, v1.NewError(codes.NotFound, "approval not found: %d", req.Id)
+			}
+			logger.KV(xlog.ERROR,
+				"status", "unable to vote",
+				"id", req.Id,
+				"err", errors.Details(err))
+			return nil, v1.NewError(codes.Internal, "unable to vote")
+		}
+
+		evt := evtApprovalDenied
+		if approved {
+			evt = evtApprovalApproved
+		}
+		s.server.Audit(
+			ServiceName,
+			evt,
+			approver,
+			contextID,
+			0,
+			fmt.Sprintf("id=%d, profile=%q, subject=%q, requester=%q, reason=%q, status=%s, approvals=%d/%d",
+				a.ID, a.Profile, a.Subject, a.Requester, req.Reason, a.Status, a.Approvals(), a.RequiredApprovals),
+		)
+	}
+
+	dto := a.ToDTO()
+	if a.Status == model.ApprovalStatusApproved {
+		dto, err = s.issueApproved(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &pb.ApprovalResponse{
+		Approval: dto,
+	}, nil
+}
+
+// issueApproved requests CA to issue the certificate for the approved request
+func (s *Service) issueApproved(ctx context.Context, a *model.Approval) (*pb.Approval, error) {
+	ca, err := s.getCAClient()
+	if err != nil {
+		return nil, v1.NewError(codes.Unavailable, "the request is approved, but CA is not available")
+	}
+
+	res, err := ca.SignCertificate(ctx, &pb.SignCertificateRequest{
+		ApprovalId: a.ID,
+	})
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "failed to issue approved request",
+			"id", a.ID,
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.Unavailable, "the request is approved, but the certificate is not issued")
+	}
+	return res.Approval, nil
+}
+
+func (s *Service) getApproval(ctx context.Context, id uint64) (*model.Approval, error) {
+	a, err := s.db.GetApproval(ctx, id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, v1.NewError(codes.NotFound, "approval not found: %d", id)
+		}
+		logger.KV(xlog.ERROR,
+			"status", "unable to get approval",
+			"id", id,
+			"err", errors.Details(err))
+		return nil, v1.NewError(codes.Internal, "unable to get approval")
+	}
+	return a, nil
+}
+
+func isApprover(roles, approvers []string) bool {
+	for _, role := range roles {
+		if slices.ContainsString(approvers, role) {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package ra
 import (
 	"context"
 	"sync"
+	"time"
 
 	pb "github.com/ekspand/trusty/api/v1/pb"
 	"github.com/ekspand/trusty/client"
@@ -13,6 +14,7 @@ import (
 	"github.com/ekspand/trusty/pkg/gserver"
 	"github.com/ekspand/trusty/pkg/poller"
 	"github.com/go-phorce/dolly/rest"
+	"github.com/go-phorce/dolly/tasks"
 	"github.com/go-phorce/dolly/xlog"
 	"github.com/go-phorce/dolly/xpki/certutil"
 	"github.com/juju/errors"
@@ -24,11 +26,17 @@ const ServiceName = "ra"
 
 var logger = xlog.NewPackageLogger("github.com/ekspand/trusty/backend/service", "ra")
 
+const (
+	evtApprovalApproved = "ApprovalApproved"
+	evtApprovalDenied   = "ApprovalDenied"
+)
+
 // Service defines the Status service
 type Service struct {
 	server        *gserver.Server
 	db            db.CertsDb
 	clientFactory client.Factory
+	scheduler     tasks.Scheduler
 	grpClient     *client.Client
 	ca            client.CAClient
 	registered    bool
@@ -44,12 +52,13 @@ func Factory(server *gserver.Server) interface{} {
 		logger.Panic("status.Factory: invalid parameter")
 	}
 
-	return func(cfg *config.Configuration, db db.CertsDb, clientFactory client.Factory) {
+	return func(cfg *config.Configuration, db db.CertsDb, clientFactory client.Factory, scheduler tasks.Scheduler) {
 		svc := &Service{
 			server:        server,
 			cfg:           cfg,
 			db:            db,
 			clientFactory: clientFactory,
+			scheduler:     scheduler,
 		}
 
 		svc.ctx, svc.cancel = context.WithCancel(context.Background())
@@ -109,9 +118,26 @@ func (s *Service) OnStarted() error {
 	p.Start(s.ctx, s.cfg.TrustyClient.DialKeepAliveTimeout)
 	go s.getCAClient()
 
+	s.scheduler.Add(tasks.NewTaskAtIntervals(1, tasks.Minutes).
+		Do("expire_approvals", s.expireApprovals))
+
 	return nil
 }
 
+// expireApprovals marks the pending approval requests as expired
+func (s *Service) expireApprovals() {
+	count, err := s.db.ExpireApprovals(s.ctx, time.Now())
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"status", "failed to expire approvals",
+			"err", errors.Details(err))
+		return
+	}
+	if count > 0 {
+		logger.KV(xlog.NOTICE, "status", "expired approvals", "count", count)
+	}
+}
+
 func (s *Service) registerCert(ctx context.Context, trust pb.Trust, location string) error {
 	crt, err := certutil.LoadFromPEM(location)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 
 	pb "github.com/ekspand/trusty/api/v1/pb"
 	"github.com/ekspand/trusty/internal/version"
+	"github.com/ekspand/trusty/pkg/authz"
 	"github.com/go-phorce/dolly/xhttp/identity"
 	"github.com/golang/protobuf/ptypes/empty"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -42,17 +43,32 @@ func (s *Service) Caller(ctx context.Context, _ *empty.Empty) (*pb.CallerStatusR
 	callerCtx := identity.FromContext(ctx)
 	role := identity.GuestRoleName
 	var id, name string
+	var caller identity.Identity
 	if callerCtx != nil {
-		caller := callerCtx.Identity()
+		caller = callerCtx.Identity()
 		id = caller.UserID()
 		name = caller.Name()
 		role = caller.Role()
 	}
+	roles := authz.RolesOf(caller)
 
 	res := &pb.CallerStatusResponse{
-		Id:   id,
-		Name: name,
-		Role: role,
+		Id:    id,
+		Name:  name,
+		Role:  role,
+		Roles: roles,
+	}
+
+	if policy := s.server.Policy(); policy != nil {
+		for _, p := range policy.PermissionsFor(roles...) {
+			res.Permissions = append(res.Permissions, &pb.CallerPermission{
+				Name:     p.Name,
+				Methods:  p.Methods,
+				Issuers:  p.Issuers,
+				Profiles: p.Profiles,
+				Orgs:     p.Orgs,
+			})
+		}
 	}
 
 	return res, nil
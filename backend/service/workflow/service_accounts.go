@@ -0,0 +1,302 @@
+package workflow
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/go-phorce/dolly/rest"
+	"github.com/go-phorce/dolly/xhttp/httperror"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/go-phorce/dolly/xhttp/marshal"
+	"github.com/juju/errors"
+)
+
+const (
+	evtServiceAccountCreated = "service_account_created"
+	evtServiceAccountDeleted = "service_account_deleted"
+	evtAPITokenCreated       = "api_token_created"
+	evtAPITokenRevoked       = "api_token_revoked"
+)
+
+// ServiceAccountsHandler returns service accounts of the org
+func (s *Service) ServiceAccountsHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p rest.Params) {
+		orgID, err := model.ID(r.URL.Query().Get("org_id"))
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithInvalidParam("invalid org_id"))
+			return
+		}
+
+		_, herr := s.orgMember(r, orgID, false)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+
+		list, err := s.db.GetOrgServiceAccounts(r.Context(), orgID)
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to get service accounts: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		marshal.WriteJSON(w, r, &v1.ServiceAccountsResponse{
+			ServiceAccounts: model.ToServiceAccountsDto(list),
+		})
+	}
+}
+
+// CreateServiceAccountHandler creates service account for the org
+func (s *Service) CreateServiceAccountHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p rest.Params) {
+		req := new(v1.CreateServiceAccountRequest)
+		err := marshal.DecodeBody(w, r, req)
+		if err != nil {
+			return
+		}
+
+		orgID, err := model.ID(req.OrgID)
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithInvalidRequest("invalid org_id"))
+			return
+		}
+
+		user, herr := s.orgMember(r, orgID, true)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+
+		sa := &model.ServiceAccount{
+			OrgID:       orgID,
+			Name:        req.Name,
+			Description: req.Description,
+			CreatedBy:   user.ID,
+		}
+		if err = sa.Validate(); err != nil {
+			marshal.WriteJSON(w, r, httperror.WithInvalidRequest(err.Error()))
+			return
+		}
+
+		sa, err = s.db.CreateServiceAccount(r.Context(), sa)
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to create service account: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		s.audit(r, evtServiceAccountCreated, user,
+			fmt.Sprintf("id=%d, org_id=%d, name=%q", sa.ID, sa.OrgID, sa.Name))
+
+		marshal.WriteJSON(w, r, &v1.ServiceAccountResponse{
+			ServiceAccount: sa.ToDto(),
+		})
+	}
+}
+
+// DeleteServiceAccountHandler deletes service account and its tokens
+func (s *Service) DeleteServiceAccountHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p rest.Params) {
+		sa, user, herr := s.serviceAccount(r, p, true)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+
+		err := s.db.RemoveServiceAccount(r.Context(), sa.ID)
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to delete service account: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		s.audit(r, evtServiceAccountDeleted, user,
+			fmt.Sprintf("id=%d, org_id=%d, name=%q", sa.ID, sa.OrgID, sa.Name))
+
+		marshal.WriteJSON(w, r, &v1.ServiceAccountResponse{
+			ServiceAccount: sa.ToDto(),
+		})
+	}
+}
+
+// APITokensHandler returns API tokens of the service account
+func (s *Service) APITokensHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p rest.Params) {
+		sa, _, herr := s.serviceAccount(r, p, false)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+
+		list, err := s.db.GetServiceAccountTokens(r.Context(), sa.ID)
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to get API tokens: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		marshal.WriteJSON(w, r, &v1.APITokensResponse{
+			Tokens: model.ToAPITokensDto(list),
+		})
+	}
+}
+
+// CreateAPITokenHandler creates API token for the service account
+func (s *Service) CreateAPITokenHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p rest.Params) {
+		req := new(v1.CreateAPITokenRequest)
+		err := marshal.DecodeBody(w, r, req)
+		if err != nil {
+			return
+		}
+
+		var expiresAt sql.NullTime
+		if req.Expiry != "" {
+			expiry, err := time.ParseDuration(req.Expiry)
+			if err != nil || expiry <= 0 {
+				marshal.WriteJSON(w, r, httperror.WithInvalidRequest("invalid expiry: %q", req.Expiry))
+				return
+			}
+			expiresAt = sql.NullTime{Time: time.Now().Add(expiry).UTC(), Valid: true}
+		}
+
+		sa, user, herr := s.serviceAccount(r, p, true)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+
+		secret, err := model.NewAPITokenSecret()
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to generate API token").WithCause(err))
+			return
+		}
+
+		token := &model.APIToken{
+			ServiceAccountID: sa.ID,
+			Name:             req.Name,
+			TokenHash:        model.HashAPIToken(secret),
+			CreatedBy:        user.ID,
+			ExpiresAt:        expiresAt,
+		}
+		if err = token.Validate(); err != nil {
+			marshal.WriteJSON(w, r, httperror.WithInvalidRequest(err.Error()))
+			return
+		}
+
+		token, err = s.db.CreateAPIToken(r.Context(), token)
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to create API token: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		s.audit(r, evtAPITokenCreated, user,
+			fmt.Sprintf("id=%d, service_account_id=%d, name=%q", token.ID, sa.ID, token.Name))
+
+		marshal.WriteJSON(w, r, &v1.CreateAPITokenResponse{
+			Token:  token.ToDto(),
+			Secret: secret,
+		})
+	}
+}
+
+// RevokeAPITokenHandler revokes API token of the service account
+func (s *Service) RevokeAPITokenHandler() rest.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p rest.Params) {
+		tokenID, err := model.ID(p.ByName("token_id"))
+		if err != nil {
+			marshal.WriteJSON(w, r, httperror.WithInvalidParam("invalid token_id"))
+			return
+		}
+
+		sa, user, herr := s.serviceAccount(r, p, true)
+		if herr != nil {
+			marshal.WriteJSON(w, r, herr)
+			return
+		}
+
+		token, err := s.db.RevokeAPIToken(r.Context(), sa.ID, tokenID, time.Now().UTC())
+		if err != nil {
+			if errors.Cause(err) == sql.ErrNoRows {
+				marshal.WriteJSON(w, r, httperror.WithNotFound("API token %d not found", tokenID))
+				return
+			}
+			marshal.WriteJSON(w, r, httperror.WithUnexpected("unable to revoke API token: %s", err.Error()).WithCause(err))
+			return
+		}
+
+		s.audit(r, evtAPITokenRevoked, user,
+			fmt.Sprintf("id=%d, service_account_id=%d, name=%q", token.ID, sa.ID, token.Name))
+
+		marshal.WriteJSON(w, r, &v1.APITokenResponse{
+			Token: token.ToDto(),
+		})
+	}
+}
+
+// serviceAccount returns the service account specified by :id parameter,
+// if the caller is a member of its org
+func (s *Service) serviceAccount(r *http.Request, p rest.Params, admin bool) (*model.ServiceAccount, *model.User, *httperror.Error) {
+	id, err := model.ID(p.ByName("id"))
+	if err != nil {
+		return nil, nil, httperror.WithInvalidParam("invalid service account ID")
+	}
+
+	sa, err := s.db.GetServiceAccount(r.Context(), id)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, nil, httperror.WithNotFound("service account %d not found", id)
+		}
+		return nil, nil, httperror.WithUnexpected("unable to get service account: %s", err.Error()).WithCause(err)
+	}
+
+	user, herr := s.orgMember(r, sa.OrgID, admin)
+	if herr != nil {
+		return nil, nil, herr
+	}
+	return sa, user, nil
+}
+
+// orgMember returns the caller, if the caller is a member of the org.
+// Service accounts can not be managed with API tokens.
+func (s *Service) orgMember(r *http.Request, orgID uint64, admin bool) (*model.User, *httperror.Error) {
+	idn := identity.FromRequest(r).Identity()
+	if idn.UserID() == "" {
+		return nil, httperror.WithForbidden("service accounts can be managed only by users")
+	}
+	userID, err := model.ID(idn.UserID())
+	if err != nil {
+		return nil, httperror.WithForbidden("invalid user ID: %s", idn.UserID())
+	}
+
+	ctx := r.Context()
+	user, err := s.db.GetUser(ctx, userID)
+	if err != nil {
+		return nil, httperror.WithForbidden("user ID %d not found: %s", userID, err.Error()).WithCause(err)
+	}
+
+	memberships, err := s.db.GetUserMemberships(ctx, userID)
+	if err != nil {
+		return nil, httperror.WithUnexpected("unable to get memberships: %s", err.Error()).WithCause(err)
+	}
+	for _, m := range memberships {
+		if m.OrgID == orgID {
+			if admin && m.GetRole() != v1.RoleAdmin {
+				return nil, httperror.WithForbidden("admin role is required in org %d", orgID)
+			}
+			return user, nil
+		}
+	}
+	return nil, httperror.WithForbidden("user is not a member of org %d", orgID)
+}
+
+func (s *Service) audit(r *http.Request, eventType string, user *model.User, message string) {
+	s.server.Audit(
+		ServiceName,
+		eventType,
+		user.Email,
+		identity.FromRequest(r).CorrelationID(),
+		0,
+		message,
+	)
+}
@@ -74,6 +74,13 @@ func (s *Service) RegisterRoute(r rest.Router) {
 	r.GET(v1.PathForWorkflowRepos, s.GetReposHandler())
 	r.GET(v1.PathForWorkflowOrgs, s.GetOrgsHandler())
 	r.GET(v1.PathForWorkflowSyncOrgs, s.SyncOrgsHandler())
+
+	r.GET(v1.PathForServiceAccounts, s.ServiceAccountsHandler())
+	r.POST(v1.PathForServiceAccounts, s.CreateServiceAccountHandler())
+	r.DELETE(v1.PathForServiceAccount, s.DeleteServiceAccountHandler())
+	r.GET(v1.PathForServiceAccountTokens, s.APITokensHandler())
+	r.POST(v1.PathForServiceAccountTokens, s.CreateAPITokenHandler())
+	r.DELETE(v1.PathForServiceAccountToken, s.RevokeAPITokenHandler())
 }
 
 // OAuthConfig returns oauth2client.Config,
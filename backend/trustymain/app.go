@@ -174,6 +174,12 @@ func (a *App) Container() (*dig.Container, error) {
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		err = a.container.Invoke(func(scheduler tasks.Scheduler) {
+			a.scheduler = scheduler
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 	return a.container, nil
 }
@@ -283,10 +289,14 @@ func (a *App) Run(startedCh chan<- bool) error {
 	}
 
 	// register for signals, and wait to be shutdown
-	signal.Notify(a.sigs, os.Interrupt, os.Kill, syscall.SIGTERM, syscall.SIGUSR2, syscall.SIGABRT)
+	signal.Notify(a.sigs, os.Interrupt, os.Kill, syscall.SIGTERM, syscall.SIGUSR2, syscall.SIGABRT, syscall.SIGHUP)
 
 	// Block until a signal is received.
 	sig := <-a.sigs
+	for sig == syscall.SIGHUP {
+		a.reloadServices()
+		sig = <-a.sigs
+	}
 	logger.Warningf("status=shuting_down, sig=%v", sig)
 
 	a.stopServers()
@@ -313,6 +323,27 @@ func (a *App) Server(name string) *gserver.Server {
 	return a.servers[name]
 }
 
+// reloadServices notifies services to reload configuration,
+// the services keep running with the current configuration if reload fails
+func (a *App) reloadServices() {
+	logger.Info("status=reloading")
+	err := a.container.Invoke(func(disco appcontainer.Discovery) error {
+		var svc gserver.Service
+		return disco.ForEach(&svc, func(key string) error {
+			if reloader, ok := svc.(gserver.ReloadSubscriber); ok {
+				logger.Infof("onreload=running, key=%s, service=%s", key, svc.Name())
+				if err := reloader.OnReload(); err != nil {
+					logger.Errorf("onreload=failed, key=%s, service=%s, err=[%v]", key, svc.Name(), errors.ErrorStack(err))
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		logger.Errorf("reason=reload, err=[%v]", errors.ErrorStack(err))
+	}
+}
+
 func (a *App) stopServers() {
 	if a.scheduler != nil {
 		a.scheduler.Stop()
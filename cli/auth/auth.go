@@ -6,12 +6,12 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
-	"strings"
+	"time"
 
 	v1 "github.com/ekspand/trusty/api/v1"
 	"github.com/ekspand/trusty/cli"
 	"github.com/go-phorce/dolly/ctl"
-	"github.com/go-phorce/dolly/rest/tlsconfig"
+	"github.com/go-phorce/dolly/xhttp/httperror"
 	"github.com/go-phorce/dolly/xhttp/retriable"
 	"github.com/juju/errors"
 )
@@ -20,6 +20,9 @@ import (
 type AuthenticateFlags struct {
 	NoBrowser *bool
 	Provider  *string
+	// Device specifies to use device authorization flow,
+	// for hosts without browser
+	Device *bool
 }
 
 // Authenticate starts authentication
@@ -34,23 +37,22 @@ func Authenticate(c ctl.Control, p interface{}) error {
 
 	hn, _ := os.Hostname()
 
-	httpClient := retriable.New()
-
-	if strings.HasPrefix(srv, "https://") {
-		tlscfg, err := tlsconfig.NewClientTLSFromFiles("", "", cli.TLSCAFile())
-		if err != nil {
-			return errors.Annotate(err, "unable to build TLS configuration")
-		}
-		httpClient.WithTLS(tlscfg)
+	httpClient, err := cli.HTTPClient(srv)
+	if err != nil {
+		return errors.Trace(err)
 	}
 
 	if flags.Provider == nil || *flags.Provider == "" {
 		return errors.New("please specify --provider parameter")
 	}
 
+	if flags.Device != nil && *flags.Device {
+		return deviceLogin(cli, httpClient, srv, hn, *flags.Provider)
+	}
+
 	res := new(v1.AuthStsURLResponse)
 	path := fmt.Sprintf("%s?redirect_url=%s/v1/auth/done&device_id=%s&sts=%s", v1.PathForAuthURL, srv, hn, *flags.Provider)
-	_, _, err := httpClient.Request(context.Background(), "GET", []string{srv}, path, nil, res)
+	_, _, err = httpClient.Request(context.Background(), "GET", []string{srv}, path, nil, res)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -71,3 +73,74 @@ func Authenticate(c ctl.Control, p interface{}) error {
 
 	return nil
 }
+
+// deviceLogin authenticates with device authorization flow, as defined in RFC 8628,
+// and stores the access token in the credentials file
+func deviceLogin(cli *cli.Cli, httpClient *retriable.Client, srv, deviceID, provider string) error {
+	ctx := context.Background()
+	req := &v1.DeviceAuthorizationRequest{
+		DeviceID: deviceID,
+		Provider: provider,
+	}
+	res := new(v1.DeviceAuthorizationResponse)
+	_, _, err := httpClient.Request(ctx, "POST", []string{srv}, v1.PathForAuthDeviceCode, req, res)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	fmt.Fprintf(cli.Writer(), "open in browser:\n%s\n\nand enter the code: %s\n", res.VerificationURI, res.UserCode)
+
+	interval := time.Duration(res.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	expiresAt := time.Now().Add(time.Duration(res.ExpiresIn) * time.Second)
+
+	tokenReq := &v1.DeviceTokenRequest{DeviceCode: res.DeviceCode}
+	for {
+		time.Sleep(interval)
+
+		tokenRes := new(v1.AuthTokenRefreshResponse)
+		_, _, err = httpClient.Request(ctx, "POST", []string{srv}, v1.PathForAuthDeviceToken, tokenReq, tokenRes)
+		if err == nil {
+			if tokenRes.Authorization == nil || tokenRes.Authorization.AccessToken == "" {
+				return errors.New("access token is not returned")
+			}
+			return saveCredentials(cli, srv, tokenRes.Authorization)
+		}
+
+		switch deviceErrorCode(err) {
+		case v1.DeviceAuthorizationPending:
+		case v1.DeviceSlowDown:
+			interval += 5 * time.Second
+		default:
+			return errors.Trace(err)
+		}
+
+		if !time.Now().Before(expiresAt) {
+			return errors.New("the code is expired, please login again")
+		}
+	}
+}
+
+func saveCredentials(c *cli.Cli, srv string, auth *v1.Authorization) error {
+	file := cli.CredentialsFile()
+	creds := &cli.Credentials{
+		Server:        srv,
+		Authorization: auth,
+	}
+	if err := creds.Save(file); err != nil {
+		return errors.Annotatef(err, "unable to save credentials")
+	}
+
+	fmt.Fprintf(c.Writer(), "\nlogged in as %s, credentials saved in %s\n", auth.Email, file)
+	return nil
+}
+
+// deviceErrorCode returns error code of the device token response
+func deviceErrorCode(err error) string {
+	if herr, ok := errors.Cause(err).(*httperror.Error); ok {
+		return herr.Code
+	}
+	return ""
+}
@@ -4,8 +4,12 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/ekspand/trusty/cli"
 	"github.com/ekspand/trusty/cli/auth"
 	"github.com/ekspand/trusty/cli/testsuite"
 	"github.com/stretchr/testify/suite"
@@ -37,6 +41,75 @@ func (s *testSuite) TestAuth() {
 	s.HasText("open auth URL in browser:\n")
 }
 
+func (s *testSuite) TestDeviceLogin() {
+	file := filepath.Join(s.T().TempDir(), "credentials.json")
+	os.Setenv("TRUSTY_CREDENTIALS", file)
+	defer os.Unsetenv("TRUSTY_CREDENTIALS")
+
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case v1.PathForAuthDeviceCode:
+			w.WriteHeader(http.StatusOK)
+			io.WriteString(w, `{
+				"device_code": "DeviceCode123",
+				"user_code": "BCDF-GHJK",
+				"verification_uri": "https://localhost:7891/v1/auth/device",
+				"expires_in": 600,
+				"interval": 1
+			}`)
+		case v1.PathForAuthDeviceToken:
+			polls++
+			if polls == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				io.WriteString(w, `{"code":"authorization_pending","message":"the authorization is pending"}`)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			io.WriteString(w, `{"authorization":{"device_id":"jumphost","email":"denis@trusty.com","access_token":"AccessToken123","expires_at":"2031-06-01T10:00:00Z"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	s.Cli.WithServer(server.URL)
+
+	device := true
+	provider := "github"
+	err := s.Run(auth.Authenticate, &auth.AuthenticateFlags{Device: &device, Provider: &provider})
+	s.Require().NoError(err)
+	s.HasText("https://localhost:7891/v1/auth/device", "BCDF-GHJK", "logged in as denis@trusty.com")
+	s.Equal(2, polls)
+
+	creds, err := cli.LoadCredentials(file)
+	s.Require().NoError(err)
+	s.Equal(server.URL, creds.Server)
+	s.Equal("AccessToken123", creds.Authorization.AccessToken)
+}
+
+func (s *testSuite) TestDeviceLoginExpired() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case v1.PathForAuthDeviceCode:
+			w.WriteHeader(http.StatusOK)
+			io.WriteString(w, `{"device_code":"DeviceCode123","user_code":"BCDF-GHJK","expires_in":600,"interval":1}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"code":"expired_token","message":"the device code is expired"}`)
+		}
+	}))
+	defer server.Close()
+
+	s.Cli.WithServer(server.URL)
+
+	device := true
+	provider := "github"
+	err := s.Run(auth.Authenticate, &auth.AuthenticateFlags{Device: &device, Provider: &provider})
+	s.Require().Error(err)
+	s.Contains(err.Error(), "the device code is expired")
+}
+
 func makeTestHandler(t *testing.T, responseBody string) http.Handler {
 	h := func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
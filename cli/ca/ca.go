@@ -39,11 +39,37 @@ func Issuers(c ctl.Control, _ interface{}) error {
 	return nil
 }
 
+// Reload reloads the CA configuration and shows the Issuing CAs
+func Reload(c ctl.Control, _ interface{}) error {
+	cli := c.(*cli.Cli)
+
+	client, err := cli.Client(config.CAServerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	res, err := client.CAClient().ReloadConfig(context.Background())
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		print.Issuers(c.Writer(), res.Issuers, true)
+	}
+	return nil
+}
+
 // ListCertsFlags defines flags for ListCerts command
 type ListCertsFlags struct {
-	Ikid  *string
-	Limit *int
-	After *string
+	Ikid *string
+	// Selector specifies the label selector, for example: env=prod,team,!deprecated
+	Selector *string
+	Limit    *int
+	After    *string
 }
 
 // ListCerts prints the certifiates
@@ -65,9 +91,10 @@ func ListCerts(c ctl.Control, p interface{}) error {
 	}
 
 	res, err := client.CAClient().ListCertificates(context.Background(), &pb.ListByIssuerRequest{
-		Ikid:  *flags.Ikid,
-		Limit: int64(*flags.Limit),
-		After: after,
+		Ikid:          stringValue(flags.Ikid),
+		LabelSelector: stringValue(flags.Selector),
+		Limit:         int64(*flags.Limit),
+		After:         after,
 	})
 	if err != nil {
 		return errors.Trace(err)
@@ -102,9 +129,10 @@ func ListRevokedCerts(c ctl.Control, p interface{}) error {
 	}
 
 	res, err := client.CAClient().ListRevokedCertificates(context.Background(), &pb.ListByIssuerRequest{
-		Ikid:  *flags.Ikid,
-		Limit: int64(*flags.Limit),
-		After: after,
+		Ikid:          stringValue(flags.Ikid),
+		LabelSelector: stringValue(flags.Selector),
+		Limit:         int64(*flags.Limit),
+		After:         after,
 	})
 	if err != nil {
 		return errors.Trace(err)
@@ -158,7 +186,9 @@ type SignFlags struct {
 	IssuerLabel *string
 	Token       *string
 	SAN         *[]string
-	Out         *string
+	// Labels specifies key=value labels for the certificate
+	Labels *[]string
+	Out    *string
 }
 
 // Sign certificate request
@@ -176,6 +206,14 @@ func Sign(c ctl.Control, p interface{}) error {
 		return errors.Annotatef(err, "failed to load request")
 	}
 
+	var labels map[string]string
+	if flags.Labels != nil {
+		labels, err = parseLabels(*flags.Labels)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	res, err := client.CAClient().SignCertificate(context.Background(), &pb.SignCertificateRequest{
 		RequestFormat: pb.EncodingFormat_PEM,
 		Request:       string(csr),
@@ -183,11 +221,23 @@ func Sign(c ctl.Control, p interface{}) error {
 		IssuerLabel:   *flags.IssuerLabel,
 		San:           *flags.SAN,
 		Token:         *flags.Token,
+		Labels:        labels,
 	})
 	if err != nil {
 		return errors.Trace(err)
 	}
 
+	if res.Certificate == nil && res.Approval != nil {
+		if cli.IsJSON() {
+			ctl.WriteJSON(c.Writer(), res)
+			fmt.Fprint(c.Writer(), "\n")
+		} else {
+			fmt.Fprintf(c.Writer(), "The request requires approval, use `trustyctl ra request --id %d --wait` to get the certificate\n\n", res.Approval.Id)
+			print.Approval(c.Writer(), res.Approval)
+		}
+		return nil
+	}
+
 	pem := res.Certificate.Pem
 	if !strings.HasSuffix(pem, "\n") {
 		pem += "\n"
@@ -241,3 +291,86 @@ func PublishCrls(c ctl.Control, p interface{}) error {
 
 	return nil
 }
+
+// UpdateLabelsFlags defines flags for UpdateLabels command
+type UpdateLabelsFlags struct {
+	ID   *string
+	SKID *string
+	// Set specifies key=value labels to add or update
+	Set *[]string
+	// Remove specifies label keys to remove
+	Remove *[]string
+}
+
+// UpdateLabels updates the labels of the certificate
+func UpdateLabels(c ctl.Control, p interface{}) error {
+	flags := p.(*UpdateLabelsFlags)
+
+	req := &pb.UpdateCertificateLabelsRequest{
+		Skid: stringValue(flags.SKID),
+	}
+
+	var err error
+	if id := stringValue(flags.ID); id != "" {
+		req.Id, err = model.ID(id)
+		if err != nil {
+			return errors.Annotate(err, "unable to parse --id")
+		}
+	}
+	if req.Id == 0 && req.Skid == "" {
+		return errors.New("either --id or --skid must be specified")
+	}
+	if flags.Set != nil {
+		req.Labels, err = parseLabels(*flags.Set)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if flags.Remove != nil {
+		req.Remove = *flags.Remove
+	}
+
+	cli := c.(*cli.Cli)
+	client, err := cli.Client(config.CAServerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	res, err := client.CAClient().UpdateCertificateLabels(context.Background(), req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		print.CertificatesTable(c.Writer(), []*pb.Certificate{res.Certificate})
+	}
+
+	return nil
+}
+
+// parseLabels parses the list of key=value labels
+func parseLabels(list []string) (map[string]string, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(list))
+	for _, l := range list {
+		kv := strings.SplitN(l, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errors.Errorf("invalid label %q, expected key=value", l)
+		}
+		labels[kv[0]] = kv[1]
+	}
+	return labels, nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
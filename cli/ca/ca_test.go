@@ -57,6 +57,33 @@ func (s *testSuite) TestIssuers() {
 	}
 }
 
+func (s *testSuite) TestReload() {
+	expectedResponse := new(pb.IssuersInfoResponse)
+	err := loadJSON("testdata/issuers.json", expectedResponse)
+	s.Require().NoError(err)
+
+	s.MockAuthority = &mockpb.MockCAServer{
+		Err:   nil,
+		Resps: []proto.Message{expectedResponse},
+	}
+	srv := s.SetupMockGRPC()
+	defer srv.Stop()
+
+	err = s.Run(ca.Reload, nil)
+	s.Require().NoError(err)
+
+	if s.Cli.IsJSON() {
+		s.HasText("{\n\t\"issuers\": [\n")
+	} else {
+		s.HasText("Subject: C=US, L=WA, O=trusty.com, CN=[TEST] Trusty Level 2 CA\n")
+	}
+
+	s.MockAuthority.Err = errors.New("failed to reload configuration: invalid config")
+	err = s.Run(ca.Reload, nil)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "failed to reload configuration: invalid config")
+}
+
 func (s *testSuite) TestProfile() {
 	expectedResponse := new(pb.CertProfileInfo)
 	err := loadJSON("testdata/server_profile.json", expectedResponse)
@@ -138,6 +165,30 @@ func (s *testSuite) TestSign() {
 		IssuerLabel: &empty,
 	})
 	s.Require().NoError(err)
+
+	s.MockAuthority.SetResponse(&pb.CertificateResponse{
+		Approval: &pb.Approval{
+			Id:                1235,
+			Profile:           "server",
+			Subject:           "CN=server",
+			Status:            pb.ApprovalStatus_PENDING,
+			RequiredApprovals: 2,
+		},
+	})
+	err = s.Run(ca.Sign, &ca.SignFlags{
+		Profile:     &profile,
+		Request:     &req,
+		Token:       &empty,
+		SAN:         &san,
+		IssuerLabel: &empty,
+	})
+	s.Require().NoError(err)
+	if s.Cli.IsJSON() {
+		s.HasText(`"id": 1235`)
+	} else {
+		s.HasText("The request requires approval, use `trustyctl ra request --id 1235 --wait` to get the certificate",
+			"Status    | PENDING")
+	}
 }
 
 func (s *testSuite) TestListCerts() {
@@ -169,6 +220,98 @@ func (s *testSuite) TestListCerts() {
 	}
 }
 
+func (s *testSuite) TestListCertsWithSelector() {
+	s.MockAuthority = &mockpb.MockCAServer{
+		Err: nil,
+		Resps: []proto.Message{&pb.CertificatesResponse{
+			List: []*pb.Certificate{
+				{
+					Id:      1234,
+					Profile: "server",
+					Labels:  map[string]string{"env": "prod", "team": "security"},
+				},
+			},
+		}},
+	}
+	srv := s.SetupMockGRPC()
+	defer srv.Stop()
+
+	limit := 3
+	empty := ""
+	selector := "env=prod"
+	err := s.Run(ca.ListCerts, &ca.ListCertsFlags{
+		Ikid:     &empty,
+		Selector: &selector,
+		Limit:    &limit,
+		After:    &empty,
+	})
+	s.Require().NoError(err)
+
+	if s.Cli.IsJSON() {
+		s.HasText(`"env": "prod"`)
+	} else {
+		s.HasText("LABELS", "env=prod,team=security")
+	}
+}
+
+func (s *testSuite) TestUpdateLabels() {
+	s.MockAuthority = &mockpb.MockCAServer{
+		Err: nil,
+		Resps: []proto.Message{&pb.CertificateResponse{
+			Certificate: &pb.Certificate{
+				Id:      1234,
+				Skid:    "23423",
+				Profile: "server",
+				Labels:  map[string]string{"env": "prod"},
+			},
+		}},
+	}
+	srv := s.SetupMockGRPC()
+	defer srv.Stop()
+
+	empty := ""
+	set := []string{"env=prod"}
+	remove := []string{"team"}
+	err := s.Run(ca.UpdateLabels, &ca.UpdateLabelsFlags{
+		ID:     &empty,
+		SKID:   &empty,
+		Set:    &set,
+		Remove: &remove,
+	})
+	s.Require().Error(err)
+	s.Equal("either --id or --skid must be specified", err.Error())
+
+	id := "notnumber"
+	err = s.Run(ca.UpdateLabels, &ca.UpdateLabelsFlags{
+		ID:  &id,
+		Set: &set,
+	})
+	s.Require().Error(err)
+	s.Contains(err.Error(), "unable to parse --id")
+
+	id = "1234"
+	invalid := []string{"env"}
+	err = s.Run(ca.UpdateLabels, &ca.UpdateLabelsFlags{
+		ID:  &id,
+		Set: &invalid,
+	})
+	s.Require().Error(err)
+	s.Equal(`invalid label "env", expected key=value`, err.Error())
+
+	err = s.Run(ca.UpdateLabels, &ca.UpdateLabelsFlags{
+		ID:     &id,
+		Set:    &set,
+		Remove: &remove,
+	})
+	s.Require().NoError(err)
+
+	if s.Cli.IsJSON() {
+		s.HasText(`"env": "prod"`)
+	} else {
+		s.HasText("LABELS", "env=prod")
+	}
+}
+
 func (s *testSuite) TestRevokedListCerts() {
 	expectedResponse := new(pb.RevokedCertificatesResponse)
 	err := loadJSON("testdata/revoked.json", expectedResponse)
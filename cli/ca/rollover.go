@@ -0,0 +1,126 @@
+package ca
+
+import (
+	"io/ioutil"
+
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/cli"
+	clicsr "github.com/ekspand/trusty/cli/csr"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/print"
+	"github.com/go-phorce/dolly/ctl"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/juju/errors"
+)
+
+// RolloverPrepareFlags specifies flags for RolloverPrepare command
+type RolloverPrepareFlags struct {
+	// CAConfig specifies file name with ca-config
+	CAConfig *string
+	// Label specifies the label of the current issuer
+	Label *string
+	// KeyLabel specifies name for generated key
+	KeyLabel *string
+	// Output specifies the optional prefix for output files,
+	// if not set, the output will be printed to STDOUT only
+	Output *string
+}
+
+// RolloverPrepare generates a new key for the issuer,
+// and creates CSR with the subject of the current issuer,
+// to be signed by the parent CA
+func RolloverPrepare(c ctl.Control, p interface{}) error {
+	flags := p.(*RolloverPrepareFlags)
+
+	cryptoprov, defaultCrypto := c.(*cli.Cli).CryptoProv()
+	if cryptoprov == nil {
+		return errors.Errorf("unsupported command for this crypto provider")
+	}
+
+	_, isscfg, err := authority.LoadRolloverConfig(*flags.CAConfig, *flags.Label)
+	if err != nil {
+		return errors.Annotate(err, "ca-config")
+	}
+
+	crt, err := certutil.LoadFromPEM(isscfg.CertFile)
+	if err != nil {
+		return errors.Annotate(err, "load issuer certificate")
+	}
+
+	prov := csr.NewProvider(defaultCrypto)
+	req, err := authority.RolloverRequest(prov, crt, clicsr.PrefixKeyLabel(*flags.KeyLabel))
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	csrPEM, key, _, _, err := prov.CreateRequestAndExportKey(req)
+	if err != nil {
+		return errors.Annotate(err, "process CSR")
+	}
+
+	if *flags.Output == "" {
+		print.CSRandCert(c.Writer(), key, csrPEM, nil)
+	} else {
+		err = clicsr.SaveCert(*flags.Output, key, csrPEM, nil)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	return nil
+}
+
+// RolloverImportFlags specifies flags for RolloverImport command
+type RolloverImportFlags struct {
+	// CAConfig specifies file name with ca-config
+	CAConfig *string
+	// Label specifies the label of the current issuer
+	Label *string
+	// NewLabel specifies the label of the new issuer
+	NewLabel *string
+	// Cert specifies file name with the new issuer's certificate
+	Cert *string
+	// Key specifies file name with the new issuer's key
+	Key *string
+	// Output specifies the optional file name for updated ca-config,
+	// if not set, the output will be printed to STDOUT only
+	Output *string
+}
+
+// RolloverImport verifies the new issuer's certificate signed by the parent CA,
+// and updates ca-config: the current issuer is marked as retiring,
+// and its profiles are moved to the new issuer
+func RolloverImport(c ctl.Control, p interface{}) error {
+	flags := p.(*RolloverImportFlags)
+
+	cryptoprov, _ := c.(*cli.Cli).CryptoProv()
+	if cryptoprov == nil {
+		return errors.Errorf("unsupported command for this crypto provider")
+	}
+
+	body, current, err := authority.LoadRolloverConfig(*flags.CAConfig, *flags.Label)
+	if err != nil {
+		return errors.Annotate(err, "ca-config")
+	}
+
+	isscfg, err := authority.ImportRollover(current, *flags.NewLabel, *flags.Cert, *flags.Key, cryptoprov)
+	if err != nil {
+		return errors.Annotate(err, "import")
+	}
+
+	cfg, err := authority.RolloverConfig(body, current.Label, isscfg, authority.RolloverExpiry(current))
+	if err != nil {
+		return errors.Annotate(err, "update ca-config")
+	}
+
+	if *flags.Output == "" {
+		c.Writer().Write(cfg)
+	} else {
+		err = ioutil.WriteFile(*flags.Output, cfg, 0664)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	return nil
+}
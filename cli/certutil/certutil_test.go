@@ -197,6 +197,130 @@ func (s *testSuite) Test_CertInfo() {
 	s.Equal("unable to parse PEM: potentially malformed PEM", err.Error())
 }
 
+func (s *testSuite) Test_Lint() {
+	pem := "testdata/trusty_dev_peer.pem"
+	err := s.Run(certutil.Lint, &certutil.LintFlags{
+		In: &pem,
+	})
+	s.Require().NoError(err)
+	s.HasText(`Subject: CN=localhost,O=trusty.com,L=WA,C=US`,
+		`Serial: 607834773097677667728671612225869896102409081150`,
+		`Lint: passed`,
+	)
+
+	pem = "testdata/wellsfargo.pem"
+	err = s.Run(certutil.Lint, &certutil.LintFlags{
+		In: &pem,
+	})
+	s.Require().NoError(err)
+	s.HasText(`[warn] validity_max_leaf: validity period is 739 days`)
+
+	cfg := "testdata/ca-config.lint.yaml"
+	profile := "server"
+	err = s.Run(certutil.Lint, &certutil.LintFlags{
+		In:       &pem,
+		CAConfig: &cfg,
+		Profile:  &profile,
+	})
+	s.Require().Error(err)
+	s.Equal("1 certificate(s) failed lint", err.Error())
+	s.HasText(`[error] validity_max_leaf: validity period is 739 days`)
+
+	profile = "notfound"
+	err = s.Run(certutil.Lint, &certutil.LintFlags{
+		In:       &pem,
+		CAConfig: &cfg,
+		Profile:  &profile,
+	})
+	s.Require().Error(err)
+	s.Equal("profile not found: notfound", err.Error())
+
+	empty := ""
+	err = s.Run(certutil.Lint, &certutil.LintFlags{
+		In:       &pem,
+		CAConfig: &empty,
+		Profile:  &profile,
+	})
+	s.Require().Error(err)
+	s.Equal("--ca-config is required with --profile", err.Error())
+
+	notfound := "notfound"
+	err = s.Run(certutil.Lint, &certutil.LintFlags{
+		In: &notfound,
+	})
+	s.Require().Error(err)
+	s.Equal("unable to load PEM file: open notfound: no such file or directory", err.Error())
+}
+
+func (s *testSuite) Test_PolicyTest() {
+	csrFile := "testdata/trusty_dev_peer.csr"
+	cfg := "testdata/ca-config.policy.yaml"
+	profile := "server"
+	role := "trusty-admin"
+	empty := ""
+	at := "2021-06-05T14:30:00Z"
+
+	flags := &certutil.PolicyTestFlags{
+		CSR:      &csrFile,
+		CAConfig: &cfg,
+		Profile:  &profile,
+		SAN:      &empty,
+		Role:     &role,
+		Time:     &at,
+	}
+	err := s.Run(certutil.PolicyTest, flags)
+	s.Require().NoError(err)
+	s.HasText("Profile: server\n",
+		"  [allowed] key\n",
+		"  [allowed] ip-addresses\n",
+		"  [allowed] no-wildcards\n",
+		"  [allowed] business-hours\n",
+		"  Issuance policy: allowed\n",
+	)
+
+	role = "team1"
+	san := "*.trusty.local"
+	flags.SAN = &san
+	err = s.Run(certutil.PolicyTest, flags)
+	s.Require().Error(err)
+	s.Equal(`request is denied by "ip-addresses": IP addresses are allowed only for admins`, err.Error())
+	s.HasText("  [denied] ip-addresses: IP addresses are allowed only for admins\n",
+		"  [denied] no-wildcards: wildcard DNS names are not allowed\n",
+	)
+
+	role = "trusty-admin"
+	flags.SAN = &empty
+	at = "2021-06-05T20:00:00Z"
+	err = s.Run(certutil.PolicyTest, flags)
+	s.Require().Error(err)
+	s.Equal(`request is denied by "business-hours": outside of business hours`, err.Error())
+
+	at = "now"
+	err = s.Run(certutil.PolicyTest, flags)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "unable to parse --time")
+
+	at = ""
+	client := "client"
+	flags.Profile = &client
+	err = s.Run(certutil.PolicyTest, flags)
+	s.Require().NoError(err)
+	s.HasText("  Issuance policy: not configured\n")
+
+	notfound := "notfound"
+	flags.Profile = &notfound
+	err = s.Run(certutil.PolicyTest, flags)
+	s.Require().Error(err)
+	s.Equal("profile not found: notfound", err.Error())
+
+	flags.Profile = &profile
+	pem := "testdata/trusty_dev_peer.pem"
+	flags.CSR = &pem
+	err = s.Run(certutil.PolicyTest, flags)
+	s.Require().Error(err)
+	s.Equal("unable to parse CSR: unsupported type in PEM: CERTIFICATE", err.Error())
+}
+
 func (s *testSuite) Test_CSRInfo() {
 	pem := "testdata/trusty_dev_peer.csr"
 
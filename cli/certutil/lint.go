@@ -0,0 +1,75 @@
+package certutil
+
+import (
+	"fmt"
+
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/cli"
+	"github.com/ekspand/trusty/pkg/certlint"
+	"github.com/go-phorce/dolly/ctl"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/juju/errors"
+)
+
+// LintFlags specifies flags for Lint action
+type LintFlags struct {
+	In *string
+	// CAConfig specifies optional CA configuration file,
+	// to apply lint levels of the Profile
+	CAConfig *string
+	Profile  *string
+}
+
+// Lint runs pre-issuance lints on existing certificates
+func Lint(c ctl.Control, p interface{}) error {
+	flags := p.(*LintFlags)
+
+	var levels map[string]certlint.Level
+	if flags.Profile != nil && *flags.Profile != "" {
+		if flags.CAConfig == nil || *flags.CAConfig == "" {
+			return errors.New("--ca-config is required with --profile")
+		}
+		cfg, err := authority.LoadConfig(*flags.CAConfig)
+		if err != nil {
+			return errors.Annotate(err, "unable to load CA config")
+		}
+		profile := cfg.Profiles[*flags.Profile]
+		if profile == nil {
+			return errors.Errorf("profile not found: %s", *flags.Profile)
+		}
+		levels = profile.Lint
+	}
+
+	pem, err := c.(*cli.Cli).ReadFileOrStdin(*flags.In)
+	if err != nil {
+		return errors.Annotate(err, "unable to load PEM file")
+	}
+
+	list, err := certutil.ParseChainFromPEM(pem)
+	if err != nil {
+		return errors.Annotate(err, "unable to parse PEM")
+	}
+
+	w := c.Writer()
+	failed := 0
+	for _, crt := range list {
+		results := certlint.Check(crt, levels)
+		fmt.Fprintf(w, "Subject: %s\n", crt.Subject.String())
+		fmt.Fprintf(w, "  Serial: %s\n", crt.SerialNumber.String())
+		if len(results) == 0 {
+			fmt.Fprintf(w, "  Lint: passed\n")
+			continue
+		}
+		for _, r := range results {
+			fmt.Fprintf(w, "  [%s] %s: %s\n", r.Level, r.Lint, r.Message)
+		}
+		if results.HasErrors() {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d certificate(s) failed lint", failed)
+	}
+	return nil
+}
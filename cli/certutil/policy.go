@@ -0,0 +1,133 @@
+package certutil
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/cli"
+	"github.com/ekspand/trusty/pkg/certpolicy"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/go-phorce/dolly/ctl"
+	"github.com/juju/errors"
+)
+
+// PolicyTestFlags specifies flags for PolicyTest action
+type PolicyTestFlags struct {
+	// CSR specifies PEM-encoded certificate request
+	CSR      *string
+	CAConfig *string
+	Profile  *string
+	// SAN specifies coma separated list of the requested SAN
+	SAN *string
+	// Issuer specifies issuer label, if not provided then the profile's issuer is used
+	Issuer *string
+	Name   *string
+	Role   *string
+	// Roles specifies coma separated list of the caller's roles
+	Roles *string
+	// Orgs specifies coma separated list of the caller's orgs
+	Orgs  *string
+	OrgID *uint64
+	// Time specifies the time of the request in RFC3339 format
+	Time *string
+}
+
+// PolicyTest evaluates issuance policy of the profile against the certificate request
+func PolicyTest(c ctl.Control, p interface{}) error {
+	flags := p.(*PolicyTestFlags)
+
+	cfg, err := authority.LoadConfig(*flags.CAConfig)
+	if err != nil {
+		return errors.Annotate(err, "unable to load CA config")
+	}
+	profile := cfg.Profiles[*flags.Profile]
+	if profile == nil {
+		return errors.Errorf("profile not found: %s", *flags.Profile)
+	}
+
+	pem, err := c.(*cli.Cli).ReadFileOrStdin(*flags.CSR)
+	if err != nil {
+		return errors.Annotate(err, "unable to load CSR file")
+	}
+	csrv, err := csr.ParseRequestPEM(pem)
+	if err != nil {
+		return errors.Annotate(err, "unable to parse CSR")
+	}
+
+	in := &certpolicy.Input{
+		CSR:     csrv,
+		SAN:     splitList(flags.SAN),
+		Profile: *flags.Profile,
+		Issuer:  profile.IssuerLabel,
+		Caller: certpolicy.Caller{
+			Name:  stringValue(flags.Name),
+			Role:  stringValue(flags.Role),
+			Roles: splitList(flags.Roles),
+			Orgs:  splitList(flags.Orgs),
+		},
+	}
+	if issuer := stringValue(flags.Issuer); issuer != "" {
+		in.Issuer = issuer
+	}
+	if in.Caller.Role != "" && len(in.Caller.Roles) == 0 {
+		in.Caller.Roles = []string{in.Caller.Role}
+	}
+	if flags.OrgID != nil {
+		in.OrgID = *flags.OrgID
+	}
+	if t := stringValue(flags.Time); t != "" {
+		in.Time, err = time.Parse(time.RFC3339, t)
+		if err != nil {
+			return errors.Annotate(err, "unable to parse --time")
+		}
+	}
+
+	w := c.Writer()
+	fmt.Fprintf(w, "Profile: %s\n", in.Profile)
+	if len(profile.IssuancePolicy) == 0 {
+		fmt.Fprintf(w, "  Issuance policy: not configured\n")
+		return nil
+	}
+
+	vars := in.Variables()
+	var denied *certpolicy.Decision
+	for _, r := range profile.IssuancePolicy {
+		reason, err := r.Evaluate(vars)
+		if err != nil {
+			reason = "evaluation error: " + err.Error()
+		}
+		if reason == "" {
+			fmt.Fprintf(w, "  [allowed] %s\n", r.Name)
+			continue
+		}
+		fmt.Fprintf(w, "  [denied] %s: %s\n", r.Name, reason)
+		if denied == nil {
+			denied = &certpolicy.Decision{Rule: r.Name, Reason: reason}
+		}
+	}
+
+	if denied != nil {
+		return errors.Errorf("request is %s", denied.String())
+	}
+	fmt.Fprintf(w, "  Issuance policy: allowed\n")
+	return nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func splitList(s *string) []string {
+	var list []string
+	for _, v := range strings.Split(stringValue(s), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
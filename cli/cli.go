@@ -355,6 +355,7 @@ func (cli *Cli) Client(svc string) (*client.Client, error) {
 		DialKeepAliveTime:    timeout,
 		Endpoints:            []string{host},
 		TLS:                  tlscfg,
+		AuthToken:            cli.AuthToken(),
 	}
 
 	client, err := client.New(clientCfg)
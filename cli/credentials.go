@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/go-phorce/dolly/rest/tlsconfig"
+	"github.com/go-phorce/dolly/xhttp/header"
+	"github.com/go-phorce/dolly/xhttp/retriable"
+	"github.com/juju/errors"
+)
+
+// refreshBefore specifies the time before expiration,
+// when the stored access token is refreshed
+const refreshBefore = time.Hour
+
+// Credentials provides the access token of the logged in user,
+// stored in the local credentials file
+type Credentials struct {
+	// Server specifies URL of the Web Front End that issued the token
+	Server        string            `json:"server"`
+	Authorization *v1.Authorization `json:"authorization"`
+}
+
+// CredentialsFile returns location of the credentials file,
+// TRUSTY_CREDENTIALS environment overrides the default location
+func CredentialsFile() string {
+	if file := os.Getenv("TRUSTY_CREDENTIALS"); file != "" {
+		return file
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "trusty", "credentials.json")
+}
+
+// LoadCredentials returns credentials from the file,
+// NotFound error is returned if the file does not exist
+func LoadCredentials(file string) (*Credentials, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.NotFoundf("credentials %q", file)
+		}
+		return nil, errors.Trace(err)
+	}
+
+	c := new(Credentials)
+	if err = json.Unmarshal(b, c); err != nil {
+		return nil, errors.Annotatef(err, "unable to decode credentials %q", file)
+	}
+	if c.Authorization == nil || c.Authorization.AccessToken == "" {
+		return nil, errors.NotFoundf("access token in %q", file)
+	}
+	return c, nil
+}
+
+// Save stores credentials in the file, accessible only by the current user
+func (c *Credentials) Save(file string) error {
+	err := os.MkdirAll(filepath.Dir(file), 0700)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(ioutil.WriteFile(file, b, 0600))
+}
+
+// AuthToken returns the access token to authorize the calls:
+// TRUSTY_AUTH_TOKEN environment, or the token from the credentials file,
+// that is refreshed if it is about to expire.
+// Empty string is returned if the user is not logged in.
+func (cli *Cli) AuthToken() string {
+	if token := os.Getenv("TRUSTY_AUTH_TOKEN"); token != "" {
+		return token
+	}
+
+	file := CredentialsFile()
+	c, err := LoadCredentials(file)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Errorf("reason=load, file=%q, err=[%v]", file, err.Error())
+		}
+		return ""
+	}
+
+	now := time.Now()
+	if !now.Before(c.Authorization.ExpiresAt) {
+		logger.Debugf("reason=expired, file=%q, expires_at=%v", file, c.Authorization.ExpiresAt)
+		return ""
+	}
+
+	if c.Authorization.ExpiresAt.Sub(now) < refreshBefore {
+		err = cli.refreshCredentials(c)
+		if err != nil {
+			// the current token is still valid
+			logger.Errorf("reason=refresh, server=%s, err=[%v]", c.Server, err.Error())
+		} else if err = c.Save(file); err != nil {
+			logger.Errorf("reason=save, file=%q, err=[%v]", file, err.Error())
+		}
+	}
+
+	return c.Authorization.AccessToken
+}
+
+// refreshCredentials refreshes the access token with the server that issued it
+func (cli *Cli) refreshCredentials(c *Credentials) error {
+	client, err := cli.HTTPClient(c.Server)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client.AddHeader(header.Authorization, header.Bearer+" "+c.Authorization.AccessToken)
+	client.AddHeader(header.XDeviceID, c.Authorization.DeviceID)
+
+	res := new(v1.AuthTokenRefreshResponse)
+	_, _, err = client.Request(context.Background(), "GET", []string{c.Server}, v1.PathForAuthTokenRefresh, nil, res)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if res.Authorization == nil || res.Authorization.AccessToken == "" {
+		return errors.New("access token is not returned")
+	}
+
+	c.Authorization = res.Authorization
+	return nil
+}
+
+// HTTPClient returns HTTP client for the server,
+// configured with --trusted-ca option
+func (cli *Cli) HTTPClient(server string) (*retriable.Client, error) {
+	client := retriable.New()
+	if strings.HasPrefix(server, "https://") {
+		tlscfg, err := tlsconfig.NewClientTLSFromFiles("", "", cli.TLSCAFile())
+		if err != nil {
+			return nil, errors.Annotate(err, "unable to build TLS configuration")
+		}
+		client.WithTLS(tlscfg)
+	}
+	return client, nil
+}
+
+// AuthorizedHTTPClient returns the client for Web Front End,
+// authorized with the token from TRUSTY_AUTH_TOKEN environment,
+// or the credentials of logged in user
+func (cli *Cli) AuthorizedHTTPClient() (*retriable.Client, string, error) {
+	srv := cli.Server()
+	if srv == "" {
+		return nil, "", errors.New("please specify --server option")
+	}
+
+	token := cli.AuthToken()
+	if token == "" {
+		return nil, "", errors.New("please login and set TRUSTY_AUTH_TOKEN environment")
+	}
+
+	client, err := cli.HTTPClient(srv)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	client.AddHeader(header.Authorization, header.Bearer+" "+token)
+
+	return client, srv, nil
+}
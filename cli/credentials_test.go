@@ -0,0 +1,105 @@
+package cli_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/ekspand/trusty/cli"
+	"github.com/go-phorce/dolly/ctl"
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentials(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "trusty", "credentials.json")
+	os.Setenv("TRUSTY_CREDENTIALS", file)
+	defer os.Unsetenv("TRUSTY_CREDENTIALS")
+	assert.Equal(t, file, cli.CredentialsFile())
+
+	_, err := cli.LoadCredentials(file)
+	assert.True(t, errors.IsNotFound(err))
+
+	c := &cli.Credentials{
+		Server: "https://localhost:7891",
+		Authorization: &v1.Authorization{
+			DeviceID:    "jumphost",
+			Email:       "denis@trusty.com",
+			AccessToken: "AccessToken123",
+			ExpiresAt:   time.Now().Add(8 * time.Hour).UTC(),
+		},
+	}
+	require.NoError(t, c.Save(file))
+
+	fi, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	c2, err := cli.LoadCredentials(file)
+	require.NoError(t, err)
+	assert.Equal(t, c.Server, c2.Server)
+	assert.Equal(t, *c.Authorization, *c2.Authorization)
+}
+
+func TestAuthToken(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "credentials.json")
+	os.Setenv("TRUSTY_CREDENTIALS", file)
+	defer os.Unsetenv("TRUSTY_CREDENTIALS")
+	os.Unsetenv("TRUSTY_AUTH_TOKEN")
+
+	var auth, device string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		device = r.Header.Get("X-Device-ID")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, `{"authorization":{"device_id":"jumphost","access_token":"Refreshed123","expires_at":"`+
+			time.Now().Add(8*time.Hour).UTC().Format(time.RFC3339)+`"}}`)
+	}))
+	defer server.Close()
+
+	out := bytes.NewBuffer([]byte{})
+	app := ctl.NewApplication("cliapp", "test")
+	c := cli.New(&ctl.ControlDefinition{App: app, Output: out})
+	defer c.Close()
+
+	assert.Empty(t, c.AuthToken(), "not logged in")
+
+	os.Setenv("TRUSTY_AUTH_TOKEN", "EnvToken123")
+	assert.Equal(t, "EnvToken123", c.AuthToken())
+	os.Unsetenv("TRUSTY_AUTH_TOKEN")
+
+	creds := &cli.Credentials{
+		Server: server.URL,
+		Authorization: &v1.Authorization{
+			DeviceID:    "jumphost",
+			AccessToken: "AccessToken123",
+			ExpiresAt:   time.Now().Add(8 * time.Hour),
+		},
+	}
+	require.NoError(t, creds.Save(file))
+	assert.Equal(t, "AccessToken123", c.AuthToken())
+	assert.Empty(t, auth, "the token must not be refreshed")
+
+	// about to expire
+	creds.Authorization.ExpiresAt = time.Now().Add(time.Minute)
+	require.NoError(t, creds.Save(file))
+	assert.Equal(t, "Refreshed123", c.AuthToken())
+	assert.Equal(t, "Bearer AccessToken123", auth)
+	assert.Equal(t, "jumphost", device)
+
+	stored, err := cli.LoadCredentials(file)
+	require.NoError(t, err)
+	assert.Equal(t, "Refreshed123", stored.Authorization.AccessToken)
+
+	// expired
+	creds.Authorization.ExpiresAt = time.Now().Add(-time.Minute)
+	require.NoError(t, creds.Save(file))
+	assert.Empty(t, c.AuthToken())
+}
@@ -39,7 +39,7 @@ func Create(c ctl.Control, p interface{}) error {
 	}
 
 	req := csr.CertificateRequest{
-		KeyRequest: prov.NewKeyRequest(prefixKeyLabel(*flags.KeyLabel), "ECDSA", 256, csr.SigningKey),
+		KeyRequest: prov.NewKeyRequest(PrefixKeyLabel(*flags.KeyLabel), "ECDSA", 256, csr.SigningKey),
 	}
 
 	err = json.Unmarshal(csrf, &req)
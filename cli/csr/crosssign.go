@@ -0,0 +1,80 @@
+package csr
+
+import (
+	"github.com/ekspand/trusty/authority"
+	"github.com/ekspand/trusty/cli"
+	"github.com/ekspand/trusty/pkg/print"
+	"github.com/go-phorce/dolly/ctl"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/juju/errors"
+)
+
+// CrossSignFlags specifies flags for CrossSign command
+type CrossSignFlags struct {
+	// CACert specifies file name with CA cert
+	CACert *string
+	// CAKey specifies file name with CA key
+	CAKey *string
+	// CAConfig specifies file name with ca-config
+	CAConfig *string
+	// Cert specifies file name with the CA certificate to cross-sign
+	Cert *string
+	// Profile specifies the profile name from ca-config
+	Profile *string
+	// Output specifies the optional prefix for output files,
+	// if not set, the output will be printed to STDOUT only
+	Output *string
+}
+
+// CrossSign issues a cross-certificate for the existing CA certificate
+func CrossSign(c ctl.Control, p interface{}) error {
+	flags := p.(*CrossSignFlags)
+
+	cryptoprov, _ := c.(*cli.Cli).CryptoProv()
+	if cryptoprov == nil {
+		return errors.Errorf("unsupported command for this crypto provider")
+	}
+
+	crt, err := certutil.LoadFromPEM(*flags.Cert)
+	if err != nil {
+		return errors.Annotate(err, "load certificate")
+	}
+
+	// Load ca-config
+	cacfg, err := authority.LoadConfig(*flags.CAConfig)
+	if err != nil {
+		return errors.Annotate(err, "ca-config")
+	}
+	err = cacfg.Validate()
+	if err != nil {
+		return errors.Annotate(err, "invalid ca-config")
+	}
+
+	isscfg := &authority.IssuerConfig{
+		Type:     authority.IssuerTypeCrossSign,
+		CertFile: *flags.CACert,
+		KeyFile:  *flags.CAKey,
+		Profiles: cacfg.Profiles,
+	}
+
+	issuer, err := authority.NewIssuer(isscfg, cryptoprov)
+	if err != nil {
+		return errors.Annotate(err, "create issuer")
+	}
+
+	_, certPEM, err := issuer.CrossSign(crt, *flags.Profile)
+	if err != nil {
+		return errors.Annotate(err, "cross-sign")
+	}
+
+	if *flags.Output == "" {
+		print.CSRandCert(c.Writer(), nil, nil, certPEM)
+	} else {
+		err = SaveCert(*flags.Output, nil, nil, certPEM)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	return nil
+}
@@ -68,7 +68,7 @@ func GenCert(c ctl.Control, p interface{}) error {
 
 	prov := csr.NewProvider(defaultCrypto)
 	req := csr.CertificateRequest{
-		KeyRequest: prov.NewKeyRequest(prefixKeyLabel(*flags.KeyLabel), "ECDSA", 256, csr.SigningKey),
+		KeyRequest: prov.NewKeyRequest(PrefixKeyLabel(*flags.KeyLabel), "ECDSA", 256, csr.SigningKey),
 	}
 
 	err = json.Unmarshal(csrf, &req)
@@ -138,8 +138,8 @@ func GenCert(c ctl.Control, p interface{}) error {
 	return nil
 }
 
-// prefixKeyLabel adds a date prefix to label for a key
-func prefixKeyLabel(label string) string {
+// PrefixKeyLabel adds a date prefix to label for a key
+func PrefixKeyLabel(label string) string {
 	if strings.HasSuffix(label, "*") {
 		g := guid.MustCreate()
 		t := time.Now().UTC()
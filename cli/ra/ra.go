@@ -0,0 +1,178 @@
+package ra
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/cli"
+	"github.com/ekspand/trusty/internal/config"
+	"github.com/ekspand/trusty/pkg/print"
+	"github.com/go-phorce/dolly/ctl"
+	"github.com/juju/errors"
+)
+
+// PollInterval specifies the interval to poll the status of the request
+var PollInterval = 5 * time.Second
+
+// ListRequestsFlags defines flags for ListRequests command
+type ListRequestsFlags struct {
+	// Status specifies pending|approved|denied|expired|issuing|issued|all
+	Status *string
+	Limit  *int
+	After  *uint64
+}
+
+// ListRequests prints the certificate requests in the approval queue
+func ListRequests(c ctl.Control, p interface{}) error {
+	flags := p.(*ListRequestsFlags)
+
+	req := &pb.ListApprovalsRequest{}
+	status := strings.ToUpper(*flags.Status)
+	if status == "" || status == "ALL" {
+		req.All = true
+	} else if val, ok := pb.ApprovalStatus_value[status]; ok {
+		req.Status = pb.ApprovalStatus(val)
+	} else {
+		return errors.Errorf("unsupported status: %s", *flags.Status)
+	}
+	if flags.Limit != nil {
+		req.Limit = int64(*flags.Limit)
+	}
+	if flags.After != nil {
+		req.After = *flags.After
+	}
+
+	cli := c.(*cli.Cli)
+	client, err := cli.Client(config.RAServerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	res, err := client.RAClient().ListApprovals(context.Background(), req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		print.ApprovalsTable(c.Writer(), res.List)
+	}
+	return nil
+}
+
+// GetRequestFlags defines flags for GetRequest command
+type GetRequestFlags struct {
+	ID *uint64
+	// Wait specifies to poll the request until it's issued, denied or expired
+	Wait *bool
+	// Out specifies the file name to save the issued certificate
+	Out *string
+}
+
+// GetRequest prints the certificate request in the approval queue
+func GetRequest(c ctl.Control, p interface{}) error {
+	flags := p.(*GetRequestFlags)
+
+	cli := c.(*cli.Cli)
+	client, err := cli.Client(config.RAServerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	var a *pb.Approval
+	for {
+		res, err := client.RAClient().GetApproval(ctx, &pb.GetApprovalRequest{Id: *flags.ID})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		a = res.Approval
+		if flags.Wait == nil || !*flags.Wait ||
+			(a.Status != pb.ApprovalStatus_PENDING &&
+				a.Status != pb.ApprovalStatus_APPROVED &&
+				a.Status != pb.ApprovalStatus_ISSUING) {
+			break
+		}
+		time.Sleep(PollInterval)
+	}
+
+	if a.Certificate != nil && flags.Out != nil && *flags.Out != "" {
+		pem := a.Certificate.Pem
+		if !strings.HasSuffix(pem, "\n") {
+			pem += "\n"
+		}
+		pem += a.Certificate.IssuersPem
+		err = ioutil.WriteFile(*flags.Out, []byte(pem), 0664)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	printApproval(c, a)
+
+	if flags.Wait != nil && *flags.Wait &&
+		(a.Status == pb.ApprovalStatus_DENIED || a.Status == pb.ApprovalStatus_EXPIRED) {
+		return errors.Errorf("the request is %s", strings.ToLower(a.Status.String()))
+	}
+	return nil
+}
+
+// DecisionFlags defines flags for Approve and Deny commands
+type DecisionFlags struct {
+	ID     *uint64
+	Reason *string
+}
+
+// Approve approves the certificate request
+func Approve(c ctl.Control, p interface{}) error {
+	return decide(c, p.(*DecisionFlags), true)
+}
+
+// Deny denies the certificate request
+func Deny(c ctl.Control, p interface{}) error {
+	return decide(c, p.(*DecisionFlags), false)
+}
+
+func decide(c ctl.Control, flags *DecisionFlags, approve bool) error {
+	cli := c.(*cli.Cli)
+	client, err := cli.Client(config.RAServerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	req := &pb.ApprovalDecisionRequest{
+		Id:     *flags.ID,
+		Reason: *flags.Reason,
+	}
+
+	var res *pb.ApprovalResponse
+	if approve {
+		res, err = client.RAClient().Approve(context.Background(), req)
+	} else {
+		res, err = client.RAClient().Deny(context.Background(), req)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	printApproval(c, res.Approval)
+	return nil
+}
+
+func printApproval(c ctl.Control, a *pb.Approval) {
+	if c.(*cli.Cli).IsJSON() {
+		ctl.WriteJSON(c.Writer(), &pb.ApprovalResponse{Approval: a})
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		print.Approval(c.Writer(), a)
+	}
+}
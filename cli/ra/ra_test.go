@@ -0,0 +1,148 @@
+package ra_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/cli/ra"
+	"github.com/ekspand/trusty/cli/testsuite"
+	"github.com/ekspand/trusty/tests/mockpb"
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type testSuite struct {
+	testsuite.Suite
+}
+
+func TestCtlSuite(t *testing.T) {
+	s := new(testSuite)
+	s.WithGRPC()
+	suite.Run(t, s)
+}
+
+func TestCtlSuiteWithJSON(t *testing.T) {
+	s := new(testSuite)
+	s.WithGRPC().WithAppFlags([]string{"--json"})
+	suite.Run(t, s)
+}
+
+func testApproval(status pb.ApprovalStatus) *pb.Approval {
+	created := time.Date(2021, time.June, 1, 10, 0, 0, 0, time.UTC)
+	return &pb.Approval{
+		Id:                1001,
+		Profile:           "subca",
+		IssuerLabel:       "trusty.svc",
+		Subject:           "CN=sub-ca",
+		Requester:         "denis@ekspand.com",
+		Status:            status,
+		RequiredApprovals: 2,
+		ApproverRoles:     []string{"trusty-admin"},
+		CreatedAt:         timestamppb.New(created),
+		ExpiresAt:         timestamppb.New(created.Add(72 * time.Hour)),
+	}
+}
+
+func (s *testSuite) TestListRequests() {
+	s.MockRA = &mockpb.MockRAServer{}
+	s.MockRA.SetResponse(&pb.ApprovalsResponse{
+		List: []*pb.Approval{testApproval(pb.ApprovalStatus_PENDING)},
+	})
+	srv := s.SetupMockGRPC()
+	defer srv.Stop()
+
+	status := "unknown"
+	limit := 10
+	after := uint64(0)
+	flags := &ra.ListRequestsFlags{Status: &status, Limit: &limit, After: &after}
+	err := s.Run(ra.ListRequests, flags)
+	s.Require().Error(err)
+	s.Equal("unsupported status: unknown", err.Error())
+
+	status = "pending"
+	err = s.Run(ra.ListRequests, flags)
+	s.Require().NoError(err)
+	if s.Cli.IsJSON() {
+		s.HasText(`"requester": "denis@ekspand.com"`)
+	} else {
+		s.HasText("1001", "subca", "CN=sub-ca", "denis@ekspand.com", "PENDING", "0/2")
+	}
+
+	s.MockRA.SetError(errors.New("request failed"))
+	err = s.Run(ra.ListRequests, flags)
+	s.Require().Error(err)
+}
+
+func (s *testSuite) TestGetRequest() {
+	issued := testApproval(pb.ApprovalStatus_ISSUED)
+	issued.Certificate = &pb.Certificate{
+		Id:         1234,
+		Pem:        "cert pem",
+		IssuersPem: "issuers pem",
+	}
+
+	s.MockRA = &mockpb.MockRAServer{}
+	s.MockRA.SetResponse(&pb.ApprovalResponse{Approval: issued})
+	srv := s.SetupMockGRPC()
+	defer srv.Stop()
+
+	id := uint64(1001)
+	wait := true
+	out := filepath.Join(s.T().TempDir(), "cert.pem")
+	err := s.Run(ra.GetRequest, &ra.GetRequestFlags{ID: &id, Wait: &wait, Out: &out})
+	s.Require().NoError(err)
+	if s.Cli.IsJSON() {
+		s.HasText(`"status": 4`)
+	} else {
+		s.HasText("Status      | ISSUED", "Certificate | 1234")
+	}
+
+	pem, err := ioutil.ReadFile(out)
+	s.Require().NoError(err)
+	s.Equal("cert pem\nissuers pem", string(pem))
+
+	s.MockRA.SetResponse(&pb.ApprovalResponse{Approval: testApproval(pb.ApprovalStatus_DENIED)})
+	err = s.Run(ra.GetRequest, &ra.GetRequestFlags{ID: &id, Wait: &wait})
+	s.Require().Error(err)
+	s.Equal("the request is denied", err.Error())
+
+	wait = false
+	err = s.Run(ra.GetRequest, &ra.GetRequestFlags{ID: &id, Wait: &wait})
+	s.Require().NoError(err)
+}
+
+func (s *testSuite) TestDecision() {
+	approval := testApproval(pb.ApprovalStatus_PENDING)
+	approval.Votes = []*pb.ApprovalVote{
+		{
+			Approver:  "admin@ekspand.com",
+			Approved:  true,
+			Reason:    "ticket 123",
+			CreatedAt: approval.CreatedAt,
+		},
+	}
+
+	s.MockRA = &mockpb.MockRAServer{}
+	s.MockRA.SetResponse(&pb.ApprovalResponse{Approval: approval})
+	srv := s.SetupMockGRPC()
+	defer srv.Stop()
+
+	id := uint64(1001)
+	reason := "ticket 123"
+	err := s.Run(ra.Approve, &ra.DecisionFlags{ID: &id, Reason: &reason})
+	s.Require().NoError(err)
+	if s.Cli.IsJSON() {
+		s.HasText(`"approver": "admin@ekspand.com"`)
+	} else {
+		s.HasText("Approvals | 1/2", "admin@ekspand.com | approved | ticket 123")
+	}
+
+	s.MockRA.SetError(errors.New("the caller is not an approver of the request"))
+	err = s.Run(ra.Deny, &ra.DecisionFlags{ID: &id, Reason: &reason})
+	s.Require().Error(err)
+	s.Contains(err.Error(), "the caller is not an approver of the request")
+}
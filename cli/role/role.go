@@ -0,0 +1,118 @@
+package role
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/ekspand/trusty/cli"
+	"github.com/ekspand/trusty/pkg/print"
+	"github.com/go-phorce/dolly/ctl"
+	"github.com/juju/errors"
+)
+
+// List prints role bindings
+func List(c ctl.Control, _ interface{}) error {
+	cli := c.(*cli.Cli)
+
+	client, srv, err := cli.AuthorizedHTTPClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	res := new(v1.RoleBindingsResponse)
+	_, _, err = client.Request(context.Background(), "GET", []string{srv}, v1.PathForAuthRoleBindings, nil, res)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		print.RoleBindingsTable(c.Writer(), res.RoleBindings)
+	}
+	return nil
+}
+
+// BindFlags defines flags for Bind command
+type BindFlags struct {
+	Role *string
+	// Subject is the identity with type prefix, see v1.RoleBindingPrefixes
+	Subject *string
+	// Group of the identity provider, in ${provider}/${group} format
+	Group *string
+}
+
+// Bind binds the role to the identity, or to the group of the identity provider
+func Bind(c ctl.Control, p interface{}) error {
+	flags := p.(*BindFlags)
+	cli := c.(*cli.Cli)
+
+	subject := *flags.Subject
+	if *flags.Group != "" {
+		if subject != "" {
+			return errors.New("only one of --subject or --group can be specified")
+		}
+		subject = v1.RoleBindingGroupPrefix + *flags.Group
+	}
+	if subject == "" {
+		return errors.New("either --subject or --group must be specified")
+	}
+
+	client, srv, err := cli.AuthorizedHTTPClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	req := &v1.CreateRoleBindingRequest{
+		Role:    *flags.Role,
+		Subject: subject,
+	}
+	res := new(v1.RoleBindingResponse)
+	_, _, err = client.Request(context.Background(), "POST", []string{srv}, v1.PathForAuthRoleBindings, req, res)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		print.RoleBindingsTable(c.Writer(), []*v1.RoleBinding{res.RoleBinding})
+	}
+	return nil
+}
+
+// UnbindFlags defines flags for Unbind command
+type UnbindFlags struct {
+	ID *string
+}
+
+// Unbind deletes the role binding
+func Unbind(c ctl.Control, p interface{}) error {
+	flags := p.(*UnbindFlags)
+	cli := c.(*cli.Cli)
+
+	client, srv, err := cli.AuthorizedHTTPClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	res := new(v1.RoleBindingResponse)
+	path := strings.Replace(v1.PathForAuthRoleBinding, ":id", url.PathEscape(*flags.ID), 1)
+	_, _, err = client.Request(context.Background(), "DELETE", []string{srv}, path, nil, res)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		fmt.Fprintf(c.Writer(), "Deleted: %s => %s\n", res.RoleBinding.Role, res.RoleBinding.Subject)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package role_test
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ekspand/trusty/cli/role"
+	"github.com/ekspand/trusty/cli/testsuite"
+	"github.com/stretchr/testify/suite"
+)
+
+const bindingResponse = `{
+	"role_binding": {
+		"id": "1001",
+		"role": "trusty-admin",
+		"subject": "group:okta/admins",
+		"created_by": "100",
+		"created_at": "2021-06-01T10:00:00Z"
+	}
+}`
+
+type testSuite struct {
+	testsuite.Suite
+}
+
+func TestCtlSuite(t *testing.T) {
+	s := new(testSuite)
+	s.WithGRPC()
+	suite.Run(t, s)
+}
+
+func (s *testSuite) TestNoToken() {
+	os.Unsetenv("TRUSTY_AUTH_TOKEN")
+
+	s.Cli.WithServer("http://localhost")
+	err := s.Run(role.List, nil)
+	s.Require().Error(err)
+	s.Equal("please login and set TRUSTY_AUTH_TOKEN environment", err.Error())
+}
+
+func (s *testSuite) TestRoleBindings() {
+	os.Setenv("TRUSTY_AUTH_TOKEN", "AccessToken123")
+	defer os.Unsetenv("TRUSTY_AUTH_TOKEN")
+
+	var method, path, body, auth, response string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.RequestURI()
+		auth = r.Header.Get("Authorization")
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, response)
+	}))
+	defer server.Close()
+	s.Cli.WithServer(server.URL)
+
+	roleName := "trusty-admin"
+	subject := ""
+	group := "okta/admins"
+	id := "1001"
+
+	// list
+	response = `{"role_bindings":[{"id":"1001","role":"trusty-admin","subject":"group:okta/admins","created_at":"2021-06-01T10:00:00Z"}]}`
+	s.Require().NoError(s.Run(role.List, nil))
+	s.Equal("GET", method)
+	s.Equal("/v1/auth/roles", path)
+	s.Equal("Bearer AccessToken123", auth)
+	s.HasText("1001", "trusty-admin", "group:okta/admins")
+
+	// bind
+	response = bindingResponse
+	s.Require().NoError(s.Run(role.Bind, &role.BindFlags{Role: &roleName, Subject: &subject, Group: &group}))
+	s.Equal("POST", method)
+	s.Equal("/v1/auth/roles", path)
+	s.Equal(`{"role":"trusty-admin","subject":"group:okta/admins"}`, body)
+	s.HasText("1001", "trusty-admin", "group:okta/admins")
+
+	// unbind
+	s.Require().NoError(s.Run(role.Unbind, &role.UnbindFlags{ID: &id}))
+	s.Equal("DELETE", method)
+	s.Equal("/v1/auth/roles/1001", path)
+	s.HasText("Deleted: trusty-admin => group:okta/admins\n")
+
+	// invalid flags
+	empty := ""
+	err := s.Run(role.Bind, &role.BindFlags{Role: &roleName, Subject: &empty, Group: &empty})
+	s.Require().Error(err)
+	s.Equal("either --subject or --group must be specified", err.Error())
+
+	subject = "user:denis@ekspand.com"
+	err = s.Run(role.Bind, &role.BindFlags{Role: &roleName, Subject: &subject, Group: &group})
+	s.Require().Error(err)
+	s.Equal("only one of --subject or --group can be specified", err.Error())
+}
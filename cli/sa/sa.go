@@ -0,0 +1,224 @@
+package sa
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/ekspand/trusty/cli"
+	"github.com/ekspand/trusty/pkg/print"
+	"github.com/go-phorce/dolly/ctl"
+	"github.com/juju/errors"
+)
+
+// ListFlags defines flags for List command
+type ListFlags struct {
+	OrgID *string
+}
+
+// List prints service accounts of the org
+func List(c ctl.Control, p interface{}) error {
+	flags := p.(*ListFlags)
+	cli := c.(*cli.Cli)
+
+	client, srv, err := cli.AuthorizedHTTPClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	res := new(v1.ServiceAccountsResponse)
+	path := v1.PathForServiceAccounts + "?org_id=" + url.QueryEscape(*flags.OrgID)
+	_, _, err = client.Request(context.Background(), "GET", []string{srv}, path, nil, res)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		print.ServiceAccountsTable(c.Writer(), res.ServiceAccounts)
+	}
+	return nil
+}
+
+// CreateFlags defines flags for Create command
+type CreateFlags struct {
+	OrgID       *string
+	Name        *string
+	Description *string
+}
+
+// Create creates a service account for the org
+func Create(c ctl.Control, p interface{}) error {
+	flags := p.(*CreateFlags)
+	cli := c.(*cli.Cli)
+
+	client, srv, err := cli.AuthorizedHTTPClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	req := &v1.CreateServiceAccountRequest{
+		OrgID:       *flags.OrgID,
+		Name:        *flags.Name,
+		Description: *flags.Description,
+	}
+	res := new(v1.ServiceAccountResponse)
+	_, _, err = client.Request(context.Background(), "POST", []string{srv}, v1.PathForServiceAccounts, req, res)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		print.ServiceAccountsTable(c.Writer(), []*v1.ServiceAccount{res.ServiceAccount})
+	}
+	return nil
+}
+
+// DeleteFlags defines flags for Delete command
+type DeleteFlags struct {
+	ID *string
+}
+
+// Delete deletes the service account and its tokens
+func Delete(c ctl.Control, p interface{}) error {
+	flags := p.(*DeleteFlags)
+	cli := c.(*cli.Cli)
+
+	client, srv, err := cli.AuthorizedHTTPClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	res := new(v1.ServiceAccountResponse)
+	path := serviceAccountPath(v1.PathForServiceAccount, *flags.ID)
+	_, _, err = client.Request(context.Background(), "DELETE", []string{srv}, path, nil, res)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		fmt.Fprintf(c.Writer(), "Deleted: %s\n", res.ServiceAccount.Name)
+	}
+	return nil
+}
+
+// TokensFlags defines flags for Tokens command
+type TokensFlags struct {
+	ServiceAccountID *string
+}
+
+// Tokens prints API tokens of the service account
+func Tokens(c ctl.Control, p interface{}) error {
+	flags := p.(*TokensFlags)
+	cli := c.(*cli.Cli)
+
+	client, srv, err := cli.AuthorizedHTTPClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	res := new(v1.APITokensResponse)
+	path := serviceAccountPath(v1.PathForServiceAccountTokens, *flags.ServiceAccountID)
+	_, _, err = client.Request(context.Background(), "GET", []string{srv}, path, nil, res)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		print.APITokensTable(c.Writer(), res.Tokens)
+	}
+	return nil
+}
+
+// CreateTokenFlags defines flags for CreateToken command
+type CreateTokenFlags struct {
+	ServiceAccountID *string
+	Name             *string
+	Expiry           *string
+}
+
+// CreateToken creates API token for the service account
+func CreateToken(c ctl.Control, p interface{}) error {
+	flags := p.(*CreateTokenFlags)
+	cli := c.(*cli.Cli)
+
+	client, srv, err := cli.AuthorizedHTTPClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	req := &v1.CreateAPITokenRequest{
+		Name:   *flags.Name,
+		Expiry: *flags.Expiry,
+	}
+	res := new(v1.CreateAPITokenResponse)
+	path := serviceAccountPath(v1.PathForServiceAccountTokens, *flags.ServiceAccountID)
+	_, _, err = client.Request(context.Background(), "POST", []string{srv}, path, req, res)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		fmt.Fprintf(c.Writer(), "Token: %s\n  ID: %s\n", res.Token.Name, res.Token.ID)
+		if res.Token.ExpiresAt != nil {
+			fmt.Fprintf(c.Writer(), "  Expires: %s\n", res.Token.ExpiresAt.Local().Format(time.RFC3339))
+		}
+		fmt.Fprintf(c.Writer(), "\nstore the token now, it can not be retrieved later:\n%s\n", res.Secret)
+	}
+	return nil
+}
+
+// RevokeTokenFlags defines flags for RevokeToken command
+type RevokeTokenFlags struct {
+	ServiceAccountID *string
+	ID               *string
+}
+
+// RevokeToken revokes API token of the service account
+func RevokeToken(c ctl.Control, p interface{}) error {
+	flags := p.(*RevokeTokenFlags)
+	cli := c.(*cli.Cli)
+
+	client, srv, err := cli.AuthorizedHTTPClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	res := new(v1.APITokenResponse)
+	path := strings.Replace(
+		serviceAccountPath(v1.PathForServiceAccountToken, *flags.ServiceAccountID),
+		":token_id", url.PathEscape(*flags.ID), 1)
+	_, _, err = client.Request(context.Background(), "DELETE", []string{srv}, path, nil, res)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		fmt.Fprintf(c.Writer(), "Revoked: %s\n", res.Token.Name)
+	}
+	return nil
+}
+
+func serviceAccountPath(path, id string) string {
+	return strings.Replace(path, ":id", url.PathEscape(id), 1)
+}
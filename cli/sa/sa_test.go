@@ -0,0 +1,127 @@
+package sa_test
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ekspand/trusty/cli/sa"
+	"github.com/ekspand/trusty/cli/testsuite"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	saResponse = `{
+		"service_account": {
+			"id": "1001",
+			"org_id": "1000",
+			"name": "ci",
+			"description": "CI pipeline",
+			"created_by": "100",
+			"created_at": "2021-06-01T10:00:00Z"
+		}
+	}`
+	tokenResponse = `{
+		"token": {
+			"id": "1002",
+			"service_account_id": "1001",
+			"name": "deploy",
+			"created_by": "100",
+			"created_at": "2021-06-01T10:00:00Z",
+			"expires_at": "2021-07-01T10:00:00Z"
+		},
+		"secret": "trusty_Zm9vYmFy"
+	}`
+)
+
+type testSuite struct {
+	testsuite.Suite
+}
+
+func TestCtlSuite(t *testing.T) {
+	s := new(testSuite)
+	s.WithGRPC()
+	suite.Run(t, s)
+}
+
+func (s *testSuite) TestNoToken() {
+	os.Unsetenv("TRUSTY_AUTH_TOKEN")
+
+	s.Cli.WithServer("http://localhost")
+	orgID := "1000"
+	err := s.Run(sa.List, &sa.ListFlags{OrgID: &orgID})
+	s.Require().Error(err)
+	s.Equal("please login and set TRUSTY_AUTH_TOKEN environment", err.Error())
+}
+
+func (s *testSuite) TestServiceAccounts() {
+	os.Setenv("TRUSTY_AUTH_TOKEN", "AccessToken123")
+	defer os.Unsetenv("TRUSTY_AUTH_TOKEN")
+
+	var method, path, body, auth, response string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.RequestURI()
+		auth = r.Header.Get("Authorization")
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, response)
+	}))
+	defer server.Close()
+	s.Cli.WithServer(server.URL)
+
+	orgID := "1000"
+	name := "ci"
+	desc := "CI pipeline"
+	saID := "1001"
+	tokenName := "deploy"
+	expiry := "720h"
+	tokenID := "1002"
+
+	// list
+	response = `{"service_accounts":[{"id":"1001","org_id":"1000","name":"ci","description":"CI pipeline","created_at":"2021-06-01T10:00:00Z"}]}`
+	s.Require().NoError(s.Run(sa.List, &sa.ListFlags{OrgID: &orgID}))
+	s.Equal("GET", method)
+	s.Equal("/v1/sa?org_id=1000", path)
+	s.Equal("Bearer AccessToken123", auth)
+	s.HasText("1001", "ci", "CI pipeline")
+
+	// create
+	response = saResponse
+	s.Require().NoError(s.Run(sa.Create, &sa.CreateFlags{OrgID: &orgID, Name: &name, Description: &desc}))
+	s.Equal("POST", method)
+	s.Equal("/v1/sa", path)
+	s.Equal(`{"org_id":"1000","name":"ci","description":"CI pipeline"}`, body)
+	s.HasText("1001", "ci", "CI pipeline")
+
+	// delete
+	s.Require().NoError(s.Run(sa.Delete, &sa.DeleteFlags{ID: &saID}))
+	s.Equal("DELETE", method)
+	s.Equal("/v1/sa/1001", path)
+	s.HasText("Deleted: ci\n")
+
+	// token create
+	response = tokenResponse
+	s.Require().NoError(s.Run(sa.CreateToken, &sa.CreateTokenFlags{ServiceAccountID: &saID, Name: &tokenName, Expiry: &expiry}))
+	s.Equal("POST", method)
+	s.Equal("/v1/sa/1001/tokens", path)
+	s.Equal(`{"name":"deploy","expiry":"720h"}`, body)
+	s.HasText("Token: deploy\n", "trusty_Zm9vYmFy\n")
+
+	// token revoke
+	s.Require().NoError(s.Run(sa.RevokeToken, &sa.RevokeTokenFlags{ServiceAccountID: &saID, ID: &tokenID}))
+	s.Equal("DELETE", method)
+	s.Equal("/v1/sa/1001/tokens/1002", path)
+	s.HasText("Revoked: deploy\n")
+
+	// token list
+	response = `{"tokens":[{"id":"1002","name":"deploy","created_at":"2021-06-01T10:00:00Z"}]}`
+	s.Require().NoError(s.Run(sa.Tokens, &sa.TokensFlags{ServiceAccountID: &saID}))
+	s.Equal("GET", method)
+	s.Equal("/v1/sa/1001/tokens", path)
+	s.HasText("1002", "deploy")
+}
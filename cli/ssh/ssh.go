@@ -0,0 +1,163 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/cli"
+	"github.com/ekspand/trusty/internal/config"
+	"github.com/go-phorce/dolly/ctl"
+	"github.com/juju/errors"
+)
+
+// SignFlags specifies flags for the Sign action
+type SignFlags struct {
+	// PublicKey specifies SSH public key file to sign
+	PublicKey  *string
+	Profile    *string
+	KeyID      *string
+	Principals *[]string
+	Out        *string
+}
+
+// Sign SSH public key
+func Sign(c ctl.Control, p interface{}) error {
+	flags := p.(*SignFlags)
+	cli := c.(*cli.Cli)
+
+	pub, err := ioutil.ReadFile(*flags.PublicKey)
+	if err != nil {
+		return errors.Annotatef(err, "failed to load public key")
+	}
+
+	client, err := cli.Client(config.CAServerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	res, err := client.SSHClient().SignSSHKey(context.Background(), &pb.SignSSHKeyRequest{
+		Profile:    *flags.Profile,
+		PublicKey:  string(pub),
+		KeyId:      *flags.KeyID,
+		Principals: *flags.Principals,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	cert := res.Certificate.Certificate
+	if !strings.HasSuffix(cert, "\n") {
+		cert += "\n"
+	}
+
+	if flags.Out != nil && *flags.Out != "" {
+		err = ioutil.WriteFile(*flags.Out, []byte(cert), 0644)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	} else if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		fmt.Fprint(c.Writer(), cert)
+	}
+
+	return nil
+}
+
+// RevokeFlags specifies flags for the Revoke action
+type RevokeFlags struct {
+	Ikid         *string
+	SerialNumber *string
+	Reason       *string
+}
+
+// Revoke SSH certificate
+func Revoke(c ctl.Control, p interface{}) error {
+	flags := p.(*RevokeFlags)
+	cli := c.(*cli.Cli)
+
+	reason := pb.Reason_UNSPECIFIED
+	if flags.Reason != nil && *flags.Reason != "" {
+		val, ok := pb.Reason_value[strings.ToUpper(*flags.Reason)]
+		if !ok {
+			return errors.Errorf("unsupported reason: %s", *flags.Reason)
+		}
+		reason = pb.Reason(val)
+	}
+
+	client, err := cli.Client(config.CAServerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	res, err := client.SSHClient().RevokeSSHCertificate(context.Background(), &pb.RevokeSSHCertificateRequest{
+		Ikid:         *flags.Ikid,
+		SerialNumber: *flags.SerialNumber,
+		Reason:       reason,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		crt := res.Certificate
+		fmt.Fprintf(c.Writer(), "Revoked: %s\n  Key ID: %s\n  Principals: %s\n",
+			crt.SerialNumber, crt.KeyId, strings.Join(crt.Principals, ","))
+	}
+
+	return nil
+}
+
+// PublishKRLFlags specifies flags for the PublishKRL action
+type PublishKRLFlags struct {
+	IssuerLabel *string
+	Out         *string
+}
+
+// PublishKRL publishes Key Revocation List
+func PublishKRL(c ctl.Control, p interface{}) error {
+	flags := p.(*PublishKRLFlags)
+	cli := c.(*cli.Cli)
+
+	client, err := cli.Client(config.CAServerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	res, err := client.SSHClient().PublishKRL(context.Background(), &pb.PublishKRLRequest{
+		IssuerLabel: *flags.IssuerLabel,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if flags.Out != nil && *flags.Out != "" {
+		if len(res.Krls) != 1 {
+			return errors.Errorf("expected one KRL, found %d: specify the issuer", len(res.Krls))
+		}
+		err = ioutil.WriteFile(*flags.Out, res.Krls[0].Krl, 0644)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	} else if cli.IsJSON() {
+		ctl.WriteJSON(c.Writer(), res)
+		fmt.Fprint(c.Writer(), "\n")
+	} else {
+		for _, krl := range res.Krls {
+			fmt.Fprintf(c.Writer(), "Issuer: %s\n  IKID: %s\n  Version: %d\n  Size: %d\n",
+				krl.IssuerLabel, krl.Ikid, krl.Version, len(krl.Krl))
+		}
+	}
+
+	return nil
+}
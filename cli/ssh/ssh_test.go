@@ -0,0 +1,186 @@
+package ssh_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ekspand/trusty/api/v1/pb"
+	"github.com/ekspand/trusty/cli/ssh"
+	"github.com/ekspand/trusty/cli/testsuite"
+	"github.com/ekspand/trusty/tests/mockpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/suite"
+)
+
+type testSuite struct {
+	testsuite.Suite
+}
+
+func TestCtlSuite(t *testing.T) {
+	s := new(testSuite)
+	s.WithGRPC()
+	suite.Run(t, s)
+}
+
+func TestCtlSuiteWithJSON(t *testing.T) {
+	s := new(testSuite)
+	s.WithGRPC().WithAppFlags([]string{"--json"})
+	suite.Run(t, s)
+}
+
+func (s *testSuite) TestSign() {
+	expectedResponse := &pb.SSHCertificateResponse{
+		Certificate: &pb.SSHCertificate{
+			Id:           1234,
+			Ikid:         "ikid",
+			SerialNumber: "5678",
+			KeyId:        "denis@ekspand.com",
+			CertType:     "user",
+			Principals:   []string{"denis"},
+			Profile:      "ssh_user",
+			Certificate:  "ssh-ed25519-cert-v01@openssh.com AAAA",
+		},
+	}
+
+	s.MockSSH = &mockpb.MockSSHServer{
+		Err:   nil,
+		Resps: []proto.Message{expectedResponse},
+	}
+	srv := s.SetupMockGRPC()
+	defer srv.Stop()
+
+	profile := "ssh_user"
+	key := "notreal"
+	empty := ""
+	principals := []string{}
+	err := s.Run(ssh.Sign, &ssh.SignFlags{
+		PublicKey:  &key,
+		Profile:    &profile,
+		KeyID:      &empty,
+		Principals: &principals,
+		Out:        &empty,
+	})
+	s.Require().Error(err)
+	s.Equal("failed to load public key: open notreal: no such file or directory", err.Error())
+
+	key = "testdata/id_ed25519.pub"
+	err = s.Run(ssh.Sign, &ssh.SignFlags{
+		PublicKey:  &key,
+		Profile:    &profile,
+		KeyID:      &empty,
+		Principals: &principals,
+		Out:        &empty,
+	})
+	s.Require().NoError(err)
+	if s.Cli.IsJSON() {
+		s.HasText("\"key_id\": \"denis@ekspand.com\"")
+	} else {
+		s.HasText("ssh-ed25519-cert-v01@openssh.com AAAA\n")
+	}
+
+	dir, err := ioutil.TempDir("", "sshsign")
+	s.Require().NoError(err)
+	defer os.RemoveAll(dir)
+
+	out := filepath.Join(dir, "id_ed25519-cert.pub")
+	err = s.Run(ssh.Sign, &ssh.SignFlags{
+		PublicKey:  &key,
+		Profile:    &profile,
+		KeyID:      &empty,
+		Principals: &principals,
+		Out:        &out,
+	})
+	s.Require().NoError(err)
+	s.HasTextInFile(out, "ssh-ed25519-cert-v01@openssh.com AAAA\n")
+}
+
+func (s *testSuite) TestRevoke() {
+	expectedResponse := &pb.SSHCertificateResponse{
+		Certificate: &pb.SSHCertificate{
+			Id:           1234,
+			Ikid:         "ikid",
+			SerialNumber: "5678",
+			KeyId:        "denis@ekspand.com",
+			Principals:   []string{"denis", "ekspand"},
+			Reason:       pb.Reason_KEY_COMPROMISE,
+		},
+	}
+
+	s.MockSSH = &mockpb.MockSSHServer{
+		Err:   nil,
+		Resps: []proto.Message{expectedResponse},
+	}
+	srv := s.SetupMockGRPC()
+	defer srv.Stop()
+
+	ikid := "ikid"
+	serial := "5678"
+	reason := "invalid"
+	err := s.Run(ssh.Revoke, &ssh.RevokeFlags{
+		Ikid:         &ikid,
+		SerialNumber: &serial,
+		Reason:       &reason,
+	})
+	s.Require().Error(err)
+	s.Equal("unsupported reason: invalid", err.Error())
+
+	reason = "key_compromise"
+	err = s.Run(ssh.Revoke, &ssh.RevokeFlags{
+		Ikid:         &ikid,
+		SerialNumber: &serial,
+		Reason:       &reason,
+	})
+	s.Require().NoError(err)
+	if s.Cli.IsJSON() {
+		s.HasText("\"reason\": 1")
+	} else {
+		s.HasText("Revoked: 5678\n  Key ID: denis@ekspand.com\n  Principals: denis,ekspand\n")
+	}
+}
+
+func (s *testSuite) TestPublishKRL() {
+	expectedResponse := &pb.KRLResponse{
+		Krls: []*pb.KRL{
+			{
+				Ikid:        "ikid",
+				IssuerLabel: "ssh",
+				Version:     1,
+				Krl:         []byte("SSHKRL\n\x00"),
+			},
+		},
+	}
+
+	s.MockSSH = &mockpb.MockSSHServer{
+		Err:   nil,
+		Resps: []proto.Message{expectedResponse},
+	}
+	srv := s.SetupMockGRPC()
+	defer srv.Stop()
+
+	label := ""
+	empty := ""
+	err := s.Run(ssh.PublishKRL, &ssh.PublishKRLFlags{
+		IssuerLabel: &label,
+		Out:         &empty,
+	})
+	s.Require().NoError(err)
+	if s.Cli.IsJSON() {
+		s.HasText("\"issuer_label\": \"ssh\"")
+	} else {
+		s.HasText("Issuer: ssh\n  IKID: ikid\n  Version: 1\n  Size: 8\n")
+	}
+
+	dir, err := ioutil.TempDir("", "sshkrl")
+	s.Require().NoError(err)
+	defer os.RemoveAll(dir)
+
+	out := filepath.Join(dir, "krl")
+	err = s.Run(ssh.PublishKRL, &ssh.PublishKRLFlags{
+		IssuerLabel: &label,
+		Out:         &out,
+	})
+	s.Require().NoError(err)
+	s.HasTextInFile(out, "SSHKRL\n")
+}
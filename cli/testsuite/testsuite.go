@@ -41,6 +41,7 @@ type Suite struct {
 	MockAuthority *mockpb.MockCAServer
 	MockCertInfo  *mockpb.MockCIServer
 	MockRA        *mockpb.MockRAServer
+	MockSSH       *mockpb.MockSSHServer
 
 	appFlags       []string
 	withGRPC       bool
@@ -186,6 +187,7 @@ func (s *Suite) SetupMockGRPC() *grpc.Server {
 	pb.RegisterCAServiceServer(serv, s.MockAuthority)
 	pb.RegisterRAServiceServer(serv, s.MockRA)
 	pb.RegisterCIServiceServer(serv, s.MockCertInfo)
+	pb.RegisterSSHServiceServer(serv, s.MockSSH)
 
 	var lis net.Listener
 	var err error
@@ -26,6 +26,10 @@ type CAClient interface {
 	ListCertificates(ctx context.Context, in *pb.ListByIssuerRequest) (*pb.CertificatesResponse, error)
 	// ListRevokedCertificates returns stream of Revoked Certificates
 	ListRevokedCertificates(ctx context.Context, in *pb.ListByIssuerRequest) (*pb.RevokedCertificatesResponse, error)
+	// UpdateCertificateLabels updates the labels of the certificate
+	UpdateCertificateLabels(ctx context.Context, in *pb.UpdateCertificateLabelsRequest) (*pb.CertificateResponse, error)
+	// ReloadConfig reloads the CA configuration and returns the issuing CAs
+	ReloadConfig(ctx context.Context) (*pb.IssuersInfoResponse, error)
 }
 
 type authorityClient struct {
@@ -88,6 +92,16 @@ func (c *authorityClient) ListRevokedCertificates(ctx context.Context, req *pb.L
 	return c.remote.ListRevokedCertificates(ctx, req, c.callOpts...)
 }
 
+// UpdateCertificateLabels updates the labels of the certificate
+func (c *authorityClient) UpdateCertificateLabels(ctx context.Context, in *pb.UpdateCertificateLabelsRequest) (*pb.CertificateResponse, error) {
+	return c.remote.UpdateCertificateLabels(ctx, in, c.callOpts...)
+}
+
+// ReloadConfig reloads the CA configuration and returns the issuing CAs
+func (c *authorityClient) ReloadConfig(ctx context.Context) (*pb.IssuersInfoResponse, error) {
+	return c.remote.ReloadConfig(ctx, emptyReq, c.callOpts...)
+}
+
 type retryCAClient struct {
 	authority pb.CAServiceClient
 }
@@ -140,3 +154,13 @@ func (c *retryCAClient) ListCertificates(ctx context.Context, req *pb.ListByIssu
 func (c *retryCAClient) ListRevokedCertificates(ctx context.Context, req *pb.ListByIssuerRequest, opts ...grpc.CallOption) (*pb.RevokedCertificatesResponse, error) {
 	return c.authority.ListRevokedCertificates(ctx, req, opts...)
 }
+
+// UpdateCertificateLabels updates the labels of the certificate
+func (c *retryCAClient) UpdateCertificateLabels(ctx context.Context, in *pb.UpdateCertificateLabelsRequest, opts ...grpc.CallOption) (*pb.CertificateResponse, error) {
+	return c.authority.UpdateCertificateLabels(ctx, in, opts...)
+}
+
+// ReloadConfig reloads the CA configuration and returns the issuing CAs
+func (c *retryCAClient) ReloadConfig(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*pb.IssuersInfoResponse, error) {
+	return c.authority.ReloadConfig(ctx, in, opts...)
+}
@@ -107,6 +107,21 @@ func (c *Client) CAClient() CAClient {
 	return NewCAClient(c.conn, c.callOpts)
 }
 
+// SigningClient returns SigningClient client from connection
+func (c *Client) SigningClient() SigningClient {
+	return NewSigningClient(c.conn, c.callOpts)
+}
+
+// SPIFFEClient returns SPIFFEClient client from connection
+func (c *Client) SPIFFEClient() SPIFFEClient {
+	return NewSPIFFEClient(c.conn, c.callOpts)
+}
+
+// SSHClient returns SSHClient client from connection
+func (c *Client) SSHClient() SSHClient {
+	return NewSSHClient(c.conn, c.callOpts)
+}
+
 // StatusClient returns StatusClient client from connection
 func (c *Client) StatusClient() StatusClient {
 	return NewStatusClient(c.conn, c.callOpts)
@@ -162,7 +177,10 @@ func newClient(cfg *Config) (*Client, error) {
 		bundle := tcredentials.NewBundle(tcredentials.Config{TLSConfig: cfg.TLS})
 		creds = bundle.TransportCredentials()
 		// grpc: the credentials require transport level security
-		tk := os.Getenv("TRUSTY_AUTH_TOKEN")
+		tk := cfg.AuthToken
+		if tk == "" {
+			tk = os.Getenv("TRUSTY_AUTH_TOKEN")
+		}
 		if tk != "" {
 			bundle.UpdateAuthToken(tk)
 			dopts = append(dopts, grpc.WithPerRPCCredentials(bundle.PerRPCCredentials()))
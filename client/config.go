@@ -27,6 +27,10 @@ type Config struct {
 	// TLS holds the client secure credentials, if any.
 	TLS *tls.Config
 
+	// AuthToken specifies the access token to authorize the calls,
+	// if not set then TRUSTY_AUTH_TOKEN environment is used.
+	AuthToken string
+
 	// DialOptions is a list of dial options for the grpc client (e.g., for interceptors).
 	// For example, pass "grpc.WithBlock()" to block until the underlying connection is up.
 	// Without this, Dial returns immediately and connecting the server happens in background.
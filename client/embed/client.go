@@ -27,6 +27,30 @@ func NewCAClient(s *gserver.Server) client.CAClient {
 	return nil
 }
 
+// NewSigningClient returns embedded SigningClient for running server
+func NewSigningClient(s *gserver.Server) client.SigningClient {
+	if signingServer, ok := s.Service(ca.ServiceName).(pb.SigningServiceServer); ok {
+		return client.NewSigningClientFromProxy(proxy.SigningServerToClient(signingServer))
+	}
+	return nil
+}
+
+// NewSPIFFEClient returns embedded SPIFFEClient for running server
+func NewSPIFFEClient(s *gserver.Server) client.SPIFFEClient {
+	if spiffeServer, ok := s.Service(ca.ServiceName).(pb.SPIFFEServiceServer); ok {
+		return client.NewSPIFFEClientFromProxy(proxy.SPIFFEServerToClient(spiffeServer))
+	}
+	return nil
+}
+
+// NewSSHClient returns embedded SSHClient for running server
+func NewSSHClient(s *gserver.Server) client.SSHClient {
+	if sshServer, ok := s.Service(ca.ServiceName).(pb.SSHServiceServer); ok {
+		return client.NewSSHClientFromProxy(proxy.SSHServerToClient(sshServer))
+	}
+	return nil
+}
+
 // NewCIClient returns embedded CIClient for running server
 func NewCIClient(s *gserver.Server) client.CIClient {
 	if cisServer, ok := s.Service(cis.ServiceName).(pb.CIServiceServer); ok {
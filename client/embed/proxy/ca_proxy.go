@@ -56,3 +56,13 @@ func (s *caSrv2C) ListCertificates(ctx context.Context, req *pb.ListByIssuerRequ
 func (s *caSrv2C) ListRevokedCertificates(ctx context.Context, req *pb.ListByIssuerRequest, opts ...grpc.CallOption) (*pb.RevokedCertificatesResponse, error) {
 	return s.srv.ListRevokedCertificates(ctx, req)
 }
+
+// UpdateCertificateLabels updates the labels of the certificate
+func (s *caSrv2C) UpdateCertificateLabels(ctx context.Context, in *pb.UpdateCertificateLabelsRequest, opts ...grpc.CallOption) (*pb.CertificateResponse, error) {
+	return s.srv.UpdateCertificateLabels(ctx, in)
+}
+
+// ReloadConfig reloads the CA configuration and returns the issuing CAs
+func (s *caSrv2C) ReloadConfig(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*pb.IssuersInfoResponse, error) {
+	return s.srv.ReloadConfig(ctx, in)
+}
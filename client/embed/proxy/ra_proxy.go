@@ -36,3 +36,23 @@ func (s *raSrv2C) RegisterCertificate(ctx context.Context, in *pb.RegisterCertif
 func (s *raSrv2C) GetCertificate(ctx context.Context, in *pb.GetCertificateRequest, opts ...grpc.CallOption) (*pb.CertificateResponse, error) {
 	return s.srv.GetCertificate(ctx, in)
 }
+
+// ListApprovals returns the certificate requests in the approval queue
+func (s *raSrv2C) ListApprovals(ctx context.Context, in *pb.ListApprovalsRequest, opts ...grpc.CallOption) (*pb.ApprovalsResponse, error) {
+	return s.srv.ListApprovals(ctx, in)
+}
+
+// GetApproval returns the certificate request in the approval queue
+func (s *raSrv2C) GetApproval(ctx context.Context, in *pb.GetApprovalRequest, opts ...grpc.CallOption) (*pb.ApprovalResponse, error) {
+	return s.srv.GetApproval(ctx, in)
+}
+
+// Approve approves the certificate request
+func (s *raSrv2C) Approve(ctx context.Context, in *pb.ApprovalDecisionRequest, opts ...grpc.CallOption) (*pb.ApprovalResponse, error) {
+	return s.srv.Approve(ctx, in)
+}
+
+// Deny denies the certificate request
+func (s *raSrv2C) Deny(ctx context.Context, in *pb.ApprovalDecisionRequest, opts ...grpc.CallOption) (*pb.ApprovalResponse, error) {
+	return s.srv.Deny(ctx, in)
+}
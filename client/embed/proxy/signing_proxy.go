@@ -0,0 +1,27 @@
+package proxy
+
+import (
+	"context"
+
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"google.golang.org/grpc"
+)
+
+type signingSrv2C struct {
+	srv pb.SigningServiceServer
+}
+
+// SigningServerToClient returns pb.SigningServiceClient
+func SigningServerToClient(srv pb.SigningServiceServer) pb.SigningServiceClient {
+	return &signingSrv2C{srv}
+}
+
+// SignDigest returns detached signature of the digest
+func (s *signingSrv2C) SignDigest(ctx context.Context, in *pb.SignDigestRequest, opts ...grpc.CallOption) (*pb.SignatureResponse, error) {
+	return s.srv.SignDigest(ctx, in)
+}
+
+// SignKeyless returns a short-lived certificate for the caller's public key
+func (s *signingSrv2C) SignKeyless(ctx context.Context, in *pb.SignKeylessRequest, opts ...grpc.CallOption) (*pb.KeylessCertificateResponse, error) {
+	return s.srv.SignKeyless(ctx, in)
+}
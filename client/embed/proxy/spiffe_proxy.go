@@ -0,0 +1,27 @@
+package proxy
+
+import (
+	"context"
+
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"google.golang.org/grpc"
+)
+
+type spiffeSrv2C struct {
+	srv pb.SPIFFEServiceServer
+}
+
+// SPIFFEServerToClient returns pb.SPIFFEServiceClient
+func SPIFFEServerToClient(srv pb.SPIFFEServiceServer) pb.SPIFFEServiceClient {
+	return &spiffeSrv2C{srv}
+}
+
+// SignJWTSVID returns JWT-SVID for SPIFFE ID of the caller
+func (s *spiffeSrv2C) SignJWTSVID(ctx context.Context, in *pb.JWTSVIDRequest, opts ...grpc.CallOption) (*pb.JWTSVIDResponse, error) {
+	return s.srv.SignJWTSVID(ctx, in)
+}
+
+// ValidateJWTSVID validates JWT-SVID for the audience
+func (s *spiffeSrv2C) ValidateJWTSVID(ctx context.Context, in *pb.ValidateJWTSVIDRequest, opts ...grpc.CallOption) (*pb.ValidateJWTSVIDResponse, error) {
+	return s.srv.ValidateJWTSVID(ctx, in)
+}
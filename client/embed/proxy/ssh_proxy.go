@@ -0,0 +1,32 @@
+package proxy
+
+import (
+	"context"
+
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"google.golang.org/grpc"
+)
+
+type sshSrv2C struct {
+	srv pb.SSHServiceServer
+}
+
+// SSHServerToClient returns pb.SSHServiceClient
+func SSHServerToClient(srv pb.SSHServiceServer) pb.SSHServiceClient {
+	return &sshSrv2C{srv}
+}
+
+// SignSSHKey returns SSH certificate for the public key
+func (s *sshSrv2C) SignSSHKey(ctx context.Context, in *pb.SignSSHKeyRequest, opts ...grpc.CallOption) (*pb.SSHCertificateResponse, error) {
+	return s.srv.SignSSHKey(ctx, in)
+}
+
+// RevokeSSHCertificate returns the revoked SSH certificate
+func (s *sshSrv2C) RevokeSSHCertificate(ctx context.Context, in *pb.RevokeSSHCertificateRequest, opts ...grpc.CallOption) (*pb.SSHCertificateResponse, error) {
+	return s.srv.RevokeSSHCertificate(ctx, in)
+}
+
+// PublishKRL returns published Key Revocation Lists
+func (s *sshSrv2C) PublishKRL(ctx context.Context, in *pb.PublishKRLRequest, opts ...grpc.CallOption) (*pb.KRLResponse, error) {
+	return s.srv.PublishKRL(ctx, in)
+}
@@ -21,6 +21,18 @@ type RAClient interface {
 
 	// GetCertificate returns certificate
 	GetCertificate(ctx context.Context, in *pb.GetCertificateRequest) (*pb.CertificateResponse, error)
+
+	// ListApprovals returns the certificate requests in the approval queue
+	ListApprovals(ctx context.Context, in *pb.ListApprovalsRequest) (*pb.ApprovalsResponse, error)
+
+	// GetApproval returns the certificate request in the approval queue
+	GetApproval(ctx context.Context, in *pb.GetApprovalRequest) (*pb.ApprovalResponse, error)
+
+	// Approve approves the certificate request
+	Approve(ctx context.Context, in *pb.ApprovalDecisionRequest) (*pb.ApprovalResponse, error)
+
+	// Deny denies the certificate request
+	Deny(ctx context.Context, in *pb.ApprovalDecisionRequest) (*pb.ApprovalResponse, error)
 }
 
 type raClient struct {
@@ -63,6 +75,26 @@ func (c *raClient) GetCertificate(ctx context.Context, in *pb.GetCertificateRequ
 	return c.remote.GetCertificate(ctx, in, c.callOpts...)
 }
 
+// ListApprovals returns the certificate requests in the approval queue
+func (c *raClient) ListApprovals(ctx context.Context, in *pb.ListApprovalsRequest) (*pb.ApprovalsResponse, error) {
+	return c.remote.ListApprovals(ctx, in, c.callOpts...)
+}
+
+// GetApproval returns the certificate request in the approval queue
+func (c *raClient) GetApproval(ctx context.Context, in *pb.GetApprovalRequest) (*pb.ApprovalResponse, error) {
+	return c.remote.GetApproval(ctx, in, c.callOpts...)
+}
+
+// Approve approves the certificate request
+func (c *raClient) Approve(ctx context.Context, in *pb.ApprovalDecisionRequest) (*pb.ApprovalResponse, error) {
+	return c.remote.Approve(ctx, in, c.callOpts...)
+}
+
+// Deny denies the certificate request
+func (c *raClient) Deny(ctx context.Context, in *pb.ApprovalDecisionRequest) (*pb.ApprovalResponse, error) {
+	return c.remote.Deny(ctx, in, c.callOpts...)
+}
+
 type retryRAClient struct {
 	ra pb.RAServiceClient
 }
@@ -95,3 +127,23 @@ func (c *retryRAClient) RegisterCertificate(ctx context.Context, in *pb.Register
 func (c *retryRAClient) GetCertificate(ctx context.Context, in *pb.GetCertificateRequest, opts ...grpc.CallOption) (*pb.CertificateResponse, error) {
 	return c.ra.GetCertificate(ctx, in, opts...)
 }
+
+// ListApprovals returns the certificate requests in the approval queue
+func (c *retryRAClient) ListApprovals(ctx context.Context, in *pb.ListApprovalsRequest, opts ...grpc.CallOption) (*pb.ApprovalsResponse, error) {
+	return c.ra.ListApprovals(ctx, in, opts...)
+}
+
+// GetApproval returns the certificate request in the approval queue
+func (c *retryRAClient) GetApproval(ctx context.Context, in *pb.GetApprovalRequest, opts ...grpc.CallOption) (*pb.ApprovalResponse, error) {
+	return c.ra.GetApproval(ctx, in, opts...)
+}
+
+// Approve approves the certificate request
+func (c *retryRAClient) Approve(ctx context.Context, in *pb.ApprovalDecisionRequest, opts ...grpc.CallOption) (*pb.ApprovalResponse, error) {
+	return c.ra.Approve(ctx, in, opts...)
+}
+
+// Deny denies the certificate request
+func (c *retryRAClient) Deny(ctx context.Context, in *pb.ApprovalDecisionRequest, opts ...grpc.CallOption) (*pb.ApprovalResponse, error) {
+	return c.ra.Deny(ctx, in, opts...)
+}
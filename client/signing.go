@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"google.golang.org/grpc"
+)
+
+// SigningClient client interface
+type SigningClient interface {
+	// SignDigest returns detached signature of the digest,
+	// signed with a short-lived certificate issued for the request
+	SignDigest(ctx context.Context, in *pb.SignDigestRequest) (*pb.SignatureResponse, error)
+	// SignKeyless returns a short-lived certificate for the caller's public key,
+	// bound to the identity presented by the token
+	SignKeyless(ctx context.Context, in *pb.SignKeylessRequest) (*pb.KeylessCertificateResponse, error)
+}
+
+type signingClient struct {
+	remote   pb.SigningServiceClient
+	callOpts []grpc.CallOption
+}
+
+// NewSigningClient returns instance of SigningService client
+func NewSigningClient(conn *grpc.ClientConn, callOpts []grpc.CallOption) SigningClient {
+	return &signingClient{
+		remote:   RetrySigningClient(conn),
+		callOpts: callOpts,
+	}
+}
+
+// NewSigningClientFromProxy returns instance of SigningService client
+func NewSigningClientFromProxy(proxy pb.SigningServiceClient) SigningClient {
+	return &signingClient{
+		remote: proxy,
+	}
+}
+
+// SignDigest returns detached signature of the digest
+func (c *signingClient) SignDigest(ctx context.Context, in *pb.SignDigestRequest) (*pb.SignatureResponse, error) {
+	return c.remote.SignDigest(ctx, in, c.callOpts...)
+}
+
+// SignKeyless returns a short-lived certificate for the caller's public key
+func (c *signingClient) SignKeyless(ctx context.Context, in *pb.SignKeylessRequest) (*pb.KeylessCertificateResponse, error) {
+	return c.remote.SignKeyless(ctx, in, c.callOpts...)
+}
+
+type retrySigningClient struct {
+	signing pb.SigningServiceClient
+}
+
+// TODO: implement retry for gRPC client interceptor
+
+// RetrySigningClient implements a SigningServiceClient.
+func RetrySigningClient(conn *grpc.ClientConn) pb.SigningServiceClient {
+	return &retrySigningClient{
+		signing: pb.NewSigningServiceClient(conn),
+	}
+}
+
+// SignDigest returns detached signature of the digest
+func (c *retrySigningClient) SignDigest(ctx context.Context, in *pb.SignDigestRequest, opts ...grpc.CallOption) (*pb.SignatureResponse, error) {
+	return c.signing.SignDigest(ctx, in, opts...)
+}
+
+// SignKeyless returns a short-lived certificate for the caller's public key
+func (c *retrySigningClient) SignKeyless(ctx context.Context, in *pb.SignKeylessRequest, opts ...grpc.CallOption) (*pb.KeylessCertificateResponse, error) {
+	return c.signing.SignKeyless(ctx, in, opts...)
+}
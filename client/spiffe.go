@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"google.golang.org/grpc"
+)
+
+// SPIFFEClient client interface
+type SPIFFEClient interface {
+	// SignJWTSVID returns JWT-SVID for SPIFFE ID of the caller,
+	// authenticated with X.509-SVID
+	SignJWTSVID(ctx context.Context, in *pb.JWTSVIDRequest) (*pb.JWTSVIDResponse, error)
+	// ValidateJWTSVID validates JWT-SVID for the audience
+	ValidateJWTSVID(ctx context.Context, in *pb.ValidateJWTSVIDRequest) (*pb.ValidateJWTSVIDResponse, error)
+}
+
+type spiffeClient struct {
+	remote   pb.SPIFFEServiceClient
+	callOpts []grpc.CallOption
+}
+
+// NewSPIFFEClient returns instance of SPIFFEService client
+func NewSPIFFEClient(conn *grpc.ClientConn, callOpts []grpc.CallOption) SPIFFEClient {
+	return &spiffeClient{
+		remote:   RetrySPIFFEClient(conn),
+		callOpts: callOpts,
+	}
+}
+
+// NewSPIFFEClientFromProxy returns instance of SPIFFEService client
+func NewSPIFFEClientFromProxy(proxy pb.SPIFFEServiceClient) SPIFFEClient {
+	return &spiffeClient{
+		remote: proxy,
+	}
+}
+
+// SignJWTSVID returns JWT-SVID for SPIFFE ID of the caller
+func (c *spiffeClient) SignJWTSVID(ctx context.Context, in *pb.JWTSVIDRequest) (*pb.JWTSVIDResponse, error) {
+	return c.remote.SignJWTSVID(ctx, in, c.callOpts...)
+}
+
+// ValidateJWTSVID validates JWT-SVID for the audience
+func (c *spiffeClient) ValidateJWTSVID(ctx context.Context, in *pb.ValidateJWTSVIDRequest) (*pb.ValidateJWTSVIDResponse, error) {
+	return c.remote.ValidateJWTSVID(ctx, in, c.callOpts...)
+}
+
+type retrySPIFFEClient struct {
+	spiffe pb.SPIFFEServiceClient
+}
+
+// TODO: implement retry for gRPC client interceptor
+
+// RetrySPIFFEClient implements a SPIFFEServiceClient.
+func RetrySPIFFEClient(conn *grpc.ClientConn) pb.SPIFFEServiceClient {
+	return &retrySPIFFEClient{
+		spiffe: pb.NewSPIFFEServiceClient(conn),
+	}
+}
+
+// SignJWTSVID returns JWT-SVID for SPIFFE ID of the caller
+func (c *retrySPIFFEClient) SignJWTSVID(ctx context.Context, in *pb.JWTSVIDRequest, opts ...grpc.CallOption) (*pb.JWTSVIDResponse, error) {
+	return c.spiffe.SignJWTSVID(ctx, in, opts...)
+}
+
+// ValidateJWTSVID validates JWT-SVID for the audience
+func (c *retrySPIFFEClient) ValidateJWTSVID(ctx context.Context, in *pb.ValidateJWTSVIDRequest, opts ...grpc.CallOption) (*pb.ValidateJWTSVIDResponse, error) {
+	return c.spiffe.ValidateJWTSVID(ctx, in, opts...)
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+
+	pb "github.com/ekspand/trusty/api/v1/pb"
+	"google.golang.org/grpc"
+)
+
+// SSHClient client interface
+type SSHClient interface {
+	// SignSSHKey returns SSH certificate for the public key
+	SignSSHKey(ctx context.Context, in *pb.SignSSHKeyRequest) (*pb.SSHCertificateResponse, error)
+	// RevokeSSHCertificate returns the revoked SSH certificate
+	RevokeSSHCertificate(ctx context.Context, in *pb.RevokeSSHCertificateRequest) (*pb.SSHCertificateResponse, error)
+	// PublishKRL returns published Key Revocation Lists
+	PublishKRL(ctx context.Context, in *pb.PublishKRLRequest) (*pb.KRLResponse, error)
+}
+
+type sshClient struct {
+	remote   pb.SSHServiceClient
+	callOpts []grpc.CallOption
+}
+
+// NewSSHClient returns instance of SSHService client
+func NewSSHClient(conn *grpc.ClientConn, callOpts []grpc.CallOption) SSHClient {
+	return &sshClient{
+		remote:   RetrySSHClient(conn),
+		callOpts: callOpts,
+	}
+}
+
+// NewSSHClientFromProxy returns instance of SSHService client
+func NewSSHClientFromProxy(proxy pb.SSHServiceClient) SSHClient {
+	return &sshClient{
+		remote: proxy,
+	}
+}
+
+// SignSSHKey returns SSH certificate for the public key
+func (c *sshClient) SignSSHKey(ctx context.Context, in *pb.SignSSHKeyRequest) (*pb.SSHCertificateResponse, error) {
+	return c.remote.SignSSHKey(ctx, in, c.callOpts...)
+}
+
+// RevokeSSHCertificate returns the revoked SSH certificate
+func (c *sshClient) RevokeSSHCertificate(ctx context.Context, in *pb.RevokeSSHCertificateRequest) (*pb.SSHCertificateResponse, error) {
+	return c.remote.RevokeSSHCertificate(ctx, in, c.callOpts...)
+}
+
+// PublishKRL returns published Key Revocation Lists
+func (c *sshClient) PublishKRL(ctx context.Context, in *pb.PublishKRLRequest) (*pb.KRLResponse, error) {
+	return c.remote.PublishKRL(ctx, in, c.callOpts...)
+}
+
+type retrySSHClient struct {
+	ssh pb.SSHServiceClient
+}
+
+// TODO: implement retry for gRPC client interceptor
+
+// RetrySSHClient implements a SSHServiceClient.
+func RetrySSHClient(conn *grpc.ClientConn) pb.SSHServiceClient {
+	return &retrySSHClient{
+		ssh: pb.NewSSHServiceClient(conn),
+	}
+}
+
+// SignSSHKey returns SSH certificate for the public key
+func (c *retrySSHClient) SignSSHKey(ctx context.Context, in *pb.SignSSHKeyRequest, opts ...grpc.CallOption) (*pb.SSHCertificateResponse, error) {
+	return c.ssh.SignSSHKey(ctx, in, opts...)
+}
+
+// RevokeSSHCertificate returns the revoked SSH certificate
+func (c *retrySSHClient) RevokeSSHCertificate(ctx context.Context, in *pb.RevokeSSHCertificateRequest, opts ...grpc.CallOption) (*pb.SSHCertificateResponse, error) {
+	return c.ssh.RevokeSSHCertificate(ctx, in, opts...)
+}
+
+// PublishKRL returns published Key Revocation Lists
+func (c *retrySSHClient) PublishKRL(ctx context.Context, in *pb.PublishKRLRequest, opts ...grpc.CallOption) (*pb.KRLResponse, error) {
+	return c.ssh.PublishKRL(ctx, in, opts...)
+}
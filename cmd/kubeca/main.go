@@ -38,6 +38,7 @@ func main() {
 
 	flag.StringVar(&f.CaCfgPath, "ca-cfg", "/trusty/etc/ca-config.yaml", "Location of CA configuration file.")
 	flag.StringVar(&f.HsmCfgPath, "hsm-cfg", "/trusty/etc/aws-kms-us-west-2.json", "Location of HSM configuration file.")
+	flag.StringVar(&f.AuditDir, "audit-dir", "", "Location of audit files, if not provided then the audit is disabled.")
 
 	flag.Parse()
 
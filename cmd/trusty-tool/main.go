@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/ekspand/trusty/cli"
+	"github.com/ekspand/trusty/cli/ca"
 	"github.com/ekspand/trusty/cli/certutil"
 	"github.com/ekspand/trusty/cli/csr"
 	"github.com/ekspand/trusty/cli/hsm"
@@ -95,6 +96,40 @@ func realMain(args []string, out io.Writer, errout io.Writer) ctl.ReturnCode {
 	genCertFlags.SAN = cmdGenCertCSR.Flag("SAN", "coma separated list of SAN to be added to certificate").String()
 	genCertFlags.Output = cmdGenCertCSR.Flag("out", "specifies the optional prefix for output files").String()
 
+	// ca cross-sign|rollover prepare|import
+	cmdCA := app.Command("ca", "CA commands").
+		PreAction(cli.PopulateControl).
+		PreAction(cli.EnsureCryptoProvider)
+
+	crossSignFlags := new(csr.CrossSignFlags)
+	cmdCrossSign := cmdCA.Command("cross-sign", "issues cross-certificate for the existing CA certificate").
+		Action(cli.RegisterAction(csr.CrossSign, crossSignFlags))
+	crossSignFlags.Cert = cmdCrossSign.Flag("cert", "CA certificate to be cross-signed").Required().String()
+	crossSignFlags.CAConfig = cmdCrossSign.Flag("ca-config", "CA configuration file").Required().String()
+	crossSignFlags.CACert = cmdCrossSign.Flag("ca-cert", "CA certificate of the cross-signing root").Required().String()
+	crossSignFlags.CAKey = cmdCrossSign.Flag("ca-key", "CA key of the cross-signing root").Required().String()
+	crossSignFlags.Profile = cmdCrossSign.Flag("profile", "certificate profile").Required().String()
+	crossSignFlags.Output = cmdCrossSign.Flag("out", "specifies the optional prefix for output files").String()
+	cmdRollover := cmdCA.Command("rollover", "issuer key and certificate rollover")
+
+	rolloverPrepareFlags := new(ca.RolloverPrepareFlags)
+	cmdRolloverPrepare := cmdRollover.Command("prepare", "generates a new key and certificate request for the issuer").
+		Action(cli.RegisterAction(ca.RolloverPrepare, rolloverPrepareFlags))
+	rolloverPrepareFlags.CAConfig = cmdRolloverPrepare.Flag("ca-config", "CA configuration file").Required().String()
+	rolloverPrepareFlags.Label = cmdRolloverPrepare.Flag("label", "label of the current issuer").Required().String()
+	rolloverPrepareFlags.KeyLabel = cmdRolloverPrepare.Flag("key-label", "label for generated key").String()
+	rolloverPrepareFlags.Output = cmdRolloverPrepare.Flag("out", "specifies the optional prefix for output files").String()
+
+	rolloverImportFlags := new(ca.RolloverImportFlags)
+	cmdRolloverImport := cmdRollover.Command("import", "imports the new issuer's certificate and updates CA configuration, the current issuer retires after the longest expiry of its profiles").
+		Action(cli.RegisterAction(ca.RolloverImport, rolloverImportFlags))
+	rolloverImportFlags.CAConfig = cmdRolloverImport.Flag("ca-config", "CA configuration file").Required().String()
+	rolloverImportFlags.Label = cmdRolloverImport.Flag("label", "label of the current issuer").Required().String()
+	rolloverImportFlags.NewLabel = cmdRolloverImport.Flag("new-label", "label of the new issuer").Required().String()
+	rolloverImportFlags.Cert = cmdRolloverImport.Flag("cert", "certificate of the new issuer signed by the parent CA").Required().String()
+	rolloverImportFlags.Key = cmdRolloverImport.Flag("key", "key of the new issuer").Required().String()
+	rolloverImportFlags.Output = cmdRolloverImport.Flag("out", "specifies the optional file name for updated CA configuration").String()
+
 	// hsm slots|lskey|rmkey|genkey
 	cmdHsm := app.Command("hsm", "Perform HSM operations").
 		PreAction(cli.PopulateControl).
@@ -132,7 +167,7 @@ func realMain(args []string, out io.Writer, errout io.Writer) ctl.ReturnCode {
 	hsmGenKeyFlags.Output = cmdHsmGenKey.Flag("output", "Optional output file name").String()
 	hsmGenKeyFlags.Force = cmdHsmGenKey.Flag("force", "Override output file if exists").Bool()
 
-	// cert info|validate
+	// cert info|validate|lint
 	cmdCert := app.Command("cert", "Cert utils").
 		PreAction(cli.PopulateControl)
 
@@ -152,6 +187,32 @@ func realMain(args []string, out io.Writer, errout io.Writer) ctl.ReturnCode {
 	validateFlags.Root = cmdCertValidate.Flag("root", "PEM-encoded file with Root CA").String()
 	validateFlags.Out = cmdCertValidate.Flag("out", "Output PEM-encoded file with Leaf and intermediate CA certificates").String()
 
+	lintFlags := new(certutil.LintFlags)
+	cmdCertLint := cmdCert.Command("lint", "Lint certificates").
+		Action(cli.RegisterAction(certutil.Lint, lintFlags))
+	lintFlags.In = cmdCertLint.Flag("in", "PEM-encoded file with certificates").Required().String()
+	lintFlags.CAConfig = cmdCertLint.Flag("ca-config", "Optional, CA configuration file").String()
+	lintFlags.Profile = cmdCertLint.Flag("profile", "Optional, certificate profile to apply lint levels").String()
+
+	// policy test
+	cmdPolicy := app.Command("policy", "Issuance policy utils").
+		PreAction(cli.PopulateControl)
+
+	policyTestFlags := new(certutil.PolicyTestFlags)
+	cmdPolicyTest := cmdPolicy.Command("test", "Evaluate issuance policy of the profile against certificate request").
+		Action(cli.RegisterAction(certutil.PolicyTest, policyTestFlags))
+	policyTestFlags.CSR = cmdPolicyTest.Flag("csr", "PEM-encoded file with certificate request").Required().String()
+	policyTestFlags.CAConfig = cmdPolicyTest.Flag("ca-config", "CA configuration file").Required().String()
+	policyTestFlags.Profile = cmdPolicyTest.Flag("profile", "certificate profile").Required().String()
+	policyTestFlags.SAN = cmdPolicyTest.Flag("SAN", "coma separated list of SAN to be added to certificate").String()
+	policyTestFlags.Issuer = cmdPolicyTest.Flag("issuer", "Optional, issuer label").String()
+	policyTestFlags.Name = cmdPolicyTest.Flag("name", "caller's name").String()
+	policyTestFlags.Role = cmdPolicyTest.Flag("role", "caller's role").String()
+	policyTestFlags.Roles = cmdPolicyTest.Flag("roles", "coma separated list of caller's roles").String()
+	policyTestFlags.Orgs = cmdPolicyTest.Flag("orgs", "coma separated list of caller's orgs").String()
+	policyTestFlags.OrgID = cmdPolicyTest.Flag("org-id", "org ID of the request").Uint64()
+	policyTestFlags.Time = cmdPolicyTest.Flag("time", "Optional, time of the request in RFC3339 format").String()
+
 	// crl info|get
 	cmdCRL := app.Command("crl", "CRL utils").
 		PreAction(cli.PopulateControl)
@@ -9,6 +9,10 @@ import (
 	"github.com/ekspand/trusty/cli/auth"
 	"github.com/ekspand/trusty/cli/ca"
 	"github.com/ekspand/trusty/cli/cis"
+	"github.com/ekspand/trusty/cli/ra"
+	"github.com/ekspand/trusty/cli/role"
+	"github.com/ekspand/trusty/cli/sa"
+	"github.com/ekspand/trusty/cli/ssh"
 	"github.com/ekspand/trusty/cli/status"
 	"github.com/ekspand/trusty/internal/version"
 	"github.com/go-phorce/dolly/ctl"
@@ -61,9 +65,10 @@ func realMain(args []string, out io.Writer, errout io.Writer) ctl.ReturnCode {
 		PreAction(cli.PopulateControl).
 		Action(cli.RegisterAction(auth.Authenticate, loginFlags))
 	loginFlags.NoBrowser = cmdLogin.Flag("no-browser", "disable openning in browser").Bool()
-	loginFlags.Provider = cmdLogin.Flag("provider", "oauth2 provider, should be github or google").Default("github").String()
+	loginFlags.Provider = cmdLogin.Flag("provider", "oauth2 provider: github, google, or provider_id of configured OIDC provider").Default("github").String()
+	loginFlags.Device = cmdLogin.Flag("device", "login with device code, the access token is stored in TRUSTY_CREDENTIALS file").Bool()
 
-	// ca: issuers|profile|sign|certs|revoked|publish_crl
+	// ca: issuers|reload|profile|sign|certs|revoked|publish_crl
 
 	cmdCA := app.Command("ca", "CA operations").
 		PreAction(cli.PopulateControl)
@@ -71,6 +76,9 @@ func realMain(args []string, out io.Writer, errout io.Writer) ctl.ReturnCode {
 	cmdCA.Command("issuers", "show the issuing CAs").
 		Action(cli.RegisterAction(ca.Issuers, nil))
 
+	cmdCA.Command("reload", "reload the CA configuration").
+		Action(cli.RegisterAction(ca.Reload, nil))
+
 	getProfileFlags := new(ca.GetProfileFlags)
 	profileCmd := cmdCA.Command("profile", "show the certificate profile").
 		Action(cli.RegisterAction(ca.Profile, getProfileFlags))
@@ -85,27 +93,158 @@ func realMain(args []string, out io.Writer, errout io.Writer) ctl.ReturnCode {
 	signFlags.IssuerLabel = signCmd.Flag("issuer", "label of issuer to use").String()
 	signFlags.Token = signCmd.Flag("token", "authorization token for the request").String()
 	signFlags.SAN = signCmd.Flag("san", "optional SAN").Strings()
+	signFlags.Labels = signCmd.Flag("label", "key=value label for the certificate, can be specified multiple times").Strings()
 	signFlags.Out = signCmd.Flag("out", "output file name").String()
 
 	listCertsFlags := new(ca.ListCertsFlags)
 	listCertsCmd := cmdCA.Command("certs", "print the certificates").
 		Action(cli.RegisterAction(ca.ListCerts, listCertsFlags))
-	listCertsFlags.Ikid = listCertsCmd.Flag("ikid", "Issuer Key Identifier").Required().String()
+	listCertsFlags.Ikid = listCertsCmd.Flag("ikid", "Issuer Key Identifier, required if --selector is not specified").String()
+	listCertsFlags.Selector = listCertsCmd.Flag("selector", "label selector, for example: env=prod,team,!deprecated").String()
 	listCertsFlags.Limit = listCertsCmd.Flag("limit", "max limit of the certificates to print").Int()
 	listCertsFlags.After = listCertsCmd.Flag("after", "the certificate ID for pagination").String()
 
 	rlistCertsFlags := new(ca.ListCertsFlags)
 	revokedCmd := cmdCA.Command("revoked", "print the revoked certificates").
 		Action(cli.RegisterAction(ca.ListRevokedCerts, rlistCertsFlags))
-	rlistCertsFlags.Ikid = revokedCmd.Flag("ikid", "Issuer Key Identifier").Required().String()
+	rlistCertsFlags.Ikid = revokedCmd.Flag("ikid", "Issuer Key Identifier, required if --selector is not specified").String()
+	rlistCertsFlags.Selector = revokedCmd.Flag("selector", "label selector, for example: env=prod,team,!deprecated").String()
 	rlistCertsFlags.Limit = revokedCmd.Flag("limit", "max limit of the certificates to print").Int()
 	rlistCertsFlags.After = revokedCmd.Flag("after", "the certificate ID for pagination").String()
 
+	labelsFlags := new(ca.UpdateLabelsFlags)
+	labelsCmd := cmdCA.Command("labels", "update the certificate labels").
+		Action(cli.RegisterAction(ca.UpdateLabels, labelsFlags))
+	labelsFlags.ID = labelsCmd.Flag("id", "ID of the certificate").String()
+	labelsFlags.SKID = labelsCmd.Flag("skid", "Subject Key Identifier of the certificate").String()
+	labelsFlags.Set = labelsCmd.Flag("set", "key=value label to add or update, can be specified multiple times").Strings()
+	labelsFlags.Remove = labelsCmd.Flag("remove", "label key to remove, can be specified multiple times").Strings()
+
 	publishCrlFlags := new(ca.PublishCrlsFlags)
 	publishCrlCmd := cmdCA.Command("publish_crl", "publish CRL").
 		Action(cli.RegisterAction(ca.PublishCrls, publishCrlFlags))
 	publishCrlFlags.Ikid = publishCrlCmd.Flag("ikid", "Issuer Key Identifier").Required().String()
 
+	// ssh: sign|revoke|krl
+
+	cmdSSH := app.Command("ssh", "SSH CA operations").
+		PreAction(cli.PopulateControl)
+
+	sshSignFlags := new(ssh.SignFlags)
+	sshSignCmd := cmdSSH.Command("sign", "sign SSH public key").
+		Action(cli.RegisterAction(ssh.Sign, sshSignFlags))
+	sshSignFlags.PublicKey = sshSignCmd.Flag("key", "SSH public key file").Required().String()
+	sshSignFlags.Profile = sshSignCmd.Flag("profile", "SSH certificate profile").Required().String()
+	sshSignFlags.KeyID = sshSignCmd.Flag("key-id", "Key ID of the certificate, must start with the caller's name followed by '/'").String()
+	sshSignFlags.Principals = sshSignCmd.Flag("principal", "requested principal").Strings()
+	sshSignFlags.Out = sshSignCmd.Flag("out", "output file name").String()
+
+	sshRevokeFlags := new(ssh.RevokeFlags)
+	sshRevokeCmd := cmdSSH.Command("revoke", "revoke SSH certificate").
+		Action(cli.RegisterAction(ssh.Revoke, sshRevokeFlags))
+	sshRevokeFlags.Ikid = sshRevokeCmd.Flag("ikid", "SHA256 fingerprint of SSH CA key").Required().String()
+	sshRevokeFlags.SerialNumber = sshRevokeCmd.Flag("serial", "serial number of the certificate").Required().String()
+	sshRevokeFlags.Reason = sshRevokeCmd.Flag("reason", "revocation reason").String()
+
+	sshKRLFlags := new(ssh.PublishKRLFlags)
+	sshKRLCmd := cmdSSH.Command("krl", "publish Key Revocation List").
+		Action(cli.RegisterAction(ssh.PublishKRL, sshKRLFlags))
+	sshKRLFlags.IssuerLabel = sshKRLCmd.Flag("issuer", "label of SSH issuer").String()
+	sshKRLFlags.Out = sshKRLCmd.Flag("out", "output file name").String()
+
+	// sa: list|create|delete|tokens|token
+
+	cmdSA := app.Command("sa", "service accounts for automation, the caller must be logged in").
+		PreAction(cli.PopulateControl)
+
+	saListFlags := new(sa.ListFlags)
+	saListCmd := cmdSA.Command("list", "show service accounts of the org").
+		Action(cli.RegisterAction(sa.List, saListFlags))
+	saListFlags.OrgID = saListCmd.Flag("org", "org ID").Required().String()
+
+	saCreateFlags := new(sa.CreateFlags)
+	saCreateCmd := cmdSA.Command("create", "create service account").
+		Action(cli.RegisterAction(sa.Create, saCreateFlags))
+	saCreateFlags.OrgID = saCreateCmd.Flag("org", "org ID").Required().String()
+	saCreateFlags.Name = saCreateCmd.Flag("name", "service account name").Required().String()
+	saCreateFlags.Description = saCreateCmd.Flag("description", "service account description").String()
+
+	saDeleteFlags := new(sa.DeleteFlags)
+	saDeleteCmd := cmdSA.Command("delete", "delete service account and its tokens").
+		Action(cli.RegisterAction(sa.Delete, saDeleteFlags))
+	saDeleteFlags.ID = saDeleteCmd.Flag("id", "service account ID").Required().String()
+
+	cmdToken := cmdSA.Command("token", "API tokens of service account")
+
+	tokensFlags := new(sa.TokensFlags)
+	tokensCmd := cmdToken.Command("list", "show API tokens of service account").
+		Action(cli.RegisterAction(sa.Tokens, tokensFlags))
+	tokensFlags.ServiceAccountID = tokensCmd.Flag("sa", "service account ID").Required().String()
+
+	createTokenFlags := new(sa.CreateTokenFlags)
+	createTokenCmd := cmdToken.Command("create", "create API token").
+		Action(cli.RegisterAction(sa.CreateToken, createTokenFlags))
+	createTokenFlags.ServiceAccountID = createTokenCmd.Flag("sa", "service account ID").Required().String()
+	createTokenFlags.Name = createTokenCmd.Flag("name", "token name").Required().String()
+	createTokenFlags.Expiry = createTokenCmd.Flag("expiry", "token lifetime, for example 720h").String()
+
+	revokeTokenFlags := new(sa.RevokeTokenFlags)
+	revokeTokenCmd := cmdToken.Command("revoke", "revoke API token").
+		Action(cli.RegisterAction(sa.RevokeToken, revokeTokenFlags))
+	revokeTokenFlags.ServiceAccountID = revokeTokenCmd.Flag("sa", "service account ID").Required().String()
+	revokeTokenFlags.ID = revokeTokenCmd.Flag("id", "token ID").Required().String()
+
+	// role: list|bind|unbind
+
+	cmdRole := app.Command("role", "role bindings, the caller must be an admin").
+		PreAction(cli.PopulateControl)
+
+	cmdRole.Command("list", "show role bindings").
+		Action(cli.RegisterAction(role.List, nil))
+
+	roleBindFlags := new(role.BindFlags)
+	roleBindCmd := cmdRole.Command("bind", "bind the role to the identity, or to the group of the identity provider").
+		Action(cli.RegisterAction(role.Bind, roleBindFlags))
+	roleBindFlags.Role = roleBindCmd.Flag("role", "role name").Required().String()
+	roleBindFlags.Subject = roleBindCmd.Flag("subject", "identity with type prefix: user:${email}, sa:${org}/${name}, spiffe:${spiffe_id}, or cn:${common_name}").String()
+	roleBindFlags.Group = roleBindCmd.Flag("group", "group of the identity provider, in ${provider}/${group} format").String()
+
+	roleUnbindFlags := new(role.UnbindFlags)
+	roleUnbindCmd := cmdRole.Command("unbind", "delete the role binding").
+		Action(cli.RegisterAction(role.Unbind, roleUnbindFlags))
+	roleUnbindFlags.ID = roleUnbindCmd.Flag("id", "role binding ID").Required().String()
+
+	// ra: requests|request|approve|deny
+
+	cmdRA := app.Command("ra", "RA operations").
+		PreAction(cli.PopulateControl)
+
+	raRequestsFlags := new(ra.ListRequestsFlags)
+	raRequestsCmd := cmdRA.Command("requests", "show the certificate requests in the approval queue").
+		Action(cli.RegisterAction(ra.ListRequests, raRequestsFlags))
+	raRequestsFlags.Status = raRequestsCmd.Flag("status", "status of the requests: pending|approved|denied|expired|issuing|issued|all").Default("pending").String()
+	raRequestsFlags.Limit = raRequestsCmd.Flag("limit", "max limit of the requests to print").Int()
+	raRequestsFlags.After = raRequestsCmd.Flag("after", "the request ID for pagination").Uint64()
+
+	raRequestFlags := new(ra.GetRequestFlags)
+	raRequestCmd := cmdRA.Command("request", "show the certificate request in the approval queue").
+		Action(cli.RegisterAction(ra.GetRequest, raRequestFlags))
+	raRequestFlags.ID = raRequestCmd.Flag("id", "request ID").Required().Uint64()
+	raRequestFlags.Wait = raRequestCmd.Flag("wait", "wait until the request is issued, denied or expired").Bool()
+	raRequestFlags.Out = raRequestCmd.Flag("out", "output file name for the issued certificate").String()
+
+	raApproveFlags := new(ra.DecisionFlags)
+	raApproveCmd := cmdRA.Command("approve", "approve the certificate request").
+		Action(cli.RegisterAction(ra.Approve, raApproveFlags))
+	raApproveFlags.ID = raApproveCmd.Flag("id", "request ID").Required().Uint64()
+	raApproveFlags.Reason = raApproveCmd.Flag("reason", "reason of the decision").String()
+
+	raDenyFlags := new(ra.DecisionFlags)
+	raDenyCmd := cmdRA.Command("deny", "deny the certificate request").
+		Action(cli.RegisterAction(ra.Deny, raDenyFlags))
+	raDenyFlags.ID = raDenyCmd.Flag("id", "request ID").Required().Uint64()
+	raDenyFlags.Reason = raDenyCmd.Flag("reason", "reason of the decision").Required().String()
+
 	// cis: roots
 
 	cmdCIS := app.Command("cis", "CIS operations").
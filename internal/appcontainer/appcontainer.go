@@ -38,7 +38,7 @@ type ProvideAuditorFn func(cfg *config.Configuration, r CloseRegistrator) (audit
 type ProvideSchedulerFn func() (tasks.Scheduler, error)
 
 // ProvideJwtFn defines JWT provider
-type ProvideJwtFn func(cfg *config.Configuration) (jwt.Parser, jwt.Provider, error)
+type ProvideJwtFn func(cfg *config.Configuration, crypto *cryptoprov.Crypto) (jwt.Parser, jwt.Provider, error)
 
 // ProvideOAuthClientsFn defines OAuth clients provider
 type ProvideOAuthClientsFn func(cfg *config.Configuration) (*oauth2client.Provider, error)
@@ -248,11 +248,11 @@ func provideAuditor(cfg *config.Configuration, r CloseRegistrator) (audit.Audito
 	return auditor, nil
 }
 
-func provideJwt(cfg *config.Configuration) (jwt.Parser, jwt.Provider, error) {
+func provideJwt(cfg *config.Configuration, crypto *cryptoprov.Crypto) (jwt.Parser, jwt.Provider, error) {
 	var provider jwt.Provider
 	var err error
 	if cfg.JWT != "" {
-		provider, err = jwt.Load(cfg.JWT)
+		provider, err = jwt.Load(cfg.JWT, jwt.WithCrypto(crypto))
 		if err != nil {
 			return nil, nil, errors.Trace(err)
 		}
@@ -19,6 +19,15 @@ type Authz struct {
 
 	// LogDenied specifies to log denied access
 	LogDenied bool `json:"log_denied" yaml:"log_denied"`
+
+	// Policy specifies location of the policy file with fine-grained permissions
+	// on gRPC methods, scoped by issuer, profile and org
+	Policy string `json:"policy" yaml:"policy"`
+
+	// RequestMetadataRoles specifies the roles allowed to view the request metadata
+	// of certificates of any org: requester, client IP, request ID and CSR.
+	// For other callers the metadata is returned only for certificates of their orgs.
+	RequestMetadataRoles []string `json:"request_metadata_roles" yaml:"request_metadata_roles"`
 }
 
 // IdentityMap contains configuration for the roles
@@ -27,6 +36,10 @@ type IdentityMap struct {
 	TLS TLSIdentityMap `json:"tls" yaml:"tls"`
 	// JWT identity map
 	JWT JWTIdentityMap `json:"jwt" yaml:"jwt"`
+	// APIToken identity map for service accounts
+	APIToken APITokenIdentityMap `json:"api_token" yaml:"api_token"`
+	// DynamicRoles provides roles resolved at the time of the request
+	DynamicRoles DynamicRolesMap `json:"dynamic_roles" yaml:"dynamic_roles"`
 }
 
 // TLSIdentityMap provides roles for TLS
@@ -47,6 +60,35 @@ type JWTIdentityMap struct {
 	Enabled bool `json:"enabled" yaml:"enabled"`
 	// Audience specifies the token audience
 	Audience string `json:"audience" yaml:"audience"`
+	// Sessions enables verification of the login session of JWT by `jti`,
+	// the tokens with revoked or unknown session are rejected.
+	// Before enabling, the `sessions` table must be created,
+	// and the tokens issued by the previous version must be refreshed
+	// by login, as they don't have a registered session.
+	Sessions bool `json:"sessions" yaml:"sessions"`
 	// Roles is a map of role to JWT identity
 	Roles map[string][]string `json:"roles" yaml:"roles"`
 }
+
+// APITokenIdentityMap provides roles for API tokens of service accounts
+type APITokenIdentityMap struct {
+	// DefaultAuthenticatedRole specifies role name for identity, if not found in maps
+	DefaultAuthenticatedRole string `json:"default_authenticated_role" yaml:"default_authenticated_role"`
+	// Enable API token identities
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Roles is a map of role to service account, in ${org}/${name} format
+	Roles map[string][]string `json:"roles" yaml:"roles"`
+}
+
+// DynamicRolesMap provides roles resolved at the time of the request,
+// in addition to the role from the identity map
+type DynamicRolesMap struct {
+	// Enable dynamic roles from org membership, IdP groups and role bindings
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// OrgRoles is a map of role to org membership, in ${org}/${role} format,
+	// where ${org} can be `*` to match the membership role in any org
+	OrgRoles map[string][]string `json:"org_roles" yaml:"org_roles"`
+	// GroupRoles is a map of role to group claims of the identity provider,
+	// in ${provider}/${group} format
+	GroupRoles map[string][]string `json:"group_roles" yaml:"group_roles"`
+}
@@ -170,6 +170,11 @@ func (f *Factory) LoadConfigForHostName(configFile, hostnameOverride string) (*C
 	for i := range c.OAuthClients {
 		filesToResove = append(filesToResove, &c.OAuthClients[i])
 	}
+	for _, srv := range c.HTTPServers {
+		if srv.Authz.Policy != "" {
+			filesToResove = append(filesToResove, &srv.Authz.Policy)
+		}
+	}
 	if c.RegistrationAuthority != nil {
 		for i := range c.RegistrationAuthority.PrivateRoots {
 			filesToResove = append(filesToResove, &c.RegistrationAuthority.PrivateRoots[i])
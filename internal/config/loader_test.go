@@ -61,6 +61,11 @@ func Test_LoadConfig(t *testing.T) {
 	require.NotNil(t, cis.CORS)
 	require.NotEmpty(t, cis.Swagger.Files)
 	testDirAbs("cis.swagger", cis.Swagger.Files["cis"])
+
+	ca := c.HTTPServers[CAServerName]
+	require.NotNil(t, ca)
+	require.NotEmpty(t, ca.Authz.Policy)
+	testDirAbs("ca.authz.policy", ca.Authz.Policy)
 }
 
 func TestParseListenURLs(t *testing.T) {
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/juju/errors"
+)
+
+// APITokenVerifier verifies API tokens of service accounts
+type APITokenVerifier struct {
+	db OrgsDb
+}
+
+// NewAPITokenVerifier returns APITokenVerifier
+func NewAPITokenVerifier(db OrgsDb) *APITokenVerifier {
+	return &APITokenVerifier{db: db}
+}
+
+// VerifyAPIToken returns the service account name in ${org}/${name} format,
+// and ID of the token
+func (v *APITokenVerifier) VerifyAPIToken(ctx context.Context, token string) (string, string, error) {
+	t, sa, err := v.db.UseAPIToken(ctx, model.HashAPIToken(token), time.Now().UTC())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", "", errors.New("invalid API token")
+		}
+		logger.Errorf("api=VerifyAPIToken, err=[%s]", errors.Details(err))
+		return "", "", errors.Trace(err)
+	}
+
+	org, err := v.db.GetOrg(ctx, sa.OrgID)
+	if err != nil {
+		logger.Errorf("api=VerifyAPIToken, org_id=%d, err=[%s]", sa.OrgID, errors.Details(err))
+		return "", "", errors.Trace(err)
+	}
+
+	return org.Login + "/" + sa.Name, strconv.FormatUint(t.ID, 10), nil
+}
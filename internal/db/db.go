@@ -45,13 +45,32 @@ type OrgsReadOnlyDb interface {
 	GetUserMemberships(ctx context.Context, userID uint64) ([]*model.OrgMemberInfo, error)
 	// GetUserOrgs returns list of orgs
 	GetUserOrgs(ctx context.Context, userID uint64) ([]*model.Organization, error)
+	// GetServiceAccount returns the service account
+	GetServiceAccount(ctx context.Context, id uint64) (*model.ServiceAccount, error)
+	// GetOrgServiceAccounts returns the service accounts of the org
+	GetOrgServiceAccounts(ctx context.Context, orgID uint64) ([]*model.ServiceAccount, error)
+	// GetServiceAccountTokens returns the tokens of the service account
+	GetServiceAccountTokens(ctx context.Context, serviceAccountID uint64) ([]*model.APIToken, error)
+	// GetSession returns the session
+	GetSession(ctx context.Context, id uint64) (*model.Session, error)
+	// GetUserSessions returns active sessions of the user
+	GetUserSessions(ctx context.Context, userID uint64) ([]*model.Session, error)
+	// GetRoleBindings returns all role bindings
+	GetRoleBindings(ctx context.Context) ([]*model.RoleBinding, error)
+	// GetSubjectsRoleBindings returns role bindings of the subjects
+	GetSubjectsRoleBindings(ctx context.Context, subjects []string) ([]*model.RoleBinding, error)
+	// GetUserOrgRoles returns roles of the user in orgs, in ${org}/${role} format,
+	// where ${org} is the login of the org
+	GetUserOrgRoles(ctx context.Context, userID uint64) ([]string, error)
 }
 
 // OrgsDb defines an interface for CRUD operations on Orgs
 type OrgsDb interface {
 	IDGenerator
 	OrgsReadOnlyDb
-	// LoginUser returns User
+	// LoginUser returns User,
+	// the users with Subject are identified by Provider and Subject,
+	// and by Email otherwise
 	LoginUser(ctx context.Context, user *model.User) (*model.User, error)
 	// UpdateOrg inserts or updates Organization
 	UpdateOrg(ctx context.Context, org *model.Organization) (*model.Organization, error)
@@ -68,6 +87,57 @@ type OrgsDb interface {
 	RemoveOrgMembers(ctx context.Context, orgID uint64, all bool) ([]*model.OrgMembership, error)
 	// RemoveOrgMember remove users from the org
 	RemoveOrgMember(ctx context.Context, orgID, memberID uint64) (*model.OrgMembership, error)
+
+	// CreateServiceAccount creates a service account for the org
+	CreateServiceAccount(ctx context.Context, sa *model.ServiceAccount) (*model.ServiceAccount, error)
+	// RemoveServiceAccount deletes the service account and its tokens
+	RemoveServiceAccount(ctx context.Context, id uint64) error
+	// CreateAPIToken creates API token for the service account
+	CreateAPIToken(ctx context.Context, token *model.APIToken) (*model.APIToken, error)
+	// RevokeAPIToken marks the token of the service account as revoked
+	RevokeAPIToken(ctx context.Context, serviceAccountID, id uint64, at time.Time) (*model.APIToken, error)
+	// UseAPIToken returns the active token by its hash and the service account,
+	// and updates the time the token was last used, if it's older than a minute
+	UseAPIToken(ctx context.Context, tokenHash string, at time.Time) (*model.APIToken, *model.ServiceAccount, error)
+
+	// CreateSession creates a login session of the user
+	CreateSession(ctx context.Context, session *model.Session) (*model.Session, error)
+	// RefreshSession extends expiration of the active session,
+	// up to model.MaxSessionLifetime since the session was issued.
+	// NotFound error is returned, if the session does not exist, expired or revoked.
+	RefreshSession(ctx context.Context, id uint64, expiresAt time.Time) (*model.Session, error)
+	// RevokeSession marks the session of the user as revoked.
+	// NotFound error is returned, if the session of the user does not exist.
+	RevokeSession(ctx context.Context, userID, id uint64, at time.Time) (*model.Session, error)
+	// RevokeUserSessions marks all active sessions of the user as revoked,
+	// and returns the revoked sessions
+	RevokeUserSessions(ctx context.Context, userID uint64, at time.Time) ([]*model.Session, error)
+	// UseSession returns the active session, and updates the time the session was last seen.
+	// NotFound error is returned, if the session does not exist, expired or revoked.
+	UseSession(ctx context.Context, id uint64, at time.Time) (*model.Session, error)
+
+	// CreateRoleBinding binds the role to the subject,
+	// the existing binding is returned if the role is already bound
+	CreateRoleBinding(ctx context.Context, b *model.RoleBinding) (*model.RoleBinding, error)
+	// RemoveRoleBinding deletes the role binding.
+	// NotFound error is returned, if the binding does not exist.
+	RemoveRoleBinding(ctx context.Context, id uint64) (*model.RoleBinding, error)
+
+	// CreateDeviceAuthorization creates a pending device authorization,
+	// and removes the expired ones
+	CreateDeviceAuthorization(ctx context.Context, d *model.DeviceAuthorization) (*model.DeviceAuthorization, error)
+	// GetDeviceAuthorization returns the pending device authorization by the user code.
+	// NotFound error is returned, if the authorization does not exist, expired or approved.
+	GetDeviceAuthorization(ctx context.Context, userCode string) (*model.DeviceAuthorization, error)
+	// ApproveDeviceAuthorization approves the pending device authorization with the session of the user
+	ApproveDeviceAuthorization(ctx context.Context, id, userID, sessionID uint64, at time.Time) (*model.DeviceAuthorization, error)
+	// PollDeviceAuthorization returns the device authorization by the hash of the device code,
+	// with the time of the previous poll, and updates the time of the poll.
+	// NotFound error is returned, if the authorization does not exist.
+	PollDeviceAuthorization(ctx context.Context, deviceCodeHash string, at time.Time) (*model.DeviceAuthorization, error)
+	// RemoveDeviceAuthorization deletes the device authorization.
+	// NotFound error is returned, if the authorization does not exist.
+	RemoveDeviceAuthorization(ctx context.Context, id uint64) error
 }
 
 // CertsReadonlyDb defines an interface for Read operations on Certs
@@ -84,10 +154,14 @@ type CertsReadonlyDb interface {
 	GetOrgRevokedCertificates(ctx context.Context, orgID uint64) (model.RevokedCertificates, error)
 	// GetCrl returns CRL by a specified issuer
 	GetCrl(ctx context.Context, ikid string) (*model.Crl, error)
-	// ListRevokedCertificates returns revoked certificates info by a specified issuer
-	ListRevokedCertificates(ctx context.Context, ikid string, limit int, afterID uint64) (model.RevokedCertificates, error)
-	// ListCertificates returns list of Certificate info
-	ListCertificates(ctx context.Context, ikid string, limit int, afterID uint64) (model.Certificates, error)
+	// ListRevokedCertificates returns revoked certificates info by a specified issuer and/or label selector
+	ListRevokedCertificates(ctx context.Context, ikid string, selector model.LabelSelector, limit int, afterID uint64) (model.RevokedCertificates, error)
+	// ListCertificates returns list of Certificate info by a specified issuer and/or label selector
+	ListCertificates(ctx context.Context, ikid string, selector model.LabelSelector, limit int, afterID uint64) (model.Certificates, error)
+	// ListRevokedSSHCertificates returns revoked SSH certificates info by a specified issuer
+	ListRevokedSSHCertificates(ctx context.Context, ikid string, limit int, afterID uint64) (model.SSHCertificates, error)
+	// GetApproval returns the certificate request in the approval queue, with the votes
+	GetApproval(ctx context.Context, id uint64) (*model.Approval, error)
 }
 
 // CertsDb defines an interface for CRUD operations on Certs
@@ -103,6 +177,9 @@ type CertsDb interface {
 	RegisterCertificate(ctx context.Context, crt *model.Certificate) (*model.Certificate, error)
 	// RemoveCertificate removes Certificate
 	RemoveCertificate(ctx context.Context, id uint64) error
+	// UpdateCertificateLabels sets and removes the labels of the certificate,
+	// NotFound error is returned if the certificate does not exist
+	UpdateCertificateLabels(ctx context.Context, id uint64, set model.Labels, remove []string) (*model.Certificate, error)
 
 	// RegisterRevokedCertificate registers revoked Certificate
 	RegisterRevokedCertificate(ctx context.Context, revoked *model.RevokedCertificate) (*model.RevokedCertificate, error)
@@ -115,6 +192,45 @@ type CertsDb interface {
 	RegisterCrl(ctx context.Context, crt *model.Crl) (*model.Crl, error)
 	// RemoveCrl removes CRL
 	RemoveCrl(ctx context.Context, id uint64) error
+
+	// RegisterSSHCertificate registers SSH Certificate
+	RegisterSSHCertificate(ctx context.Context, crt *model.SSHCertificate) (*model.SSHCertificate, error)
+	// RevokeSSHCertificate marks SSH Certificate as revoked
+	RevokeSSHCertificate(ctx context.Context, ikid, serialNumber string, at time.Time, reason int) (*model.SSHCertificate, error)
+	// RemoveSSHCertificate removes SSH Certificate
+	RemoveSSHCertificate(ctx context.Context, id uint64) error
+	// NextSSHKRLVersion returns the version of the next KRL published by the issuer,
+	// the version increases with each call, and starts with the Unix time of the first call
+	NextSSHKRLVersion(ctx context.Context, ikid string, at time.Time) (uint64, error)
+
+	// CreateApproval creates a pending certificate request in the approval queue
+	CreateApproval(ctx context.Context, a *model.Approval) (*model.Approval, error)
+	// ListApprovals returns the certificate requests in the approval queue
+	// with the specified status, or with any status if the status is empty.
+	// The pending requests are marked as expired by ExpireApprovals.
+	ListApprovals(ctx context.Context, status string, limit int, afterID uint64) (model.Approvals, error)
+	// ExpireApprovals marks the pending requests as expired, after their expiration time,
+	// and returns the number of expired requests
+	ExpireApprovals(ctx context.Context, at time.Time) (int, error)
+	// VoteApproval adds the vote of the approver to the pending request.
+	// The request is approved when the required approvals are received,
+	// or denied by the first vote against it.
+	// NotValid error is returned, if the request is not pending or expired,
+	// and AlreadyExists error, if the approver already voted.
+	VoteApproval(ctx context.Context, vote *model.ApprovalVote, at time.Time) (*model.Approval, error)
+	// ClaimApproval marks the approved request as issuing,
+	// so only one of the concurrent callers issues the certificate.
+	// NotFound error is returned, if the request does not exist or not approved.
+	ClaimApproval(ctx context.Context, id uint64) (*model.Approval, error)
+	// ReleaseApproval marks the issuing request as approved,
+	// after the certificate failed to be issued.
+	// NotFound error is returned, if the request does not exist or not issuing.
+	ReleaseApproval(ctx context.Context, id uint64) (*model.Approval, error)
+	// IssueApproval marks the issuing request as issued with the certificate.
+	// NotFound error is returned, if the request does not exist or not issuing.
+	IssueApproval(ctx context.Context, id, certificateID uint64) (*model.Approval, error)
+	// RemoveApproval deletes the request and its votes
+	RemoveApproval(ctx context.Context, id uint64) error
 }
 
 // Provider provides complete DB access
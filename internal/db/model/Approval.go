@@ -0,0 +1,179 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ekspand/trusty/api/v1/pb"
+	"github.com/juju/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Approval statuses
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusDenied   = "denied"
+	ApprovalStatusExpired  = "expired"
+	ApprovalStatusIssuing  = "issuing"
+	ApprovalStatusIssued   = "issued"
+)
+
+// Approval represents a certificate request waiting for manual approval
+type Approval struct {
+	ID          uint64   `db:"id"`
+	OrgID       uint64   `db:"org_id"`
+	Profile     string   `db:"profile"`
+	IssuerLabel string   `db:"issuer_label"`
+	Subject     string   `db:"subject"`
+	SAN         []string `db:"san"`
+	// Request provides PEM encoded CSR
+	Request string `db:"request"`
+	// Requester is the name of the caller who submitted the request
+	Requester string `db:"requester"`
+	// ClientIP is the IP address of the requester
+	ClientIP string `db:"client_ip"`
+	// RequestID is the correlation ID of the submitted request
+	RequestID string `db:"request_id"`
+	Labels    Labels `db:"labels"`
+	Status    string `db:"status"`
+	// RequiredApprovals specifies M in M-of-N approvals
+	RequiredApprovals int `db:"required_approvals"`
+	// ApproverRoles specifies the roles allowed to approve the request
+	ApproverRoles []string  `db:"approver_roles"`
+	CreatedAt     time.Time `db:"created_at"`
+	ExpiresAt     time.Time `db:"expires_at"`
+	// CertificateID is set when the certificate is issued
+	CertificateID uint64 `db:"certificate_id"`
+
+	Votes []*ApprovalVote `db:"-"`
+}
+
+// ApprovalVote represents a decision of the approver
+type ApprovalVote struct {
+	ApprovalID uint64    `db:"approval_id"`
+	Approver   string    `db:"approver"`
+	Approved   bool      `db:"approved"`
+	Reason     string    `db:"reason"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// Approvals defines a list of Approval
+type Approvals []*Approval
+
+// Validate returns error if the model is not valid
+func (a *Approval) Validate() error {
+	if a.Profile == "" || len(a.Profile) > 32 {
+		return errors.Errorf("invalid profile: %q", a.Profile)
+	}
+	if a.IssuerLabel == "" || len(a.IssuerLabel) > MaxLenForName {
+		return errors.Errorf("invalid issuer label: %q", a.IssuerLabel)
+	}
+	if len(a.Subject) > 256 {
+		return errors.Errorf("invalid subject: %q", a.Subject)
+	}
+	if a.Request == "" {
+		return errors.New("missing request")
+	}
+	if len(a.Requester) > MaxLenForEmail {
+		return errors.Errorf("invalid requester: %q", a.Requester)
+	}
+	if len(a.ClientIP) > 64 {
+		return errors.Errorf("invalid client IP: %q", a.ClientIP)
+	}
+	if len(a.RequestID) > 64 {
+		return errors.Errorf("invalid request ID: %q", a.RequestID)
+	}
+	if err := a.Labels.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if a.RequiredApprovals < 1 {
+		return errors.New("at least one approval must be required")
+	}
+	if len(a.ApproverRoles) == 0 {
+		return errors.New("missing approver roles")
+	}
+	if a.ExpiresAt.IsZero() {
+		return errors.New("missing expiration")
+	}
+	return nil
+}
+
+// Validate returns error if the model is not valid
+func (v *ApprovalVote) Validate() error {
+	if v.Approver == "" || len(v.Approver) > MaxLenForEmail {
+		return errors.Errorf("invalid approver: %q", v.Approver)
+	}
+	if len(v.Reason) > 256 {
+		return errors.New("reason is too long")
+	}
+	return nil
+}
+
+// IsExpired returns true if the pending request is expired at the specified time
+func (a *Approval) IsExpired(at time.Time) bool {
+	return a.Status == ApprovalStatusPending && !at.Before(a.ExpiresAt)
+}
+
+// Approvals returns the number of received approvals
+func (a *Approval) Approvals() int {
+	count := 0
+	for _, v := range a.Votes {
+		if v.Approved {
+			count++
+		}
+	}
+	return count
+}
+
+// Vote returns the vote of the approver, or nil if the approver did not vote
+func (a *Approval) Vote(approver string) *ApprovalVote {
+	for _, v := range a.Votes {
+		if strings.EqualFold(v.Approver, approver) {
+			return v
+		}
+	}
+	return nil
+}
+
+// ToDTO returns DTO
+func (a *Approval) ToDTO() *pb.Approval {
+	dto := &pb.Approval{
+		Id:                a.ID,
+		OrgId:             a.OrgID,
+		Profile:           a.Profile,
+		IssuerLabel:       a.IssuerLabel,
+		Subject:           a.Subject,
+		San:               a.SAN,
+		Request:           a.Request,
+		Requester:         a.Requester,
+		Status:            pb.ApprovalStatus(pb.ApprovalStatus_value[strings.ToUpper(a.Status)]),
+		RequiredApprovals: uint32(a.RequiredApprovals),
+		ApproverRoles:     a.ApproverRoles,
+		CreatedAt:         timestamppb.New(a.CreatedAt),
+		ExpiresAt:         timestamppb.New(a.ExpiresAt),
+	}
+	for _, v := range a.Votes {
+		dto.Votes = append(dto.Votes, &pb.ApprovalVote{
+			Approver:  v.Approver,
+			Approved:  v.Approved,
+			Reason:    v.Reason,
+			CreatedAt: timestamppb.New(v.CreatedAt),
+		})
+	}
+	return dto
+}
+
+// ToDTO returns DTO
+func (list Approvals) ToDTO() []*pb.Approval {
+	dto := make([]*pb.Approval, len(list))
+	for i, a := range list {
+		dto[i] = a.ToDTO()
+	}
+	return dto
+}
+
+// ApprovalStatus returns the status of the approval from DTO
+func ApprovalStatus(status pb.ApprovalStatus) string {
+	return strings.ToLower(status.String())
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/ekspand/trusty/api/v1/pb"
 	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/juju/errors"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -24,11 +25,36 @@ type Certificate struct {
 	Profile          string    `db:"profile"`
 	Pem              string    `db:"pem"`
 	IssuersPem       string    `db:"issuers_pem"`
+	// Requester is the name of the caller who requested the certificate
+	Requester string `db:"requester"`
+	// ClientIP is the IP address of the requester
+	ClientIP string `db:"client_ip"`
+	// RequestID is the correlation ID of the request
+	RequestID string `db:"request_id"`
+	// Request provides PEM encoded CSR
+	Request string `db:"request"`
+	// SAN provides Subject Alternative Names requested to override the CSR
+	SAN    []string `db:"san"`
+	Labels Labels   `db:"labels"`
 }
 
 // Certificates defines a list of Certificate
 type Certificates []*Certificate
 
+// Validate returns error if the model is not valid
+func (r *Certificate) Validate() error {
+	if len(r.Requester) > MaxLenForEmail {
+		return errors.Errorf("invalid requester: %q", r.Requester)
+	}
+	if len(r.ClientIP) > 64 {
+		return errors.Errorf("invalid client IP: %q", r.ClientIP)
+	}
+	if len(r.RequestID) > 64 {
+		return errors.Errorf("invalid request ID: %q", r.RequestID)
+	}
+	return errors.Trace(r.Labels.Validate())
+}
+
 // ToDTO returns DTO
 func (r *Certificate) ToDTO() *pb.Certificate {
 	return &pb.Certificate{
@@ -45,6 +71,12 @@ func (r *Certificate) ToDTO() *pb.Certificate {
 		Profile:      r.Profile,
 		Pem:          r.Pem,
 		IssuersPem:   r.IssuersPem,
+		Requester:    r.Requester,
+		ClientIp:     r.ClientIP,
+		RequestId:    r.RequestID,
+		Request:      r.Request,
+		San:          r.SAN,
+		Labels:       r.Labels,
 	}
 }
 
@@ -64,6 +96,12 @@ func CertificateFromPB(r *pb.Certificate) *Certificate {
 		Profile:          r.Profile,
 		Pem:              r.Pem,
 		IssuersPem:       r.IssuersPem,
+		Requester:        r.Requester,
+		ClientIP:         r.ClientIp,
+		RequestID:        r.RequestId,
+		Request:          r.Request,
+		SAN:              r.San,
+		Labels:           r.Labels,
 	}
 }
 
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// userCodeCharset excludes vowels and similar looking characters,
+// as recommended by RFC 8628
+const userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+
+// DeviceAuthorization represents a pending device authorization,
+// only SHA256 hash of the device code is stored
+type DeviceAuthorization struct {
+	ID         uint64    `db:"id"`
+	DeviceCode string    `db:"device_code"`
+	UserCode   string    `db:"user_code"`
+	Provider   string    `db:"provider"`
+	DeviceID   string    `db:"device_id"`
+	CreatedAt  time.Time `db:"created_at"`
+	ExpiresAt  time.Time `db:"expires_at"`
+	// UserID and SessionID are set when the authorization is approved
+	UserID     uint64       `db:"user_id"`
+	SessionID  uint64       `db:"session_id"`
+	ApprovedAt sql.NullTime `db:"approved_at"`
+	PolledAt   sql.NullTime `db:"polled_at"`
+}
+
+// Validate returns error if the model is not valid
+func (d *DeviceAuthorization) Validate() error {
+	if len(d.DeviceCode) != 64 {
+		return errors.New("invalid device code hash")
+	}
+	if len(d.UserCode) != 9 {
+		return errors.New("invalid user code")
+	}
+	if d.Provider == "" || len(d.Provider) > MaxLenForName {
+		return errors.New("invalid provider")
+	}
+	if d.DeviceID == "" || len(d.DeviceID) > MaxLenForName {
+		return errors.New("invalid device ID")
+	}
+	if d.ExpiresAt.IsZero() {
+		return errors.New("missing expiration")
+	}
+	return nil
+}
+
+// IsApproved returns true if the authorization is approved by the user
+func (d *DeviceAuthorization) IsApproved() bool {
+	return d.ApprovedAt.Valid && d.SessionID != 0
+}
+
+// IsExpired returns true if the authorization is expired at the specified time
+func (d *DeviceAuthorization) IsExpired(at time.Time) bool {
+	return !at.Before(d.ExpiresAt)
+}
+
+// NewDeviceCode returns a new random device code
+func NewDeviceCode() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", errors.Trace(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+// HashDeviceCode returns hex encoded SHA256 hash of the device code
+func HashDeviceCode(code string) string {
+	h := sha256.Sum256([]byte(strings.TrimSpace(code)))
+	return hex.EncodeToString(h[:])
+}
+
+// NewUserCode returns a new random user code in XXXX-XXXX format
+func NewUserCode() (string, error) {
+	max := big.NewInt(int64(len(userCodeCharset)))
+	var b strings.Builder
+	for i := 0; i < 8; i++ {
+		if i == 4 {
+			b.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		b.WriteByte(userCodeCharset[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// NormalizeUserCode returns the user code as entered by the user,
+// in XXXX-XXXX format
+func NormalizeUserCode(code string) string {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) != 8 {
+		return code
+	}
+	return code[:4] + "-" + code[4:]
+}
@@ -0,0 +1,197 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Labels provides key/value labels, stored as JSON
+type Labels map[string]string
+
+// Value implements driver.Valuer
+func (l Labels) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (l *Labels) Scan(src interface{}) error {
+	*l = nil
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.Errorf("unsupported labels type: %T", src)
+	}
+	if len(b) == 0 {
+		return nil
+	}
+	return errors.Trace(json.Unmarshal(b, l))
+}
+
+// MaxLabels specifies the maximum number of labels
+const MaxLabels = 64
+
+var labelKeyRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9._/-]{0,61}[a-z0-9])?$`)
+
+// Validate returns error if the labels are not valid
+func (l Labels) Validate() error {
+	if len(l) > MaxLabels {
+		return errors.Errorf("too many labels: %d", len(l))
+	}
+	for k, v := range l {
+		if err := ValidateLabelKey(k); err != nil {
+			return err
+		}
+		if len(v) > MaxLenForName {
+			return errors.Errorf("invalid label value: %q", v)
+		}
+	}
+	return nil
+}
+
+// ValidateLabelKey returns error if the label key is not valid
+func ValidateLabelKey(key string) error {
+	if !labelKeyRegex.MatchString(key) {
+		return errors.Errorf("invalid label key: %q", key)
+	}
+	return nil
+}
+
+// Update returns a copy of the labels with the specified labels set,
+// and the specified keys removed
+func (l Labels) Update(set Labels, remove []string) Labels {
+	res := Labels{}
+	for k, v := range l {
+		res[k] = v
+	}
+	for k, v := range set {
+		res[k] = v
+	}
+	for _, k := range remove {
+		delete(res, k)
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
+
+// String returns comma separated list of key=value, sorted by key
+func (l Labels) String() string {
+	list := make([]string, 0, len(l))
+	for k, v := range l {
+		list = append(list, k+"="+v)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+// Label selector operators
+const (
+	LabelEquals    = "="
+	LabelNotEquals = "!="
+	LabelExists    = "exists"
+	LabelNotExists = "!"
+)
+
+// LabelRequirement specifies a requirement for a label
+type LabelRequirement struct {
+	Key      string
+	Operator string
+	Value    string
+}
+
+// LabelSelector specifies a list of requirements,
+// all of them must be satisfied
+type LabelSelector []LabelRequirement
+
+// ParseLabelSelector parses comma separated list of requirements:
+// key=value, key==value, key!=value, key, !key
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	var res LabelSelector
+	for _, s := range strings.Split(selector, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		var r LabelRequirement
+		switch {
+		case strings.HasPrefix(s, "!") && !strings.Contains(s, "="):
+			r.Key, r.Operator = strings.TrimSpace(s[1:]), LabelNotExists
+		case strings.Contains(s, "!="):
+			parts := strings.SplitN(s, "!=", 2)
+			r.Key, r.Operator, r.Value = strings.TrimSpace(parts[0]), LabelNotEquals, strings.TrimSpace(parts[1])
+		case strings.Contains(s, "="):
+			parts := strings.SplitN(s, "=", 2)
+			r.Key, r.Operator, r.Value = strings.TrimSpace(parts[0]), LabelEquals, strings.TrimSpace(strings.TrimPrefix(parts[1], "="))
+		default:
+			r.Key, r.Operator = s, LabelExists
+		}
+
+		if err := ValidateLabelKey(r.Key); err != nil {
+			return nil, errors.Annotatef(err, "invalid selector %q", s)
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// Matches returns true if the labels satisfy all requirements
+func (s LabelSelector) Matches(l Labels) bool {
+	for _, r := range s {
+		v, ok := l[r.Key]
+		switch r.Operator {
+		case LabelEquals:
+			if !ok || v != r.Value {
+				return false
+			}
+		case LabelNotEquals:
+			if ok && v == r.Value {
+				return false
+			}
+		case LabelExists:
+			if !ok {
+				return false
+			}
+		case LabelNotExists:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String returns the selector in the parsed format
+func (s LabelSelector) String() string {
+	list := make([]string, len(s))
+	for i, r := range s {
+		switch r.Operator {
+		case LabelExists:
+			list[i] = r.Key
+		case LabelNotExists:
+			list[i] = "!" + r.Key
+		default:
+			list[i] = r.Key + r.Operator + r.Value
+		}
+	}
+	return strings.Join(list, ",")
+}
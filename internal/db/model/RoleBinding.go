@@ -0,0 +1,79 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/juju/errors"
+)
+
+// RoleBinding binds the role to the subject,
+// which is prefixed with its type, see v1.RoleBindingPrefixes
+type RoleBinding struct {
+	ID        uint64    `db:"id"`
+	Role      string    `db:"role"`
+	Subject   string    `db:"subject"`
+	CreatedBy uint64    `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Validate returns error if the model is not valid
+func (b *RoleBinding) Validate() error {
+	if len(b.Role) > MaxLenForName || !nameRegex.MatchString(b.Role) {
+		return errors.Errorf("invalid role: %q", b.Role)
+	}
+	prefix := subjectPrefix(b.Subject)
+	subject := strings.TrimPrefix(b.Subject, prefix)
+	if strings.TrimSpace(subject) == "" {
+		return errors.New("missing subject")
+	}
+	if len(b.Subject) > MaxLenForShortURL {
+		return errors.New("subject is too long")
+	}
+	if prefix == "" {
+		return errors.Errorf("subject must have one of prefixes: %s",
+			strings.Join(v1.RoleBindingPrefixes, ", "))
+	}
+	if prefix == v1.RoleBindingGroupPrefix {
+		if i := strings.Index(subject, "/"); i <= 0 || i == len(subject)-1 {
+			return errors.New("group must be in ${provider}/${group} format")
+		}
+	}
+	return nil
+}
+
+// subjectPrefix returns the type prefix of the subject,
+// or empty string if the prefix is not supported
+func subjectPrefix(subject string) string {
+	for _, prefix := range v1.RoleBindingPrefixes {
+		if strings.HasPrefix(subject, prefix) {
+			return prefix
+		}
+	}
+	return ""
+}
+
+// ToDto converts model to v1.RoleBinding DTO
+func (b *RoleBinding) ToDto() *v1.RoleBinding {
+	res := &v1.RoleBinding{
+		ID:        strconv.FormatUint(b.ID, 10),
+		Role:      b.Role,
+		Subject:   b.Subject,
+		CreatedAt: b.CreatedAt,
+	}
+	if b.CreatedBy != 0 {
+		res.CreatedBy = strconv.FormatUint(b.CreatedBy, 10)
+	}
+	return res
+}
+
+// ToRoleBindingsDto returns RoleBindings
+func ToRoleBindingsDto(list []*RoleBinding) []*v1.RoleBinding {
+	res := make([]*v1.RoleBinding, len(list))
+	for i, b := range list {
+		res[i] = b.ToDto()
+	}
+	return res
+}
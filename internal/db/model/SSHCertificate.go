@@ -0,0 +1,102 @@
+package model
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/ekspand/trusty/api/v1/pb"
+	"github.com/juju/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SSHCertificate provides SSH Cert information
+type SSHCertificate struct {
+	ID               uint64       `db:"id"`
+	OrgID            uint64       `db:"org_id"`
+	IKID             string       `db:"ikid"`
+	SerialNumber     string       `db:"serial_number"`
+	KeyID            string       `db:"key_id"`
+	CertType         string       `db:"cert_type"`
+	Principals       []string     `db:"principals"`
+	NotBefore        time.Time    `db:"not_before"`
+	NotAfter         time.Time    `db:"no_tafter"`
+	ThumbprintSha256 string       `db:"sha256"`
+	Profile          string       `db:"profile"`
+	Pem              string       `db:"pem"`
+	RevokedAt        sql.NullTime `db:"revoked_at"`
+	Reason           int          `db:"reason"`
+}
+
+// SSHCertificates defines a list of SSHCertificate
+type SSHCertificates []*SSHCertificate
+
+// Validate returns error if the model is not valid
+func (r *SSHCertificate) Validate() error {
+	if r.IKID == "" || len(r.IKID) > 64 {
+		return errors.Errorf("invalid ikid: %q", r.IKID)
+	}
+	if r.SerialNumber == "" || len(r.SerialNumber) > 64 {
+		return errors.Errorf("invalid serial number: %q", r.SerialNumber)
+	}
+	if len(r.KeyID) > 260 {
+		return errors.Errorf("invalid key ID: %q", r.KeyID)
+	}
+	if r.ThumbprintSha256 == "" || len(r.ThumbprintSha256) > 64 {
+		return errors.Errorf("invalid sha256: %q", r.ThumbprintSha256)
+	}
+	if r.Pem == "" {
+		return errors.New("invalid certificate")
+	}
+	return nil
+}
+
+// IsRevoked returns true if the certificate is revoked
+func (r *SSHCertificate) IsRevoked() bool {
+	return r.RevokedAt.Valid
+}
+
+// PrincipalsString returns comma separated list of principals
+func (r *SSHCertificate) PrincipalsString() string {
+	return strings.Join(r.Principals, ",")
+}
+
+// SetPrincipals sets principals from comma separated list
+func (r *SSHCertificate) SetPrincipals(val string) {
+	r.Principals = nil
+	if val != "" {
+		r.Principals = strings.Split(val, ",")
+	}
+}
+
+// ToDTO returns DTO
+func (r *SSHCertificate) ToDTO() *pb.SSHCertificate {
+	dto := &pb.SSHCertificate{
+		Id:           r.ID,
+		OrgId:        r.OrgID,
+		Ikid:         r.IKID,
+		SerialNumber: r.SerialNumber,
+		KeyId:        r.KeyID,
+		CertType:     r.CertType,
+		Principals:   r.Principals,
+		NotBefore:    timestamppb.New(r.NotBefore),
+		NotAfter:     timestamppb.New(r.NotAfter),
+		Sha256:       r.ThumbprintSha256,
+		Profile:      r.Profile,
+		Certificate:  r.Pem,
+		Reason:       pb.Reason(r.Reason),
+	}
+	if r.RevokedAt.Valid {
+		dto.RevokedAt = timestamppb.New(r.RevokedAt.Time)
+	}
+	return dto
+}
+
+// ToDTO returns DTO
+func (list SSHCertificates) ToDTO() []*pb.SSHCertificate {
+	dto := make([]*pb.SSHCertificate, len(list))
+	for i, r := range list {
+		dto[i] = r.ToDTO()
+	}
+	return dto
+}
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/juju/errors"
+)
+
+var nameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ServiceAccount represents an org-scoped account for automation
+type ServiceAccount struct {
+	ID          uint64    `db:"id"`
+	OrgID       uint64    `db:"org_id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedBy   uint64    `db:"created_by"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// Validate returns error if the model is not valid
+func (s *ServiceAccount) Validate() error {
+	if s.OrgID == 0 {
+		return errors.New("missing org ID")
+	}
+	if len(s.Name) > MaxLenForName || !nameRegex.MatchString(s.Name) {
+		return errors.Errorf("invalid name: %q", s.Name)
+	}
+	if len(s.Description) > MaxLenForShortURL {
+		return errors.New("description is too long")
+	}
+	return nil
+}
+
+// ToDto converts model to v1.ServiceAccount DTO
+func (s *ServiceAccount) ToDto() *v1.ServiceAccount {
+	return &v1.ServiceAccount{
+		ID:          strconv.FormatUint(s.ID, 10),
+		OrgID:       strconv.FormatUint(s.OrgID, 10),
+		Name:        s.Name,
+		Description: s.Description,
+		CreatedBy:   strconv.FormatUint(s.CreatedBy, 10),
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+// ToServiceAccountsDto returns ServiceAccounts
+func ToServiceAccountsDto(list []*ServiceAccount) []*v1.ServiceAccount {
+	res := make([]*v1.ServiceAccount, len(list))
+	for i, sa := range list {
+		res[i] = sa.ToDto()
+	}
+	return res
+}
+
+// APIToken represents a named token of the service account,
+// only SHA256 hash of the token is stored
+type APIToken struct {
+	ID               uint64       `db:"id"`
+	ServiceAccountID uint64       `db:"service_account_id"`
+	Name             string       `db:"name"`
+	TokenHash        string       `db:"token_hash"`
+	CreatedBy        uint64       `db:"created_by"`
+	CreatedAt        time.Time    `db:"created_at"`
+	ExpiresAt        sql.NullTime `db:"expires_at"`
+	LastUsedAt       sql.NullTime `db:"last_used_at"`
+	RevokedAt        sql.NullTime `db:"revoked_at"`
+}
+
+// Validate returns error if the model is not valid
+func (t *APIToken) Validate() error {
+	if t.ServiceAccountID == 0 {
+		return errors.New("missing service account ID")
+	}
+	if len(t.Name) > MaxLenForName || !nameRegex.MatchString(t.Name) {
+		return errors.Errorf("invalid name: %q", t.Name)
+	}
+	if len(t.TokenHash) != 64 {
+		return errors.New("invalid token hash")
+	}
+	return nil
+}
+
+// IsRevoked returns true if the token is revoked
+func (t *APIToken) IsRevoked() bool {
+	return t.RevokedAt.Valid
+}
+
+// IsExpired returns true if the token is expired at the specified time
+func (t *APIToken) IsExpired(at time.Time) bool {
+	return t.ExpiresAt.Valid && !at.Before(t.ExpiresAt.Time)
+}
+
+// ToDto converts model to v1.APIToken DTO
+func (t *APIToken) ToDto() *v1.APIToken {
+	return &v1.APIToken{
+		ID:               strconv.FormatUint(t.ID, 10),
+		ServiceAccountID: strconv.FormatUint(t.ServiceAccountID, 10),
+		Name:             t.Name,
+		CreatedBy:        strconv.FormatUint(t.CreatedBy, 10),
+		CreatedAt:        t.CreatedAt,
+		ExpiresAt:        timePtr(t.ExpiresAt),
+		LastUsedAt:       timePtr(t.LastUsedAt),
+		RevokedAt:        timePtr(t.RevokedAt),
+	}
+}
+
+// ToAPITokensDto returns APITokens
+func ToAPITokensDto(list []*APIToken) []*v1.APIToken {
+	res := make([]*v1.APIToken, len(list))
+	for i, t := range list {
+		res[i] = t.ToDto()
+	}
+	return res
+}
+
+// NewAPITokenSecret returns a new random API token value
+func NewAPITokenSecret() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", errors.Trace(err)
+	}
+	return v1.APITokenPrefix + base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+// HashAPIToken returns hex encoded SHA256 hash of the API token value
+func HashAPIToken(secret string) string {
+	h := sha256.Sum256([]byte(strings.TrimSpace(secret)))
+	return hex.EncodeToString(h[:])
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
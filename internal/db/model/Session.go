@@ -0,0 +1,70 @@
+package model
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/juju/errors"
+)
+
+// MaxSessionLifetime specifies the maximum lifetime of the session since login,
+// the session can not be refreshed after that
+const MaxSessionLifetime = 30 * 24 * time.Hour
+
+// Session represents a login session of the user on a device,
+// the ID of the session is used as `jti` claim of issued access tokens
+type Session struct {
+	ID         uint64       `db:"id"`
+	UserID     uint64       `db:"user_id"`
+	DeviceID   string       `db:"device_id"`
+	IssuedAt   time.Time    `db:"issued_at"`
+	ExpiresAt  time.Time    `db:"expires_at"`
+	LastSeenAt sql.NullTime `db:"last_seen_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at"`
+	// Groups of the identity provider, asserted at login
+	Groups []string `db:"groups"`
+}
+
+// Validate returns error if the model is not valid
+func (s *Session) Validate() error {
+	if s.UserID == 0 {
+		return errors.New("missing user ID")
+	}
+	if len(s.DeviceID) > MaxLenForName {
+		return errors.New("device ID is too long")
+	}
+	if s.ExpiresAt.IsZero() {
+		return errors.New("missing expiration")
+	}
+	return nil
+}
+
+// IsActive returns true if the session is not revoked and not expired at the specified time
+func (s *Session) IsActive(at time.Time) bool {
+	return !s.RevokedAt.Valid && at.Before(s.ExpiresAt)
+}
+
+// ToDto converts model to v1.Session DTO
+func (s *Session) ToDto() *v1.Session {
+	return &v1.Session{
+		ID:         strconv.FormatUint(s.ID, 10),
+		UserID:     strconv.FormatUint(s.UserID, 10),
+		DeviceID:   s.DeviceID,
+		IssuedAt:   s.IssuedAt,
+		ExpiresAt:  s.ExpiresAt,
+		LastSeenAt: timePtr(s.LastSeenAt),
+		RevokedAt:  timePtr(s.RevokedAt),
+		Groups:     s.Groups,
+	}
+}
+
+// ToSessionsDto returns Sessions
+func ToSessionsDto(list []*Session) []*v1.Session {
+	res := make([]*v1.Session, len(list))
+	for i, s := range list {
+		res[i] = s.ToDto()
+	}
+	return res
+}
@@ -13,6 +13,7 @@ type User struct {
 	ID             uint64       `db:"id"`
 	ExternalID     uint64       `db:"extern_id"`
 	Provider       string       `db:"provider"`
+	Subject        string       `db:"subject"`
 	Login          string       `db:"login"`
 	Name           string       `db:"name"`
 	Email          string       `db:"email"`
@@ -64,6 +65,9 @@ func (u *User) Validate() error {
 	if len(u.Company) > MaxLenForName {
 		return errors.Errorf("invalid company: %q", u.Company)
 	}
+	if len(u.Subject) > MaxLenForShortURL {
+		return errors.Errorf("invalid subject: %q", u.Subject)
+	}
 	if len(u.AvatarURL) > MaxLenForShortURL {
 		return errors.Errorf("invalid avatar: %q", u.AvatarURL)
 	}
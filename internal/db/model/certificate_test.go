@@ -1,9 +1,11 @@
 package model_test
 
 import (
+	"database/sql"
 	"testing"
 	"time"
 
+	"github.com/ekspand/trusty/api/v1/pb"
 	"github.com/ekspand/trusty/internal/db/model"
 	"github.com/go-phorce/dolly/xpki/certutil"
 	"github.com/stretchr/testify/assert"
@@ -54,7 +56,15 @@ func TestCertificate(t *testing.T) {
 		Profile:          "profile",
 		Pem:              "pem",
 		IssuersPem:       "issuers_pem",
+		Requester:        "denis@ekspand.com",
+		ClientIP:         "10.0.0.1",
+		RequestID:        "req-123",
+		Request:          "csr",
+		SAN:              []string{"trusty.com"},
+		Labels:           model.Labels{"team": "security"},
 	}
+	assert.NoError(t, m.Validate())
+
 	dto := m.ToDTO()
 	assert.Equal(t, uint64(123), dto.Id)
 	assert.Equal(t, uint64(234), dto.OrgId)
@@ -69,6 +79,12 @@ func TestCertificate(t *testing.T) {
 	assert.Equal(t, m.Profile, dto.Profile)
 	assert.Equal(t, m.Pem, dto.Pem)
 	assert.Equal(t, m.IssuersPem, dto.IssuersPem)
+	assert.Equal(t, m.Requester, dto.Requester)
+	assert.Equal(t, m.ClientIP, dto.ClientIp)
+	assert.Equal(t, m.RequestID, dto.RequestId)
+	assert.Equal(t, m.Request, dto.Request)
+	assert.Equal(t, m.SAN, dto.San)
+	assert.Equal(t, map[string]string{"team": "security"}, dto.Labels)
 
 	m2 := model.CertificateFromPB(dto)
 	assert.Equal(t, *m, *m2)
@@ -146,3 +162,53 @@ A2cAMGQCMG5FNrmdZIMD8hWH9nVsUoLCQoLbuuvS/HdC8fPi5fwvdZvJtZ7K/JNG
 IEN9D35UWQIwEsqs1R1K+zi6jfjBzuXCgKdvcOxRnxNOokh69FVCCoegVEDbgDBj
 yMrvIi4tTwKn
 -----END CERTIFICATE-----`
+
+func TestSSHCertificate(t *testing.T) {
+	nb, err := time.Parse(time.RFC3339, "2012-11-01T22:08:41+00:00")
+	require.NoError(t, err)
+	na, err := time.Parse(time.RFC3339, "2012-12-01T22:08:41+00:00")
+	require.NoError(t, err)
+
+	m := &model.SSHCertificate{
+		ID:               123,
+		OrgID:            234,
+		IKID:             "ikid",
+		SerialNumber:     "1234",
+		KeyID:            "key_id",
+		CertType:         "user",
+		NotBefore:        nb.UTC(),
+		NotAfter:         na.UTC(),
+		ThumbprintSha256: "sha256",
+		Profile:          "profile",
+		Pem:              "pem",
+	}
+	m.SetPrincipals("denis,ekspand")
+	assert.Equal(t, []string{"denis", "ekspand"}, m.Principals)
+	assert.Equal(t, "denis,ekspand", m.PrincipalsString())
+	assert.NoError(t, m.Validate())
+	assert.False(t, m.IsRevoked())
+
+	dto := m.ToDTO()
+	assert.Equal(t, uint64(123), dto.Id)
+	assert.Equal(t, uint64(234), dto.OrgId)
+	assert.Equal(t, m.IKID, dto.Ikid)
+	assert.Equal(t, m.SerialNumber, dto.SerialNumber)
+	assert.Equal(t, m.KeyID, dto.KeyId)
+	assert.Equal(t, m.CertType, dto.CertType)
+	assert.Equal(t, m.Principals, dto.Principals)
+	assert.Equal(t, m.NotBefore, dto.NotBefore.AsTime().UTC())
+	assert.Equal(t, m.NotAfter, dto.NotAfter.AsTime().UTC())
+	assert.Equal(t, m.Pem, dto.Certificate)
+	assert.Nil(t, dto.RevokedAt)
+
+	m.RevokedAt = sql.NullTime{Time: na.UTC(), Valid: true}
+	m.Reason = 1
+	dto = model.SSHCertificates{m}.ToDTO()[0]
+	assert.Equal(t, na.UTC(), dto.RevokedAt.AsTime().UTC())
+	assert.Equal(t, pb.Reason_KEY_COMPROMISE, dto.Reason)
+
+	m.SetPrincipals("")
+	assert.Nil(t, m.Principals)
+	m.Pem = ""
+	assert.EqualError(t, m.Validate(), "invalid certificate")
+}
@@ -0,0 +1,100 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabels(t *testing.T) {
+	tcases := []struct {
+		labels model.Labels
+		err    string
+	}{
+		{nil, ""},
+		{model.Labels{"team": "security", "env/stage": "prod", "ticket.id": ""}, ""},
+		{model.Labels{"Team": "security"}, `invalid label key: "Team"`},
+		{model.Labels{"team-": "security"}, `invalid label key: "team-"`},
+		{model.Labels{"": "security"}, `invalid label key: ""`},
+		{model.Labels{"team": strings.Repeat("x", 65)}, `invalid label value: "` + strings.Repeat("x", 65) + `"`},
+	}
+	for _, tc := range tcases {
+		err := tc.labels.Validate()
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+
+	m := &model.Certificate{Labels: model.Labels{"team": "security"}}
+	assert.NoError(t, m.Validate())
+	m.Labels["Team"] = "security"
+	assert.EqualError(t, m.Validate(), `invalid label key: "Team"`)
+
+	v, err := model.Labels(nil).Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = model.Labels{"team": "security"}.Value()
+	require.NoError(t, err)
+	assert.Equal(t, `{"team":"security"}`, v)
+
+	var l model.Labels
+	require.NoError(t, l.Scan([]byte(`{"team":"security"}`)))
+	assert.Equal(t, model.Labels{"team": "security"}, l)
+	require.NoError(t, l.Scan(nil))
+	assert.Nil(t, l)
+	require.NoError(t, l.Scan(`{"env":"prod"}`))
+	assert.Equal(t, model.Labels{"env": "prod"}, l)
+	assert.EqualError(t, l.Scan(1), "unsupported labels type: int")
+}
+
+func TestLabelsUpdate(t *testing.T) {
+	l := model.Labels{"team": "security", "env": "dev"}
+	u := l.Update(model.Labels{"env": "prod", "owner": "denis"}, []string{"team", "missing"})
+	assert.Equal(t, model.Labels{"env": "prod", "owner": "denis"}, u)
+	assert.Equal(t, model.Labels{"team": "security", "env": "dev"}, l, "the original labels must not change")
+	assert.Equal(t, "env=prod,owner=denis", u.String())
+
+	assert.Nil(t, l.Update(nil, []string{"team", "env"}))
+	assert.Equal(t, model.Labels{"env": "prod"}, model.Labels(nil).Update(model.Labels{"env": "prod"}, nil))
+}
+
+func TestLabelSelector(t *testing.T) {
+	tcases := []struct {
+		selector string
+		parsed   string
+		err      string
+	}{
+		{"", "", ""},
+		{"team=security", "team=security", ""},
+		{" team == security , env!=dev,owner,!deprecated ", "team=security,env!=dev,owner,!deprecated", ""},
+		{"Team=security", "", `invalid selector "Team=security": invalid label key: "Team"`},
+		{"=security", "", `invalid selector "=security": invalid label key: ""`},
+		{"!", "", `invalid selector "!": invalid label key: ""`},
+	}
+	for _, tc := range tcases {
+		s, err := model.ParseLabelSelector(tc.selector)
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err)
+		} else {
+			require.NoError(t, err, tc.selector)
+			assert.Equal(t, tc.parsed, s.String())
+		}
+	}
+
+	s, err := model.ParseLabelSelector("team=security,env!=dev,owner,!deprecated")
+	require.NoError(t, err)
+	assert.True(t, s.Matches(model.Labels{"team": "security", "env": "prod", "owner": "denis"}))
+	assert.True(t, s.Matches(model.Labels{"team": "security", "owner": ""}))
+	assert.False(t, s.Matches(model.Labels{"team": "security", "env": "dev", "owner": "denis"}))
+	assert.False(t, s.Matches(model.Labels{"team": "payments", "owner": "denis"}))
+	assert.False(t, s.Matches(model.Labels{"team": "security"}))
+	assert.False(t, s.Matches(model.Labels{"team": "security", "owner": "denis", "deprecated": "true"}))
+	assert.False(t, s.Matches(nil))
+	assert.True(t, model.LabelSelector(nil).Matches(nil))
+}
@@ -4,9 +4,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/ekspand/trusty/api/v1/pb"
 	"github.com/ekspand/trusty/internal/db/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -157,6 +159,281 @@ func TestRepository(t *testing.T) {
 	assert.Equal(t, "", dto.ExternalID)
 }
 
+func TestServiceAccount(t *testing.T) {
+	tcases := []struct {
+		sa  *model.ServiceAccount
+		err string
+	}{
+		{&model.ServiceAccount{}, "missing org ID"},
+		{&model.ServiceAccount{OrgID: 1}, "invalid name: \"\""},
+		{&model.ServiceAccount{OrgID: 1, Name: "ci bot"}, "invalid name: \"ci bot\""},
+		{&model.ServiceAccount{OrgID: 1, Name: longVal}, fmt.Sprintf("invalid name: %q", longVal)},
+		{&model.ServiceAccount{OrgID: 1, Name: "ci", Description: longURL}, "description is too long"},
+		{&model.ServiceAccount{OrgID: 1, Name: "ci-bot_1.0", Description: "CI"}, ""},
+	}
+	for _, tc := range tcases {
+		err := tc.sa.Validate()
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+
+	sa := &model.ServiceAccount{ID: 1000, OrgID: 1001, Name: "ci", Description: "CI", CreatedBy: 1002}
+	dto := sa.ToDto()
+	assert.Equal(t, "1000", dto.ID)
+	assert.Equal(t, "1001", dto.OrgID)
+	assert.Equal(t, "1002", dto.CreatedBy)
+	assert.Equal(t, sa.Name, dto.Name)
+	assert.Equal(t, sa.Description, dto.Description)
+	assert.Len(t, model.ToServiceAccountsDto([]*model.ServiceAccount{sa}), 1)
+}
+
+func TestAPIToken(t *testing.T) {
+	secret, err := model.NewAPITokenSecret()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, "trusty_"))
+	assert.Len(t, secret, len("trusty_")+43)
+
+	secret2, err := model.NewAPITokenSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, secret2)
+
+	hash := model.HashAPIToken(secret)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, model.HashAPIToken(secret+"\n"))
+	assert.NotEqual(t, hash, model.HashAPIToken(secret2))
+
+	tcases := []struct {
+		tk  *model.APIToken
+		err string
+	}{
+		{&model.APIToken{}, "missing service account ID"},
+		{&model.APIToken{ServiceAccountID: 1}, "invalid name: \"\""},
+		{&model.APIToken{ServiceAccountID: 1, Name: "deploy"}, "invalid token hash"},
+		{&model.APIToken{ServiceAccountID: 1, Name: "deploy", TokenHash: hash}, ""},
+	}
+	for _, tc := range tcases {
+		err := tc.tk.Validate()
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+
+	now := time.Now()
+	tk := &model.APIToken{ID: 1000, ServiceAccountID: 1001, Name: "deploy", TokenHash: hash, CreatedBy: 1002}
+	assert.False(t, tk.IsExpired(now))
+	assert.False(t, tk.IsRevoked())
+
+	dto := tk.ToDto()
+	assert.Equal(t, "1000", dto.ID)
+	assert.Equal(t, "1001", dto.ServiceAccountID)
+	assert.Equal(t, "1002", dto.CreatedBy)
+	assert.Nil(t, dto.ExpiresAt)
+	assert.Nil(t, dto.LastUsedAt)
+	assert.Nil(t, dto.RevokedAt)
+
+	tk.ExpiresAt = sql.NullTime{Time: now, Valid: true}
+	tk.RevokedAt = sql.NullTime{Time: now, Valid: true}
+	assert.True(t, tk.IsExpired(now))
+	assert.False(t, tk.IsExpired(now.Add(-time.Second)))
+	assert.True(t, tk.IsRevoked())
+
+	dto = tk.ToDto()
+	require.NotNil(t, dto.ExpiresAt)
+	require.NotNil(t, dto.RevokedAt)
+	assert.Equal(t, now, *dto.ExpiresAt)
+	assert.Len(t, model.ToAPITokensDto([]*model.APIToken{tk}), 1)
+}
+
+func TestSession(t *testing.T) {
+	now := time.Now()
+	tcases := []struct {
+		s   *model.Session
+		err string
+	}{
+		{&model.Session{}, "missing user ID"},
+		{&model.Session{UserID: 1, DeviceID: strings.Repeat("d", 65)}, "device ID is too long"},
+		{&model.Session{UserID: 1, DeviceID: "laptop"}, "missing expiration"},
+		{&model.Session{UserID: 1, DeviceID: "laptop", ExpiresAt: now}, ""},
+	}
+	for _, tc := range tcases {
+		err := tc.s.Validate()
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+
+	s := &model.Session{ID: 1000, UserID: 1001, DeviceID: "laptop", IssuedAt: now, ExpiresAt: now.Add(time.Hour)}
+	assert.True(t, s.IsActive(now))
+	assert.False(t, s.IsActive(now.Add(time.Hour)))
+
+	dto := s.ToDto()
+	assert.Equal(t, "1000", dto.ID)
+	assert.Equal(t, "1001", dto.UserID)
+	assert.Equal(t, "laptop", dto.DeviceID)
+	assert.Nil(t, dto.LastSeenAt)
+	assert.Nil(t, dto.RevokedAt)
+
+	s.RevokedAt = sql.NullTime{Time: now, Valid: true}
+	assert.False(t, s.IsActive(now))
+	dto = s.ToDto()
+	require.NotNil(t, dto.RevokedAt)
+	assert.Equal(t, now, *dto.RevokedAt)
+	assert.Len(t, model.ToSessionsDto([]*model.Session{s}), 1)
+
+	s.Groups = []string{"admins"}
+	assert.Equal(t, []string{"admins"}, s.ToDto().Groups)
+}
+
+func TestRoleBinding(t *testing.T) {
+	tcases := []struct {
+		b   *model.RoleBinding
+		err string
+	}{
+		{&model.RoleBinding{}, `invalid role: ""`},
+		{&model.RoleBinding{Role: "trusty admin"}, `invalid role: "trusty admin"`},
+		{&model.RoleBinding{Role: "trusty-admin"}, "missing subject"},
+		{&model.RoleBinding{Role: "trusty-admin", Subject: "group:"}, "missing subject"},
+		{&model.RoleBinding{Role: "trusty-admin", Subject: strings.Repeat("s", 257)}, "subject is too long"},
+		{&model.RoleBinding{Role: "trusty-admin", Subject: "denis@ekspand.com"}, "subject must have one of prefixes: user:, sa:, spiffe:, cn:, group:"},
+		{&model.RoleBinding{Role: "trusty-admin", Subject: "group:admins"}, "group must be in ${provider}/${group} format"},
+		{&model.RoleBinding{Role: "trusty-admin", Subject: "group:okta/"}, "group must be in ${provider}/${group} format"},
+		{&model.RoleBinding{Role: "trusty-admin", Subject: "user:denis@ekspand.com"}, ""},
+		{&model.RoleBinding{Role: "trusty-admin", Subject: "sa:ekspand/ci"}, ""},
+		{&model.RoleBinding{Role: "trusty-admin", Subject: "spiffe:spiffe://trusty/ca"}, ""},
+		{&model.RoleBinding{Role: "trusty-admin", Subject: "cn:trusty-peer"}, ""},
+		{&model.RoleBinding{Role: "trusty-admin", Subject: "group:okta/admins"}, ""},
+	}
+	for _, tc := range tcases {
+		err := tc.b.Validate()
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+
+	now := time.Now()
+	b := &model.RoleBinding{ID: 1000, Role: "trusty-admin", Subject: "group:okta/admins", CreatedAt: now}
+	dto := b.ToDto()
+	assert.Equal(t, "1000", dto.ID)
+	assert.Equal(t, "trusty-admin", dto.Role)
+	assert.Equal(t, "group:okta/admins", dto.Subject)
+	assert.Empty(t, dto.CreatedBy)
+	assert.Equal(t, now, dto.CreatedAt)
+
+	b.CreatedBy = 1001
+	assert.Equal(t, "1001", b.ToDto().CreatedBy)
+	assert.Len(t, model.ToRoleBindingsDto([]*model.RoleBinding{b}), 1)
+}
+
+func TestDeviceAuthorization(t *testing.T) {
+	code, err := model.NewDeviceCode()
+	require.NoError(t, err)
+	assert.Len(t, code, 43)
+	hash := model.HashDeviceCode(code)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, model.HashDeviceCode(code+"\n"))
+
+	userCode, err := model.NewUserCode()
+	require.NoError(t, err)
+	assert.Len(t, userCode, 9)
+	assert.Equal(t, "-", userCode[4:5])
+	assert.Equal(t, userCode, model.NormalizeUserCode(strings.ToLower(strings.Replace(userCode, "-", " ", 1))))
+	assert.Equal(t, "ABC", model.NormalizeUserCode("abc"))
+
+	now := time.Now()
+	tcases := []struct {
+		d   *model.DeviceAuthorization
+		err string
+	}{
+		{&model.DeviceAuthorization{}, "invalid device code hash"},
+		{&model.DeviceAuthorization{DeviceCode: hash}, "invalid user code"},
+		{&model.DeviceAuthorization{DeviceCode: hash, UserCode: userCode}, "invalid provider"},
+		{&model.DeviceAuthorization{DeviceCode: hash, UserCode: userCode, Provider: "github"}, "invalid device ID"},
+		{&model.DeviceAuthorization{DeviceCode: hash, UserCode: userCode, Provider: "github", DeviceID: "host"}, "missing expiration"},
+		{&model.DeviceAuthorization{DeviceCode: hash, UserCode: userCode, Provider: "github", DeviceID: "host", ExpiresAt: now}, ""},
+	}
+	for _, tc := range tcases {
+		err := tc.d.Validate()
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+
+	d := &model.DeviceAuthorization{ExpiresAt: now}
+	assert.False(t, d.IsApproved())
+	assert.True(t, d.IsExpired(now))
+	assert.False(t, d.IsExpired(now.Add(-time.Second)))
+
+	d.SessionID = 1
+	d.ApprovedAt = sql.NullTime{Time: now, Valid: true}
+	assert.True(t, d.IsApproved())
+}
+
+func TestApproval(t *testing.T) {
+	now := time.Now()
+	tcases := []struct {
+		a   *model.Approval
+		err string
+	}{
+		{&model.Approval{}, `invalid profile: ""`},
+		{&model.Approval{Profile: "subca"}, `invalid issuer label: ""`},
+		{&model.Approval{Profile: "subca", IssuerLabel: "trusty"}, "missing request"},
+		{&model.Approval{Profile: "subca", IssuerLabel: "trusty", Request: "csr"}, "at least one approval must be required"},
+		{&model.Approval{Profile: "subca", IssuerLabel: "trusty", Request: "csr", RequiredApprovals: 1}, "missing approver roles"},
+		{&model.Approval{Profile: "subca", IssuerLabel: "trusty", Request: "csr", RequiredApprovals: 1, ApproverRoles: []string{"trusty-admin"}}, "missing expiration"},
+		{&model.Approval{Profile: "subca", IssuerLabel: "trusty", Request: "csr", RequiredApprovals: 1, ApproverRoles: []string{"trusty-admin"}, ExpiresAt: now}, ""},
+	}
+	for _, tc := range tcases {
+		err := tc.a.Validate()
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+
+	assert.EqualError(t, (&model.ApprovalVote{}).Validate(), `invalid approver: ""`)
+	assert.EqualError(t, (&model.ApprovalVote{Approver: "admin", Reason: strings.Repeat("x", 257)}).Validate(), "reason is too long")
+	assert.NoError(t, (&model.ApprovalVote{Approver: "admin", Reason: "ok"}).Validate())
+
+	a := &model.Approval{
+		ID:                1001,
+		Status:            model.ApprovalStatusPending,
+		RequiredApprovals: 2,
+		ExpiresAt:         now,
+		Votes: []*model.ApprovalVote{
+			{Approver: "admin1@trusty.com", Approved: true},
+			{Approver: "admin2@trusty.com", Approved: false},
+		},
+	}
+	assert.True(t, a.IsExpired(now))
+	assert.False(t, a.IsExpired(now.Add(-time.Second)))
+	assert.Equal(t, 1, a.Approvals())
+	assert.NotNil(t, a.Vote("ADMIN2@trusty.com"))
+	assert.Nil(t, a.Vote("admin3@trusty.com"))
+
+	dto := a.ToDTO()
+	assert.Equal(t, pb.ApprovalStatus_PENDING, dto.Status)
+	assert.Len(t, dto.Votes, 2)
+
+	a.Status = model.ApprovalStatusIssued
+	assert.False(t, a.IsExpired(now))
+	list := model.Approvals{a}.ToDTO()
+	require.Len(t, list, 1)
+	assert.Equal(t, pb.ApprovalStatus_ISSUED, list[0].Status)
+	assert.Equal(t, model.ApprovalStatusDenied, model.ApprovalStatus(pb.ApprovalStatus_DENIED))
+}
+
 func NullTime(t *testing.T) {
 	v := model.NullTime(nil)
 	require.NotNil(t, v)
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/ekspand/trusty/pkg/authz"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/juju/errors"
+)
+
+// OwnerResolver resolves owners of certificates and orgs of callers,
+// for the conditions of the authz policy
+type OwnerResolver struct {
+	certs CertsReadonlyDb
+	orgs  OrgsReadOnlyDb
+}
+
+// NewOwnerResolver returns OwnerResolver
+func NewOwnerResolver(certs CertsReadonlyDb, orgs OrgsReadOnlyDb) *OwnerResolver {
+	return &OwnerResolver{certs: certs, orgs: orgs}
+}
+
+// certificateRequest is implemented by CA requests for a certificate
+type certificateRequest interface {
+	GetId() uint64
+	GetSkid() string
+}
+
+// ResourceOwner returns the org and the requester of the certificate
+// of CAService request, or nil if the request does not reference a certificate
+func (r *OwnerResolver) ResourceOwner(ctx context.Context, method string, req interface{}) (*authz.Owner, error) {
+	cr, ok := req.(certificateRequest)
+	if !ok || !strings.HasPrefix(method, "/pb.CAService/") {
+		return nil, nil
+	}
+
+	var crt *model.Certificate
+	var err error
+	switch {
+	case cr.GetId() != 0:
+		crt, err = r.certs.GetCertificate(ctx, cr.GetId())
+	case cr.GetSkid() != "":
+		crt, err = r.certs.GetCertificateBySKID(ctx, cr.GetSkid())
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			// the condition is not satisfied for unknown certificate
+			return nil, nil
+		}
+		logger.Errorf("api=ResourceOwner, method=%s, err=[%s]", method, errors.Details(err))
+		return nil, errors.Trace(err)
+	}
+
+	owner := &authz.Owner{Requester: crt.Requester}
+	if crt.OrgID != 0 {
+		owner.OrgID = strconv.FormatUint(crt.OrgID, 10)
+	}
+	return owner, nil
+}
+
+// CallerOrgs returns IDs of the orgs of the user
+func (r *OwnerResolver) CallerOrgs(ctx context.Context, idn identity.Identity) ([]string, error) {
+	if idn.UserID() == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(idn.UserID(), 10, 64)
+	if err != nil {
+		// service accounts and TLS identities are not users
+		return nil, nil
+	}
+	orgs, err := r.orgs.GetUserOrgs(ctx, id)
+	if err != nil {
+		logger.Errorf("api=CallerOrgs, user_id=%d, err=[%s]", id, errors.Details(err))
+		return nil, errors.Trace(err)
+	}
+	res := make([]string, len(orgs))
+	for i, org := range orgs {
+		res[i] = strconv.FormatUint(org.ID, 10)
+	}
+	return res, nil
+}
@@ -0,0 +1,349 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/juju/errors"
+	"github.com/lib/pq"
+)
+
+const approvalColumns = `id,COALESCE(org_id,0),profile,issuer_label,subject,san,request,requester,
+	COALESCE(client_ip,''),COALESCE(request_id,''),labels,status,
+	required_approvals,approver_roles,created_at,expires_at,COALESCE(certificate_id,0)`
+
+// CreateApproval creates a pending certificate request in the approval queue
+func (p *Provider) CreateApproval(ctx context.Context, a *model.Approval) (*model.Approval, error) {
+	id, err := p.NextID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = model.Validate(a)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	logger.Debugf("profile=%s, subject=%q, requester=%q", a.Profile, a.Subject, a.Requester)
+
+	res := new(model.Approval)
+	err = p.db.QueryRowContext(ctx, `
+			INSERT INTO approvals(id,org_id,profile,issuer_label,subject,san,request,requester,client_ip,request_id,labels,status,
+				required_approvals,approver_roles,created_at,expires_at)
+				VALUES($1, NULLIF($2,0), $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			RETURNING `+approvalColumns+`
+			;`, id, a.OrgID, a.Profile, a.IssuerLabel, a.Subject, pq.Array(a.SAN),
+		a.Request, a.Requester, a.ClientIP, a.RequestID, a.Labels, model.ApprovalStatusPending,
+		a.RequiredApprovals, pq.Array(a.ApproverRoles),
+		time.Now().UTC(), a.ExpiresAt.UTC(),
+	).Scan(approvalFields(res)...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return utcApproval(res), nil
+}
+
+// GetApproval returns the certificate request in the approval queue, with the votes
+func (p *Provider) GetApproval(ctx context.Context, id uint64) (*model.Approval, error) {
+	res := new(model.Approval)
+	err := p.db.QueryRowContext(ctx, `
+		SELECT `+approvalColumns+`
+		FROM approvals
+		WHERE id=$1
+		;`, id,
+	).Scan(approvalFields(res)...)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundf("approval")
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = loadApprovalVotes(ctx, p.db, res)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return utcApproval(res), nil
+}
+
+// ListApprovals returns the certificate requests in the approval queue
+// with the specified status, or with any status if the status is empty.
+// The pending requests are marked as expired by ExpireApprovals.
+func (p *Provider) ListApprovals(ctx context.Context, status string, limit int, afterID uint64) (model.Approvals, error) {
+	if limit == 0 {
+		limit = 1000
+	}
+
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT `+approvalColumns+`
+		FROM approvals
+		WHERE ($1 = '' OR status = $1) AND id > $2
+		ORDER BY id
+		LIMIT $3
+		;`, status, afterID, limit)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer rows.Close()
+
+	var list model.Approvals
+	for rows.Next() {
+		a := new(model.Approval)
+		err = rows.Scan(approvalFields(a)...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		list = append(list, utcApproval(a))
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	for _, a := range list {
+		err = loadApprovalVotes(ctx, p.db, a)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return list, nil
+}
+
+// ExpireApprovals marks the pending requests as expired, after their expiration time,
+// and returns the number of expired requests
+func (p *Provider) ExpireApprovals(ctx context.Context, at time.Time) (int, error) {
+	res, err := p.db.ExecContext(ctx, `
+		UPDATE approvals
+			SET status=$1
+		WHERE status=$2 AND expires_at <= $3
+		;`, model.ApprovalStatusExpired, model.ApprovalStatusPending, at.UTC())
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if count > 0 {
+		logger.Noticef("api=ExpireApprovals, count=%d", count)
+	}
+	return int(count), nil
+}
+
+// VoteApproval adds the vote of the approver to the pending request.
+// The request is approved when the required approvals are received,
+// or denied by the first vote against it.
+// NotValid error is returned, if the request is not pending or expired,
+// and AlreadyExists error, if the approver already voted.
+func (p *Provider) VoteApproval(ctx context.Context, vote *model.ApprovalVote, at time.Time) (*model.Approval, error) {
+	err := model.Validate(vote)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer tx.Rollback()
+
+	res := new(model.Approval)
+	err = tx.QueryRowContext(ctx, `
+		SELECT `+approvalColumns+`
+		FROM approvals
+		WHERE id=$1
+		FOR UPDATE
+		;`, vote.ApprovalID,
+	).Scan(approvalFields(res)...)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundf("approval")
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if res.IsExpired(at) {
+		return nil, errors.NotValidf("expired approval")
+	}
+	if res.Status != model.ApprovalStatusPending {
+		return nil, errors.NotValidf("%s approval", res.Status)
+	}
+
+	err = loadApprovalVotes(ctx, tx, res)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if res.Vote(vote.Approver) != nil {
+		return nil, errors.AlreadyExistsf("vote of %q", vote.Approver)
+	}
+
+	v := &model.ApprovalVote{
+		ApprovalID: res.ID,
+		Approver:   vote.Approver,
+		Approved:   vote.Approved,
+		Reason:     vote.Reason,
+		CreatedAt:  at.UTC(),
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO approval_votes(approval_id,approver,approved,reason,created_at)
+			VALUES($1, $2, $3, $4, $5)
+		;`, v.ApprovalID, v.Approver, v.Approved, v.Reason, v.CreatedAt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	res.Votes = append(res.Votes, v)
+
+	status := res.Status
+	if !v.Approved {
+		status = model.ApprovalStatusDenied
+	} else if res.Approvals() >= res.RequiredApprovals {
+		status = model.ApprovalStatusApproved
+	}
+	if status != res.Status {
+		_, err = tx.ExecContext(ctx, `UPDATE approvals SET status=$2 WHERE id=$1;`, res.ID, status)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		res.Status = status
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	logger.Noticef("api=VoteApproval, id=%d, approver=%q, approved=%t, status=%s",
+		res.ID, v.Approver, v.Approved, res.Status)
+	return utcApproval(res), nil
+}
+
+// ClaimApproval marks the approved request as issuing,
+// so only one of the concurrent callers issues the certificate.
+// NotFound error is returned, if the request does not exist or not approved.
+func (p *Provider) ClaimApproval(ctx context.Context, id uint64) (*model.Approval, error) {
+	return p.updateApprovalStatus(ctx, "ClaimApproval", id, model.ApprovalStatusApproved, model.ApprovalStatusIssuing)
+}
+
+// ReleaseApproval marks the issuing request as approved,
+// after the certificate failed to be issued.
+// NotFound error is returned, if the request does not exist or not issuing.
+func (p *Provider) ReleaseApproval(ctx context.Context, id uint64) (*model.Approval, error) {
+	return p.updateApprovalStatus(ctx, "ReleaseApproval", id, model.ApprovalStatusIssuing, model.ApprovalStatusApproved)
+}
+
+func (p *Provider) updateApprovalStatus(ctx context.Context, api string, id uint64, from, to string) (*model.Approval, error) {
+	res := new(model.Approval)
+	err := p.db.QueryRowContext(ctx, `
+			UPDATE approvals
+				SET status=$3
+			WHERE id=$1 AND status=$2
+			RETURNING `+approvalColumns+`
+			;`, id, from, to,
+	).Scan(approvalFields(res)...)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundf("%s request", from)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = loadApprovalVotes(ctx, p.db, res)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Noticef("api=%s, id=%d, status=%s", api, id, to)
+	return utcApproval(res), nil
+}
+
+// IssueApproval marks the issuing request as issued with the certificate.
+// NotFound error is returned, if the request does not exist or not issuing.
+func (p *Provider) IssueApproval(ctx context.Context, id, certificateID uint64) (*model.Approval, error) {
+	res := new(model.Approval)
+	err := p.db.QueryRowContext(ctx, `
+			UPDATE approvals
+				SET status=$3, certificate_id=$2
+			WHERE id=$1 AND status=$4
+			RETURNING `+approvalColumns+`
+			;`, id, certificateID, model.ApprovalStatusIssued, model.ApprovalStatusIssuing,
+	).Scan(approvalFields(res)...)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundf("issuing request")
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = loadApprovalVotes(ctx, p.db, res)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Noticef("api=IssueApproval, id=%d, certificate_id=%d", id, certificateID)
+	return utcApproval(res), nil
+}
+
+// RemoveApproval deletes the request and its votes
+func (p *Provider) RemoveApproval(ctx context.Context, id uint64) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM approvals WHERE id=$1;`, id)
+	if err != nil {
+		logger.Errorf("api=RemoveApproval, err=[%s]", errors.Details(err))
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func loadApprovalVotes(ctx context.Context, q queryer, a *model.Approval) error {
+	rows, err := q.QueryContext(ctx, `
+		SELECT approval_id,approver,approved,COALESCE(reason,''),created_at
+		FROM approval_votes
+		WHERE approval_id=$1
+		ORDER BY created_at
+		;`, a.ID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer rows.Close()
+
+	a.Votes = nil
+	for rows.Next() {
+		v := new(model.ApprovalVote)
+		err = rows.Scan(&v.ApprovalID, &v.Approver, &v.Approved, &v.Reason, &v.CreatedAt)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		v.CreatedAt = v.CreatedAt.UTC()
+		a.Votes = append(a.Votes, v)
+	}
+	return errors.Trace(rows.Err())
+}
+
+func approvalFields(a *model.Approval) []interface{} {
+	return []interface{}{
+		&a.ID,
+		&a.OrgID,
+		&a.Profile,
+		&a.IssuerLabel,
+		&a.Subject,
+		pq.Array(&a.SAN),
+		&a.Request,
+		&a.Requester,
+		&a.ClientIP,
+		&a.RequestID,
+		&a.Labels,
+		&a.Status,
+		&a.RequiredApprovals,
+		pq.Array(&a.ApproverRoles),
+		&a.CreatedAt,
+		&a.ExpiresAt,
+		&a.CertificateID,
+	}
+}
+
+func utcApproval(a *model.Approval) *model.Approval {
+	a.CreatedAt = a.CreatedAt.UTC()
+	a.ExpiresAt = a.ExpiresAt.UTC()
+	return a
+}
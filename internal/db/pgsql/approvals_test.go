@@ -0,0 +1,149 @@
+package pgsql_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovals(t *testing.T) {
+	id, err := provider.NextID()
+	require.NoError(t, err)
+
+	requester := fmt.Sprintf("requester%d@trusty.com", id)
+	a, err := provider.CreateApproval(ctx, &model.Approval{
+		Profile:           "subca",
+		IssuerLabel:       "trusty.svc",
+		Subject:           fmt.Sprintf("CN=sub-ca-%d", id),
+		SAN:               []string{"sub-ca.trusty.com"},
+		Request:           "csr",
+		Requester:         requester,
+		ClientIP:          "10.0.0.1",
+		RequestID:         "req-123",
+		Labels:            model.Labels{"team": "security"},
+		RequiredApprovals: 2,
+		ApproverRoles:     []string{"trusty-admin"},
+		ExpiresAt:         time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	defer provider.RemoveApproval(ctx, a.ID)
+	assert.Equal(t, model.ApprovalStatusPending, a.Status)
+	assert.Equal(t, []string{"sub-ca.trusty.com"}, a.SAN)
+	assert.Equal(t, []string{"trusty-admin"}, a.ApproverRoles)
+	assert.Equal(t, "10.0.0.1", a.ClientIP)
+	assert.Equal(t, "req-123", a.RequestID)
+	assert.Equal(t, model.Labels{"team": "security"}, a.Labels)
+
+	got, err := provider.GetApproval(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, *a, *got)
+
+	_, err = provider.GetApproval(ctx, 1)
+	assert.True(t, errors.IsNotFound(err))
+
+	list, err := provider.ListApprovals(ctx, model.ApprovalStatusPending, 100, a.ID-1)
+	require.NoError(t, err)
+	require.NotEmpty(t, list)
+	assert.Equal(t, a.ID, list[0].ID)
+
+	a, err = provider.VoteApproval(ctx, &model.ApprovalVote{ApprovalID: a.ID, Approver: "admin1@trusty.com", Approved: true}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, model.ApprovalStatusPending, a.Status)
+	assert.Equal(t, 1, a.Approvals())
+
+	_, err = provider.VoteApproval(ctx, &model.ApprovalVote{ApprovalID: a.ID, Approver: "admin1@trusty.com", Approved: true}, time.Now())
+	assert.True(t, errors.IsAlreadyExists(err), "can vote only once")
+
+	_, err = provider.VoteApproval(ctx, &model.ApprovalVote{ApprovalID: a.ID, Approver: "admin2@trusty.com", Approved: true}, time.Now().Add(2*time.Hour))
+	assert.True(t, errors.IsNotValid(err), "expired")
+
+	_, err = provider.ClaimApproval(ctx, a.ID)
+	assert.True(t, errors.IsNotFound(err), "pending request can not be claimed")
+
+	a, err = provider.VoteApproval(ctx, &model.ApprovalVote{ApprovalID: a.ID, Approver: "admin2@trusty.com", Approved: true, Reason: "ok"}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, model.ApprovalStatusApproved, a.Status)
+	assert.Len(t, a.Votes, 2)
+
+	_, err = provider.VoteApproval(ctx, &model.ApprovalVote{ApprovalID: a.ID, Approver: "admin3@trusty.com", Approved: false}, time.Now())
+	assert.True(t, errors.IsNotValid(err), "approved request")
+
+	_, err = provider.IssueApproval(ctx, a.ID, 1234)
+	assert.True(t, errors.IsNotFound(err), "approved request must be claimed")
+
+	a, err = provider.ClaimApproval(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ApprovalStatusIssuing, a.Status)
+
+	_, err = provider.ClaimApproval(ctx, a.ID)
+	assert.True(t, errors.IsNotFound(err), "can claim only once")
+
+	a, err = provider.ReleaseApproval(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ApprovalStatusApproved, a.Status)
+
+	_, err = provider.ReleaseApproval(ctx, a.ID)
+	assert.True(t, errors.IsNotFound(err), "approved request can not be released")
+
+	a, err = provider.ClaimApproval(ctx, a.ID)
+	require.NoError(t, err)
+
+	a, err = provider.IssueApproval(ctx, a.ID, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, model.ApprovalStatusIssued, a.Status)
+	assert.Equal(t, uint64(1234), a.CertificateID)
+	assert.Len(t, a.Votes, 2)
+
+	//
+	// denied and expired
+	//
+	denied, err := provider.CreateApproval(ctx, &model.Approval{
+		Profile:           "subca",
+		IssuerLabel:       "trusty.svc",
+		Request:           "csr",
+		Requester:         requester,
+		RequiredApprovals: 1,
+		ApproverRoles:     []string{"trusty-admin"},
+		ExpiresAt:         time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	defer provider.RemoveApproval(ctx, denied.ID)
+	assert.Empty(t, denied.SAN)
+
+	denied, err = provider.VoteApproval(ctx, &model.ApprovalVote{ApprovalID: denied.ID, Approver: "admin1@trusty.com", Reason: "no ticket"}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, model.ApprovalStatusDenied, denied.Status)
+
+	expired, err := provider.CreateApproval(ctx, &model.Approval{
+		Profile:           "subca",
+		IssuerLabel:       "trusty.svc",
+		Request:           "csr",
+		Requester:         requester,
+		RequiredApprovals: 1,
+		ApproverRoles:     []string{"trusty-admin"},
+		ExpiresAt:         time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	defer provider.RemoveApproval(ctx, expired.ID)
+
+	list, err = provider.ListApprovals(ctx, model.ApprovalStatusExpired, 100, expired.ID-1)
+	require.NoError(t, err)
+	assert.Empty(t, list, "list must not expire requests")
+
+	count, err := provider.ExpireApprovals(ctx, time.Now())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, 1)
+
+	expired, err = provider.GetApproval(ctx, expired.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ApprovalStatusExpired, expired.Status)
+
+	list, err = provider.ListApprovals(ctx, "", 100, a.ID-1)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, len(list), 3)
+}
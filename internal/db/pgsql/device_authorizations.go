@@ -0,0 +1,156 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/juju/errors"
+)
+
+const deviceAuthorizationColumns = `id,device_code,user_code,provider,device_id,created_at,expires_at,
+	COALESCE(user_id,0),COALESCE(session_id,0),approved_at,polled_at`
+
+// CreateDeviceAuthorization creates a pending device authorization,
+// and removes the expired ones
+func (p *Provider) CreateDeviceAuthorization(ctx context.Context, d *model.DeviceAuthorization) (*model.DeviceAuthorization, error) {
+	id, err := p.NextID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = model.Validate(d)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	now := time.Now().UTC()
+	_, err = p.db.ExecContext(ctx, `DELETE FROM device_authorizations WHERE expires_at < $1;`, now)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	logger.Debugf("device_id=%s, provider=%s", d.DeviceID, d.Provider)
+
+	res := new(model.DeviceAuthorization)
+	err = p.db.QueryRowContext(ctx, `
+			INSERT INTO device_authorizations(id,device_code,user_code,provider,device_id,created_at,expires_at)
+				VALUES($1, $2, $3, $4, $5, $6, $7)
+			RETURNING `+deviceAuthorizationColumns+`
+			;`, id, d.DeviceCode, d.UserCode, d.Provider, d.DeviceID, now, d.ExpiresAt.UTC(),
+	).Scan(deviceAuthorizationFields(res)...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return utcDeviceAuthorization(res), nil
+}
+
+// GetDeviceAuthorization returns the pending device authorization by the user code.
+// NotFound error is returned, if the authorization does not exist, expired or approved.
+func (p *Provider) GetDeviceAuthorization(ctx context.Context, userCode string) (*model.DeviceAuthorization, error) {
+	res := new(model.DeviceAuthorization)
+	err := p.db.QueryRowContext(ctx, `
+		SELECT `+deviceAuthorizationColumns+`
+		FROM device_authorizations
+		WHERE user_code=$1 AND approved_at IS NULL AND expires_at > $2
+		;`, userCode, time.Now().UTC(),
+	).Scan(deviceAuthorizationFields(res)...)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundf("device authorization")
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return utcDeviceAuthorization(res), nil
+}
+
+// ApproveDeviceAuthorization approves the pending device authorization with the session of the user
+func (p *Provider) ApproveDeviceAuthorization(ctx context.Context, id, userID, sessionID uint64, at time.Time) (*model.DeviceAuthorization, error) {
+	res := new(model.DeviceAuthorization)
+	err := p.db.QueryRowContext(ctx, `
+			UPDATE device_authorizations
+				SET user_id=$2, session_id=$3, approved_at=$4
+			WHERE id=$1 AND approved_at IS NULL AND expires_at > $4
+			RETURNING `+deviceAuthorizationColumns+`
+			;`, id, userID, sessionID, at.UTC(),
+	).Scan(deviceAuthorizationFields(res)...)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundf("device authorization")
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Noticef("api=ApproveDeviceAuthorization, id=%d, user_id=%d, session_id=%d", id, userID, sessionID)
+	return utcDeviceAuthorization(res), nil
+}
+
+// PollDeviceAuthorization returns the device authorization by the hash of the device code,
+// with the time of the previous poll, and updates the time of the poll.
+// NotFound error is returned, if the authorization does not exist.
+func (p *Provider) PollDeviceAuthorization(ctx context.Context, deviceCodeHash string, at time.Time) (*model.DeviceAuthorization, error) {
+	res := new(model.DeviceAuthorization)
+	err := p.db.QueryRowContext(ctx, `
+			UPDATE device_authorizations AS d
+				SET polled_at=$2
+			FROM (SELECT id, polled_at FROM device_authorizations WHERE device_code=$1 FOR UPDATE) AS prev
+			WHERE d.id=prev.id
+			RETURNING d.id,d.device_code,d.user_code,d.provider,d.device_id,d.created_at,d.expires_at,
+				COALESCE(d.user_id,0),COALESCE(d.session_id,0),d.approved_at,prev.polled_at
+			;`, deviceCodeHash, at.UTC(),
+	).Scan(deviceAuthorizationFields(res)...)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundf("device authorization")
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return utcDeviceAuthorization(res), nil
+}
+
+// RemoveDeviceAuthorization deletes the device authorization.
+// NotFound error is returned, if the authorization does not exist,
+// so only one of the concurrent callers succeeds.
+func (p *Provider) RemoveDeviceAuthorization(ctx context.Context, id uint64) error {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM device_authorizations WHERE id=$1;`, id)
+	if err != nil {
+		logger.Errorf("api=RemoveDeviceAuthorization, err=[%s]", errors.Details(err))
+		return errors.Trace(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if count == 0 {
+		return errors.NotFoundf("device authorization")
+	}
+	return nil
+}
+
+func deviceAuthorizationFields(d *model.DeviceAuthorization) []interface{} {
+	return []interface{}{
+		&d.ID,
+		&d.DeviceCode,
+		&d.UserCode,
+		&d.Provider,
+		&d.DeviceID,
+		&d.CreatedAt,
+		&d.ExpiresAt,
+		&d.UserID,
+		&d.SessionID,
+		&d.ApprovedAt,
+		&d.PolledAt,
+	}
+}
+
+func utcDeviceAuthorization(d *model.DeviceAuthorization) *model.DeviceAuthorization {
+	d.CreatedAt = d.CreatedAt.UTC()
+	d.ExpiresAt = d.ExpiresAt.UTC()
+	if d.ApprovedAt.Valid {
+		d.ApprovedAt.Time = d.ApprovedAt.Time.UTC()
+	}
+	if d.PolledAt.Valid {
+		d.PolledAt.Time = d.PolledAt.Time.UTC()
+	}
+	return d
+}
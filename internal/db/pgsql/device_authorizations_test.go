@@ -0,0 +1,90 @@
+package pgsql_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceAuthorizations(t *testing.T) {
+	id, err := provider.NextID()
+	require.NoError(t, err)
+
+	login := fmt.Sprintf("device%d", id)
+	email := login + "@trusty.com"
+	user, err := provider.LoginUser(ctx, &model.User{Login: login, Email: email, Name: email})
+	require.NoError(t, err)
+
+	deviceCode, err := model.NewDeviceCode()
+	require.NoError(t, err)
+	userCode, err := model.NewUserCode()
+	require.NoError(t, err)
+	hash := model.HashDeviceCode(deviceCode)
+
+	d, err := provider.CreateDeviceAuthorization(ctx, &model.DeviceAuthorization{
+		DeviceCode: hash,
+		UserCode:   userCode,
+		Provider:   "github",
+		DeviceID:   "jumphost",
+		ExpiresAt:  time.Now().Add(10 * time.Minute),
+	})
+	require.NoError(t, err)
+	defer provider.RemoveDeviceAuthorization(ctx, d.ID)
+	assert.Equal(t, hash, d.DeviceCode)
+	assert.Equal(t, userCode, d.UserCode)
+	assert.False(t, d.IsApproved())
+
+	got, err := provider.GetDeviceAuthorization(ctx, userCode)
+	require.NoError(t, err)
+	assert.Equal(t, *d, *got)
+
+	_, err = provider.GetDeviceAuthorization(ctx, "XXXX-XXXX")
+	assert.True(t, errors.IsNotFound(err))
+
+	polled, err := provider.PollDeviceAuthorization(ctx, hash, time.Now())
+	require.NoError(t, err)
+	assert.False(t, polled.PolledAt.Valid, "the first poll")
+	assert.False(t, polled.IsApproved())
+
+	polled, err = provider.PollDeviceAuthorization(ctx, hash, time.Now())
+	require.NoError(t, err)
+	assert.True(t, polled.PolledAt.Valid)
+
+	_, err = provider.PollDeviceAuthorization(ctx, model.HashDeviceCode("unknown"), time.Now())
+	assert.True(t, errors.IsNotFound(err))
+
+	session, err := provider.CreateSession(ctx, &model.Session{
+		UserID:    user.ID,
+		DeviceID:  "jumphost",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	approved, err := provider.ApproveDeviceAuthorization(ctx, d.ID, user.ID, session.ID, time.Now())
+	require.NoError(t, err)
+	assert.True(t, approved.IsApproved())
+	assert.Equal(t, user.ID, approved.UserID)
+	assert.Equal(t, session.ID, approved.SessionID)
+
+	_, err = provider.ApproveDeviceAuthorization(ctx, d.ID, user.ID, session.ID, time.Now())
+	assert.True(t, errors.IsNotFound(err), "can be approved only once")
+
+	_, err = provider.GetDeviceAuthorization(ctx, userCode)
+	assert.True(t, errors.IsNotFound(err), "approved authorization is not pending")
+
+	polled, err = provider.PollDeviceAuthorization(ctx, hash, time.Now())
+	require.NoError(t, err)
+	assert.True(t, polled.IsApproved())
+
+	require.NoError(t, provider.RemoveDeviceAuthorization(ctx, d.ID))
+	_, err = provider.PollDeviceAuthorization(ctx, hash, time.Now())
+	assert.True(t, errors.IsNotFound(err))
+
+	err = provider.RemoveDeviceAuthorization(ctx, d.ID)
+	assert.True(t, errors.IsNotFound(err), "can be removed only once")
+}
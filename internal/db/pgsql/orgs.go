@@ -87,9 +87,14 @@ func (p *Provider) GetOrg(ctx context.Context, id uint64) (*model.Organization,
 	return res, nil
 }
 
-// RemoveOrg deletes org and all its members
+// RemoveOrg deletes org, all its members and service accounts
 func (p *Provider) RemoveOrg(ctx context.Context, id uint64) error {
-	_, err := p.db.ExecContext(ctx, `DELETE FROM orgmembers WHERE org_id=$1;`, id)
+	_, err := p.db.ExecContext(ctx, `DELETE FROM service_accounts WHERE org_id=$1;`, id)
+	if err != nil {
+		logger.Errorf("api=RemoveOrg, err=[%s]", errors.Details(err))
+		return errors.Trace(err)
+	}
+	_, err = p.db.ExecContext(ctx, `DELETE FROM orgmembers WHERE org_id=$1;`, id)
 	if err != nil {
 		logger.Errorf("api=RemoveOrg, err=[%s]", errors.Details(err))
 		return errors.Trace(err)
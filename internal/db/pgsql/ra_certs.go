@@ -2,12 +2,20 @@ package pgsql
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/ekspand/trusty/internal/db/model"
 	"github.com/go-phorce/dolly/xlog"
 	"github.com/juju/errors"
+	"github.com/lib/pq"
 )
 
+const certificateColumns = `id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,
+	COALESCE(requester,''),COALESCE(client_ip,''),COALESCE(request_id,''),COALESCE(request,''),san,labels`
+
 // RegisterCertificate registers Cert
 func (p *Provider) RegisterCertificate(ctx context.Context, crt *model.Certificate) (*model.Certificate, error) {
 	id, err := p.NextID()
@@ -25,32 +33,22 @@ func (p *Provider) RegisterCertificate(ctx context.Context, crt *model.Certifica
 	res := new(model.Certificate)
 
 	err = p.db.QueryRowContext(ctx, `
-			INSERT INTO certificates(id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile)
-				VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			INSERT INTO certificates(id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,
+				requester,client_ip,request_id,request,san,labels)
+				VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 			ON CONFLICT (sha256)
 			DO UPDATE
 				SET org_id=$2,issuers_pem=$12
-			RETURNING id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile
+			RETURNING `+certificateColumns+`
 			;`, id, crt.OrgID, crt.SKID, crt.IKID, crt.SerialNumber,
 		crt.NotBefore, crt.NotAfter,
 		crt.Subject, crt.Issuer,
 		crt.ThumbprintSha256,
 		crt.Pem, crt.IssuersPem,
 		crt.Profile,
-	).Scan(&res.ID,
-		&res.OrgID,
-		&res.SKID,
-		&res.IKID,
-		&res.SerialNumber,
-		&res.NotBefore,
-		&res.NotAfter,
-		&res.Subject,
-		&res.Issuer,
-		&res.ThumbprintSha256,
-		&res.Pem,
-		&res.IssuersPem,
-		&res.Profile,
-	)
+		crt.Requester, crt.ClientIP, crt.RequestID, crt.Request,
+		pq.Array(crt.SAN), crt.Labels,
+	).Scan(certificateFields(res)...)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -76,31 +74,11 @@ func (p *Provider) RemoveCertificate(ctx context.Context, id uint64) error {
 func (p *Provider) GetCertificate(ctx context.Context, id uint64) (*model.Certificate, error) {
 	c := new(model.Certificate)
 	err := p.db.QueryRowContext(ctx, `
-		SELECT
-			id,org_id,skid,ikid,serial_number,
-			not_before,no_tafter,
-			subject,issuer,
-			sha256,
-			pem,issuers_pem,
-			profile
+		SELECT `+certificateColumns+`
 		FROM certificates
 		WHERE id = $1
 		;
-		`, id).Scan(
-		&c.ID,
-		&c.OrgID,
-		&c.SKID,
-		&c.IKID,
-		&c.SerialNumber,
-		&c.NotBefore,
-		&c.NotAfter,
-		&c.Subject,
-		&c.Issuer,
-		&c.ThumbprintSha256,
-		&c.Pem,
-		&c.IssuersPem,
-		&c.Profile,
-	)
+		`, id).Scan(certificateFields(c)...)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -114,31 +92,11 @@ func (p *Provider) GetCertificate(ctx context.Context, id uint64) (*model.Certif
 func (p *Provider) GetCertificateBySKID(ctx context.Context, skid string) (*model.Certificate, error) {
 	c := new(model.Certificate)
 	err := p.db.QueryRowContext(ctx, `
-			SELECT
-				id,org_id,skid,ikid,serial_number,
-				not_before,no_tafter,
-				subject,issuer,
-				sha256,
-				pem,issuers_pem,
-				profile
+			SELECT `+certificateColumns+`
 			FROM certificates
 			WHERE skid = $1
 			;
-			`, skid).Scan(
-		&c.ID,
-		&c.OrgID,
-		&c.SKID,
-		&c.IKID,
-		&c.SerialNumber,
-		&c.NotBefore,
-		&c.NotAfter,
-		&c.Subject,
-		&c.Issuer,
-		&c.ThumbprintSha256,
-		&c.Pem,
-		&c.IssuersPem,
-		&c.Profile,
-	)
+			`, skid).Scan(certificateFields(c)...)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -152,8 +110,7 @@ func (p *Provider) GetCertificateBySKID(ctx context.Context, skid string) (*mode
 func (p *Provider) GetOrgCertificates(ctx context.Context, orgID uint64) (model.Certificates, error) {
 
 	res, err := p.db.QueryContext(ctx, `
-		SELECT
-			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile
+		SELECT `+certificateColumns+`
 		FROM
 			certificates
 		WHERE org_id = $1
@@ -168,21 +125,7 @@ func (p *Provider) GetOrgCertificates(ctx context.Context, orgID uint64) (model.
 
 	for res.Next() {
 		r := new(model.Certificate)
-		err = res.Scan(
-			&r.ID,
-			&r.OrgID,
-			&r.SKID,
-			&r.IKID,
-			&r.SerialNumber,
-			&r.NotBefore,
-			&r.NotAfter,
-			&r.Subject,
-			&r.Issuer,
-			&r.ThumbprintSha256,
-			&r.Pem,
-			&r.IssuersPem,
-			&r.Profile,
-		)
+		err = res.Scan(certificateFields(r)...)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -194,29 +137,37 @@ func (p *Provider) GetOrgCertificates(ctx context.Context, orgID uint64) (model.
 	return list, nil
 }
 
-// ListCertificates returns list of Certificate info
-func (p *Provider) ListCertificates(ctx context.Context, ikid string, limit int, afterID uint64) (model.Certificates, error) {
+// ListCertificates returns list of Certificate info,
+// by a specified issuer and/or label selector
+func (p *Provider) ListCertificates(ctx context.Context, ikid string, selector model.LabelSelector, limit int, afterID uint64) (model.Certificates, error) {
 	if limit == 0 {
 		limit = 1000
 	}
 	logger.KV(xlog.DEBUG,
 		"ikid", ikid,
+		"selector", selector.String(),
 		"limit", limit,
 		"afterID", afterID,
 	)
 
+	where, args, err := labelSelectorSQL(selector, []interface{}{ikid, afterID, limit})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	res, err := p.db.QueryContext(ctx,
 		`SELECT
-			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,profile
+			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,profile,
+			COALESCE(requester,''),labels
 		FROM
 			certificates
 		WHERE 
-			ikid = $1 AND id > $2
+			($1 = '' OR ikid = $1) AND id > $2`+where+`
 		ORDER BY
 			id ASC
 		LIMIT $3
 		;
-		`, ikid, afterID, limit)
+		`, args...)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -238,6 +189,8 @@ func (p *Provider) ListCertificates(ctx context.Context, ikid string, limit int,
 			&r.Issuer,
 			&r.ThumbprintSha256,
 			&r.Profile,
+			&r.Requester,
+			&r.Labels,
 		)
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -249,3 +202,113 @@ func (p *Provider) ListCertificates(ctx context.Context, ikid string, limit int,
 
 	return list, nil
 }
+
+// UpdateCertificateLabels sets and removes the labels of the certificate
+func (p *Provider) UpdateCertificateLabels(ctx context.Context, id uint64, set model.Labels, remove []string) (*model.Certificate, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer tx.Rollback()
+
+	var labels model.Labels
+	err = tx.QueryRowContext(ctx, `
+		SELECT labels
+		FROM certificates
+		WHERE id = $1
+		FOR UPDATE
+		;`, id).Scan(&labels)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundf("certificate %d", id)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	labels = labels.Update(set, remove)
+	err = labels.Validate()
+	if err != nil {
+		return nil, errors.NewNotValid(err, "invalid labels")
+	}
+
+	res := new(model.Certificate)
+	err = tx.QueryRowContext(ctx, `
+		UPDATE certificates
+			SET labels=$2
+		WHERE id = $1
+		RETURNING `+certificateColumns+`
+		;`, id, labels).Scan(certificateFields(res)...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	logger.Noticef("api=UpdateCertificateLabels, id=%d, labels=%q", id, labels.String())
+
+	res.NotAfter = res.NotAfter.UTC()
+	res.NotBefore = res.NotBefore.UTC()
+	return res, nil
+}
+
+// labelSelectorSQL returns the conditions for the label selector,
+// to be appended to WHERE clause, and the arguments with the selector values
+func labelSelectorSQL(selector model.LabelSelector, args []interface{}) (string, []interface{}, error) {
+	var where strings.Builder
+	for _, r := range selector {
+		var arg interface{}
+		switch r.Operator {
+		case model.LabelEquals, model.LabelNotEquals:
+			js, err := json.Marshal(map[string]string{r.Key: r.Value})
+			if err != nil {
+				return "", nil, errors.Trace(err)
+			}
+			arg = string(js)
+		default:
+			arg = r.Key
+		}
+		args = append(args, arg)
+		n := len(args)
+
+		switch r.Operator {
+		case model.LabelEquals:
+			fmt.Fprintf(&where, " AND COALESCE(labels,'{}'::jsonb) @> $%d::jsonb", n)
+		case model.LabelNotEquals:
+			fmt.Fprintf(&where, " AND NOT (COALESCE(labels,'{}'::jsonb) @> $%d::jsonb)", n)
+		case model.LabelExists:
+			fmt.Fprintf(&where, " AND COALESCE(labels,'{}'::jsonb) ? $%d", n)
+		case model.LabelNotExists:
+			fmt.Fprintf(&where, " AND NOT (COALESCE(labels,'{}'::jsonb) ? $%d)", n)
+		default:
+			return "", nil, errors.NotSupportedf("label operator %q", r.Operator)
+		}
+	}
+	return where.String(), args, nil
+}
+
+func certificateFields(c *model.Certificate) []interface{} {
+	return []interface{}{
+		&c.ID,
+		&c.OrgID,
+		&c.SKID,
+		&c.IKID,
+		&c.SerialNumber,
+		&c.NotBefore,
+		&c.NotAfter,
+		&c.Subject,
+		&c.Issuer,
+		&c.ThumbprintSha256,
+		&c.Pem,
+		&c.IssuersPem,
+		&c.Profile,
+		&c.Requester,
+		&c.ClientIP,
+		&c.RequestID,
+		&c.Request,
+		pq.Array(&c.SAN),
+		&c.Labels,
+	}
+}
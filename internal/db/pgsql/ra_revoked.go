@@ -7,8 +7,11 @@ import (
 	"github.com/ekspand/trusty/internal/db/model"
 	"github.com/go-phorce/dolly/xlog"
 	"github.com/juju/errors"
+	"github.com/lib/pq"
 )
 
+const revokedColumns = certificateColumns + `,revoked_at,reason`
+
 // RegisterRevokedCertificate registers revoked Certificate
 func (p *Provider) RegisterRevokedCertificate(ctx context.Context, revoked *model.RevokedCertificate) (*model.RevokedCertificate, error) {
 	id := revoked.Certificate.ID
@@ -32,36 +35,24 @@ func (p *Provider) RegisterRevokedCertificate(ctx context.Context, revoked *mode
 	res := new(model.RevokedCertificate)
 
 	err = p.db.QueryRowContext(ctx, `
-			INSERT INTO revoked(id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,revoked_at,reason)
-				VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			INSERT INTO revoked(id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,
+				requester,client_ip,request_id,request,san,labels,revoked_at,reason)
+				VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
 			ON CONFLICT (sha256)
 			DO UPDATE
 				SET org_id=$2,issuers_pem=$12
-			RETURNING id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,revoked_at,reason
+			RETURNING `+revokedColumns+`
 			;`, id, crt.OrgID, crt.SKID, crt.IKID, crt.SerialNumber,
 		crt.NotBefore, crt.NotAfter,
 		crt.Subject, crt.Issuer,
 		crt.ThumbprintSha256,
 		crt.Pem, crt.IssuersPem,
 		crt.Profile,
+		crt.Requester, crt.ClientIP, crt.RequestID, crt.Request,
+		pq.Array(crt.SAN), crt.Labels,
 		revoked.RevokedAt,
 		revoked.Reason,
-	).Scan(&res.Certificate.ID,
-		&res.Certificate.OrgID,
-		&res.Certificate.SKID,
-		&res.Certificate.IKID,
-		&res.Certificate.SerialNumber,
-		&res.Certificate.NotBefore,
-		&res.Certificate.NotAfter,
-		&res.Certificate.Subject,
-		&res.Certificate.Issuer,
-		&res.Certificate.ThumbprintSha256,
-		&res.Certificate.Pem,
-		&res.Certificate.IssuersPem,
-		&res.Certificate.Profile,
-		&res.RevokedAt,
-		&res.Reason,
-	)
+	).Scan(revokedFields(res)...)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -88,8 +79,7 @@ func (p *Provider) RemoveRevokedCertificate(ctx context.Context, id uint64) erro
 func (p *Provider) GetOrgRevokedCertificates(ctx context.Context, orgID uint64) (model.RevokedCertificates, error) {
 
 	res, err := p.db.QueryContext(ctx, `
-		SELECT
-		id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,revoked_at,reason
+		SELECT `+revokedColumns+`
 		FROM
 			revoked
 		WHERE org_id = $1
@@ -104,23 +94,7 @@ func (p *Provider) GetOrgRevokedCertificates(ctx context.Context, orgID uint64)
 
 	for res.Next() {
 		r := new(model.RevokedCertificate)
-		err = res.Scan(
-			&r.Certificate.ID,
-			&r.Certificate.OrgID,
-			&r.Certificate.SKID,
-			&r.Certificate.IKID,
-			&r.Certificate.SerialNumber,
-			&r.Certificate.NotBefore,
-			&r.Certificate.NotAfter,
-			&r.Certificate.Subject,
-			&r.Certificate.Issuer,
-			&r.Certificate.ThumbprintSha256,
-			&r.Certificate.Pem,
-			&r.Certificate.IssuersPem,
-			&r.Certificate.Profile,
-			&r.RevokedAt,
-			&r.Reason,
-		)
+		err = res.Scan(revokedFields(r)...)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -133,30 +107,38 @@ func (p *Provider) GetOrgRevokedCertificates(ctx context.Context, orgID uint64)
 	return list, nil
 }
 
-// ListRevokedCertificates returns revoked certificates by a specified issuer
-func (p *Provider) ListRevokedCertificates(ctx context.Context, ikid string, limit int, afterID uint64) (model.RevokedCertificates, error) {
+// ListRevokedCertificates returns revoked certificates,
+// by a specified issuer and/or label selector
+func (p *Provider) ListRevokedCertificates(ctx context.Context, ikid string, selector model.LabelSelector, limit int, afterID uint64) (model.RevokedCertificates, error) {
 	if limit == 0 {
 		limit = 1000
 	}
 
 	logger.KV(xlog.DEBUG,
 		"ikid", ikid,
+		"selector", selector.String(),
 		"limit", limit,
 		"afterID", afterID,
 	)
 
+	where, args, err := labelSelectorSQL(selector, []interface{}{ikid, afterID, limit})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	res, err := p.db.QueryContext(ctx,
 		`SELECT
-			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,profile,revoked_at,reason
+			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,profile,
+			COALESCE(requester,''),labels,revoked_at,reason
 		FROM
 			revoked
 		WHERE 
-			ikid = $1 AND id > $2
+			($1 = '' OR ikid = $1) AND id > $2`+where+`
 		ORDER BY
 			id ASC
 		LIMIT $3
 		;
-		`, ikid, afterID, limit)
+		`, args...)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -178,6 +160,8 @@ func (p *Provider) ListRevokedCertificates(ctx context.Context, ikid string, lim
 			&r.Certificate.Issuer,
 			&r.Certificate.ThumbprintSha256,
 			&r.Certificate.Profile,
+			&r.Certificate.Requester,
+			&r.Certificate.Labels,
 			&r.RevokedAt,
 			&r.Reason,
 		)
@@ -232,3 +216,7 @@ func (p *Provider) RevokeCertificate(ctx context.Context, crt *model.Certificate
 	}
 	return revoked, nil
 }
+
+func revokedFields(r *model.RevokedCertificate) []interface{} {
+	return append(certificateFields(&r.Certificate), &r.RevokedAt, &r.Reason)
+}
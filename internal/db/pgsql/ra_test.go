@@ -9,6 +9,7 @@ import (
 	"github.com/ekspand/trusty/internal/db/model"
 	"github.com/go-phorce/dolly/algorithms/guid"
 	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/juju/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -96,12 +97,24 @@ func TestRegisterCertificate(t *testing.T) {
 		Pem:              "pem",
 		IssuersPem:       "ipem",
 		Profile:          "client",
+		Requester:        email1,
+		ClientIP:         "10.0.0.1",
+		RequestID:        guid.MustCreate(),
+		Request:          "csr",
+		SAN:              []string{"trusty.com"},
+		Labels:           model.Labels{"team": "security"},
 	}
 
 	r, err := provider.RegisterCertificate(ctx, rc)
 	require.NoError(t, err)
 	require.NotNil(t, r)
 	defer provider.RemoveCertificate(ctx, r.ID)
+	assert.Equal(t, rc.Requester, r.Requester)
+	assert.Equal(t, rc.ClientIP, r.ClientIP)
+	assert.Equal(t, rc.RequestID, r.RequestID)
+	assert.Equal(t, rc.Request, r.Request)
+	assert.Equal(t, rc.SAN, r.SAN)
+	assert.Equal(t, rc.Labels, r.Labels)
 
 	assert.Equal(t, rc.OrgID, r.OrgID)
 	assert.Equal(t, rc.SKID, r.SKID)
@@ -127,14 +140,14 @@ func TestRegisterCertificate(t *testing.T) {
 	require.NotNil(t, r3)
 	assert.Equal(t, *r, *r3)
 
-	list2, err := provider.ListCertificates(ctx, r3.IKID, 100, 0)
+	list2, err := provider.ListCertificates(ctx, r3.IKID, nil, 100, 0)
 	require.NoError(t, err)
 	c := list2.Find(r.ID)
 	require.NotNil(t, c)
 	assert.NotEqual(t, *r, *c)
 
 	last := list2[len(list2)-1]
-	list2, err = provider.ListCertificates(ctx, r3.IKID, 100, last.ID)
+	list2, err = provider.ListCertificates(ctx, r3.IKID, nil, 100, last.ID)
 	require.NoError(t, err)
 	assert.Empty(t, list2)
 
@@ -148,10 +161,41 @@ func TestRegisterCertificate(t *testing.T) {
 	require.NotNil(t, r4)
 	assert.Equal(t, *r, *r4)
 
+	selector, err := model.ParseLabelSelector("team=security,!deprecated")
+	require.NoError(t, err)
+	list2, err = provider.ListCertificates(ctx, "", selector, 100, r.ID-1)
+	require.NoError(t, err)
+	require.NotNil(t, list2.Find(r.ID))
+	assert.Equal(t, rc.Labels, list2.Find(r.ID).Labels)
+	assert.Equal(t, rc.Requester, list2.Find(r.ID).Requester)
+
+	r4, err = provider.UpdateCertificateLabels(ctx, r.ID, model.Labels{"deprecated": "true", "env": "prod"}, []string{"team"})
+	require.NoError(t, err)
+	assert.Equal(t, model.Labels{"deprecated": "true", "env": "prod"}, r4.Labels)
+	assert.Equal(t, r.Pem, r4.Pem)
+
+	list2, err = provider.ListCertificates(ctx, r3.IKID, selector, 100, 0)
+	require.NoError(t, err)
+	assert.Nil(t, list2.Find(r.ID))
+
+	selector, err = model.ParseLabelSelector("env=prod,deprecated,team!=security")
+	require.NoError(t, err)
+	list2, err = provider.ListCertificates(ctx, r3.IKID, selector, 100, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, list2.Find(r.ID))
+
+	_, err = provider.UpdateCertificateLabels(ctx, 1, model.Labels{"env": "prod"}, nil)
+	assert.True(t, errors.IsNotFound(err))
+
 	revoked, err := provider.RevokeCertificate(ctx, r4, time.Now(), 0)
 	require.NoError(t, err)
 	assert.Equal(t, revoked.Certificate, *r4)
 
+	rlist, err := provider.ListRevokedCertificates(ctx, "", selector, 100, r.ID-1)
+	require.NoError(t, err)
+	require.NotEmpty(t, rlist)
+	assert.Equal(t, r4.Labels, rlist[0].Certificate.Labels)
+
 	_, err = provider.GetCertificate(ctx, r2.ID)
 	require.Error(t, err)
 	assert.Equal(t, "sql: no rows in result set", err.Error())
@@ -239,7 +283,7 @@ func TestRegisterRevokedCertificate(t *testing.T) {
 	require.NotNil(t, r3)
 	assert.Equal(t, *mr, *r3)
 
-	list, err = provider.ListRevokedCertificates(ctx, r.IKID, 0, 0)
+	list, err = provider.ListRevokedCertificates(ctx, r.IKID, nil, 0, 0)
 	require.NoError(t, err)
 	r4 := list.Find(r.ID)
 	require.NotNil(t, r4)
@@ -306,26 +350,26 @@ func TestListCertificate(t *testing.T) {
 		defer provider.RemoveCertificate(ctx, r.ID)
 	}
 
-	list, err := provider.ListCertificates(ctx, ikid, 1000, 0)
+	list, err := provider.ListCertificates(ctx, ikid, nil, 1000, 0)
 	require.NoError(t, err)
 	require.Len(t, list, count)
 
 	first := list[0].ID
 	last := list[count-1].ID
 
-	list2, err := provider.ListCertificates(ctx, ikid, 1000, first)
+	list2, err := provider.ListCertificates(ctx, ikid, nil, 1000, first)
 	require.NoError(t, err)
 	require.Len(t, list2, count-1)
 	assert.Nil(t, list2.Find(first))
 
-	list3, err := provider.ListCertificates(ctx, ikid, 1000, last)
+	list3, err := provider.ListCertificates(ctx, ikid, nil, 1000, last)
 	require.NoError(t, err)
 	require.Len(t, list3, 0)
 
 	last = uint64(0)
 	bulk := make([]*model.Certificate, 0, count)
 	for {
-		list3, err = provider.ListCertificates(ctx, ikid, 5, last)
+		list3, err = provider.ListCertificates(ctx, ikid, nil, 5, last)
 		require.NoError(t, err)
 		if len(list3) == 0 {
 			break
@@ -0,0 +1,145 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/juju/errors"
+	"github.com/lib/pq"
+)
+
+// CreateRoleBinding binds the role to the subject,
+// the existing binding is returned if the role is already bound
+func (p *Provider) CreateRoleBinding(ctx context.Context, b *model.RoleBinding) (*model.RoleBinding, error) {
+	id, err := p.NextID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = model.Validate(b)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	logger.Debugf("role=%s, subject=%s", b.Role, b.Subject)
+
+	res := new(model.RoleBinding)
+	err = p.db.QueryRowContext(ctx, `
+			INSERT INTO role_bindings(id,role,subject,created_by,created_at)
+				VALUES($1, $2, $3, $4, $5)
+			ON CONFLICT (role,subject)
+			DO UPDATE
+				SET role=$2
+			RETURNING id,role,subject,created_by,created_at
+			;`, id, b.Role, b.Subject, b.CreatedBy, time.Now().UTC(),
+	).Scan(roleBindingFields(res)...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	res.CreatedAt = res.CreatedAt.UTC()
+	return res, nil
+}
+
+// RemoveRoleBinding deletes the role binding
+func (p *Provider) RemoveRoleBinding(ctx context.Context, id uint64) (*model.RoleBinding, error) {
+	res := new(model.RoleBinding)
+	err := p.db.QueryRowContext(ctx, `
+			DELETE FROM role_bindings
+			WHERE id=$1
+			RETURNING id,role,subject,created_by,created_at
+			;`, id,
+	).Scan(roleBindingFields(res)...)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundf("role binding")
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	res.CreatedAt = res.CreatedAt.UTC()
+	logger.Noticef("api=RemoveRoleBinding, id=%d, role=%s, subject=%s", id, res.Role, res.Subject)
+	return res, nil
+}
+
+// GetRoleBindings returns all role bindings
+func (p *Provider) GetRoleBindings(ctx context.Context) ([]*model.RoleBinding, error) {
+	res, err := p.db.QueryContext(ctx, `
+		SELECT id,role,subject,created_by,created_at
+		FROM role_bindings
+		ORDER BY role,subject
+		;`)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return scanRoleBindings(res)
+}
+
+// GetSubjectsRoleBindings returns role bindings of the subjects
+func (p *Provider) GetSubjectsRoleBindings(ctx context.Context, subjects []string) ([]*model.RoleBinding, error) {
+	res, err := p.db.QueryContext(ctx, `
+		SELECT id,role,subject,created_by,created_at
+		FROM role_bindings
+		WHERE subject = ANY($1)
+		ORDER BY role,subject
+		;`, pq.Array(subjects))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return scanRoleBindings(res)
+}
+
+// GetUserOrgRoles returns roles of the user in orgs, in ${org}/${role} format,
+// where ${org} is the login of the org
+func (p *Provider) GetUserOrgRoles(ctx context.Context, userID uint64) ([]string, error) {
+	res, err := p.db.QueryContext(ctx, `
+		SELECT orgs.login, orgmembers.role
+		FROM orgmembers
+		JOIN orgs ON orgs.id = orgmembers.org_id
+		WHERE orgmembers.user_id = $1 AND orgmembers.role IS NOT NULL
+		ORDER BY orgs.login
+		;`, userID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer res.Close()
+
+	list := make([]string, 0, 10)
+	for res.Next() {
+		var org, role string
+		err = res.Scan(&org, &role)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		list = append(list, org+"/"+role)
+	}
+
+	return list, nil
+}
+
+func scanRoleBindings(res *sql.Rows) ([]*model.RoleBinding, error) {
+	defer res.Close()
+
+	list := make([]*model.RoleBinding, 0, 10)
+	for res.Next() {
+		b := new(model.RoleBinding)
+		err := res.Scan(roleBindingFields(b)...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		b.CreatedAt = b.CreatedAt.UTC()
+		list = append(list, b)
+	}
+
+	return list, nil
+}
+
+func roleBindingFields(b *model.RoleBinding) []interface{} {
+	return []interface{}{
+		&b.ID,
+		&b.Role,
+		&b.Subject,
+		&b.CreatedBy,
+		&b.CreatedAt,
+	}
+}
@@ -0,0 +1,104 @@
+package pgsql_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleBindings(t *testing.T) {
+	id, err := provider.NextID()
+	require.NoError(t, err)
+
+	subject := fmt.Sprintf("%srb%d@trusty.com", v1.RoleBindingUserPrefix, id)
+	group := fmt.Sprintf("%s%s", v1.RoleBindingGroupPrefix, v1.ProviderGroup("okta", fmt.Sprintf("rb%d", id)))
+
+	b1, err := provider.CreateRoleBinding(ctx, &model.RoleBinding{
+		Role:      "trusty-admin",
+		Subject:   subject,
+		CreatedBy: id,
+	})
+	require.NoError(t, err)
+	defer provider.RemoveRoleBinding(ctx, b1.ID)
+	assert.Equal(t, "trusty-admin", b1.Role)
+	assert.Equal(t, subject, b1.Subject)
+	assert.Equal(t, id, b1.CreatedBy)
+
+	b2, err := provider.CreateRoleBinding(ctx, &model.RoleBinding{
+		Role:      "trusty-admin",
+		Subject:   subject,
+		CreatedBy: id,
+	})
+	require.NoError(t, err, "binding must be idempotent")
+	assert.Equal(t, b1.ID, b2.ID)
+
+	b3, err := provider.CreateRoleBinding(ctx, &model.RoleBinding{
+		Role:      "ca-operator",
+		Subject:   group,
+		CreatedBy: id,
+	})
+	require.NoError(t, err)
+	defer provider.RemoveRoleBinding(ctx, b3.ID)
+
+	_, err = provider.CreateRoleBinding(ctx, &model.RoleBinding{Role: "trusty-admin"})
+	require.Error(t, err)
+
+	list, err := provider.GetSubjectsRoleBindings(ctx, []string{subject, group, "unknown"})
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	list, err = provider.GetRoleBindings(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, len(list), 2)
+
+	removed, err := provider.RemoveRoleBinding(ctx, b3.ID)
+	require.NoError(t, err)
+	assert.Equal(t, group, removed.Subject)
+
+	_, err = provider.RemoveRoleBinding(ctx, b3.ID)
+	assert.True(t, errors.IsNotFound(err))
+
+	list, err = provider.GetSubjectsRoleBindings(ctx, []string{group})
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestGetUserOrgRoles(t *testing.T) {
+	id, err := provider.NextID()
+	require.NoError(t, err)
+
+	login := fmt.Sprintf("orgroles%d", id)
+	org, err := provider.UpdateOrg(ctx, &model.Organization{
+		ExternalID: id,
+		Provider:   v1.ProviderGithub,
+		Name:       "Org Roles",
+		Login:      login,
+		Email:      login + "@trusty.com",
+		Type:       "Organization",
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	})
+	require.NoError(t, err)
+	defer provider.RemoveOrg(ctx, org.ID)
+
+	user, err := provider.LoginUser(ctx, &model.User{Login: login, Email: login + "@trusty.com", Name: login})
+	require.NoError(t, err)
+
+	roles, err := provider.GetUserOrgRoles(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, roles)
+
+	_, err = provider.AddOrgMember(ctx, org.ID, user.ID, "admin", v1.ProviderGithub)
+	require.NoError(t, err)
+	defer provider.RemoveOrgMembers(ctx, org.ID, true)
+
+	roles, err = provider.GetUserOrgRoles(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{login + "/admin"}, roles)
+}
@@ -0,0 +1,259 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/juju/errors"
+)
+
+// CreateServiceAccount creates a service account for the org
+func (p *Provider) CreateServiceAccount(ctx context.Context, sa *model.ServiceAccount) (*model.ServiceAccount, error) {
+	id, err := p.NextID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = model.Validate(sa)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	logger.Debugf("org_id=%d, name=%s", sa.OrgID, sa.Name)
+
+	res := new(model.ServiceAccount)
+	err = p.db.QueryRowContext(ctx, `
+			INSERT INTO service_accounts(id,org_id,name,description,created_by,created_at)
+				VALUES($1, $2, $3, $4, $5, $6)
+			RETURNING id,org_id,name,description,created_by,created_at
+			;`, id, sa.OrgID, sa.Name, sa.Description, sa.CreatedBy, time.Now().UTC(),
+	).Scan(&res.ID,
+		&res.OrgID,
+		&res.Name,
+		&res.Description,
+		&res.CreatedBy,
+		&res.CreatedAt,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	res.CreatedAt = res.CreatedAt.UTC()
+	return res, nil
+}
+
+// GetServiceAccount returns the service account
+func (p *Provider) GetServiceAccount(ctx context.Context, id uint64) (*model.ServiceAccount, error) {
+	res := new(model.ServiceAccount)
+	err := p.db.QueryRowContext(ctx, `
+		SELECT id,org_id,name,description,created_by,created_at
+		FROM service_accounts
+		WHERE id=$1
+		;`, id,
+	).Scan(&res.ID,
+		&res.OrgID,
+		&res.Name,
+		&res.Description,
+		&res.CreatedBy,
+		&res.CreatedAt,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	res.CreatedAt = res.CreatedAt.UTC()
+	return res, nil
+}
+
+// GetOrgServiceAccounts returns the service accounts of the org
+func (p *Provider) GetOrgServiceAccounts(ctx context.Context, orgID uint64) ([]*model.ServiceAccount, error) {
+	res, err := p.db.QueryContext(ctx, `
+		SELECT id,org_id,name,description,created_by,created_at
+		FROM service_accounts
+		WHERE org_id=$1
+		ORDER BY name
+		;`, orgID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer res.Close()
+
+	list := make([]*model.ServiceAccount, 0, 10)
+	for res.Next() {
+		sa := new(model.ServiceAccount)
+		err = res.Scan(
+			&sa.ID,
+			&sa.OrgID,
+			&sa.Name,
+			&sa.Description,
+			&sa.CreatedBy,
+			&sa.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sa.CreatedAt = sa.CreatedAt.UTC()
+		list = append(list, sa)
+	}
+
+	return list, nil
+}
+
+// RemoveServiceAccount deletes the service account and its tokens
+func (p *Provider) RemoveServiceAccount(ctx context.Context, id uint64) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM api_tokens WHERE service_account_id=$1;`, id)
+	if err != nil {
+		logger.Errorf("api=RemoveServiceAccount, err=[%s]", errors.Details(err))
+		return errors.Trace(err)
+	}
+	_, err = p.db.ExecContext(ctx, `DELETE FROM service_accounts WHERE id=$1;`, id)
+	if err != nil {
+		logger.Errorf("api=RemoveServiceAccount, err=[%s]", errors.Details(err))
+		return errors.Trace(err)
+	}
+	logger.Noticef("api=RemoveServiceAccount, id=%d", id)
+
+	return nil
+}
+
+// CreateAPIToken creates API token for the service account
+func (p *Provider) CreateAPIToken(ctx context.Context, token *model.APIToken) (*model.APIToken, error) {
+	id, err := p.NextID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = model.Validate(token)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	logger.Debugf("service_account_id=%d, name=%s", token.ServiceAccountID, token.Name)
+
+	res := new(model.APIToken)
+	err = p.db.QueryRowContext(ctx, `
+			INSERT INTO api_tokens(id,service_account_id,name,token_hash,created_by,created_at,expires_at)
+				VALUES($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id,service_account_id,name,token_hash,created_by,created_at,expires_at,last_used_at,revoked_at
+			;`, id, token.ServiceAccountID, token.Name, token.TokenHash, token.CreatedBy,
+		time.Now().UTC(), token.ExpiresAt,
+	).Scan(apiTokenFields(res)...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return utcAPIToken(res), nil
+}
+
+// GetServiceAccountTokens returns the tokens of the service account
+func (p *Provider) GetServiceAccountTokens(ctx context.Context, serviceAccountID uint64) ([]*model.APIToken, error) {
+	res, err := p.db.QueryContext(ctx, `
+		SELECT id,service_account_id,name,token_hash,created_by,created_at,expires_at,last_used_at,revoked_at
+		FROM api_tokens
+		WHERE service_account_id=$1
+		ORDER BY name
+		;`, serviceAccountID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer res.Close()
+
+	list := make([]*model.APIToken, 0, 10)
+	for res.Next() {
+		t := new(model.APIToken)
+		err = res.Scan(apiTokenFields(t)...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		list = append(list, utcAPIToken(t))
+	}
+
+	return list, nil
+}
+
+// RevokeAPIToken marks the token of the service account as revoked
+func (p *Provider) RevokeAPIToken(ctx context.Context, serviceAccountID, id uint64, at time.Time) (*model.APIToken, error) {
+	res := new(model.APIToken)
+	err := p.db.QueryRowContext(ctx, `
+			UPDATE api_tokens
+				SET revoked_at=COALESCE(revoked_at,$3)
+			WHERE id=$1 AND service_account_id=$2
+			RETURNING id,service_account_id,name,token_hash,created_by,created_at,expires_at,last_used_at,revoked_at
+			;`, id, serviceAccountID, at.UTC(),
+	).Scan(apiTokenFields(res)...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Noticef("api=RevokeAPIToken, service_account_id=%d, id=%d", serviceAccountID, id)
+	return utcAPIToken(res), nil
+}
+
+// apiTokenUsageInterval specifies how often the time the token was last used
+// is updated, to avoid writing to DB on every request
+const apiTokenUsageInterval = time.Minute
+
+// UseAPIToken returns the active token by its hash and the service account,
+// and updates the time the token was last used, if it's older than a minute.
+// NotFound error is returned, if the token does not exist, expired or revoked.
+func (p *Provider) UseAPIToken(ctx context.Context, tokenHash string, at time.Time) (*model.APIToken, *model.ServiceAccount, error) {
+	at = at.UTC()
+	res := new(model.APIToken)
+	err := p.db.QueryRowContext(ctx, `
+			SELECT id,service_account_id,name,token_hash,created_by,created_at,expires_at,last_used_at,revoked_at
+			FROM api_tokens
+			WHERE token_hash=$1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2)
+			;`, tokenHash, at,
+	).Scan(apiTokenFields(res)...)
+	if err == sql.ErrNoRows {
+		return nil, nil, errors.NotFoundf("API token")
+	}
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	if !res.LastUsedAt.Valid || at.Sub(res.LastUsedAt.Time) >= apiTokenUsageInterval {
+		_, err = p.db.ExecContext(ctx, `
+			UPDATE api_tokens
+				SET last_used_at=$2
+			WHERE id=$1 AND (last_used_at IS NULL OR last_used_at < $2)
+			;`, res.ID, at,
+		)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		res.LastUsedAt = sql.NullTime{Time: at, Valid: true}
+	}
+
+	sa, err := p.GetServiceAccount(ctx, res.ServiceAccountID)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return utcAPIToken(res), sa, nil
+}
+
+func apiTokenFields(t *model.APIToken) []interface{} {
+	return []interface{}{
+		&t.ID,
+		&t.ServiceAccountID,
+		&t.Name,
+		&t.TokenHash,
+		&t.CreatedBy,
+		&t.CreatedAt,
+		&t.ExpiresAt,
+		&t.LastUsedAt,
+		&t.RevokedAt,
+	}
+}
+
+func utcAPIToken(t *model.APIToken) *model.APIToken {
+	t.CreatedAt = t.CreatedAt.UTC()
+	if t.ExpiresAt.Valid {
+		t.ExpiresAt.Time = t.ExpiresAt.Time.UTC()
+	}
+	if t.LastUsedAt.Valid {
+		t.LastUsedAt.Time = t.LastUsedAt.Time.UTC()
+	}
+	if t.RevokedAt.Valid {
+		t.RevokedAt.Time = t.RevokedAt.Time.UTC()
+	}
+	return t
+}
@@ -0,0 +1,128 @@
+package pgsql_test
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceAccounts(t *testing.T) {
+	id, err := provider.NextID()
+	require.NoError(t, err)
+
+	login := fmt.Sprintf("sa%d", id)
+	org, err := provider.UpdateOrg(ctx, &model.Organization{
+		ExternalID: id,
+		Provider:   v1.ProviderGithub,
+		Name:       login,
+		Login:      login,
+		Email:      login + "@trusty.com",
+		Type:       "Organization",
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	})
+	require.NoError(t, err)
+	defer provider.RemoveOrg(ctx, org.ID)
+
+	sa, err := provider.CreateServiceAccount(ctx, &model.ServiceAccount{
+		OrgID:       org.ID,
+		Name:        "ci",
+		Description: "CI pipeline",
+		CreatedBy:   id,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, org.ID, sa.OrgID)
+	assert.Equal(t, "ci", sa.Name)
+	assert.Equal(t, "CI pipeline", sa.Description)
+	assert.Equal(t, id, sa.CreatedBy)
+
+	_, err = provider.CreateServiceAccount(ctx, &model.ServiceAccount{OrgID: org.ID, Name: "ci"})
+	require.Error(t, err, "name must be unique in the org")
+
+	sa2, err := provider.GetServiceAccount(ctx, sa.ID)
+	require.NoError(t, err)
+	assert.Equal(t, *sa, *sa2)
+
+	list, err := provider.GetOrgServiceAccounts(ctx, org.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, *sa, *list[0])
+
+	secret, err := model.NewAPITokenSecret()
+	require.NoError(t, err)
+	hash := model.HashAPIToken(secret)
+
+	tk, err := provider.CreateAPIToken(ctx, &model.APIToken{
+		ServiceAccountID: sa.ID,
+		Name:             "deploy",
+		TokenHash:        hash,
+		CreatedBy:        id,
+		ExpiresAt:        sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, hash, tk.TokenHash)
+	assert.True(t, tk.ExpiresAt.Valid)
+	assert.False(t, tk.LastUsedAt.Valid)
+
+	expiredSecret, err := model.NewAPITokenSecret()
+	require.NoError(t, err)
+	_, err = provider.CreateAPIToken(ctx, &model.APIToken{
+		ServiceAccountID: sa.ID,
+		Name:             "expired",
+		TokenHash:        model.HashAPIToken(expiredSecret),
+		ExpiresAt:        sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+
+	tokens, err := provider.GetServiceAccountTokens(ctx, sa.ID)
+	require.NoError(t, err)
+	assert.Len(t, tokens, 2)
+
+	used, usedSA, err := provider.UseAPIToken(ctx, hash, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, tk.ID, used.ID)
+	assert.True(t, used.LastUsedAt.Valid)
+	assert.Equal(t, sa.ID, usedSA.ID)
+
+	// last_used_at is not updated within a minute
+	usedAgain, _, err := provider.UseAPIToken(ctx, hash, used.LastUsedAt.Time.Add(10*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, used.LastUsedAt.Time.Unix(), usedAgain.LastUsedAt.Time.Unix())
+
+	usedLater, _, err := provider.UseAPIToken(ctx, hash, used.LastUsedAt.Time.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.True(t, usedLater.LastUsedAt.Time.After(used.LastUsedAt.Time))
+
+	_, _, err = provider.UseAPIToken(ctx, model.HashAPIToken(expiredSecret), time.Now())
+	assert.True(t, errors.IsNotFound(err))
+
+	_, _, err = provider.UseAPIToken(ctx, model.HashAPIToken("trusty_unknown"), time.Now())
+	assert.True(t, errors.IsNotFound(err))
+
+	_, err = provider.RevokeAPIToken(ctx, sa.ID+1, tk.ID, time.Now())
+	require.Error(t, err)
+
+	revoked, err := provider.RevokeAPIToken(ctx, sa.ID, tk.ID, time.Now())
+	require.NoError(t, err)
+	assert.True(t, revoked.IsRevoked())
+
+	_, _, err = provider.UseAPIToken(ctx, hash, time.Now())
+	assert.True(t, errors.IsNotFound(err))
+
+	err = provider.RemoveServiceAccount(ctx, sa.ID)
+	require.NoError(t, err)
+
+	_, err = provider.GetServiceAccount(ctx, sa.ID)
+	require.Error(t, err)
+
+	tokens, err = provider.GetServiceAccountTokens(ctx, sa.ID)
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+}
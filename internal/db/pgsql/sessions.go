@@ -0,0 +1,200 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/juju/errors"
+	"github.com/lib/pq"
+)
+
+// CreateSession creates a login session of the user
+func (p *Provider) CreateSession(ctx context.Context, session *model.Session) (*model.Session, error) {
+	id, err := p.NextID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = model.Validate(session)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	logger.Debugf("user_id=%d, device_id=%s", session.UserID, session.DeviceID)
+
+	issuedAt := session.IssuedAt
+	if issuedAt.IsZero() {
+		issuedAt = time.Now()
+	}
+
+	res := new(model.Session)
+	err = p.db.QueryRowContext(ctx, `
+			INSERT INTO sessions(id,user_id,device_id,issued_at,expires_at,groups)
+				VALUES($1, $2, $3, $4, $5, $6)
+			RETURNING id,user_id,device_id,issued_at,expires_at,last_seen_at,revoked_at,groups
+			;`, id, session.UserID, session.DeviceID, issuedAt.UTC(), session.ExpiresAt.UTC(),
+		pq.Array(session.Groups),
+	).Scan(sessionFields(res)...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return utcSession(res), nil
+}
+
+// RefreshSession extends expiration of the active session,
+// the expiration is limited by model.MaxSessionLifetime since the session was issued.
+// NotFound error is returned, if the session does not exist, expired or revoked.
+func (p *Provider) RefreshSession(ctx context.Context, id uint64, expiresAt time.Time) (*model.Session, error) {
+	res := new(model.Session)
+	err := p.db.QueryRowContext(ctx, `
+			UPDATE sessions
+				SET expires_at=LEAST(GREATEST(expires_at,$2), issued_at + $3 * interval '1 second')
+			WHERE id=$1 AND revoked_at IS NULL AND expires_at > $4
+			RETURNING id,user_id,device_id,issued_at,expires_at,last_seen_at,revoked_at,groups
+			;`, id, expiresAt.UTC(), int64(model.MaxSessionLifetime/time.Second), time.Now().UTC(),
+	).Scan(sessionFields(res)...)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundf("session")
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return utcSession(res), nil
+}
+
+// GetSession returns the session
+func (p *Provider) GetSession(ctx context.Context, id uint64) (*model.Session, error) {
+	res := new(model.Session)
+	err := p.db.QueryRowContext(ctx, `
+		SELECT id,user_id,device_id,issued_at,expires_at,last_seen_at,revoked_at,groups
+		FROM sessions
+		WHERE id=$1
+		;`, id,
+	).Scan(sessionFields(res)...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return utcSession(res), nil
+}
+
+// GetUserSessions returns active sessions of the user
+func (p *Provider) GetUserSessions(ctx context.Context, userID uint64) ([]*model.Session, error) {
+	res, err := p.db.QueryContext(ctx, `
+		SELECT id,user_id,device_id,issued_at,expires_at,last_seen_at,revoked_at,groups
+		FROM sessions
+		WHERE user_id=$1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY issued_at DESC
+		;`, userID, time.Now().UTC())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer res.Close()
+
+	list := make([]*model.Session, 0, 10)
+	for res.Next() {
+		s := new(model.Session)
+		err = res.Scan(sessionFields(s)...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		list = append(list, utcSession(s))
+	}
+
+	return list, nil
+}
+
+// RevokeSession marks the session of the user as revoked.
+// NotFound error is returned, if the session of the user does not exist.
+func (p *Provider) RevokeSession(ctx context.Context, userID, id uint64, at time.Time) (*model.Session, error) {
+	res := new(model.Session)
+	err := p.db.QueryRowContext(ctx, `
+			UPDATE sessions
+				SET revoked_at=COALESCE(revoked_at,$3)
+			WHERE id=$1 AND user_id=$2
+			RETURNING id,user_id,device_id,issued_at,expires_at,last_seen_at,revoked_at,groups
+			;`, id, userID, at.UTC(),
+	).Scan(sessionFields(res)...)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundf("session")
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Noticef("api=RevokeSession, user_id=%d, id=%d", userID, id)
+	return utcSession(res), nil
+}
+
+// RevokeUserSessions marks all active sessions of the user as revoked,
+// and returns the revoked sessions
+func (p *Provider) RevokeUserSessions(ctx context.Context, userID uint64, at time.Time) ([]*model.Session, error) {
+	res, err := p.db.QueryContext(ctx, `
+			UPDATE sessions
+				SET revoked_at=$2
+			WHERE user_id=$1 AND revoked_at IS NULL AND expires_at > $2
+			RETURNING id,user_id,device_id,issued_at,expires_at,last_seen_at,revoked_at,groups
+			;`, userID, at.UTC())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer res.Close()
+
+	list := make([]*model.Session, 0, 10)
+	for res.Next() {
+		s := new(model.Session)
+		err = res.Scan(sessionFields(s)...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		list = append(list, utcSession(s))
+	}
+	logger.Noticef("api=RevokeUserSessions, user_id=%d, revoked=%d", userID, len(list))
+
+	return list, nil
+}
+
+// UseSession returns the active session, and updates the time the session was last seen.
+// NotFound error is returned, if the session does not exist, expired or revoked.
+func (p *Provider) UseSession(ctx context.Context, id uint64, at time.Time) (*model.Session, error) {
+	res := new(model.Session)
+	err := p.db.QueryRowContext(ctx, `
+			UPDATE sessions
+				SET last_seen_at=$2
+			WHERE id=$1 AND revoked_at IS NULL AND expires_at > $2
+			RETURNING id,user_id,device_id,issued_at,expires_at,last_seen_at,revoked_at,groups
+			;`, id, at.UTC(),
+	).Scan(sessionFields(res)...)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundf("session")
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return utcSession(res), nil
+}
+
+func sessionFields(s *model.Session) []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.UserID,
+		&s.DeviceID,
+		&s.IssuedAt,
+		&s.ExpiresAt,
+		&s.LastSeenAt,
+		&s.RevokedAt,
+		pq.Array(&s.Groups),
+	}
+}
+
+func utcSession(s *model.Session) *model.Session {
+	s.IssuedAt = s.IssuedAt.UTC()
+	s.ExpiresAt = s.ExpiresAt.UTC()
+	if s.LastSeenAt.Valid {
+		s.LastSeenAt.Time = s.LastSeenAt.Time.UTC()
+	}
+	if s.RevokedAt.Valid {
+		s.RevokedAt.Time = s.RevokedAt.Time.UTC()
+	}
+	return s
+}
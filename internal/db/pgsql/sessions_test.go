@@ -0,0 +1,102 @@
+package pgsql_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessions(t *testing.T) {
+	id, err := provider.NextID()
+	require.NoError(t, err)
+
+	login := fmt.Sprintf("session%d", id)
+	email := login + "@trusty.com"
+	user, err := provider.LoginUser(ctx, &model.User{Login: login, Email: email, Name: email})
+	require.NoError(t, err)
+
+	now := time.Now()
+	s1, err := provider.CreateSession(ctx, &model.Session{
+		UserID:    user.ID,
+		DeviceID:  "laptop",
+		ExpiresAt: now.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, s1.UserID)
+	assert.Equal(t, "laptop", s1.DeviceID)
+	assert.False(t, s1.LastSeenAt.Valid)
+	assert.False(t, s1.RevokedAt.Valid)
+
+	s2, err := provider.CreateSession(ctx, &model.Session{
+		UserID:    user.ID,
+		DeviceID:  "phone",
+		ExpiresAt: now.Add(time.Hour),
+		Groups:    []string{"admins", "devs"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admins", "devs"}, s2.Groups)
+	assert.Empty(t, s1.Groups)
+
+	expired, err := provider.CreateSession(ctx, &model.Session{
+		UserID:    user.ID,
+		DeviceID:  "expired",
+		IssuedAt:  now.Add(-2 * time.Hour),
+		ExpiresAt: now.Add(-time.Hour),
+	})
+	require.NoError(t, err)
+
+	list, err := provider.GetUserSessions(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Len(t, list, 2, "expired sessions must not be returned")
+
+	refreshed, err := provider.RefreshSession(ctx, s1.ID, now.Add(8*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, refreshed.ExpiresAt.After(s1.ExpiresAt))
+
+	// the session can not be refreshed beyond the maximum lifetime
+	refreshed, err = provider.RefreshSession(ctx, s1.ID, now.Add(2*model.MaxSessionLifetime))
+	require.NoError(t, err)
+	assert.Equal(t, s1.IssuedAt.Add(model.MaxSessionLifetime).Unix(), refreshed.ExpiresAt.Unix())
+
+	// the expired session can not be refreshed
+	_, err = provider.RefreshSession(ctx, expired.ID, now.Add(8*time.Hour))
+	assert.True(t, errors.IsNotFound(err))
+
+	used, err := provider.UseSession(ctx, s1.ID, time.Now())
+	require.NoError(t, err)
+	assert.True(t, used.LastSeenAt.Valid)
+
+	got, err := provider.GetSession(ctx, s1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, *used, *got)
+
+	_, err = provider.RevokeSession(ctx, user.ID+1, s1.ID, time.Now())
+	assert.True(t, errors.IsNotFound(err), "session of another user must not be revoked")
+
+	revoked, err := provider.RevokeSession(ctx, user.ID, s1.ID, time.Now())
+	require.NoError(t, err)
+	assert.True(t, revoked.RevokedAt.Valid)
+
+	_, err = provider.UseSession(ctx, s1.ID, time.Now())
+	assert.True(t, errors.IsNotFound(err))
+
+	_, err = provider.RefreshSession(ctx, s1.ID, now.Add(8*time.Hour))
+	assert.True(t, errors.IsNotFound(err))
+
+	all, err := provider.RevokeUserSessions(ctx, user.ID, time.Now())
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, s2.ID, all[0].ID)
+
+	_, err = provider.UseSession(ctx, s2.ID, time.Now())
+	assert.True(t, errors.IsNotFound(err))
+
+	list, err = provider.GetUserSessions(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
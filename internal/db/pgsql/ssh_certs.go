@@ -0,0 +1,191 @@
+package pgsql
+
+import (
+	"context"
+	"time"
+
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/go-phorce/dolly/xlog"
+	"github.com/juju/errors"
+)
+
+// RegisterSSHCertificate registers SSH Certificate
+func (p *Provider) RegisterSSHCertificate(ctx context.Context, crt *model.SSHCertificate) (*model.SSHCertificate, error) {
+	id, err := p.NextID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = model.Validate(crt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	logger.Debugf("key_id=%q, ikid=%s, serial=%s", crt.KeyID, crt.IKID, crt.SerialNumber)
+
+	res := new(model.SSHCertificate)
+	var principals string
+
+	err = p.db.QueryRowContext(ctx, `
+			INSERT INTO sshcerts(id,org_id,ikid,serial_number,key_id,cert_type,principals,not_before,no_tafter,sha256,pem,profile)
+				VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			ON CONFLICT (sha256)
+			DO UPDATE
+				SET org_id=$2
+			RETURNING id,org_id,ikid,serial_number,key_id,cert_type,principals,not_before,no_tafter,sha256,pem,profile
+			;`, id, crt.OrgID, crt.IKID, crt.SerialNumber,
+		crt.KeyID, crt.CertType, crt.PrincipalsString(),
+		crt.NotBefore, crt.NotAfter,
+		crt.ThumbprintSha256,
+		crt.Pem,
+		crt.Profile,
+	).Scan(&res.ID,
+		&res.OrgID,
+		&res.IKID,
+		&res.SerialNumber,
+		&res.KeyID,
+		&res.CertType,
+		&principals,
+		&res.NotBefore,
+		&res.NotAfter,
+		&res.ThumbprintSha256,
+		&res.Pem,
+		&res.Profile,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	res.SetPrincipals(principals)
+	res.NotAfter = res.NotAfter.UTC()
+	res.NotBefore = res.NotBefore.UTC()
+	return res, nil
+}
+
+// RevokeSSHCertificate marks SSH Certificate as revoked
+func (p *Provider) RevokeSSHCertificate(ctx context.Context, ikid, serialNumber string, at time.Time, reason int) (*model.SSHCertificate, error) {
+	logger.Debugf("ikid=%s, serial=%s", ikid, serialNumber)
+
+	res := new(model.SSHCertificate)
+	var principals string
+
+	err := p.db.QueryRowContext(ctx, `
+			UPDATE sshcerts
+				SET revoked_at=$3,reason=$4
+			WHERE ikid=$1 AND serial_number=$2
+			RETURNING id,org_id,ikid,serial_number,key_id,cert_type,principals,not_before,no_tafter,sha256,pem,profile,revoked_at,reason
+			;`, ikid, serialNumber, at.UTC(), reason,
+	).Scan(&res.ID,
+		&res.OrgID,
+		&res.IKID,
+		&res.SerialNumber,
+		&res.KeyID,
+		&res.CertType,
+		&principals,
+		&res.NotBefore,
+		&res.NotAfter,
+		&res.ThumbprintSha256,
+		&res.Pem,
+		&res.Profile,
+		&res.RevokedAt,
+		&res.Reason,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	res.SetPrincipals(principals)
+	res.NotAfter = res.NotAfter.UTC()
+	res.NotBefore = res.NotBefore.UTC()
+	return res, nil
+}
+
+// ListRevokedSSHCertificates returns revoked SSH certificates info by a specified issuer
+func (p *Provider) ListRevokedSSHCertificates(ctx context.Context, ikid string, limit int, afterID uint64) (model.SSHCertificates, error) {
+	if limit == 0 {
+		limit = 1000
+	}
+	logger.KV(xlog.DEBUG,
+		"ikid", ikid,
+		"limit", limit,
+		"afterID", afterID,
+	)
+
+	res, err := p.db.QueryContext(ctx,
+		`SELECT
+			id,org_id,ikid,serial_number,key_id,cert_type,principals,not_before,no_tafter,sha256,profile,revoked_at,reason
+		FROM
+			sshcerts
+		WHERE
+			ikid = $1 AND id > $2 AND revoked_at IS NOT NULL
+		ORDER BY
+			id ASC
+		LIMIT $3
+		;
+		`, ikid, afterID, limit)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer res.Close()
+
+	list := make([]*model.SSHCertificate, 0, limit)
+
+	for res.Next() {
+		r := new(model.SSHCertificate)
+		var principals string
+		err = res.Scan(
+			&r.ID,
+			&r.OrgID,
+			&r.IKID,
+			&r.SerialNumber,
+			&r.KeyID,
+			&r.CertType,
+			&principals,
+			&r.NotBefore,
+			&r.NotAfter,
+			&r.ThumbprintSha256,
+			&r.Profile,
+			&r.RevokedAt,
+			&r.Reason,
+		)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		r.SetPrincipals(principals)
+		r.NotAfter = r.NotAfter.UTC()
+		r.NotBefore = r.NotBefore.UTC()
+		list = append(list, r)
+	}
+
+	return list, nil
+}
+
+// RemoveSSHCertificate removes SSH Certificate
+func (p *Provider) RemoveSSHCertificate(ctx context.Context, id uint64) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM sshcerts WHERE id=$1;`, id)
+	if err != nil {
+		logger.Errorf("api=RemoveSSHCertificate, err=[%s]", errors.Details(err))
+		return errors.Trace(err)
+	}
+
+	logger.Noticef("api=RemoveSSHCertificate, id=%d", id)
+
+	return nil
+}
+
+// NextSSHKRLVersion returns the version of the next KRL published by the issuer
+func (p *Provider) NextSSHKRLVersion(ctx context.Context, ikid string, at time.Time) (uint64, error) {
+	var version uint64
+	err := p.db.QueryRowContext(ctx, `
+			INSERT INTO sshkrls(ikid,version,updated_at)
+				VALUES($1, $2, $3)
+			ON CONFLICT (ikid)
+			DO UPDATE
+				SET version=sshkrls.version+1, updated_at=$3
+			RETURNING version
+			;`, ikid, at.Unix(), at.UTC(),
+	).Scan(&version)
+	if err != nil {
+		logger.Errorf("api=NextSSHKRLVersion, ikid=%s, err=[%s]", ikid, errors.Details(err))
+		return 0, errors.Trace(err)
+	}
+	return version, nil
+}
@@ -0,0 +1,82 @@
+package pgsql_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/internal/db/model"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterSSHCertificate(t *testing.T) {
+	id, err := provider.NextID()
+	require.NoError(t, err)
+
+	ikid := certutil.RandomString(43)
+	crt := &model.SSHCertificate{
+		IKID:             ikid,
+		SerialNumber:     strconv.FormatUint(id, 10),
+		KeyID:            "denis@ekspand.com",
+		CertType:         "user",
+		Principals:       []string{"denis", "ekspand"},
+		NotBefore:        time.Now().Add(-time.Minute).UTC(),
+		NotAfter:         time.Now().Add(time.Hour).UTC(),
+		ThumbprintSha256: certutil.RandomString(64),
+		Profile:          "ssh_user",
+		Pem:              "ssh-ed25519-cert-v01@openssh.com AAAA",
+	}
+
+	r, err := provider.RegisterSSHCertificate(ctx, crt)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	defer provider.RemoveSSHCertificate(ctx, r.ID)
+
+	assert.Equal(t, crt.IKID, r.IKID)
+	assert.Equal(t, crt.SerialNumber, r.SerialNumber)
+	assert.Equal(t, crt.KeyID, r.KeyID)
+	assert.Equal(t, crt.CertType, r.CertType)
+	assert.Equal(t, crt.Principals, r.Principals)
+	assert.Equal(t, crt.ThumbprintSha256, r.ThumbprintSha256)
+	assert.Equal(t, crt.Pem, r.Pem)
+	assert.Equal(t, crt.NotBefore.Unix(), r.NotBefore.Unix())
+	assert.Equal(t, crt.NotAfter.Unix(), r.NotAfter.Unix())
+	assert.False(t, r.IsRevoked())
+
+	list, err := provider.ListRevokedSSHCertificates(ctx, ikid, 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	revoked, err := provider.RevokeSSHCertificate(ctx, ikid, crt.SerialNumber, time.Now(), 1)
+	require.NoError(t, err)
+	assert.True(t, revoked.IsRevoked())
+	assert.Equal(t, 1, revoked.Reason)
+
+	list, err = provider.ListRevokedSSHCertificates(ctx, ikid, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, r.ID, list[0].ID)
+
+	_, err = provider.RevokeSSHCertificate(ctx, ikid, "0", time.Now(), 1)
+	require.Error(t, err)
+}
+
+func TestNextSSHKRLVersion(t *testing.T) {
+	ikid := certutil.RandomString(43)
+	now := time.Now()
+
+	v1, err := provider.NextSSHKRLVersion(ctx, ikid, now)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(now.Unix()), v1)
+
+	// the version increases, even if the clock goes back
+	v2, err := provider.NextSSHKRLVersion(ctx, ikid, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, v1+1, v2)
+
+	other, err := provider.NextSSHKRLVersion(ctx, certutil.RandomString(43), now)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(now.Unix()), other)
+}
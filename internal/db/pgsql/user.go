@@ -8,7 +8,9 @@ import (
 	"github.com/juju/errors"
 )
 
-// LoginUser returns logged in user info
+// LoginUser returns logged in user info.
+// The users with Subject, authenticated by OIDC provider,
+// are identified by Provider and Subject, and by Email otherwise.
 func (p *Provider) LoginUser(ctx context.Context, user *model.User) (*model.User, error) {
 	id, err := p.NextID()
 	if err != nil {
@@ -20,18 +22,34 @@ func (p *Provider) LoginUser(ctx context.Context, user *model.User) (*model.User
 		return nil, errors.Trace(err)
 	}
 
+	conflict := `(email)`
+	if user.Subject != "" {
+		conflict = `(provider, subject)`
+
+		// the accounts registered by the provider before the subject was stored,
+		// are bound to the subject on the first login
+		_, err = p.db.ExecContext(ctx, `
+			UPDATE users
+				SET subject=$1
+			WHERE provider=$2 AND email=$3 AND subject IS NULL
+			;`, user.Subject, user.Provider, user.Email)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	res := new(model.User)
 
 	err = p.db.QueryRowContext(ctx, `
-		INSERT INTO users(id,extern_id,provider,login,name,email,company,avatar_url,access_token,refresh_token,token_expires_at,login_count,last_login_at)
-			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-		ON CONFLICT (email)
+		INSERT INTO users(id,extern_id,provider,login,name,email,company,avatar_url,access_token,refresh_token,token_expires_at,login_count,last_login_at,subject)
+			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NULLIF($14,''))
+		ON CONFLICT `+conflict+`
 		DO UPDATE
 			SET access_token=$9, refresh_token=$10, token_expires_at=$11, login_count = users.login_count + 1, last_login_at=$13
-		RETURNING id,extern_id,provider,login,name,email,company,avatar_url,access_token,refresh_token,token_expires_at,login_count,last_login_at
+		RETURNING id,extern_id,provider,login,name,email,company,avatar_url,access_token,refresh_token,token_expires_at,login_count,last_login_at,COALESCE(subject,'')
 		;`, id, user.ExternalID, user.Provider, user.Login, user.Name, user.Email, user.Company, user.AvatarURL,
 		user.AccessToken, user.RefreshToken, user.TokenExpiresAt,
-		1, time.Now().UTC(),
+		1, time.Now().UTC(), user.Subject,
 	).Scan(&res.ID,
 		&res.ExternalID,
 		&res.Provider,
@@ -45,6 +63,7 @@ func (p *Provider) LoginUser(ctx context.Context, user *model.User) (*model.User
 		&res.TokenExpiresAt,
 		&res.LoginCount,
 		&res.LastLoginAt,
+		&res.Subject,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -58,7 +77,7 @@ func (p *Provider) GetUser(ctx context.Context, id uint64) (*model.User, error)
 	user := new(model.User)
 
 	err := p.db.QueryRowContext(ctx,
-		`SELECT id,extern_id,provider,login,name,email,company,avatar_url,access_token,refresh_token,token_expires_at,login_count,last_login_at
+		`SELECT id,extern_id,provider,login,name,email,company,avatar_url,access_token,refresh_token,token_expires_at,login_count,last_login_at,COALESCE(subject,'')
 		FROM users
 		WHERE id=$1
 		;`, id,
@@ -75,6 +94,7 @@ func (p *Provider) GetUser(ctx context.Context, id uint64) (*model.User, error)
 		&user.TokenExpiresAt,
 		&user.LoginCount,
 		&user.LastLoginAt,
+		&user.Subject,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -54,3 +54,67 @@ func Test_LoginUser(t *testing.T) {
 		require.NotEmpty(t, list)
 	*/
 }
+
+func Test_LoginUserWithSubject(t *testing.T) {
+	id, err := provider.NextID()
+	require.NoError(t, err)
+
+	login := fmt.Sprintf("oidc%d", id)
+	email := login + "@trusty.com"
+	subject := fmt.Sprintf("sub-%d", id)
+
+	u := &model.User{
+		Provider: "okta",
+		Subject:  subject,
+		Name:     login,
+		Login:    login,
+		Email:    email,
+	}
+
+	user, err := provider.LoginUser(ctx, u)
+	require.NoError(t, err)
+	assert.Equal(t, subject, user.Subject)
+	assert.Equal(t, 1, user.LoginCount)
+
+	user2, err := provider.LoginUser(ctx, u)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, user2.ID)
+	assert.Equal(t, 2, user2.LoginCount)
+
+	user3, err := provider.GetUser(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, subject, user3.Subject)
+
+	// another subject with the same email is not matched by email
+	_, err = provider.LoginUser(ctx, &model.User{
+		Provider: "okta",
+		Subject:  subject + "-other",
+		Name:     login,
+		Login:    login + "-other",
+		Email:    email,
+	})
+	require.Error(t, err)
+
+	// the account registered before the subject was stored is bound on login
+	legacy := fmt.Sprintf("legacy%d", id)
+	user4, err := provider.LoginUser(ctx, &model.User{
+		Provider: "okta",
+		Name:     legacy,
+		Login:    legacy,
+		Email:    legacy + "@trusty.com",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, user4.Subject)
+
+	user5, err := provider.LoginUser(ctx, &model.User{
+		Provider: "okta",
+		Subject:  "sub-" + legacy,
+		Name:     legacy,
+		Login:    legacy,
+		Email:    legacy + "@trusty.com",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, user4.ID, user5.ID)
+	assert.Equal(t, "sub-"+legacy, user5.Subject)
+	assert.Equal(t, 2, user5.LoginCount)
+}
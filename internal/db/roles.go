@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/juju/errors"
+)
+
+// RoleResolver resolves roles of identities from role bindings and org membership
+type RoleResolver struct {
+	db OrgsReadOnlyDb
+}
+
+// NewRoleResolver returns RoleResolver
+func NewRoleResolver(db OrgsReadOnlyDb) *RoleResolver {
+	return &RoleResolver{db: db}
+}
+
+// RoleBindings returns roles bound to the subjects
+func (r *RoleResolver) RoleBindings(ctx context.Context, subjects []string) ([]string, error) {
+	list, err := r.db.GetSubjectsRoleBindings(ctx, subjects)
+	if err != nil {
+		logger.Errorf("api=RoleBindings, subjects=%v, err=[%s]", subjects, errors.Details(err))
+		return nil, errors.Trace(err)
+	}
+	res := make([]string, len(list))
+	for i, b := range list {
+		res[i] = b.Role
+	}
+	return res, nil
+}
+
+// OrgRoles returns roles of the user in orgs, in ${org}/${role} format
+func (r *RoleResolver) OrgRoles(ctx context.Context, userID string) ([]string, error) {
+	id, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("invalid user ID: %q", userID)
+	}
+	res, err := r.db.GetUserOrgRoles(ctx, id)
+	if err != nil {
+		logger.Errorf("api=OrgRoles, user_id=%s, err=[%s]", userID, errors.Details(err))
+		return nil, errors.Trace(err)
+	}
+	return res, nil
+}
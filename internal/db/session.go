@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// SessionVerifier verifies login sessions of issued access tokens
+type SessionVerifier struct {
+	db OrgsDb
+}
+
+// NewSessionVerifier returns SessionVerifier
+func NewSessionVerifier(db OrgsDb) *SessionVerifier {
+	return &SessionVerifier{db: db}
+}
+
+// VerifySession returns ID of the user and groups asserted at login,
+// if the session with the specified `jti` is active
+func (v *SessionVerifier) VerifySession(ctx context.Context, jti string) (string, []string, error) {
+	id, err := strconv.ParseUint(jti, 10, 64)
+	if err != nil {
+		return "", nil, errors.New("invalid session")
+	}
+
+	s, err := v.db.UseSession(ctx, id, time.Now().UTC())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil, errors.New("session is revoked or expired")
+		}
+		logger.Errorf("api=VerifySession, jti=%s, err=[%s]", jti, errors.Details(err))
+		return "", nil, errors.Trace(err)
+	}
+
+	return strconv.FormatUint(s.UserID, 10), s.Groups, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/ekspand/trusty/authority"
 	csrapi "github.com/ekspand/trusty/pkg/csr"
@@ -24,8 +25,24 @@ type CertificateSigningRequestSigningReconciler struct {
 	client.Client
 	Log           logr.Logger
 	Scheme        *runtime.Scheme
-	Authority     *authority.Authority
 	EventRecorder record.EventRecorder
+
+	lock      sync.RWMutex
+	authority *authority.Authority
+}
+
+// SetAuthority replaces the Authority used to sign requests
+func (r *CertificateSigningRequestSigningReconciler) SetAuthority(ca *authority.Authority) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.authority = ca
+}
+
+// Authority returns the current Authority
+func (r *CertificateSigningRequestSigningReconciler) Authority() *authority.Authority {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.authority
 }
 
 // +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests,verbs=get;list;watch
@@ -107,8 +124,8 @@ func (r *CertificateSigningRequestSigningReconciler) findIssuer(signerName strin
 	// [0] - issuer name, [1] - profile name
 	issuerTokens := strings.Split(signerName, "/")
 	if len(issuerTokens) == 2 {
-		issuer, _ := r.Authority.GetIssuerByProfile(issuerTokens[1])
-		if issuer != nil && issuer.Label() == issuerTokens[0] {
+		issuer, _ := r.Authority().GetIssuerByProfileAndLabel(issuerTokens[1], issuerTokens[0])
+		if issuer != nil {
 			return issuer, issuerTokens[1]
 		}
 	}
@@ -1,6 +1,13 @@
 package controller
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
 	capi "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -8,6 +15,8 @@ import (
 
 	// +kubebuilder:scaffold:imports
 	"github.com/ekspand/trusty/authority"
+	"github.com/go-phorce/dolly/audit"
+	fauditor "github.com/go-phorce/dolly/audit/log"
 	"github.com/go-phorce/dolly/xlog"
 	"github.com/go-phorce/dolly/xpki/cryptoprov"
 )
@@ -19,6 +28,12 @@ var (
 
 const controllerName = "CSRSigningReconciler"
 
+const (
+	auditSource           = "kubeca"
+	evtConfigReloaded     = "CAConfigReloaded"
+	evtConfigReloadFailed = "CAConfigReloadFailed"
+)
+
 func init() {
 	_ = capi.AddToScheme(scheme)
 	_ = corev1.AddToScheme(scheme)
@@ -33,6 +48,9 @@ type CertificateSigningRequestControllerFlags struct {
 	LeaderElectionID     string
 	CaCfgPath            string
 	HsmCfgPath           string
+	// AuditDir specifies the folder for audit files,
+	// if not provided then the audit events are not recorded
+	AuditDir string
 }
 
 // StartCertificateSigningRequestController starts controller loop
@@ -60,29 +78,20 @@ func StartCertificateSigningRequestController(f *CertificateSigningRequestContro
 		return err
 	}
 
-	caCfg, err := authority.LoadConfig(f.CaCfgPath)
+	ca, err := loadAuthority(f.CaCfgPath, crypto)
 	if err != nil {
-		logger.KV(xlog.ERROR,
-			"reason", "unable to load CA config",
-			"config", f.CaCfgPath,
-			"err", err)
 		return err
 	}
 
-	ca, err := authority.NewAuthority(caCfg, crypto)
-	if err != nil {
-		logger.KV(xlog.ERROR,
-			"reason", "unable to create CA",
-			"err", err)
-		return err
-	}
-	if err := (&CertificateSigningRequestSigningReconciler{
+	reconciler := &CertificateSigningRequestSigningReconciler{
 		Client:        mgr.GetClient(),
 		Log:           ctrl.Log.WithName(controllerName),
 		Scheme:        mgr.GetScheme(),
-		Authority:     ca,
 		EventRecorder: mgr.GetEventRecorderFor(controllerName),
-	}).SetupWithManager(mgr); err != nil {
+	}
+	reconciler.SetAuthority(ca)
+
+	if err := reconciler.SetupWithManager(mgr); err != nil {
 		logger.KV(xlog.ERROR,
 			"reason", "unable to create Controller",
 			"controller", controllerName,
@@ -92,8 +101,24 @@ func StartCertificateSigningRequestController(f *CertificateSigningRequestContro
 	}
 	// +kubebuilder:scaffold:builder
 
+	var auditor audit.Auditor
+	if f.AuditDir != "" {
+		auditor, err = fauditor.New(auditSource+".log", f.AuditDir, 0, 0)
+		if err != nil {
+			logger.KV(xlog.ERROR,
+				"reason", "unable to create auditor",
+				"dir", f.AuditDir,
+				"err", err)
+			return err
+		}
+		defer auditor.Close()
+	}
+
+	ctx := ctrl.SetupSignalHandler()
+	go reloadOnSignal(ctx, reconciler, f.CaCfgPath, crypto, auditor)
+
 	logger.Info("starting controller")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		logger.KV(xlog.ERROR,
 			"reason", "unable to start controller",
 			"controller", controllerName,
@@ -102,3 +127,72 @@ func StartCertificateSigningRequestController(f *CertificateSigningRequestContro
 	}
 	return nil
 }
+
+func loadAuthority(caCfgPath string, crypto *cryptoprov.Crypto) (*authority.Authority, error) {
+	caCfg, err := authority.LoadConfig(caCfgPath)
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"reason", "unable to load CA config",
+			"config", caCfgPath,
+			"err", err)
+		return nil, err
+	}
+
+	ca, err := authority.NewAuthority(caCfg, crypto)
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"reason", "unable to create CA",
+			"err", err)
+		return nil, err
+	}
+	return ca, nil
+}
+
+// reloadOnSignal reloads CA configuration on SIGHUP,
+// the reconciler keeps the current Authority if reload fails
+func reloadOnSignal(ctx context.Context, r *CertificateSigningRequestSigningReconciler, caCfgPath string, crypto *cryptoprov.Crypto, auditor audit.Auditor) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+	defer signal.Stop(sigs)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigs:
+			reload(r, caCfgPath, crypto, auditor)
+		}
+	}
+}
+
+func reload(r *CertificateSigningRequestSigningReconciler, caCfgPath string, crypto *cryptoprov.Crypto, auditor audit.Auditor) {
+	ca, err := loadAuthority(caCfgPath, crypto)
+	if err != nil {
+		logger.KV(xlog.ERROR,
+			"reason", "reload failed",
+			"config", caCfgPath,
+			"err", err)
+		if auditor != nil {
+			auditor.Audit(auditSource, evtConfigReloadFailed, "SIGHUP", "", 0,
+				fmt.Sprintf("config=%q, err=%q", caCfgPath, err.Error()))
+		}
+		return
+	}
+
+	r.SetAuthority(ca)
+
+	issuers := ca.Issuers()
+	labels := make([]string, len(issuers))
+	for i, issuer := range issuers {
+		labels[i] = issuer.Label()
+	}
+
+	logger.KV(xlog.NOTICE,
+		"status", "reloaded CA configuration",
+		"config", caCfgPath,
+		"issuers", labels)
+	if auditor != nil {
+		auditor.Audit(auditSource, evtConfigReloaded, "SIGHUP", "", 0,
+			fmt.Sprintf("config=%q, issuers=[%s]", caCfgPath, strings.Join(labels, ",")))
+	}
+}
@@ -0,0 +1,293 @@
+// Package authz provides an implemention of http and gRPC authorization,
+// where specific URI (or URI's and their children) are allowed access by a set of roles.
+//
+// Unlike github.com/go-phorce/dolly/xhttp/authz, the caller may have
+// multiple roles: the static role from the identity map,
+// and the roles resolved from org membership, IdP groups and role bindings.
+// The access is allowed if any of the caller's roles is allowed.
+//
+// The access control points are on entire URI segments only, e.g.
+// "/foo/bar:bob" gives access to /foo/bar /foo/bar/baz, but not /foo/barry
+//
+// Access is based on the deepest matching path, not the accumulated paths, so,
+// "/foo:bob" and "/foo/bar:barry"
+// will allow barry access to /foo/bar but not access to /foo
+package authz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ekspand/trusty/internal/config"
+	"github.com/go-phorce/dolly/xhttp/httperror"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/go-phorce/dolly/xhttp/marshal"
+	"github.com/go-phorce/dolly/xlog"
+	"github.com/juju/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var logger = xlog.NewPackageLogger("github.com/ekspand/trusty/pkg", "authz")
+
+// ErrNoPathsConfigured is returned by NewHandler if no paths are configured
+var ErrNoPathsConfigured = errors.New("you must have at least one path before being able to create a http.Handler")
+
+// RolesIdentity is implemented by identities with multiple roles
+type RolesIdentity interface {
+	// Roles returns all roles of the identity
+	Roles() []string
+}
+
+// Provider represents an Authorization provider
+type Provider struct {
+	cfg      config.Authz
+	pathRoot *pathNode
+}
+
+type allowTypes int8
+
+const (
+	allowAny allowTypes = 1 << iota
+	allowAnyRole
+)
+
+// the auth info is stored in a tree based on the path segments,
+// the deepest node that matches the request is used to validate the request
+type pathNode struct {
+	value        string
+	children     map[string]*pathNode
+	allowedRoles map[string]bool
+	allow        allowTypes
+}
+
+// New returns new Authz provider
+func New(cfg *config.Authz) (*Provider, error) {
+	az := &Provider{
+		cfg:      *cfg,
+		pathRoot: newPathNode(""),
+	}
+
+	for _, s := range cfg.AllowAny {
+		az.walkPath(s, true).allow = allowAny
+		logger.Noticef("AllowAny=%s", s)
+	}
+
+	for _, s := range cfg.AllowAnyRole {
+		az.walkPath(s, true).allow |= allowAnyRole
+		logger.Noticef("AllowAnyRole=%s", s)
+	}
+
+	for _, s := range cfg.Allow {
+		parts := strings.Split(s, ":")
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return nil, errors.NotValidf("Authz allow configuration %q", s)
+		}
+		logger.Noticef("Allow=%s:%s", parts[0], parts[1])
+		node := az.walkPath(parts[0], true)
+		for _, role := range strings.Split(parts[1], ",") {
+			if role != "" {
+				node.allowedRoles[role] = true
+			}
+		}
+	}
+
+	return az, nil
+}
+
+// RolesOf returns roles of the identity
+func RolesOf(idn identity.Identity) []string {
+	if idn == nil {
+		return []string{identity.GuestRoleName}
+	}
+	if ri, ok := idn.(RolesIdentity); ok {
+		return ri.Roles()
+	}
+	return []string{idn.Role()}
+}
+
+// IsAllowed returns true if access to 'path' is allowed for any of the specified roles
+func (c *Provider) IsAllowed(path string, roles ...string) bool {
+	node := c.walkPath(path, false)
+	if node.allowAny() {
+		if c.cfg.LogAllowedAny {
+			logger.Infof("status=allowed, reason=AllowAny, roles=%q, path=%s, node=%s",
+				roles, path, node.value)
+		}
+		return true
+	}
+	for _, role := range roles {
+		if node.allowRole(role) {
+			if c.cfg.LogAllowed {
+				logger.Noticef("status=allowed, role=%q, path=%s, node=%s",
+					role, path, node.value)
+			}
+			return true
+		}
+	}
+	if c.cfg.LogDenied {
+		logger.Noticef("status=denied, roles=%q, path=%s, allowed_roles='%v', node=%s",
+			roles, path, strings.Join(node.allowedRoleKeys(), ","), node.value)
+	}
+	return false
+}
+
+// NewHandler returns a http.Handler that enforces the authorization configuration
+func (c *Provider) NewHandler(delegate http.Handler) (http.Handler, error) {
+	if len(c.pathRoot.children) == 0 && c.pathRoot.allow == 0 && len(c.pathRoot.allowedRoles) == 0 {
+		return nil, errors.Trace(ErrNoPathsConfigured)
+	}
+	logger.Infof("config=[%s]", c.treeAsText())
+	return &authHandler{
+		delegate: delegate,
+		config:   c,
+	}, nil
+}
+
+type authHandler struct {
+	delegate http.Handler
+	config   *Provider
+}
+
+func (a *authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// always allow OPTIONS
+	if r.Method == http.MethodOptions {
+		a.delegate.ServeHTTP(w, r)
+		return
+	}
+
+	roles := RolesOf(identity.FromRequest(r).Identity())
+	if !a.config.IsAllowed(r.URL.Path, roles...) {
+		marshal.WriteJSON(w, r, httperror.WithUnauthorized(deniedMessage(roles)))
+		return
+	}
+	a.delegate.ServeHTTP(w, r)
+}
+
+// NewUnaryInterceptor returns grpc.UnaryServerInterceptor to check access
+func (c *Provider) NewUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var idn identity.Identity
+		if callerCtx := identity.FromContext(ctx); callerCtx != nil {
+			idn = callerCtx.Identity()
+		}
+		roles := RolesOf(idn)
+		if !c.IsAllowed(info.FullMethod, roles...) {
+			return nil, status.Error(codes.PermissionDenied, deniedMessage(roles))
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func deniedMessage(roles []string) string {
+	if len(roles) == 1 {
+		return fmt.Sprintf("the %q role is not allowed", roles[0])
+	}
+	return fmt.Sprintf("the %q roles are not allowed", strings.Join(roles, ","))
+}
+
+// walkPath converts a URI path into a tree of pathNodes.
+// If create is true, all nodes required to create a tree equaling the supplied
+// path will be created if needed.
+// If create is false, the deepest node matching the supplied path is returned.
+func (c *Provider) walkPath(path string, create bool) *pathNode {
+	if len(path) == 0 || path[0] != '/' {
+		panic(fmt.Sprintf("Invalid path supplied to walkPath %v", path))
+	}
+	pathLen := len(path)
+	pathPos := 1
+	currentNode := c.pathRoot
+	for pathPos < pathLen {
+		segEnd := pathPos
+		for segEnd < pathLen && path[segEnd] != '/' {
+			segEnd++
+		}
+		pathSegment := path[pathPos:segEnd]
+		childNode := currentNode.children[pathSegment]
+		if childNode == nil && !create {
+			return currentNode
+		}
+		if childNode == nil {
+			childNode = newPathNode(pathSegment)
+			currentNode.children[pathSegment] = childNode
+		}
+		currentNode = childNode
+		pathPos = segEnd + 1
+	}
+	return currentNode
+}
+
+// treeAsText returns the configured tree in human readable text format
+func (c *Provider) treeAsText() string {
+	o := bytes.NewBuffer(make([]byte, 0, 256))
+	io.WriteString(o, "\n")
+	var visitNode func(int, *pathNode)
+	visitNode = func(depth int, n *pathNode) {
+		pad := strings.Repeat(" ", depth*2)
+		slash := ""
+		if len(n.children) > 0 {
+			slash = "/"
+		}
+		fmt.Fprintf(o, "%s  %s%s ", pad, n.value, slash)
+		switch {
+		case n.allowAny():
+			io.WriteString(o, "[Any]")
+		case (n.allow & allowAnyRole) != 0:
+			io.WriteString(o, "[Any Role]")
+		case len(n.allowedRoles) > 0:
+			fmt.Fprintf(o, "[%s]", strings.Join(n.allowedRoleKeys(), ","))
+		}
+		fmt.Fprintln(o)
+		for _, ck := range n.childKeys() {
+			visitNode(depth+1, n.children[ck])
+		}
+	}
+	visitNode(0, c.pathRoot)
+	return o.String()
+}
+
+func newPathNode(pathItem string) *pathNode {
+	return &pathNode{
+		value:        pathItem,
+		children:     make(map[string]*pathNode),
+		allowedRoles: make(map[string]bool),
+	}
+}
+
+// childKeys returns the child key names sorted alpabetically
+func (n *pathNode) childKeys() []string {
+	r := make([]string, 0, len(n.children))
+	for k := range n.children {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return r
+}
+
+// allowedRoleKeys returns the allowed role names sorted alphabetically
+func (n *pathNode) allowedRoleKeys() []string {
+	r := make([]string, 0, len(n.allowedRoles))
+	for k := range n.allowedRoles {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return r
+}
+
+func (n *pathNode) allowAny() bool {
+	return (n.allow & allowAny) != 0
+}
+
+func (n *pathNode) allowRole(r string) bool {
+	if r == "" || r == identity.GuestRoleName {
+		return false
+	}
+	return ((n.allow & allowAnyRole) != 0) || n.allowedRoles[r]
+}
@@ -0,0 +1,142 @@
+package authz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ekspand/trusty/internal/config"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type rolesIdentity struct {
+	identity.Identity
+	roles []string
+}
+
+func (i rolesIdentity) Roles() []string {
+	return i.roles
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&config.Authz{Allow: []string{"/a"}})
+	assert.Error(t, err, "Should fail without :")
+
+	_, err = New(&config.Authz{Allow: []string{"/a:"}})
+	assert.Error(t, err, "Should fail without role")
+
+	_, err = New(&config.Authz{Allow: []string{"/a:,"}})
+	assert.NoError(t, err, "Empty role will not be mapped")
+
+	az, err := New(&config.Authz{})
+	require.NoError(t, err)
+	_, err = az.NewHandler(http.NotFoundHandler())
+	assert.Error(t, err)
+}
+
+func TestIsAllowed(t *testing.T) {
+	az, err := New(&config.Authz{
+		Allow: []string{
+			"/v1/auth/roles:trusty-admin",
+			"/v1/ca:trusty-admin,ca-operator",
+		},
+		AllowAny:     []string{"/v1/status"},
+		AllowAnyRole: []string{"/v1/auth"},
+		LogAllowed:   true,
+		LogDenied:    true,
+	})
+	require.NoError(t, err)
+	t.Log(az.treeAsText())
+
+	tcases := []struct {
+		path    string
+		roles   []string
+		allowed bool
+	}{
+		{"/v1/status", nil, true},
+		{"/v1/status/caller", []string{identity.GuestRoleName}, true},
+		{"/v1/auth/sessions", []string{identity.GuestRoleName}, false},
+		{"/v1/auth/sessions", []string{"jwt_authenticated"}, true},
+		{"/v1/auth/roles", []string{"jwt_authenticated"}, false},
+		{"/v1/auth/roles", []string{"jwt_authenticated", "trusty-admin"}, true},
+		{"/v1/ca/issuers", []string{"ca-operator"}, true},
+		{"/v1/ca/issuers", []string{"", identity.GuestRoleName}, false},
+		{"/v1/cis", []string{"trusty-admin"}, false},
+		{"/", []string{"trusty-admin"}, false},
+	}
+	for _, tc := range tcases {
+		assert.Equal(t, tc.allowed, az.IsAllowed(tc.path, tc.roles...), "%s: %v", tc.path, tc.roles)
+	}
+}
+
+func TestRolesOf(t *testing.T) {
+	assert.Equal(t, []string{identity.GuestRoleName}, RolesOf(nil))
+
+	idn := identity.NewIdentity("jwt_authenticated", "denis", "")
+	assert.Equal(t, []string{"jwt_authenticated"}, RolesOf(idn))
+	assert.Equal(t, []string{"jwt_authenticated", "trusty-admin"},
+		RolesOf(rolesIdentity{Identity: idn, roles: []string{"jwt_authenticated", "trusty-admin"}}))
+}
+
+func TestHandler(t *testing.T) {
+	az, err := New(&config.Authz{
+		Allow: []string{"/v1/auth/roles:trusty-admin"},
+	})
+	require.NoError(t, err)
+
+	handler, err := az.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	require.NoError(t, err)
+
+	idn := identity.NewIdentity("jwt_authenticated", "denis", "1")
+
+	r, err := http.NewRequest(http.MethodGet, "/v1/auth/roles", nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, identity.WithTestIdentity(r, idn))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), `the \"jwt_authenticated\" role is not allowed`)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, identity.WithTestIdentity(r, rolesIdentity{
+		Identity: idn,
+		roles:    []string{"jwt_authenticated", "trusty-admin"},
+	}))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	r, err = http.NewRequest(http.MethodOptions, "/v1/auth/roles", nil)
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, identity.WithTestIdentity(r, idn))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	az, err := New(&config.Authz{
+		Allow:    []string{"/trusty.v1.CAService:trusty-admin"},
+		AllowAny: []string{"/trusty.v1.StatusService"},
+	})
+	require.NoError(t, err)
+
+	interceptor := az.NewUnaryInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/trusty.v1.StatusService/Version"}
+	res, err := interceptor(context.Background(), nil, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", res)
+
+	info = &grpc.UnaryServerInfo{FullMethod: "/trusty.v1.CAService/Issuers"}
+	_, err = interceptor(context.Background(), nil, info, handler)
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,385 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/juju/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// EvtPermissionDenied is audit event for the call denied by the policy
+	EvtPermissionDenied = "permission_denied"
+
+	// policyAuditSource is the source of audit events
+	policyAuditSource = "authz"
+)
+
+// Auditor interface to audit the denied calls
+type Auditor interface {
+	Audit(source string, eventType string, identity string, contextID string, raftIndex uint64, message string)
+}
+
+// Policy provides fine-grained permissions on gRPC methods,
+// scoped by issuer label, profile and org of the request.
+//
+// The policy is evaluated after the path based authorization,
+// for the methods that match any of the permissions:
+// the call is allowed, if one of the caller's roles has a permission
+// that matches the method, issuer, profile and org of the request.
+// The methods that do not match any permission are not restricted by the policy.
+type Policy struct {
+	// Permissions allowed to the roles
+	Permissions []*Permission `json:"permissions" yaml:"permissions"`
+
+	owners OwnerResolver
+}
+
+// Permission allows the roles to call the methods,
+// with the specified issuers, profiles and orgs.
+// Empty list of issuers, profiles or orgs matches any request,
+// otherwise the request must specify one of the values.
+type Permission struct {
+	// Name of the permission
+	Name string `json:"name" yaml:"name"`
+	// Roles specifies the roles that are granted the permission
+	Roles []string `json:"roles" yaml:"roles"`
+	// Methods specifies full names of gRPC methods,
+	// in /${service}/${method} format, where ${method} can be `*`
+	// to match all methods of the service, or `*` to match any method
+	Methods []string `json:"methods" yaml:"methods"`
+	// Issuers specifies allowed issuer labels
+	Issuers []string `json:"issuers,omitempty" yaml:"issuers,omitempty"`
+	// Profiles specifies allowed certificate profiles
+	Profiles []string `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+	// Orgs specifies allowed org IDs
+	Orgs []string `json:"orgs,omitempty" yaml:"orgs,omitempty"`
+	// Condition specifies the ownership of the resource of the request:
+	// own_org|requester. If not provided, then any resource is allowed.
+	Condition string `json:"condition,omitempty" yaml:"condition,omitempty"`
+}
+
+const (
+	// ConditionOwnOrg allows the resources of the caller's orgs only
+	ConditionOwnOrg = "own_org"
+	// ConditionRequester allows the resources requested by the caller only
+	ConditionRequester = "requester"
+)
+
+// Owner describes the owner of the resource of the request
+type Owner struct {
+	// OrgID specifies ID of the org of the resource
+	OrgID string
+	// Requester specifies the name of the requester of the resource
+	Requester string
+}
+
+// OwnerResolver resolves the owner of the resource of the request,
+// and the orgs of the caller, to evaluate the conditions of the permissions
+type OwnerResolver interface {
+	// ResourceOwner returns the owner of the resource of gRPC request,
+	// or nil if the request does not reference a resource
+	ResourceOwner(ctx context.Context, method string, req interface{}) (*Owner, error)
+	// CallerOrgs returns IDs of the orgs of the caller
+	CallerOrgs(ctx context.Context, idn identity.Identity) ([]string, error)
+}
+
+// Request provides the attributes of gRPC request, evaluated by the policy
+type Request struct {
+	Method string
+	// Issuer is set if the request specifies issuer label
+	Issuer *string
+	// Profile is set if the request specifies profile
+	Profile *string
+	// Org is set if the request specifies org ID
+	Org *string
+	// Caller specifies the name of the caller
+	Caller string
+	// CallerOrgs specifies IDs of the orgs of the caller
+	CallerOrgs []string
+	// Owner is set if the owner of the resource of the request is resolved
+	Owner *Owner
+}
+
+// LoadPolicy returns Policy loaded from the file
+func LoadPolicy(file string) (*Policy, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Annotate(err, "unable to read policy file")
+	}
+
+	p := new(Policy)
+	if strings.HasSuffix(file, ".json") {
+		err = json.Unmarshal(b, p)
+	} else {
+		err = yaml.Unmarshal(b, p)
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to unmarshal policy")
+	}
+
+	if err = p.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return p, nil
+}
+
+// Validate returns error if the policy is not valid
+func (p *Policy) Validate() error {
+	for i, perm := range p.Permissions {
+		name := perm.Name
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		if len(perm.Roles) == 0 {
+			return errors.NotValidf("permission %q: missing roles", name)
+		}
+		if len(perm.Methods) == 0 {
+			return errors.NotValidf("permission %q: missing methods", name)
+		}
+		for _, m := range perm.Methods {
+			if m != "*" && !strings.HasPrefix(m, "/") {
+				return errors.NotValidf("permission %q: method %q", name, m)
+			}
+		}
+		switch perm.Condition {
+		case "", ConditionOwnOrg, ConditionRequester:
+		default:
+			return errors.NotValidf("permission %q: condition %q", name, perm.Condition)
+		}
+	}
+	return nil
+}
+
+// WithOwnerResolver sets the resolver of the resource owners,
+// required for the permissions with conditions
+func (p *Policy) WithOwnerResolver(owners OwnerResolver) *Policy {
+	p.owners = owners
+	return p
+}
+
+// hasConditions returns true if any permission of the method has condition
+func (p *Policy) hasConditions(method string) bool {
+	for _, perm := range p.Permissions {
+		if perm.Condition != "" && perm.matchMethod(method) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRestricted returns true if the method matches any permission of the policy
+func (p *Policy) IsRestricted(method string) bool {
+	for _, perm := range p.Permissions {
+		if perm.matchMethod(method) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAllowed returns true if the request is allowed for any of the roles
+func (p *Policy) IsAllowed(req *Request, roles ...string) bool {
+	if !p.IsRestricted(req.Method) {
+		return true
+	}
+	for _, perm := range p.Permissions {
+		if perm.matchRoles(roles) && perm.matches(req) {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionsFor returns the permissions granted to any of the roles
+func (p *Policy) PermissionsFor(roles ...string) []*Permission {
+	var res []*Permission
+	for _, perm := range p.Permissions {
+		if perm.matchRoles(roles) {
+			res = append(res, perm)
+		}
+	}
+	return res
+}
+
+// NewUnaryInterceptor returns grpc.UnaryServerInterceptor to check the policy,
+// the denied calls are reported to the auditor
+func (p *Policy) NewUnaryInterceptor(auditor Auditor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !p.IsRestricted(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		var idn identity.Identity
+		var contextID string
+		if callerCtx := identity.FromContext(ctx); callerCtx != nil {
+			idn = callerCtx.Identity()
+			contextID = callerCtx.CorrelationID()
+		}
+		roles := RolesOf(idn)
+		r := NewRequest(info.FullMethod, req)
+		var name string
+		if idn != nil {
+			name = idn.Name()
+		}
+		if p.owners != nil && p.hasConditions(r.Method) {
+			r.Caller = name
+			if err := p.resolveOwner(ctx, r, idn, req); err != nil {
+				logger.Errorf("status=owner_failed, name=%s, method=%s, err=[%s]",
+					name, r.Method, errors.Details(err))
+				return nil, status.Errorf(codes.Internal, "unable to resolve the owner of the resource")
+			}
+		}
+		if !p.IsAllowed(r, roles...) {
+			if name == "" {
+				name = identity.GuestRoleName
+			}
+			msg := fmt.Sprintf("method=%s, roles=%s, %s", r.Method, strings.Join(roles, ","), r)
+			logger.Noticef("status=denied, name=%s, %s", name, msg)
+			if auditor != nil {
+				auditor.Audit(policyAuditSource, EvtPermissionDenied, name, contextID, 0, msg)
+			}
+			return nil, status.Errorf(codes.PermissionDenied, "the call is not permitted: %s", r)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// resolveOwner sets the owner of the resource, and the orgs of the caller
+func (p *Policy) resolveOwner(ctx context.Context, r *Request, idn identity.Identity, req interface{}) error {
+	owner, err := p.owners.ResourceOwner(ctx, r.Method, req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	r.Owner = owner
+	if owner != nil && owner.OrgID != "" && idn != nil {
+		r.CallerOrgs, err = p.owners.CallerOrgs(ctx, idn)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+type issuerRequest interface {
+	GetIssuerLabel() string
+}
+
+type profileRequest interface {
+	GetProfile() string
+}
+
+type orgRequest interface {
+	GetOrgId() uint64
+}
+
+// NewRequest returns Request with the attributes of gRPC request
+func NewRequest(method string, req interface{}) *Request {
+	r := &Request{Method: method}
+	if v, ok := req.(issuerRequest); ok && v.GetIssuerLabel() != "" {
+		s := v.GetIssuerLabel()
+		r.Issuer = &s
+	}
+	if v, ok := req.(profileRequest); ok && v.GetProfile() != "" {
+		s := v.GetProfile()
+		r.Profile = &s
+	}
+	if v, ok := req.(orgRequest); ok && v.GetOrgId() != 0 {
+		s := strconv.FormatUint(v.GetOrgId(), 10)
+		r.Org = &s
+	}
+	return r
+}
+
+// String returns the attributes of the request
+func (r *Request) String() string {
+	return fmt.Sprintf("issuer=%s, profile=%s, org=%s",
+		strOrAny(r.Issuer), strOrAny(r.Profile), strOrAny(r.Org))
+}
+
+func strOrAny(s *string) string {
+	if s == nil {
+		return "*"
+	}
+	return *s
+}
+
+func (p *Permission) matches(req *Request) bool {
+	return p.matchMethod(req.Method) &&
+		matchValue(p.Issuers, req.Issuer) &&
+		matchValue(p.Profiles, req.Profile) &&
+		matchValue(p.Orgs, req.Org) &&
+		p.matchCondition(req)
+}
+
+// matchCondition returns true if the permission has no condition,
+// or the resource of the request is owned by the caller
+func (p *Permission) matchCondition(req *Request) bool {
+	switch p.Condition {
+	case "":
+		return true
+	case ConditionOwnOrg:
+		if req.Owner == nil || req.Owner.OrgID == "" {
+			return false
+		}
+		for _, org := range req.CallerOrgs {
+			if org == req.Owner.OrgID {
+				return true
+			}
+		}
+		return false
+	case ConditionRequester:
+		return req.Owner != nil && req.Caller != "" && req.Owner.Requester == req.Caller
+	}
+	return false
+}
+
+func (p *Permission) matchMethod(method string) bool {
+	for _, m := range p.Methods {
+		if m == "*" || m == method {
+			return true
+		}
+		if strings.HasSuffix(m, "/*") && strings.HasPrefix(method, m[:len(m)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Permission) matchRoles(roles []string) bool {
+	for _, allowed := range p.Roles {
+		for _, role := range roles {
+			if role == allowed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchValue returns true if the list is empty,
+// or the request specifies one of the values
+func matchValue(list []string, val *string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	if val == nil {
+		return false
+	}
+	for _, s := range list {
+		if s == *val {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,271 @@
+package authz
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ekspand/trusty/api/v1/pb"
+	"github.com/go-phorce/dolly/xhttp/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type auditEvent struct {
+	source, eventType, identity, contextID, message string
+}
+
+type mockAuditor struct {
+	events []auditEvent
+}
+
+func (a *mockAuditor) Audit(source string, eventType string, identity string, contextID string, raftIndex uint64, message string) {
+	a.events = append(a.events, auditEvent{source, eventType, identity, contextID, message})
+}
+
+func TestLoadPolicy(t *testing.T) {
+	p, err := LoadPolicy("../../etc/dev/rbac-policy.yaml")
+	require.NoError(t, err)
+	assert.NotEmpty(t, p.Permissions)
+
+	_, err = LoadPolicy("missing.yaml")
+	require.Error(t, err)
+
+	dir, err := ioutil.TempDir("", "policy")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tcases := []struct {
+		policy string
+		err    string
+	}{
+		{"permissions: [", "failed to unmarshal policy"},
+		{"permissions:\n  - name: p1\n    methods: [/pb.CAService/*]\n", `permission "p1": missing roles not valid`},
+		{"permissions:\n  - roles: [admin]\n", `permission "0": missing methods not valid`},
+		{"permissions:\n  - roles: [admin]\n    methods: [pb.CAService]\n", `permission "0": method "pb.CAService" not valid`},
+	}
+	for _, tc := range tcases {
+		file := filepath.Join(dir, "policy.yaml")
+		require.NoError(t, ioutil.WriteFile(file, []byte(tc.policy), 0644))
+		_, err = LoadPolicy(file)
+		require.Error(t, err, tc.policy)
+		assert.Contains(t, err.Error(), tc.err)
+	}
+
+	file := filepath.Join(dir, "policy.json")
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{"permissions":[{"roles":["admin"],"methods":["*"]}]}`), 0644))
+	p, err = LoadPolicy(file)
+	require.NoError(t, err)
+	assert.True(t, p.IsRestricted("/pb.StatusService/Version"))
+}
+
+func TestPolicy(t *testing.T) {
+	p := &Policy{
+		Permissions: []*Permission{
+			{
+				Name:    "admin",
+				Roles:   []string{"trusty-admin"},
+				Methods: []string{"/pb.CAService/*"},
+			},
+			{
+				Name:     "team-server",
+				Roles:    []string{"team"},
+				Methods:  []string{"/pb.CAService/SignCertificate"},
+				Issuers:  []string{"issuer1"},
+				Profiles: []string{"server"},
+				Orgs:     []string{"123"},
+			},
+		},
+	}
+	require.NoError(t, p.Validate())
+
+	assert.True(t, p.IsRestricted("/pb.CAService/PublishCrls"))
+	assert.False(t, p.IsRestricted("/pb.CAServiceX/PublishCrls"))
+	assert.False(t, p.IsRestricted("/pb.StatusService/Version"))
+
+	sign := "/pb.CAService/SignCertificate"
+	tcases := []struct {
+		req     *pb.SignCertificateRequest
+		method  string
+		roles   []string
+		allowed bool
+	}{
+		{&pb.SignCertificateRequest{IssuerLabel: "issuer1", Profile: "server", OrgId: 123}, sign, []string{"team"}, true},
+		{&pb.SignCertificateRequest{IssuerLabel: "issuer1", Profile: "server", OrgId: 123}, sign, []string{"trusty-admin"}, true},
+		{&pb.SignCertificateRequest{IssuerLabel: "issuer1", Profile: "client", OrgId: 123}, sign, []string{"team"}, false},
+		{&pb.SignCertificateRequest{IssuerLabel: "issuer2", Profile: "server", OrgId: 123}, sign, []string{"team"}, false},
+		{&pb.SignCertificateRequest{IssuerLabel: "issuer1", Profile: "server", OrgId: 124}, sign, []string{"team"}, false},
+		{&pb.SignCertificateRequest{Profile: "server", OrgId: 123}, sign, []string{"team"}, false},
+		{&pb.SignCertificateRequest{IssuerLabel: "issuer1", Profile: "server"}, sign, []string{"team"}, false},
+		{&pb.SignCertificateRequest{IssuerLabel: "issuer1", Profile: "server", OrgId: 123}, sign, []string{identity.GuestRoleName}, false},
+		{nil, "/pb.CAService/PublishCrls", []string{"team"}, false},
+		{nil, "/pb.CAService/PublishCrls", []string{"team", "trusty-admin"}, true},
+		{nil, "/pb.StatusService/Version", []string{identity.GuestRoleName}, true},
+	}
+	for _, tc := range tcases {
+		var req interface{}
+		if tc.req != nil {
+			req = tc.req
+		}
+		r := NewRequest(tc.method, req)
+		assert.Equal(t, tc.allowed, p.IsAllowed(r, tc.roles...), "%s: %v: %s", tc.method, tc.roles, r)
+	}
+
+	perms := p.PermissionsFor("team")
+	require.Len(t, perms, 1)
+	assert.Equal(t, "team-server", perms[0].Name)
+	assert.Len(t, p.PermissionsFor("team", "trusty-admin"), 2)
+	assert.Empty(t, p.PermissionsFor(identity.GuestRoleName))
+}
+
+func TestPolicyUnaryInterceptor(t *testing.T) {
+	p := &Policy{
+		Permissions: []*Permission{
+			{
+				Name:     "team-server",
+				Roles:    []string{"team"},
+				Methods:  []string{"/pb.CAService/SignCertificate"},
+				Profiles: []string{"server"},
+			},
+		},
+	}
+	auditor := &mockAuditor{}
+	interceptor := p.NewUnaryInterceptor(auditor)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.StatusService/Version"}
+	res, err := interceptor(context.Background(), nil, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", res)
+
+	idn := rolesIdentity{
+		Identity: identity.NewIdentity("jwt_authenticated", "denis", "1"),
+		roles:    []string{"jwt_authenticated", "team"},
+	}
+	ctx := identity.AddToContext(context.Background(), identity.NewRequestContext(idn))
+
+	info = &grpc.UnaryServerInfo{FullMethod: "/pb.CAService/SignCertificate"}
+	res, err = interceptor(ctx, &pb.SignCertificateRequest{Profile: "server"}, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", res)
+	assert.Empty(t, auditor.events)
+
+	_, err = interceptor(ctx, &pb.SignCertificateRequest{Profile: "client"}, info, handler)
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, err.Error(), "profile=client")
+
+	require.Len(t, auditor.events, 1)
+	evt := auditor.events[0]
+	assert.Equal(t, EvtPermissionDenied, evt.eventType)
+	assert.Equal(t, "denis", evt.identity)
+	assert.True(t, strings.HasPrefix(evt.message, "method=/pb.CAService/SignCertificate, roles=jwt_authenticated,team"), evt.message)
+
+	_, err = interceptor(context.Background(), &pb.SignCertificateRequest{Profile: "server"}, info, handler)
+	require.Error(t, err)
+	require.Len(t, auditor.events, 2)
+	assert.Equal(t, identity.GuestRoleName, auditor.events[1].identity)
+}
+
+type mockOwners struct {
+	owners map[uint64]*Owner
+	orgs   map[string][]string
+}
+
+func (m *mockOwners) ResourceOwner(ctx context.Context, method string, req interface{}) (*Owner, error) {
+	if r, ok := req.(*pb.RevokeCertificateRequest); ok {
+		return m.owners[r.Id], nil
+	}
+	return nil, nil
+}
+
+func (m *mockOwners) CallerOrgs(ctx context.Context, idn identity.Identity) ([]string, error) {
+	return m.orgs[idn.UserID()], nil
+}
+
+func TestPolicyConditions(t *testing.T) {
+	p := &Policy{
+		Permissions: []*Permission{
+			{
+				Name:    "revocation",
+				Roles:   []string{"trusty-admin"},
+				Methods: []string{"/pb.CAService/RevokeCertificate"},
+			},
+			{
+				Name:      "team-revocation",
+				Roles:     []string{"team"},
+				Methods:   []string{"/pb.CAService/RevokeCertificate"},
+				Condition: ConditionOwnOrg,
+			},
+			{
+				Name:      "requester-revocation",
+				Roles:     []string{"agent"},
+				Methods:   []string{"/pb.CAService/RevokeCertificate"},
+				Condition: ConditionRequester,
+			},
+		},
+	}
+	require.NoError(t, p.Validate())
+	p.WithOwnerResolver(&mockOwners{
+		owners: map[uint64]*Owner{
+			1: {OrgID: "100", Requester: "denis"},
+			2: {OrgID: "200", Requester: "build-agent"},
+			3: {Requester: "build-agent"},
+		},
+		orgs: map[string][]string{
+			"1": {"100"},
+		},
+	})
+
+	auditor := &mockAuditor{}
+	interceptor := p.NewUnaryInterceptor(auditor)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.CAService/RevokeCertificate"}
+
+	callerContext := func(role, name, userID string) context.Context {
+		return identity.AddToContext(context.Background(),
+			identity.NewRequestContext(identity.NewIdentity(role, name, userID)))
+	}
+
+	tcases := []struct {
+		ctx     context.Context
+		id      uint64
+		allowed bool
+	}{
+		// admin may revoke certificates of any org
+		{callerContext("trusty-admin", "admin", ""), 2, true},
+		// team member may revoke only certificates of own org
+		{callerContext("team", "denis", "1"), 1, true},
+		{callerContext("team", "denis", "1"), 2, false},
+		{callerContext("team", "denis", "1"), 3, false},
+		{callerContext("team", "denis", "1"), 4, false},
+		{callerContext("team", "other", "2"), 1, false},
+		// the requester may revoke own certificates
+		{callerContext("agent", "build-agent", ""), 2, true},
+		{callerContext("agent", "build-agent", ""), 3, true},
+		{callerContext("agent", "build-agent", ""), 1, false},
+		{callerContext("agent", "other-agent", ""), 2, false},
+	}
+	for _, tc := range tcases {
+		_, err := interceptor(tc.ctx, &pb.RevokeCertificateRequest{Id: tc.id}, info, handler)
+		if tc.allowed {
+			assert.NoError(t, err, "%s: %d", identity.FromContext(tc.ctx).Identity(), tc.id)
+		} else {
+			require.Error(t, err, "%s: %d", identity.FromContext(tc.ctx).Identity(), tc.id)
+			assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		}
+	}
+
+	p.Permissions[1].Condition = "owner"
+	assert.EqualError(t, p.Validate(), `permission "team-revocation": condition "owner" not valid`)
+}
@@ -0,0 +1,151 @@
+// Package certlint provides pre-issuance and post-issuance lint checks
+// for X.509 certificates, based on RFC 5280 and CA/B Forum Baseline Requirements.
+//
+// The checks operate on *x509.Certificate, so they can be applied
+// to a TBS template before it is signed, as well as to an existing certificate.
+package certlint
+
+import (
+	"crypto/x509"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Level specifies how a failed lint is treated
+type Level string
+
+const (
+	// LevelError specifies that the failed lint prevents issuance
+	LevelError Level = "error"
+	// LevelWarn specifies that the failed lint is reported, but does not prevent issuance
+	LevelWarn Level = "warn"
+	// LevelIgnore specifies that the lint is not performed
+	LevelIgnore Level = "ignore"
+)
+
+// IsValid returns true if the level is supported
+func (l Level) IsValid() bool {
+	switch l {
+	case LevelError, LevelWarn, LevelIgnore:
+		return true
+	}
+	return false
+}
+
+// Lint provides a single lint check
+type Lint struct {
+	// Name specifies unique name of the lint
+	Name string
+	// Description provides a short description of the lint
+	Description string
+	// Source specifies the standard the lint is based on
+	Source string
+	// Level specifies the default level of the lint
+	Level Level
+
+	check func(crt *x509.Certificate) error
+}
+
+// Result provides a result of a failed lint
+type Result struct {
+	Lint    string `json:"lint" yaml:"lint"`
+	Level   Level  `json:"level" yaml:"level"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// Results provides a list of failed lints
+type Results []Result
+
+// HasErrors returns true if the results contain errors
+func (r Results) HasErrors() bool {
+	for _, res := range r {
+		if res.Level == LevelError {
+			return true
+		}
+	}
+	return false
+}
+
+// Warnings returns results with LevelWarn
+func (r Results) Warnings() Results {
+	var list Results
+	for _, res := range r {
+		if res.Level == LevelWarn {
+			list = append(list, res)
+		}
+	}
+	return list
+}
+
+// Err returns an error if the results contain errors
+func (r Results) Err() error {
+	var msgs []string
+	for _, res := range r {
+		if res.Level == LevelError {
+			msgs = append(msgs, res.Lint+": "+res.Message)
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errors.Errorf("certificate lint failed: %s", strings.Join(msgs, "; "))
+}
+
+// Lints returns the list of supported lints, sorted by name
+func Lints() []Lint {
+	list := make([]Lint, len(registry))
+	copy(list, registry)
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// ValidateLevels returns an error if the levels contain unknown lint names,
+// or unsupported values
+func ValidateLevels(levels map[string]Level) error {
+	for name, level := range levels {
+		if findLint(name) == nil {
+			return errors.Errorf("unknown lint: %s", name)
+		}
+		if !level.IsValid() {
+			return errors.Errorf("invalid level for %s lint: %q", name, level)
+		}
+	}
+	return nil
+}
+
+// Check runs all lints over the certificate or TBS template,
+// and returns the failed lints.
+// The levels parameter allows to override the default level of the lints by name.
+func Check(crt *x509.Certificate, levels map[string]Level) Results {
+	var results Results
+	for _, l := range registry {
+		level := l.Level
+		if override, ok := levels[l.Name]; ok && override != "" {
+			level = override
+		}
+		if level == LevelIgnore {
+			continue
+		}
+		if err := l.check(crt); err != nil {
+			results = append(results, Result{
+				Lint:    l.Name,
+				Level:   level,
+				Message: err.Error(),
+			})
+		}
+	}
+	return results
+}
+
+func findLint(name string) *Lint {
+	for i := range registry {
+		if registry[i].Name == name {
+			return &registry[i]
+		}
+	}
+	return nil
+}
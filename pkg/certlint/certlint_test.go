@@ -0,0 +1,181 @@
+package certlint_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/certlint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validTemplate(t *testing.T) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, ok := new(big.Int).SetString("4c1f4a3e5b9d8c7a6f5e4d3c2b1a09", 16)
+	require.True(t, ok)
+
+	now := time.Now().UTC()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   "www.trusty.com",
+			Organization: []string{"trusty"},
+			Country:      []string{"US"},
+		},
+		NotBefore:   now,
+		NotAfter:    now.Add(90 * 24 * time.Hour),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:    []string{"www.trusty.com", "*.trusty.com"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		PublicKey:   key.Public(),
+	}
+}
+
+func lintNames(results certlint.Results) []string {
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Lint)
+	}
+	return names
+}
+
+func TestLints(t *testing.T) {
+	names := map[string]bool{}
+	for _, l := range certlint.Lints() {
+		assert.NotEmpty(t, l.Description)
+		assert.NotEmpty(t, l.Source)
+		assert.True(t, l.Level.IsValid())
+		assert.False(t, names[l.Name], "duplicate lint: %s", l.Name)
+		names[l.Name] = true
+	}
+}
+
+func TestValidateLevels(t *testing.T) {
+	assert.NoError(t, certlint.ValidateLevels(nil))
+	assert.NoError(t, certlint.ValidateLevels(map[string]certlint.Level{
+		"cn_in_san":         certlint.LevelError,
+		"validity_max_leaf": certlint.LevelIgnore,
+	}))
+
+	err := certlint.ValidateLevels(map[string]certlint.Level{"unknown": certlint.LevelError})
+	require.Error(t, err)
+	assert.Equal(t, "unknown lint: unknown", err.Error())
+
+	err = certlint.ValidateLevels(map[string]certlint.Level{"cn_in_san": "fatal"})
+	require.Error(t, err)
+	assert.Equal(t, `invalid level for cn_in_san lint: "fatal"`, err.Error())
+}
+
+func TestCheckValid(t *testing.T) {
+	results := certlint.Check(validTemplate(t), nil)
+	assert.Empty(t, results)
+	assert.NoError(t, results.Err())
+	assert.False(t, results.HasErrors())
+}
+
+func TestCheck(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	p224Key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	require.NoError(t, err)
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tcases := []struct {
+		name   string
+		modify func(crt *x509.Certificate)
+		exp    []string
+	}{
+		{"serial_negative", func(crt *x509.Certificate) { crt.SerialNumber = big.NewInt(-1) }, []string{"serial_positive", "serial_min_length"}},
+		{"serial_short", func(crt *x509.Certificate) { crt.SerialNumber = big.NewInt(12345) }, []string{"serial_min_length"}},
+		{"serial_long", func(crt *x509.Certificate) {
+			crt.SerialNumber = new(big.Int).Lsh(big.NewInt(1), 160)
+		}, []string{"serial_length"}},
+		{"validity_period", func(crt *x509.Certificate) { crt.NotAfter = crt.NotBefore.Add(-time.Hour) }, []string{"validity_period"}},
+		{"validity_max_leaf", func(crt *x509.Certificate) { crt.NotAfter = crt.NotBefore.Add(400 * 24 * time.Hour) }, []string{"validity_max_leaf"}},
+		{"san_present", func(crt *x509.Certificate) {
+			crt.DNSNames = nil
+			crt.IPAddresses = nil
+		}, []string{"san_present", "cn_in_san"}},
+		{"san_dns_name", func(crt *x509.Certificate) { crt.DNSNames = append(crt.DNSNames, "bad_host.trusty.com") }, []string{"san_dns_name"}},
+		{"san_dns_wildcard", func(crt *x509.Certificate) { crt.DNSNames = append(crt.DNSNames, "www.*.trusty.com") }, []string{"san_dns_name"}},
+		{"cn_in_san", func(crt *x509.Certificate) { crt.Subject.CommonName = "trusty.com" }, []string{"cn_in_san"}},
+		{"subject_empty_without_san", func(crt *x509.Certificate) {
+			crt.Subject = pkix.Name{}
+			crt.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+			crt.DNSNames = nil
+			crt.IPAddresses = nil
+		}, []string{"subject_empty_without_san"}},
+		{"subject_encoding", func(crt *x509.Certificate) { crt.Subject.Organization = []string{"trusty\x00"} }, []string{"subject_encoding"}},
+		{"subject_attribute_length", func(crt *x509.Certificate) {
+			crt.Subject.OrganizationalUnit = []string{strings.Repeat("a", 65)}
+		}, []string{"subject_attribute_length"}},
+		{"subject_country", func(crt *x509.Certificate) { crt.Subject.Country = []string{"USA"} }, []string{"subject_country"}},
+		{"ku_ca_cert_sign", func(crt *x509.Certificate) {
+			crt.IsCA = true
+			crt.ExtKeyUsage = nil
+		}, []string{"ku_ca_cert_sign"}},
+		{"ku_leaf_cert_sign", func(crt *x509.Certificate) { crt.KeyUsage |= x509.KeyUsageCertSign }, []string{"ku_leaf_cert_sign"}},
+		{"ku_key_type", func(crt *x509.Certificate) { crt.KeyUsage |= x509.KeyUsageKeyEncipherment }, []string{"ku_key_type"}},
+		{"ku_key_type_ed25519", func(crt *x509.Certificate) {
+			crt.PublicKey = edKey
+			crt.KeyUsage |= x509.KeyUsageKeyAgreement
+		}, []string{"ku_key_type"}},
+		{"key_size_rsa", func(crt *x509.Certificate) { crt.PublicKey = rsaKey.Public() }, []string{"key_size"}},
+		{"key_size_ecdsa", func(crt *x509.Certificate) { crt.PublicKey = p224Key.Public() }, []string{"key_size"}},
+		{"key_missing", func(crt *x509.Certificate) { crt.PublicKey = nil }, []string{"key_size"}},
+	}
+
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			crt := validTemplate(t)
+			tc.modify(crt)
+			results := certlint.Check(crt, nil)
+			assert.ElementsMatch(t, tc.exp, lintNames(results))
+		})
+	}
+}
+
+func TestCheckLevels(t *testing.T) {
+	crt := validTemplate(t)
+	crt.NotAfter = crt.NotBefore.Add(400 * 24 * time.Hour)
+	crt.SerialNumber = big.NewInt(12345)
+
+	results := certlint.Check(crt, nil)
+	require.Len(t, results, 2)
+	assert.True(t, results.HasErrors())
+	require.Len(t, results.Warnings(), 1)
+	assert.Equal(t, "validity_max_leaf", results.Warnings()[0].Lint)
+	err := results.Err()
+	require.Error(t, err)
+	assert.Equal(t, "certificate lint failed: serial_min_length: serial number has 14 bits", err.Error())
+
+	results = certlint.Check(crt, map[string]certlint.Level{
+		"serial_min_length": certlint.LevelWarn,
+		"validity_max_leaf": certlint.LevelIgnore,
+	})
+	require.Len(t, results, 1)
+	assert.False(t, results.HasErrors())
+	assert.NoError(t, results.Err())
+	assert.Equal(t, certlint.LevelWarn, results[0].Level)
+
+	results = certlint.Check(crt, map[string]certlint.Level{
+		"validity_max_leaf": certlint.LevelError,
+	})
+	err = results.Err()
+	require.Error(t, err)
+	assert.Equal(t, "certificate lint failed: serial_min_length: serial number has 14 bits; validity_max_leaf: validity period is 400 days", err.Error())
+}
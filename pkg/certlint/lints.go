@@ -0,0 +1,405 @@
+package certlint
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/juju/errors"
+)
+
+const (
+	sourceRFC5280 = "RFC 5280"
+	sourceCABF    = "CA/B Forum BR"
+)
+
+// MaxLeafValidity specifies the maximum validity period
+// of a TLS server certificate, per CA/B Forum BR 6.3.2
+const MaxLeafValidity = 398 * 24 * time.Hour
+
+// upper bounds of the subject attributes, per RFC 5280 Appendix A.1
+var subjectAttributeBounds = map[string]struct {
+	name string
+	max  int
+}{
+	"2.5.4.3":  {"CommonName", 64},
+	"2.5.4.5":  {"SerialNumber", 64},
+	"2.5.4.7":  {"Locality", 128},
+	"2.5.4.8":  {"Province", 128},
+	"2.5.4.10": {"Organization", 64},
+	"2.5.4.11": {"OrganizationalUnit", 64},
+	"2.5.4.17": {"PostalCode", 40},
+}
+
+var registry = []Lint{
+	{
+		Name:        "serial_positive",
+		Description: "serial number must be a positive integer",
+		Source:      sourceRFC5280 + " 4.1.2.2",
+		Level:       LevelError,
+		check:       checkSerialPositive,
+	},
+	{
+		Name:        "serial_length",
+		Description: "serial number must not be longer than 20 octets",
+		Source:      sourceRFC5280 + " 4.1.2.2",
+		Level:       LevelError,
+		check:       checkSerialLength,
+	},
+	{
+		Name:        "serial_min_length",
+		Description: "serial number must be at least 64 bits long",
+		Source:      sourceCABF + " 7.1",
+		Level:       LevelError,
+		check:       checkSerialMinLength,
+	},
+	{
+		Name:        "validity_period",
+		Description: "NotAfter must be after NotBefore",
+		Source:      sourceRFC5280 + " 4.1.2.5",
+		Level:       LevelError,
+		check:       checkValidityPeriod,
+	},
+	{
+		Name:        "validity_max_leaf",
+		Description: "validity of TLS server certificate must not exceed 398 days",
+		Source:      sourceCABF + " 6.3.2",
+		Level:       LevelWarn,
+		check:       checkValidityMaxLeaf,
+	},
+	{
+		Name:        "san_present",
+		Description: "TLS server certificate must include Subject Alternative Name",
+		Source:      sourceCABF + " 7.1.2.3",
+		Level:       LevelWarn,
+		check:       checkSANPresent,
+	},
+	{
+		Name:        "san_dns_name",
+		Description: "DNS names in Subject Alternative Name must be valid host names",
+		Source:      sourceRFC5280 + " 4.2.1.6",
+		Level:       LevelError,
+		check:       checkSANDNSName,
+	},
+	{
+		Name:        "cn_in_san",
+		Description: "CommonName of TLS server certificate must be present in Subject Alternative Name",
+		Source:      sourceCABF + " 7.1.4.2.2",
+		Level:       LevelWarn,
+		check:       checkCommonNameInSAN,
+	},
+	{
+		Name:        "subject_empty_without_san",
+		Description: "certificate with empty Subject must include Subject Alternative Name",
+		Source:      sourceRFC5280 + " 4.2.1.6",
+		Level:       LevelWarn,
+		check:       checkSubjectEmptyWithoutSAN,
+	},
+	{
+		Name:        "subject_encoding",
+		Description: "Subject attributes must be valid UTF-8 without control characters",
+		Source:      sourceRFC5280 + " 4.1.2.6",
+		Level:       LevelError,
+		check:       checkSubjectEncoding,
+	},
+	{
+		Name:        "subject_attribute_length",
+		Description: "Subject attributes must not exceed upper bounds",
+		Source:      sourceRFC5280 + " A.1",
+		Level:       LevelError,
+		check:       checkSubjectAttributeLength,
+	},
+	{
+		Name:        "subject_country",
+		Description: "Subject Country must be two-letter ISO 3166-1 code",
+		Source:      sourceCABF + " 7.1.4.2.2",
+		Level:       LevelError,
+		check:       checkSubjectCountry,
+	},
+	{
+		Name:        "ku_ca_cert_sign",
+		Description: "CA certificate must include keyCertSign key usage",
+		Source:      sourceRFC5280 + " 4.2.1.3",
+		Level:       LevelError,
+		check:       checkCACertSign,
+	},
+	{
+		Name:        "ku_leaf_cert_sign",
+		Description: "non-CA certificate must not include keyCertSign key usage",
+		Source:      sourceRFC5280 + " 4.2.1.3",
+		Level:       LevelError,
+		check:       checkLeafCertSign,
+	},
+	{
+		Name:        "ku_key_type",
+		Description: "key usage must be consistent with the public key algorithm",
+		Source:      "RFC 5480 3, RFC 8410 5",
+		Level:       LevelWarn,
+		check:       checkKeyUsageKeyType,
+	},
+	{
+		Name:        "key_size",
+		Description: "RSA key must be at least 2048 bits, ECDSA key must use P-256, P-384 or P-521 curve",
+		Source:      sourceCABF + " 6.1.5",
+		Level:       LevelError,
+		check:       checkKeySize,
+	},
+}
+
+func checkSerialPositive(crt *x509.Certificate) error {
+	if crt.SerialNumber == nil || crt.SerialNumber.Sign() <= 0 {
+		return errors.New("serial number is not positive")
+	}
+	return nil
+}
+
+func checkSerialLength(crt *x509.Certificate) error {
+	if crt.SerialNumber == nil {
+		return nil
+	}
+	// DER encoding adds a leading zero octet if the high bit is set
+	size := len(crt.SerialNumber.Bytes())
+	if crt.SerialNumber.BitLen()%8 == 0 {
+		size++
+	}
+	if size > 20 {
+		return errors.Errorf("serial number is %d octets", size)
+	}
+	return nil
+}
+
+// checkSerialMinLength checks only the size of the serial number,
+// the entropy can not be verified on the certificate,
+// and is provided by the issuer with 159 random bits
+func checkSerialMinLength(crt *x509.Certificate) error {
+	if crt.SerialNumber == nil {
+		return nil
+	}
+	if crt.SerialNumber.BitLen() < 64 {
+		return errors.Errorf("serial number has %d bits", crt.SerialNumber.BitLen())
+	}
+	return nil
+}
+
+func checkValidityPeriod(crt *x509.Certificate) error {
+	if !crt.NotAfter.After(crt.NotBefore) {
+		return errors.Errorf("NotAfter %s is not after NotBefore %s",
+			crt.NotAfter.Format(time.RFC3339), crt.NotBefore.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func checkValidityMaxLeaf(crt *x509.Certificate) error {
+	if crt.IsCA || !isServerAuth(crt) {
+		return nil
+	}
+	if validity := crt.NotAfter.Sub(crt.NotBefore); validity > MaxLeafValidity {
+		return errors.Errorf("validity period is %d days", validity/(24*time.Hour))
+	}
+	return nil
+}
+
+func checkSANPresent(crt *x509.Certificate) error {
+	if crt.IsCA || !isServerAuth(crt) {
+		return nil
+	}
+	if !hasSAN(crt) {
+		return errors.New("Subject Alternative Name is missing")
+	}
+	return nil
+}
+
+func checkSANDNSName(crt *x509.Certificate) error {
+	for _, name := range crt.DNSNames {
+		if err := validateDNSName(name); err != nil {
+			return errors.Annotatef(err, "invalid DNS name %q", name)
+		}
+	}
+	return nil
+}
+
+func checkCommonNameInSAN(crt *x509.Certificate) error {
+	cn := crt.Subject.CommonName
+	if cn == "" || crt.IsCA || !isServerAuth(crt) {
+		return nil
+	}
+	for _, name := range crt.DNSNames {
+		if strings.EqualFold(name, cn) {
+			return nil
+		}
+	}
+	for _, ip := range crt.IPAddresses {
+		if ip.String() == cn {
+			return nil
+		}
+	}
+	return errors.Errorf("CommonName %q is not present in Subject Alternative Name", cn)
+}
+
+func checkSubjectEmptyWithoutSAN(crt *x509.Certificate) error {
+	if len(crt.Subject.ToRDNSequence()) == 0 && !hasSAN(crt) {
+		return errors.New("Subject is empty and Subject Alternative Name is missing")
+	}
+	return nil
+}
+
+func checkSubjectEncoding(crt *x509.Certificate) error {
+	for _, rdn := range crt.Subject.ToRDNSequence() {
+		for _, atv := range rdn {
+			val, ok := atv.Value.(string)
+			if !ok {
+				continue
+			}
+			if !utf8.ValidString(val) {
+				return errors.Errorf("%s is not valid UTF-8", attributeName(atv.Type))
+			}
+			for _, r := range val {
+				if unicode.IsControl(r) {
+					return errors.Errorf("%s contains control character %U", attributeName(atv.Type), r)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func checkSubjectAttributeLength(crt *x509.Certificate) error {
+	for _, rdn := range crt.Subject.ToRDNSequence() {
+		for _, atv := range rdn {
+			val, ok := atv.Value.(string)
+			if !ok {
+				continue
+			}
+			bound, ok := subjectAttributeBounds[atv.Type.String()]
+			if ok && utf8.RuneCountInString(val) > bound.max {
+				return errors.Errorf("%s exceeds %d characters", bound.name, bound.max)
+			}
+		}
+	}
+	return nil
+}
+
+func checkSubjectCountry(crt *x509.Certificate) error {
+	for _, c := range crt.Subject.Country {
+		if len(c) != 2 || c[0] < 'A' || c[0] > 'Z' || c[1] < 'A' || c[1] > 'Z' {
+			return errors.Errorf("invalid Country %q", c)
+		}
+	}
+	return nil
+}
+
+func checkCACertSign(crt *x509.Certificate) error {
+	if crt.IsCA && crt.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return errors.New("keyCertSign is missing")
+	}
+	return nil
+}
+
+func checkLeafCertSign(crt *x509.Certificate) error {
+	if !crt.IsCA && crt.KeyUsage&x509.KeyUsageCertSign != 0 {
+		return errors.New("keyCertSign is not allowed")
+	}
+	return nil
+}
+
+func checkKeyUsageKeyType(crt *x509.Certificate) error {
+	switch crt.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		if crt.KeyUsage&(x509.KeyUsageKeyEncipherment|x509.KeyUsageDataEncipherment) != 0 {
+			return errors.New("keyEncipherment and dataEncipherment are not allowed for ECDSA key")
+		}
+	case ed25519.PublicKey:
+		allowed := x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment |
+			x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+		if crt.KeyUsage&^allowed != 0 {
+			return errors.New("only signing key usages are allowed for Ed25519 key")
+		}
+	}
+	return nil
+}
+
+func checkKeySize(crt *x509.Certificate) error {
+	switch key := crt.PublicKey.(type) {
+	case *rsa.PublicKey:
+		size := key.N.BitLen()
+		if size < 2048 {
+			return errors.Errorf("RSA key size is %d bits", size)
+		}
+		if size%8 != 0 {
+			return errors.Errorf("RSA key size %d is not divisible by 8", size)
+		}
+	case *ecdsa.PublicKey:
+		switch key.Curve {
+		case elliptic.P256(), elliptic.P384(), elliptic.P521():
+		default:
+			return errors.Errorf("unsupported ECDSA curve: %s", key.Curve.Params().Name)
+		}
+	case ed25519.PublicKey:
+	case nil:
+		return errors.New("public key is missing")
+	default:
+		return errors.Errorf("unsupported public key: %T", key)
+	}
+	return nil
+}
+
+func isServerAuth(crt *x509.Certificate) bool {
+	for _, eku := range crt.ExtKeyUsage {
+		if eku == x509.ExtKeyUsageServerAuth || eku == x509.ExtKeyUsageAny {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSAN(crt *x509.Certificate) bool {
+	return len(crt.DNSNames) > 0 ||
+		len(crt.IPAddresses) > 0 ||
+		len(crt.EmailAddresses) > 0 ||
+		len(crt.URIs) > 0
+}
+
+func attributeName(oid asn1.ObjectIdentifier) string {
+	if bound, ok := subjectAttributeBounds[oid.String()]; ok {
+		return bound.name
+	}
+	return fmt.Sprintf("attribute %s", oid.String())
+}
+
+func validateDNSName(name string) error {
+	if name == "" {
+		return errors.New("empty name")
+	}
+	if len(name) > 253 {
+		return errors.New("name exceeds 253 characters")
+	}
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if label == "*" && i == 0 && len(labels) > 2 {
+			continue
+		}
+		if label == "" {
+			return errors.New("empty label")
+		}
+		if len(label) > 63 {
+			return errors.Errorf("label %q exceeds 63 characters", label)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return errors.Errorf("label %q starts or ends with hyphen", label)
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return errors.Errorf("label %q contains invalid character %q", label, c)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+// Package certpolicy provides per-profile issuance policies,
+// evaluated before a certificate is signed.
+//
+// A policy is a list of rules with expressions in
+// Common Expression Language (CEL), see https://github.com/google/cel-spec,
+// which have access to the parsed CSR, the requested SANs,
+// the caller's identity, the org and the current time.
+// An expression returns bool, where false denies the request with the rule's message,
+// or string, where a non-empty value denies the request with the returned reason.
+//
+// The following variables are available to the expressions:
+//
+//	csr.subject.common_name            string
+//	csr.subject.organization           list(string)
+//	csr.subject.organizational_unit    list(string)
+//	csr.subject.country                list(string)
+//	csr.subject.province               list(string)
+//	csr.subject.locality               list(string)
+//	csr.dns_names                      list(string)
+//	csr.email_addresses                list(string)
+//	csr.ip_addresses                   list(string)
+//	csr.uris                           list(string)
+//	csr.key_algorithm                  string: RSA|ECDSA|Ed25519
+//	csr.key_size                       int
+//	san                                list(string), the requested SANs
+//	profile                            string
+//	issuer                             string
+//	caller.id                          string
+//	caller.name                        string
+//	caller.role                        string
+//	caller.roles                       list(string)
+//	caller.orgs                        list(string), the logins of caller's orgs
+//	org_id                             int
+//	now                                timestamp
+package certpolicy
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/juju/errors"
+)
+
+// env provides CEL environment with the variables available to the expressions
+var env = newEnv()
+
+func newEnv() *cel.Env {
+	e, err := cel.NewEnv(cel.Declarations(
+		decls.NewVar("csr", decls.NewMapType(decls.String, decls.Dyn)),
+		decls.NewVar("san", decls.NewListType(decls.String)),
+		decls.NewVar("profile", decls.String),
+		decls.NewVar("issuer", decls.String),
+		decls.NewVar("caller", decls.NewMapType(decls.String, decls.Dyn)),
+		decls.NewVar("org_id", decls.Int),
+		decls.NewVar("now", decls.Timestamp),
+	))
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Rule specifies an expression of the issuance policy
+type Rule struct {
+	// Name of the rule
+	Name string `json:"name" yaml:"name"`
+	// Expression returns bool or string,
+	// false or non-empty string denies the request
+	Expression string `json:"expr" yaml:"expr"`
+	// Message specifies the reason when the bool expression denies the request
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+
+	program cel.Program
+}
+
+// Rules provides the list of rules of the issuance policy
+type Rules []*Rule
+
+// Caller provides identity of the caller
+type Caller struct {
+	ID    string
+	Name  string
+	Role  string
+	Roles []string
+	Orgs  []string
+}
+
+// Input provides attributes of the request to evaluate
+type Input struct {
+	// CSR provides the parsed certificate request
+	CSR *x509.CertificateRequest
+	// SAN provides the requested SANs
+	SAN     []string
+	Profile string
+	Issuer  string
+	Caller  Caller
+	OrgID   uint64
+	// Time specifies the time of the request, if not set then the current time is used
+	Time time.Time
+}
+
+// Decision provides the result of the policy evaluation
+type Decision struct {
+	Allowed bool
+	// Rule specifies the rule that denied the request
+	Rule string
+	// Reason specifies the reason of the denial
+	Reason string
+}
+
+// String returns the decision in human readable format
+func (d *Decision) String() string {
+	if d.Allowed {
+		return "allowed"
+	}
+	return fmt.Sprintf("denied by %q: %s", d.Rule, d.Reason)
+}
+
+// Compile compiles the expression of the rule
+func (r *Rule) Compile() error {
+	if r.Expression == "" {
+		return errors.Errorf("rule %q: missing expression", r.Name)
+	}
+	prg, err := compile(r.Expression)
+	if err != nil {
+		return errors.Annotatef(err, "rule %q", r.Name)
+	}
+	r.program = prg
+	return nil
+}
+
+// Evaluate returns empty string if the request is allowed by the rule,
+// or the reason of the denial
+func (r *Rule) Evaluate(vars map[string]interface{}) (string, error) {
+	prg := r.program
+	if prg == nil {
+		// the rule was not compiled, for example in a copy of the profile
+		var err error
+		prg, err = compile(r.Expression)
+		if err != nil {
+			return "", errors.Annotatef(err, "rule %q", r.Name)
+		}
+	}
+	v, _, err := prg.Eval(vars)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	switch res := v.Value().(type) {
+	case bool:
+		if res {
+			return "", nil
+		}
+		if r.Message != "" {
+			return r.Message, nil
+		}
+		return "expression is false", nil
+	case string:
+		return res, nil
+	}
+	return "", errors.Errorf("expected bool or string result, found %s", v.Type().TypeName())
+}
+
+// compile returns the program of the expression,
+// that must return bool or string
+func compile(expr string) (cel.Program, error) {
+	ast, iss := env.Compile(expr)
+	if iss != nil && iss.Err() != nil {
+		list := make([]string, 0, len(iss.Errors()))
+		for _, e := range iss.Errors() {
+			// add one to the 0-based column for display
+			list = append(list, fmt.Sprintf("%s at %d:%d", e.Message, e.Location.Line(), e.Location.Column()+1))
+		}
+		return nil, errors.New(strings.Join(list, "; "))
+	}
+
+	t := ast.ResultType()
+	if !proto.Equal(t, decls.Bool) && !proto.Equal(t, decls.String) && !proto.Equal(t, decls.Dyn) {
+		return nil, errors.Errorf("expected bool or string result, found %s", cel.FormatType(t))
+	}
+
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return prg, nil
+}
+
+// Compile compiles the expressions of all rules
+func (rules Rules) Compile() error {
+	names := map[string]bool{}
+	for _, r := range rules {
+		if r.Name == "" {
+			return errors.New("missing rule name")
+		}
+		if names[r.Name] {
+			return errors.Errorf("duplicate rule %q", r.Name)
+		}
+		names[r.Name] = true
+		if err := r.Compile(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the decision for the request,
+// the request is denied by the first rule that does not allow it,
+// or by a rule that failed to evaluate
+func (rules Rules) Evaluate(in *Input) *Decision {
+	vars := in.Variables()
+	for _, r := range rules {
+		reason, err := r.Evaluate(vars)
+		if err != nil {
+			return &Decision{Rule: r.Name, Reason: "evaluation error: " + err.Error()}
+		}
+		if reason != "" {
+			return &Decision{Rule: r.Name, Reason: reason}
+		}
+	}
+	return &Decision{Allowed: true}
+}
+
+// Variables returns the variables for the expressions
+func (in *Input) Variables() map[string]interface{} {
+	now := in.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return map[string]interface{}{
+		"csr":     csrVariables(in.CSR),
+		"san":     stringsOrEmpty(in.SAN),
+		"profile": in.Profile,
+		"issuer":  in.Issuer,
+		"caller": map[string]interface{}{
+			"id":    in.Caller.ID,
+			"name":  in.Caller.Name,
+			"role":  in.Caller.Role,
+			"roles": stringsOrEmpty(in.Caller.Roles),
+			"orgs":  stringsOrEmpty(in.Caller.Orgs),
+		},
+		"org_id": int64(in.OrgID),
+		"now":    now.UTC(),
+	}
+}
+
+func csrVariables(csr *x509.CertificateRequest) map[string]interface{} {
+	if csr == nil {
+		csr = &x509.CertificateRequest{}
+	}
+
+	ips := make([]string, len(csr.IPAddresses))
+	for i, ip := range csr.IPAddresses {
+		ips[i] = ip.String()
+	}
+	uris := make([]string, len(csr.URIs))
+	for i, u := range csr.URIs {
+		uris[i] = u.String()
+	}
+
+	var algo string
+	var size int
+	switch key := csr.PublicKey.(type) {
+	case *rsa.PublicKey:
+		algo = "RSA"
+		size = key.N.BitLen()
+	case *ecdsa.PublicKey:
+		algo = "ECDSA"
+		size = key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		algo = "Ed25519"
+		size = 256
+	}
+
+	return map[string]interface{}{
+		"subject": map[string]interface{}{
+			"common_name":         csr.Subject.CommonName,
+			"organization":        stringsOrEmpty(csr.Subject.Organization),
+			"organizational_unit": stringsOrEmpty(csr.Subject.OrganizationalUnit),
+			"country":             stringsOrEmpty(csr.Subject.Country),
+			"province":            stringsOrEmpty(csr.Subject.Province),
+			"locality":            stringsOrEmpty(csr.Subject.Locality),
+		},
+		"dns_names":       stringsOrEmpty(csr.DNSNames),
+		"email_addresses": stringsOrEmpty(csr.EmailAddresses),
+		"ip_addresses":    ips,
+		"uris":            uris,
+		"key_algorithm":   algo,
+		"key_size":        size,
+	}
+}
+
+func stringsOrEmpty(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
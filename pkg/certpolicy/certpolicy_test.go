@@ -0,0 +1,140 @@
+package certpolicy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesCompile(t *testing.T) {
+	assert.NoError(t, Rules{}.Compile())
+
+	tcases := []struct {
+		rules Rules
+		err   string
+	}{
+		{Rules{{Expression: "true"}}, "missing rule name"},
+		{Rules{{Name: "r1", Expression: "true"}, {Name: "r1", Expression: "true"}}, `duplicate rule "r1"`},
+		{Rules{{Name: "r1"}}, `rule "r1": missing expression`},
+		{Rules{{Name: "r1", Expression: "csr.subject.common_name == user"}}, `rule "r1": undeclared reference to 'user' (in container '') at 1:28`},
+		{Rules{{Name: "r1", Expression: "csr.key_size >"}}, `rule "r1": Syntax error`},
+		{Rules{{Name: "r1", Expression: "org_id"}}, `rule "r1": expected bool or string result, found int`},
+		{Rules{{Name: "r1", Expression: "san"}}, `rule "r1": expected bool or string result, found list(string)`},
+	}
+	for _, tc := range tcases {
+		err := tc.rules.Compile()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tc.err)
+	}
+}
+
+func TestRulesEvaluate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   "api.team1.trusty.local",
+			Organization: []string{"ekspand"},
+		},
+		DNSNames:    []string{"api.team1.trusty.local", "www.team1.trusty.local"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+	}, key)
+	require.NoError(t, err)
+	csr, err := x509.ParseCertificateRequest(der)
+	require.NoError(t, err)
+
+	rules := Rules{
+		{
+			Name:       "key",
+			Expression: "csr.key_algorithm == 'ECDSA' && csr.key_size >= 256",
+			Message:    "ECDSA P-256 or stronger key is required",
+		},
+		{
+			Name:       "team-dns",
+			Expression: "csr.dns_names.all(n, caller.orgs.exists(o, n.endsWith('.' + o + '.trusty.local')))",
+			Message:    "DNS names must be in the org's domain",
+		},
+		{
+			Name:       "no-ip",
+			Expression: "size(csr.ip_addresses) == 0 || 'trusty-admin' in caller.roles ? '' : 'IP addresses are allowed only for admins'",
+		},
+		{
+			Name:       "org",
+			Expression: "org_id != 0",
+			Message:    "org is required",
+		},
+		{
+			Name:       "business-hours",
+			Expression: "now.getHours() >= 8 && now.getHours() < 18",
+		},
+	}
+	require.NoError(t, rules.Compile())
+
+	in := &Input{
+		CSR:     csr,
+		Profile: "server",
+		Issuer:  "trusty.svc",
+		Caller: Caller{
+			ID:    "1",
+			Name:  "denis",
+			Role:  "jwt_authenticated",
+			Roles: []string{"jwt_authenticated", "trusty-admin"},
+			Orgs:  []string{"team1"},
+		},
+		OrgID: 123,
+		Time:  time.Date(2021, time.June, 5, 14, 30, 0, 0, time.UTC),
+	}
+
+	d := rules.Evaluate(in)
+	assert.True(t, d.Allowed, d.String())
+	assert.Equal(t, "allowed", d.String())
+
+	in.Caller.Roles = []string{"jwt_authenticated"}
+	d = rules.Evaluate(in)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, "no-ip", d.Rule)
+	assert.Equal(t, `denied by "no-ip": IP addresses are allowed only for admins`, d.String())
+
+	in.Caller.Roles = []string{"trusty-admin"}
+	in.Caller.Orgs = []string{"team2"}
+	d = rules.Evaluate(in)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, "team-dns", d.Rule)
+	assert.Equal(t, "DNS names must be in the org's domain", d.Reason)
+
+	in.Caller.Orgs = []string{"team1"}
+	in.OrgID = 0
+	d = rules.Evaluate(in)
+	assert.Equal(t, "org", d.Rule)
+
+	in.OrgID = 123
+	in.Time = time.Date(2021, time.June, 5, 20, 0, 0, 0, time.UTC)
+	d = rules.Evaluate(in)
+	assert.Equal(t, "business-hours", d.Rule)
+	assert.Equal(t, "expression is false", d.Reason)
+
+	d = Rules{{Name: "err", Expression: "csr.subject.email == ''"}}.Evaluate(in)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, "evaluation error: no such key: email", d.Reason)
+
+	d = Rules{{Name: "int", Expression: "org_id"}}.Evaluate(in)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, `evaluation error: rule "int": expected bool or string result, found int`, d.Reason)
+
+	d = Rules{{Name: "dyn", Expression: "caller.roles"}}.Evaluate(in)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, "evaluation error: expected bool or string result, found list", d.Reason)
+
+	vars := (&Input{SAN: []string{"10.0.0.1"}}).Variables()
+	assert.Equal(t, "", vars["csr"].(map[string]interface{})["key_algorithm"])
+	assert.Equal(t, []string{"10.0.0.1"}, vars["san"])
+}
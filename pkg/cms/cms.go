@@ -0,0 +1,357 @@
+// Package cms provides RFC 5652 Cryptographic Message Syntax SignedData
+// with a single signer and signed attributes,
+// used for time-stamp tokens and detached signatures.
+package cms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+var (
+	// OIDData specifies id-data content type
+	OIDData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	// OIDSignedData specifies id-signedData content type
+	OIDSignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+
+	oidAttrContentType   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidAttrMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidAttrSigningTime   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+
+	oidSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+
+	oidSignatureSHA256RSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidSignatureSHA384RSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 12}
+	oidSignatureSHA512RSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}
+	oidSignatureECDSASHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidSignatureECDSASHA384 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}
+	oidSignatureECDSASHA512 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}
+	oidSignatureEd25519     = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+// signature algorithms by digest for supported key types
+var (
+	// digestOIDs specifies the digests of the signed content,
+	// SHA-1 is supported only for digests computed by the clients,
+	// like time-stamp message imprints
+	digestOIDs = map[crypto.Hash]asn1.ObjectIdentifier{
+		crypto.SHA1:   oidSHA1,
+		crypto.SHA256: oidSHA256,
+		crypto.SHA384: oidSHA384,
+		crypto.SHA512: oidSHA512,
+	}
+	hashOIDs = map[crypto.Hash]asn1.ObjectIdentifier{
+		crypto.SHA256: oidSHA256,
+		crypto.SHA384: oidSHA384,
+		crypto.SHA512: oidSHA512,
+	}
+	rsaSignatures = map[crypto.Hash]asn1.ObjectIdentifier{
+		crypto.SHA256: oidSignatureSHA256RSA,
+		crypto.SHA384: oidSignatureSHA384RSA,
+		crypto.SHA512: oidSignatureSHA512RSA,
+	}
+	ecdsaSignatures = map[crypto.Hash]asn1.ObjectIdentifier{
+		crypto.SHA256: oidSignatureECDSASHA256,
+		crypto.SHA384: oidSignatureECDSASHA384,
+		crypto.SHA512: oidSignatureECDSASHA512,
+	}
+)
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type encapContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     asn1.RawValue `asn1:"optional,explicit,tag:0"`
+}
+
+type issuerAndSerial struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type signerInfo struct {
+	Version            int
+	SID                issuerAndSerial
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+// Attribute provides additional signed attribute
+type Attribute struct {
+	Type asn1.ObjectIdentifier
+	// Value contains DER encoded value of the attribute
+	Value []byte
+}
+
+// DefaultHash returns the digest algorithm for the key
+func DefaultHash(pub crypto.PublicKey) crypto.Hash {
+	if key, ok := pub.(*ecdsa.PublicKey); ok {
+		switch key.Curve.Params().BitSize {
+		case 384:
+			return crypto.SHA384
+		case 521:
+			return crypto.SHA512
+		}
+	}
+	if _, ok := pub.(ed25519.PublicKey); ok {
+		return crypto.SHA512
+	}
+	return crypto.SHA256
+}
+
+// HashOID returns OID of the digest algorithm
+func HashOID(h crypto.Hash) (asn1.ObjectIdentifier, error) {
+	oid, ok := hashOIDs[h]
+	if !ok {
+		return nil, errors.Errorf("unsupported hash algorithm: %v", h)
+	}
+	return oid, nil
+}
+
+// DigestOID returns OID of the digest algorithm of the content
+func DigestOID(h crypto.Hash) (asn1.ObjectIdentifier, error) {
+	oid, ok := digestOIDs[h]
+	if !ok {
+		return nil, errors.Errorf("unsupported hash algorithm: %v", h)
+	}
+	return oid, nil
+}
+
+// DigestByOID returns the digest algorithm by OID
+func DigestByOID(oid asn1.ObjectIdentifier) (crypto.Hash, error) {
+	for h, o := range digestOIDs {
+		if o.Equal(oid) {
+			return h, nil
+		}
+	}
+	return 0, errors.Errorf("unsupported hash algorithm: %s", oid.String())
+}
+
+// Sign returns DER encoded ContentInfo with SignedData,
+// that encapsulates the content.
+// The signing certificate is identified by issuer and serial,
+// the certs are included in SignedData, if provided.
+func Sign(contentType asn1.ObjectIdentifier, content []byte, signer crypto.Signer, cert *x509.Certificate, certs []*x509.Certificate, attrs ...Attribute) ([]byte, error) {
+	h := DefaultHash(signer.Public())
+	d := h.New()
+	d.Write(content)
+
+	return sign(contentType, content, h, d.Sum(nil), signer, cert, certs, attrs)
+}
+
+// SignDetached returns DER encoded ContentInfo with SignedData,
+// that does not include the content, for the digest of the content.
+func SignDetached(contentType asn1.ObjectIdentifier, h crypto.Hash, digest []byte, signer crypto.Signer, cert *x509.Certificate, certs []*x509.Certificate, attrs ...Attribute) ([]byte, error) {
+	if len(digest) != h.Size() {
+		return nil, errors.Errorf("invalid digest size: %d", len(digest))
+	}
+	return sign(contentType, nil, h, digest, signer, cert, certs, attrs)
+}
+
+func sign(contentType asn1.ObjectIdentifier, content []byte, h crypto.Hash, digest []byte, signer crypto.Signer, cert *x509.Certificate, certs []*x509.Certificate, attrs []Attribute) ([]byte, error) {
+	digestAlg, sigAlg, err := signatureAlgorithm(signer.Public(), h)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	signedAttrs, err := encodeSignedAttrs(contentType, digest, attrs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// the signature is computed over the DER encoding of SET OF attributes
+	signedInput, err := asn1.Marshal(asn1.RawValue{
+		Tag:        asn1.TagSet,
+		IsCompound: true,
+		Bytes:      signedAttrs,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var signature []byte
+	if sigAlg.Algorithm.Equal(oidSignatureEd25519) {
+		// Ed25519 signs the message itself
+		signature, err = signer.Sign(rand.Reader, signedInput, crypto.Hash(0))
+	} else {
+		d := h.New()
+		d.Write(signedInput)
+		signature, err = signer.Sign(rand.Reader, d.Sum(nil), h)
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to sign")
+	}
+
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{digestAlg},
+		EncapContentInfo: encapContentInfo{
+			EContentType: contentType,
+		},
+		SignerInfos: []signerInfo{
+			{
+				Version: 1,
+				SID: issuerAndSerial{
+					Issuer:       asn1.RawValue{FullBytes: cert.RawIssuer},
+					SerialNumber: cert.SerialNumber,
+				},
+				DigestAlgorithm: digestAlg,
+				SignedAttrs: asn1.RawValue{
+					Class:      asn1.ClassContextSpecific,
+					Tag:        0,
+					IsCompound: true,
+					Bytes:      signedAttrs,
+				},
+				SignatureAlgorithm: sigAlg,
+				Signature:          signature,
+			},
+		},
+	}
+	if !contentType.Equal(OIDData) {
+		// RFC 5652 5.1: version is 3 for other content types
+		sd.Version = 3
+	}
+
+	if content != nil {
+		eContent, err := asn1.Marshal(content)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sd.EncapContentInfo.EContent = explicit(eContent)
+	}
+
+	if len(certs) > 0 {
+		var raw []byte
+		for _, c := range certs {
+			raw = append(raw, c.Raw...)
+		}
+		sd.Certificates = asn1.RawValue{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        0,
+			IsCompound: true,
+			Bytes:      raw,
+		}
+	}
+
+	sdDER, err := asn1.Marshal(sd)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to encode SignedData")
+	}
+
+	der, err := asn1.Marshal(contentInfo{
+		ContentType: OIDSignedData,
+		Content:     explicit(sdDER),
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return der, nil
+}
+
+// explicit returns [0] EXPLICIT tagged value,
+// RawValue is marshaled as is, ignoring the field parameters
+func explicit(der []byte) asn1.RawValue {
+	return asn1.RawValue{
+		Class:      asn1.ClassContextSpecific,
+		Tag:        0,
+		IsCompound: true,
+		Bytes:      der,
+	}
+}
+
+// signatureAlgorithm returns the digest and signature algorithms for the key
+func signatureAlgorithm(pub crypto.PublicKey, h crypto.Hash) (pkix.AlgorithmIdentifier, pkix.AlgorithmIdentifier, error) {
+	var sigOID asn1.ObjectIdentifier
+	var params asn1.RawValue
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		sigOID = rsaSignatures[h]
+		params = asn1.NullRawValue
+	case *ecdsa.PublicKey:
+		sigOID = ecdsaSignatures[h]
+	case ed25519.PublicKey:
+		if h == crypto.SHA512 {
+			sigOID = oidSignatureEd25519
+		}
+	default:
+		return pkix.AlgorithmIdentifier{}, pkix.AlgorithmIdentifier{}, errors.Errorf("unsupported public key: %T", pub)
+	}
+	if sigOID == nil {
+		return pkix.AlgorithmIdentifier{}, pkix.AlgorithmIdentifier{}, errors.Errorf("unsupported hash algorithm for %T: %v", pub, h)
+	}
+	return pkix.AlgorithmIdentifier{Algorithm: hashOIDs[h]},
+		pkix.AlgorithmIdentifier{Algorithm: sigOID, Parameters: params},
+		nil
+}
+
+func encodeSignedAttrs(contentType asn1.ObjectIdentifier, digest []byte, attrs []Attribute) ([]byte, error) {
+	ct, err := asn1.Marshal(contentType)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	md, err := asn1.Marshal(digest)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	st, err := asn1.Marshal(time.Now().UTC())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	attrs = append([]Attribute{
+		{Type: oidAttrContentType, Value: ct},
+		{Type: oidAttrMessageDigest, Value: md},
+		{Type: oidAttrSigningTime, Value: st},
+	}, attrs...)
+
+	list := make([][]byte, 0, len(attrs))
+	for _, attr := range attrs {
+		der, err := asn1.Marshal(attribute{
+			Type:   attr.Type,
+			Values: []asn1.RawValue{{FullBytes: attr.Value}},
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		list = append(list, der)
+	}
+
+	// DER requires SET OF to be sorted
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i], list[j]) < 0
+	})
+	return bytes.Join(list, nil), nil
+}
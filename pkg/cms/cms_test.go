@@ -0,0 +1,133 @@
+package cms_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/cms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createCert(t *testing.T, signer crypto.Signer) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1234),
+		Subject:      pkix.Name{CommonName: "[TEST] Trusty Signer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, signer.Public(), signer)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestSign(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	content := []byte("trusty")
+
+	for name, signer := range map[string]crypto.Signer{
+		"ECDSA":   ecKey,
+		"RSA":     rsaKey,
+		"Ed25519": edKey,
+	} {
+		t.Run(name, func(t *testing.T) {
+			cert := createCert(t, signer)
+
+			der, err := cms.Sign(cms.OIDData, content, signer, cert, []*x509.Certificate{cert})
+			require.NoError(t, err)
+
+			sd, err := cms.Parse(der)
+			require.NoError(t, err)
+			assert.Equal(t, cms.OIDData, sd.ContentType)
+			assert.Equal(t, content, sd.Content)
+			require.Len(t, sd.Certificates, 1)
+			assert.Equal(t, cert.Raw, sd.Certificates[0].Raw)
+
+			h, err := sd.Hash()
+			require.NoError(t, err)
+			assert.Equal(t, cms.DefaultHash(signer.Public()), h)
+
+			require.NoError(t, sd.Verify(cert, nil))
+			assert.EqualError(t, sd.Verify(cert, make([]byte, h.Size())), "message digest does not match")
+
+			other := createCert(t, ecKey)
+			if name != "ECDSA" {
+				assert.Error(t, sd.Verify(other, nil))
+			}
+		})
+	}
+}
+
+func TestSignDetached(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	cert := createCert(t, key)
+
+	digest := sha512.Sum384([]byte("trusty"))
+
+	_, err = cms.SignDetached(cms.OIDData, crypto.SHA256, digest[:], key, cert, nil)
+	assert.EqualError(t, err, "invalid digest size: 48")
+	_, err = cms.SignDetached(cms.OIDData, crypto.SHA1, make([]byte, 20), key, cert, nil)
+	assert.EqualError(t, err, "unsupported hash algorithm for *ecdsa.PublicKey: SHA-1")
+
+	der, err := cms.SignDetached(cms.OIDData, crypto.SHA384, digest[:], key, cert, nil)
+	require.NoError(t, err)
+
+	sd, err := cms.Parse(der)
+	require.NoError(t, err)
+	assert.Nil(t, sd.Content)
+	assert.Empty(t, sd.Certificates)
+
+	h, err := sd.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, crypto.SHA384, h)
+
+	require.NoError(t, sd.Verify(cert, digest[:]))
+	assert.EqualError(t, sd.Verify(cert, nil), "digest is required for detached signature")
+
+	wrong := sha256.Sum256([]byte("trusty"))
+	assert.EqualError(t, sd.Verify(cert, wrong[:]), "message digest does not match")
+
+	_, err = cms.Parse([]byte("invalid"))
+	assert.Error(t, err)
+}
+
+func TestDigestOID(t *testing.T) {
+	for _, h := range []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA384, crypto.SHA512} {
+		oid, err := cms.DigestOID(h)
+		require.NoError(t, err)
+		h2, err := cms.DigestByOID(oid)
+		require.NoError(t, err)
+		assert.Equal(t, h, h2)
+	}
+
+	_, err := cms.DigestOID(crypto.MD5)
+	assert.EqualError(t, err, "unsupported hash algorithm: MD5")
+	_, err = cms.DigestByOID(asn1.ObjectIdentifier{1, 2, 3})
+	assert.EqualError(t, err, "unsupported hash algorithm: 1.2.3")
+
+	_, err = cms.HashOID(crypto.SHA1)
+	assert.Error(t, err, "SHA-1 is not supported for signing")
+}
@@ -0,0 +1,172 @@
+package cms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/asn1"
+
+	"github.com/juju/errors"
+)
+
+// SignedData provides parsed SignedData
+type SignedData struct {
+	// Raw contains DER encoded ContentInfo
+	Raw []byte
+	// ContentType specifies the type of encapsulated content
+	ContentType asn1.ObjectIdentifier
+	// Content contains encapsulated content, or nil if detached
+	Content []byte
+	// Certificates contains the certificates included in SignedData
+	Certificates []*x509.Certificate
+
+	signerInfo  signerInfo
+	signedAttrs []byte
+	attrs       []attribute
+}
+
+// Parse parses DER encoded ContentInfo with SignedData
+func Parse(der []byte) (*SignedData, error) {
+	var ci contentInfo
+	rest, err := asn1.Unmarshal(der, &ci)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to parse ContentInfo")
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("trailing data after ContentInfo")
+	}
+	if !ci.ContentType.Equal(OIDSignedData) {
+		return nil, errors.Errorf("unsupported content type: %s", ci.ContentType.String())
+	}
+
+	var sd signedData
+	_, err = asn1.Unmarshal(ci.Content.Bytes, &sd)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to parse SignedData")
+	}
+	if len(sd.SignerInfos) != 1 {
+		return nil, errors.Errorf("invalid number of signers: %d", len(sd.SignerInfos))
+	}
+
+	s := &SignedData{
+		Raw:         der,
+		ContentType: sd.EncapContentInfo.EContentType,
+		signerInfo:  sd.SignerInfos[0],
+	}
+
+	if len(sd.EncapContentInfo.EContent.Bytes) > 0 {
+		_, err = asn1.Unmarshal(sd.EncapContentInfo.EContent.Bytes, &s.Content)
+		if err != nil {
+			return nil, errors.Annotate(err, "failed to parse content")
+		}
+	}
+
+	if len(sd.Certificates.Bytes) > 0 {
+		s.Certificates, err = x509.ParseCertificates(sd.Certificates.Bytes)
+		if err != nil {
+			return nil, errors.Annotate(err, "failed to parse certificates")
+		}
+	}
+
+	if len(s.signerInfo.SignedAttrs.FullBytes) == 0 {
+		return nil, errors.New("missing signed attributes")
+	}
+	// replace [0] IMPLICIT with SET tag
+	s.signedAttrs = append([]byte{}, s.signerInfo.SignedAttrs.FullBytes...)
+	s.signedAttrs[0] = 0x31
+
+	_, err = asn1.UnmarshalWithParams(s.signedAttrs, &s.attrs, "set")
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to parse signed attributes")
+	}
+	for _, attr := range s.attrs {
+		if len(attr.Values) != 1 {
+			return nil, errors.Errorf("invalid attribute: %s", attr.Type.String())
+		}
+	}
+
+	return s, nil
+}
+
+// Hash returns the digest algorithm of the signer
+func (s *SignedData) Hash() (crypto.Hash, error) {
+	for h, oid := range hashOIDs {
+		if s.signerInfo.DigestAlgorithm.Algorithm.Equal(oid) {
+			return h, nil
+		}
+	}
+	return 0, errors.Errorf("unsupported digest algorithm: %s", s.signerInfo.DigestAlgorithm.Algorithm.String())
+}
+
+// Attribute returns DER encoded value of the signed attribute
+func (s *SignedData) Attribute(oid asn1.ObjectIdentifier) []byte {
+	for _, attr := range s.attrs {
+		if attr.Type.Equal(oid) {
+			return attr.Values[0].FullBytes
+		}
+	}
+	return nil
+}
+
+// Verify verifies the signature with the signer's certificate.
+// The digest of the content is computed if not provided,
+// the digest is required for detached signatures.
+func (s *SignedData) Verify(cert *x509.Certificate, digest []byte) error {
+	sid := s.signerInfo.SID
+	if !bytes.Equal(sid.Issuer.FullBytes, cert.RawIssuer) || sid.SerialNumber.Cmp(cert.SerialNumber) != 0 {
+		return errors.New("not signed by the certificate")
+	}
+
+	h, err := s.Hash()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, sigAlg, err := signatureAlgorithm(cert.PublicKey, h)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !s.signerInfo.SignatureAlgorithm.Algorithm.Equal(sigAlg.Algorithm) {
+		return errors.New("unexpected signature algorithm")
+	}
+
+	if digest == nil {
+		if s.Content == nil {
+			return errors.New("digest is required for detached signature")
+		}
+		d := h.New()
+		d.Write(s.Content)
+		digest = d.Sum(nil)
+	}
+
+	var md []byte
+	if _, err = asn1.Unmarshal(s.Attribute(oidAttrMessageDigest), &md); err != nil || !bytes.Equal(md, digest) {
+		return errors.New("message digest does not match")
+	}
+	var ct asn1.ObjectIdentifier
+	if _, err = asn1.Unmarshal(s.Attribute(oidAttrContentType), &ct); err != nil || !ct.Equal(s.ContentType) {
+		return errors.New("invalid content type attribute")
+	}
+
+	var algo x509.SignatureAlgorithm
+	switch {
+	case sigAlg.Algorithm.Equal(oidSignatureSHA256RSA):
+		algo = x509.SHA256WithRSA
+	case sigAlg.Algorithm.Equal(oidSignatureSHA384RSA):
+		algo = x509.SHA384WithRSA
+	case sigAlg.Algorithm.Equal(oidSignatureSHA512RSA):
+		algo = x509.SHA512WithRSA
+	case sigAlg.Algorithm.Equal(oidSignatureECDSASHA256):
+		algo = x509.ECDSAWithSHA256
+	case sigAlg.Algorithm.Equal(oidSignatureECDSASHA384):
+		algo = x509.ECDSAWithSHA384
+	case sigAlg.Algorithm.Equal(oidSignatureECDSASHA512):
+		algo = x509.ECDSAWithSHA512
+	case sigAlg.Algorithm.Equal(oidSignatureEd25519):
+		algo = x509.PureEd25519
+	}
+	err = cert.CheckSignature(algo, s.signedAttrs, s.signerInfo.Signature)
+	if err != nil {
+		return errors.Annotate(err, "invalid signature")
+	}
+	return nil
+}
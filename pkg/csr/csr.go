@@ -182,17 +182,17 @@ func appendIf(s string, a *[]string) {
 	}
 }
 
-// Parse takes an incoming certificate request and
-// builds a certificate template from it.
+// Parse takes an incoming certificate request, verifies its signature,
+// and builds a certificate template from it.
 func Parse(csrBytes []byte) (*x509.Certificate, error) {
 	csrv, err := x509.ParseCertificateRequest(csrBytes)
 	if err != nil {
 		return nil, errors.Annotatef(err, "failed to parse")
 	}
 
-	err = csrv.CheckSignature()
+	err = CheckSignature(csrv)
 	if err != nil {
-		return nil, errors.Annotatef(err, "key mismatch")
+		return nil, errors.Trace(err)
 	}
 
 	template := &x509.Certificate{
@@ -228,10 +228,48 @@ func Parse(csrBytes []byte) (*x509.Certificate, error) {
 	return template, nil
 }
 
-// ParsePEM takes an incoming certificate request and
-// builds a certificate template from it.
+// CheckSignature verifies the self-signature of the certificate request,
+// that proves possession of the private key by the requester
+func CheckSignature(csrv *x509.CertificateRequest) error {
+	if csrv.SignatureAlgorithm == x509.UnknownSignatureAlgorithm {
+		return errors.New("invalid CSR signature: unsupported signature algorithm")
+	}
+	if err := csrv.CheckSignature(); err != nil {
+		return errors.Annotatef(err, "invalid CSR signature: algorithm=%s, subject=%q",
+			csrv.SignatureAlgorithm, csrv.Subject.String())
+	}
+	return nil
+}
+
+// ParsePEM takes an incoming PEM-encoded certificate request, verifies its signature,
+// and builds a certificate template from it.
 func ParsePEM(csrPEM []byte) (*x509.Certificate, error) {
-	block, _ := pem.Decode([]byte(csrPEM))
+	block, err := decodeRequestPEM(csrPEM)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return Parse(block.Bytes)
+}
+
+// ParseRequestPEM takes an incoming PEM-encoded certificate request,
+// and returns the parsed request with verified signature.
+func ParseRequestPEM(csrPEM []byte) (*x509.CertificateRequest, error) {
+	block, err := decodeRequestPEM(csrPEM)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	csrv, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to parse")
+	}
+	if err = CheckSignature(csrv); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return csrv, nil
+}
+
+func decodeRequestPEM(csrPEM []byte) (*pem.Block, error) {
+	block, _ := pem.Decode(csrPEM)
 	if block == nil {
 		return nil, errors.New("unable to parse PEM")
 	}
@@ -239,8 +277,7 @@ func ParsePEM(csrPEM []byte) (*x509.Certificate, error) {
 	if block.Type != "NEW CERTIFICATE REQUEST" && block.Type != "CERTIFICATE REQUEST" {
 		return nil, errors.Errorf("unsupported type in PEM: " + block.Type)
 	}
-
-	return Parse(block.Bytes)
+	return block, nil
 }
 
 type subjectPublicKeyInfo struct {
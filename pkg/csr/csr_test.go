@@ -1,7 +1,12 @@
 package csr_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"testing"
 
 	"github.com/ekspand/trusty/pkg/csr"
@@ -121,6 +126,10 @@ r6sX2RQbFtUPYEjg2EJdwo8KP0KMzDQEzdh0TzkFaTSxBvMjSR9L2HuntIYCMCuZ
 	require.NoError(t, err)
 	assert.Equal(t, "C=US, L=WA, O=trusty.com, CN=[TEST] Trusty Level 1 CA", certutil.NameToString(&crt.Subject))
 
+	req, err := csr.ParseRequestPEM([]byte(pem))
+	require.NoError(t, err)
+	assert.Equal(t, "C=US, L=WA, O=trusty.com, CN=[TEST] Trusty Level 1 CA", certutil.NameToString(&req.Subject))
+
 	pem = `-----BEGIN CERTIFICATE REQUEST-----
 	MIICiDCCAXACAQAwQzELMAkGA1UEBhMCVVMxCzAJBgNVBAcTAldBMRMwEQYDVQQK
 	Ewp0cnVzdHkuY29tMRIwEAYDVQQDEwlsb2NhbGhvc3QwggEiMA0GCSqGSIb3DQEB
@@ -139,6 +148,34 @@ Ewp0cnVzdHkuY29tMRIwEAYDVQQDEwlsb2NhbGhvc3QwggEiMA0GCSqGSIb3DQEB
 	_, err = csr.ParsePEM([]byte(pem))
 	require.Error(t, err)
 	assert.Equal(t, "unsupported type in PEM: CERTIFICATE", err.Error())
+
+	_, err = csr.ParseRequestPEM([]byte(pem))
+	require.Error(t, err)
+	assert.Equal(t, "unsupported type in PEM: CERTIFICATE", err.Error())
+}
+
+func TestParseInvalidSignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "trusty.com"},
+	}, key)
+	require.NoError(t, err)
+
+	_, err = csr.Parse(der)
+	require.NoError(t, err)
+
+	// corrupt the last byte of the signature
+	der[len(der)-1] ^= 0xff
+
+	_, err = csr.ParsePEM(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+	require.Error(t, err)
+	assert.Equal(t, `invalid CSR signature: algorithm=ECDSA-SHA256, subject="CN=trusty.com": x509: ECDSA verification failure`, err.Error())
+
+	_, err = csr.ParseRequestPEM(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+	require.Error(t, err)
+	assert.Equal(t, `invalid CSR signature: algorithm=ECDSA-SHA256, subject="CN=trusty.com": x509: ECDSA verification failure`, err.Error())
 }
 
 func TestSetSAN(t *testing.T) {
@@ -3,6 +3,7 @@ package csr
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
@@ -150,6 +151,48 @@ func (c *Provider) GenerateKeyAndRequest(req *CertificateRequest) (csrPEM []byte
 	return
 }
 
+// NewSigner returns crypto.Signer for the PEM encoded key, or PKCS#11 URI.
+// If prov is nil, then only PEM encoded keys are supported.
+func NewSigner(prov *cryptoprov.Crypto, key []byte) (crypto.Signer, error) {
+	var pvk crypto.PrivateKey
+	var err error
+	if prov != nil {
+		_, pvk, err = prov.LoadPrivateKey(key)
+	} else {
+		pvk, err = cryptoprov.ParsePrivateKeyPEM(key)
+	}
+	if err != nil {
+		// cryptoprov does not support Ed25519 keys in PEM format
+		ed, edErr := parseEd25519PrivateKeyPEM(key)
+		if edErr != nil {
+			return nil, errors.Trace(err)
+		}
+		pvk = ed
+	}
+
+	signer, supported := pvk.(crypto.Signer)
+	if !supported {
+		return nil, errors.Errorf("loaded key of %T type does not support crypto.Signer", pvk)
+	}
+	return signer, nil
+}
+
+func parseEd25519PrivateKeyPEM(key []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(key)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("invalid PEM")
+	}
+	pvk, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ed, ok := pvk.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("unsupported key: %T", pvk)
+	}
+	return ed, nil
+}
+
 // DefaultSigAlgo returns an appropriate X.509 signature algorithm given
 // the CA's private key.
 func DefaultSigAlgo(priv crypto.Signer) x509.SignatureAlgorithm {
@@ -178,6 +221,8 @@ func DefaultSigAlgo(priv crypto.Signer) x509.SignatureAlgorithm {
 		default:
 			return x509.ECDSAWithSHA1
 		}
+	case ed25519.PublicKey:
+		return x509.PureEd25519
 	default:
 		return x509.UnknownSignatureAlgorithm
 	}
@@ -2,6 +2,12 @@ package csr_test
 
 import (
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 
 	"github.com/ekspand/trusty/pkg/csr"
@@ -141,3 +147,23 @@ func TestCreateRequestAndExportKey(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSigner(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	for _, key := range []crypto.Signer{edKey, ecKey} {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		pemKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+		signer, err := csr.NewSigner(nil, pemKey)
+		require.NoError(t, err)
+		assert.Equal(t, key.Public(), signer.Public())
+	}
+
+	_, err = csr.NewSigner(nil, []byte("not a key"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,130 @@
+package csr
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"strings"
+
+	"github.com/go-phorce/dolly/algorithms/slices"
+	"github.com/juju/errors"
+)
+
+const (
+	// AlgoRSA specifies RSA key algorithm
+	AlgoRSA = "RSA"
+	// AlgoECDSA specifies ECDSA key algorithm
+	AlgoECDSA = "ECDSA"
+	// AlgoEd25519 specifies Ed25519 key algorithm
+	AlgoEd25519 = "Ed25519"
+
+	// MinRSAKeySize specifies the minimum size of RSA key in bits
+	MinRSAKeySize = 2048
+)
+
+var supportedCurves = []string{"P-256", "P-384", "P-521"}
+
+// KeyPolicy specifies allowed key algorithms and sizes
+type KeyPolicy struct {
+	// Algorithms specifies allowed key algorithms: RSA|ECDSA|Ed25519.
+	// If not provided, then all algorithms are allowed
+	Algorithms []string `json:"algorithms,omitempty" yaml:"algorithms,omitempty"`
+
+	// MinRSASize specifies the minimum size of RSA key in bits.
+	// If not provided, then MinRSAKeySize is used
+	MinRSASize int `json:"min_rsa_size,omitempty" yaml:"min_rsa_size,omitempty"`
+
+	// ECDSACurves specifies allowed ECDSA curves: P-256|P-384|P-521.
+	// If not provided, then all curves are allowed
+	ECDSACurves []string `json:"ecdsa_curves,omitempty" yaml:"ecdsa_curves,omitempty"`
+}
+
+// DefaultKeyPolicy returns a policy that allows RSA keys
+// of at least 2048 bits, ECDSA keys on P-256, P-384 or P-521 curves,
+// and Ed25519 keys
+func DefaultKeyPolicy() *KeyPolicy {
+	return &KeyPolicy{}
+}
+
+// Validate returns an error if the policy is invalid
+func (p *KeyPolicy) Validate() error {
+	for _, algo := range p.Algorithms {
+		if normalizeAlgo(algo) == "" {
+			return errors.Errorf("unsupported key algorithm: %s", algo)
+		}
+	}
+	if p.MinRSASize != 0 && p.MinRSASize < MinRSAKeySize {
+		return errors.Errorf("min_rsa_size must be at least %d bits", MinRSAKeySize)
+	}
+	for _, curve := range p.ECDSACurves {
+		if !slices.ContainsString(supportedCurves, curve) {
+			return errors.Errorf("unsupported ECDSA curve: %s", curve)
+		}
+	}
+	return nil
+}
+
+// GetMinRSASize returns the minimum size of RSA key in bits
+func (p *KeyPolicy) GetMinRSASize() int {
+	if p.MinRSASize > MinRSAKeySize {
+		return p.MinRSASize
+	}
+	return MinRSAKeySize
+}
+
+// IsAllowedAlgorithm returns true if the key algorithm is allowed by the policy
+func (p *KeyPolicy) IsAllowedAlgorithm(algo string) bool {
+	if len(p.Algorithms) == 0 {
+		return true
+	}
+	for _, allowed := range p.Algorithms {
+		if normalizeAlgo(allowed) == algo {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns an error if the public key is not allowed by the policy
+func (p *KeyPolicy) Check(pub crypto.PublicKey) error {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		if !p.IsAllowedAlgorithm(AlgoRSA) {
+			return errors.New("RSA key is not allowed")
+		}
+		if size := key.N.BitLen(); size < p.GetMinRSASize() {
+			return errors.Errorf("RSA key is too weak: %d bits, minimum %d bits is required", size, p.GetMinRSASize())
+		}
+	case *ecdsa.PublicKey:
+		if !p.IsAllowedAlgorithm(AlgoECDSA) {
+			return errors.New("ECDSA key is not allowed")
+		}
+		curve := key.Curve.Params().Name
+		if !slices.ContainsString(supportedCurves, curve) {
+			return errors.Errorf("unsupported ECDSA curve: %s", curve)
+		}
+		if len(p.ECDSACurves) > 0 && !slices.ContainsString(p.ECDSACurves, curve) {
+			return errors.Errorf("ECDSA curve is not allowed: %s", curve)
+		}
+	case ed25519.PublicKey:
+		if !p.IsAllowedAlgorithm(AlgoEd25519) {
+			return errors.New("Ed25519 key is not allowed")
+		}
+	default:
+		return errors.Errorf("unsupported public key: %T", pub)
+	}
+	return nil
+}
+
+func normalizeAlgo(algo string) string {
+	switch strings.ToUpper(algo) {
+	case "RSA":
+		return AlgoRSA
+	case "ECDSA":
+		return AlgoECDSA
+	case "ED25519":
+		return AlgoEd25519
+	}
+	return ""
+}
@@ -0,0 +1,192 @@
+package csr_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyPolicyValidate(t *testing.T) {
+	tcases := []struct {
+		p   *csr.KeyPolicy
+		err string
+	}{
+		{csr.DefaultKeyPolicy(), ""},
+		{&csr.KeyPolicy{Algorithms: []string{"rsa", "ECDSA", "Ed25519"}, MinRSASize: 3072, ECDSACurves: []string{"P-384"}}, ""},
+		{&csr.KeyPolicy{Algorithms: []string{"DSA"}}, "unsupported key algorithm: DSA"},
+		{&csr.KeyPolicy{MinRSASize: 1024}, "min_rsa_size must be at least 2048 bits"},
+		{&csr.KeyPolicy{ECDSACurves: []string{"P-224"}}, "unsupported ECDSA curve: P-224"},
+	}
+	for _, tc := range tcases {
+		err := tc.p.Validate()
+		if tc.err == "" {
+			assert.NoError(t, err)
+		} else {
+			require.Error(t, err)
+			assert.Equal(t, tc.err, err.Error())
+		}
+	}
+}
+
+func TestKeyPolicyCheck(t *testing.T) {
+	rsa1024, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	rsa2048, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p224, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	require.NoError(t, err)
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	def := csr.DefaultKeyPolicy()
+	assert.NoError(t, def.Check(rsa2048.Public()))
+	assert.NoError(t, def.Check(p256.Public()))
+	assert.NoError(t, def.Check(p384.Public()))
+	assert.NoError(t, def.Check(edKey))
+
+	err = def.Check(rsa1024.Public())
+	require.Error(t, err)
+	assert.Equal(t, "RSA key is too weak: 1024 bits, minimum 2048 bits is required", err.Error())
+
+	err = def.Check(p224.Public())
+	require.Error(t, err)
+	assert.Equal(t, "unsupported ECDSA curve: P-224", err.Error())
+
+	err = def.Check("key")
+	require.Error(t, err)
+	assert.Equal(t, "unsupported public key: string", err.Error())
+
+	strict := &csr.KeyPolicy{
+		Algorithms:  []string{"ecdsa"},
+		ECDSACurves: []string{"P-384"},
+	}
+	assert.NoError(t, strict.Check(p384.Public()))
+
+	err = strict.Check(p256.Public())
+	require.Error(t, err)
+	assert.Equal(t, "ECDSA curve is not allowed: P-256", err.Error())
+
+	err = strict.Check(rsa2048.Public())
+	require.Error(t, err)
+	assert.Equal(t, "RSA key is not allowed", err.Error())
+
+	err = strict.Check(edKey)
+	require.Error(t, err)
+	assert.Equal(t, "Ed25519 key is not allowed", err.Error())
+
+	minSize := &csr.KeyPolicy{MinRSASize: 3072}
+	err = minSize.Check(rsa2048.Public())
+	require.Error(t, err)
+	assert.Equal(t, "RSA key is too weak: 2048 bits, minimum 3072 bits is required", err.Error())
+}
+
+func TestWeakKeys(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var nilKeys *csr.WeakKeys
+	assert.Equal(t, 0, nilKeys.Len())
+	assert.NoError(t, nilKeys.Check(key.Public()))
+
+	h := sha1.Sum([]byte("Modulus=" + strings.ToUpper(key.N.Text(16)) + "\n"))
+	fp := hex.EncodeToString(h[:])
+
+	dir, err := ioutil.TempDir("", "weakkeys")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "blacklist.RSA-2048")
+	err = ioutil.WriteFile(file, []byte(fmt.Sprintf("# comment\n\n%s\n%s\n", fp[20:], strings.Repeat("a", 40))), 0644)
+	require.NoError(t, err)
+
+	w, err := csr.LoadWeakKeys(file)
+	require.NoError(t, err)
+	assert.Equal(t, 2, w.Len())
+	assert.True(t, w.IsBlocked(key.N))
+	assert.False(t, w.IsBlocked(other.N))
+
+	err = w.Check(key.Public())
+	require.Error(t, err)
+	assert.Equal(t, "RSA key is in the weak keys blocklist", err.Error())
+	assert.NoError(t, w.Check(other.Public()))
+
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	assert.NoError(t, w.Check(p256.Public()))
+
+	invalid := filepath.Join(dir, "invalid")
+	err = ioutil.WriteFile(invalid, []byte("not-hex\n"), 0644)
+	require.NoError(t, err)
+	_, err = csr.LoadWeakKeys(invalid)
+	require.Error(t, err)
+	assert.Equal(t, fmt.Sprintf("unable to load weak keys from %q: invalid entry at line 1", invalid), err.Error())
+
+	_, err = csr.LoadWeakKeys(filepath.Join(dir, "notfound"))
+	require.Error(t, err)
+}
+
+func TestWeakKeysExponent(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	err = (*csr.WeakKeys)(nil).Check(&rsa.PublicKey{N: key.N, E: 3})
+	require.Error(t, err)
+	assert.Equal(t, "RSA key has small public exponent: 3", err.Error())
+
+	err = (*csr.WeakKeys)(nil).Check(&rsa.PublicKey{N: key.N, E: 65538})
+	require.Error(t, err)
+	assert.Equal(t, "RSA key has even public exponent: 65538", err.Error())
+
+	even := new(big.Int).Add(key.N, big.NewInt(1))
+	err = (*csr.WeakKeys)(nil).Check(&rsa.PublicKey{N: even, E: 65537})
+	require.Error(t, err)
+	assert.Equal(t, "RSA key has even modulus", err.Error())
+}
+
+func TestROCA(t *testing.T) {
+	for i := 0; i < 5; i++ {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		assert.False(t, csr.IsROCAModulus(key.N))
+	}
+
+	// modulus with ROCA fingerprint: N = M*r + 65537^2,
+	// where M is the product of the small primes
+	primes := []int64{3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71, 73,
+		79, 83, 89, 97, 101, 103, 107, 109, 113, 127, 131, 137, 139, 149, 151, 157, 163, 167}
+	m := big.NewInt(1)
+	for _, p := range primes {
+		m.Mul(m, big.NewInt(p))
+	}
+	r, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 1800))
+	require.NoError(t, err)
+	r.SetBit(r, 0, 0)
+	n := new(big.Int).Mul(m, r)
+	n.Add(n, new(big.Int).Exp(big.NewInt(65537), big.NewInt(2), nil))
+
+	assert.True(t, csr.IsROCAModulus(n))
+	err = (*csr.WeakKeys)(nil).Check(&rsa.PublicKey{N: n, E: 65537})
+	require.Error(t, err)
+	assert.Equal(t, "RSA key is vulnerable to ROCA (CVE-2017-15361)", err.Error())
+}
@@ -33,6 +33,11 @@ const (
 	EncryptionKey KeyPurpose = 2
 )
 
+// Ed25519KeyGenerator is implemented by crypto providers that support Ed25519 keys
+type Ed25519KeyGenerator interface {
+	GenerateEd25519Key(label string) (crypto.PrivateKey, error)
+}
+
 // KeyRequest contains the algorithm and key size for a new private key.
 type KeyRequest interface {
 	Algo() string
@@ -79,7 +84,7 @@ func (kr *keyRequest) SigAlgo() x509.SignatureAlgorithm {
 }
 
 // Generate generates a key as specified in the request. Currently,
-// only ECDSA and RSA are supported.
+// ECDSA, RSA and Ed25519 are supported.
 func (kr *keyRequest) Generate() (crypto.PrivateKey, error) {
 	switch algo := kr.Algo(); strings.ToUpper(algo) {
 	case "RSA":
@@ -114,6 +119,16 @@ func (kr *keyRequest) Generate() (crypto.PrivateKey, error) {
 			return nil, errors.Annotate(err, "generate ECDSA key")
 		}
 		return pk, nil
+	case "ED25519":
+		gen, ok := kr.prov.(Ed25519KeyGenerator)
+		if !ok {
+			return nil, errors.Errorf("Ed25519 is not supported by %s provider", kr.prov.Manufacturer())
+		}
+		pk, err := gen.GenerateEd25519Key(kr.Label())
+		if err != nil {
+			return nil, errors.Annotate(err, "generate Ed25519 key")
+		}
+		return pk, nil
 	default:
 		return nil, errors.Errorf("invalid algorithm: %s", algo)
 	}
@@ -188,7 +203,23 @@ func SigAlgo(algo string, size int) x509.SignatureAlgorithm {
 		default:
 			return x509.ECDSAWithSHA1
 		}
+	case "ED25519":
+		return x509.PureEd25519
 	default:
 		return x509.UnknownSignatureAlgorithm
 	}
 }
+
+// PSSSigAlgo returns RSA-PSS signature algorithm
+// for the given RSA PKCS#1 v1.5 signature algorithm
+func PSSSigAlgo(algo x509.SignatureAlgorithm) (x509.SignatureAlgorithm, error) {
+	switch algo {
+	case x509.SHA256WithRSA, x509.SHA256WithRSAPSS:
+		return x509.SHA256WithRSAPSS, nil
+	case x509.SHA384WithRSA, x509.SHA384WithRSAPSS:
+		return x509.SHA384WithRSAPSS, nil
+	case x509.SHA512WithRSA, x509.SHA512WithRSAPSS:
+		return x509.SHA512WithRSAPSS, nil
+	}
+	return x509.UnknownSignatureAlgorithm, errors.Errorf("RSA-PSS is not supported for %s", algo)
+}
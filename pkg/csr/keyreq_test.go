@@ -8,6 +8,7 @@ import (
 
 	"github.com/ekspand/trusty/pkg/csr"
 	"github.com/ekspand/trusty/pkg/inmemcrypto"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -32,6 +33,8 @@ func TestKeyRequest(t *testing.T) {
 		{"ECDSA", 256, x509.ECDSAWithSHA256, ""},
 		{"ECDSA", 128, x509.ECDSAWithSHA1, "validate ECDSA key: invalid curve size: 128"},
 		{"DSA", 256, x509.UnknownSignatureAlgorithm, "invalid algorithm: DSA"},
+		{"ed25519", 0, x509.PureEd25519, ""},
+		{"Ed25519", 256, x509.PureEd25519, ""},
 	}
 
 	for _, tc := range tt {
@@ -52,3 +55,38 @@ func TestKeyRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestKeyRequestEd25519NotSupported(t *testing.T) {
+	// wrapped provider does not implement Ed25519KeyGenerator
+	prov := struct{ cryptoprov.Provider }{inmemcrypto.NewProvider()}
+	kr := csr.NewKeyRequest(prov, "ed25519", "ED25519", 0, csr.SigningKey)
+	_, err := kr.Generate()
+	require.Error(t, err)
+	assert.Equal(t, "Ed25519 is not supported by trusty provider", err.Error())
+}
+
+func TestPSSSigAlgo(t *testing.T) {
+	tt := []struct {
+		algo   x509.SignatureAlgorithm
+		expalg x509.SignatureAlgorithm
+		experr string
+	}{
+		{x509.SHA256WithRSA, x509.SHA256WithRSAPSS, ""},
+		{x509.SHA384WithRSA, x509.SHA384WithRSAPSS, ""},
+		{x509.SHA512WithRSA, x509.SHA512WithRSAPSS, ""},
+		{x509.SHA256WithRSAPSS, x509.SHA256WithRSAPSS, ""},
+		{x509.SHA1WithRSA, x509.UnknownSignatureAlgorithm, "RSA-PSS is not supported for SHA1-RSA"},
+		{x509.ECDSAWithSHA256, x509.UnknownSignatureAlgorithm, "RSA-PSS is not supported for ECDSA-SHA256"},
+		{x509.PureEd25519, x509.UnknownSignatureAlgorithm, "RSA-PSS is not supported for Ed25519"},
+	}
+	for _, tc := range tt {
+		algo, err := csr.PSSSigAlgo(tc.algo)
+		assert.Equal(t, tc.expalg, algo)
+		if tc.experr != "" {
+			require.Error(t, err)
+			assert.Equal(t, tc.experr, err.Error())
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
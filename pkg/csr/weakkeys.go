@@ -0,0 +1,144 @@
+package csr
+
+import (
+	"bufio"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// MinRSAExponent specifies the minimum allowed RSA public exponent
+const MinRSAExponent = 65537
+
+// rocaPrimes are small primes used to detect RSA moduli generated by
+// Infineon RSALib (CVE-2017-15361, ROCA).
+// The primes generated by the vulnerable library have form k*M + (65537^a mod M),
+// therefore the moduli is in the subgroup generated by 65537 modulo each of these primes.
+var rocaPrimes = []int64{
+	3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71, 73,
+	79, 83, 89, 97, 101, 103, 107, 109, 113, 127, 131, 137, 139, 149, 151, 157, 163, 167,
+}
+
+// rocaMarkers contains the subgroups generated by 65537 modulo rocaPrimes
+var rocaMarkers = func() []map[int64]bool {
+	markers := make([]map[int64]bool, len(rocaPrimes))
+	for i, p := range rocaPrimes {
+		m := map[int64]bool{}
+		g := int64(MinRSAExponent) % p
+		for v := int64(1); !m[v]; v = v * g % p {
+			m[v] = true
+		}
+		markers[i] = m
+	}
+	return markers
+}()
+
+// IsROCAModulus returns true if RSA modulus has the ROCA fingerprint
+func IsROCAModulus(n *big.Int) bool {
+	rem := new(big.Int)
+	for i, p := range rocaPrimes {
+		rem.Mod(n, big.NewInt(p))
+		if !rocaMarkers[i][rem.Int64()] {
+			return false
+		}
+	}
+	return true
+}
+
+// WeakKeys provides a blocklist of known weak RSA keys,
+// such as keys generated by Debian OpenSSL (CVE-2008-0166)
+type WeakKeys struct {
+	// suffixes of SHA1 hashes in openssl-blacklist format
+	suffixes map[string]bool
+}
+
+// LoadWeakKeys loads the blocklist from files in openssl-blacklist format,
+// where each line contains the last 20 hex characters of SHA1 hash
+// of the "Modulus=<HEX>\n" string, as printed by "openssl rsa -modulus".
+func LoadWeakKeys(files ...string) (*WeakKeys, error) {
+	w := &WeakKeys{
+		suffixes: map[string]bool{},
+	}
+	for _, file := range files {
+		if err := w.load(file); err != nil {
+			return nil, errors.Annotatef(err, "unable to load weak keys from %q", file)
+		}
+	}
+	return w, nil
+}
+
+func (w *WeakKeys) load(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		s := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		if len(s) == 40 {
+			s = s[20:]
+		}
+		if _, err := hex.DecodeString(s); err != nil || len(s) != 20 {
+			return errors.Errorf("invalid entry at line %d", line)
+		}
+		w.suffixes[s] = true
+	}
+	return errors.Trace(scanner.Err())
+}
+
+// Len returns the number of keys in the blocklist
+func (w *WeakKeys) Len() int {
+	if w == nil {
+		return 0
+	}
+	return len(w.suffixes)
+}
+
+// IsBlocked returns true if RSA modulus is in the blocklist
+func (w *WeakKeys) IsBlocked(n *big.Int) bool {
+	if w.Len() == 0 {
+		return false
+	}
+	h := sha1.Sum([]byte("Modulus=" + strings.ToUpper(n.Text(16)) + "\n"))
+	return w.suffixes[hex.EncodeToString(h[:])[20:]]
+}
+
+// Check returns an error if the public key is known to be weak:
+// RSA key with small or even exponent, ROCA-vulnerable modulus,
+// or the key is in the blocklist.
+// Check can be called on nil WeakKeys, in which case the blocklist is not used.
+func (w *WeakKeys) Check(pub crypto.PublicKey) error {
+	key, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil
+	}
+	if key.E < MinRSAExponent {
+		return errors.Errorf("RSA key has small public exponent: %d", key.E)
+	}
+	if key.E%2 == 0 {
+		return errors.Errorf("RSA key has even public exponent: %d", key.E)
+	}
+	if key.N.Bit(0) == 0 {
+		return errors.New("RSA key has even modulus")
+	}
+	if IsROCAModulus(key.N) {
+		return errors.New("RSA key is vulnerable to ROCA (CVE-2017-15361)")
+	}
+	if w.IsBlocked(key.N) {
+		return errors.New("RSA key is in the weak keys blocklist")
+	}
+	return nil
+}
@@ -328,6 +328,9 @@ func grpcServer(s *Server, tls *tls.Config, gopts ...grpc.ServerOption) *grpc.Se
 		grpc_prometheus.UnaryServerInterceptor,
 		s.authz.NewUnaryInterceptor(),
 	}
+	if s.policy != nil {
+		chainUnaryInterceptors = append(chainUnaryInterceptors, s.policy.NewUnaryInterceptor(s))
+	}
 
 	chainStreamInterceptors := []grpc.StreamServerInterceptor{
 		newStreamInterceptor(s),
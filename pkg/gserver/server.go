@@ -9,12 +9,13 @@ import (
 
 	"github.com/ekspand/trusty/internal/appcontainer"
 	"github.com/ekspand/trusty/internal/config"
+	"github.com/ekspand/trusty/internal/db"
+	"github.com/ekspand/trusty/pkg/authz"
 	"github.com/ekspand/trusty/pkg/jwt"
 	"github.com/ekspand/trusty/pkg/roles"
 	"github.com/go-phorce/dolly/audit"
 	"github.com/go-phorce/dolly/netutil"
 	"github.com/go-phorce/dolly/rest"
-	"github.com/go-phorce/dolly/xhttp/authz"
 	"github.com/go-phorce/dolly/xlog"
 	"github.com/go-phorce/dolly/xpki/cryptoprov"
 	"github.com/juju/errors"
@@ -42,6 +43,14 @@ type StartSubcriber interface {
 	OnStarted() error
 }
 
+// ReloadSubscriber provides interface for the services
+// that reload their configuration on the server's request
+type ReloadSubscriber interface {
+	// OnReload is called when the server received
+	// a signal to reload configuration
+	OnReload() error
+}
+
 // RouteRegistrator provides interface to register HTTP route
 type RouteRegistrator interface {
 	RegisterRoute(rest.Router)
@@ -73,6 +82,7 @@ type Server struct {
 	services map[string]Service
 
 	authz    *authz.Provider
+	policy   *authz.Policy
 	auditor  audit.Auditor
 	identity roles.IdentityProvider
 	crypto   *cryptoprov.Crypto
@@ -107,6 +117,30 @@ func Start(
 		return nil, errors.Trace(err)
 	}
 
+	var tokens roles.APITokenVerifier
+	var sessions roles.SessionVerifier
+	var resolver roles.RoleResolver
+	if cfg.IdentityMap.APIToken.Enabled ||
+		cfg.IdentityMap.JWT.Enabled ||
+		cfg.IdentityMap.DynamicRoles.Enabled {
+		// API tokens of service accounts, sessions of issued JWT,
+		// and dynamic roles are resolved with DB
+		err = container.Invoke(func(orgs db.OrgsDb) {
+			if cfg.IdentityMap.APIToken.Enabled {
+				tokens = db.NewAPITokenVerifier(orgs)
+			}
+			if cfg.IdentityMap.JWT.Enabled && cfg.IdentityMap.JWT.Sessions {
+				sessions = db.NewSessionVerifier(orgs)
+			}
+			if cfg.IdentityMap.DynamicRoles.Enabled {
+				resolver = db.NewRoleResolver(orgs)
+			}
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	err = container.Invoke(func(
 		d appcontainer.Discovery,
 		jwtParser jwt.Parser,
@@ -115,7 +149,7 @@ func Start(
 		e.auditor = auditor
 		e.crypto = crypto
 		e.disco = d
-		iden, err := roles.New(&cfg.IdentityMap, jwtParser)
+		iden, err := roles.New(&cfg.IdentityMap, jwtParser, tokens, sessions, resolver)
 		if err != nil {
 			logger.Errorf("err=[%v]", errors.Details(err))
 			return err
@@ -132,13 +166,20 @@ func Start(
 	if len(cfg.Authz.Allow) > 0 ||
 		len(cfg.Authz.AllowAny) > 0 ||
 		len(cfg.Authz.AllowAnyRole) > 0 {
-		e.authz, err = authz.New(&authz.Config{
-			Allow:         cfg.Authz.Allow,
-			AllowAny:      cfg.Authz.AllowAny,
-			AllowAnyRole:  cfg.Authz.AllowAnyRole,
-			LogAllowedAny: cfg.Authz.LogAllowedAny,
-			LogAllowed:    cfg.Authz.LogAllowed,
-			LogDenied:     cfg.Authz.LogDenied,
+		e.authz, err = authz.New(&cfg.Authz)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if cfg.Authz.Policy != "" {
+		e.policy, err = authz.LoadPolicy(cfg.Authz.Policy)
+		if err != nil {
+			return nil, errors.Annotatef(err, "failed to load policy: %s", cfg.Authz.Policy)
+		}
+		// the conditions of the policy are evaluated with the owners
+		// of the certificates and the orgs of the callers
+		err = container.Invoke(func(certs db.CertsDb, orgs db.OrgsDb) {
+			e.policy.WithOwnerResolver(db.NewOwnerResolver(certs, orgs))
 		})
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -372,6 +413,11 @@ func (e *Server) Discovery() appcontainer.Discovery {
 	return e.disco
 }
 
+// Policy returns the authorization policy, or nil if not configured
+func (e *Server) Policy() *authz.Policy {
+	return e.policy
+}
+
 // Audit create an audit event
 func (e *Server) Audit(
 	source string,
@@ -3,6 +3,7 @@ package inmemcrypto
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
@@ -129,7 +130,7 @@ type Provider struct {
 	inMemProv *inMemProv
 }
 
-// NewProvider creates new provider for exportable RSA and ECDSA keys.
+// NewProvider creates new provider for exportable RSA, ECDSA and Ed25519 keys.
 // This provider should be used only when HSM use is not applicable.
 func NewProvider() *Provider {
 	inMemProv := inMemProv{
@@ -215,6 +216,28 @@ func (p *Provider) GenerateECDSAKey(label string, curve elliptic.Curve) (crypto.
 	return si, nil
 }
 
+// GenerateEd25519Key creates signer using randomly generated Ed25519 key
+func (p *Provider) GenerateEd25519Key(label string) (crypto.PrivateKey, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if len(label) == 0 {
+		label = fmt.Sprintf("%x", guid.MustCreate())
+	}
+
+	id := p.idGenerator.Generate()
+
+	si := &provImpl{
+		id:    id,
+		label: label,
+		pvk:   key,
+	}
+	p.inMemProv.registerKey(id, si)
+	return si, nil
+}
+
 // IdentifyKey returns key id and label for the given private key
 func (p *Provider) IdentifyKey(priv crypto.PrivateKey) (keyID, label string, err error) {
 	if ki, ok := priv.(*provImpl); ok {
@@ -252,6 +275,16 @@ func (p *Provider) ExportKey(keyID string) (string, []byte, error) {
 			Bytes: key,
 		}
 		key = pem.EncodeToMemory(&block)
+	case ed25519.PrivateKey:
+		key, err = x509.MarshalPKCS8PrivateKey(priv)
+		if err != nil {
+			return "", nil, errors.Annotatef(err, "export Ed25519 key: %s", keyID)
+		}
+		block := pem.Block{
+			Type:  "PRIVATE KEY",
+			Bytes: key,
+		}
+		key = pem.EncodeToMemory(&block)
 	default:
 		logger.Errorf("unsupported key: %T", priv)
 		panic("Generate should have failed to produce a valid key.")
@@ -0,0 +1,169 @@
+// Package jwk provides RFC 7517 JSON Web Key for public keys
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"math/big"
+
+	"github.com/juju/errors"
+)
+
+// Key provides JSON Web Key
+type Key struct {
+	// Kty specifies the key type: EC|RSA|OKP
+	Kty string `json:"kty"`
+	// Use specifies the intended use of the key
+	Use string `json:"use,omitempty"`
+	// Kid specifies ID of the key
+	Kid string `json:"kid,omitempty"`
+	// Alg specifies the algorithm intended for use with the key
+	Alg string `json:"alg,omitempty"`
+	// Crv specifies the curve of EC or OKP key
+	Crv string `json:"crv,omitempty"`
+	// X specifies x coordinate of EC key, or OKP public key
+	X string `json:"x,omitempty"`
+	// Y specifies y coordinate of EC key
+	Y string `json:"y,omitempty"`
+	// N specifies modulus of RSA key
+	N string `json:"n,omitempty"`
+	// E specifies exponent of RSA key
+	E string `json:"e,omitempty"`
+	// X5c specifies base64 encoded DER certificates chain
+	X5c []string `json:"x5c,omitempty"`
+}
+
+// Set provides JSON Web Key Set
+type Set struct {
+	Keys []*Key `json:"keys"`
+}
+
+// NewKey returns JWK for the public key
+func NewKey(pub crypto.PublicKey, use, kid string) (*Key, error) {
+	k := &Key{
+		Use: use,
+		Kid: kid,
+	}
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		k.Kty = "EC"
+		k.Crv = key.Curve.Params().Name
+		k.X = encode(key.X.FillBytes(make([]byte, size)))
+		k.Y = encode(key.Y.FillBytes(make([]byte, size)))
+	case *rsa.PublicKey:
+		k.Kty = "RSA"
+		k.N = encode(key.N.Bytes())
+		k.E = encode(big.NewInt(int64(key.E)).Bytes())
+	case ed25519.PublicKey:
+		k.Kty = "OKP"
+		k.Crv = "Ed25519"
+		k.X = encode(key)
+	default:
+		return nil, errors.Errorf("unsupported public key: %T", pub)
+	}
+	return k, nil
+}
+
+// NewKeyFromCertificate returns JWK for the certificate,
+// the certificate is included in x5c
+func NewKeyFromCertificate(cert *x509.Certificate, use, kid string) (*Key, error) {
+	k, err := NewKey(cert.PublicKey, use, kid)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	k.X5c = []string{base64.StdEncoding.EncodeToString(cert.Raw)}
+	return k, nil
+}
+
+// PublicKey returns the public key
+func (k *Key) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		pub := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("invalid EC key")
+		}
+		return pub, nil
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		exp := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exp.IsInt64() || exp.Int64() < 2 || exp.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA key")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(exp.Int64()),
+		}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, errors.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, errors.Errorf("unsupported key type: %s", k.Kty)
+}
+
+// Find returns the key by ID, or nil if not found
+func (s *Set) Find(kid string) *Key {
+	for _, k := range s.Keys {
+		if k.Kid == kid {
+			return k
+		}
+	}
+	return nil
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid base64url value")
+	}
+	return b, nil
+}
@@ -0,0 +1,85 @@
+package jwk_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for kty, pub := range map[string]crypto.PublicKey{
+		"EC":  ecKey.Public(),
+		"RSA": rsaKey.Public(),
+		"OKP": edPub,
+	} {
+		t.Run(kty, func(t *testing.T) {
+			k, err := jwk.NewKey(pub, "sig", "kid1")
+			require.NoError(t, err)
+			assert.Equal(t, kty, k.Kty)
+
+			js, err := json.Marshal(&jwk.Set{Keys: []*jwk.Key{k}})
+			require.NoError(t, err)
+
+			var set jwk.Set
+			require.NoError(t, json.Unmarshal(js, &set))
+			assert.Nil(t, set.Find("kid2"))
+			found := set.Find("kid1")
+			require.NotNil(t, found)
+			assert.Equal(t, "sig", found.Use)
+
+			parsed, err := found.PublicKey()
+			require.NoError(t, err)
+			assert.Equal(t, pub, parsed)
+		})
+	}
+
+	_, err = jwk.NewKey("invalid", "sig", "")
+	assert.EqualError(t, err, "unsupported public key: string")
+
+	_, err = (&jwk.Key{Kty: "EC", Crv: "P-256", X: "AA", Y: "AA"}).PublicKey()
+	assert.EqualError(t, err, "invalid EC key")
+	_, err = (&jwk.Key{Kty: "oct"}).PublicKey()
+	assert.EqualError(t, err, "unsupported key type: oct")
+}
+
+func TestNewKeyFromCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "[TEST] Trusty JWK"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	k, err := jwk.NewKeyFromCertificate(cert, "x509-svid", "")
+	require.NoError(t, err)
+	assert.Equal(t, "EC", k.Kty)
+	assert.Equal(t, "P-256", k.Crv)
+	assert.Equal(t, []string{base64.StdEncoding.EncodeToString(der)}, k.X5c)
+}
@@ -1,16 +1,22 @@
 package jwt
 
 import (
+	"crypto"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"io/ioutil"
-	"strconv"
+	"sort"
 	"strings"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/ekspand/trusty/pkg/jwk"
 	"github.com/go-phorce/dolly/fileutil"
 	"github.com/go-phorce/dolly/xlog"
 	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/go-phorce/dolly/xpki/cryptoprov"
 	"github.com/juju/errors"
 	"gopkg.in/yaml.v2"
 )
@@ -29,17 +35,32 @@ type Parser interface {
 	ParseToken(authorization, audience string) (*jwt.StandardClaims, error)
 }
 
+// KeySet specifies interface to publish the public keys
+type KeySet interface {
+	// JWKS returns the public keys to verify tokens,
+	// the keys for HMAC signature are not included
+	JWKS() *jwk.Set
+}
+
 // Provider specifies JWT provider interface
 type Provider interface {
 	Signer
 	Parser
+	KeySet
 }
 
 // Key for JWT signature
 type Key struct {
 	// ID of the key
-	ID   string `json:"id" yaml:"id"`
+	ID string `json:"id" yaml:"id"`
+	// Seed specifies the secret for HS256 signature
 	Seed string `json:"seed" yaml:"seed"`
+	// Key specifies location of the private key file for RS256|ES256|EdDSA signature,
+	// the file contains PEM encoded key, or PKCS#11 URI of the key in crypto provider
+	Key string `json:"key" yaml:"key"`
+	// PublicKey specifies location of PEM encoded public key file,
+	// the key can only be used to verify tokens signed before the rotation
+	PublicKey string `json:"public_key" yaml:"public_key"`
 }
 
 // Config provides OAuth2 configuration
@@ -52,11 +73,44 @@ type Config struct {
 	Keys []*Key `json:"keys" yaml:"keys"`
 }
 
+// Option configures the provider
+type Option interface {
+	apply(*options)
+}
+
+type options struct {
+	crypto *cryptoprov.Crypto
+}
+
+type funcOption struct {
+	f func(*options)
+}
+
+func (fo *funcOption) apply(o *options) {
+	fo.f(o)
+}
+
+func newFuncOption(f func(*options)) *funcOption {
+	return &funcOption{
+		f: f,
+	}
+}
+
+// WithCrypto option to provide crypto provider,
+// to load the private keys specified by PKCS#11 URI
+func WithCrypto(crypto *cryptoprov.Crypto) Option {
+	return newFuncOption(func(o *options) {
+		o.crypto = crypto
+	})
+}
+
 // provider for JWT
 type provider struct {
-	issuer string
-	kid    string
-	keys   map[string][]byte
+	issuer  string
+	kid     string
+	keys    map[string][]byte
+	signers map[string]crypto.Signer
+	pubKeys map[string]crypto.PublicKey
 }
 
 // LoadConfig returns configuration loaded from a file
@@ -94,20 +148,27 @@ func LoadConfig(file string) (*Config, error) {
 }
 
 // Load returns new provider
-func Load(cfgfile string) (Provider, error) {
+func Load(cfgfile string, ops ...Option) (Provider, error) {
 	cfg, err := LoadConfig(cfgfile)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return New(cfg), nil
+	return New(cfg, ops...), nil
 }
 
 // New returns new provider
-func New(cfg *Config) Provider {
+func New(cfg *Config, ops ...Option) Provider {
+	dops := options{}
+	for _, op := range ops {
+		op.apply(&dops)
+	}
+
 	p := &provider{
-		issuer: cfg.Issuer,
-		kid:    cfg.KeyID,
-		keys:   map[string][]byte{},
+		issuer:  cfg.Issuer,
+		kid:     cfg.KeyID,
+		keys:    map[string][]byte{},
+		signers: map[string]crypto.Signer{},
+		pubKeys: map[string]crypto.PublicKey{},
 	}
 
 	if p.issuer == "" {
@@ -119,17 +180,43 @@ func New(cfg *Config) Provider {
 	}
 
 	for _, key := range cfg.Keys {
-		seed, err := fileutil.LoadConfigWithSchema(key.Seed)
-		if err != nil {
-			logger.Panic("failed to load seed: " + err.Error())
+		switch {
+		case key.Key != "":
+			signer, err := loadSigner(dops.crypto, key.Key)
+			if err != nil {
+				logger.Panic("failed to load key: " + err.Error())
+			}
+			if _, _, err = Algorithm(signer.Public()); err != nil {
+				logger.Panic("failed to load key: " + err.Error())
+			}
+			p.signers[key.ID] = signer
+			p.pubKeys[key.ID] = signer.Public()
+		case key.PublicKey != "":
+			pub, err := loadPublicKey(key.PublicKey)
+			if err != nil {
+				logger.Panic("failed to load public key: " + err.Error())
+			}
+			if _, _, err = Algorithm(pub); err != nil {
+				logger.Panic("failed to load public key: " + err.Error())
+			}
+			p.pubKeys[key.ID] = pub
+		default:
+			seed, err := fileutil.LoadConfigWithSchema(key.Seed)
+			if err != nil {
+				logger.Panic("failed to load seed: " + err.Error())
+			}
+			p.keys[key.ID] = certutil.SHA256([]byte(seed))
 		}
-		p.keys[key.ID] = certutil.SHA256([]byte(seed))
 	}
 
 	if p.kid == "" {
 		p.kid = cfg.Keys[len(cfg.Keys)-1].ID
 	}
 
+	if _, ok := p.pubKeys[p.kid]; ok && p.signers[p.kid] == nil {
+		logger.Panic("private key not provided for kid: " + p.kid)
+	}
+
 	return p
 }
 
@@ -141,9 +228,26 @@ func (p *provider) currentKey() (string, []byte) {
 	return "", nil
 }
 
+// JWKS returns the public keys to verify tokens
+func (p *provider) JWKS() *jwk.Set {
+	set := &jwk.Set{
+		Keys: []*jwk.Key{},
+	}
+	for kid, pub := range p.pubKeys {
+		k, err := jwk.NewKey(pub, "sig", kid)
+		if err != nil {
+			// the keys are validated on load
+			continue
+		}
+		k.Alg, _, _ = Algorithm(pub)
+		set.Keys = append(set.Keys, k)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
 // SignToken returns signed JWT token with custom claims
 func (p *provider) SignToken(id, subject, audience string, expiry time.Duration) (string, *jwt.StandardClaims, error) {
-	kid, key := p.currentKey()
 	now := time.Now().UTC()
 	expiresAt := now.Add(expiry)
 	claims := &jwt.StandardClaims{
@@ -155,6 +259,15 @@ func (p *provider) SignToken(id, subject, audience string, expiry time.Duration)
 		Subject:   subject,
 	}
 
+	if signer, ok := p.signers[p.kid]; ok {
+		tokenString, err := Sign(signer, p.kid, claims)
+		if err != nil {
+			return "", nil, errors.Trace(err)
+		}
+		return tokenString, claims, nil
+	}
+
+	kid, key := p.currentKey()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	token.Header["kid"] = kid
 
@@ -174,41 +287,71 @@ func (p *provider) ParseToken(authorization, audience string) (*jwt.StandardClai
 		Audience: audience,
 	}
 
-	token, err := jwt.ParseWithClaims(authorization, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.Errorf("unexpected signing method: %v", token.Header["alg"])
+	_, err := parse(authorization, claims, func(header *Header, method jwt.SigningMethod) (interface{}, error) {
+		if header.Kid == "" {
+			return nil, errors.Errorf("missing kid")
 		}
-
-		if kid, ok := token.Header["kid"]; ok {
-			var id string
-			switch t := kid.(type) {
-			case string:
-				id = t
-			case int:
-				id = strconv.Itoa(t)
-			}
-
-			if key, ok := p.keys[id]; ok {
+		switch method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if key, ok := p.keys[header.Kid]; ok {
 				return key, nil
 			}
-			return nil, errors.Errorf("unexpected kid")
+		case *SigningMethod:
+			// the key type is verified by the signing method
+			if pub, ok := p.pubKeys[header.Kid]; ok {
+				return pub, nil
+			}
+		default:
+			return nil, errors.Errorf("unexpected signing method: %v", header.Alg)
 		}
-		return nil, errors.Errorf("missing kid")
+		return nil, errors.Errorf("unexpected kid")
 	})
 	if err != nil {
 		return nil, errors.Annotatef(err, "failed to verify token")
 	}
+	if err = claims.Valid(); err != nil {
+		return nil, errors.Annotatef(err, "failed to verify token")
+	}
 
-	if claims, ok := token.Claims.(*jwt.StandardClaims); ok && token.Valid {
-		if claims.Issuer != p.issuer {
-			return nil, errors.Errorf("invalid issuer: %s", claims.Issuer)
-		}
-		if audience != "" && claims.Audience != audience {
-			return nil, errors.Errorf("invalid audience: %s", claims.Audience)
-		}
+	if claims.Issuer != p.issuer {
+		return nil, errors.Errorf("invalid issuer: %s", claims.Issuer)
+	}
+	if audience != "" && claims.Audience != audience {
+		return nil, errors.Errorf("invalid audience: %s", claims.Audience)
+	}
+
+	return claims, nil
+}
 
-		return claims, nil
+// loadSigner returns the signer for the key file,
+// that contains PEM encoded key, or PKCS#11 URI
+func loadSigner(prov *cryptoprov.Crypto, file string) (crypto.Signer, error) {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// remove trailing space and end-of-line
+	raw = []byte(strings.TrimSpace(string(raw)))
+
+	signer, err := csr.NewSigner(prov, raw)
+	if err != nil {
+		return nil, errors.Trace(err)
 	}
+	return signer, nil
+}
 
-	return nil, errors.Errorf("invalid token")
+func loadPublicKey(file string) (crypto.PublicKey, error) {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.Errorf("invalid PEM: %s", file)
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return pub, nil
 }
@@ -95,3 +95,82 @@ func Test_Sign(t *testing.T) {
 	require.Error(t, err)
 	assert.Equal(t, "invalid issuer: trusty.com", err.Error())
 }
+
+func Test_SignWithKeys(t *testing.T) {
+	p, err := jwt.Load("testdata/jwtprov_keys.yaml")
+	require.NoError(t, err)
+
+	jwks := p.JWKS()
+	require.Len(t, jwks.Keys, 3)
+	assert.Equal(t, "ec", jwks.Keys[0].Kid)
+	assert.Equal(t, "ES256", jwks.Keys[0].Alg)
+	assert.Equal(t, "sig", jwks.Keys[0].Use)
+	assert.Equal(t, "ed25519", jwks.Keys[1].Kid)
+	assert.Equal(t, "EdDSA", jwks.Keys[1].Alg)
+	assert.Equal(t, "rsa", jwks.Keys[2].Kid)
+	assert.Equal(t, "RS256", jwks.Keys[2].Alg)
+
+	token, std, err := p.SignToken("1000", "denis@ekspand.com", "trusty.com", time.Minute)
+	require.NoError(t, err)
+
+	claims, err := p.ParseToken(token, "trusty.com")
+	require.NoError(t, err)
+	assert.Equal(t, *std, *claims)
+
+	_, err = p.ParseToken(token, "other.com")
+	assert.EqualError(t, err, "invalid audience: trusty.com")
+
+	// the previous key remains valid for verification after rotation
+	rotated, err := jwt.Load("testdata/jwtprov_rotated.yaml")
+	require.NoError(t, err)
+	claims, err = rotated.ParseToken(token, "trusty.com")
+	require.NoError(t, err)
+	assert.Equal(t, *std, *claims)
+
+	token2, _, err := rotated.SignToken("1001", "denis@ekspand.com", "trusty.com", time.Minute)
+	require.NoError(t, err)
+	_, err = p.ParseToken(token2, "trusty.com")
+	require.NoError(t, err)
+
+	hmac, err := jwt.Load("testdata/jwtprov.json")
+	require.NoError(t, err)
+	assert.Empty(t, hmac.JWKS().Keys)
+	_, err = hmac.ParseToken(token, "trusty.com")
+	assert.EqualError(t, err, "failed to verify token: unexpected kid")
+
+	expired, _, err := rotated.SignToken("1002", "denis@ekspand.com", "trusty.com", -time.Minute)
+	require.NoError(t, err)
+	_, err = rotated.ParseToken(expired, "trusty.com")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "token is expired")
+
+	for _, kid := range []string{"rsa", "ed25519"} {
+		cfg, err := jwt.LoadConfig("testdata/jwtprov_keys.yaml")
+		require.NoError(t, err)
+		cfg.KeyID = kid
+
+		token, std, err := jwt.New(cfg).SignToken("1000", "denis@ekspand.com", "trusty.com", time.Minute)
+		require.NoError(t, err)
+		claims, err := p.ParseToken(token, "trusty.com")
+		require.NoError(t, err, kid)
+		assert.Equal(t, *std, *claims)
+	}
+
+	assert.Panics(t, func() {
+		jwt.New(&jwt.Config{
+			Issuer: "issuer",
+			KeyID:  "ec",
+			Keys: []*jwt.Key{
+				{ID: "ec", PublicKey: "testdata/ec.pem"},
+			},
+		})
+	})
+	assert.Panics(t, func() {
+		jwt.New(&jwt.Config{
+			Issuer: "issuer",
+			Keys: []*jwt.Key{
+				{ID: "ec", Key: "testdata/missing.pem"},
+			},
+		})
+	})
+}
@@ -0,0 +1,319 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/juju/errors"
+)
+
+// Header provides JOSE header of the token
+type Header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// KeyFunc returns the public key to verify the token
+type KeyFunc func(header *Header) (crypto.PublicKey, error)
+
+// Audience provides aud claim, that can be a string or an array
+type Audience []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (a *Audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = Audience{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return errors.New("invalid audience")
+	}
+	*a = list
+	return nil
+}
+
+// Contains returns true if the audience contains the value
+func (a Audience) Contains(aud string) bool {
+	for _, v := range a {
+		if v == aud {
+			return true
+		}
+	}
+	return false
+}
+
+// Algorithm returns JWS algorithm and the digest for the public key:
+// ES256|ES384|ES512 for ECDSA, RS256 for RSA, and EdDSA for Ed25519
+func Algorithm(pub crypto.PublicKey) (string, crypto.Hash, error) {
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		switch key.Curve.Params().BitSize {
+		case 256:
+			return "ES256", crypto.SHA256, nil
+		case 384:
+			return "ES384", crypto.SHA384, nil
+		case 521:
+			return "ES512", crypto.SHA512, nil
+		}
+		return "", 0, errors.Errorf("unsupported curve: %s", key.Curve.Params().Name)
+	case *rsa.PublicKey:
+		return "RS256", crypto.SHA256, nil
+	case ed25519.PublicKey:
+		return "EdDSA", crypto.Hash(0), nil
+	}
+	return "", 0, errors.Errorf("unsupported public key: %T", pub)
+}
+
+// SigningMethod implements jwt.SigningMethod with crypto.Signer,
+// that may be backed by HSM or KMS.
+// Sign accepts crypto.Signer, and Verify accepts crypto.PublicKey.
+type SigningMethod struct {
+	alg  string
+	hash crypto.Hash
+}
+
+// Signing methods for the asymmetric keys,
+// they are not registered in jwt-go, to keep the default ES and RS methods
+// for other packages, and are used explicitly by Sign, Verify and Provider
+var (
+	SigningMethodES256 = &SigningMethod{alg: "ES256", hash: crypto.SHA256}
+	SigningMethodES384 = &SigningMethod{alg: "ES384", hash: crypto.SHA384}
+	SigningMethodES512 = &SigningMethod{alg: "ES512", hash: crypto.SHA512}
+	SigningMethodRS256 = &SigningMethod{alg: "RS256", hash: crypto.SHA256}
+	SigningMethodEdDSA = &SigningMethod{alg: "EdDSA", hash: crypto.Hash(0)}
+)
+
+var signingMethods = map[string]*SigningMethod{
+	SigningMethodES256.alg: SigningMethodES256,
+	SigningMethodES384.alg: SigningMethodES384,
+	SigningMethodES512.alg: SigningMethodES512,
+	SigningMethodRS256.alg: SigningMethodRS256,
+	SigningMethodEdDSA.alg: SigningMethodEdDSA,
+}
+
+// GetSigningMethod returns the signing method for the algorithm,
+// or nil if the algorithm is not supported
+func GetSigningMethod(alg string) *SigningMethod {
+	return signingMethods[alg]
+}
+
+// Alg implements jwt.SigningMethod
+func (m *SigningMethod) Alg() string {
+	return m.alg
+}
+
+// Sign implements jwt.SigningMethod,
+// the key must be crypto.Signer
+func (m *SigningMethod) Sign(signingString string, key interface{}) (string, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	if alg, _, err := Algorithm(signer.Public()); err != nil || alg != m.alg {
+		return "", jwt.ErrInvalidKeyType
+	}
+
+	var sig []byte
+	var err error
+	if m.hash == crypto.Hash(0) {
+		sig, err = signer.Sign(rand.Reader, []byte(signingString), m.hash)
+	} else {
+		d := m.hash.New()
+		d.Write([]byte(signingString))
+		sig, err = signer.Sign(rand.Reader, d.Sum(nil), m.hash)
+	}
+	if err != nil {
+		return "", errors.Annotate(err, "failed to sign token")
+	}
+
+	if key, ok := signer.Public().(*ecdsa.PublicKey); ok {
+		// JWS uses R || S format for ECDSA signature
+		sig, err = ecdsaToJWS(sig, (key.Curve.Params().BitSize+7)/8)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	return jwt.EncodeSegment(sig), nil
+}
+
+// Verify implements jwt.SigningMethod,
+// the key must be crypto.PublicKey
+func (m *SigningMethod) Verify(signingString, signature string, key interface{}) error {
+	if alg, _, err := Algorithm(key); err != nil || alg != m.alg {
+		return jwt.ErrInvalidKeyType
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return errors.New("invalid signature")
+	}
+
+	var digest []byte
+	if m.hash != crypto.Hash(0) {
+		d := m.hash.New()
+		d.Write([]byte(signingString))
+		digest = d.Sum(nil)
+	}
+
+	valid := false
+	switch pub := key.(type) {
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig) == 2*size {
+			r := new(big.Int).SetBytes(sig[:size])
+			s := new(big.Int).SetBytes(sig[size:])
+			valid = ecdsa.Verify(pub, digest, r, s)
+		}
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(pub, m.hash, digest, sig) == nil
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(pub, []byte(signingString), sig)
+	}
+	if !valid {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// Sign returns the token with claims, signed by the signer.
+// The signer may be backed by HSM or KMS.
+func Sign(signer crypto.Signer, kid string, claims interface{}) (string, error) {
+	alg, _, err := Algorithm(signer.Public())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	token := jwt.NewWithClaims(GetSigningMethod(alg), &anyClaims{v: claims})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	tokenString, err := token.SignedString(signer)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return tokenString, nil
+}
+
+// Verify verifies the token signature with the key returned by keyFunc,
+// and unmarshals the payload into claims.
+// The validation of claims is the responsibility of the caller.
+func Verify(token string, keyFunc KeyFunc, claims interface{}) (*Header, error) {
+	return parse(token, claims, func(header *Header, method jwt.SigningMethod) (interface{}, error) {
+		pub, err := keyFunc(header)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, ok := method.(*SigningMethod); !ok {
+			return nil, errors.Errorf("unexpected signing method: %s", header.Alg)
+		}
+		alg, _, err := Algorithm(pub)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if header.Alg != alg {
+			return nil, errors.Errorf("unexpected signing method: %s", header.Alg)
+		}
+		return pub, nil
+	})
+}
+
+// parseKeyFunc returns the key to verify the token with the signing method
+type parseKeyFunc func(header *Header, method jwt.SigningMethod) (interface{}, error)
+
+// parse verifies the token signature with the key returned by keyFunc,
+// and unmarshals the payload into claims.
+// Unlike jwt.Parser, the signing method is not looked up
+// in the global registry of jwt-go.
+func parse(token string, claims interface{}, keyFunc parseKeyFunc) (*Header, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("invalid token format")
+	}
+
+	raw, err := jwt.DecodeSegment(parts[0])
+	if err != nil {
+		return nil, errors.New("invalid token format")
+	}
+	var fields map[string]interface{}
+	if err = json.Unmarshal(raw, &fields); err != nil {
+		return nil, errors.New("invalid token format")
+	}
+	header := new(Header)
+	header.Alg, _ = fields["alg"].(string)
+	header.Kid, _ = fields["kid"].(string)
+	header.Typ, _ = fields["typ"].(string)
+
+	raw, err = jwt.DecodeSegment(parts[1])
+	if err != nil {
+		return nil, errors.New("invalid token format")
+	}
+	if err = json.Unmarshal(raw, claims); err != nil {
+		return nil, errors.New("invalid token format")
+	}
+
+	method := signingMethod(header.Alg)
+	if method == nil {
+		return nil, errors.Errorf("unexpected signing method: %s", header.Alg)
+	}
+	key, err := keyFunc(header, method)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = method.Verify(parts[0]+"."+parts[1], parts[2], key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return header, nil
+}
+
+// signingMethod returns the signing method for the algorithm,
+// the asymmetric algorithms are served by SigningMethod,
+// and HS256 by jwt-go
+func signingMethod(alg string) jwt.SigningMethod {
+	if m := GetSigningMethod(alg); m != nil {
+		return m
+	}
+	if alg == jwt.SigningMethodHS256.Alg() {
+		return jwt.SigningMethodHS256
+	}
+	return nil
+}
+
+// anyClaims wraps the claims of any type into jwt.Claims,
+// the validation of claims is the responsibility of the caller
+type anyClaims struct {
+	v interface{}
+}
+
+// Valid implements jwt.Claims
+func (c *anyClaims) Valid() error {
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (c *anyClaims) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.v)
+}
+
+func ecdsaToJWS(der []byte, size int) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, errors.Annotate(err, "invalid ECDSA signature")
+	}
+	out := make([]byte, 2*size)
+	sig.R.FillBytes(out[:size])
+	sig.S.FillBytes(out[size:])
+	return out, nil
+}
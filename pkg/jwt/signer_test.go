@@ -0,0 +1,129 @@
+package jwt_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	"github.com/ekspand/trusty/pkg/jwt"
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testClaims struct {
+	Subject  string       `json:"sub"`
+	Audience jwt.Audience `json:"aud"`
+}
+
+func Test_SignWithKey(t *testing.T) {
+	ec256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ec384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for alg, signer := range map[string]crypto.Signer{
+		"ES256": ec256,
+		"ES384": ec384,
+		"RS256": rsaKey,
+		"EdDSA": edKey,
+	} {
+		t.Run(alg, func(t *testing.T) {
+			token, err := jwt.Sign(signer, "kid1", &testClaims{
+				Subject:  "spiffe://trusty.ekspand.com/workload",
+				Audience: jwt.Audience{"trusty"},
+			})
+			require.NoError(t, err)
+
+			claims := new(testClaims)
+			header, err := jwt.Verify(token, func(h *jwt.Header) (crypto.PublicKey, error) {
+				if h.Kid != "kid1" {
+					return nil, errors.New("unexpected kid")
+				}
+				return signer.Public(), nil
+			}, claims)
+			require.NoError(t, err)
+			assert.Equal(t, alg, header.Alg)
+			assert.Equal(t, "JWT", header.Typ)
+			assert.Equal(t, "spiffe://trusty.ekspand.com/workload", claims.Subject)
+			assert.True(t, claims.Audience.Contains("trusty"))
+
+			_, err = jwt.Verify(token, func(h *jwt.Header) (crypto.PublicKey, error) {
+				return ec256.Public(), nil
+			}, claims)
+			if alg == "ES256" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+
+			_, err = jwt.Verify(token[:len(token)-4]+"AAAA", func(h *jwt.Header) (crypto.PublicKey, error) {
+				return signer.Public(), nil
+			}, claims)
+			assert.EqualError(t, err, "invalid signature")
+		})
+	}
+
+	_, err = jwt.Verify("invalid", nil, nil)
+	assert.EqualError(t, err, "invalid token format")
+}
+
+func Test_SigningMethod(t *testing.T) {
+	ec384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for alg, signer := range map[string]crypto.Signer{
+		"ES384": ec384,
+		"EdDSA": edKey,
+	} {
+		t.Run(alg, func(t *testing.T) {
+			method := jwt.GetSigningMethod(alg)
+			require.NotNil(t, method)
+			assert.Equal(t, alg, method.Alg())
+
+			token, err := jwtgo.NewWithClaims(method, jwtgo.MapClaims{"sub": "denis"}).SignedString(signer)
+			require.NoError(t, err)
+
+			claims := new(testClaims)
+			_, err = jwt.Verify(token, func(*jwt.Header) (crypto.PublicKey, error) {
+				return signer.Public(), nil
+			}, claims)
+			require.NoError(t, err)
+			assert.Equal(t, "denis", claims.Subject)
+
+			_, err = jwtgo.NewWithClaims(method, jwtgo.MapClaims{"sub": "denis"}).SignedString([]byte("secret"))
+			assert.Equal(t, jwtgo.ErrInvalidKeyType, err)
+		})
+	}
+
+	_, err = jwt.SigningMethodES256.Sign("payload", ec384)
+	assert.Equal(t, jwtgo.ErrInvalidKeyType, err)
+	assert.Nil(t, jwt.GetSigningMethod("HS256"))
+
+	// the signing methods of jwt-go are not replaced
+	assert.IsType(t, &jwtgo.SigningMethodECDSA{}, jwtgo.GetSigningMethod("ES256"))
+	assert.IsType(t, &jwtgo.SigningMethodRSA{}, jwtgo.GetSigningMethod("RS256"))
+	assert.Nil(t, jwtgo.GetSigningMethod("EdDSA"))
+}
+
+func Test_Audience(t *testing.T) {
+	var c testClaims
+	require.NoError(t, json.Unmarshal([]byte(`{"aud":"trusty"}`), &c))
+	assert.Equal(t, jwt.Audience{"trusty"}, c.Audience)
+	require.NoError(t, json.Unmarshal([]byte(`{"aud":["a","b"]}`), &c))
+	assert.Equal(t, jwt.Audience{"a", "b"}, c.Audience)
+	assert.False(t, c.Audience.Contains("trusty"))
+	assert.Error(t, json.Unmarshal([]byte(`{"aud":1}`), &c))
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"io/ioutil"
+	"net/http"
 	"strings"
 
 	"github.com/go-phorce/dolly/fileutil"
@@ -21,6 +22,9 @@ var logger = xlog.NewPackageLogger("github.com/ekspand/trusty/pkg", "oauth2clien
 type Client struct {
 	cfg       *Config
 	verifyKey *rsa.PublicKey
+
+	httpClient *http.Client
+	oidc       oidcState
 }
 
 // LoadConfig returns configuration loaded from a file
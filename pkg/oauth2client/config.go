@@ -22,4 +22,25 @@ type Config struct {
 	Audience string `json:"audience" yaml:"audience"`
 	// Issuer of JWT token
 	Issuer string `json:"issuer" yaml:"issuer"`
+	// DiscoveryURL specifies OpenID Connect discovery document URL,
+	// if provided, then the client is used as generic OIDC provider,
+	// and AuthURL and TokenURL are discovered
+	DiscoveryURL string `json:"discovery_url" yaml:"discovery_url"`
+	// Claims specifies the mapping of ID token claims to user info
+	Claims ClaimsMapping `json:"claims" yaml:"claims"`
+	// TrustEmail specifies to trust the email claim without email_verified,
+	// to be used only for the providers that own the email domains of their users
+	TrustEmail bool `json:"trust_email" yaml:"trust_email"`
+}
+
+// ClaimsMapping specifies the names of ID token claims
+type ClaimsMapping struct {
+	// Login specifies claim for user's login, default is preferred_username
+	Login string `json:"login" yaml:"login"`
+	// Email specifies claim for user's email, default is email
+	Email string `json:"email" yaml:"email"`
+	// Name specifies claim for user's name, default is name
+	Name string `json:"name" yaml:"name"`
+	// Groups specifies claim for user's groups, default is groups
+	Groups string `json:"groups" yaml:"groups"`
 }
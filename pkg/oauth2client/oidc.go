@@ -0,0 +1,348 @@
+package oauth2client
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/jwk"
+	"github.com/ekspand/trusty/pkg/jwt"
+	"github.com/go-phorce/dolly/xpki/certutil"
+	"github.com/juju/errors"
+)
+
+// DefaultOIDCScopes are requested, if scopes are not configured
+var DefaultOIDCScopes = []string{"openid", "profile", "email"}
+
+// allowed clock skew for ID token validation
+const oidcClockSkew = time.Minute
+
+// DefaultKeysRefreshInterval specifies the minimum interval between
+// the retrievals of OIDC keys, when the token is signed with unknown kid
+const DefaultKeysRefreshInterval = time.Minute
+
+// OIDCConfiguration provides OpenID Provider metadata
+type OIDCConfiguration struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint,omitempty"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// IDToken provides validated claims of OIDC ID token
+type IDToken struct {
+	Issuer    string       `json:"iss"`
+	Subject   string       `json:"sub"`
+	Audience  jwt.Audience `json:"aud"`
+	ExpiresAt int64        `json:"exp"`
+	IssuedAt  int64        `json:"iat"`
+	Nonce     string       `json:"nonce,omitempty"`
+	// Claims provides all claims of the token
+	Claims map[string]interface{} `json:"-"`
+}
+
+// OIDCUser provides user info mapped from ID token claims
+type OIDCUser struct {
+	Subject string
+	Login   string
+	Email   string
+	// EmailVerified is true, if the email is verified by the provider,
+	// or the provider is configured with TrustEmail
+	EmailVerified bool
+	Name          string
+	Groups        []string
+}
+
+// oidcState provides discovered metadata and keys of OIDC provider
+type oidcState struct {
+	lock      sync.Mutex
+	discovery *OIDCConfiguration
+	keys      *jwk.Set
+	// fetchLock serializes the retrievals of the keys,
+	// the lock is not held while the keys are retrieved
+	fetchLock sync.Mutex
+	fetchedAt time.Time
+	// refreshInterval specifies the minimum interval between the retrievals,
+	// if zero, then DefaultKeysRefreshInterval is used
+	refreshInterval time.Duration
+}
+
+// IsOIDC returns true, if the client is configured as OIDC provider
+func (p *Client) IsOIDC() bool {
+	return p.cfg.DiscoveryURL != ""
+}
+
+// SetHTTPClient sets HTTP client to be used for discovery and keys,
+// to be used in tests
+func (p *Client) SetHTTPClient(c *http.Client) *Client {
+	p.httpClient = c
+	return p
+}
+
+// SetKeysRefreshInterval sets the minimum interval between
+// the retrievals of OIDC keys, to be used in tests
+func (p *Client) SetKeysRefreshInterval(d time.Duration) *Client {
+	p.oidc.lock.Lock()
+	defer p.oidc.lock.Unlock()
+	p.oidc.refreshInterval = d
+	return p
+}
+
+// Discover returns OpenID Provider metadata,
+// the metadata is retrieved once on the first call
+func (p *Client) Discover(ctx context.Context) (*OIDCConfiguration, error) {
+	if !p.IsOIDC() {
+		return nil, errors.Errorf("OIDC discovery is not configured: %s", p.cfg.ProviderID)
+	}
+
+	p.oidc.lock.Lock()
+	defer p.oidc.lock.Unlock()
+
+	if p.oidc.discovery != nil {
+		return p.oidc.discovery, nil
+	}
+
+	var cfg OIDCConfiguration
+	err := p.getJSON(ctx, p.cfg.DiscoveryURL, &cfg)
+	if err != nil {
+		return nil, errors.Annotatef(err, "unable to discover OIDC provider")
+	}
+	if cfg.Issuer == "" || cfg.AuthorizationEndpoint == "" || cfg.TokenEndpoint == "" || cfg.JWKSURI == "" {
+		return nil, errors.Errorf("invalid OIDC discovery document: %s", p.cfg.DiscoveryURL)
+	}
+	if p.cfg.Issuer != "" && p.cfg.Issuer != cfg.Issuer {
+		return nil, errors.Errorf("unexpected OIDC issuer: %s", cfg.Issuer)
+	}
+
+	p.oidc.discovery = &cfg
+	return p.oidc.discovery, nil
+}
+
+// VerifyIDToken validates the signature and claims of ID token,
+// the nonce must match if provided
+func (p *Client) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*IDToken, error) {
+	discovery, err := p.Discover(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var raw json.RawMessage
+	_, err = jwt.Verify(rawIDToken, func(header *jwt.Header) (crypto.PublicKey, error) {
+		return p.publicKey(ctx, discovery.JWKSURI, header.Kid)
+	}, &raw)
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to verify ID token")
+	}
+
+	token := new(IDToken)
+	if err = json.Unmarshal(raw, token); err != nil {
+		return nil, errors.Annotatef(err, "invalid ID token")
+	}
+	if err = json.Unmarshal(raw, &token.Claims); err != nil {
+		return nil, errors.Annotatef(err, "invalid ID token")
+	}
+
+	audience := p.cfg.Audience
+	if audience == "" {
+		audience = p.cfg.ClientID
+	}
+
+	now := time.Now()
+	switch {
+	case token.Issuer != discovery.Issuer:
+		return nil, errors.Errorf("invalid issuer: %s", token.Issuer)
+	case !token.Audience.Contains(audience):
+		return nil, errors.Errorf("invalid audience: %v", []string(token.Audience))
+	case token.ExpiresAt == 0 || now.Add(-oidcClockSkew).Unix() > token.ExpiresAt:
+		return nil, errors.New("token is expired")
+	case token.IssuedAt > now.Add(oidcClockSkew).Unix():
+		return nil, errors.New("token used before issued")
+	case nonce != "" && token.Nonce != nonce:
+		return nil, errors.New("invalid nonce")
+	case token.Subject == "":
+		return nil, errors.New("missing subject")
+	}
+
+	return token, nil
+}
+
+// User returns user info, mapped from the ID token claims
+func (p *Client) User(token *IDToken) *OIDCUser {
+	m := p.cfg.Claims
+	return &OIDCUser{
+		Subject:       token.Subject,
+		Login:         claimString(token.Claims, m.Login, "preferred_username"),
+		Email:         claimString(token.Claims, m.Email, "email"),
+		EmailVerified: p.cfg.TrustEmail || claimBool(token.Claims, "email_verified"),
+		Name:          claimString(token.Claims, m.Name, "name"),
+		Groups:        claimStrings(token.Claims, m.Groups, "groups"),
+	}
+}
+
+// NewCodeVerifier returns random PKCE code verifier
+func NewCodeVerifier() string {
+	return base64.RawURLEncoding.EncodeToString(certutil.Random(32))
+}
+
+// CodeChallenge returns PKCE code challenge with S256 method
+func CodeChallenge(verifier string) string {
+	h := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+// publicKey returns the signing key of the provider,
+// the keys are refreshed if kid is not found, to support keys rotation.
+// The refresh is rate limited, so the tokens with unknown kid
+// can not force the retrieval of the keys on each request.
+func (p *Client) publicKey(ctx context.Context, jwksURI, kid string) (crypto.PublicKey, error) {
+	if key := p.cachedKey(kid); key != nil {
+		return key.PublicKey()
+	}
+
+	p.oidc.fetchLock.Lock()
+	defer p.oidc.fetchLock.Unlock()
+
+	p.oidc.lock.Lock()
+	// the keys may be refreshed while waiting for fetchLock
+	var key *jwk.Key
+	if p.oidc.keys != nil {
+		key = findKey(p.oidc.keys, kid)
+	}
+	interval := p.oidc.refreshInterval
+	if interval == 0 {
+		interval = DefaultKeysRefreshInterval
+	}
+	refresh := key == nil && (p.oidc.fetchedAt.IsZero() || time.Since(p.oidc.fetchedAt) >= interval)
+	if refresh {
+		p.oidc.fetchedAt = time.Now()
+	}
+	p.oidc.lock.Unlock()
+
+	if refresh {
+		keys := new(jwk.Set)
+		if err := p.getJSON(ctx, jwksURI, keys); err != nil {
+			return nil, errors.Annotatef(err, "unable to retrieve OIDC keys")
+		}
+
+		p.oidc.lock.Lock()
+		p.oidc.keys = keys
+		p.oidc.lock.Unlock()
+
+		key = findKey(keys, kid)
+	}
+	if key == nil {
+		return nil, errors.Errorf("unexpected kid: %s", kid)
+	}
+	return key.PublicKey()
+}
+
+// cachedKey returns the signing key from the retrieved keys,
+// or nil if not found
+func (p *Client) cachedKey(kid string) *jwk.Key {
+	p.oidc.lock.Lock()
+	defer p.oidc.lock.Unlock()
+	if p.oidc.keys == nil {
+		return nil
+	}
+	return findKey(p.oidc.keys, kid)
+}
+
+func (p *Client) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := p.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %d from %s", res.StatusCode, url)
+	}
+	if err = json.Unmarshal(body, v); err != nil {
+		return errors.Annotatef(err, "invalid response from %s", url)
+	}
+	return nil
+}
+
+// findKey returns the signing key by ID,
+// or the only signing key, if kid is not specified
+func findKey(keys *jwk.Set, kid string) *jwk.Key {
+	if kid != "" {
+		return keys.Find(kid)
+	}
+	var found *jwk.Key
+	for _, k := range keys.Keys {
+		if k.Use == "" || k.Use == "sig" {
+			if found != nil {
+				return nil
+			}
+			found = k
+		}
+	}
+	return found
+}
+
+func claimString(claims map[string]interface{}, name, def string) string {
+	if name == "" {
+		name = def
+	}
+	if s, ok := claims[name].(string); ok {
+		return s
+	}
+	return ""
+}
+
+// claimBool returns true, if the claim is boolean true,
+// some providers return boolean claims as strings
+func claimBool(claims map[string]interface{}, name string) bool {
+	switch v := claims[name].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+func claimStrings(claims map[string]interface{}, name, def string) []string {
+	if name == "" {
+		name = def
+	}
+	switch v := claims[name].(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return strings.Split(v, ",")
+	case []interface{}:
+		var list []string
+		for _, i := range v {
+			if s, ok := i.(string); ok && s != "" {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package oauth2client_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/jwk"
+	"github.com/ekspand/trusty/pkg/jwt"
+	"github.com/ekspand/trusty/pkg/oauth2client"
+	"github.com/go-phorce/dolly/xhttp/marshal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testIdP is a stand-in OpenID Provider
+type testIdP struct {
+	server *httptest.Server
+	keys   *jwk.Set
+	signer *ecdsa.PrivateKey
+	kid    string
+}
+
+func newTestIdP(t *testing.T) *testIdP {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	idp := &testIdP{
+		signer: key,
+		kid:    "key1",
+	}
+	idp.setKey(t, key, "key1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		marshal.WriteJSON(w, r, &oauth2client.OIDCConfiguration{
+			Issuer:                idp.server.URL,
+			AuthorizationEndpoint: idp.server.URL + "/authorize",
+			TokenEndpoint:         idp.server.URL + "/token",
+			JWKSURI:               idp.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		marshal.WriteJSON(w, r, idp.keys)
+	})
+	idp.server = httptest.NewServer(mux)
+	return idp
+}
+
+func (idp *testIdP) setKey(t *testing.T, key *ecdsa.PrivateKey, kid string) {
+	k, err := jwk.NewKey(key.Public(), "sig", kid)
+	require.NoError(t, err)
+	idp.signer = key
+	idp.kid = kid
+	idp.keys = &jwk.Set{Keys: []*jwk.Key{k}}
+}
+
+func (idp *testIdP) idToken(t *testing.T, claims map[string]interface{}) string {
+	c := map[string]interface{}{
+		"iss":   idp.server.URL,
+		"sub":   "00u1234",
+		"aud":   "trusty",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": "nonce1",
+	}
+	for k, v := range claims {
+		if v == nil {
+			delete(c, k)
+		} else {
+			c[k] = v
+		}
+	}
+	token, err := jwt.Sign(idp.signer, idp.kid, c)
+	require.NoError(t, err)
+	return token
+}
+
+func TestOIDC(t *testing.T) {
+	idp := newTestIdP(t)
+	defer idp.server.Close()
+
+	ctx := context.Background()
+
+	p, err := oauth2client.New(&oauth2client.Config{
+		ProviderID:   "okta",
+		ClientID:     "trusty",
+		ClientSecret: "secret",
+		DiscoveryURL: idp.server.URL + "/.well-known/openid-configuration",
+		Claims: oauth2client.ClaimsMapping{
+			Login:  "login",
+			Groups: "roles",
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, p.IsOIDC())
+
+	d, err := p.Discover(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, idp.server.URL, d.Issuer)
+	assert.Equal(t, idp.server.URL+"/token", d.TokenEndpoint)
+
+	token, err := p.VerifyIDToken(ctx, idp.idToken(t, map[string]interface{}{
+		"login":          "denis",
+		"email":          "denis@ekspand.com",
+		"email_verified": true,
+		"name":           "Denis",
+		"roles":          []string{"admin", "dev"},
+	}), "nonce1")
+	require.NoError(t, err)
+	assert.Equal(t, "00u1234", token.Subject)
+
+	u := p.User(token)
+	assert.Equal(t, &oauth2client.OIDCUser{
+		Subject:       "00u1234",
+		Login:         "denis",
+		Email:         "denis@ekspand.com",
+		EmailVerified: true,
+		Name:          "Denis",
+		Groups:        []string{"admin", "dev"},
+	}, u)
+
+	t.Run("email_verified", func(t *testing.T) {
+		for _, v := range []interface{}{nil, false, "false"} {
+			token, err := p.VerifyIDToken(ctx, idp.idToken(t, map[string]interface{}{
+				"email":          "denis@ekspand.com",
+				"email_verified": v,
+			}), "")
+			require.NoError(t, err)
+			assert.False(t, p.User(token).EmailVerified, "email_verified=%v", v)
+		}
+
+		token, err := p.VerifyIDToken(ctx, idp.idToken(t, map[string]interface{}{
+			"email":          "denis@ekspand.com",
+			"email_verified": "true",
+		}), "")
+		require.NoError(t, err)
+		assert.True(t, p.User(token).EmailVerified)
+
+		trusted, err := oauth2client.New(&oauth2client.Config{
+			ProviderID:   "okta",
+			ClientID:     "trusty",
+			DiscoveryURL: idp.server.URL + "/.well-known/openid-configuration",
+			TrustEmail:   true,
+		})
+		require.NoError(t, err)
+		token, err = trusted.VerifyIDToken(ctx, idp.idToken(t, map[string]interface{}{
+			"email": "denis@ekspand.com",
+		}), "")
+		require.NoError(t, err)
+		assert.True(t, trusted.User(token).EmailVerified)
+	})
+
+	tcases := []struct {
+		name   string
+		claims map[string]interface{}
+		nonce  string
+		err    string
+	}{
+		{"nonce", nil, "nonce2", "invalid nonce"},
+		{"audience", map[string]interface{}{"aud": []string{"other"}}, "", "invalid audience: [other]"},
+		{"issuer", map[string]interface{}{"iss": "https://other"}, "", "invalid issuer: https://other"},
+		{"expired", map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()}, "", "token is expired"},
+		{"no_exp", map[string]interface{}{"exp": nil}, "", "token is expired"},
+		{"future", map[string]interface{}{"iat": time.Now().Add(time.Hour).Unix()}, "", "token used before issued"},
+		{"subject", map[string]interface{}{"sub": nil}, "", "missing subject"},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := p.VerifyIDToken(ctx, idp.idToken(t, tc.claims), tc.nonce)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+
+	t.Run("rotation", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		idp.setKey(t, key, "key2")
+
+		// the keys were retrieved recently
+		_, err = p.VerifyIDToken(ctx, idp.idToken(t, nil), "")
+		assert.EqualError(t, err, "failed to verify ID token: unexpected kid: key2")
+
+		p.SetKeysRefreshInterval(time.Millisecond)
+		time.Sleep(2 * time.Millisecond)
+
+		token, err := p.VerifyIDToken(ctx, idp.idToken(t, nil), "")
+		require.NoError(t, err)
+		// default claims mapping
+		u := p.User(token)
+		assert.Empty(t, u.Login)
+		assert.Empty(t, u.Groups)
+
+		other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		forged, err := jwt.Sign(other, "key2", map[string]interface{}{"sub": "1"})
+		require.NoError(t, err)
+		_, err = p.VerifyIDToken(ctx, forged, "")
+		assert.EqualError(t, err, "failed to verify ID token: invalid signature")
+
+		forged, err = jwt.Sign(other, "key3", map[string]interface{}{"sub": "1"})
+		require.NoError(t, err)
+		_, err = p.VerifyIDToken(ctx, forged, "")
+		assert.EqualError(t, err, "failed to verify ID token: unexpected kid: key3")
+
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		forged, err = jwt.Sign(rsaKey, "key2", map[string]interface{}{"sub": "1"})
+		require.NoError(t, err)
+		_, err = p.VerifyIDToken(ctx, forged, "")
+		assert.EqualError(t, err, "failed to verify ID token: unexpected signing method: RS256")
+	})
+
+	t.Run("pkce", func(t *testing.T) {
+		v := oauth2client.NewCodeVerifier()
+		assert.NotEqual(t, v, oauth2client.NewCodeVerifier())
+		assert.Len(t, v, 43)
+		// BASE64URL(SHA256(verifier))
+		assert.Equal(t, "NPsYzawS-__wqk67X9gyb4dr3JBo3hnlEi5MNyD5jX0",
+			oauth2client.CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r-wW1gFWFOEjXk"))
+	})
+
+	t.Run("discovery", func(t *testing.T) {
+		p, err := oauth2client.New(&oauth2client.Config{
+			ProviderID:   "okta",
+			ClientID:     "trusty",
+			Issuer:       "https://other",
+			DiscoveryURL: idp.server.URL + "/.well-known/openid-configuration",
+		})
+		require.NoError(t, err)
+		_, err = p.Discover(ctx)
+		assert.EqualError(t, err, "unexpected OIDC issuer: "+idp.server.URL)
+
+		p, err = oauth2client.New(&oauth2client.Config{
+			ProviderID:   "okta",
+			DiscoveryURL: idp.server.URL + "/missing",
+		})
+		require.NoError(t, err)
+		_, err = p.Discover(ctx)
+		assert.EqualError(t, err, "unable to discover OIDC provider: unexpected status 404 from "+idp.server.URL+"/missing")
+
+		p, err = oauth2client.New(&oauth2client.Config{ProviderID: "github"})
+		require.NoError(t, err)
+		assert.False(t, p.IsOIDC())
+		_, err = p.Discover(ctx)
+		assert.EqualError(t, err, "OIDC discovery is not configured: github")
+	})
+}
@@ -0,0 +1,77 @@
+package print
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/olekukonko/tablewriter"
+)
+
+// ServiceAccountsTable prints list of Service Accounts
+func ServiceAccountsTable(w io.Writer, list []*v1.ServiceAccount) {
+	table := tablewriter.NewWriter(w)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Id", "OrgId", "Name", "Created", "Description"})
+
+	for _, sa := range list {
+		table.Append([]string{
+			sa.ID,
+			sa.OrgID,
+			sa.Name,
+			sa.CreatedAt.Local().Format(time.RFC3339),
+			sa.Description,
+		})
+	}
+	table.Render()
+	fmt.Fprintln(w)
+}
+
+// APITokensTable prints list of API Tokens
+func APITokensTable(w io.Writer, list []*v1.APIToken) {
+	table := tablewriter.NewWriter(w)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Id", "Name", "Created", "Expires", "Last Used", "Revoked"})
+
+	for _, t := range list {
+		table.Append([]string{
+			t.ID,
+			t.Name,
+			t.CreatedAt.Local().Format(time.RFC3339),
+			optionalTime(t.ExpiresAt),
+			optionalTime(t.LastUsedAt),
+			optionalTime(t.RevokedAt),
+		})
+	}
+	table.Render()
+	fmt.Fprintln(w)
+}
+
+func optionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Local().Format(time.RFC3339)
+}
+
+// RoleBindingsTable prints list of Role Bindings
+func RoleBindingsTable(w io.Writer, list []*v1.RoleBinding) {
+	table := tablewriter.NewWriter(w)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Id", "Role", "Subject", "Created"})
+
+	for _, b := range list {
+		table.Append([]string{
+			b.ID,
+			b.Role,
+			b.Subject,
+			b.CreatedAt.Local().Format(time.RFC3339),
+		})
+	}
+	table.Render()
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,71 @@
+package print_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	v1 "github.com/ekspand/trusty/api/v1"
+	"github.com/ekspand/trusty/pkg/print"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceAccountsTable(t *testing.T) {
+	created, err := time.Parse(time.RFC3339, "2012-11-01T22:08:41+00:00")
+	require.NoError(t, err)
+
+	list := []*v1.ServiceAccount{
+		{
+			ID:          "123",
+			OrgID:       "1000",
+			Name:        "ci",
+			Description: "CI pipeline",
+			CreatedAt:   created,
+		},
+	}
+	w := bytes.NewBuffer([]byte{})
+	print.ServiceAccountsTable(w, list)
+	out := w.String()
+	assert.Contains(t, out, "  ID  | ORGID | NAME |")
+	assert.Contains(t, out, "  123 | 1000  | ci   |")
+}
+
+func TestAPITokensTable(t *testing.T) {
+	created, err := time.Parse(time.RFC3339, "2012-11-01T22:08:41+00:00")
+	require.NoError(t, err)
+	expires := created.Add(time.Hour)
+
+	list := []*v1.APIToken{
+		{
+			ID:        "123",
+			Name:      "deploy",
+			CreatedAt: created,
+			ExpiresAt: &expires,
+		},
+	}
+	w := bytes.NewBuffer([]byte{})
+	print.APITokensTable(w, list)
+	out := w.String()
+	assert.Contains(t, out, "  ID  |  NAME  |")
+	assert.Contains(t, out, "LAST USED")
+}
+
+func TestRoleBindingsTable(t *testing.T) {
+	created, err := time.Parse(time.RFC3339, "2012-11-01T22:08:41+00:00")
+	require.NoError(t, err)
+
+	list := []*v1.RoleBinding{
+		{
+			ID:        "123",
+			Role:      "trusty-admin",
+			Subject:   "group:admins",
+			CreatedAt: created,
+		},
+	}
+	w := bytes.NewBuffer([]byte{})
+	print.RoleBindingsTable(w, list)
+	out := w.String()
+	assert.Contains(t, out, "  ID  |     ROLE     |   SUBJECT    |")
+	assert.Contains(t, out, "  123 | trusty-admin | group:admins |")
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -47,8 +48,35 @@ func CallerStatusResponse(w io.Writer, r *pb.CallerStatusResponse) {
 	table.Append([]string{"Name", r.Name})
 	table.Append([]string{"ID", r.Id})
 	table.Append([]string{"Role", r.Role})
+	if len(r.Roles) > 0 {
+		table.Append([]string{"Roles", strings.Join(r.Roles, ",")})
+	}
 	table.Render()
 	fmt.Fprintln(w)
+
+	if len(r.Permissions) > 0 {
+		table = tablewriter.NewWriter(w)
+		table.SetBorder(false)
+		table.SetHeader([]string{"Permission", "Methods", "Issuers", "Profiles", "Orgs"})
+		for _, p := range r.Permissions {
+			table.Append([]string{
+				p.Name,
+				strings.Join(p.Methods, ","),
+				anyIfEmpty(p.Issuers),
+				anyIfEmpty(p.Profiles),
+				anyIfEmpty(p.Orgs),
+			})
+		}
+		table.Render()
+		fmt.Fprintln(w)
+	}
+}
+
+func anyIfEmpty(list []string) string {
+	if len(list) == 0 {
+		return "*"
+	}
+	return strings.Join(list, ",")
 }
 
 // Issuers prints list of IssuerInfo
@@ -78,6 +106,9 @@ func Issuers(w io.Writer, issuers []*pb.IssuerInfo, withPem bool) {
 		fmt.Fprintf(w, "  Serial: %s\n", bundle.Cert.SerialNumber.String())
 		fmt.Fprintf(w, "  Issued: %s (%s ago)\n", bundle.Cert.NotBefore.Local().String(), issuedIn.String())
 		fmt.Fprintf(w, "  Expires: %s (in %s)\n", bundle.Cert.NotAfter.Local().String(), expiresIn.String())
+		if ci.State != pb.IssuerState_ACTIVE {
+			fmt.Fprintf(w, "  State: %s\n", ci.State.String())
+		}
 		if len(bundleStatus.ExpiringSKIs) > 0 {
 			fmt.Fprintf(w, "  Expiring SKI:\n")
 			for _, ski := range bundleStatus.ExpiringSKIs {
@@ -171,7 +202,7 @@ func CertificatesTable(w io.Writer, list []*pb.Certificate) {
 	table := tablewriter.NewWriter(w)
 	table.SetBorder(false)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
-	table.SetHeader([]string{"Id", "OrgId", "SKID", "Serial", "From", "To", "Subject", "Profile"})
+	table.SetHeader([]string{"Id", "OrgId", "SKID", "Serial", "From", "To", "Subject", "Profile", "Labels"})
 
 	for _, c := range list {
 		table.Append([]string{
@@ -183,12 +214,27 @@ func CertificatesTable(w io.Writer, list []*pb.Certificate) {
 			c.NotAfter.AsTime().Local().Format(time.RFC3339),
 			c.Subject,
 			c.Profile,
+			labels(c.Labels),
 		})
 	}
 	table.Render()
 	fmt.Fprintln(w)
 }
 
+// labels returns sorted key=value pairs
+func labels(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + m[k]
+	}
+	return strings.Join(pairs, ",")
+}
+
 // RevokedCertificatesTable prints list of Revoked Certificates
 func RevokedCertificatesTable(w io.Writer, list []*pb.RevokedCertificate) {
 	table := tablewriter.NewWriter(w)
@@ -235,3 +281,84 @@ func CrlsTable(w io.Writer, list []*pb.Crl) {
 	table.Render()
 	fmt.Fprintln(w)
 }
+
+// ApprovalsTable prints list of requests in the approval queue
+func ApprovalsTable(w io.Writer, list []*pb.Approval) {
+	table := tablewriter.NewWriter(w)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Id", "OrgId", "Profile", "Subject", "Requester", "Status", "Approvals", "Expires"})
+
+	for _, a := range list {
+		table.Append([]string{
+			strconv.FormatUint(a.Id, 10),
+			strconv.FormatUint(a.OrgId, 10),
+			a.Profile,
+			a.Subject,
+			a.Requester,
+			a.Status.String(),
+			fmt.Sprintf("%d/%d", approvalsCount(a), a.RequiredApprovals),
+			a.ExpiresAt.AsTime().Local().Format(time.RFC3339),
+		})
+	}
+	table.Render()
+	fmt.Fprintln(w)
+}
+
+// Approval prints the request in the approval queue
+func Approval(w io.Writer, a *pb.Approval) {
+	table := tablewriter.NewWriter(w)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.Append([]string{"ID", strconv.FormatUint(a.Id, 10)})
+	table.Append([]string{"Status", a.Status.String()})
+	table.Append([]string{"Profile", a.Profile})
+	table.Append([]string{"Issuer", a.IssuerLabel})
+	if a.OrgId != 0 {
+		table.Append([]string{"OrgId", strconv.FormatUint(a.OrgId, 10)})
+	}
+	table.Append([]string{"Subject", a.Subject})
+	if len(a.San) > 0 {
+		table.Append([]string{"SAN", strings.Join(a.San, ",")})
+	}
+	table.Append([]string{"Requester", a.Requester})
+	table.Append([]string{"Approvers", strings.Join(a.ApproverRoles, ",")})
+	table.Append([]string{"Approvals", fmt.Sprintf("%d/%d", approvalsCount(a), a.RequiredApprovals)})
+	table.Append([]string{"Created", a.CreatedAt.AsTime().Local().Format(time.RFC3339)})
+	table.Append([]string{"Expires", a.ExpiresAt.AsTime().Local().Format(time.RFC3339)})
+	if a.Certificate != nil {
+		table.Append([]string{"Certificate", strconv.FormatUint(a.Certificate.Id, 10)})
+	}
+	table.Render()
+	fmt.Fprintln(w)
+
+	if len(a.Votes) > 0 {
+		table = tablewriter.NewWriter(w)
+		table.SetBorder(false)
+		table.SetHeader([]string{"Approver", "Decision", "Reason", "Time"})
+		for _, v := range a.Votes {
+			decision := "denied"
+			if v.Approved {
+				decision = "approved"
+			}
+			table.Append([]string{
+				v.Approver,
+				decision,
+				v.Reason,
+				v.CreatedAt.AsTime().Local().Format(time.RFC3339),
+			})
+		}
+		table.Render()
+		fmt.Fprintln(w)
+	}
+}
+
+func approvalsCount(a *pb.Approval) int {
+	count := 0
+	for _, v := range a.Votes {
+		if v.Approved {
+			count++
+		}
+	}
+	return count
+}
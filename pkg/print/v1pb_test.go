@@ -75,6 +75,24 @@ func TestCallerStatusResponse(t *testing.T) {
 	assert.Equal(t, "  Name | local             \n"+
 		"  ID   | 12341234-1234124  \n"+
 		"  Role | trustry           \n\n", out)
+
+	r.Roles = []string{"trustry", "trusty-admin"}
+	r.Permissions = []*pb.CallerPermission{
+		{
+			Name:     "sign-server",
+			Methods:  []string{"/pb.CAService/SignCertificate"},
+			Issuers:  []string{"issuer1"},
+			Profiles: []string{"server"},
+		},
+	}
+	w.Reset()
+	print.CallerStatusResponse(w, r)
+	out = w.String()
+	assert.Contains(t, out, "  Roles | trustry,trusty-admin")
+	assert.Contains(t, out, "sign-server")
+	assert.Contains(t, out, "/pb.CAService/SignCertificate")
+	assert.Contains(t, out, "issuer1")
+	assert.Contains(t, out, "server")
 }
 
 func TestRoots(t *testing.T) {
@@ -114,12 +132,14 @@ func TestCertificatesTable(t *testing.T) {
 			Skid:      "23423",
 			NotBefore: timestamppb.New(nb.UTC()),
 			NotAfter:  timestamppb.New(na.UTC()),
+			Labels:    map[string]string{"team": "security", "env": "prod"},
 		},
 	}
 	w := bytes.NewBuffer([]byte{})
 	print.CertificatesTable(w, list)
 	out := w.String()
 	assert.Contains(t, out, "  ID  | ORGID | SKID  | SERIAL |")
+	assert.Contains(t, out, "| env=prod,team=security")
 }
 
 func TestRevokedCertificatesTable(t *testing.T) {
@@ -169,3 +189,40 @@ func TestCrlTable(t *testing.T) {
 	out := w.String()
 	assert.Contains(t, out, "  ID  |  IKID  |")
 }
+
+func TestApprovals(t *testing.T) {
+	created, err := time.Parse(time.RFC3339, "2021-06-01T10:00:00+00:00")
+	require.NoError(t, err)
+
+	a := &pb.Approval{
+		Id:                123,
+		OrgId:             1000,
+		Profile:           "subca",
+		IssuerLabel:       "trusty.svc",
+		Subject:           "CN=sub-ca",
+		San:               []string{"ca.trusty.local"},
+		Requester:         "denis@ekspand.com",
+		Status:            pb.ApprovalStatus_PENDING,
+		RequiredApprovals: 2,
+		ApproverRoles:     []string{"trusty-admin"},
+		Votes: []*pb.ApprovalVote{
+			{Approver: "admin@ekspand.com", Approved: true, Reason: "ticket 123", CreatedAt: timestamppb.New(created)},
+		},
+		CreatedAt: timestamppb.New(created),
+		ExpiresAt: timestamppb.New(created.Add(72 * time.Hour)),
+	}
+
+	w := bytes.NewBuffer([]byte{})
+	print.ApprovalsTable(w, []*pb.Approval{a})
+	out := w.String()
+	assert.Contains(t, out, "  ID  | ORGID | PROFILE |  SUBJECT  |     REQUESTER     | STATUS  | APPROVALS |")
+	assert.Contains(t, out, "| PENDING | 1/2       |")
+
+	w.Reset()
+	print.Approval(w, a)
+	out = w.String()
+	assert.Contains(t, out, "  Status    | PENDING")
+	assert.Contains(t, out, "  SAN       | ca.trusty.local")
+	assert.Contains(t, out, "  Approvals | 1/2")
+	assert.Contains(t, out, "  admin@ekspand.com | approved | ticket 123 |")
+}
@@ -0,0 +1,37 @@
+package roles
+
+import (
+	"github.com/go-phorce/dolly/xhttp/identity"
+)
+
+// Identity provides identity with multiple roles,
+// the Role() method returns the role mapped by the identity map
+type Identity interface {
+	identity.Identity
+	// Roles returns all roles of the identity
+	Roles() []string
+}
+
+type rolesIdentity struct {
+	identity.Identity
+	roles []string
+}
+
+// NewIdentityWithRoles returns Identity with the role of the specified identity,
+// and the additional roles
+func NewIdentityWithRoles(idn identity.Identity, roles ...string) Identity {
+	res := rolesIdentity{Identity: idn}
+	seen := map[string]bool{}
+	for _, role := range append([]string{idn.Role()}, roles...) {
+		if role != "" && !seen[role] {
+			seen[role] = true
+			res.roles = append(res.roles, role)
+		}
+	}
+	return res
+}
+
+// Roles returns all roles of the identity
+func (i rolesIdentity) Roles() []string {
+	return i.roles
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	v1 "github.com/ekspand/trusty/api/v1"
 	"github.com/ekspand/trusty/internal/config"
 	tcredentials "github.com/ekspand/trusty/pkg/credentials"
 	"github.com/ekspand/trusty/pkg/jwt"
@@ -29,6 +30,9 @@ const (
 
 	// JWTUserRoleName defines a generic role name for an authenticated user
 	JWTUserRoleName = "jwt_authenticated"
+
+	// APITokenRoleName defines a generic role name for an authenticated service account
+	APITokenRoleName = "api_token_authenticated"
 )
 
 // IdentityProvider interface to extract identity from requests
@@ -44,21 +48,59 @@ type IdentityProvider interface {
 	IdentityFromContext(ctx context.Context) (identity.Identity, error)
 }
 
+// APITokenVerifier interface to verify API tokens of service accounts
+type APITokenVerifier interface {
+	// VerifyAPIToken returns the service account name in ${org}/${name} format,
+	// and ID of the token
+	VerifyAPIToken(ctx context.Context, token string) (name, id string, err error)
+}
+
+// SessionVerifier interface to verify login sessions of issued JWT
+type SessionVerifier interface {
+	// VerifySession returns ID of the user and groups asserted at login,
+	// if the session with the specified `jti` is active
+	VerifySession(ctx context.Context, jti string) (userID string, groups []string, err error)
+}
+
+// RoleResolver interface to resolve roles at the time of the request
+type RoleResolver interface {
+	// RoleBindings returns roles bound to the subjects
+	RoleBindings(ctx context.Context, subjects []string) ([]string, error)
+	// OrgRoles returns roles of the user in orgs, in ${org}/${role} format
+	OrgRoles(ctx context.Context, userID string) ([]string, error)
+}
+
 // Provider for identity
 type provider struct {
-	config   config.IdentityMap
-	jwtRoles map[string]string
-	tlsRoles map[string]string
-	jwt      jwt.Parser
+	config     config.IdentityMap
+	jwtRoles   map[string]string
+	tlsRoles   map[string]string
+	apiRoles   map[string]string
+	orgRoles   map[string][]string
+	groupRoles map[string][]string
+	jwt        jwt.Parser
+	tokens     APITokenVerifier
+	sessions   SessionVerifier
+	resolver   RoleResolver
 }
 
-// New returns Authz provider instance
-func New(config *config.IdentityMap, jwt jwt.Parser) (IdentityProvider, error) {
+// New returns Authz provider instance.
+// If sessions verifier is provided, then JWT with revoked or unknown `jti`
+// are rejected, and `jti` is resolved to the user ID.
+// If dynamic roles are enabled, then the identity has roles resolved
+// from org membership, IdP groups and role bindings, in addition to the mapped role.
+func New(config *config.IdentityMap, jwt jwt.Parser, tokens APITokenVerifier, sessions SessionVerifier, resolver RoleResolver) (IdentityProvider, error) {
 	prov := &provider{
-		config:   *config,
-		jwtRoles: make(map[string]string),
-		tlsRoles: make(map[string]string),
-		jwt:      jwt,
+		config:     *config,
+		jwtRoles:   make(map[string]string),
+		tlsRoles:   make(map[string]string),
+		apiRoles:   make(map[string]string),
+		orgRoles:   make(map[string][]string),
+		groupRoles: make(map[string][]string),
+		jwt:        jwt,
+		tokens:     tokens,
+		sessions:   sessions,
+		resolver:   resolver,
 	}
 
 	if config.JWT.Enabled {
@@ -75,13 +117,38 @@ func New(config *config.IdentityMap, jwt jwt.Parser) (IdentityProvider, error) {
 			}
 		}
 	}
+	if config.APIToken.Enabled {
+		if tokens == nil {
+			return nil, errors.New("API token verifier is not provided")
+		}
+		for role, accounts := range config.APIToken.Roles {
+			for _, account := range accounts {
+				prov.apiRoles[account] = role
+			}
+		}
+	}
+	if config.DynamicRoles.Enabled {
+		if resolver == nil {
+			return nil, errors.New("role resolver is not provided")
+		}
+		for role, memberships := range config.DynamicRoles.OrgRoles {
+			for _, membership := range memberships {
+				prov.orgRoles[membership] = append(prov.orgRoles[membership], role)
+			}
+		}
+		for role, groups := range config.DynamicRoles.GroupRoles {
+			for _, group := range groups {
+				prov.groupRoles[group] = append(prov.groupRoles[group], role)
+			}
+		}
+	}
 
 	return prov, nil
 }
 
 // ApplicableForRequest returns true if the provider is applicable for the request
 func (p *provider) ApplicableForRequest(r *http.Request) bool {
-	if p.config.JWT.Enabled {
+	if p.config.JWT.Enabled || p.config.APIToken.Enabled {
 		key := r.Header.Get(header.Authorization)
 		if key != "" && strings.HasPrefix(key, header.Bearer) {
 			return true
@@ -96,7 +163,7 @@ func (p *provider) ApplicableForRequest(r *http.Request) bool {
 
 // ApplicableForContext returns true if the provider is applicable for context
 func (p *provider) ApplicableForContext(ctx context.Context) bool {
-	if p.config.JWT.Enabled {
+	if p.config.JWT.Enabled || p.config.APIToken.Enabled {
 		md, ok := metadata.FromIncomingContext(ctx)
 		if ok && len(md["authorization"]) > 0 {
 			return true
@@ -117,15 +184,19 @@ func (p *provider) ApplicableForContext(ctx context.Context) bool {
 
 // IdentityFromRequest returns identity from the request
 func (p *provider) IdentityFromRequest(r *http.Request) (identity.Identity, error) {
-	if p.config.JWT.Enabled {
-		key := r.Header.Get(header.Authorization)
-		if key != "" && strings.HasPrefix(key, header.Bearer) {
-			return p.jwtIdentity(key[7:])
+	key := r.Header.Get(header.Authorization)
+	if key != "" && strings.HasPrefix(key, header.Bearer) {
+		token := key[7:]
+		if p.config.APIToken.Enabled && isAPIToken(token) {
+			return p.apiTokenIdentity(r.Context(), token)
+		}
+		if p.config.JWT.Enabled {
+			return p.jwtIdentity(r.Context(), token)
 		}
 	}
 
 	if p.config.TLS.Enabled && r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
-		id, err := p.tlsIdentity(r.TLS)
+		id, err := p.tlsIdentity(r.Context(), r.TLS)
 		if err == nil {
 			logger.Debugf("type=TLS, role=%v", id)
 			return id, nil
@@ -139,10 +210,14 @@ func (p *provider) IdentityFromRequest(r *http.Request) (identity.Identity, erro
 
 // IdentityFromContext returns identity from context
 func (p *provider) IdentityFromContext(ctx context.Context) (identity.Identity, error) {
-	if p.config.JWT.Enabled {
-		md, ok := metadata.FromIncomingContext(ctx)
-		if ok && len(md[tcredentials.TokenFieldNameGRPC]) > 0 {
-			return p.jwtIdentity(md[tcredentials.TokenFieldNameGRPC][0])
+	md, ok := metadata.FromIncomingContext(ctx)
+	if ok && len(md[tcredentials.TokenFieldNameGRPC]) > 0 {
+		token := md[tcredentials.TokenFieldNameGRPC][0]
+		if p.config.APIToken.Enabled && isAPIToken(token) {
+			return p.apiTokenIdentity(ctx, token)
+		}
+		if p.config.JWT.Enabled {
+			return p.jwtIdentity(ctx, token)
 		}
 	}
 
@@ -151,7 +226,7 @@ func (p *provider) IdentityFromContext(ctx context.Context) (identity.Identity,
 		if ok {
 			si, ok := c.AuthInfo.(credentials.TLSInfo)
 			if ok && len(si.State.PeerCertificates) > 0 {
-				id, err := p.tlsIdentity(&si.State)
+				id, err := p.tlsIdentity(ctx, &si.State)
 				if err == nil {
 					logger.Debugf("type=TLS, role=%v", id)
 					return id, nil
@@ -162,31 +237,119 @@ func (p *provider) IdentityFromContext(ctx context.Context) (identity.Identity,
 	return identity.GuestIdentityForContext(ctx)
 }
 
-func (p *provider) jwtIdentity(auth string) (identity.Identity, error) {
+func (p *provider) jwtIdentity(ctx context.Context, auth string) (identity.Identity, error) {
 	token, err := p.jwt.ParseToken(auth, p.config.JWT.Audience)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	userID := token.Id
+	var groups []string
+	if p.sessions != nil {
+		userID, groups, err = p.sessions.VerifySession(ctx, token.Id)
+		if err != nil {
+			logger.Debugf("reason=session, subject=%s, id=%s, err=[%s]",
+				token.Subject, token.Id, err.Error())
+			return nil, errors.Trace(err)
+		}
+	}
 	role := p.jwtRoles[token.Subject]
 	if role == "" {
 		role = p.config.JWT.DefaultAuthenticatedRole
 	}
-	logger.Debugf("role=%s, subject=%s, id=%s",
-		role, token.Subject, token.Id)
-	return identity.NewIdentity(role, token.Subject, token.Id), nil
+	logger.Debugf("role=%s, subject=%s, id=%s, user=%s",
+		role, token.Subject, token.Id, userID)
+	// the claims are provided as UserInfo, to identify the session of the caller
+	idn := identity.NewIdentityWithUserInfo(role, token.Subject, userID, token)
+	if p.sessions == nil {
+		// without sessions, the user is not known
+		userID = ""
+	}
+	return p.withDynamicRoles(ctx, idn, userID, []string{v1.RoleBindingUserPrefix + token.Subject}, groups), nil
 }
 
-func (p *provider) tlsIdentity(TLS *tls.ConnectionState) (identity.Identity, error) {
+func (p *provider) apiTokenIdentity(ctx context.Context, token string) (identity.Identity, error) {
+	name, id, err := p.tokens.VerifyAPIToken(ctx, token)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	role := p.apiRoles[name]
+	if role == "" {
+		role = p.config.APIToken.DefaultAuthenticatedRole
+	}
+	logger.Debugf("type=APIToken, role=%s, name=%s, token_id=%s",
+		role, name, id)
+	// the service account is not a user, so UserID is not set
+	idn := identity.NewIdentity(role, name, "")
+	return p.withDynamicRoles(ctx, idn, "", []string{v1.RoleBindingServiceAccountPrefix + name}, nil), nil
+}
+
+func isAPIToken(token string) bool {
+	return strings.HasPrefix(token, v1.APITokenPrefix)
+}
+
+func (p *provider) tlsIdentity(ctx context.Context, TLS *tls.ConnectionState) (identity.Identity, error) {
 	peer := TLS.PeerCertificates[0]
-	if len(peer.URIs) == 1 && peer.URIs[0].Scheme == "spifee" {
+	if len(peer.URIs) == 1 && (peer.URIs[0].Scheme == "spifee" || peer.URIs[0].Scheme == "spiffe") {
 		spifee := peer.URIs[0].String()
 		role := p.tlsRoles[spifee]
 		if role == "" {
 			role = p.config.TLS.DefaultAuthenticatedRole
 		}
+		// X.509-SVID may have empty Subject
+		name := peer.Subject.CommonName
+		if name == "" {
+			name = spifee
+		}
 		logger.Debugf("spifee=%s, role=%s", spifee, role)
-		return identity.NewIdentity(role, peer.Subject.CommonName, ""), nil
+		subjects := []string{v1.RoleBindingSPIFFEPrefix + spifee}
+		if name != spifee {
+			subjects = append(subjects, v1.RoleBindingCNPrefix+name)
+		}
+		return p.withDynamicRoles(ctx, identity.NewIdentity(role, name, ""), "", subjects, nil), nil
 	}
 
 	return nil, errors.Errorf("could not determine identity: %q", peer.Subject.CommonName)
 }
+
+// withDynamicRoles returns the identity with roles resolved from
+// org membership of the user, IdP groups and role bindings of the subjects.
+// The subjects are prefixed with their type, see v1.RoleBindingPrefixes,
+// and the groups are in ${provider}/${group} format.
+// The resolved roles only extend the access of the identity,
+// so the failure to resolve is logged, and the mapped role is used.
+func (p *provider) withDynamicRoles(ctx context.Context, idn identity.Identity, userID string, subjects, groups []string) identity.Identity {
+	if !p.config.DynamicRoles.Enabled {
+		return idn
+	}
+
+	var roles []string
+	for _, group := range groups {
+		roles = append(roles, p.groupRoles[group]...)
+		subjects = append(subjects, v1.RoleBindingGroupPrefix+group)
+	}
+
+	if userID != "" {
+		memberships, err := p.resolver.OrgRoles(ctx, userID)
+		if err != nil {
+			logger.Warningf("reason=OrgRoles, name=%s, user=%s, err=[%s]",
+				idn.Name(), userID, err.Error())
+		}
+		for _, membership := range memberships {
+			roles = append(roles, p.orgRoles[membership]...)
+			if i := strings.LastIndex(membership, "/"); i >= 0 {
+				roles = append(roles, p.orgRoles["*"+membership[i:]]...)
+			}
+		}
+	}
+
+	bound, err := p.resolver.RoleBindings(ctx, subjects)
+	if err != nil {
+		logger.Warningf("reason=RoleBindings, name=%s, err=[%s]",
+			idn.Name(), err.Error())
+	}
+	roles = append(roles, bound...)
+
+	res := NewIdentityWithRoles(idn, roles...)
+	logger.Debugf("name=%s, roles=%v", idn.Name(), res.Roles())
+	return res
+}
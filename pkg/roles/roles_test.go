@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"net/http"
 	"net/url"
 	"testing"
@@ -21,7 +22,7 @@ import (
 )
 
 func Test_Empty(t *testing.T) {
-	p, err := roles.New(&config.IdentityMap{}, nil)
+	p, err := roles.New(&config.IdentityMap{}, nil, nil, nil, nil)
 	require.NoError(t, err)
 
 	r, _ := http.NewRequest(http.MethodGet, "/", nil)
@@ -54,7 +55,7 @@ func Test_All(t *testing.T) {
 				"trusty-client": {"denis@trusty.ca"},
 			},
 		},
-	}, mock)
+	}, mock, nil, nil, nil)
 	require.NoError(t, err)
 
 	t.Run("default role http", func(t *testing.T) {
@@ -115,11 +116,30 @@ func TestTLSOnly(t *testing.T) {
 			DefaultAuthenticatedRole: "tls_authenticated",
 			Roles: map[string][]string{
 				"trusty-client": {"spifee://trusty/client"},
+				"trusty-svid":   {"spiffe://trusty.ekspand.com/workload"},
 			},
 		},
-	}, nil)
+	}, nil, nil, nil, nil)
 	require.NoError(t, err)
 
+	t.Run("tls:svid", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+
+		u, _ := url.Parse("spiffe://trusty.ekspand.com/workload")
+		r.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{
+				{
+					URIs: []*url.URL{u},
+				},
+			},
+		}
+
+		id, err := p.IdentityFromRequest(r)
+		require.NoError(t, err)
+		assert.Equal(t, "trusty-svid", id.Role())
+		assert.Equal(t, "spiffe://trusty.ekspand.com/workload", id.Name())
+	})
+
 	t.Run("default role http", func(t *testing.T) {
 		r, _ := http.NewRequest(http.MethodGet, "/", nil)
 		setAuthorizationHeader(r, "AccessToken123")
@@ -204,6 +224,271 @@ func TestTLSOnly(t *testing.T) {
 
 }
 
+func TestAPIToken(t *testing.T) {
+	_, err := roles.New(&config.IdentityMap{
+		APIToken: config.APITokenIdentityMap{Enabled: true},
+	}, nil, nil, nil, nil)
+	assert.EqualError(t, err, "API token verifier is not provided")
+
+	mock := mockJWT{
+		claims: &jwtjwt.StandardClaims{
+			Subject: "denis@trusty.com",
+		},
+	}
+	tokens := mockTokens{
+		"trusty_ci":     "ekspand/ci",
+		"trusty_deploy": "ekspand/deploy",
+	}
+
+	p, err := roles.New(&config.IdentityMap{
+		JWT: config.JWTIdentityMap{
+			Enabled:                  true,
+			DefaultAuthenticatedRole: "jwt_authenticated",
+		},
+		APIToken: config.APITokenIdentityMap{
+			Enabled:                  true,
+			DefaultAuthenticatedRole: "api_token_authenticated",
+			Roles: map[string][]string{
+				"trusty-deploy": {"ekspand/deploy"},
+			},
+		},
+	}, mock, tokens, nil, nil)
+	require.NoError(t, err)
+
+	t.Run("http", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		setAuthorizationHeader(r, "trusty_ci")
+		assert.True(t, p.ApplicableForRequest(r))
+
+		id, err := p.IdentityFromRequest(r)
+		require.NoError(t, err)
+		assert.Equal(t, "api_token_authenticated", id.Role())
+		assert.Equal(t, "ekspand/ci", id.Name())
+		assert.Empty(t, id.UserID())
+
+		setAuthorizationHeader(r, "trusty_deploy")
+		id, err = p.IdentityFromRequest(r)
+		require.NoError(t, err)
+		assert.Equal(t, "trusty-deploy", id.Role())
+		assert.Equal(t, "ekspand/deploy", id.Name())
+
+		setAuthorizationHeader(r, "trusty_revoked")
+		_, err = p.IdentityFromRequest(r)
+		assert.EqualError(t, err, "invalid API token")
+
+		// JWT is still accepted
+		setAuthorizationHeader(r, "AccessToken123")
+		id, err = p.IdentityFromRequest(r)
+		require.NoError(t, err)
+		assert.Equal(t, "jwt_authenticated", id.Role())
+		assert.Equal(t, "denis@trusty.com", id.Name())
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "trusty_deploy"))
+		assert.True(t, p.ApplicableForContext(ctx))
+
+		id, err := p.IdentityFromContext(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "trusty-deploy", id.Role())
+		assert.Equal(t, "ekspand/deploy", id.Name())
+
+		ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "trusty_revoked"))
+		_, err = p.IdentityFromContext(ctx)
+		assert.EqualError(t, err, "invalid API token")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		p, err := roles.New(&config.IdentityMap{}, nil, tokens, nil, nil)
+		require.NoError(t, err)
+
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		setAuthorizationHeader(r, "trusty_ci")
+		assert.False(t, p.ApplicableForRequest(r))
+
+		id, err := p.IdentityFromRequest(r)
+		require.NoError(t, err)
+		assert.Equal(t, "guest", id.Role())
+	})
+}
+
+func TestSessions(t *testing.T) {
+	mock := mockJWT{
+		claims: &jwtjwt.StandardClaims{
+			Id:      "1001",
+			Subject: "denis@trusty.com",
+		},
+	}
+	sessions := mockSessions{
+		"1001": {userID: "100"},
+	}
+
+	p, err := roles.New(&config.IdentityMap{
+		JWT: config.JWTIdentityMap{
+			Enabled:                  true,
+			DefaultAuthenticatedRole: "jwt_authenticated",
+		},
+	}, mock, nil, sessions, nil)
+	require.NoError(t, err)
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	setAuthorizationHeader(r, "AccessToken123")
+
+	id, err := p.IdentityFromRequest(r)
+	require.NoError(t, err)
+	assert.Equal(t, "jwt_authenticated", id.Role())
+	assert.Equal(t, "denis@trusty.com", id.Name())
+	assert.Equal(t, "100", id.UserID())
+	claims, ok := id.UserInfo().(*jwtjwt.StandardClaims)
+	require.True(t, ok)
+	assert.Equal(t, "1001", claims.Id)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "AccessToken123"))
+	id, err = p.IdentityFromContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "100", id.UserID())
+
+	// revoked
+	delete(sessions, "1001")
+	_, err = p.IdentityFromRequest(r)
+	assert.EqualError(t, err, "session is revoked or expired")
+	_, err = p.IdentityFromContext(ctx)
+	assert.EqualError(t, err, "session is revoked or expired")
+}
+
+func TestDynamicRoles(t *testing.T) {
+	_, err := roles.New(&config.IdentityMap{
+		DynamicRoles: config.DynamicRolesMap{Enabled: true},
+	}, nil, nil, nil, nil)
+	assert.EqualError(t, err, "role resolver is not provided")
+
+	mock := mockJWT{
+		claims: &jwtjwt.StandardClaims{
+			Id:      "1001",
+			Subject: "denis@trusty.com",
+		},
+	}
+	sessions := mockSessions{
+		"1001": {userID: "100", groups: []string{"okta/devs", "okta/admins"}},
+	}
+	tokens := mockTokens{
+		"trusty_ci": "ekspand/ci",
+	}
+	resolver := mockResolver{
+		bindings: map[string][]string{
+			"user:denis@trusty.com":       {"trusty-admin"},
+			"group:okta/devs":             {"trusty-developer"},
+			"sa:ekspand/ci":               {"trusty-deploy"},
+			"spiffe:spifee://trusty/peer": {"trusty-peer"},
+			// the names of other types, or groups of other providers, are not matched
+			"denis@trusty.com":    {"trusty-other"},
+			"user:ekspand/ci":     {"trusty-other"},
+			"group:github/devs":   {"trusty-other"},
+			"cn:denis@trusty.com": {"trusty-other"},
+		},
+		orgRoles: map[string][]string{
+			"100": {"ekspand/admin", "other/member"},
+		},
+	}
+	cfg := &config.IdentityMap{
+		TLS: config.TLSIdentityMap{
+			Enabled:                  true,
+			DefaultAuthenticatedRole: "tls_authenticated",
+		},
+		JWT: config.JWTIdentityMap{
+			Enabled:                  true,
+			DefaultAuthenticatedRole: "jwt_authenticated",
+		},
+		APIToken: config.APITokenIdentityMap{
+			Enabled:                  true,
+			DefaultAuthenticatedRole: "api_token_authenticated",
+		},
+		DynamicRoles: config.DynamicRolesMap{
+			Enabled: true,
+			OrgRoles: map[string][]string{
+				"ekspand-admin": {"ekspand/admin"},
+				"org-member":    {"*/member"},
+			},
+			GroupRoles: map[string][]string{
+				"trusty-admin":   {"okta/admins"},
+				"trusty-auditor": {"okta/admins"},
+				"trusty-other":   {"admins", "github/admins"},
+			},
+		},
+	}
+
+	p, err := roles.New(cfg, mock, tokens, sessions, resolver)
+	require.NoError(t, err)
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	setAuthorizationHeader(r, "AccessToken123")
+
+	id, err := p.IdentityFromRequest(r)
+	require.NoError(t, err)
+	assert.Equal(t, "jwt_authenticated", id.Role())
+	assert.Equal(t, "100", id.UserID())
+	ri, ok := id.(roles.Identity)
+	require.True(t, ok)
+	assert.ElementsMatch(t, []string{
+		"jwt_authenticated",
+		"trusty-admin",
+		"trusty-auditor",
+		"trusty-developer",
+		"ekspand-admin",
+		"org-member",
+	}, ri.Roles())
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "trusty_ci"))
+	id, err = p.IdentityFromContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api_token_authenticated", "trusty-deploy"}, id.(roles.Identity).Roles())
+
+	u, _ := url.Parse("spifee://trusty/peer")
+	state := &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{URIs: []*url.URL{u}}},
+	}
+	r, _ = http.NewRequest(http.MethodGet, "/", nil)
+	r.TLS = state
+	id, err = p.IdentityFromRequest(r)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tls_authenticated", "trusty-peer"}, id.(roles.Identity).Roles())
+
+	t.Run("resolver failed", func(t *testing.T) {
+		p, err := roles.New(cfg, mock, tokens, sessions, mockResolver{err: errors.New("db is down")})
+		require.NoError(t, err)
+
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		setAuthorizationHeader(r, "AccessToken123")
+		id, err := p.IdentityFromRequest(r)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"jwt_authenticated", "trusty-admin", "trusty-auditor"},
+			id.(roles.Identity).Roles())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		cfg := *cfg
+		cfg.DynamicRoles.Enabled = false
+		p, err := roles.New(&cfg, mock, tokens, sessions, nil)
+		require.NoError(t, err)
+
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		setAuthorizationHeader(r, "AccessToken123")
+		id, err := p.IdentityFromRequest(r)
+		require.NoError(t, err)
+		_, ok := id.(roles.Identity)
+		assert.False(t, ok)
+	})
+}
+
+func TestNewIdentityWithRoles(t *testing.T) {
+	idn := roles.NewIdentityWithRoles(identity.NewIdentity("jwt_authenticated", "denis", "1"),
+		"trusty-admin", "", "jwt_authenticated", "trusty-admin")
+	assert.Equal(t, "jwt_authenticated", idn.Role())
+	assert.Equal(t, "denis", idn.Name())
+	assert.Equal(t, "1", idn.UserID())
+	assert.Equal(t, []string{"jwt_authenticated", "trusty-admin"}, idn.Roles())
+}
+
 func createPeerContext(ctx context.Context, TLS *tls.ConnectionState) context.Context {
 	creds := credentials.TLSInfo{
 		State: *TLS,
@@ -227,3 +512,50 @@ type mockJWT struct {
 func (m mockJWT) ParseToken(authorization, audience string) (*jwtjwt.StandardClaims, error) {
 	return m.claims, m.err
 }
+
+type mockTokens map[string]string
+
+func (m mockTokens) VerifyAPIToken(ctx context.Context, token string) (string, string, error) {
+	if name, ok := m[token]; ok {
+		return name, "1", nil
+	}
+	return "", "", errors.New("invalid API token")
+}
+
+type mockSession struct {
+	userID string
+	groups []string
+}
+
+type mockSessions map[string]mockSession
+
+func (m mockSessions) VerifySession(ctx context.Context, jti string) (string, []string, error) {
+	if s, ok := m[jti]; ok {
+		return s.userID, s.groups, nil
+	}
+	return "", nil, errors.New("session is revoked or expired")
+}
+
+type mockResolver struct {
+	bindings map[string][]string
+	orgRoles map[string][]string
+	err      error
+}
+
+func (m mockResolver) RoleBindings(ctx context.Context, subjects []string) ([]string, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var res []string
+	for _, subject := range subjects {
+		res = append(res, m.bindings[subject]...)
+	}
+	return res, nil
+}
+
+func (m mockResolver) OrgRoles(ctx context.Context, userID string) ([]string, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.orgRoles[userID], nil
+}
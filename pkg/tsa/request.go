@@ -0,0 +1,83 @@
+package tsa
+
+import (
+	"crypto"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+
+	"github.com/ekspand/trusty/pkg/cms"
+	"github.com/juju/errors"
+)
+
+// Request provides TimeStampReq
+type Request struct {
+	Version        int
+	MessageImprint MessageImprint
+	ReqPolicy      asn1.ObjectIdentifier `asn1:"optional"`
+	Nonce          *big.Int              `asn1:"optional"`
+	CertReq        bool                  `asn1:"optional,default:false"`
+	Extensions     []pkix.Extension      `asn1:"tag:0,optional"`
+}
+
+// NewRequest returns TimeStampReq for the digest
+func NewRequest(h crypto.Hash, digest []byte, nonce *big.Int, certReq bool) (*Request, error) {
+	oid, err := cms.DigestOID(h)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(digest) != h.Size() {
+		return nil, errors.Errorf("invalid digest size: %d", len(digest))
+	}
+	return &Request{
+		Version: 1,
+		MessageImprint: MessageImprint{
+			HashAlgorithm: pkix.AlgorithmIdentifier{
+				Algorithm:  oid,
+				Parameters: asn1.NullRawValue,
+			},
+			HashedMessage: digest,
+		},
+		Nonce:   nonce,
+		CertReq: certReq,
+	}, nil
+}
+
+// ParseRequest parses DER encoded TimeStampReq
+func ParseRequest(der []byte) (*Request, error) {
+	req := new(Request)
+	rest, err := asn1.Unmarshal(der, req)
+	if err != nil {
+		return nil, NewError(BadDataFormat, "failed to parse request: %s", err.Error())
+	}
+	if len(rest) > 0 {
+		return nil, NewError(BadDataFormat, "trailing data after request")
+	}
+	if req.Version != 1 {
+		return nil, NewError(BadRequest, "unsupported version: %d", req.Version)
+	}
+
+	h, err := req.MessageImprint.Hash()
+	if err != nil {
+		return nil, err
+	}
+	if len(req.MessageImprint.HashedMessage) != h.Size() {
+		return nil, NewError(BadDataFormat, "invalid message imprint size: %d", len(req.MessageImprint.HashedMessage))
+	}
+	for _, ext := range req.Extensions {
+		if ext.Critical {
+			return nil, NewError(UnacceptedExtension, "unsupported critical extension: %s", ext.Id.String())
+		}
+	}
+
+	return req, nil
+}
+
+// Marshal returns DER encoded TimeStampReq
+func (r *Request) Marshal() ([]byte, error) {
+	der, err := asn1.Marshal(*r)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return der, nil
+}
@@ -0,0 +1,108 @@
+package tsa
+
+import (
+	"encoding/asn1"
+
+	"github.com/go-phorce/dolly/xlog"
+	"github.com/juju/errors"
+)
+
+type pkiStatusInfo struct {
+	Status       int
+	StatusString []asn1.RawValue `asn1:"optional"`
+	FailInfo     asn1.BitString  `asn1:"optional"`
+}
+
+type response struct {
+	Status         pkiStatusInfo
+	TimeStampToken asn1.RawValue `asn1:"optional"`
+}
+
+// Response provides parsed TimeStampResp
+type Response struct {
+	Status       Status
+	StatusString []string
+	// FailInfo is set when the Status is StatusRejection
+	FailInfo FailureInfo
+	// Token is set when the Status is StatusGranted or StatusGrantedWithMods
+	Token *Token
+}
+
+// NewResponse returns DER encoded TimeStampResp with granted status
+func NewResponse(token []byte) ([]byte, error) {
+	der, err := asn1.Marshal(response{
+		Status:         pkiStatusInfo{Status: int(StatusGranted)},
+		TimeStampToken: asn1.RawValue{FullBytes: token},
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to encode response")
+	}
+	return der, nil
+}
+
+// FailureResponse returns DER encoded TimeStampResp with rejection status,
+// the failure info is taken from Error, or SystemFailure for other errors
+func FailureResponse(err error) []byte {
+	info := SystemFailure
+	msg := "internal error"
+	if e, ok := errors.Cause(err).(*Error); ok {
+		info = e.Info
+		msg = e.Message
+	}
+
+	fail := asn1.BitString{
+		Bytes:     make([]byte, int(info)/8+1),
+		BitLength: int(info) + 1,
+	}
+	fail.Bytes[int(info)/8] |= 0x80 >> (uint(info) % 8)
+
+	der, merr := asn1.Marshal(response{
+		Status: pkiStatusInfo{
+			Status: int(StatusRejection),
+			// PKIFreeText is SEQUENCE OF UTF8String
+			StatusString: []asn1.RawValue{
+				{Tag: asn1.TagUTF8String, Bytes: []byte(msg)},
+			},
+			FailInfo: fail,
+		},
+	})
+	if merr != nil {
+		// must not happen
+		logger.KV(xlog.ERROR, "reason", "marshal", "err", merr.Error())
+		return nil
+	}
+	return der
+}
+
+// ParseResponse parses DER encoded TimeStampResp
+func ParseResponse(der []byte) (*Response, error) {
+	var resp response
+	rest, err := asn1.Unmarshal(der, &resp)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to parse response")
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("trailing data after response")
+	}
+
+	res := &Response{
+		Status: Status(resp.Status.Status),
+	}
+	for _, s := range resp.Status.StatusString {
+		res.StatusString = append(res.StatusString, string(s.Bytes))
+	}
+	for i := 0; i < resp.Status.FailInfo.BitLength; i++ {
+		if resp.Status.FailInfo.At(i) != 0 {
+			res.FailInfo = FailureInfo(i)
+			break
+		}
+	}
+
+	if len(resp.TimeStampToken.FullBytes) > 0 {
+		res.Token, err = ParseToken(resp.TimeStampToken.FullBytes)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return res, nil
+}
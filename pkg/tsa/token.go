@@ -0,0 +1,145 @@
+package tsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+
+	"github.com/ekspand/trusty/pkg/cms"
+	"github.com/juju/errors"
+)
+
+type issuerSerial struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type essCertIDv2 struct {
+	HashAlgorithm pkix.AlgorithmIdentifier `asn1:"optional"`
+	CertHash      []byte
+	IssuerSerial  issuerSerial `asn1:"optional"`
+}
+
+type signingCertificateV2 struct {
+	Certs []essCertIDv2
+}
+
+// Token provides parsed TimeStampToken
+type Token struct {
+	// Raw contains DER encoded TimeStampToken
+	Raw []byte
+	// Info contains TSTInfo
+	Info *TSTInfo
+	// Certificates contains the certificates included in the token
+	Certificates []*x509.Certificate
+
+	sd *cms.SignedData
+}
+
+// SignToken returns DER encoded TimeStampToken.
+// The signing certificate is always referenced in the signed attributes,
+// the certs are included in the token, if provided.
+func SignToken(info *TSTInfo, signer crypto.Signer, cert *x509.Certificate, certs []*x509.Certificate) ([]byte, error) {
+	tstDER, err := asn1.Marshal(*info)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to encode TSTInfo")
+	}
+
+	signingCert, err := encodeSigningCert(cert)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	token, err := cms.Sign(oidTSTInfo, tstDER, signer, cert, certs, cms.Attribute{
+		Type:  oidAttrSigningCertV2,
+		Value: signingCert,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return token, nil
+}
+
+// ParseToken parses DER encoded TimeStampToken
+func ParseToken(der []byte) (*Token, error) {
+	sd, err := cms.Parse(der)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !sd.ContentType.Equal(oidTSTInfo) {
+		return nil, errors.Errorf("unsupported content type: %s", sd.ContentType.String())
+	}
+
+	info := new(TSTInfo)
+	_, err = asn1.Unmarshal(sd.Content, info)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to parse TSTInfo")
+	}
+
+	return &Token{
+		Raw:          der,
+		Info:         info,
+		Certificates: sd.Certificates,
+		sd:           sd,
+	}, nil
+}
+
+// Verify verifies the signature of the token with the TSA certificate,
+// and that the token was issued for the digest
+func (t *Token) Verify(cert *x509.Certificate, digest []byte) error {
+	if !bytes.Equal(t.Info.MessageImprint.HashedMessage, digest) {
+		return errors.New("message imprint does not match")
+	}
+
+	var sc signingCertificateV2
+	if _, err := asn1.Unmarshal(t.sd.Attribute(oidAttrSigningCertV2), &sc); err != nil || len(sc.Certs) == 0 {
+		return errors.New("invalid signing certificate attribute")
+	}
+	certHash := sha256.Sum256(cert.Raw)
+	if !bytes.Equal(sc.Certs[0].CertHash, certHash[:]) {
+		return errors.New("signing certificate does not match")
+	}
+
+	err := t.sd.Verify(cert, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// encodeSigningCert returns DER encoded SigningCertificateV2 attribute value
+func encodeSigningCert(cert *x509.Certificate) ([]byte, error) {
+	// IssuerSerial.issuer is GeneralNames with directoryName
+	issuerNames, err := asn1.Marshal([]asn1.RawValue{
+		{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        4,
+			IsCompound: true,
+			Bytes:      cert.RawIssuer,
+		},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	certHash := sha256.Sum256(cert.Raw)
+	der, err := asn1.Marshal(signingCertificateV2{
+		Certs: []essCertIDv2{
+			{
+				CertHash: certHash[:],
+				IssuerSerial: issuerSerial{
+					Issuer:       asn1.RawValue{FullBytes: issuerNames},
+					SerialNumber: cert.SerialNumber,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return der, nil
+}
@@ -0,0 +1,136 @@
+// Package tsa provides RFC 3161 Time-Stamp Protocol messages:
+// TimeStampReq, TimeStampResp and TimeStampToken,
+// that is CMS SignedData with TSTInfo content.
+package tsa
+
+import (
+	"crypto"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/cms"
+	"github.com/go-phorce/dolly/xlog"
+)
+
+var logger = xlog.NewPackageLogger("github.com/ekspand/trusty/pkg", "tsa")
+
+const (
+	// ContentTypeQuery specifies Content-Type of TimeStampReq
+	ContentTypeQuery = "application/timestamp-query"
+	// ContentTypeReply specifies Content-Type of TimeStampResp
+	ContentTypeReply = "application/timestamp-reply"
+)
+
+var (
+	oidTSTInfo           = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+	oidAttrSigningCertV2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 47}
+)
+
+// Status specifies PKIStatus
+type Status int
+
+const (
+	// StatusGranted specifies that the TimeStampToken is present
+	StatusGranted Status = 0
+	// StatusGrantedWithMods specifies that the TimeStampToken is present with modifications
+	StatusGrantedWithMods Status = 1
+	// StatusRejection specifies that the request is rejected
+	StatusRejection Status = 2
+)
+
+// FailureInfo specifies PKIFailureInfo bit
+type FailureInfo int
+
+const (
+	// BadAlg specifies unrecognized or unsupported Algorithm Identifier
+	BadAlg FailureInfo = 0
+	// BadRequest specifies that the transaction is not permitted or supported
+	BadRequest FailureInfo = 2
+	// BadDataFormat specifies that the data submitted has the wrong format
+	BadDataFormat FailureInfo = 5
+	// TimeNotAvailable specifies that the TSA's time source is not available
+	TimeNotAvailable FailureInfo = 14
+	// UnacceptedPolicy specifies that the requested TSA policy is not supported
+	UnacceptedPolicy FailureInfo = 15
+	// UnacceptedExtension specifies that the requested extension is not supported
+	UnacceptedExtension FailureInfo = 16
+	// AddInfoNotAvailable specifies that the additional information requested
+	// could not be understood or is not available
+	AddInfoNotAvailable FailureInfo = 17
+	// SystemFailure specifies that the request cannot be handled due to system failure
+	SystemFailure FailureInfo = 25
+)
+
+// Error specifies the failure to be returned in TimeStampResp
+type Error struct {
+	Info    FailureInfo
+	Message string
+}
+
+// Error returns the error message
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// NewError returns Error
+func NewError(info FailureInfo, msgFormat string, vals ...interface{}) *Error {
+	return &Error{
+		Info:    info,
+		Message: fmt.Sprintf(msgFormat, vals...),
+	}
+}
+
+// MessageImprint contains the hash of the datum to be time-stamped
+type MessageImprint struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	HashedMessage []byte
+}
+
+// Hash returns the hash algorithm of the message imprint
+func (m *MessageImprint) Hash() (crypto.Hash, error) {
+	h, err := cms.DigestByOID(m.HashAlgorithm.Algorithm)
+	if err != nil {
+		return 0, NewError(BadAlg, err.Error())
+	}
+	return h, nil
+}
+
+// Accuracy of the time deviation around the genTime
+type Accuracy struct {
+	Seconds int `asn1:"optional"`
+	Millis  int `asn1:"tag:0,optional"`
+	Micros  int `asn1:"tag:1,optional"`
+}
+
+// NewAccuracy returns Accuracy for the duration
+func NewAccuracy(d time.Duration) Accuracy {
+	return Accuracy{
+		Seconds: int(d / time.Second),
+		Millis:  int(d % time.Second / time.Millisecond),
+		Micros:  int(d % time.Millisecond / time.Microsecond),
+	}
+}
+
+// Duration returns the accuracy as duration
+func (a Accuracy) Duration() time.Duration {
+	return time.Duration(a.Seconds)*time.Second +
+		time.Duration(a.Millis)*time.Millisecond +
+		time.Duration(a.Micros)*time.Microsecond
+}
+
+// TSTInfo provides the time-stamp token info
+type TSTInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint MessageImprint
+	SerialNumber   *big.Int
+	GenTime        time.Time        `asn1:"generalized"`
+	Accuracy       Accuracy         `asn1:"optional"`
+	Ordering       bool             `asn1:"optional,default:false"`
+	Nonce          *big.Int         `asn1:"optional"`
+	TSA            asn1.RawValue    `asn1:"tag:0,optional"`
+	Extensions     []pkix.Extension `asn1:"tag:1,optional"`
+}
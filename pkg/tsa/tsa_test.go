@@ -0,0 +1,184 @@
+package tsa_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/tsa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPolicy = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 59765, 1, 1}
+
+func createTSACert(t *testing.T, signer crypto.Signer) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1234),
+		Subject:               pkix.Name{CommonName: "[TEST] Trusty TSA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, signer.Public(), signer)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestRequest(t *testing.T) {
+	digest := sha256.Sum256([]byte("trusty"))
+
+	_, err := tsa.NewRequest(crypto.MD5, digest[:], nil, false)
+	assert.Error(t, err)
+	_, err = tsa.NewRequest(crypto.SHA384, digest[:], nil, false)
+	assert.EqualError(t, err, "invalid digest size: 32")
+
+	req, err := tsa.NewRequest(crypto.SHA256, digest[:], big.NewInt(42), true)
+	require.NoError(t, err)
+	req.ReqPolicy = testPolicy
+
+	der, err := req.Marshal()
+	require.NoError(t, err)
+
+	parsed, err := tsa.ParseRequest(der)
+	require.NoError(t, err)
+	assert.Equal(t, 1, parsed.Version)
+	assert.Equal(t, digest[:], parsed.MessageImprint.HashedMessage)
+	assert.Equal(t, testPolicy, parsed.ReqPolicy)
+	assert.Equal(t, int64(42), parsed.Nonce.Int64())
+	assert.True(t, parsed.CertReq)
+
+	h, err := parsed.MessageImprint.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, crypto.SHA256, h)
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := tsa.ParseRequest([]byte("invalid"))
+		require.Error(t, err)
+		assert.Equal(t, tsa.BadDataFormat, err.(*tsa.Error).Info)
+
+		r := *req
+		r.Version = 2
+		der, err := r.Marshal()
+		require.NoError(t, err)
+		_, err = tsa.ParseRequest(der)
+		require.Error(t, err)
+		assert.Equal(t, tsa.BadRequest, err.(*tsa.Error).Info)
+
+		r = *req
+		r.MessageImprint.HashAlgorithm.Algorithm = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 5}
+		der, err = r.Marshal()
+		require.NoError(t, err)
+		_, err = tsa.ParseRequest(der)
+		require.Error(t, err)
+		assert.Equal(t, tsa.BadAlg, err.(*tsa.Error).Info)
+
+		r = *req
+		r.Extensions = []pkix.Extension{{Id: asn1.ObjectIdentifier{1, 2, 3}, Critical: true, Value: []byte{5, 0}}}
+		der, err = r.Marshal()
+		require.NoError(t, err)
+		_, err = tsa.ParseRequest(der)
+		require.Error(t, err)
+		assert.Equal(t, tsa.UnacceptedExtension, err.(*tsa.Error).Info)
+	})
+}
+
+func TestAccuracy(t *testing.T) {
+	d := 1*time.Second + 250*time.Millisecond + 10*time.Microsecond
+	a := tsa.NewAccuracy(d)
+	assert.Equal(t, tsa.Accuracy{Seconds: 1, Millis: 250, Micros: 10}, a)
+	assert.Equal(t, d, a.Duration())
+}
+
+func TestToken(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("trusty"))
+	req, err := tsa.NewRequest(crypto.SHA256, digest[:], big.NewInt(42), true)
+	require.NoError(t, err)
+
+	for name, signer := range map[string]crypto.Signer{
+		"ECDSA":   ecKey,
+		"RSA":     rsaKey,
+		"Ed25519": edKey,
+	} {
+		t.Run(name, func(t *testing.T) {
+			cert := createTSACert(t, signer)
+			info := &tsa.TSTInfo{
+				Version:        1,
+				Policy:         testPolicy,
+				MessageImprint: req.MessageImprint,
+				SerialNumber:   big.NewInt(1),
+				GenTime:        time.Now().UTC().Truncate(time.Second),
+				Accuracy:       tsa.NewAccuracy(time.Second),
+				Nonce:          req.Nonce,
+			}
+
+			der, err := tsa.SignToken(info, signer, cert, []*x509.Certificate{cert})
+			require.NoError(t, err)
+
+			resp, err := tsa.NewResponse(der)
+			require.NoError(t, err)
+
+			parsed, err := tsa.ParseResponse(resp)
+			require.NoError(t, err)
+			assert.Equal(t, tsa.StatusGranted, parsed.Status)
+			require.NotNil(t, parsed.Token)
+
+			token := parsed.Token
+			assert.Equal(t, der, token.Raw)
+			assert.Equal(t, testPolicy, token.Info.Policy)
+			assert.Equal(t, info.GenTime, token.Info.GenTime)
+			assert.Equal(t, time.Second, token.Info.Accuracy.Duration())
+			assert.Equal(t, int64(42), token.Info.Nonce.Int64())
+			require.Len(t, token.Certificates, 1)
+			assert.Equal(t, cert.Raw, token.Certificates[0].Raw)
+
+			require.NoError(t, token.Verify(cert, digest[:]))
+
+			wrong := sha256.Sum256([]byte("wrong"))
+			assert.EqualError(t, token.Verify(cert, wrong[:]), "message imprint does not match")
+
+			other := createTSACert(t, ecKey)
+			if name != "ECDSA" {
+				assert.Error(t, token.Verify(other, digest[:]))
+			}
+		})
+	}
+}
+
+func TestFailureResponse(t *testing.T) {
+	der := tsa.FailureResponse(tsa.NewError(tsa.UnacceptedPolicy, "unsupported policy: %s", "1.2.3"))
+	resp, err := tsa.ParseResponse(der)
+	require.NoError(t, err)
+	assert.Equal(t, tsa.StatusRejection, resp.Status)
+	assert.Equal(t, tsa.UnacceptedPolicy, resp.FailInfo)
+	assert.Equal(t, []string{"unsupported policy: 1.2.3"}, resp.StatusString)
+	assert.Nil(t, resp.Token)
+
+	der = tsa.FailureResponse(assert.AnError)
+	resp, err = tsa.ParseResponse(der)
+	require.NoError(t, err)
+	assert.Equal(t, tsa.StatusRejection, resp.Status)
+	assert.Equal(t, tsa.SystemFailure, resp.FailInfo)
+	assert.Equal(t, []string{"internal error"}, resp.StatusString)
+}
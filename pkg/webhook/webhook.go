@@ -0,0 +1,249 @@
+// Package webhook provides a client for external pre-issuance validation.
+//
+// Before signing, the CA sends the details of the certificate request
+// to the webhook, which can approve or deny the request,
+// and optionally narrow the requested SANs.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/go-phorce/dolly/rest/tlsconfig"
+	"github.com/go-phorce/dolly/xhttp/header"
+	"github.com/juju/errors"
+)
+
+// DefaultTimeout specifies the default timeout of the webhook call
+const DefaultTimeout = 5 * time.Second
+
+// maxResponseSize specifies the limit of the webhook response
+const maxResponseSize = 64 * 1024
+
+// Config specifies the webhook
+type Config struct {
+	// URL specifies the endpoint of the webhook
+	URL string `json:"url" yaml:"url"`
+	// Timeout specifies the timeout of the call, the default is 5s
+	Timeout csr.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// FailOpen specifies to allow the issuance,
+	// if the webhook is not available or returns an invalid response
+	FailOpen bool `json:"fail_open,omitempty" yaml:"fail_open,omitempty"`
+	// CertFile specifies the client certificate for mTLS
+	CertFile string `json:"cert,omitempty" yaml:"cert,omitempty"`
+	// KeyFile specifies the client key for mTLS
+	KeyFile string `json:"key,omitempty" yaml:"key,omitempty"`
+	// TrustedCAFile specifies the trusted roots to verify the webhook server
+	TrustedCAFile string `json:"trusted_ca,omitempty" yaml:"trusted_ca,omitempty"`
+}
+
+// Validate returns an error if the configuration is invalid
+func (c *Config) Validate() error {
+	if c.URL == "" {
+		return errors.New("missing url")
+	}
+	if !strings.HasPrefix(c.URL, "https://") && !strings.HasPrefix(c.URL, "http://") {
+		return errors.Errorf("unsupported url: %s", c.URL)
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("both cert and key must be specified")
+	}
+	return nil
+}
+
+// GetTimeout returns the timeout of the call
+func (c *Config) GetTimeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout.TimeDuration()
+	}
+	return DefaultTimeout
+}
+
+// Caller provides identity of the caller
+type Caller struct {
+	ID    string   `json:"id,omitempty"`
+	Name  string   `json:"name,omitempty"`
+	Role  string   `json:"role,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+	Orgs  []string `json:"orgs,omitempty"`
+}
+
+// CSR provides the details of the certificate request
+type CSR struct {
+	Subject        string   `json:"subject"`
+	CommonName     string   `json:"common_name,omitempty"`
+	DNSNames       []string `json:"dns_names,omitempty"`
+	EmailAddresses []string `json:"email_addresses,omitempty"`
+	IPAddresses    []string `json:"ip_addresses,omitempty"`
+	URIs           []string `json:"uris,omitempty"`
+	KeyAlgorithm   string   `json:"key_algorithm"`
+	KeySize        int      `json:"key_size"`
+	PEM            string   `json:"pem"`
+}
+
+// Request is sent to the webhook
+type Request struct {
+	// RequestID specifies the correlation ID of the request
+	RequestID string `json:"request_id,omitempty"`
+	Profile   string `json:"profile"`
+	Issuer    string `json:"issuer"`
+	OrgID     uint64 `json:"org_id,omitempty"`
+	Caller    Caller `json:"caller"`
+	CSR       CSR    `json:"csr"`
+	// SAN provides the requested SANs
+	SAN []string `json:"san,omitempty"`
+}
+
+// Response is returned by the webhook
+type Response struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+	// SAN optionally replaces the requested SANs,
+	// the list must be a subset of the requested SANs,
+	// or of the CSR's SANs if SANs were not requested
+	SAN []string `json:"san,omitempty"`
+}
+
+// NewCSR returns CSR details for the webhook request
+func NewCSR(csrv *x509.CertificateRequest, pem string) CSR {
+	c := CSR{
+		Subject:        csrv.Subject.String(),
+		CommonName:     csrv.Subject.CommonName,
+		DNSNames:       csrv.DNSNames,
+		EmailAddresses: csrv.EmailAddresses,
+		PEM:            pem,
+	}
+	for _, ip := range csrv.IPAddresses {
+		c.IPAddresses = append(c.IPAddresses, ip.String())
+	}
+	for _, u := range csrv.URIs {
+		c.URIs = append(c.URIs, u.String())
+	}
+	switch key := csrv.PublicKey.(type) {
+	case *rsa.PublicKey:
+		c.KeyAlgorithm = "RSA"
+		c.KeySize = key.N.BitLen()
+	case *ecdsa.PublicKey:
+		c.KeyAlgorithm = "ECDSA"
+		c.KeySize = key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		c.KeyAlgorithm = "Ed25519"
+		c.KeySize = 256
+	}
+	return c
+}
+
+// SANs returns all SANs of the CSR
+func (c *CSR) SANs() []string {
+	var list []string
+	list = append(list, c.DNSNames...)
+	list = append(list, c.IPAddresses...)
+	list = append(list, c.EmailAddresses...)
+	list = append(list, c.URIs...)
+	return list
+}
+
+// Client provides the webhook client
+type Client struct {
+	cfg  Config
+	http *http.Client
+}
+
+// New returns webhook Client
+func New(cfg *Config) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Annotate(err, "invalid webhook configuration")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if strings.HasPrefix(cfg.URL, "https://") && (cfg.CertFile != "" || cfg.TrustedCAFile != "") {
+		tlscfg, err := tlsconfig.NewClientTLSFromFiles(cfg.CertFile, cfg.KeyFile, cfg.TrustedCAFile)
+		if err != nil {
+			return nil, errors.Annotate(err, "unable to build TLS configuration")
+		}
+		transport.TLSClientConfig = tlscfg
+	}
+
+	return &Client{
+		cfg: *cfg,
+		http: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.GetTimeout(),
+		},
+	}, nil
+}
+
+// Config returns the configuration of the client
+func (c *Client) Config() *Config {
+	return &c.cfg
+}
+
+// Call sends the request to the webhook, and returns the validated response
+func (c *Client) Call(ctx context.Context, req *Request) (*Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.GetTimeout())
+	defer cancel()
+
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	hreq.Header.Set(header.ContentType, header.ApplicationJSON)
+	hreq.Header.Set(header.Accept, header.ApplicationJSON)
+
+	hres, err := c.http.Do(hreq)
+	if err != nil {
+		return nil, errors.Annotate(err, "webhook call failed")
+	}
+	defer hres.Body.Close()
+
+	rbody, err := ioutil.ReadAll(io.LimitReader(hres.Body, maxResponseSize))
+	if err != nil {
+		return nil, errors.Annotate(err, "unable to read webhook response")
+	}
+	if hres.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("webhook returned status %d", hres.StatusCode)
+	}
+
+	res := new(Response)
+	if err = json.Unmarshal(rbody, res); err != nil {
+		return nil, errors.Annotate(err, "invalid webhook response")
+	}
+
+	if len(res.SAN) > 0 {
+		allowed := req.SAN
+		if len(allowed) == 0 {
+			allowed = req.CSR.SANs()
+		}
+		for _, san := range res.SAN {
+			if !containsFold(allowed, san) {
+				return nil, errors.Errorf("webhook returned SAN that was not requested: %s", san)
+			}
+		}
+	}
+	return res, nil
+}
+
+func containsFold(list []string, val string) bool {
+	for _, s := range list {
+		if strings.EqualFold(s, val) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package webhook
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ekspand/trusty/pkg/csr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	tcases := []struct {
+		cfg Config
+		err string
+	}{
+		{Config{}, "missing url"},
+		{Config{URL: "ftp://localhost"}, "unsupported url: ftp://localhost"},
+		{Config{URL: "https://localhost", CertFile: "cert.pem"}, "both cert and key must be specified"},
+		{Config{URL: "https://localhost", CertFile: "cert.pem", KeyFile: "key.pem"}, ""},
+	}
+	for _, tc := range tcases {
+		err := tc.cfg.Validate()
+		if tc.err == "" {
+			assert.NoError(t, err)
+		} else {
+			require.Error(t, err)
+			assert.Equal(t, tc.err, err.Error())
+		}
+	}
+
+	cfg := &Config{}
+	assert.Equal(t, DefaultTimeout, cfg.GetTimeout())
+	cfg.Timeout = csr.Duration(time.Second)
+	assert.Equal(t, time.Second, cfg.GetTimeout())
+
+	_, err := New(&Config{})
+	require.Error(t, err)
+	assert.Equal(t, "invalid webhook configuration: missing url", err.Error())
+}
+
+func TestCall(t *testing.T) {
+	var received *Request
+	var res interface{}
+	status := http.StatusOK
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = new(Request)
+		_ = json.NewDecoder(r.Body).Decode(received)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+
+	client, err := New(&Config{URL: srv.URL})
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL, client.Config().URL)
+
+	req := &Request{
+		RequestID: "123",
+		Profile:   "server",
+		Issuer:    "trusty.svc",
+		OrgID:     1,
+		Caller:    Caller{Name: "denis", Roles: []string{"trusty-client"}},
+		CSR:       NewCSR(testCSR(t), "pem"),
+	}
+
+	t.Run("approve", func(t *testing.T) {
+		res = &Response{Allowed: true}
+		r, err := client.Call(context.Background(), req)
+		require.NoError(t, err)
+		assert.True(t, r.Allowed)
+		assert.Empty(t, r.SAN)
+
+		require.NotNil(t, received)
+		assert.Equal(t, "123", received.RequestID)
+		assert.Equal(t, "server", received.Profile)
+		assert.Equal(t, "denis", received.Caller.Name)
+		assert.Equal(t, "api.trusty.local", received.CSR.CommonName)
+		assert.Equal(t, "ECDSA", received.CSR.KeyAlgorithm)
+		assert.Equal(t, 256, received.CSR.KeySize)
+		assert.Equal(t, []string{"10.0.0.1"}, received.CSR.IPAddresses)
+	})
+
+	t.Run("deny", func(t *testing.T) {
+		res = &Response{Allowed: false, Reason: "not in inventory"}
+		r, err := client.Call(context.Background(), req)
+		require.NoError(t, err)
+		assert.False(t, r.Allowed)
+		assert.Equal(t, "not in inventory", r.Reason)
+	})
+
+	t.Run("modify", func(t *testing.T) {
+		res = &Response{Allowed: true, SAN: []string{"API.trusty.local"}}
+		r, err := client.Call(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"API.trusty.local"}, r.SAN)
+
+		res = &Response{Allowed: true, SAN: []string{"www.trusty.local"}}
+		_, err = client.Call(context.Background(), req)
+		require.Error(t, err)
+		assert.Equal(t, "webhook returned SAN that was not requested: www.trusty.local", err.Error())
+
+		// the requested SAN overrides CSR
+		req2 := *req
+		req2.SAN = []string{"www.trusty.local"}
+		r, err = client.Call(context.Background(), &req2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"www.trusty.local"}, r.SAN)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		res = "allowed"
+		_, err := client.Call(context.Background(), req)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid webhook response")
+
+		status = http.StatusInternalServerError
+		defer func() { status = http.StatusOK }()
+		res = &Response{Allowed: true}
+		_, err = client.Call(context.Background(), req)
+		require.Error(t, err)
+		assert.Equal(t, "webhook returned status 500", err.Error())
+	})
+}
+
+func TestCallTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	client, err := New(&Config{URL: srv.URL, Timeout: csr.Duration(100 * time.Millisecond)})
+	require.NoError(t, err)
+
+	started := time.Now()
+	_, err = client.Call(context.Background(), &Request{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "webhook call failed")
+	assert.True(t, time.Since(started) < 5*time.Second)
+}
+
+func TestCallTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(&Response{Allowed: true})
+	}))
+	defer srv.Close()
+
+	// not trusted
+	client, err := New(&Config{URL: srv.URL})
+	require.NoError(t, err)
+	_, err = client.Call(context.Background(), &Request{})
+	require.Error(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "webhook_ca.pem")
+	err = ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	}), 0644)
+	require.NoError(t, err)
+
+	client, err = New(&Config{URL: srv.URL, TrustedCAFile: caFile})
+	require.NoError(t, err)
+	r, err := client.Call(context.Background(), &Request{})
+	require.NoError(t, err)
+	assert.True(t, r.Allowed)
+
+	_, err = New(&Config{URL: srv.URL, TrustedCAFile: caFile + ".missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to build TLS configuration")
+}
+
+func testCSR(t *testing.T) *x509.CertificateRequest {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:     pkix.Name{CommonName: "api.trusty.local"},
+		DNSNames:    []string{"api.trusty.local"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+	}, key)
+	require.NoError(t, err)
+	csrv, err := x509.ParseCertificateRequest(der)
+	require.NoError(t, err)
+	return csrv
+}
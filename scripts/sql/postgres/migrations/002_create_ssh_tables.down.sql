@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.sshcerts;
+DROP INDEX IF EXISTS unique_sshcerts_sha256;
+DROP INDEX IF EXISTS idx_sshcerts_org;
+DROP INDEX IF EXISTS idx_sshcerts_ikid;
+DROP INDEX IF EXISTS idx_sshcerts_notafter;
+DROP INDEX IF EXISTS idx_sshcerts_revoked_at;
+DROP INDEX IF EXISTS idx_sshcerts_sha256;
+
+COMMIT;
@@ -0,0 +1,56 @@
+BEGIN;
+
+--
+-- SSH Certificates
+--
+CREATE TABLE IF NOT EXISTS public.sshcerts
+(
+    id bigint NOT NULL,
+    org_id bigint NOT NULL,
+    ikid character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    serial_number character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    key_id character varying(260) COLLATE pg_catalog."default" NOT NULL,
+    cert_type character varying(16) COLLATE pg_catalog."default" NOT NULL,
+    principals text COLLATE pg_catalog."default" NULL,
+    not_before timestamp with time zone,
+    no_tafter timestamp with time zone,
+    sha256 character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    pem text COLLATE pg_catalog."default" NOT NULL,
+    profile character varying(32) COLLATE pg_catalog."default" NULL,
+    revoked_at timestamp with time zone NULL,
+    reason int NULL,
+    CONSTRAINT sshcerts_pkey PRIMARY KEY (id),
+    CONSTRAINT sshcerts_sha256 UNIQUE (sha256),
+    CONSTRAINT sshcerts_issuer_sn UNIQUE (ikid, serial_number)
+)
+WITH (
+    OIDS = FALSE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sshcerts_sha256
+    ON public.sshcerts USING btree
+    (sha256 COLLATE pg_catalog."default");
+
+CREATE INDEX IF NOT EXISTS idx_sshcerts_org
+    ON public.sshcerts USING btree
+    (org_id);
+
+CREATE INDEX IF NOT EXISTS idx_sshcerts_ikid
+    ON public.sshcerts USING btree
+    (ikid COLLATE pg_catalog."default");
+
+CREATE INDEX IF NOT EXISTS idx_sshcerts_notafter
+    ON public.sshcerts USING btree
+    (no_tafter);
+
+CREATE INDEX IF NOT EXISTS idx_sshcerts_revoked_at
+    ON public.sshcerts USING btree
+    (revoked_at);
+
+SELECT create_constraint_if_not_exists(
+    'public',
+    'sshcerts',
+    'unique_sshcerts_sha256',
+    'ALTER TABLE public.sshcerts ADD CONSTRAINT unique_sshcerts_sha256 UNIQUE USING INDEX idx_sshcerts_sha256;');
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.api_tokens;
+DROP INDEX IF EXISTS unique_api_tokens_hash;
+DROP INDEX IF EXISTS idx_api_tokens_hash;
+DROP INDEX IF EXISTS idx_api_tokens_sa;
+
+DROP TABLE IF EXISTS public.service_accounts;
+DROP INDEX IF EXISTS idx_service_accounts_org;
+
+COMMIT;
@@ -0,0 +1,60 @@
+BEGIN;
+
+--
+-- Service Accounts
+--
+CREATE TABLE IF NOT EXISTS public.service_accounts
+(
+    id bigint NOT NULL,
+    org_id bigint NOT NULL REFERENCES public.orgs ON DELETE RESTRICT,
+    name character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    description character varying(256) COLLATE pg_catalog."default" NULL,
+    created_by bigint NOT NULL,
+    created_at timestamp with time zone,
+    CONSTRAINT service_accounts_pkey PRIMARY KEY (id),
+    CONSTRAINT service_accounts_org_name UNIQUE (org_id, name)
+)
+WITH (
+    OIDS = FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_accounts_org
+    ON public.service_accounts USING btree
+    (org_id);
+
+--
+-- API Tokens
+--
+CREATE TABLE IF NOT EXISTS public.api_tokens
+(
+    id bigint NOT NULL,
+    service_account_id bigint NOT NULL REFERENCES public.service_accounts ON DELETE CASCADE,
+    name character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    token_hash character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    created_by bigint NOT NULL,
+    created_at timestamp with time zone,
+    expires_at timestamp with time zone NULL,
+    last_used_at timestamp with time zone NULL,
+    revoked_at timestamp with time zone NULL,
+    CONSTRAINT api_tokens_pkey PRIMARY KEY (id),
+    CONSTRAINT api_tokens_sa_name UNIQUE (service_account_id, name)
+)
+WITH (
+    OIDS = FALSE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_tokens_hash
+    ON public.api_tokens USING btree
+    (token_hash COLLATE pg_catalog."default");
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_sa
+    ON public.api_tokens USING btree
+    (service_account_id);
+
+SELECT create_constraint_if_not_exists(
+    'public',
+    'api_tokens',
+    'unique_api_tokens_hash',
+    'ALTER TABLE public.api_tokens ADD CONSTRAINT unique_api_tokens_hash UNIQUE USING INDEX idx_api_tokens_hash;');
+
+COMMIT;
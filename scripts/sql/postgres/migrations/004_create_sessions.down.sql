@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.sessions;
+DROP INDEX IF EXISTS idx_sessions_user_id;
+DROP INDEX IF EXISTS idx_sessions_expires_at;
+
+COMMIT;
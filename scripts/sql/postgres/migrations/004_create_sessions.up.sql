@@ -0,0 +1,29 @@
+BEGIN;
+
+--
+-- Sessions of issued JWT
+--
+CREATE TABLE IF NOT EXISTS public.sessions
+(
+    id bigint NOT NULL,
+    user_id bigint NOT NULL REFERENCES public.users ON DELETE CASCADE,
+    device_id character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    issued_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    last_seen_at timestamp with time zone NULL,
+    revoked_at timestamp with time zone NULL,
+    CONSTRAINT sessions_pkey PRIMARY KEY (id)
+)
+WITH (
+    OIDS = FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id
+    ON public.sessions USING btree
+    (user_id);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at
+    ON public.sessions USING btree
+    (expires_at);
+
+COMMIT;
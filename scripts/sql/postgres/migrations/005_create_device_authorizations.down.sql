@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.device_authorizations;
+DROP INDEX IF EXISTS idx_device_authorizations_expires_at;
+
+COMMIT;
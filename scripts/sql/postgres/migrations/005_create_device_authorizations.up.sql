@@ -0,0 +1,31 @@
+BEGIN;
+
+--
+-- Pending device authorizations, RFC 8628
+--
+CREATE TABLE IF NOT EXISTS public.device_authorizations
+(
+    id bigint NOT NULL,
+    device_code character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    user_code character varying(16) COLLATE pg_catalog."default" NOT NULL,
+    provider character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    device_id character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    user_id bigint NULL REFERENCES public.users ON DELETE CASCADE,
+    session_id bigint NULL REFERENCES public.sessions ON DELETE CASCADE,
+    approved_at timestamp with time zone NULL,
+    polled_at timestamp with time zone NULL,
+    CONSTRAINT device_authorizations_pkey PRIMARY KEY (id),
+    CONSTRAINT device_authorizations_device_code UNIQUE (device_code),
+    CONSTRAINT device_authorizations_user_code UNIQUE (user_code)
+)
+WITH (
+    OIDS = FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_authorizations_expires_at
+    ON public.device_authorizations USING btree
+    (expires_at);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE public.sessions
+    DROP COLUMN IF EXISTS groups;
+
+DROP TABLE IF EXISTS public.role_bindings;
+DROP INDEX IF EXISTS idx_role_bindings_subject;
+
+COMMIT;
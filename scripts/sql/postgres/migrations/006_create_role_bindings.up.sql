@@ -0,0 +1,30 @@
+BEGIN;
+
+--
+-- Roles bound to identities, or to groups of the identity provider
+--
+CREATE TABLE IF NOT EXISTS public.role_bindings
+(
+    id bigint NOT NULL,
+    role character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    subject character varying(256) COLLATE pg_catalog."default" NOT NULL,
+    created_by bigint NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    CONSTRAINT role_bindings_pkey PRIMARY KEY (id),
+    CONSTRAINT role_bindings_role_subject UNIQUE (role, subject)
+)
+WITH (
+    OIDS = FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_role_bindings_subject
+    ON public.role_bindings USING btree
+    (subject);
+
+--
+-- Groups of the identity provider, asserted at login
+--
+ALTER TABLE public.sessions
+    ADD COLUMN IF NOT EXISTS groups text[] NULL;
+
+COMMIT;
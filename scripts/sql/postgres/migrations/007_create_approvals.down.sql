@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.approval_votes;
+DROP TABLE IF EXISTS public.approvals;
+DROP INDEX IF EXISTS idx_approvals_status;
+
+COMMIT;
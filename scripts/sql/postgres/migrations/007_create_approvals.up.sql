@@ -0,0 +1,48 @@
+BEGIN;
+
+--
+-- Certificate requests waiting for manual approval
+--
+CREATE TABLE IF NOT EXISTS public.approvals
+(
+    id bigint NOT NULL,
+    org_id bigint NULL,
+    profile character varying(32) COLLATE pg_catalog."default" NOT NULL,
+    issuer_label character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    subject character varying(256) COLLATE pg_catalog."default" NOT NULL,
+    san text[] NULL,
+    request text COLLATE pg_catalog."default" NOT NULL,
+    requester character varying(160) COLLATE pg_catalog."default" NOT NULL,
+    status character varying(16) COLLATE pg_catalog."default" NOT NULL,
+    required_approvals integer NOT NULL,
+    approver_roles text[] NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    certificate_id bigint NULL,
+    CONSTRAINT approvals_pkey PRIMARY KEY (id)
+)
+WITH (
+    OIDS = FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_approvals_status
+    ON public.approvals USING btree
+    (status);
+
+--
+-- Decisions of approvers
+--
+CREATE TABLE IF NOT EXISTS public.approval_votes
+(
+    approval_id bigint NOT NULL REFERENCES public.approvals ON DELETE CASCADE,
+    approver character varying(160) COLLATE pg_catalog."default" NOT NULL,
+    approved boolean NOT NULL,
+    reason character varying(256) COLLATE pg_catalog."default" NULL,
+    created_at timestamp with time zone NOT NULL,
+    CONSTRAINT approval_votes_pkey PRIMARY KEY (approval_id, approver)
+)
+WITH (
+    OIDS = FALSE
+);
+
+COMMIT;
@@ -0,0 +1,26 @@
+BEGIN;
+
+ALTER TABLE public.approvals
+    DROP COLUMN IF EXISTS client_ip,
+    DROP COLUMN IF EXISTS request_id,
+    DROP COLUMN IF EXISTS labels;
+
+DROP INDEX IF EXISTS idx_certificates_requester;
+
+ALTER TABLE public.certificates
+    DROP COLUMN IF EXISTS requester,
+    DROP COLUMN IF EXISTS client_ip,
+    DROP COLUMN IF EXISTS request_id,
+    DROP COLUMN IF EXISTS request,
+    DROP COLUMN IF EXISTS san,
+    DROP COLUMN IF EXISTS labels;
+
+ALTER TABLE public.revoked
+    DROP COLUMN IF EXISTS requester,
+    DROP COLUMN IF EXISTS client_ip,
+    DROP COLUMN IF EXISTS request_id,
+    DROP COLUMN IF EXISTS request,
+    DROP COLUMN IF EXISTS san,
+    DROP COLUMN IF EXISTS labels;
+
+COMMIT;
@@ -0,0 +1,34 @@
+BEGIN;
+
+--
+-- Request metadata of the issued and revoked certificates
+--
+ALTER TABLE public.certificates
+    ADD COLUMN IF NOT EXISTS requester character varying(160) COLLATE pg_catalog."default" NULL,
+    ADD COLUMN IF NOT EXISTS client_ip character varying(64) COLLATE pg_catalog."default" NULL,
+    ADD COLUMN IF NOT EXISTS request_id character varying(64) COLLATE pg_catalog."default" NULL,
+    ADD COLUMN IF NOT EXISTS request text COLLATE pg_catalog."default" NULL,
+    ADD COLUMN IF NOT EXISTS san text[] NULL,
+    ADD COLUMN IF NOT EXISTS labels jsonb NULL;
+
+ALTER TABLE public.revoked
+    ADD COLUMN IF NOT EXISTS requester character varying(160) COLLATE pg_catalog."default" NULL,
+    ADD COLUMN IF NOT EXISTS client_ip character varying(64) COLLATE pg_catalog."default" NULL,
+    ADD COLUMN IF NOT EXISTS request_id character varying(64) COLLATE pg_catalog."default" NULL,
+    ADD COLUMN IF NOT EXISTS request text COLLATE pg_catalog."default" NULL,
+    ADD COLUMN IF NOT EXISTS san text[] NULL,
+    ADD COLUMN IF NOT EXISTS labels jsonb NULL;
+
+CREATE INDEX IF NOT EXISTS idx_certificates_requester
+    ON public.certificates USING btree
+    (requester);
+
+--
+-- Request metadata of the requests in the approval queue
+--
+ALTER TABLE public.approvals
+    ADD COLUMN IF NOT EXISTS client_ip character varying(64) COLLATE pg_catalog."default" NULL,
+    ADD COLUMN IF NOT EXISTS request_id character varying(64) COLLATE pg_catalog."default" NULL,
+    ADD COLUMN IF NOT EXISTS labels jsonb NULL;
+
+COMMIT;
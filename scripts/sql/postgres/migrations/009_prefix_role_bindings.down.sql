@@ -0,0 +1,11 @@
+BEGIN;
+
+UPDATE public.role_bindings
+    SET subject = substring(subject from 8)
+    WHERE subject LIKE 'spiffe:%';
+
+UPDATE public.role_bindings
+    SET subject = substring(subject from 6)
+    WHERE subject LIKE 'user:%';
+
+COMMIT;
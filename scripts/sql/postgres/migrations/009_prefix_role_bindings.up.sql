@@ -0,0 +1,25 @@
+BEGIN;
+
+--
+-- Subjects of role bindings are prefixed with their type,
+-- the subjects of SPIFFE IDs and emails are migrated,
+-- the bindings of groups must be re-created in group:${provider}/${group} format,
+-- and of service accounts and TLS common names with sa: and cn: prefixes
+--
+UPDATE public.role_bindings
+    SET subject = 'spiffe:' || subject
+    WHERE subject LIKE 'spiffe://%' OR subject LIKE 'spifee://%';
+
+UPDATE public.role_bindings
+    SET subject = 'user:' || subject
+    WHERE subject LIKE '%@%' AND subject NOT LIKE '%:%';
+
+--
+-- Groups of active sessions are not qualified by the provider,
+-- the users must login again
+--
+UPDATE public.sessions
+    SET groups = NULL
+    WHERE groups IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS unique_users_provider_subject;
+
+ALTER TABLE public.users
+    DROP COLUMN IF EXISTS subject;
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+--
+-- Users of OpenID Connect providers are identified by (provider, subject),
+-- the email asserted by the provider is not used to match the account,
+-- the existing accounts of the provider are bound to the subject on the first login
+--
+ALTER TABLE public.users
+    ADD COLUMN IF NOT EXISTS subject character varying(256) COLLATE pg_catalog."default" NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_users_provider_subject
+    ON public.users USING btree
+    (provider, subject)
+    ;
+
+COMMIT;
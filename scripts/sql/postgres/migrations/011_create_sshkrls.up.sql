@@ -0,0 +1,17 @@
+BEGIN;
+
+--
+-- Versions of the published SSH KRLs, incremented with each KRL of the issuer
+--
+CREATE TABLE IF NOT EXISTS public.sshkrls
+(
+    ikid character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    version bigint NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    CONSTRAINT sshkrls_pkey PRIMARY KEY (ikid)
+)
+WITH (
+    OIDS = FALSE
+);
+
+COMMIT;
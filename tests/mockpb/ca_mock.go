@@ -90,3 +90,19 @@ func (m *MockCAServer) ListRevokedCertificates(ctx context.Context, in *pb.ListB
 	}
 	return m.Resps[0].(*pb.RevokedCertificatesResponse), nil
 }
+
+// UpdateCertificateLabels updates the labels of the certificate
+func (m *MockCAServer) UpdateCertificateLabels(ctx context.Context, in *pb.UpdateCertificateLabelsRequest) (*pb.CertificateResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Resps[0].(*pb.CertificateResponse), nil
+}
+
+// ReloadConfig reloads the CA configuration and returns the issuing CAs
+func (m *MockCAServer) ReloadConfig(context.Context, *empty.Empty) (*pb.IssuersInfoResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Resps[0].(*pb.IssuersInfoResponse), nil
+}
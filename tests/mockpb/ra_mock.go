@@ -63,3 +63,35 @@ func (m *MockRAServer) GetCertificate(ctx context.Context, in *pb.GetCertificate
 	}
 	return m.Resps[0].(*pb.CertificateResponse), nil
 }
+
+// ListApprovals returns the certificate requests in the approval queue
+func (m *MockRAServer) ListApprovals(ctx context.Context, in *pb.ListApprovalsRequest) (*pb.ApprovalsResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Resps[0].(*pb.ApprovalsResponse), nil
+}
+
+// GetApproval returns the certificate request in the approval queue
+func (m *MockRAServer) GetApproval(ctx context.Context, in *pb.GetApprovalRequest) (*pb.ApprovalResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Resps[0].(*pb.ApprovalResponse), nil
+}
+
+// Approve approves the certificate request
+func (m *MockRAServer) Approve(ctx context.Context, in *pb.ApprovalDecisionRequest) (*pb.ApprovalResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Resps[0].(*pb.ApprovalResponse), nil
+}
+
+// Deny denies the certificate request
+func (m *MockRAServer) Deny(ctx context.Context, in *pb.ApprovalDecisionRequest) (*pb.ApprovalResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Resps[0].(*pb.ApprovalResponse), nil
+}
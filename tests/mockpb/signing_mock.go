@@ -0,0 +1,43 @@
+package mockpb
+
+import (
+	"context"
+
+	"github.com/ekspand/trusty/api/v1/pb"
+	"github.com/gogo/protobuf/proto"
+)
+
+// MockSigningServer for testing
+type MockSigningServer struct {
+	pb.SigningServiceServer
+
+	Reqs []proto.Message
+
+	// If set, all calls return this error.
+	Err error
+
+	// responses to return if err == nil
+	Resps []proto.Message
+}
+
+// SetResponse sets a single response without errors
+func (m *MockSigningServer) SetResponse(r proto.Message) {
+	m.Err = nil
+	m.Resps = []proto.Message{r}
+}
+
+// SignDigest returns detached signature of the digest
+func (m *MockSigningServer) SignDigest(ctx context.Context, in *pb.SignDigestRequest) (*pb.SignatureResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Resps[0].(*pb.SignatureResponse), nil
+}
+
+// SignKeyless returns a short-lived certificate for the caller's public key
+func (m *MockSigningServer) SignKeyless(ctx context.Context, in *pb.SignKeylessRequest) (*pb.KeylessCertificateResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Resps[0].(*pb.KeylessCertificateResponse), nil
+}
@@ -0,0 +1,43 @@
+package mockpb
+
+import (
+	"context"
+
+	"github.com/ekspand/trusty/api/v1/pb"
+	"github.com/gogo/protobuf/proto"
+)
+
+// MockSPIFFEServer for testing
+type MockSPIFFEServer struct {
+	pb.SPIFFEServiceServer
+
+	Reqs []proto.Message
+
+	// If set, all calls return this error.
+	Err error
+
+	// responses to return if err == nil
+	Resps []proto.Message
+}
+
+// SetResponse sets a single response without errors
+func (m *MockSPIFFEServer) SetResponse(r proto.Message) {
+	m.Err = nil
+	m.Resps = []proto.Message{r}
+}
+
+// SignJWTSVID returns JWT-SVID for SPIFFE ID of the caller
+func (m *MockSPIFFEServer) SignJWTSVID(ctx context.Context, in *pb.JWTSVIDRequest) (*pb.JWTSVIDResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Resps[0].(*pb.JWTSVIDResponse), nil
+}
+
+// ValidateJWTSVID validates JWT-SVID for the audience
+func (m *MockSPIFFEServer) ValidateJWTSVID(ctx context.Context, in *pb.ValidateJWTSVIDRequest) (*pb.ValidateJWTSVIDResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Resps[0].(*pb.ValidateJWTSVIDResponse), nil
+}
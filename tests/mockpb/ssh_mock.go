@@ -0,0 +1,51 @@
+package mockpb
+
+import (
+	"context"
+
+	"github.com/ekspand/trusty/api/v1/pb"
+	"github.com/gogo/protobuf/proto"
+)
+
+// MockSSHServer for testing
+type MockSSHServer struct {
+	pb.SSHServiceServer
+
+	Reqs []proto.Message
+
+	// If set, all calls return this error.
+	Err error
+
+	// responses to return if err == nil
+	Resps []proto.Message
+}
+
+// SetResponse sets a single response without errors
+func (m *MockSSHServer) SetResponse(r proto.Message) {
+	m.Err = nil
+	m.Resps = []proto.Message{r}
+}
+
+// SignSSHKey returns SSH certificate for the public key
+func (m *MockSSHServer) SignSSHKey(ctx context.Context, in *pb.SignSSHKeyRequest) (*pb.SSHCertificateResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Resps[0].(*pb.SSHCertificateResponse), nil
+}
+
+// RevokeSSHCertificate returns the revoked SSH certificate
+func (m *MockSSHServer) RevokeSSHCertificate(ctx context.Context, in *pb.RevokeSSHCertificateRequest) (*pb.SSHCertificateResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Resps[0].(*pb.SSHCertificateResponse), nil
+}
+
+// PublishKRL returns published Key Revocation Lists
+func (m *MockSSHServer) PublishKRL(ctx context.Context, in *pb.PublishKRLRequest) (*pb.KRLResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Resps[0].(*pb.KRLResponse), nil
+}